@@ -0,0 +1,86 @@
+/*
+Package bench provides reusable benchmark drivers, with representative workloads, for the lexer and parser cores.
+
+The workloads cover JSON-like input, whitespace-heavy input, long identifiers, and pathological lookahead (where the
+parser backtracks over long statements), so performance regressions in the buffer implementations are caught by
+running:
+
+	go test -bench . github.com/tekwizely/go-parsing/bench
+
+The drivers accept lexer and parser options, allowing options (i.e. lexer.WithLazyValues) to be compared against the
+defaults:
+
+	func BenchmarkLazy(b *testing.B) {
+		for _, w := range bench.Workloads(64 * 1024) {
+			b.Run(w.Name, func(b *testing.B) {
+				bench.Lex(b, w, lexer.WithLazyValues())
+			})
+		}
+	}
+
+*/
+package bench
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// Lex benchmarks lexing the workload with LexStart, reporting throughput in bytes.
+// Fails the benchmark if the lexer returns an error.
+//
+func Lex(b *testing.B, w Workload, opts ...lexer.Option) {
+	b.SetBytes(int64(len(w.Input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := token.Drain(lexer.LexString(w.Input, LexStart, opts...)); err != nil {
+			b.Fatalf("%s: %v", w.Name, err)
+		}
+	}
+}
+
+// LexReader benchmarks lexing the workload from an io.Reader with LexStart, reporting throughput in bytes.
+// Fails the benchmark if the lexer returns an error.
+//
+func LexReader(b *testing.B, w Workload, opts ...lexer.Option) {
+	b.SetBytes(int64(len(w.Input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r := &readerOnly{strings.NewReader(w.Input)} // Hide io.RuneReader, forcing LexReader to wrap the input
+		if _, err := token.Drain(lexer.LexReader(r, LexStart, opts...)); err != nil {
+			b.Fatalf("%s: %v", w.Name, err)
+		}
+	}
+}
+
+// Parse benchmarks lexing and parsing the workload with LexStart and ParseStart, reporting throughput in bytes.
+// Fails the benchmark if the lexer or parser returns an error.
+//
+func Parse(b *testing.B, w Workload, opts ...parser.Option) {
+	b.SetBytes(int64(len(w.Input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parser.Drain(parser.Parse(lexer.LexString(w.Input, LexStart), ParseStart, opts...)); err != nil {
+			b.Fatalf("%s: %v", w.Name, err)
+		}
+	}
+}
+
+// readerOnly exposes just the io.Reader of a strings.Reader.
+//
+type readerOnly struct {
+	r *strings.Reader
+}
+
+// Read implements io.Reader.Read().
+//
+func (r *readerOnly) Read(p []byte) (int, error) {
+	return r.r.Read(p)
+}
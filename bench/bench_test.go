@@ -0,0 +1,100 @@
+package bench
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// benchSize is the approximate size of each workload, in bytes.
+//
+const benchSize = 64 * 1024
+
+// TestWorkloads confirms each workload lexes and parses without errors.
+//
+func TestWorkloads(t *testing.T) {
+	for _, w := range Workloads(4 * 1024) {
+		count, err := token.Drain(lexer.LexString(w.Input, LexStart))
+		if err != nil || count == 0 {
+			t.Errorf("%s: lexing expecting tokens, received %d, %v", w.Name, count, err)
+		}
+		asts, err := parser.ParseAll(lexer.LexString(w.Input, LexStart), ParseStart)
+		if err != nil || len(asts) == 0 {
+			t.Errorf("%s: parsing expecting ASTs, received %d, %v", w.Name, len(asts), err)
+		}
+		total := 0
+		for _, ast := range asts {
+			total += ast.(int)
+		}
+		if total != count {
+			t.Errorf("%s: parsing expecting %d tokens, received %d", w.Name, count, total)
+		}
+	}
+}
+
+// TestParseError
+//
+func TestParseError(t *testing.T) {
+	for _, input := range []string{"[1,", "a b", `{"a" 1}`, "]"} {
+		if _, err := parser.ParseAll(lexer.LexString(input, LexStart), ParseStart); err == nil {
+			t.Errorf("'%s': parsing expecting error", input)
+		}
+	}
+}
+
+// BenchmarkLex
+//
+func BenchmarkLex(b *testing.B) {
+	for _, w := range Workloads(benchSize) {
+		w := w
+		b.Run(w.Name, func(b *testing.B) {
+			Lex(b, w)
+		})
+	}
+}
+
+// BenchmarkLexReader
+//
+func BenchmarkLexReader(b *testing.B) {
+	for _, w := range Workloads(benchSize) {
+		w := w
+		b.Run(w.Name, func(b *testing.B) {
+			LexReader(b, w)
+		})
+	}
+}
+
+// BenchmarkParse
+//
+func BenchmarkParse(b *testing.B) {
+	for _, w := range Workloads(benchSize) {
+		w := w
+		b.Run(w.Name, func(b *testing.B) {
+			Parse(b, w)
+		})
+	}
+}
+
+// BenchmarkLexStats measures the cost of recording counters, relative to BenchmarkLex.
+//
+func BenchmarkLexStats(b *testing.B) {
+	for _, w := range Workloads(benchSize) {
+		w := w
+		b.Run(w.Name, func(b *testing.B) {
+			Lex(b, w, lexer.WithStats(&lexer.Stats{}))
+		})
+	}
+}
+
+// BenchmarkParseStats measures the cost of recording counters, relative to BenchmarkParse.
+//
+func BenchmarkParseStats(b *testing.B) {
+	for _, w := range Workloads(benchSize) {
+		w := w
+		b.Run(w.Name, func(b *testing.B) {
+			Parse(b, w, parser.WithStats(&parser.Stats{}))
+		})
+	}
+}
@@ -0,0 +1,17 @@
+module github.com/tekwizely/go-parsing/bench
+
+go 1.12
+
+require (
+	github.com/tekwizely/go-parsing/lexer v0.0.0
+	github.com/tekwizely/go-parsing/lexer/token v0.0.0
+	github.com/tekwizely/go-parsing/parser v0.0.0
+)
+
+// Benchmarks always run against the local modules
+//
+replace (
+	github.com/tekwizely/go-parsing/lexer => ../lexer
+	github.com/tekwizely/go-parsing/lexer/token => ../lexer/token
+	github.com/tekwizely/go-parsing/parser => ../parser
+)
@@ -0,0 +1,193 @@
+package bench
+
+import (
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// Token types emitted by LexStart.
+//
+const (
+	TObjectStart token.Type = lexer.TStart + iota // '{'
+	TObjectEnd                                    // '}'
+	TArrayStart                                   // '['
+	TArrayEnd                                     // ']'
+	TComma                                        // ','
+	TColon                                        // ':'
+	TSemi                                         // ';'
+	TDot                                          // '.'
+	TString                                       // Double-quoted string, including the quotes
+	TNumber                                       // Integer, with optional leading '-'
+	TIdent                                        // Letters, digits and '_', starting with a letter or '_'
+)
+
+// punctuation maps single-rune tokens to their types.
+//
+var punctuation = map[rune]token.Type{
+	'{': TObjectStart,
+	'}': TObjectEnd,
+	'[': TArrayStart,
+	']': TArrayEnd,
+	',': TComma,
+	':': TColon,
+	';': TSemi,
+	'.': TDot,
+}
+
+// LexStart lexes all of the workloads, emitting the token types above.
+// Whitespace is discarded, and unrecognized runes are emitted as lexer.TUnknown.
+// Identifiers are peeked in full before being matched, exercising the peek buffer.
+//
+func LexStart(l *lexer.Lexer) lexer.Fn {
+	r := l.Peek(1)
+	switch {
+	case isSpace(r):
+		for l.CanPeek(1) && isSpace(l.Peek(1)) {
+			l.Next()
+		}
+		l.Clear()
+	case punctuation[r] != 0:
+		l.Next()
+		l.EmitType(punctuation[r])
+	case r == '"':
+		l.Next()
+		for l.CanPeek(1) && l.Peek(1) != '"' {
+			if l.Next() == '\\' && l.CanPeek(1) {
+				l.Next()
+			}
+		}
+		if !l.CanPeek(1) {
+			l.EmitError("unterminated string")
+			return nil
+		}
+		l.Next()
+		l.EmitToken(TString)
+	case r == '-' || isDigit(r):
+		l.Next()
+		for l.CanPeek(1) && isDigit(l.Peek(1)) {
+			l.Next()
+		}
+		l.EmitToken(TNumber)
+	case isLetter(r):
+		n := 1
+		for l.CanPeek(n+1) && (isLetter(l.Peek(n+1)) || isDigit(l.Peek(n+1))) {
+			n++
+		}
+		for ; n > 0; n-- {
+			l.Next()
+		}
+		l.EmitToken(TIdent)
+	default:
+		l.Next()
+		l.EmitToken(lexer.TUnknown)
+	}
+	return LexStart
+}
+
+// isSpace
+//
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+// isDigit
+//
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isLetter
+//
+func isLetter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_'
+}
+
+// ParseStart parses all of the workloads, emitting the number of tokens in each top-level value or statement.
+// JSON values are parsed recursively.
+// Statements (identifiers terminated by '.' or ';') are parsed by trying the '.' alternative first, backtracking via
+// a marker when it fails.
+//
+func ParseStart(p *parser.Parser) parser.Fn {
+	if p.PeekType(1) == TIdent {
+		for _, end := range []token.Type{TDot, TSemi} {
+			m := p.Marker()
+			if n := statement(p, end); n > 0 {
+				p.Emit(n)
+				return ParseStart
+			}
+			m.Apply()
+		}
+		p.EmitError("expecting statement")
+		return nil
+	}
+	n := value(p)
+	if n == 0 {
+		p.EmitError("expecting value")
+		return nil
+	}
+	p.Emit(n)
+	return ParseStart
+}
+
+// statement matches identifiers terminated by end, returning the number of tokens matched, or 0 if no match.
+//
+func statement(p *parser.Parser, end token.Type) int {
+	n := 0
+	for p.CanPeek(1) && p.PeekType(1) == TIdent {
+		p.Next()
+		n++
+	}
+	if !p.CanPeek(1) || p.PeekType(1) != end {
+		return 0
+	}
+	p.Next()
+	return n + 1
+}
+
+// value matches a JSON value, returning the number of tokens matched, or 0 if no match.
+//
+func value(p *parser.Parser) int {
+	if !p.CanPeek(1) {
+		return 0
+	}
+	switch p.Next().Type() {
+	case TString, TNumber, TIdent:
+		return 1
+	case TArrayStart:
+		return container(p, TArrayEnd, false)
+	case TObjectStart:
+		return container(p, TObjectEnd, true)
+	}
+	return 0
+}
+
+// container matches the comma-separated entries of an array or object, up to and including end, returning the
+// number of tokens matched (including the opening token), or 0 if no match.
+//
+func container(p *parser.Parser, end token.Type, keyed bool) int {
+	n := 1
+	for i := 0; ; i++ {
+		if p.CanPeek(1) && p.PeekType(1) == end {
+			p.Next()
+			return n + 1
+		}
+		if i > 0 {
+			if !p.CanPeek(1) || p.Next().Type() != TComma {
+				return 0
+			}
+			n++
+		}
+		if keyed {
+			if !p.CanPeek(2) || p.Next().Type() != TString || p.Next().Type() != TColon {
+				return 0
+			}
+			n += 2
+		}
+		m := value(p)
+		if m == 0 {
+			return 0
+		}
+		n += m
+	}
+}
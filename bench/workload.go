@@ -0,0 +1,87 @@
+package bench
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Workload is a representative benchmark input.
+//
+type Workload struct {
+	Name  string
+	Input string
+}
+
+// Workloads returns the standard workloads, each approximately size bytes long.
+//
+func Workloads(size int) []Workload {
+	return []Workload{
+		{Name: "json", Input: JSONLike(size)},
+		{Name: "whitespace", Input: Whitespace(size)},
+		{Name: "longident", Input: LongIdent(size)},
+		{Name: "lookahead", Input: Lookahead(size)},
+	}
+}
+
+// JSONLike returns a JSON array of small, nested, records, approximately size bytes long.
+//
+func JSONLike(size int) string {
+	b := &strings.Builder{}
+	b.WriteString("[")
+	for i := 0; b.Len() < size; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		n := strconv.Itoa(i)
+		b.WriteString(`{"name": "value-` + n + `", "id": ` + n + `, "tags": ["a", "b\"c"], "nested": {"ok": true}}`)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// Whitespace returns a JSON array of numbers separated by long runs of whitespace, approximately size bytes long.
+//
+func Whitespace(size int) string {
+	b := &strings.Builder{}
+	b.WriteString("[")
+	for i := 0; b.Len() < size; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n\t\t        \t\n    ")
+		b.WriteString(strconv.Itoa(i))
+		b.WriteString("    \t    \n")
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// LongIdent returns statements of 256-rune identifiers, each terminated by ';', approximately size bytes long.
+//
+func LongIdent(size int) string {
+	ident := strings.Repeat("abcdefgh", 32)
+	b := &strings.Builder{}
+	for i := 0; b.Len() < size; i++ {
+		b.WriteString(ident)
+		b.WriteString(strconv.Itoa(i))
+		b.WriteString(" ")
+		b.WriteString(ident)
+		b.WriteString(";\n")
+	}
+	return b.String()
+}
+
+// Lookahead returns statements of 500 short identifiers, each terminated by ';', approximately size bytes long.
+// The statement parser (see ParseStart) first tries a '.'-terminated alternative, only failing at the end of each
+// statement, forcing it to backtrack over the whole statement.
+//
+func Lookahead(size int) string {
+	b := &strings.Builder{}
+	for b.Len() < size {
+		for i := 0; i < 500; i++ {
+			b.WriteString("id ")
+		}
+		b.WriteString(";\n")
+	}
+	return b.String()
+}
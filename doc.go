@@ -50,6 +50,19 @@ Some Features of this Parser:
  * Mark / Reset Functionality
 
 
+Bench
+
+Reusable benchmark drivers, with representative workloads, that exercise the lexer and parser cores.
+
+
+Multi-File Driver
+
+ParseFiles lexes and parses a set of files concurrently, using a pool of workers, returning the ASTs and diagnostics
+of each file in the order the files were given - the standard shape of a compiler front-end driver:
+
+	results, err := parsing.ParseFiles(paths, lexStart, parseStart, 4)
+
+
 Links
 
 You can learn more online:
@@ -0,0 +1,99 @@
+package parsing
+
+import (
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"sync"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/diag"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// Result holds the outcome of parsing a single file - see ParseFiles.
+//
+type Result struct {
+	Path        string            // Path of the file, as passed to ParseFiles
+	ASTs        []interface{}     // ASTs emitted by the parser, in order
+	Diagnostics []diag.Diagnostic // Warnings and errors reported by the lexer and parser, in the order they were reported
+	Err         error             // Error reading the file, or the first error returned while lexing and parsing it
+}
+
+// FileError annotates an error with the path of the file that produced it.
+//
+type FileError struct {
+	Path string
+	Err  error
+}
+
+// Error implements error, i.e. "main.calc: unexpected ')'".
+//
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err)
+}
+
+// Unwrap returns the wrapped error.
+//
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// ParseFiles reads, lexes and parses each of the files concurrently, using a pool of workers, starting each file with
+// the lex and parse functions.
+// Results are returned in the same order as paths, regardless of the order in which the files complete.
+// Each file is lexed and parsed independently, with its own diagnostics collector (see lexer.WithDiagnostics and
+// parser.WithDiagnostics); As with parser.Collect, parsing continues past errors, including lexer errors (see
+// parser.WithLexErrors).
+// Lexer and parser functions are shared across workers, so must not modify shared state without synchronization.
+// If workers < 1, runtime.GOMAXPROCS(0) workers are used.
+// All files are always processed; Returns the error of the first file (in path order) that failed, if any, as a
+// *FileError.
+//
+func ParseFiles(paths []string, lex lexer.Fn, parse parser.Fn, workers int) ([]Result, error) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+	results := make([]Result, len(paths))
+	jobs := make(chan int)
+	wg := &sync.WaitGroup{}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = parseFile(paths[i], lex, parse)
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	for _, r := range results {
+		if r.Err != nil {
+			return results, &FileError{Path: r.Path, Err: r.Err}
+		}
+	}
+	return results, nil
+}
+
+// parseFile reads, lexes and parses a single file.
+//
+func parseFile(path string, lex lexer.Fn, parse parser.Fn) Result {
+	r := Result{Path: path}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	diags := diag.NewCollector()
+	tokens := lexer.LexBytes(data, lex, lexer.WithDiagnostics(diags))
+	r.ASTs, r.Err = parser.Collect(parser.Parse(tokens, parse, parser.WithDiagnostics(diags), parser.WithLexErrors()))
+	r.Diagnostics = diags.Diagnostics()
+	return r
+}
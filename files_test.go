@@ -0,0 +1,157 @@
+package parsing
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// TWord is the token type for words
+//
+const TWord token.Type = lexer.TStart
+
+// lexWords lexes lower-case words, separated by spaces, emitting an error for anything else
+//
+func lexWords(l *lexer.Lexer) lexer.Fn {
+	r := l.Next()
+	switch {
+	case r == ' ' || r == '\n':
+		l.Clear()
+	case r >= 'a' && r <= 'z':
+		for l.CanPeek(1) && l.Peek(1) >= 'a' && l.Peek(1) <= 'z' {
+			l.Next()
+		}
+		l.EmitToken(TWord)
+	default:
+		l.EmitErrorToken("unexpected character")
+	}
+	return lexWords
+}
+
+// parseWords emits each word, emitting an error for the word 'bad'
+//
+func parseWords(p *parser.Parser) parser.Fn {
+	tok := p.Next()
+	if tok.Value() == "bad" {
+		p.EmitError("bad word")
+	} else {
+		p.Emit(tok.Value())
+	}
+	return parseWords
+}
+
+// writeFiles writes each of the files into dir, returning their paths
+//
+func writeFiles(t *testing.T, dir string, contents ...string) []string {
+	var paths []string
+	for i, content := range contents {
+		path := filepath.Join(dir, string(rune('a'+i))+".txt")
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// TestParseFiles
+//
+func TestParseFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parsefiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	paths := writeFiles(t, dir, "one two", "three bad four", "five", "six 7", "")
+	paths = append(paths, filepath.Join(dir, "missing.txt"))
+
+	for _, workers := range []int{0, 1, 3, 10} {
+		results, err := ParseFiles(paths, lexWords, parseWords, workers)
+		if err == nil || err.Error() != paths[1]+": bad word" {
+			t.Errorf("ParseFiles(workers=%d) expecting error for second file, received '%v'", workers, err)
+		}
+		if len(results) != len(paths) {
+			t.Fatalf("ParseFiles(workers=%d) expecting %d results, received %d", workers, len(paths), len(results))
+		}
+		expected := []struct {
+			asts  string
+			diags string
+			err   string
+		}{
+			{"one,two", "", ""},
+			{"three,four", "1:7: error: bad word", "bad word"},
+			{"five", "", ""},
+			{"six", "1:5: error: unexpected character", "1:5: unexpected character"},
+			{"", "", ""},
+			{"", "", "no such file"},
+		}
+		for i, r := range results {
+			if r.Path != paths[i] {
+				t.Errorf("ParseFiles(workers=%d) result %d expecting path '%s', received '%s'", workers, i, paths[i], r.Path)
+			}
+			var asts, diags []string
+			for _, ast := range r.ASTs {
+				asts = append(asts, ast.(string))
+			}
+			for _, d := range r.Diagnostics {
+				diags = append(diags, d.String())
+			}
+			if s := strings.Join(asts, ","); s != expected[i].asts {
+				t.Errorf("ParseFiles(workers=%d) result %d expecting ASTs '%s', received '%s'", workers, i, expected[i].asts, s)
+			}
+			if s := strings.Join(diags, ","); s != expected[i].diags {
+				t.Errorf("ParseFiles(workers=%d) result %d expecting diagnostics '%s', received '%s'", workers, i, expected[i].diags, s)
+			}
+			if expected[i].err == "" && r.Err != nil || expected[i].err != "" && (r.Err == nil || !strings.Contains(r.Err.Error(), expected[i].err)) {
+				t.Errorf("ParseFiles(workers=%d) result %d expecting error '%s', received '%v'", workers, i, expected[i].err, r.Err)
+			}
+		}
+	}
+}
+
+// TestParseFilesLexError confirms parsing continues past lexer errors in the middle of a file
+//
+func TestParseFilesLexError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parsefiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	paths := writeFiles(t, dir, "eight 9 nine\n0 ten")
+
+	results, err := ParseFiles(paths, lexWords, parseWords, 1)
+	if err == nil || err.Error() != paths[0]+": 1:7: unexpected character" {
+		t.Errorf("ParseFiles() expecting first lexer error, received '%v'", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("ParseFiles() expecting 1 result, received %d", len(results))
+	}
+	var asts, diags []string
+	for _, ast := range results[0].ASTs {
+		asts = append(asts, ast.(string))
+	}
+	for _, d := range results[0].Diagnostics {
+		diags = append(diags, d.String())
+	}
+	if s := strings.Join(asts, ","); s != "eight,nine,ten" {
+		t.Errorf("ParseFiles() expecting ASTs 'eight,nine,ten', received '%s'", s)
+	}
+	if s := strings.Join(diags, ","); s != "1:7: error: unexpected character,2:1: error: unexpected character" {
+		t.Errorf("ParseFiles() expecting both lexer diagnostics, received '%s'", s)
+	}
+}
+
+// TestParseFilesEmpty
+//
+func TestParseFilesEmpty(t *testing.T) {
+	results, err := ParseFiles(nil, lexWords, parseWords, 4)
+	if len(results) != 0 || err != nil {
+		t.Errorf("ParseFiles(nil) expecting no results, received %v, %v", results, err)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Package adapter bridges the lexer with other tokenizers and parser generators, allowing existing code bases to
+migrate incrementally.
+
+Adapting Standard Library Scanners
+
+FromTextScanner and FromGoSource expose the text/scanner and go/scanner packages as a token.Nexter, allowing parsers
+built with this library to consume tokens from the stdlib scanners.
+
+Adapting The Lexer
+
+NewScanner exposes a token.Nexter (such as the one returned by the lexer) via a text/scanner-like interface.
+
+Adapting Parser Generators
+
+NewYaccLexer adapts a token.Nexter for use with goyacc-generated parsers, mapping token types to yacc token codes.
+
+*/
+package adapter
+
+import "github.com/tekwizely/go-parsing/lexer/token"
+
+// adaptedToken is the token.Token implementation for tokens converted from other tokenizers.
+//
+type adaptedToken struct {
+	typ    token.Type
+	value  string
+	line   int
+	column int
+}
+
+// Type implements Token.Type().
+//
+func (t *adaptedToken) Type() token.Type {
+	return t.typ
+}
+
+// Value implements Token.Value().
+//
+func (t *adaptedToken) Value() string {
+	return t.value
+}
+
+// Line implements Token.Line().
+//
+func (t *adaptedToken) Line() int {
+	return t.line
+}
+
+// Column implements Token.Column().
+//
+func (t *adaptedToken) Column() int {
+	return t.column
+}
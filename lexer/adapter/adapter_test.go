@@ -0,0 +1,41 @@
+package adapter
+
+import (
+	"io"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// expectNext
+//
+func expectNext(t *testing.T, nexter token.Nexter, typ token.Type, value string, line int, column int) {
+	tok, err := nexter.Next()
+	if err != nil {
+		t.Errorf("Nexter.Next() received unexpected error '%s'", err)
+		return
+	}
+	expectToken(t, tok, typ, value, line, column)
+}
+
+// expectToken
+//
+func expectToken(t *testing.T, tok token.Token, typ token.Type, value string, line int, column int) {
+	if tok == nil {
+		t.Error("Expecting token, received nil")
+		return
+	}
+	if tok.Type() != typ || tok.Value() != value || tok.Line() != line || tok.Column() != column {
+		t.Errorf("Expecting token (%d, '%s', %d:%d), received (%d, '%s', %d:%d)",
+			typ, value, line, column, tok.Type(), tok.Value(), tok.Line(), tok.Column())
+	}
+}
+
+// expectEOF
+//
+func expectEOF(t *testing.T, nexter token.Nexter) {
+	tok, err := nexter.Next()
+	if tok != nil || err != io.EOF {
+		t.Errorf("Nexter.Next() expecting (nil, EOF), received ('%v', '%v')", tok, err)
+	}
+}
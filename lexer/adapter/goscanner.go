@@ -0,0 +1,71 @@
+package adapter
+
+import (
+	"errors"
+	"go/scanner"
+	gotoken "go/token"
+	"io"
+	"strings"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// goScannerNexter adapts a go/scanner Scanner into a token.Nexter.
+//
+type goScannerNexter struct {
+	scanner scanner.Scanner
+	fset    *gotoken.FileSet
+	errs    []string
+	eof     bool
+}
+
+// FromGoSource returns a token.Nexter that retrieves tokens from a go/scanner Scanner initialized against src.
+// Token types are the go/token Token values, i.e. token.Type(go/token.IDENT).
+// Token values are the literal text for identifiers, basic literals and comments, and the token string otherwise.
+// Automatically inserted semicolons have a value of "\n".
+// Token positions are as reported by go/token.Position (columns are byte-based).
+// Errors reported by the scanner are returned from Next(), along with the token being scanned when the error was
+// reported.
+//
+func FromGoSource(filename string, src []byte, mode scanner.Mode) token.Nexter {
+	n := &goScannerNexter{fset: gotoken.NewFileSet(), errs: nil, eof: false}
+	file := n.fset.AddFile(filename, n.fset.Base(), len(src))
+	n.scanner.Init(file, src, func(pos gotoken.Position, msg string) {
+		n.errs = append(n.errs, pos.String()+": "+msg)
+	}, mode)
+	return n
+}
+
+// Next implements token.Nexter.Next().
+//
+func (n *goScannerNexter) Next() (token.Token, error) {
+	if n.eof {
+		return nil, io.EOF
+	}
+	var tok token.Token
+	if pos, t, lit := n.scanner.Scan(); t == gotoken.EOF {
+		n.eof = true
+	} else {
+		if lit == "" {
+			lit = t.String()
+		}
+		position := n.fset.Position(pos)
+		tok = &adaptedToken{
+			typ:    token.Type(t),
+			value:  lit,
+			line:   position.Line,
+			column: position.Column,
+		}
+	}
+	// Errors take priority over EOF, which will be returned on the next call
+	//
+	if len(n.errs) > 0 {
+		err := errors.New(strings.Join(n.errs, "; "))
+		n.errs = nil
+		return tok, err
+	}
+	if n.eof {
+		return nil, io.EOF
+	}
+	return tok, nil
+}
@@ -0,0 +1,35 @@
+package adapter
+
+import (
+	"go/scanner"
+	gotoken "go/token"
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestFromGoSource
+//
+func TestFromGoSource(t *testing.T) {
+	nexter := FromGoSource("x.go", []byte("x := 42 // answer\n"), scanner.ScanComments)
+	expectNext(t, nexter, token.Type(gotoken.IDENT), "x", 1, 1)
+	expectNext(t, nexter, token.Type(gotoken.DEFINE), ":=", 1, 3)
+	expectNext(t, nexter, token.Type(gotoken.INT), "42", 1, 6)
+	expectNext(t, nexter, token.Type(gotoken.COMMENT), "// answer", 1, 9)
+	expectNext(t, nexter, token.Type(gotoken.SEMICOLON), "\n", 1, 18)
+	expectEOF(t, nexter)
+	expectEOF(t, nexter)
+}
+
+// TestFromGoSourceError
+//
+func TestFromGoSourceError(t *testing.T) {
+	nexter := FromGoSource("x.go", []byte("x @"), 0)
+	expectNext(t, nexter, token.Type(gotoken.IDENT), "x", 1, 1)
+	tok, err := nexter.Next()
+	if err == nil || !strings.Contains(err.Error(), "x.go:1:3: illegal character") {
+		t.Errorf("Nexter.Next() expecting 'illegal character' error, received '%v'", err)
+	}
+	expectToken(t, tok, token.Type(gotoken.ILLEGAL), "@", 1, 3)
+}
@@ -0,0 +1,84 @@
+package adapter
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Scanner exposes a token.Nexter via an interface modeled after text/scanner's Scanner, allowing code written against
+// text/scanner to consume tokens from the lexer.
+//
+type Scanner struct {
+	tokens token.Nexter
+	tok    token.Token
+
+	// Error is called for each error returned by the token.Nexter.
+	// If Error is nil, errors are reported to os.Stderr.
+	//
+	Error func(s *Scanner, err error)
+
+	// ErrorCount is incremented by one for each error encountered.
+	//
+	ErrorCount int
+}
+
+// NewScanner returns a Scanner that retrieves tokens from the token.Nexter.
+//
+func NewScanner(tokens token.Nexter) *Scanner {
+	return &Scanner{
+		tokens:     tokens,
+		tok:        nil,
+		Error:      nil,
+		ErrorCount: 0,
+	}
+}
+
+// Scan reads the next token, returning its type.
+// Errors are reported via Error, and scanning continues with the next token.
+// Returns lexer.TEof at the end of the input.
+//
+func (s *Scanner) Scan() token.Type {
+	for {
+		tok, err := s.tokens.Next()
+		if err == io.EOF {
+			s.tok = nil
+			return lexer.TEof
+		}
+		if err != nil {
+			s.ErrorCount++
+			if s.Error != nil {
+				s.Error(s, err)
+			} else {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+		if tok != nil {
+			s.tok = tok
+			return tok.Type()
+		}
+	}
+}
+
+// TokenText returns the value of the most recently scanned token.
+// Returns "" at the end of the input.
+//
+func (s *Scanner) TokenText() string {
+	if s.tok == nil {
+		return ""
+	}
+	return s.tok.Value()
+}
+
+// Pos returns the starting position of the most recently scanned token.
+// Returns an invalid position at the end of the input.
+//
+func (s *Scanner) Pos() token.Position {
+	if s.tok == nil {
+		return token.Position{Line: -1, Column: -1}
+	}
+	return token.Start(s.tok)
+}
@@ -0,0 +1,54 @@
+package adapter
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+const (
+	TChar = lexer.TStart + iota
+)
+
+// lexChar emits each rune as a TChar, and 'x' as an error
+//
+func lexChar(l *lexer.Lexer) lexer.Fn {
+	if l.Next() == 'x' {
+		l.EmitError("bad rune")
+	} else {
+		l.EmitToken(TChar)
+	}
+	return lexChar
+}
+
+// TestScanner
+//
+func TestScanner(t *testing.T) {
+	s := NewScanner(lexer.LexString("ax\nb", lexChar))
+	var errs []error
+	s.Error = func(s *Scanner, err error) {
+		errs = append(errs, err)
+	}
+	expectScan(t, s, TChar, "a", "1:1")
+	expectScan(t, s, TChar, "\n", "1:3")
+	expectScan(t, s, TChar, "b", "2:1")
+	expectScan(t, s, lexer.TEof, "", "-1:-1")
+	if s.ErrorCount != 1 || len(errs) != 1 || errs[0].Error() != "1:3: bad rune" {
+		t.Errorf("Scanner expecting 1 error '1:3: bad rune', received %d '%v'", s.ErrorCount, errs)
+	}
+}
+
+// expectScan
+//
+func expectScan(t *testing.T, s *Scanner, typ token.Type, text string, pos string) {
+	if r := s.Scan(); r != typ {
+		t.Errorf("Scanner.Scan() expecting %v, received %v", typ, r)
+	}
+	if s.TokenText() != text {
+		t.Errorf("Scanner.TokenText() expecting '%s', received '%s'", text, s.TokenText())
+	}
+	if s.Pos().String() != pos {
+		t.Errorf("Scanner.Pos() expecting '%s', received '%s'", pos, s.Pos())
+	}
+}
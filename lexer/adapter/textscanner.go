@@ -0,0 +1,101 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/scanner"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Token types for the text/scanner token classes, as returned by the token.Nexter from FromTextScanner.
+// The classes are negative in text/scanner, so are mapped into the user token range (see lexer.TStart).
+//
+const (
+	TextIdent     token.Type = lexer.TStart + iota // scanner.Ident
+	TextInt                                        // scanner.Int
+	TextFloat                                      // scanner.Float
+	TextChar                                       // scanner.Char
+	TextString                                     // scanner.String
+	TextRawString                                  // scanner.RawString
+	TextComment                                    // scanner.Comment
+	TextRune                                       // Single-character tokens are mapped to TextRune + the literal rune
+)
+
+// textClasses maps the text/scanner token classes to token types.
+//
+var textClasses = map[rune]token.Type{
+	scanner.Ident:     TextIdent,
+	scanner.Int:       TextInt,
+	scanner.Float:     TextFloat,
+	scanner.Char:      TextChar,
+	scanner.String:    TextString,
+	scanner.RawString: TextRawString,
+	scanner.Comment:   TextComment,
+}
+
+// textScannerNexter adapts a text/scanner Scanner into a token.Nexter.
+//
+type textScannerNexter struct {
+	scanner *scanner.Scanner
+	errs    []string
+	eof     bool
+}
+
+// FromTextScanner returns a token.Nexter that retrieves tokens from the (already initialized) text/scanner Scanner.
+// Token types are mapped from the values returned by Scanner.Scan(): TextIdent for scanner.Ident, TextInt for
+// scanner.Int, etc, or TextRune + the literal rune for single-character tokens (i.e. TextRune + '=').
+// Token values are the results of Scanner.TokenText().
+// Errors reported by the scanner are returned from Next(), along with the token being scanned when the error was
+// reported.
+// NOTE: The scanner's Error function is replaced in order to capture errors.
+//
+func FromTextScanner(s *scanner.Scanner) token.Nexter {
+	n := &textScannerNexter{scanner: s, errs: nil, eof: false}
+	s.Error = func(s *scanner.Scanner, msg string) {
+		n.errs = append(n.errs, fmt.Sprintf("%s: %s", s.Pos(), msg))
+	}
+	return n
+}
+
+// Next implements token.Nexter.Next().
+//
+func (n *textScannerNexter) Next() (token.Token, error) {
+	if n.eof {
+		return nil, io.EOF
+	}
+	var tok token.Token
+	if r := n.scanner.Scan(); r == scanner.EOF {
+		n.eof = true
+	} else {
+		tok = &adaptedToken{
+			typ:    textType(r),
+			value:  n.scanner.TokenText(),
+			line:   n.scanner.Position.Line,
+			column: n.scanner.Position.Column,
+		}
+	}
+	// Errors take priority over EOF, which will be returned on the next call
+	//
+	if len(n.errs) > 0 {
+		err := errors.New(strings.Join(n.errs, "; "))
+		n.errs = nil
+		return tok, err
+	}
+	if n.eof {
+		return nil, io.EOF
+	}
+	return tok, nil
+}
+
+// textType returns the token type for the value r returned by Scanner.Scan() - see FromTextScanner.
+//
+func textType(r rune) token.Type {
+	if typ, ok := textClasses[r]; ok {
+		return typ
+	}
+	return TextRune + token.Type(r)
+}
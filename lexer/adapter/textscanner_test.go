@@ -0,0 +1,52 @@
+package adapter
+
+import (
+	"strings"
+	"testing"
+	"text/scanner"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestFromTextScanner
+//
+func TestFromTextScanner(t *testing.T) {
+	var s scanner.Scanner
+	s.Init(strings.NewReader("x = 42\n\"hi\""))
+	nexter := FromTextScanner(&s)
+	expectNext(t, nexter, TextIdent, "x", 1, 1)
+	expectNext(t, nexter, TextRune+'=', "=", 1, 3)
+	expectNext(t, nexter, TextInt, "42", 1, 5)
+	expectNext(t, nexter, TextString, "\"hi\"", 2, 1)
+	expectEOF(t, nexter)
+	expectEOF(t, nexter)
+}
+
+// TestFromTextScannerSet confirms the token types can be used with token.Set
+//
+func TestFromTextScannerSet(t *testing.T) {
+	set := token.NewSet(TextIdent, TextComment, TextRune+'=')
+	for _, typ := range []token.Type{TextIdent, TextComment, TextRune + '='} {
+		if !set.Contains(typ) {
+			t.Errorf("Set.Contains(%d) expecting true", typ)
+		}
+	}
+	if set.Contains(TextInt) {
+		t.Errorf("Set.Contains(%d) expecting false", TextInt)
+	}
+}
+
+// TestFromTextScannerError
+//
+func TestFromTextScannerError(t *testing.T) {
+	var s scanner.Scanner
+	s.Init(strings.NewReader("x \"open"))
+	nexter := FromTextScanner(&s)
+	expectNext(t, nexter, TextIdent, "x", 1, 1)
+	tok, err := nexter.Next()
+	if err == nil || !strings.Contains(err.Error(), "literal not terminated") {
+		t.Errorf("Nexter.Next() expecting 'literal not terminated' error, received '%v'", err)
+	}
+	expectToken(t, tok, TextString, "\"open", 1, 3)
+	expectEOF(t, nexter)
+}
@@ -0,0 +1,90 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// YaccLexer adapts a token.Nexter for use with goyacc-generated parsers.
+// As the yyLexer interface depends on the grammar-specific yySymType, grammars need a small wrapper to satisfy it:
+//
+//	type yyLex struct {
+//		*adapter.YaccLexer
+//	}
+//
+//	func (l yyLex) Lex(lval *yySymType) int {
+//		typ, tok := l.Next()
+//		lval.tok = tok
+//		return typ
+//	}
+//
+//	yyParse(yyLex{adapter.NewYaccLexer(tokens, types)})
+//
+// The embedded YaccLexer provides the Error method required by yyLexer.
+//
+type YaccLexer struct {
+	tokens token.Nexter
+	types  map[token.Type]int
+	last   token.Token
+	errs   []error
+	eof    bool
+}
+
+// NewYaccLexer returns a YaccLexer that retrieves tokens from the token.Nexter.
+// The types table maps token types to the token codes generated by goyacc (i.e. the %token constants).
+// Token types not found in the table are passed to the parser as int(type), which is useful for single-character
+// literal tokens.
+//
+func NewYaccLexer(tokens token.Nexter, types map[token.Type]int) *YaccLexer {
+	return &YaccLexer{
+		tokens: tokens,
+		types:  types,
+		last:   nil,
+		errs:   nil,
+		eof:    false,
+	}
+}
+
+// Next retrieves the next token, returning its yacc token code, along with the token itself.
+// Errors returned by the token.Nexter are recorded (see Errors) and the token is skipped.
+// Returns 0 and nil at EOF, as expected by goyacc.
+//
+func (l *YaccLexer) Next() (int, token.Token) {
+	for !l.eof {
+		tok, err := l.tokens.Next()
+		if err == io.EOF {
+			l.eof = true
+			break
+		}
+		if err != nil {
+			l.errs = append(l.errs, err)
+		}
+		if tok != nil {
+			l.last = tok
+			if typ, ok := l.types[tok.Type()]; ok {
+				return typ, tok
+			}
+			return int(tok.Type()), tok
+		}
+	}
+	return 0, nil
+}
+
+// Error implements the Error method of the goyacc yyLexer interface.
+// The message is recorded (see Errors), prefixed with the position of the most recently returned token, if known.
+//
+func (l *YaccLexer) Error(s string) {
+	if l.last != nil && token.Start(l.last).IsValid() {
+		s = fmt.Sprintf("%s: %s", token.Start(l.last), s)
+	}
+	l.errs = append(l.errs, errors.New(s))
+}
+
+// Errors returns the errors recorded from the token.Nexter and the parser, in the order encountered.
+//
+func (l *YaccLexer) Errors() []error {
+	return l.errs
+}
@@ -0,0 +1,78 @@
+package adapter
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+const (
+	TOp = TChar + 1 + iota
+)
+
+const (
+	yaccNUM = 57346 + iota
+)
+
+// yySymType mimics the goyacc-generated type
+//
+type yySymType struct {
+	text string
+}
+
+// yyLexer mimics the goyacc-generated interface
+//
+type yyLexer interface {
+	Lex(lval *yySymType) int
+	Error(s string)
+}
+
+// yyLex is the documented wrapper for satisfying yyLexer
+//
+type yyLex struct {
+	*YaccLexer
+}
+
+func (l yyLex) Lex(lval *yySymType) int {
+	typ, tok := l.Next()
+	if tok != nil {
+		lval.text = tok.Value()
+	}
+	return typ
+}
+
+// lexYacc emits digits as TChar, 'x' as an error, and other runes as TOp
+//
+func lexYacc(l *lexer.Lexer) lexer.Fn {
+	switch r := l.Next(); {
+	case r >= '0' && r <= '9':
+		l.EmitToken(TChar)
+	case r == 'x':
+		l.EmitError("bad rune")
+	default:
+		l.EmitToken(TOp)
+	}
+	return lexYacc
+}
+
+// TestYaccLexer
+//
+func TestYaccLexer(t *testing.T) {
+	var yy yyLexer = yyLex{NewYaccLexer(lexer.LexString("1x+", lexYacc), map[token.Type]int{TChar: yaccNUM})}
+	var lval yySymType
+	if typ := yy.Lex(&lval); typ != yaccNUM || lval.text != "1" {
+		t.Errorf("Lex() expecting (%d, '1'), received (%d, '%s')", yaccNUM, typ, lval.text)
+	}
+	if typ := yy.Lex(&lval); typ != int(TOp) || lval.text != "+" {
+		t.Errorf("Lex() expecting (%d, '+'), received (%d, '%s')", TOp, typ, lval.text)
+	}
+	yy.Error("syntax error")
+	if typ := yy.Lex(&lval); typ != 0 {
+		t.Errorf("Lex() expecting 0, received %d", typ)
+	}
+	errs := yy.(yyLex).Errors()
+	if len(errs) != 2 || errs[0].Error() != "1:3: bad rune" || errs[1].Error() != "1:3: syntax error" {
+		t.Errorf("Errors() expecting ['1:3: bad rune', '1:3: syntax error'], received %v", errs)
+	}
+}
@@ -0,0 +1,102 @@
+package lexer
+
+import "github.com/tekwizely/go-parsing/lexer/token"
+
+// defaultArenaSize is the number of tokens in each arena slab, when not specified.
+//
+const defaultArenaSize = 1024
+
+// Arena is a bump allocator for emitted tokens, cutting GC pressure for batch tools (i.e. compilers) that lex
+// thousands of inputs.
+// Tokens are allocated from fixed-size slabs, which are retained and reused once the arena is released, either
+// directly via Release, or via the Release method of the token.Nexter.
+// Tokens allocated from the arena must not be used after it is released.
+// An Arena is not safe for concurrent use; Share it only between lexers used from the same goroutine.
+// See WithArena.
+//
+type Arena struct {
+	slabs [][]_token
+	slab  int // Index of the current slab
+	used  int // Tokens used in the current slab
+	size  int // Tokens per slab
+}
+
+// NewArena returns a new, empty, Arena, allocating tokens in slabs of the specified size.
+// A size < 1 uses a default size of 1024 tokens.
+//
+func NewArena(size int) *Arena {
+	if size < 1 {
+		size = defaultArenaSize
+	}
+	return &Arena{slabs: nil, slab: 0, used: 0, size: size}
+}
+
+// Len returns the number of tokens allocated since the arena was created or last released.
+//
+func (a *Arena) Len() int {
+	if len(a.slabs) == 0 {
+		return 0
+	}
+	return a.slab*a.size + a.used
+}
+
+// Release frees all tokens allocated from the arena at once, retaining the slabs for reuse.
+// Tokens allocated from the arena must not be used after this call.
+//
+func (a *Arena) Release() {
+	for i := 0; i < len(a.slabs) && i <= a.slab; i++ {
+		slab := a.slabs[i]
+		for j := range slab {
+			slab[j] = _token{} // Release the token values
+		}
+	}
+	a.slab = 0
+	a.used = 0
+}
+
+// token allocates a token from the arena.
+//
+func (a *Arena) token(typ token.Type, value string, line int, column int) *_token {
+	if len(a.slabs) == 0 {
+		a.slabs = append(a.slabs, make([]_token, a.size))
+	} else if a.used == a.size {
+		a.slab++
+		a.used = 0
+		if a.slab == len(a.slabs) {
+			a.slabs = append(a.slabs, make([]_token, a.size))
+		}
+	}
+	t := &a.slabs[a.slab][a.used]
+	a.used++
+	*t = _token{
+		typ:     typ,
+		value:   value,
+		raw:     nil,
+		line:    line,
+		column:  column,
+		end:     token.Position{Line: line, Column: column},
+		channel: token.ChannelDefault,
+		err:     nil,
+		cooked:  nil,
+	}
+	return t
+}
+
+// newToken allocates a token from the arena, if enabled (see WithArena).
+//
+func (l *Lexer) newToken(typ token.Type, value string, line int, column int) *_token {
+	if l.arena != nil {
+		return l.arena.token(typ, value, line, column)
+	}
+	return newToken(typ, value, line, column)
+}
+
+// Release frees all tokens allocated from the lexer's arena at once (see WithArena).
+// Tokens received from the nexter must not be used after this call.
+// Does nothing if the lexer has no arena.
+//
+func (t *tokenNexter) Release() {
+	if t.lexer.arena != nil {
+		t.lexer.arena.Release()
+	}
+}
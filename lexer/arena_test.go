@@ -0,0 +1,46 @@
+package lexer
+
+import "testing"
+
+// TestWithArena
+//
+func TestWithArena(t *testing.T) {
+	a := NewArena(2)
+	nexter := LexString("ab cd ef", lexFields, WithArena(a))
+	tok, _ := nexter.Next()
+	expectNexterNext(t, nexter, TStart, "cd", 1, 4)
+	expectNexterNext(t, nexter, TStart, "ef", 1, 7)
+	expectNexterEOF(t, nexter)
+	if tok.Value() != "ab" {
+		t.Errorf("Token.Value() expecting 'ab', received '%s'", tok.Value())
+	}
+	if a.Len() != 4 || len(a.slabs) != 2 {
+		t.Errorf("Arena expecting 4 tokens in 2 slabs, received %d tokens in %d slabs", a.Len(), len(a.slabs))
+	}
+	nexter.(interface{ Release() }).Release()
+	if a.Len() != 0 {
+		t.Errorf("Arena.Len() expecting 0 after Release(), received %d", a.Len())
+	}
+	if tok.Value() != "" {
+		t.Errorf("Token.Value() expecting '' after Release(), received '%s'", tok.Value())
+	}
+	// Slabs are reused
+	//
+	nexter = LexString("gh", lexFields, WithArena(a))
+	expectNexterNext(t, nexter, TStart, "gh", 1, 1)
+	expectNexterEOF(t, nexter)
+	if a.Len() != 2 || len(a.slabs) != 2 {
+		t.Errorf("Arena expecting 2 tokens in 2 slabs, received %d tokens in %d slabs", a.Len(), len(a.slabs))
+	}
+}
+
+// TestReleaseWithoutArena
+//
+func TestReleaseWithoutArena(t *testing.T) {
+	nexter := LexString("ab", lexFields)
+	tok, _ := nexter.Next()
+	nexter.(interface{ Release() }).Release()
+	if tok.Type() != TStart || tok.Value() != "ab" {
+		t.Errorf("Release() without arena expecting token 'ab' to remain valid, received '%s'", tok.Value())
+	}
+}
@@ -0,0 +1,90 @@
+package lexer
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// defaultReadSize is the size of each chunk read from io.Reader inputs, when not specified.
+//
+const defaultReadSize = 64 * 1024
+
+// maxEmptyReads is the number of consecutive empty reads tolerated before reporting io.ErrNoProgress.
+//
+const maxEmptyReads = 100
+
+// chunkReader reads an io.Reader in chunks into an internal buffer, decoding runes directly from the buffer.
+// Used by LexReader; See WithReadSize.
+//
+type chunkReader struct {
+	input io.Reader
+	buf   []byte // Allocated on first read
+	r     int    // Read position within buf
+	w     int    // Write position within buf
+	err   error  // Error returned by the input, reported once the buffer is drained
+	size  int    // Chunk size
+}
+
+// newChunkReader
+//
+func newChunkReader(input io.Reader) *chunkReader {
+	return &chunkReader{input: input, buf: nil, r: 0, w: 0, err: nil, size: defaultReadSize}
+}
+
+// ReadRune implements io.RuneReader.ReadRune().
+// Invalid bytes are returned as (utf8.RuneError, 1).
+//
+func (c *chunkReader) ReadRune() (rune, int, error) {
+	for c.w-c.r < utf8.UTFMax && !utf8.FullRune(c.buf[c.r:c.w]) && c.err == nil {
+		c.fill()
+	}
+	if c.r == c.w {
+		return 0, 0, c.err
+	}
+	r, size := rune(c.buf[c.r]), 1
+	if r >= utf8.RuneSelf {
+		r, size = utf8.DecodeRune(c.buf[c.r:c.w])
+	}
+	c.r += size
+	return r, size, nil
+}
+
+// Read implements io.Reader.Read(), draining the buffer before reading from the input directly.
+// Allows the input to be normalized (see WithNormalization) without double-buffering.
+//
+func (c *chunkReader) Read(p []byte) (int, error) {
+	if c.r < c.w {
+		n := copy(p, c.buf[c.r:c.w])
+		c.r += n
+		return n, nil
+	}
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.input.Read(p)
+}
+
+// fill reads the next chunk from the input, after moving any unread bytes to the front of the buffer.
+//
+func (c *chunkReader) fill() {
+	if c.buf == nil {
+		c.buf = make([]byte, c.size)
+	}
+	if c.r > 0 {
+		copy(c.buf, c.buf[c.r:c.w])
+		c.w -= c.r
+		c.r = 0
+	}
+	for i := 0; i < maxEmptyReads; i++ {
+		n, err := c.input.Read(c.buf[c.w:])
+		c.w += n
+		if err != nil {
+			c.err = err
+			return
+		}
+		if n > 0 {
+			return
+		}
+	}
+	c.err = io.ErrNoProgress
+}
@@ -0,0 +1,84 @@
+package lexer
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestChunkReader
+//
+func TestChunkReader(t *testing.T) {
+	input := "ab 字é\xffc 字字"
+	for _, size := range []int{1, 4, 5, 64} {
+		c := newChunkReader(iotest.HalfReader(strings.NewReader(input)))
+		c.size = size
+		if c.size < utf8.UTFMax {
+			c.size = utf8.UTFMax
+		}
+		var runes []rune
+		var sizes int
+		for {
+			r, n, err := c.ReadRune()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("chunkReader.ReadRune() returned error: %v", err)
+			}
+			runes = append(runes, r)
+			sizes += n
+		}
+		if string(runes) != "ab 字é�c 字字" || sizes != len(input) {
+			t.Errorf("chunkReader(%d) expecting '%s', received '%s' (%d bytes)", size, input, string(runes), sizes)
+		}
+	}
+}
+
+// TestChunkReaderNoProgress
+//
+func TestChunkReaderNoProgress(t *testing.T) {
+	c := newChunkReader(emptyReader{})
+	if _, _, err := c.ReadRune(); err != io.ErrNoProgress {
+		t.Errorf("chunkReader.ReadRune() expecting io.ErrNoProgress, received %v", err)
+	}
+}
+
+// emptyReader never returns any bytes.
+//
+type emptyReader struct{}
+
+func (emptyReader) Read([]byte) (int, error) { return 0, nil }
+
+// TestWithReadSize
+//
+func TestWithReadSize(t *testing.T) {
+	nexter := LexReader(iotest.OneByteReader(strings.NewReader("ab 字字 cd")), lexFields, WithReadSize(1))
+	expectNexterNext(t, nexter, TStart, "ab", 1, 1)
+	expectNexterNext(t, nexter, TStart, "字字", 1, 4)
+	expectNexterNext(t, nexter, TStart, "cd", 1, 7)
+	expectNexterEOF(t, nexter)
+}
+
+// BenchmarkLexReader measures lexer throughput over a large io.Reader input, read in chunks
+//
+func BenchmarkLexReader(b *testing.B) {
+	input := strings.Repeat("abcdefghij", 100000)
+	var fn Fn
+	fn = func(l *Lexer) Fn {
+		l.Next()
+		l.EmitType(TStart)
+		return fn
+	}
+	b.SetBytes(int64(len(input)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if count, _ := token.Drain(LexReader(struct{ io.Reader }{strings.NewReader(input)}, fn)); count != len(input) {
+			b.Fatalf("Drain expecting %d tokens, received %d", len(input), count)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+//
+//	lexdump reads input and prints the emitted token stream with types, values, and positions.
+//
+//	Usage:
+//
+//		lexdump [-format table|json|ndjson] -lexer name [file]
+//		lexdump -list
+//
+//	Built-in lexers:
+//
+//		runes - Emits each rune as a RUNE token
+//		lines - Emits each line (including its newline) as a LINE token
+//		words - Emits WORD, NUMBER, SPACE and PUNCT tokens
+//
+//	To dump tokens from your own lexers, register them with the lexdump package from your own main():
+//
+//		lexdump.Register("mylang", lexMyLang, typeNames)
+//		lexdump.Main()
+//
+
+import (
+	"unicode"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/lexdump"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// We define our lexer tokens starting from the pre-defined START token
+//
+const (
+	TRune token.Type = lexer.TStart + iota
+	TLine
+	TWord
+	TNumber
+	TSpace
+	TPunct
+)
+
+func main() {
+	lexdump.Register("runes", lexRunes, map[token.Type]string{TRune: "RUNE"})
+	lexdump.Register("lines", lexLines, map[token.Type]string{TLine: "LINE"})
+	lexdump.Register("words", lexWords, map[token.Type]string{
+		TWord:   "WORD",
+		TNumber: "NUMBER",
+		TSpace:  "SPACE",
+		TPunct:  "PUNCT",
+	})
+	lexdump.Main()
+}
+
+// lexRunes emits each rune as a TRune
+//
+func lexRunes(l *lexer.Lexer) lexer.Fn {
+	l.Next()
+	l.EmitToken(TRune)
+	return lexRunes
+}
+
+// lexLines emits each line, including its newline (if present), as a TLine
+//
+func lexLines(l *lexer.Lexer) lexer.Fn {
+	for l.CanPeek(1) {
+		if l.Next() == '\n' {
+			break
+		}
+	}
+	l.EmitToken(TLine)
+	return lexLines
+}
+
+// lexWords emits runs of letters, digits and whitespace as TWord, TNumber and TSpace, and all other runes as TPunct
+//
+func lexWords(l *lexer.Lexer) lexer.Fn {
+	var typ token.Type
+	var match func(rune) bool
+	switch r := l.Peek(1); {
+	case unicode.IsLetter(r):
+		typ, match = TWord, unicode.IsLetter
+	case unicode.IsDigit(r):
+		typ, match = TNumber, unicode.IsDigit
+	case unicode.IsSpace(r):
+		typ, match = TSpace, unicode.IsSpace
+	default:
+		l.Next()
+		l.EmitToken(TPunct)
+		return lexWords
+	}
+	for l.CanPeek(1) && match(l.Peek(1)) {
+		l.Next()
+	}
+	l.EmitToken(typ)
+	return lexWords
+}
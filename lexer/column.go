@@ -0,0 +1,86 @@
+package lexer
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// ColumnMode determines how the lexer advances the column number of the tokens it emits.
+// See WithColumns.
+//
+type ColumnMode int
+
+const (
+	// ColumnRunes advances the column by 1 for each rune (the default).
+	//
+	ColumnRunes ColumnMode = iota
+	// ColumnBytes advances the column by the UTF-8 encoded length of each rune.
+	//
+	ColumnBytes
+	// ColumnWidth advances the column by the display width of each rune: 2 for wide (i.e. CJK) runes, 0 for
+	// combining marks and other zero-width runes, 1 otherwise.
+	//
+	ColumnWidth
+)
+
+// String implements fmt.Stringer.
+//
+func (m ColumnMode) String() string {
+	switch m {
+	case ColumnRunes:
+		return "runes"
+	case ColumnBytes:
+		return "bytes"
+	case ColumnWidth:
+		return "width"
+	}
+	return "ColumnMode(?)"
+}
+
+// width returns the number of columns that r advances, per the column mode.
+//
+func (m ColumnMode) width(r rune) int {
+	switch m {
+	case ColumnBytes:
+		return utf8.RuneLen(r)
+	case ColumnWidth:
+		return runeWidth(r)
+	}
+	return 1
+}
+
+// wideRanges lists the (inclusive) ranges of wide runes, per the East Asian Width property (W and F).
+//
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},   // Hangul Jamo
+	{0x2E80, 0x303E},   // CJK Radicals .. CJK Symbols and Punctuation
+	{0x3041, 0x33FF},   // Hiragana .. CJK Compatibility
+	{0x3400, 0x4DBF},   // CJK Unified Ideographs Extension A
+	{0x4E00, 0x9FFF},   // CJK Unified Ideographs
+	{0xA000, 0xA4CF},   // Yi Syllables / Radicals
+	{0xAC00, 0xD7A3},   // Hangul Syllables
+	{0xF900, 0xFAFF},   // CJK Compatibility Ideographs
+	{0xFE30, 0xFE4F},   // CJK Compatibility Forms
+	{0xFF00, 0xFF60},   // Fullwidth Forms
+	{0xFFE0, 0xFFE6},   // Fullwidth Signs
+	{0x1F300, 0x1F64F}, // Misc Symbols and Pictographs, Emoticons
+	{0x1F900, 0x1F9FF}, // Supplemental Symbols and Pictographs
+	{0x20000, 0x3FFFD}, // CJK Unified Ideographs Extension B .. Tertiary Ideographic Plane
+}
+
+// runeWidth returns the display width of r.
+//
+func runeWidth(r rune) int {
+	if r == 0 || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	for _, w := range wideRanges {
+		if r < w[0] {
+			break
+		}
+		if r <= w[1] {
+			return 2
+		}
+	}
+	return 1
+}
@@ -0,0 +1,90 @@
+package lexer
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// lexFields emits each space-separated field as a token, discarding the spaces.
+//
+func lexFields(l *Lexer) Fn {
+	if l.Peek(1) == ' ' {
+		l.Next()
+		l.Clear()
+		return lexFields
+	}
+	for l.CanPeek(1) && l.Peek(1) != ' ' {
+		l.Next()
+	}
+	l.EmitToken(TStart)
+	return lexFields
+}
+
+// TestWithColumns
+//
+func TestWithColumns(t *testing.T) {
+	input := "é字 e\u0301x 字\ny"
+	tests := []struct {
+		mode    ColumnMode
+		columns []int
+	}{
+		{ColumnRunes, []int{1, 4, 8}},
+		{ColumnBytes, []int{1, 7, 12}},
+		{ColumnWidth, []int{1, 5, 8}},
+	}
+	for _, test := range tests {
+		nexter := LexString(input, lexFields, WithColumns(test.mode))
+		expectNexterNext(t, nexter, TStart, "é字", 1, test.columns[0])
+		expectNexterNext(t, nexter, TStart, "e\u0301x", 1, test.columns[1])
+		expectNexterNext(t, nexter, TStart, "字\ny", 1, test.columns[2])
+		expectNexterEOF(t, nexter)
+	}
+}
+
+// TestWithColumnsEnd
+//
+func TestWithColumnsEnd(t *testing.T) {
+	input := "é字 e\u0301x 字\ny"
+	tests := []struct {
+		mode ColumnMode
+		ends []string
+	}{
+		{ColumnRunes, []string{"1:3", "1:7", "2:2"}},
+		{ColumnBytes, []string{"1:6", "1:11", "2:2"}},
+		{ColumnWidth, []string{"1:4", "1:7", "2:2"}},
+	}
+	for _, test := range tests {
+		nexter := LexString(input, lexFields, WithColumns(test.mode))
+		for _, end := range test.ends {
+			tok, err := nexter.Next()
+			if err != nil {
+				t.Fatalf("Nexter.Next() expecting token, received error '%s'", err)
+			}
+			if s := token.End(tok).String(); s != end {
+				t.Errorf("token.End(%q) in %s mode expecting '%s', received '%s'", tok.Value(), test.mode, end, s)
+			}
+		}
+		expectNexterEOF(t, nexter)
+	}
+}
+
+// TestColumnModeString
+//
+func TestColumnModeString(t *testing.T) {
+	for mode, match := range map[ColumnMode]string{ColumnRunes: "runes", ColumnBytes: "bytes", ColumnWidth: "width", 99: "ColumnMode(?)"} {
+		if s := mode.String(); s != match {
+			t.Errorf("ColumnMode.String() expecting '%s', received '%s'", match, s)
+		}
+	}
+}
+
+// TestRuneWidth
+//
+func TestRuneWidth(t *testing.T) {
+	for r, match := range map[rune]int{'a': 1, 'é': 1, '\u0301': 0, '\u200b': 0, '字': 2, 'ｱ': 1, 'Ａ': 2, '😀': 2, 0x20001: 2} {
+		if w := runeWidth(r); w != match {
+			t.Errorf("runeWidth(%U) expecting %d, received %d", r, match, w)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package lexer
+
+import (
+	"strings"
+	"time"
+)
+
+// ScanDateTime matches an ISO-8601 (RFC 3339 profile) date, date-time or time literal, validating it as it is
+// consumed, and returns its parsed value, along with true if matched, i.e:
+//
+//  - Date      : 2006-01-02
+//  - Date-Time : 2006-01-02T15:04:05.999Z, 2006-01-02T15:04-07:00, 2006-01-02T15:04:05
+//  - Time      : 15:04:05.999, 15:04
+//
+// The 'T' and 'Z' may be lowercase; Seconds (and their fraction) are optional.
+// Values without a zone offset are returned in UTC, and times are returned on the zero date (0000-01-01).
+// The matched runes are added to the previously-matched runes; Emit them via EmitCooked to attach the value to the
+// token (see token.Cooked).
+// Returns false, with no runes matched, if no literal is found, or if it is invalid (i.e. 2006-02-30).
+//
+func ScanDateTime(l *Lexer) (time.Time, bool) {
+	s := &lookahead{l: l}
+	layout := ""
+	if s.digits(4) && s.rune("-") != 0 && s.digits(2) && s.rune("-") != 0 && s.digits(2) {
+		layout = "2006-01-02"
+		if m := s.n; s.rune("Tt") != 0 {
+			if s.clock() {
+				layout += "T" + s.layout
+			} else {
+				s.n = m // Just the date
+			}
+		}
+	} else {
+		s.n = 0
+		if !s.clock() {
+			return time.Time{}, false
+		}
+		layout = s.layout
+	}
+	value := strings.NewReplacer("t", "T", "z", "Z").Replace(s.text())
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	s.match()
+	return t, true
+}
+
+// ScanDuration matches a Go-style duration literal (see time.ParseDuration), validating it as it is consumed, and
+// returns its parsed value, along with true if matched, i.e. "1h30m", "-1.5s" or "300ms".
+// Valid units are "ns", "us" (or "µs" / "μs"), "ms", "s", "m" and "h"; A unit is required, except for "0".
+// The matched runes are added to the previously-matched runes; Emit them via EmitCooked to attach the value to the
+// token (see token.Cooked).
+// Returns false, with no runes matched, if no literal is found, or if it is invalid (i.e. it overflows).
+//
+func ScanDuration(l *Lexer) (time.Duration, bool) {
+	s := &lookahead{l: l}
+	s.rune("+-")
+	units := 0
+	for {
+		m := s.n
+		whole := s.digitRun()
+		fraction := s.rune(".") != 0 && s.digitRun()
+		if !(whole || fraction) || !s.unit() {
+			s.n = m
+			break
+		}
+		units++
+	}
+	if units == 0 {
+		// Allow a bare "0"
+		//
+		if s.rune("0") == 0 || s.rune("0123456789.") != 0 {
+			return 0, false
+		}
+	}
+	d, err := time.ParseDuration(s.text())
+	if err != nil {
+		return 0, false
+	}
+	s.match()
+	return d, true
+}
+
+// lookahead peeks ahead at the input, allowing literals to be validated before they are matched.
+//
+type lookahead struct {
+	l      *Lexer
+	n      int    // Runes peeked
+	layout string // Time layout of the clock peeked - see clock()
+}
+
+// text returns the runes peeked.
+//
+func (s *lookahead) text() string {
+	b := &strings.Builder{}
+	for i := 1; i <= s.n; i++ {
+		b.WriteRune(s.l.Peek(i))
+	}
+	return b.String()
+}
+
+// match matches the runes peeked.
+//
+func (s *lookahead) match() {
+	for ; s.n > 0; s.n-- {
+		s.l.Next()
+	}
+}
+
+// rune peeks at the next rune, accepting it if it is in set, returning the rune accepted, or 0.
+//
+func (s *lookahead) rune(set string) rune {
+	if !s.l.CanPeek(s.n + 1) {
+		return 0
+	}
+	r := s.l.Peek(s.n + 1)
+	if !strings.ContainsRune(set, r) {
+		return 0
+	}
+	s.n++
+	return r
+}
+
+// digits accepts exactly k digits, returning true if accepted.
+// On failure, some digits may have been accepted.
+//
+func (s *lookahead) digits(k int) bool {
+	for ; k > 0; k-- {
+		if s.rune("0123456789") == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// digitRun accepts 1 or more digits, returning true if any accepted.
+//
+func (s *lookahead) digitRun() bool {
+	n := s.n
+	for s.rune("0123456789") != 0 {
+	}
+	return s.n > n
+}
+
+// unit accepts a duration unit, returning true if accepted.
+//
+func (s *lookahead) unit() bool {
+	switch s.rune("nu\u00b5\u03bcmsh") {
+	case 'n', 'u', '\u00b5', '\u03bc':
+		return s.rune("s") != 0
+	case 'm':
+		s.rune("s") // "m" or "ms"
+		return true
+	case 's', 'h':
+		return true
+	}
+	return false
+}
+
+// clock accepts a time ("15:04", with optional seconds, fraction and zone), recording its layout, returning true if
+// accepted.
+//
+func (s *lookahead) clock() bool {
+	if !s.digits(2) || s.rune(":") == 0 || !s.digits(2) {
+		return false
+	}
+	s.layout = "15:04"
+	if m := s.n; s.rune(":") != 0 && s.digits(2) {
+		s.layout += ":05"
+		if m := s.n; s.rune(".") != 0 && !s.digitRun() {
+			s.n = m
+		}
+	} else {
+		s.n = m
+	}
+	m := s.n
+	switch s.rune("Zz+-") {
+	case 'Z', 'z':
+		s.layout += "Z07:00"
+	case '+', '-':
+		if s.digits(2) && s.rune(":") != 0 && s.digits(2) {
+			s.layout += "Z07:00"
+		} else {
+			s.n = m
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package lexer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestScanDateTime
+//
+func TestScanDateTime(t *testing.T) {
+	tests := []struct {
+		input    string
+		literal  string
+		expected time.Time
+	}{
+		{"2006-01-02 x", "2006-01-02", time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"2006-01-02T15:04:05Z", "2006-01-02T15:04:05Z", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"2006-01-02t15:04:05.25z", "2006-01-02t15:04:05.25z", time.Date(2006, 1, 2, 15, 4, 5, 250000000, time.UTC)},
+		{"2006-01-02T15:04-07:00", "2006-01-02T15:04-07:00", time.Date(2006, 1, 2, 22, 4, 0, 0, time.UTC)},
+		{"2006-01-02T15:04:05", "2006-01-02T15:04:05", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"2006-01-02Tx", "2006-01-02", time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"15:04:05.5", "15:04:05.5", time.Date(0, 1, 1, 15, 4, 5, 500000000, time.UTC)},
+		{"15:04:x", "15:04", time.Date(0, 1, 1, 15, 4, 0, 0, time.UTC)},
+		{"2006-02-30", "", time.Time{}},
+		{"25:00", "", time.Time{}},
+		{"2006-1-02", "", time.Time{}},
+		{"abc", "", time.Time{}},
+	}
+	for _, test := range tests {
+		fn := func(l *Lexer) Fn {
+			if v, ok := ScanDateTime(l); ok {
+				l.EmitCooked(TStart, v)
+			} else if l.PeekToken() != "" {
+				t.Errorf("ScanDateTime('%s') matched runes on failure", test.input)
+			}
+			return nil
+		}
+		tok, err := LexString(test.input, fn).Next()
+		switch {
+		case test.literal == "" && err == nil:
+			t.Errorf("ScanDateTime('%s') expecting no match, received '%v'", test.input, tok.Value())
+		case test.literal == "":
+		case err != nil:
+			t.Errorf("ScanDateTime('%s') expecting '%s', received %v", test.input, test.literal, err)
+		case tok.Value() != test.literal:
+			t.Errorf("ScanDateTime('%s') expecting '%s', received '%s'", test.input, test.literal, tok.Value())
+		case !token.Cooked(tok).(time.Time).Equal(test.expected):
+			t.Errorf("ScanDateTime('%s') expecting %v, received %v", test.input, test.expected, token.Cooked(tok))
+		}
+	}
+}
+
+// TestScanDuration
+//
+func TestScanDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		literal  string
+		expected time.Duration
+	}{
+		{"1h30m x", "1h30m", 90 * time.Minute},
+		{"-1.5s", "-1.5s", -1500 * time.Millisecond},
+		{"300ms", "300ms", 300 * time.Millisecond},
+		{"2us3ns", "2us3ns", 2003 * time.Nanosecond},
+		{"1\u00b5s", "1\u00b5s", time.Microsecond},
+		{".5m", ".5m", 30 * time.Second},
+		{"0", "0", 0},
+		{"5mx", "5m", 5 * time.Minute},
+		{"5", "", 0},
+		{"05", "", 0},
+		{"1h5", "1h", time.Hour},
+		{"9999999999h", "", 0},
+		{"-", "", 0},
+	}
+	for _, test := range tests {
+		fn := func(l *Lexer) Fn {
+			if v, ok := ScanDuration(l); ok {
+				l.EmitCooked(TStart, v)
+			} else if l.PeekToken() != "" {
+				t.Errorf("ScanDuration('%s') matched runes on failure", test.input)
+			}
+			return nil
+		}
+		tok, err := LexString(test.input, fn).Next()
+		switch {
+		case test.literal == "" && err == nil:
+			t.Errorf("ScanDuration('%s') expecting no match, received '%v'", test.input, tok.Value())
+		case test.literal == "":
+		case err != nil:
+			t.Errorf("ScanDuration('%s') expecting '%s', received %v", test.input, test.literal, err)
+		case tok.Value() != test.literal:
+			t.Errorf("ScanDuration('%s') expecting '%s', received '%s'", test.input, test.literal, tok.Value())
+		case token.Cooked(tok) != test.expected:
+			t.Errorf("ScanDuration('%s') expecting %v, received %v", test.input, test.expected, token.Cooked(tok))
+		}
+	}
+}
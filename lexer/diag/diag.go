@@ -0,0 +1,123 @@
+/*
+Package diag collects the diagnostics (errors, warnings, etc) reported while lexing and parsing.
+
+Unlike emitted errors, which are returned in place of a token or AST, diagnostics are gathered into a Collector,
+allowing non-fatal issues (i.e. deprecated syntax) to be reported while processing continues:
+
+	diags := diag.NewCollector()
+	tokens := lexer.LexString(input, lexStart, lexer.WithDiagnostics(diags))
+	asts := parser.Parse(tokens, parseStart, parser.WithDiagnostics(diags))
+	...
+	for _, d := range diags.Diagnostics() {
+		fmt.Println(d) // i.e. "3:7: warning: 'var' is deprecated"
+	}
+
+A single Collector can be shared by the lexer and the parser, and is safe for concurrent use.
+
+*/
+package diag
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Severity classifies a diagnostic.
+//
+type Severity int
+
+// Diagnostic severities, from most to least severe.
+//
+const (
+	Error   Severity = iota // A problem that prevents a valid result
+	Warning                 // A problem that does not prevent a valid result, i.e. deprecated syntax
+	Info                    // Informational
+	Hint                    // A suggestion, i.e. a simpler alternative
+)
+
+// severityNames
+//
+var severityNames = [...]string{"error", "warning", "info", "hint"}
+
+// String returns the severity name, i.e. "error" or "warning".
+//
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// Diagnostic describes a single issue within the input.
+//
+type Diagnostic struct {
+	Severity Severity
+	Span     token.Span // Input covered by the diagnostic, Start == End if covering no input
+	Message  string
+}
+
+// String returns the diagnostic formatted as "line:column: severity: message".
+//
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s", d.Span.Start, d.Severity, d.Message)
+}
+
+// Collector gathers diagnostics, in the order they are reported.
+// Safe for concurrent use.
+//
+type Collector struct {
+	mu    sync.Mutex
+	diags []Diagnostic
+}
+
+// NewCollector returns a new, empty, Collector.
+//
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Add records a diagnostic.
+//
+func (c *Collector) Add(d Diagnostic) {
+	c.mu.Lock()
+	c.diags = append(c.diags, d)
+	c.mu.Unlock()
+}
+
+// Report records a diagnostic with the formatted message.
+// This is a convenience method that simply sends the diagnostic to Add().
+//
+func (c *Collector) Report(severity Severity, span token.Span, format string, args ...interface{}) {
+	c.Add(Diagnostic{Severity: severity, Span: span, Message: fmt.Sprintf(format, args...)})
+}
+
+// Diagnostics returns the recorded diagnostics, in the order they were reported.
+// The returned slice is a copy and can be retained.
+//
+func (c *Collector) Diagnostics() []Diagnostic {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Diagnostic{}, c.diags...)
+}
+
+// Count returns the number of recorded diagnostics with the specified severity.
+//
+func (c *Collector) Count(severity Severity) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, d := range c.diags {
+		if d.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// HasErrors confirms if any diagnostics with Error severity have been recorded.
+//
+func (c *Collector) HasErrors() bool {
+	return c.Count(Error) > 0
+}
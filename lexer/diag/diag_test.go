@@ -0,0 +1,46 @@
+package diag
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestSeverityString
+//
+func TestSeverityString(t *testing.T) {
+	for s, match := range map[Severity]string{Error: "error", Warning: "warning", Info: "info", Hint: "hint", 9: "Severity(9)", -1: "Severity(-1)"} {
+		if name := s.String(); name != match {
+			t.Errorf("Severity.String() expecting '%s', received '%s'", match, name)
+		}
+	}
+}
+
+// TestCollector
+//
+func TestCollector(t *testing.T) {
+	c := NewCollector()
+	if c.HasErrors() || len(c.Diagnostics()) != 0 {
+		t.Error("Collector expecting no diagnostics")
+	}
+	span := token.Span{Start: token.Position{Line: 1, Column: 5}, End: token.Position{Line: 1, Column: 8}}
+	c.Report(Warning, span, "'%s' is deprecated", "var")
+	c.Add(Diagnostic{Severity: Error, Span: span, Message: "oops"})
+	c.Report(Warning, span, "again")
+	diags := c.Diagnostics()
+	if len(diags) != 3 {
+		t.Fatalf("Collector.Diagnostics() expecting 3 diagnostics, received %d", len(diags))
+	}
+	if s := diags[0].String(); s != "1:5: warning: 'var' is deprecated" {
+		t.Errorf("Diagnostic.String() expecting '1:5: warning: 'var' is deprecated', received '%s'", s)
+	}
+	if s := diags[1].String(); s != "1:5: error: oops" {
+		t.Errorf("Diagnostic.String() expecting '1:5: error: oops', received '%s'", s)
+	}
+	if n := c.Count(Warning); n != 2 {
+		t.Errorf("Collector.Count(Warning) expecting 2, received %d", n)
+	}
+	if !c.HasErrors() {
+		t.Error("Collector.HasErrors() expecting true")
+	}
+}
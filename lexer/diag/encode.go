@@ -0,0 +1,180 @@
+package diag
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// jsonPosition is the JSON encoding of a token.Position.
+//
+type jsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// jsonDiagnostic is the JSON encoding of a Diagnostic.
+//
+type jsonDiagnostic struct {
+	Severity string       `json:"severity"`
+	Message  string       `json:"message"`
+	Start    jsonPosition `json:"start"`
+	End      jsonPosition `json:"end"`
+}
+
+// WriteJSON writes the diagnostics as a JSON array, followed by a newline, i.e.
+//
+//	[{"severity":"warning","message":"'var' is deprecated","start":{"line":3,"column":7},"end":{"line":3,"column":10}}]
+//
+// An empty (or nil) slice is written as [].
+//
+func WriteJSON(w io.Writer, diags []Diagnostic) error {
+	out := make([]jsonDiagnostic, len(diags))
+	for i, d := range diags {
+		out[i] = jsonDiagnostic{
+			Severity: d.Severity.String(),
+			Message:  d.Message,
+			Start:    jsonPosition{Line: d.Span.Start.Line, Column: d.Span.Start.Column},
+			End:      jsonPosition{Line: d.Span.End.Line, Column: d.Span.End.Column},
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(out)
+}
+
+// SARIFVersion is the version of the SARIF format written by WriteSARIF.
+//
+const SARIFVersion = "2.1.0"
+
+// sarifSchema is the JSON schema of the SARIF format written by WriteSARIF.
+//
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifLog is the root of a SARIF document.
+//
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+// sarifRun is a single run of a tool.
+//
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+// sarifTool identifies the tool that produced the results.
+//
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+// sarifDriver names the tool.
+//
+type sarifDriver struct {
+	Name string `json:"name"`
+}
+
+// sarifResult is a single diagnostic.
+//
+type sarifResult struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+// sarifMessage is the text of a result.
+//
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// sarifLocation locates a result within an artifact (file).
+//
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+// sarifPhysicalLocation is the artifact and region of a result.
+//
+type sarifPhysicalLocation struct {
+	ArtifactLocation *sarifArtifactLocation `json:"artifactLocation,omitempty"`
+	Region           *sarifRegion           `json:"region,omitempty"`
+}
+
+// sarifArtifactLocation identifies an artifact.
+//
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifRegion is a range of lines and columns within an artifact; SARIF lines and columns are 1-based, with endColumn
+// exclusive.
+//
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// sarifLevels maps severities to SARIF levels
+//
+var sarifLevels = map[Severity]string{Error: "error", Warning: "warning", Info: "note", Hint: "note"}
+
+// WriteSARIF writes the diagnostics as a SARIF (Static Analysis Results Interchange Format) log, for integration with
+// code-review systems and editors.
+// The log contains a single run of the named tool, with a result for each diagnostic, located within file (i.e. a
+// path relative to the repository root); If file is "", results are located by region only.
+// Info and Hint severities are written with the "note" level.
+// Diagnostics with invalid positions are written without a region.
+//
+func WriteSARIF(w io.Writer, tool string, file string, diags []Diagnostic) error {
+	results := make([]sarifResult, len(diags))
+	for i, d := range diags {
+		level, ok := sarifLevels[d.Severity]
+		if !ok {
+			level = "none"
+		}
+		results[i] = sarifResult{Level: level, Message: sarifMessage{Text: d.Message}}
+		loc := sarifPhysicalLocation{Region: sarifRegionOf(d.Span)}
+		if file != "" {
+			loc.ArtifactLocation = &sarifArtifactLocation{URI: file}
+		}
+		if loc.ArtifactLocation != nil || loc.Region != nil {
+			results[i].Locations = []sarifLocation{{PhysicalLocation: loc}}
+		}
+	}
+	log := sarifLog{
+		Version: SARIFVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: sarifDriver{Name: tool}}, Results: results}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// sarifRegionOf converts the span to a SARIF region, returning nil if the span start is not a valid 1-based position.
+// An end position at column 0 (i.e. following a newline) is converted to column 1 of the same line; End positions
+// that are invalid, or that precede the start, are omitted.
+//
+func sarifRegionOf(span token.Span) *sarifRegion {
+	start, end := span.Start, span.End
+	if start.Line < 1 || start.Column < 1 {
+		return nil
+	}
+	r := &sarifRegion{StartLine: start.Line, StartColumn: start.Column}
+	if end.Column == 0 {
+		end.Column = 1
+	}
+	if end.Line > start.Line || (end.Line == start.Line && end.Column >= start.Column) {
+		r.EndLine, r.EndColumn = end.Line, end.Column
+	}
+	return r
+}
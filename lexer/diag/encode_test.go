@@ -0,0 +1,114 @@
+package diag
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// testDiags covers each severity, and an invalid position
+//
+var testDiags = []Diagnostic{
+	{Severity: Warning, Span: token.Span{Start: token.Position{Line: 3, Column: 7}, End: token.Position{Line: 3, Column: 10}}, Message: "'var' is deprecated"},
+	{Severity: Error, Span: token.Span{Start: token.Position{Line: 1, Column: 5}, End: token.Position{Line: 2, Column: 0}}, Message: "expected <expr>"},
+	{Severity: Hint, Span: token.Span{Start: token.Position{Line: -1, Column: -1}, End: token.Position{Line: -1, Column: -1}}, Message: "end"},
+}
+
+// TestWriteJSON
+//
+func TestWriteJSON(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := WriteJSON(b, testDiags[:2]); err != nil {
+		t.Fatalf("WriteJSON() received unexpected error '%s'", err)
+	}
+	expected := `[{"severity":"warning","message":"'var' is deprecated","start":{"line":3,"column":7},"end":{"line":3,"column":10}},` +
+		`{"severity":"error","message":"expected <expr>","start":{"line":1,"column":5},"end":{"line":2,"column":0}}]` + "\n"
+	if b.String() != expected {
+		t.Errorf("WriteJSON() expecting:\n%s\nreceived:\n%s", expected, b.String())
+	}
+	b.Reset()
+	if err := WriteJSON(b, nil); err != nil || b.String() != "[]\n" {
+		t.Errorf("WriteJSON(nil) expecting '[]', received '%s' (%v)", b.String(), err)
+	}
+}
+
+// TestWriteSARIF
+//
+func TestWriteSARIF(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := WriteSARIF(b, "mylint", "src/main.x", testDiags); err != nil {
+		t.Fatalf("WriteSARIF() received unexpected error '%s'", err)
+	}
+	var log struct {
+		Version string
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name string
+				}
+			}
+			Results []struct {
+				Level   string
+				Message struct {
+					Text string
+				}
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation *struct {
+							URI string
+						}
+						Region *sarifRegion
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(b.Bytes(), &log); err != nil {
+		t.Fatalf("WriteSARIF() wrote invalid JSON: %s", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || log.Runs[0].Tool.Driver.Name != "mylint" {
+		t.Fatalf("WriteSARIF() unexpected log: %s", b.String())
+	}
+	results := log.Runs[0].Results
+	if len(results) != 3 {
+		t.Fatalf("WriteSARIF() expecting 3 results, received %d", len(results))
+	}
+	expected := []struct {
+		level  string
+		text   string
+		region *sarifRegion
+	}{
+		{"warning", "'var' is deprecated", &sarifRegion{StartLine: 3, StartColumn: 7, EndLine: 3, EndColumn: 10}},
+		{"error", "expected <expr>", &sarifRegion{StartLine: 1, StartColumn: 5, EndLine: 2, EndColumn: 1}},
+		{"note", "end", nil},
+	}
+	for i, r := range results {
+		e := expected[i]
+		if r.Level != e.level || r.Message.Text != e.text {
+			t.Errorf("WriteSARIF() result %d expecting (%s, %s), received (%s, %s)", i, e.level, e.text, r.Level, r.Message.Text)
+		}
+		if len(r.Locations) != 1 || r.Locations[0].PhysicalLocation.ArtifactLocation == nil ||
+			r.Locations[0].PhysicalLocation.ArtifactLocation.URI != "src/main.x" {
+			t.Errorf("WriteSARIF() result %d expecting location in src/main.x", i)
+			continue
+		}
+		region := r.Locations[0].PhysicalLocation.Region
+		if (region == nil) != (e.region == nil) || (region != nil && *region != *e.region) {
+			t.Errorf("WriteSARIF() result %d expecting region %v, received %v", i, e.region, region)
+		}
+	}
+}
+
+// TestWriteSARIFNoFile
+//
+func TestWriteSARIFNoFile(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := WriteSARIF(b, "mylint", "", testDiags[2:]); err != nil {
+		t.Fatalf("WriteSARIF() received unexpected error '%s'", err)
+	}
+	if bytes.Contains(b.Bytes(), []byte(`"locations"`)) {
+		t.Errorf("WriteSARIF() expecting no locations, received:\n%s", b.String())
+	}
+}
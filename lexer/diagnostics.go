@@ -0,0 +1,46 @@
+package lexer
+
+import (
+	"fmt"
+
+	"github.com/tekwizely/go-parsing/lexer/diag"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Diagnostics returns the collector receiving diagnostics from the lexer, as specified via WithDiagnostics.
+// Returns nil if diagnostics are not being collected.
+//
+func (l *Lexer) Diagnostics() *diag.Collector {
+	return l.diags
+}
+
+// EmitWarning reports a warning with the specified text, covering the previously-matched runes.
+// Unlike EmitError, no token is emitted and the matched runes are retained, allowing lexing to continue normally;
+// The warning is recorded by the diagnostics collector (see WithDiagnostics), and is dropped if there is none.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (l *Lexer) EmitWarning(msg string) {
+	// Nothing can be emitted after EOF emitted
+	//
+	if l.afterEOF("Lexer.EmitWarning: No further emits allowed after EOF is emitted") {
+		return
+	}
+	if l.diags != nil {
+		l.diags.Add(diag.Diagnostic{Severity: diag.Warning, Span: l.matchSpan(), Message: msg})
+	}
+}
+
+// EmitWarningf reports a warning with the formatted text, covering the previously-matched runes.
+// Panics if EOF already emitted (see WithLenient).
+// This is a convenience method that simply sends the formatted string to EmitWarning().
+//
+func (l *Lexer) EmitWarningf(format string, args ...interface{}) {
+	l.EmitWarning(fmt.Sprintf(format, args...))
+}
+
+// matchSpan computes the span of the matched runes, without consuming them.
+// See pendingSpan.
+//
+func (l *Lexer) matchSpan() token.Span {
+	return l.pendingSpan(nil)
+}
@@ -0,0 +1,65 @@
+package lexer
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/diag"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestWithDiagnostics
+//
+func TestWithDiagnostics(t *testing.T) {
+	c := diag.NewCollector()
+	fn := func(l *Lexer) Fn {
+		if l.Diagnostics() != c {
+			t.Error("Lexer.Diagnostics() expecting collector from WithDiagnostics")
+		}
+		expectNextString(t, l, "var")
+		l.EmitWarningf("'%s' is deprecated", l.PeekToken())
+		l.EmitToken(TStart)
+		expectNextString(t, l, " \nx")
+		l.EmitWarning("spans lines")
+		l.EmitError("bad")
+		return nil
+	}
+	nexter := LexString("var \nx", fn, WithDiagnostics(c))
+	expectNexterNext(t, nexter, TStart, "var", 1, 1)
+	expectNexterError(t, nexter, "2:2: bad")
+	expectNexterEOF(t, nexter)
+	expected := []diag.Diagnostic{
+		{Severity: diag.Warning, Span: token.Span{Start: token.Position{Line: 1, Column: 1}, End: token.Position{Line: 1, Column: 4}}, Message: "'var' is deprecated"},
+		{Severity: diag.Warning, Span: token.Span{Start: token.Position{Line: 1, Column: 4}, End: token.Position{Line: 2, Column: 2}}, Message: "spans lines"},
+		{Severity: diag.Error, Span: token.Span{Start: token.Position{Line: 2, Column: 2}, End: token.Position{Line: 2, Column: 2}}, Message: "bad"},
+	}
+	diags := c.Diagnostics()
+	if len(diags) != len(expected) {
+		t.Fatalf("Collector.Diagnostics() expecting %d diagnostics, received %d", len(expected), len(diags))
+	}
+	for i, d := range diags {
+		if d != expected[i] {
+			t.Errorf("Collector.Diagnostics()[%d] expecting '%v', received '%v'", i, expected[i], d)
+		}
+	}
+}
+
+// TestEmitWarningWithoutDiagnostics
+//
+func TestEmitWarningWithoutDiagnostics(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		if l.Diagnostics() != nil {
+			t.Error("Lexer.Diagnostics() expecting nil")
+		}
+		l.Next()
+		l.EmitWarning("dropped")
+		l.EmitToken(TStart)
+		l.EmitEOF()
+		assertPanic(t, func() {
+			l.EmitWarning("after EOF")
+		}, "Lexer.EmitWarning: No further emits allowed after EOF is emitted")
+		return nil
+	}
+	nexter := LexString("a", fn)
+	expectNexterNext(t, nexter, TStart, "a", 1, 1)
+	expectNexterEOF(t, nexter)
+}
@@ -16,23 +16,156 @@ Lexing is initiated through various Lex* methods, each accepting a different typ
 
 	// Input Type: string
 	//
-	func LexString(input string, start lexer.Fn) token.Nexter
+	func LexString(input string, start lexer.Fn, opts ...lexer.Option) token.Nexter
 
 	// Input Type: io.RuneReader
 	//
-	func LexRuneReader(input io.RuneReader, start lexer.Fn) token.Nexter
+	func LexRuneReader(input io.RuneReader, start lexer.Fn, opts ...lexer.Option) token.Nexter
 
 	// Input Type: io.Reader
 	//
-	func LexReader(input io.Reader, start lexer.Fn) token.Nexter
+	func LexReader(input io.Reader, start lexer.Fn, opts ...lexer.Option) token.Nexter
 
 	// Input Type: []rune
 	//
-	func LexRunes(input []rune, start lexer.Fn) token.Nexter
+	func LexRunes(input []rune, start lexer.Fn, opts ...lexer.Option) token.Nexter
 
 	// Input Type: []byte
 	//
-	func LexBytes(input []byte, start lexer.Fn) token.Nexter
+	func LexBytes(input []byte, start lexer.Fn, opts ...lexer.Option) token.Nexter
+
+	// Input Type: *source.File (see the source package)
+	//
+	func LexSource(file *source.File, start lexer.Fn, opts ...lexer.Option) token.Nexter
+
+
+Lexer Options
+
+Each Lex function accepts optional configuration via lexer.Option values:
+
+	// WithContext sets the initial user context value of the lexer.
+	//
+	func WithContext(ctx interface{}) lexer.Option
+
+	// WithLenient converts post-EOF usage panics into no-ops that return zero values.
+	// The first such usage is recorded and can be retrieved via Lexer.MisuseError().
+	// The same error is returned once by the token.Nexter, just before io.EOF.
+	//
+	func WithLenient() lexer.Option
+
+	// WithTrace registers a function to receive trace events for lexer activity (function enter/exit, Next/Peek,
+	// emits, markers, Clear), including positions.
+	//
+	func WithTrace(fn func(event TraceEvent)) lexer.Option
+
+	// WithRecorder records the sequence of lexer function transitions (with positions), for dumping via Recorder.Dump().
+	//
+	func WithRecorder(r *Recorder) lexer.Option
+
+	// WithLoopGuard aborts with an error, naming the function and position, after limit consecutive lexer
+	// function calls without consuming input or emitting tokens.
+	//
+	func WithLoopGuard(limit int) lexer.Option
+
+	// WithStats records counters (see Stats) into the specified Stats, for retrieval after lexing completes.
+	// Stats can be published via expvar, or retrieved from within your lexer functions via Lexer.Stats().
+	//
+	func WithStats(s *Stats) lexer.Option
+
+	// WithProgress registers a function to receive progress reports (every 64KiB of input read, and at the end of
+	// the input), allowing tools that lex large inputs to render progress bars.
+	//
+	func WithProgress(fn func(bytesRead, runesRead int64)) lexer.Option
+
+	// WithOutputBuffer batches emitted tokens, delivering them once at least n are pending (or on EOF / Flush()),
+	// allowing lexer functions that emit many tokens in a single call to do so without growing the buffer.
+	//
+	func WithOutputBuffer(n int) lexer.Option
+
+	// WithUnknown consumes one rune and emits it as TUnknown (or calls fallback, if not nil) whenever the start function
+	// returns itself without consuming input or emitting tokens, preventing the lexer from stalling.
+	//
+	func WithUnknown(fallback Fn) lexer.Option
+
+	// WithColumns sets how the column advances: by rune (ColumnRunes, the default), by byte (ColumnBytes), or by
+	// display width (ColumnWidth, wide CJK runes = 2), so diagnostics align with different editor conventions.
+	//
+	func WithColumns(mode ColumnMode) lexer.Option
+
+	// WithNormalization applies Unicode normalization (i.e. norm.NFC from golang.org/x/text/unicode/norm) to the
+	// input before lexing; The form name is reported via Lexer.Normalization().
+	//
+	func WithNormalization(form string, n Normalizer) lexer.Option
+
+	// WithLineTable records the line offsets of the input into t, also available via Lexer.LineTable(), for converting
+	// byte offsets to/from line/column positions after lexing.
+	//
+	func WithLineTable(t *LineTable) lexer.Option
+
+	// WithDiagnostics records warnings (see Lexer.EmitWarning) and errors into the specified collector, which can be
+	// shared with the parser (see the diag package).
+	//
+	func WithDiagnostics(c *diag.Collector) lexer.Option
+
+	// WithMaxErrors stops lexing once n errors have been emitted, following the last with a "too many errors" error.
+	//
+	func WithMaxErrors(n int) lexer.Option
+
+	// WithReadTimeout returns ErrReadTimeout from the token.Nexter if the input blocks for longer than d while waiting
+	// for the start of the next token; Calling Next() again resumes waiting.
+	//
+	func WithReadTimeout(d time.Duration) lexer.Option
+
+	// WithProfiler records per-function cumulative time and per-type token counts, for finding hot spots in your
+	// lexer rules; Reports can be written as CSV (Profiler.WriteCSV) or as a pprof profile (Profiler.WritePprof).
+	//
+	func WithProfiler(p *Profiler) lexer.Option
+
+	// WithArena allocates emitted tokens from the specified Arena, cutting GC pressure for batch tools; Release the
+	// arena all at once via the Release() method of the token.Nexter, once done with the tokens.
+	//
+	func WithArena(a *Arena) lexer.Option
+
+	// WithLazyValues defers building token value strings until Token.Value() is first called, so parsers that only
+	// inspect Token.Type() for most tokens (punctuation, keywords) skip the string allocation entirely.
+	//
+	func WithLazyValues() lexer.Option
+
+	// WithReadSize sets the size of the chunks read from io.Reader inputs (see LexReader), in bytes; The default is 64KB.
+	//
+	func WithReadSize(n int) lexer.Option
+
+	// WithLineTerminators sets which runes are recognized as line terminators: '\n' (LineLF, the default), plus '\r'
+	// with "\r\n" as a single terminator (LineCRLF), and/or NEL, U+2028 and U+2029 (LineUnicode).
+	//
+	func WithLineTerminators(mode LineMode) lexer.Option
+
+	// WithPrescan adds hooks that consume header constructs (i.e. shebang lines, encoding declarations and pragma
+	// comments) before lexing starts, recording them as headers - see Lexer.Headers() and Lexer.Header().
+	//
+	func WithPrescan(hooks ...Prescan) lexer.Option
+
+	// WithInteractive enables interactive mode for REPLs; Errors emitted once the input is exhausted (i.e. an
+	// unterminated string) are reported as incomplete - see token.IsIncomplete().
+	//
+	func WithInteractive() lexer.Option
+
+	// WithMaxTokens stops lexing with a "too many tokens" error once more than n tokens have been emitted, a guardrail
+	// when lexing untrusted input with pathological token densities.
+	//
+	func WithMaxTokens(n int) lexer.Option
+
+
+Reusing Lexers
+
+Servers lexing many small payloads can reuse lexers via a Pool, avoiding per-input allocation of the lexer machinery:
+
+	pool := lexer.NewPool(opts...)
+	tokens := pool.Lex(input, start)
+	// ... consume tokens ...
+	pool.Put(tokens)
+
+Pooled lexers are re-initialized via Lexer.Reset(), which retains the options and internal buffers.
 
 
 Lexer Functions
@@ -79,6 +212,20 @@ All previously emitted tokens will still be available for pickup, but the lexer
 calls.
 
 
+Lexer User Context
+
+State, such as interpolation depth or pragma flags, can travel with the lexer, instead of requiring closures or
+global variables:
+
+	// Context returns the user context value of the lexer.
+	//
+	func (l *Lexer) Context() interface{}
+
+	// SetContext sets the user context value of the lexer.
+	//
+	func (l *Lexer) SetContext(ctx interface{})
+
+
 Scanning Runes
 
 Your Lexer function receives a `*Lexer` when called and can use the following methods to inspect and match runes:
@@ -99,6 +246,66 @@ Your Lexer function receives a `*Lexer` when called and can use the following me
 	//
 	func (l *Lexer) PeekToken() string
 
+	// Pending returns the currently matched rune sequence, along with its start and end positions.
+	//
+	func (l *Lexer) Pending() (text string, start token.Position, end token.Position)
+
+	// TokenReader returns an io.Reader over the currently matched rune sequence, for streaming large matches.
+	//
+	func (l *Lexer) TokenReader() io.Reader
+
+	// LastEmitted returns the token most recently emitted by the lexer, or nil if none.
+	//
+	func (l *Lexer) LastEmitted() token.Token
+
+Defensive code paths can use the non-panicking variants, which report availability instead of requiring
+CanPeek-then-Peek pairs:
+
+	// TryPeek is a non-panicking variant of Peek.
+	//
+	func (l *Lexer) TryPeek(n int) (rune, bool)
+
+	// TryNext is a non-panicking variant of Next.
+	//
+	func (l *Lexer) TryNext() (rune, bool)
+
+Lexer also implements io.RuneScanner, so rune-based helpers from other libraries can be pointed directly at the
+lexer's input, with the runes they consume becoming part of the current match:
+
+	// ReadRune implements io.RuneScanner.ReadRune(), matching the next rune.
+	//
+	func (l *Lexer) ReadRune() (rune, int, error)
+
+	// UnreadRune implements io.RuneScanner.UnreadRune(), un-matching the last matched rune.
+	//
+	func (l *Lexer) UnreadRune() error
+
+PEG-style negative lookahead can be expressed without managing markers yourself:
+
+	// NotFollowedBy calls match, then resets the lexer to its prior state, returning true if match returned false.
+	//
+	func (l *Lexer) NotFollowedBy(match func(*Lexer) bool) bool
+
+ScanIdent matches an identifier per a language profile, i.e. IdentC, IdentXID (Unicode, per UAX #31), IdentKebab or
+IdentSQL (including quoted identifiers, see IdentProfile.Unquote):
+
+	// ScanIdent matches an identifier per the specified profile, returning true if matched.
+	//
+	func ScanIdent(l *Lexer, profile *IdentProfile) bool
+
+ScanDateTime and ScanDuration match ISO-8601 dates / times and Go-style durations, validating them as they are
+consumed, and return their parsed values, i.e. for use with EmitCooked:
+
+	// ScanDateTime matches an ISO-8601 (RFC 3339 profile) date, date-time or time literal, validating it as it is
+	// consumed, and returns its parsed value, along with true if matched.
+	//
+	func ScanDateTime(l *Lexer) (time.Time, bool)
+
+	// ScanDuration matches a Go-style duration literal (see time.ParseDuration), validating it as it is consumed, and
+	// returns its parsed value, along with true if matched, i.e. "1h30m", "-1.5s" or "300ms".
+	//
+	func ScanDuration(l *Lexer) (time.Duration, bool)
+
 
 Emitting Tokens
 
@@ -113,8 +320,42 @@ Once you've determined what the matched rune(s) represent, you can emit a token
 	//
 	func (l *Lexer) EmitType(t token.Type)
 
+Tokens such as whitespace and comments can be emitted on a channel other than token.ChannelDefault, allowing them to
+flow through the token stream while remaining invisible to grammar code (see parser.WithChannels):
+
+	// EmitTokenOn emits a token of the specified type, along with all of the previously-matched runes, on the specified
+	// channel (see token.Channel).
+	//
+	func (l *Lexer) EmitTokenOn(t token.Type, ch token.Channel)
+
+Tokens can carry a cooked value, parsed from the token text by the lexer (i.e. the time.Time of a date literal),
+retrieved via token.Cooked:
+
+	// EmitCooked emits a token of the specified type, along with all of the previously-matched runes, attaching the
+	// cooked value v (see token.Cooked).
+	//
+	func (l *Lexer) EmitCooked(t token.Type, v interface{})
+
 NOTE: See the section of the document regarding "Token Types" for details on defining tokens for your lexer.
 
+Non-fatal issues, such as deprecated syntax, can be reported as warnings without emitting a token, allowing lexing to
+continue normally; Warnings are recorded by the diagnostics collector (see WithDiagnostics):
+
+	// EmitWarning reports a warning with the specified text, covering the previously-matched runes.
+	//
+	func (l *Lexer) EmitWarning(msg string)
+
+	// EmitWarningf reports a warning with the formatted text, covering the previously-matched runes.
+	//
+	func (l *Lexer) EmitWarningf(format string, args ...interface{})
+
+When output batching is enabled (see WithOutputBuffer), you can deliver the pending tokens without waiting for a full
+batch:
+
+	// Flush ends the current output batch, delivering all pending tokens before any further lexer functions are called.
+	//
+	func (l *Lexer) Flush()
+
 
 Discarding Matched Runes
 
@@ -124,6 +365,38 @@ Sometimes, you may match a series of runes that you simply wish to discard:
 	//
 	func (l *Lexer) Clear()
 
+If a scan overshoots (i.e. a trailing '.' after a number), you can return the extra runes to the peek buffer before
+emitting:
+
+	// Truncate un-matches the last n matched runes, returning them to the front of the peek buffer.
+	//
+	func (l *Lexer) Truncate(n int)
+
+To drop upcoming runes (i.e. shebang lines, BOMs, or delimiters) without affecting the current match or outstanding
+markers:
+
+	// Skip consumes and immediately discards the next n runes in the input.
+	//
+	func (l *Lexer) Skip(n int)
+
+	// Discard consumes and immediately discards runes from the input for as long as match returns true.
+	//
+	func (l *Lexer) Discard(match func(rune) bool) int
+
+For error recovery (i.e. skipping to the end of the line after a bad literal), SkipTo / SkipToAny match runes up to
+a resync point, adding them to the current match, so the whole region can be discarded via Clear, surfaced via
+EmitToken(TUnknown), or reported via EmitErrorToken:
+
+	// SkipTo matches runes up to, but not including, the first rune that satisfies pred, or to the end of the input,
+	// returning the number of runes matched.
+	//
+	func (l *Lexer) SkipTo(pred func(rune) bool) int
+
+	// SkipToAny matches runes up to, but not including, the first rune found in set, or to the end of the input,
+	// returning the number of runes matched.
+	//
+	func (l *Lexer) SkipToAny(set string) int
+
 
 Creating Save Points
 
@@ -155,6 +428,12 @@ you can use this pattern:
 
 	return marker.Apply(); // Resets the lexer and returns control to the saved Lexer.Fn
 
+To commit part of a match and retry the rest, you can instead discard just the runes matched since the marker:
+
+	// ClearTo discards the runes matched since the marker was created, keeping any runes matched before it.
+	//
+	func (m *Marker) ClearTo()
+
 
 Token Types
 
@@ -188,12 +467,52 @@ emitted from the lexer:
 		Next() (token.Token, error)
 	}
 
+When you just want all of the tokens, or the first error, LexAll drains the token.Nexter for you:
+
+	tokens, err := lexer.LexAll(input, lexStart)
+
+Errors emitted via EmitError are returned as an *Error, which preserves the raw message (Error.Message) along with
+its position (Error.Pos); Error() returns the formatted form, i.e. "1:5: unexpected character".
+
+EmitError discards the matched runes. To keep the offending text, use EmitErrorToken, which attaches the matched
+runes to the error (Error.Text), spanning them (Error.Pos / Error.End), so messages can quote exactly what was seen.
+
+
+Snapshots
+
+Long-running interactive sessions (REPLs, notebook kernels) can checkpoint the lexer via Snapshot, and resume it
+later, possibly in another process, via LexSnapshot (or RestoreSnapshot):
+
+	// Within a lexer function
+	//
+	data, err := l.Snapshot()
+
+	// Later, with the input not yet read by the lexer
+	//
+	nexter, err := lexer.LexSnapshot(data, remaining, lexStart)
+
+Snapshots capture the buffered input, the line/column position, and any tokens emitted but not yet delivered.
+Lexer functions, the user context and the options are not captured, and are supplied again when restoring.
+
 
 Tracking Lines and Columns
 
 Lexer tracks lines and columns as runes are consumed, and exposes them in the emitted Tokens.
 
-Lexer uses '\n' as the newline separator when tracking line counts.
+By default, the lexer uses '\n' as the newline separator when tracking line counts.
+See WithLineTerminators to also recognize '\r' (treating "\r\n" as a single line break) and the Unicode line
+terminators; Within your lexer functions, MatchNewline matches the next line terminator per the same policy.
+
+By default, columns count runes; See WithColumns to count bytes or display width instead.
+
+To convert byte offsets to/from line/column positions after lexing (i.e. for diagnostics renderers or LSP
+conversions), record a LineTable via WithLineTable:
+
+	lines := lexer.NewLineTable()
+	tokens := lexer.LexString(input, start, lexer.WithLineTable(lines))
+	...
+	pos := lines.Position(offset) // offset -> line/column
+	offset = lines.Offset(pos)    // line/column -> offset
 
 NOTE: Error messages with line/column information may reference the start of an attempted token match and not the
 position of the rune(s) that generated the error.
@@ -0,0 +1,43 @@
+package lexer
+
+import (
+	"fmt"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Error is the error returned by the token.Nexter for errors emitted via Lexer.EmitError.
+// The raw message is preserved, with the position of the error stored separately, allowing programmatic consumers
+// to match on the message content.
+// Error() returns the formatted form, i.e. "1:5: unexpected character".
+//
+type Error struct {
+	Message string         // The error message, as passed to EmitError
+	Pos     token.Position // Position of the error within the input
+	Text    string         // The offending text, if emitted via EmitErrorToken
+	End     token.Position // End of the offending text (see token.End), if emitted via EmitErrorToken
+
+	incomplete bool // Emitted at the end of the input in interactive mode - see WithInteractive()
+}
+
+// Incomplete implements token.Incompleter.Incomplete(), confirming if the error was emitted at the end of the input
+// in interactive mode (see WithInteractive).
+//
+func (e *Error) Incomplete() bool {
+	return e.incomplete
+}
+
+// Error implements error, returning the error formatted as "line:column: message".
+//
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// newError returns the Error for the TLexErr token tok.
+//
+func newError(tok token.Token) *Error {
+	if t, ok := tok.(*_token); ok && t.err != nil {
+		return t.err
+	}
+	return &Error{Message: tok.Value(), Pos: token.Start(tok)}
+}
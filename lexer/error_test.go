@@ -0,0 +1,68 @@
+package lexer
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestError
+//
+func TestError(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		l.Next()
+		l.Next()
+		l.EmitError("unexpected 'b'")
+		return nil
+	}
+	_, err := LexString("ab", fn).Next()
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("Nexter.Next() expecting *Error, received %T", err)
+	}
+	if e.Message != "unexpected 'b'" {
+		t.Errorf("Error.Message expecting 'unexpected 'b'', received '%s'", e.Message)
+	}
+	if e.Pos != (token.Position{Line: 1, Column: 3}) {
+		t.Errorf("Error.Pos expecting 1:3, received %v", e.Pos)
+	}
+	if s := e.Error(); s != "1:3: unexpected 'b'" {
+		t.Errorf("Error.Error() expecting '1:3: unexpected 'b'', received '%s'", s)
+	}
+}
+
+// TestErrorToken
+//
+func TestErrorToken(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		for l.CanPeek(1) && l.Peek(1) != ' ' {
+			l.Next()
+		}
+		l.EmitErrorToken("unexpected input")
+		return lexFields
+	}
+	nexter := LexString("a$%b c", func(l *Lexer) Fn {
+		l.Next()
+		l.EmitToken(TStart)
+		return fn
+	})
+	expectNexterNext(t, nexter, TStart, "a", 1, 1)
+	_, err := nexter.Next()
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("Nexter.Next() expecting *Error, received %T", err)
+	}
+	if e.Text != "$%b" {
+		t.Errorf("Error.Text expecting '$%%b', received '%s'", e.Text)
+	}
+	if e.Pos != (token.Position{Line: 1, Column: 2}) || e.End != (token.Position{Line: 1, Column: 5}) {
+		t.Errorf("Error span expecting 1:2-1:5, received %v-%v", e.Pos, e.End)
+	}
+	if s := e.Error(); s != "1:2: unexpected input" {
+		t.Errorf("Error.Error() expecting '1:2: unexpected input', received '%s'", s)
+	}
+	// Lexing continues after the bad region
+	//
+	expectNexterNext(t, nexter, TStart, "c", 1, 6)
+	expectNexterEOF(t, nexter)
+}
@@ -0,0 +1,256 @@
+/*
+Package escape decodes escape sequences within string literals, independently of how the literals are scanned.
+
+Schemes describe the escape sequences of a language; C and JSON are predefined, and custom schemes can be built from
+a table of simple escapes, along with the numeric forms supported:
+
+	value, err := escape.JSON.Decode(text[1 : len(text)-1]) // Strip the quotes
+	if e, ok := err.(*escape.Error); ok {
+		pos := e.Position(token.Start(tok)) // Adjust for the opening quote, i.e. pos.Column++
+		...
+	}
+
+Error positions are relative to the start of the decoded text, and can be converted to positions within the input via
+Error.Position, given the position of the start of the text.
+
+*/
+package escape
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Scheme describes the escape sequences of a language.
+//
+type Scheme struct {
+	Escape     rune            // Rune that starts an escape sequence, i.e. '\\'
+	Simple     map[rune]string // Simple escapes, keyed by the rune following the Escape rune, i.e. 'n' -> "\n"
+	Octal      bool            // Support \ooo (1-3 octal digits, max \377), decoded as a byte
+	Hex        bool            // Support \xhh (exactly 2 hex digits), decoded as a byte
+	Short      bool            // Support \uhhhh (exactly 4 hex digits), decoded as a rune
+	Long       bool            // Support \Uhhhhhhhh (exactly 8 hex digits), decoded as a rune
+	Surrogates bool            // Combine \uhhhh surrogate pairs (UTF-16) into a single rune, as in JSON
+}
+
+// C decodes C-style escapes: \a \b \f \n \r \t \v \\ \' \" \? along with octal, hex and unicode escapes.
+//
+var C = &Scheme{
+	Escape: '\\',
+	Simple: map[rune]string{
+		'a': "\a", 'b': "\b", 'f': "\f", 'n': "\n", 'r': "\r", 't': "\t", 'v': "\v",
+		'\\': "\\", '\'': "'", '"': "\"", '?': "?",
+	},
+	Octal: true,
+	Hex:   true,
+	Short: true,
+	Long:  true,
+}
+
+// JSON decodes JSON escapes: \" \\ \/ \b \f \n \r \t along with \uhhhh escapes, combining surrogate pairs.
+//
+var JSON = &Scheme{
+	Escape: '\\',
+	Simple: map[rune]string{
+		'"': "\"", '\\': "\\", '/': "/", 'b': "\b", 'f': "\f", 'n': "\n", 'r': "\r", 't': "\t",
+	},
+	Short:      true,
+	Surrogates: true,
+}
+
+// Error describes an invalid escape sequence.
+// Positions are relative to the start of the decoded text; See Position.
+//
+type Error struct {
+	Message string // The error message, i.e. "unknown escape sequence"
+	Seq     string // The invalid escape sequence, i.e. `\q`
+	Offset  int    // Byte offset of the escape sequence, 0-based
+	Line    int    // Line of the escape sequence, 1-based
+	Column  int    // Column of the escape sequence, 1-based, counting runes
+}
+
+// Error implements error, i.e. "1:5: unknown escape sequence `\q`".
+//
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d:%d: %s `%s`", e.Line, e.Column, e.Message, e.Seq)
+}
+
+// Position returns the position of the escape sequence within the input, given the position of the start of the
+// decoded text, i.e. the position of the containing token, adjusted for any opening quote.
+//
+func (e *Error) Position(start token.Position) token.Position {
+	if e.Line == 1 {
+		return token.Position{Line: start.Line, Column: start.Column + e.Column - 1}
+	}
+	return token.Position{Line: start.Line + e.Line - 1, Column: e.Column}
+}
+
+// Decode decodes the escape sequences in text, returning the decoded text.
+// Returns an *Error for the first invalid escape sequence.
+//
+func (s *Scheme) Decode(text string) (string, error) {
+	if !strings.ContainsRune(text, s.Escape) {
+		return text, nil
+	}
+	d := &decoder{scheme: s, text: text, line: 1, column: 1}
+	for d.i < len(d.text) {
+		r, size := utf8.DecodeRuneInString(d.text[d.i:])
+		if r != s.Escape {
+			d.b.WriteString(d.text[d.i : d.i+size])
+			d.advance(size)
+			continue
+		}
+		if err := d.escape(); err != nil {
+			return "", err
+		}
+	}
+	return d.b.String(), nil
+}
+
+// decoder holds the state of a single Decode call.
+//
+type decoder struct {
+	scheme *Scheme
+	text   string
+	i      int // Byte offset of the next rune
+	line   int // Line of the next rune
+	column int // Column of the next rune
+	b      strings.Builder
+}
+
+// advance moves past the next size bytes, tracking lines and columns.
+// Invalid runes are not counted, matching the lexer.
+//
+func (d *decoder) advance(size int) {
+	for end := d.i + size; d.i < end; {
+		r, n := utf8.DecodeRuneInString(d.text[d.i:])
+		d.i += n
+		switch {
+		case r == '\n':
+			d.line++
+			d.column = 1
+		case r != utf8.RuneError || n > 1:
+			d.column++
+		}
+	}
+}
+
+// escape decodes the escape sequence at the current offset, returning an *Error if invalid.
+//
+func (d *decoder) escape() error {
+	s := d.scheme
+	start := len(string(s.Escape))
+	if d.i+start >= len(d.text) {
+		return d.fail("incomplete escape sequence", len(d.text)-d.i)
+	}
+	r, size := utf8.DecodeRuneInString(d.text[d.i+start:])
+	if v, ok := s.Simple[r]; ok {
+		d.b.WriteString(v)
+		d.advance(start + size)
+		return nil
+	}
+	switch {
+	case s.Octal && r >= '0' && r <= '7':
+		n := 1
+		for n < 3 && d.i+start+n < len(d.text) && isOctal(d.text[d.i+start+n]) {
+			n++
+		}
+		v := 0
+		for _, c := range d.text[d.i+start : d.i+start+n] {
+			v = v*8 + int(c-'0')
+		}
+		if v > 0xff {
+			return d.fail("octal escape value out of range", start+n)
+		}
+		d.b.WriteByte(byte(v))
+		d.advance(start + n)
+		return nil
+	case s.Hex && r == 'x':
+		v, ok := d.hex(start+1, 2)
+		if !ok {
+			return d.fail("invalid hex escape", start+1+2)
+		}
+		d.b.WriteByte(byte(v))
+		d.advance(start + 1 + 2)
+		return nil
+	case s.Short && r == 'u':
+		return d.unicode(start, 4)
+	case s.Long && r == 'U':
+		return d.unicode(start, 8)
+	}
+	return d.fail("unknown escape sequence", start+size)
+}
+
+// unicode decodes a \u or \U escape, with n hex digits, starting at the current offset.
+//
+func (d *decoder) unicode(start int, n int) error {
+	seq := start + 1 + n
+	v, ok := d.hex(start+1, n)
+	if !ok {
+		return d.fail("invalid unicode escape", seq)
+	}
+	r := rune(v)
+	if d.scheme.Surrogates && utf16.IsSurrogate(r) {
+		// Expect the low half of the pair
+		//
+		low, ok := rune(0), false
+		if strings.HasPrefix(d.text[d.i+seq:], d.text[d.i:d.i+start+1]) {
+			var v uint32
+			v, ok = d.hex(seq+start+1, n)
+			low = rune(v)
+		}
+		if r = utf16.DecodeRune(r, low); !ok || r == utf8.RuneError {
+			return d.fail("invalid surrogate pair", seq)
+		}
+		seq *= 2
+	}
+	if !utf8.ValidRune(r) {
+		return d.fail("unicode escape value out of range", seq)
+	}
+	d.b.WriteRune(r)
+	d.advance(seq)
+	return nil
+}
+
+// hex parses n hex digits at offset from the current offset, returning the value, along with true if valid.
+//
+func (d *decoder) hex(offset int, n int) (uint32, bool) {
+	i := d.i + offset
+	if i+n > len(d.text) {
+		return 0, false
+	}
+	v := uint32(0)
+	for _, c := range []byte(d.text[i : i+n]) {
+		switch {
+		case c >= '0' && c <= '9':
+			v = v*16 + uint32(c-'0')
+		case c >= 'a' && c <= 'f':
+			v = v*16 + uint32(c-'a'+10)
+		case c >= 'A' && c <= 'F':
+			v = v*16 + uint32(c-'A'+10)
+		default:
+			return 0, false
+		}
+	}
+	return v, true
+}
+
+// fail returns an *Error for the escape sequence of (up to) size bytes at the current offset.
+//
+func (d *decoder) fail(msg string, size int) *Error {
+	end := d.i + size
+	if end > len(d.text) {
+		end = len(d.text)
+	}
+	return &Error{Message: msg, Seq: d.text[d.i:end], Offset: d.i, Line: d.line, Column: d.column}
+}
+
+// isOctal
+//
+func isOctal(c byte) bool {
+	return c >= '0' && c <= '7'
+}
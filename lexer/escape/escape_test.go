@@ -0,0 +1,84 @@
+package escape
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestDecode
+//
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		scheme   *Scheme
+		text     string
+		expected string
+	}{
+		{C, `plain`, "plain"},
+		{C, `a\tb\n`, "a\tb\n"},
+		{C, `\'\"\?\\`, `'"?\`},
+		{C, `\0\101\1010`, "\x00A\x410"},
+		{C, `\x41\xff`, "A\xff"},
+		{C, `\u00e9\U0001F600`, "\u00e9\U0001F600"},
+		{JSON, `\"\/\b`, "\"/\b"},
+		{JSON, `\u00e9`, "\u00e9"},
+		{JSON, `\ud83d\ude00!`, "\U0001F600!"},
+		{&Scheme{Escape: '%', Simple: map[rune]string{'%': "%", 's': " "}}, `a%sb%%`, "a b%"},
+	}
+	for _, test := range tests {
+		s, err := test.scheme.Decode(test.text)
+		if err != nil {
+			t.Errorf("Decode(`%s`) returned error: %v", test.text, err)
+		} else if s != test.expected {
+			t.Errorf("Decode(`%s`) expecting %q, received %q", test.text, test.expected, s)
+		}
+	}
+}
+
+// TestDecodeError
+//
+func TestDecodeError(t *testing.T) {
+	tests := []struct {
+		scheme *Scheme
+		text   string
+		err    string
+	}{
+		{C, `ab\q`, "1:3: unknown escape sequence `\\q`"},
+		{C, `\400`, "1:1: octal escape value out of range `\\400`"},
+		{C, `x\xg1`, "1:2: invalid hex escape `\\xg1`"},
+		{C, "\u00e9\\xg1", "1:2: invalid hex escape `\\xg1`"},
+		{C, `\u12`, "1:1: invalid unicode escape `\\u12`"},
+		{C, `\ud800`, "1:1: unicode escape value out of range `\\ud800`"},
+		{C, `\U00110000`, "1:1: unicode escape value out of range `\\U00110000`"},
+		{C, "a\nb\\", "2:2: incomplete escape sequence `\\`"},
+		{JSON, `\x41`, "1:1: unknown escape sequence `\\x`"},
+		{JSON, `\ud83d!`, "1:1: invalid surrogate pair `\\ud83d`"},
+		{JSON, `\ud83dA`, "1:1: invalid surrogate pair `\\ud83d`"},
+	}
+	for _, test := range tests {
+		_, err := test.scheme.Decode(test.text)
+		if err == nil {
+			t.Errorf("Decode(`%s`) expecting error", test.text)
+		} else if err.Error() != test.err {
+			t.Errorf("Decode(`%s`) expecting '%s', received '%s'", test.text, test.err, err.Error())
+		}
+	}
+}
+
+// TestErrorPosition
+//
+func TestErrorPosition(t *testing.T) {
+	_, err := C.Decode(`ab\q`)
+	e := err.(*Error)
+	if pos := e.Position(token.Position{Line: 3, Column: 10}); pos != (token.Position{Line: 3, Column: 12}) {
+		t.Errorf("Error.Position() expecting 3:12, received %v", pos)
+	}
+	_, err = C.Decode("a\nbc\\q")
+	e = err.(*Error)
+	if e.Offset != 4 {
+		t.Errorf("Error.Offset expecting 4, received %d", e.Offset)
+	}
+	if pos := e.Position(token.Position{Line: 3, Column: 10}); pos != (token.Position{Line: 4, Column: 3}) {
+		t.Errorf("Error.Position() expecting 4:3, received %v", pos)
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+//
+//	Input is read from STDIN, and streamed through the lexer, so large files are never held in memory
+//
+//	Each record is printed as soon as it is complete:
+//
+//	$ printf 'name,quote\r\nbob,"say ""hi"",\nthen leave"\r\n' | csv
+//	1: ["name" "quote"]
+//	2: ["bob" "say \"hi\",\nthen leave"]
+//
+//	Flags select the dialect:
+//
+//	-tsv     : Tab-separated values (no quoting)
+//	-d <rune>: Field delimiter (default ',')
+//	-c <rune>: Comment rune; Lines starting with it are skipped
+//	-lazy    : Allow quotes within unquoted fields
+//
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// We define our lexer tokens starting from the pre-defined START token
+//
+const (
+	TField     token.Type = lexer.TStart + iota // Cooked value is the unquoted field
+	TDelimiter                                  // Field delimiter
+	TEndRecord                                  // Record terminator (newline)
+)
+
+// Dialect describes a CSV-like format.
+//
+type Dialect struct {
+	Delimiter  rune // Field delimiter, i.e. ',' or '\t'
+	Quote      rune // Quote rune, 0 if fields cannot be quoted
+	Comment    rune // Lines starting with this rune are skipped, 0 if comments are not supported
+	LazyQuotes bool // Allow quotes within unquoted fields
+}
+
+// CSV is the RFC 4180 dialect.
+//
+var CSV = &Dialect{Delimiter: ',', Quote: '"'}
+
+// TSV is the IANA tab-separated values dialect, which does not support quoting.
+//
+var TSV = &Dialect{Delimiter: '\t'}
+
+// main
+//
+func main() {
+	tsv := flag.Bool("tsv", false, "tab-separated values")
+	delimiter := flag.String("d", ",", "field delimiter")
+	comment := flag.String("c", "", "comment rune")
+	lazy := flag.Bool("lazy", false, "allow quotes within unquoted fields")
+	flag.Parse()
+
+	d := *CSV
+	if *tsv {
+		d = *TSV
+	} else {
+		d.Delimiter, _ = utf8.DecodeRuneInString(*delimiter)
+	}
+	d.Comment, _ = utf8.DecodeRuneInString(*comment)
+	if d.Comment == utf8.RuneError {
+		d.Comment = 0
+	}
+	d.LazyQuotes = *lazy
+
+	n := 0
+	err := d.Stream(bufio.NewReader(os.Stdin), func(record []string) error {
+		n++
+		fmt.Printf("%d: %q\n", n, record)
+		return nil
+	})
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
+}
+
+// Stream lexes the input, calling fn with each record as soon as it is complete.
+// Blank lines, and comment lines, are skipped.
+// Returns the first lexer error, or the first error returned by fn, if any.
+//
+func (d *Dialect) Stream(input io.Reader, fn func(record []string) error) error {
+	tokens := lexer.LexReader(input, d.lexLine, lexer.WithLineTerminators(lexer.LineCRLF))
+	var record []string
+	for {
+		tok, err := tokens.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch tok.Type() {
+		case TField:
+			record = append(record, token.Cooked(tok).(string))
+		case TEndRecord:
+			if err = fn(record); err != nil {
+				return err
+			}
+			record = nil
+		}
+	}
+	// Last record may not have a newline
+	//
+	if record != nil {
+		return fn(record)
+	}
+	return nil
+}
+
+// ReadAll lexes the input, returning all of the records.
+//
+func (d *Dialect) ReadAll(input string) ([][]string, error) {
+	var records [][]string
+	err := d.Stream(strings.NewReader(input), func(record []string) error {
+		records = append(records, record)
+		return nil
+	})
+	return records, err
+}
+
+// lexLine lexes the start of a record, skipping blank lines and comment lines.
+//
+func (d *Dialect) lexLine(l *lexer.Lexer) lexer.Fn {
+	switch {
+	case l.MatchNewline():
+		l.Clear()
+		return d.lexLine
+	case d.Comment != 0 && l.Peek(1) == d.Comment:
+		for l.CanPeek(1) && !l.MatchNewline() {
+			l.Next()
+		}
+		l.Clear()
+		return d.lexLine
+	}
+	return d.lexField
+}
+
+// lexField lexes a field, along with the delimiter or newline that follows it.
+//
+func (d *Dialect) lexField(l *lexer.Lexer) lexer.Fn {
+	if d.Quote != 0 && l.Peek(1) == d.Quote {
+		return d.lexQuoted
+	}
+	text := &strings.Builder{}
+	for l.CanPeek(1) {
+		r := l.Peek(1)
+		if r == d.Delimiter || isNewline(r) {
+			break
+		}
+		if r == d.Quote && d.Quote != 0 && !d.LazyQuotes {
+			l.Clear() // Position the error at the quote
+			l.Next()
+			l.EmitErrorToken("bare quote in unquoted field")
+			return nil
+		}
+		text.WriteRune(l.Next())
+	}
+	l.EmitCooked(TField, text.String())
+	return d.lexEnd(l)
+}
+
+// lexQuoted lexes a quoted field, with doubled quotes as escaped quotes.
+// Newlines within the field are normalized to "\n".
+//
+func (d *Dialect) lexQuoted(l *lexer.Lexer) lexer.Fn {
+	text := &strings.Builder{}
+	m := l.Marker()
+	l.Next() // Opening quote
+	for {
+		switch {
+		case !l.CanPeek(1):
+			// Position the error at the opening quote
+			//
+			m.Apply()
+			l.Next()
+			l.EmitErrorToken("unterminated quoted field")
+			return nil
+		case l.MatchNewline():
+			text.WriteRune('\n')
+		case l.Peek(1) != d.Quote:
+			text.WriteRune(l.Next())
+		case l.CanPeek(2) && l.Peek(2) == d.Quote:
+			l.Next()
+			text.WriteRune(l.Next())
+		default:
+			l.Next() // Closing quote
+			l.EmitCooked(TField, text.String())
+			return d.lexEnd(l)
+		}
+	}
+}
+
+// lexEnd lexes the delimiter or newline following a field, returning the next function.
+//
+func (d *Dialect) lexEnd(l *lexer.Lexer) lexer.Fn {
+	switch {
+	case !l.CanPeek(1):
+		return nil
+	case l.MatchNewline():
+		l.EmitToken(TEndRecord)
+		return d.lexLine
+	case l.Peek(1) == d.Delimiter:
+		l.Next()
+		l.EmitToken(TDelimiter)
+		// A trailing delimiter is followed by an empty field
+		//
+		if !l.CanPeek(1) || isNewline(l.Peek(1)) {
+			l.EmitCooked(TField, "")
+			return d.lexEnd(l)
+		}
+		return d.lexField
+	}
+	l.Next()
+	l.EmitErrorToken("unexpected text after quoted field")
+	return nil
+}
+
+// isNewline
+//
+func isNewline(r rune) bool {
+	return r == '\n' || r == '\r'
+}
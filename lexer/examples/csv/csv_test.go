@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// TestReadAll
+//
+func TestReadAll(t *testing.T) {
+	tests := []struct {
+		dialect  *Dialect
+		input    string
+		expected [][]string
+	}{
+		{CSV, "", nil},
+		{CSV, "a,b,c", [][]string{{"a", "b", "c"}}},
+		{CSV, "a,b\r\nc,d\r\n", [][]string{{"a", "b"}, {"c", "d"}}},
+		{CSV, "a,b\rc,d\n", [][]string{{"a", "b"}, {"c", "d"}}},
+		{CSV, ",a,,\n,", [][]string{{"", "a", "", ""}, {"", ""}}},
+		{CSV, "a\n\n\r\nb\n", [][]string{{"a"}, {"b"}}},
+		{CSV, `"a","b,c",""`, [][]string{{"a", "b,c", ""}}},
+		{CSV, `"say ""hi""",""""`, [][]string{{`say "hi"`, `"`}}},
+		{CSV, "\"line 1\r\nline 2\",x\n", [][]string{{"line 1\nline 2", "x"}}},
+		{CSV, " a , b ", [][]string{{" a ", " b "}}},
+		{TSV, "a\tb \"c\"\t\n", [][]string{{"a", `b "c"`, ""}}},
+		{&Dialect{Delimiter: ';', Quote: '\'', Comment: '#'}, "#h\na;'b;c'\n# x\n", [][]string{{"a", "b;c"}}},
+		{&Dialect{Delimiter: ',', Quote: '"', LazyQuotes: true}, `a"b,c`, [][]string{{`a"b`, "c"}}},
+	}
+	for _, test := range tests {
+		records, err := test.dialect.ReadAll(test.input)
+		if err != nil {
+			t.Errorf("ReadAll(%q) unexpected error: %v", test.input, err)
+		} else if !reflect.DeepEqual(records, test.expected) {
+			t.Errorf("ReadAll(%q) expecting %q, received %q", test.input, test.expected, records)
+		}
+	}
+}
+
+// TestReadAllError
+//
+func TestReadAllError(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a,b\nc,d\"e\n", "2:4: bare quote in unquoted field"},
+		{"a,\"b\"c\n", "1:6: unexpected text after quoted field"},
+		{"a\n\"b\nc", "2:1: unterminated quoted field"},
+	}
+	for _, test := range tests {
+		_, err := CSV.ReadAll(test.input)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("ReadAll(%q) expecting error '%s', received '%v'", test.input, test.expected, err)
+		}
+	}
+}
+
+// TestStream confirms records are delivered as soon as they are complete, before the rest of the input is
+// available.
+//
+func TestStream(t *testing.T) {
+	r, w := io.Pipe()
+	records := make(chan []string)
+	done := make(chan error)
+	go func() {
+		done <- CSV.Stream(r, func(record []string) error {
+			records <- record
+			return nil
+		})
+	}()
+	chunks := []string{"a,\"b\n", "c\"\r\n", "d,e\n"}
+	expected := [][]string{{"a", "b\nc"}, {"d", "e"}}
+	for i, chunk := range chunks {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Write() unexpected error: %v", err)
+		}
+		if i == 0 {
+			continue // Record not yet complete
+		}
+		if record := <-records; !reflect.DeepEqual(record, expected[i-1]) {
+			t.Errorf("Stream() expecting %q, received %q", expected[i-1], record)
+		}
+	}
+	_ = w.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Stream() unexpected error: %v", err)
+	}
+}
+
+// TestStreamStop
+//
+func TestStreamStop(t *testing.T) {
+	stop := errors.New("stop")
+	count := 0
+	err := CSV.Stream(&infinite{line: "a,b\n"}, func(record []string) error {
+		if count++; count == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || count != 3 {
+		t.Errorf("Stream() expecting stop after 3 records, received %v after %d", err, count)
+	}
+}
+
+// infinite is an io.Reader that repeats line forever.
+//
+type infinite struct {
+	line string
+	i    int
+}
+
+// Read implements io.Reader.Read().
+//
+func (r *infinite) Read(p []byte) (int, error) {
+	for n := range p {
+		p[n] = r.line[r.i%len(r.line)]
+		r.i++
+	}
+	return len(p), nil
+}
@@ -0,0 +1,428 @@
+package main
+
+//
+//	Input is read from STDIN
+//
+//	The input is lexed as Markdown inline text, and rendered as HTML:
+//
+//	$ echo 'Some *emphasis*, **strong `code`**, a [*link*](http://x.io) and *not emphasis' | markdown
+//	<p>Some <em>emphasis</em>, <strong>strong <code>code</code></strong>, a <a href="http://x.io"><em>link</em></a> and *not emphasis</p>
+//
+//	The following constructs are supported:
+//
+//	`code`  ``co`de``  : Code spans, delimited by backtick runs of the same length
+//	*em*    _em_       : Emphasis
+//	**str** __str__    : Strong emphasis
+//	[text](url)        : Links; Links cannot be nested
+//	\*                 : Backslash escapes of ASCII punctuation
+//
+//	Opening delimiters must be followed by a non-space, and closing delimiters must follow a non-space.
+//	Constructs can be nested, i.e. *a **b** `c*`*, but must close within their parent.
+//
+//	Constructs that fail to close (i.e. "*not emphasis") are plain text.
+//	Before emitting an opening token, the lexer matches ahead to confirm the construct closes, then rewinds to the
+//	opening delimiter via a Marker; The match ahead skips nested constructs whole, confirming them via markers of their
+//	own.
+//	As no tokens are emitted while matching ahead, all of the markers remain valid.
+//
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// We define our lexer tokens starting from the pre-defined START token
+//
+const (
+	TText        token.Type = lexer.TStart + iota // Cooked value is the text, unescaped
+	TCode                                         // Cooked value is the code, without its delimiters
+	TEmphOpen                                     // '*' or '_'
+	TEmphClose                                    // '*' or '_'
+	TStrongOpen                                   // "**" or "__"
+	TStrongClose                                  // "**" or "__"
+	TLinkOpen                                     // '[', cooked value is the URL
+	TLinkClose                                    // "](url)"
+)
+
+// main
+//
+func main() {
+	input, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		panic(err)
+	}
+	out, err := Render(strings.TrimRight(string(input), "\n"))
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("<p>%s</p>\n", out)
+}
+
+// Lex lexes the inline text.
+//
+func Lex(input string) ([]token.Token, error) {
+	s := &scanner{prev: ' ', failed: map[opener]bool{}}
+	return lexer.LexAll(input, s.lexInline)
+}
+
+// Render renders the inline text as HTML.
+//
+func Render(input string) (string, error) {
+	tokens, err := Lex(input)
+	if err != nil {
+		return "", err
+	}
+	b := &strings.Builder{}
+	for _, tok := range tokens {
+		switch tok.Type() {
+		case TText:
+			b.WriteString(html.EscapeString(token.Cooked(tok).(string)))
+		case TCode:
+			b.WriteString("<code>" + html.EscapeString(token.Cooked(tok).(string)) + "</code>")
+		case TEmphOpen:
+			b.WriteString("<em>")
+		case TEmphClose:
+			b.WriteString("</em>")
+		case TStrongOpen:
+			b.WriteString("<strong>")
+		case TStrongClose:
+			b.WriteString("</strong>")
+		case TLinkOpen:
+			b.WriteString(`<a href="` + html.EscapeString(token.Cooked(tok).(string)) + `">`)
+		case TLinkClose:
+			b.WriteString("</a>")
+		}
+	}
+	return b.String(), nil
+}
+
+// delim is the opening delimiter of a construct.
+//
+type delim struct {
+	r rune // '*', '_' or '['
+	n int  // Length of the run
+}
+
+// scanner holds the state of the lexer.
+//
+type scanner struct {
+	open   []delim         // Open constructs, innermost last
+	prev   rune            // Last rune matched
+	offset int             // Offset of the next rune, in runes
+	url    string          // URL of the last link matched
+	failed map[opener]bool // Constructs known not to close
+}
+
+// opener identifies an attempt at matching a construct, from its opening delimiter.
+// The outcome of an attempt does not depend on the constructs containing it, so failures are remembered, avoiding
+// exponential re-matching for inputs full of unclosed delimiters, i.e. "*a *a *a ...".
+//
+type opener struct {
+	offset int  // Offset of the opening delimiter
+	r      rune // '*', '_' or '['
+	inLink bool // Within link text?
+}
+
+// mark is a lexer marker, along with the matching state of the scanner.
+//
+type mark struct {
+	m      *lexer.Marker
+	prev   rune
+	offset int
+}
+
+// mark marks the current position.
+//
+func (s *scanner) mark(l *lexer.Lexer) mark {
+	return mark{m: l.Marker(), prev: s.prev, offset: s.offset}
+}
+
+// apply rewinds to the marked position.
+//
+func (s *scanner) apply(m mark) {
+	m.m.Apply()
+	s.prev = m.prev
+	s.offset = m.offset
+}
+
+// remember returns a matcher that records the failures of match, skipping attempts known to fail.
+//
+func (s *scanner) remember(r rune, inLink bool, match func(*lexer.Lexer) bool) func(*lexer.Lexer) bool {
+	return func(l *lexer.Lexer) bool {
+		key := opener{offset: s.offset, r: r, inLink: inLink}
+		if s.failed[key] {
+			return false
+		}
+		ok := match(l)
+		if !ok {
+			s.failed[key] = true
+		}
+		return ok
+	}
+}
+
+// lookahead confirms if match succeeds at the current position, rewinding either way.
+//
+func (s *scanner) lookahead(l *lexer.Lexer, match func(*lexer.Lexer) bool) bool {
+	m := s.mark(l)
+	ok := match(l)
+	s.apply(m)
+	return ok
+}
+
+// attempt tries match at the current position, rewinding only if it fails.
+//
+func (s *scanner) attempt(l *lexer.Lexer, match func(*lexer.Lexer) bool) bool {
+	m := s.mark(l)
+	ok := match(l)
+	if !ok {
+		s.apply(m)
+	}
+	return ok
+}
+
+// next matches the next rune.
+//
+func (s *scanner) next(l *lexer.Lexer) {
+	s.prev = l.Next()
+	s.offset++
+}
+
+// run matches a run of r, returning its length.
+//
+func (s *scanner) run(l *lexer.Lexer, r rune) int {
+	n := 0
+	for l.CanPeek(1) && l.Peek(1) == r {
+		s.next(l)
+		n++
+	}
+	return n
+}
+
+// inLink confirms if a link is open.
+//
+func (s *scanner) inLink() bool {
+	for _, d := range s.open {
+		if d.r == '[' {
+			return true
+		}
+	}
+	return false
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Lexer
+// ---------------------------------------------------------------------------------------------------------------------
+
+// lexInline
+//
+func (s *scanner) lexInline(l *lexer.Lexer) lexer.Fn {
+	inLink := s.inLink()
+	switch r := l.Peek(1); {
+	case s.close(l):
+	case r == '\\' && l.CanPeek(2) && isEscapable(l.Peek(2)):
+		s.next(l)
+		s.next(l)
+	case r == '`' && s.lookahead(l, s.code):
+		s.emitText(l)
+		s.code(l)
+		code := l.PeekToken()
+		n := len(code) - len(strings.TrimLeft(code, "`"))
+		code = code[n : len(code)-n]
+		// A single space is stripped from each end, allowing code to start or end with a backtick
+		//
+		if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+			code = code[1 : len(code)-1]
+		}
+		l.EmitCooked(TCode, code)
+	case (r == '*' || r == '_') && s.lookahead(l, s.emphasis(r, inLink)):
+		s.emitText(l)
+		d := delim{r: r, n: s.run(l, r)}
+		l.EmitToken(openTypes[d.n])
+		s.open = append(s.open, d)
+	case r == '[' && !inLink && s.lookahead(l, s.link()):
+		s.emitText(l)
+		s.next(l)
+		l.EmitCooked(TLinkOpen, s.url)
+		s.open = append(s.open, delim{r: '[', n: 1})
+	case r == '*' || r == '_' || r == '`':
+		s.run(l, r) // Unmatched delimiters are text
+	default:
+		s.next(l)
+	}
+	if !l.CanPeek(1) {
+		s.emitText(l)
+	}
+	return s.lexInline
+}
+
+// Emphasis token types, by delimiter length
+//
+var (
+	openTypes  = [...]token.Type{1: TEmphOpen, 2: TStrongOpen}
+	closeTypes = [...]token.Type{1: TEmphClose, 2: TStrongClose}
+)
+
+// close emits the closing token of the innermost open construct, if it closes at the current position.
+//
+func (s *scanner) close(l *lexer.Lexer) bool {
+	if len(s.open) == 0 {
+		return false
+	}
+	d := s.open[len(s.open)-1]
+	if d.r == '[' {
+		if !s.lookahead(l, s.linkEnd) {
+			return false
+		}
+		s.emitText(l)
+		s.linkEnd(l)
+		l.EmitToken(TLinkClose)
+	} else {
+		if !s.lookahead(l, s.closer(d)) {
+			return false
+		}
+		s.emitText(l)
+		s.run(l, d.r)
+		l.EmitToken(closeTypes[d.n])
+	}
+	s.open = s.open[:len(s.open)-1]
+	return true
+}
+
+// emitText emits the matched text, if any.
+//
+func (s *scanner) emitText(l *lexer.Lexer) {
+	if text := l.PeekToken(); text != "" {
+		l.EmitCooked(TText, unescape(text))
+	}
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Matchers
+// Each matcher matches a construct in full, returning true if matched; On failure, some runes may remain matched, so
+// matchers are called via lookahead or attempt, which rewind them.
+// ---------------------------------------------------------------------------------------------------------------------
+
+// code matches a code span, i.e. "`x`"; Spans delimited by longer backtick runs can contain shorter runs.
+//
+func (s *scanner) code(l *lexer.Lexer) bool {
+	n := s.run(l, '`')
+	for l.CanPeek(1) {
+		if l.Peek(1) != '`' {
+			s.next(l)
+		} else if s.run(l, '`') == n {
+			return true
+		}
+	}
+	return false
+}
+
+// emphasis returns a matcher for emphasis, i.e. "*x*" or "__x__", including its nested constructs.
+// Within a link, emphasis must close before the link text ends.
+//
+func (s *scanner) emphasis(r rune, inLink bool) func(*lexer.Lexer) bool {
+	return s.remember(r, inLink, func(l *lexer.Lexer) bool {
+		n := s.run(l, r)
+		if n > 2 || !l.CanPeek(1) || unicode.IsSpace(l.Peek(1)) {
+			return false
+		}
+		return s.content(l, s.closer(delim{r: r, n: n}), inLink)
+	})
+}
+
+// closer returns a matcher for the closing delimiter of d; The delimiter must follow a non-space.
+//
+func (s *scanner) closer(d delim) func(*lexer.Lexer) bool {
+	return func(l *lexer.Lexer) bool {
+		return !unicode.IsSpace(s.prev) && s.run(l, d.r) == d.n
+	}
+}
+
+// link returns a matcher for a link, i.e. "[text](url)", including the constructs nested in its text, saving its URL.
+//
+func (s *scanner) link() func(*lexer.Lexer) bool {
+	return s.remember('[', false, func(l *lexer.Lexer) bool {
+		s.next(l) // '['
+		return s.content(l, s.linkEnd, true)
+	})
+}
+
+// linkEnd matches the end of a link, i.e. "](url)", saving its URL.
+// URLs cannot contain spaces.
+//
+func (s *scanner) linkEnd(l *lexer.Lexer) bool {
+	if !l.CanPeek(2) || l.Peek(1) != ']' || l.Peek(2) != '(' {
+		return false
+	}
+	s.next(l)
+	s.next(l)
+	url := &strings.Builder{}
+	for l.CanPeek(1) && l.Peek(1) != ')' && !unicode.IsSpace(l.Peek(1)) {
+		url.WriteRune(l.Peek(1))
+		s.next(l)
+	}
+	if !l.CanPeek(1) || l.Peek(1) != ')' {
+		return false
+	}
+	s.next(l)
+	s.url = url.String()
+	return true
+}
+
+// content matches inline content, up to and including the closing delimiter matched by end.
+// Nested constructs are skipped whole, so delimiters within them are ignored.
+// When inLink is true, the content must end before the link text ends.
+//
+func (s *scanner) content(l *lexer.Lexer, end func(*lexer.Lexer) bool, inLink bool) bool {
+	for l.CanPeek(1) {
+		if s.attempt(l, end) {
+			return true
+		}
+		switch r := l.Peek(1); {
+		case r == ']' && inLink:
+			return false
+		case r == '\\' && l.CanPeek(2) && isEscapable(l.Peek(2)):
+			s.next(l)
+			s.next(l)
+		case r == '`' && s.attempt(l, s.code):
+		case (r == '*' || r == '_') && s.attempt(l, s.emphasis(r, inLink)):
+		case r == '[' && !inLink && s.attempt(l, s.link()):
+		case r == '*' || r == '_' || r == '`':
+			s.run(l, r)
+		default:
+			s.next(l)
+		}
+	}
+	return false
+}
+
+// isEscapable confirms if r can be backslash-escaped, i.e. is ASCII punctuation.
+//
+func isEscapable(r rune) bool {
+	return r < unicode.MaxASCII && unicode.IsPunct(r) || strings.ContainsRune("$+<=>^`|~", r)
+}
+
+// unescape removes the backslashes from escaped punctuation.
+//
+func unescape(text string) string {
+	if !strings.ContainsRune(text, '\\') {
+		return text
+	}
+	b := &strings.Builder{}
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '\\' && i+1 < len(runes) && isEscapable(runes[i+1]) {
+			i++
+		}
+		b.WriteRune(runes[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestRender
+//
+func TestRender(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{``, ``},
+		{`plain <text> & more`, `plain &lt;text&gt; &amp; more`},
+		{`*em* _em_ **strong** __strong__`, `<em>em</em> <em>em</em> <strong>strong</strong> <strong>strong</strong>`},
+		{"`code` ``a`b`` `` `x` ``", "<code>code</code> <code>a`b</code> <code>`x`</code>"},
+		{"`*not em*` *`code*`*", "<code>*not em*</code> <em><code>code*</code></em>"},
+		{`[a *link*](http://x.io?a=1&b=2)`, `<a href="http://x.io?a=1&amp;b=2">a <em>link</em></a>`},
+		{`*a **b** c*`, `<em>a <strong>b</strong> c</em>`},
+		{`*a *b* c*`, `<em>a <em>b</em> c</em>`},
+		{`**a *b* c**`, `<strong>a <em>b</em> c</strong>`},
+		{`\*not em\* \[not](link) \a`, `*not em* [not](link) \a`},
+	}
+	for _, test := range tests {
+		out, err := Render(test.input)
+		if err != nil {
+			t.Errorf("Render(%q) unexpected error: %v", test.input, err)
+		} else if out != test.expected {
+			t.Errorf("Render(%q) expecting %q, received %q", test.input, test.expected, out)
+		}
+	}
+}
+
+// TestUnclosed confirms constructs that fail to close are plain text.
+//
+func TestUnclosed(t *testing.T) {
+	tests := []string{
+		`*not emphasis`,
+		`**not strong*`,
+		`* not emphasis*`,
+		`*not emphasis *`,
+		"`not code",
+		"``not code`",
+		`[not a link]`,
+		`[not a link](no spaces)`,
+		`[not a link](x`,
+		`***not supported***`,
+		`a * b _ c`,
+	}
+	for _, input := range tests {
+		tokens, err := Lex(input)
+		if err != nil {
+			t.Errorf("Lex(%q) unexpected error: %v", input, err)
+			continue
+		}
+		if len(tokens) != 1 || tokens[0].Type() != TText || token.Cooked(tokens[0]) != input {
+			t.Errorf("Lex(%q) expecting a single text token, received %v", input, tokens)
+		}
+	}
+}
+
+// TestNesting confirms constructs close within their parent.
+//
+func TestNesting(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`[*a](u)*`, `<a href="u">*a</a>*`},
+		{`*[x*](u)`, `*<a href="u">x*</a>`},
+		{`*a [b](c*)`, `*a <a href="c*">b</a>`},
+		{"*a `b* c`", "*a <code>b* c</code>"},
+		{`[a [b](c)](d)`, `<a href="c">a [b</a>](d)`},
+	}
+	for _, test := range tests {
+		out, err := Render(test.input)
+		if err != nil {
+			t.Errorf("Render(%q) unexpected error: %v", test.input, err)
+		} else if out != test.expected {
+			t.Errorf("Render(%q) expecting %q, received %q", test.input, test.expected, out)
+		}
+	}
+}
+
+// TestLex
+//
+func TestLex(t *testing.T) {
+	tokens, err := Lex("a *b*\n`c` [d](e)")
+	if err != nil {
+		t.Fatalf("Lex() unexpected error: %v", err)
+	}
+	expected := []struct {
+		typ   token.Type
+		value string
+		pos   string
+	}{
+		{TText, "a ", "1:1"},
+		{TEmphOpen, "*", "1:3"},
+		{TText, "b", "1:4"},
+		{TEmphClose, "*", "1:5"},
+		{TText, "\n", "1:6"},
+		{TCode, "`c`", "2:1"},
+		{TText, " ", "2:4"},
+		{TLinkOpen, "[", "2:5"},
+		{TText, "d", "2:6"},
+		{TLinkClose, "](e)", "2:7"},
+	}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Lex() expecting %d tokens, received %d: %v", len(expected), len(tokens), tokens)
+	}
+	for i, e := range expected {
+		tok := tokens[i]
+		if tok.Type() != e.typ || tok.Value() != e.value || token.Start(tok).String() != e.pos {
+			t.Errorf("token %d expecting %d %q at %s, received %d %q at %s", i, e.typ, e.value, e.pos, tok.Type(),
+				tok.Value(), token.Start(tok))
+		}
+	}
+}
+
+// TestManyUnclosed confirms failed constructs are not re-matched, as nested attempts would otherwise take exponential
+// time.
+//
+func TestManyUnclosed(t *testing.T) {
+	input := strings.Repeat("*a _b [c ", 200)
+	out, err := Render(input)
+	if err != nil || out != input {
+		t.Errorf("Render() expecting input unchanged, received %d bytes (%v)", len(out), err)
+	}
+}
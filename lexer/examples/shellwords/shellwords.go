@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/shell"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Usage : shellwords
+//
+// Reads command lines from stdin, printing the words of each, with variables expanded from the environment.
+// Lines with unterminated quotes (or a trailing backslash) are continued on the next line.
+//
+func main() {
+	scanner := bufio.NewScanner(os.Stdin)
+	input := ""
+	prompt("$ ")
+	for scanner.Scan() {
+		input += scanner.Text() + "\n"
+
+		// Interactive mode reports unterminated quotes at the end of the input as incomplete
+		//
+		words, err := shell.Split(input, os.Getenv, lexer.WithInteractive())
+		if token.IsIncomplete(err) {
+			prompt("> ")
+			continue
+		}
+		if err != nil {
+			fmt.Println("error:", err)
+		}
+		for i, word := range words {
+			fmt.Printf("%d: %q\n", i, word)
+		}
+		input = ""
+		prompt("$ ")
+	}
+	if input != "" {
+		fmt.Println("error: unexpected end of input")
+	}
+}
+
+// prompt prints the prompt, if stdin is a terminal.
+//
+func prompt(p string) {
+	if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		fmt.Print(p)
+	}
+}
@@ -22,13 +22,6 @@ const (
 	tWord
 )
 
-// We will attempt to match 3 newline styles: [ "\n", "\r", "\r\n" ]
-//
-const (
-	runeNewLine = '\n'
-	runeReturn  = '\r'
-)
-
 func main() {
 	if len(os.Args) < 2 {
 		usage()
@@ -59,7 +52,10 @@ func main() {
 	//
 	var emptyLine = true
 
-	tokens := lexer.LexReader(file, lexerFn)
+	// We will attempt to match 3 newline styles: [ "\n", "\r", "\r\n" ]
+	// The same policy is used for the line numbers of the tokens
+	//
+	tokens := lexer.LexReader(file, lexerFn, lexer.WithLineTerminators(lexer.LineCRLF))
 
 	// Process lexer-emitted tokens
 	//
@@ -96,31 +92,20 @@ func main() {
 
 func lexerFn(l *lexer.Lexer) lexer.Fn {
 
-	// Can skip canPeek() check on first rune, per lexer rules
-	//
-	switch r := l.Peek(1); {
+	switch {
 
-	// Newline '\n'
+	// Newline '\n', or Return '\r', optionally followed by newLine '\n'
 	// We check this before Space to avoid hit from unicode.IsSpace() check
 	//
-	case r == runeNewLine:
-		l.Next()
-		l.EmitToken(tNewline)
-
-	// Return '\r', optionally followed by newLine '\n'
-	// We check this before Space to avoid hit from unicode.IsSpace() check
-	//
-	case r == runeReturn:
-		l.Next()
-		if l.CanPeek(1) && l.Peek(1) == runeNewLine {
-			l.Next()
-		}
+	case l.MatchNewline():
 		l.EmitToken(tNewline)
 
 	// Space or Word
 	//
 	default:
-		isSpace := unicode.IsSpace(r)
+		// Can skip canPeek() check on first rune, per lexer rules
+		//
+		isSpace := unicode.IsSpace(l.Peek(1))
 		// Match verified rune to avoid re-check
 		//
 		l.Next()
@@ -9,5 +9,7 @@ go 1.12
 require github.com/tekwizely/go-parsing/lexer/token v0.0.0-20190714025745-8a1a69651c50
 
 // For Local testing against changes that aren't upstream
+// The lexer depends on token changes not yet tagged, so this stays enabled until the next release;
+// To release: tag lexer/token, update the require version above via go get, then comment this out.
 //
-//replace github.com/tekwizely/go-parsing/lexer/token => ./token
+replace github.com/tekwizely/go-parsing/lexer/token => ./token
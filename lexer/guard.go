@@ -0,0 +1,116 @@
+package lexer
+
+import (
+	"container/list"
+	"fmt"
+	"reflect"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// progress snapshots the lexer state, for detecting lexer functions that neither consume input nor emit tokens.
+//
+type progress struct {
+	front    *list.Element
+	matchLen int
+	outLen   int
+	skipped  int
+}
+
+// progress returns a snapshot of the lexer state.
+//
+func (l *Lexer) progress() progress {
+	return progress{front: l.cache.Front(), matchLen: l.matchLen, outLen: l.output.Len(), skipped: l.skipped}
+}
+
+// guardLoop checks if the lexer function fn made progress since the snapshot was taken.
+// If the loop guard is enabled (see WithLoopGuard) and too many consecutive calls have made no progress, an error is
+// emitted naming the function and lexing is terminated.
+//
+func (l *Lexer) guardLoop(fn Fn, before progress) {
+	if l.loopLimit <= 0 || l.eofOut {
+		return
+	}
+	if l.progress() != before {
+		l.idleCalls = 0
+		return
+	}
+	l.idleCalls++
+	if l.idleCalls >= l.loopLimit {
+		e := &LoopError{Fn: fnName(fn), Calls: l.idleCalls}
+		// Bypass the error limit (see WithMaxErrors), so the error is always emitted, and is the last token before EOF
+		//
+		l.emitError(e.message(), false)
+		e.tok = l.output.Back()
+		e.Pos = token.Start(e.tok)
+		l.loopErr = e
+		l.EmitEOF()
+		l.nextFn = nil
+	}
+}
+
+// LoopError is the error returned by the token.Nexter when the loop guard terminates lexing (see WithLoopGuard).
+//
+type LoopError struct {
+	Fn    string         // Name of the lexer function that made no progress
+	Calls int            // Consecutive calls without progress
+	Pos   token.Position // Position of the error
+
+	tok *_token // The emitted error token, matched by the token.Nexter
+}
+
+// Error implements error, i.e. "3:14: runaway loop detected: main.lexValue returned 100 times without consuming input
+// or emitting tokens".
+//
+func (e *LoopError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.message())
+}
+
+// message returns the error message, without the position.
+//
+func (e *LoopError) message() string {
+	return fmt.Sprintf("runaway loop detected: %s returned %d times without consuming input or emitting tokens", e.Fn,
+		e.Calls)
+}
+
+// guardUnknown checks if the start function fn returned itself without making progress since the snapshot was
+// taken, and if so, applies the unknown-rune policy (see WithUnknown), returning the function to call next.
+// Returns nextFn if the policy is not enabled, or fn did not stall.
+//
+func (l *Lexer) guardUnknown(fn Fn, before progress, nextFn Fn) Fn {
+	if !l.unknown || l.eofOut || nextFn == nil || l.progress() != before {
+		return nextFn
+	}
+	if reflect.ValueOf(fn).Pointer() != l.startPC || reflect.ValueOf(nextFn).Pointer() != l.startPC {
+		return nextFn
+	}
+	if l.unknownFn != nil {
+		return l.unknownFn(l)
+	}
+	l.Next()
+	l.EmitToken(TUnknown)
+	return nextFn
+}
+
+// guardTokens terminates lexing with an error once the token limit is exceeded (see WithMaxTokens).
+//
+func (l *Lexer) guardTokens() {
+	if l.maxTokens > 0 && l.outCount > l.maxTokens && !l.eofOut {
+		l.emitError(fmt.Sprintf("too many tokens: limit of %d exceeded", l.maxTokens), false)
+		l.EmitEOF()
+		l.nextFn = nil
+	}
+}
+
+// tooManyErrors is the error emitted once the error limit is reached (see WithMaxErrors).
+//
+const tooManyErrors = "too many errors"
+
+// guardErrors terminates lexing once the error limit is reached (see WithMaxErrors).
+//
+func (l *Lexer) guardErrors() {
+	if l.maxErrors > 0 && l.errCount >= l.maxErrors && !l.eofOut {
+		l.EmitEOF()
+		l.nextFn = nil
+	}
+}
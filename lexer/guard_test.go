@@ -0,0 +1,165 @@
+package lexer
+
+import "testing"
+
+// lexStuck never consumes input
+//
+func lexStuck(l *Lexer) Fn {
+	l.Peek(1)
+	return lexStuck
+}
+
+// TestWithLoopGuard
+//
+func TestWithLoopGuard(t *testing.T) {
+	nexter := LexString("abc", lexStuck, WithLoopGuard(10))
+	expectNexterError(t, nexter,
+		"0:0: runaway loop detected: github.com/tekwizely/go-parsing/lexer.lexStuck returned 10 times without consuming input or emitting tokens")
+	expectNexterEOF(t, nexter)
+}
+
+// TestLoopError
+//
+func TestLoopError(t *testing.T) {
+	_, err := LexString("abc", lexStuck, WithLoopGuard(10)).Next()
+	e, ok := err.(*LoopError)
+	if !ok {
+		t.Fatalf("Nexter.Next() expecting *LoopError, received %T", err)
+	}
+	if e.Calls != 10 || e.Pos.String() != "0:0" {
+		t.Errorf("LoopError expecting 10 calls at 0:0, received %d calls at %s", e.Calls, e.Pos)
+	}
+	_, err = LexString("abc", func(l *Lexer) Fn {
+		l.EmitError("runaway loop detected")
+		return nil
+	}, WithLoopGuard(10)).Next()
+	if _, ok := err.(*LoopError); ok {
+		t.Errorf("Nexter.Next() expecting user error, received *LoopError '%s'", err)
+	}
+}
+
+// TestWithLoopGuardProgress
+//
+func TestWithLoopGuardProgress(t *testing.T) {
+	calls := 0
+	var fn Fn
+	fn = func(l *Lexer) Fn {
+		calls++
+		// Make progress every other call
+		//
+		if calls%2 == 0 {
+			l.Next()
+			if !l.CanPeek(1) {
+				l.EmitToken(TStart)
+			}
+		}
+		return fn
+	}
+	nexter := LexString("abc", fn, WithLoopGuard(2))
+	expectNexterNext(t, nexter, TStart, "abc", 1, 1)
+	expectNexterEOF(t, nexter)
+}
+
+// lexDigits emits runs of digits, returning without consuming anything else
+//
+func lexDigits(l *Lexer) Fn {
+	for l.CanPeek(1) && l.Peek(1) >= '0' && l.Peek(1) <= '9' {
+		l.Next()
+	}
+	if len(l.PeekToken()) > 0 {
+		l.EmitToken(TStart)
+	}
+	return lexDigits
+}
+
+// TestWithUnknown
+//
+func TestWithUnknown(t *testing.T) {
+	nexter := LexString("12a3", lexDigits, WithUnknown(nil))
+	expectNexterNext(t, nexter, TStart, "12", 1, 1)
+	expectNexterNext(t, nexter, TUnknown, "a", 1, 3)
+	expectNexterNext(t, nexter, TStart, "3", 1, 4)
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithUnknownFallback
+//
+func TestWithUnknownFallback(t *testing.T) {
+	fallback := func(l *Lexer) Fn {
+		l.EmitErrorf("unexpected '%c'", l.Next())
+		return nil
+	}
+	nexter := LexString("12a3", lexDigits, WithUnknown(fallback))
+	expectNexterNext(t, nexter, TStart, "12", 1, 1)
+	expectNexterError(t, nexter, "1:4: unexpected 'a'")
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithUnknownNotStart
+//
+func TestWithUnknownNotStart(t *testing.T) {
+	// Only the start function returning itself is guarded
+	//
+	var start Fn
+	start = func(l *Lexer) Fn {
+		if l.Peek(1) == 'a' {
+			return lexStuck
+		}
+		return lexDigits
+	}
+	nexter := LexString("a", start, WithUnknown(nil), WithLoopGuard(5))
+	expectNexterError(t, nexter,
+		"0:0: runaway loop detected: github.com/tekwizely/go-parsing/lexer.lexStuck returned 5 times without consuming input or emitting tokens")
+	expectNexterEOF(t, nexter)
+}
+
+// lexErrors emits each rune as an error, emitting two errors for 'x'
+//
+func lexErrors(l *Lexer) Fn {
+	r := l.Next()
+	l.EmitErrorf("bad '%c'", r)
+	if r == 'x' {
+		l.EmitError("bad again")
+	}
+	return lexErrors
+}
+
+// TestWithMaxErrors
+//
+func TestWithMaxErrors(t *testing.T) {
+	nexter := LexString("abcd", lexErrors, WithMaxErrors(2))
+	expectNexterError(t, nexter, "1:2: bad 'a'")
+	expectNexterError(t, nexter, "1:3: bad 'b'")
+	expectNexterError(t, nexter, "1:3: too many errors")
+	expectNexterEOF(t, nexter)
+	// Errors past the limit within the same function are discarded
+	//
+	nexter = LexString("ax", lexErrors, WithMaxErrors(2))
+	expectNexterError(t, nexter, "1:2: bad 'a'")
+	expectNexterError(t, nexter, "1:3: bad 'x'")
+	expectNexterError(t, nexter, "1:3: too many errors")
+	expectNexterEOF(t, nexter)
+	// No limit
+	//
+	nexter = LexString("ax", lexErrors)
+	expectNexterError(t, nexter, "1:2: bad 'a'")
+	expectNexterError(t, nexter, "1:3: bad 'x'")
+	expectNexterError(t, nexter, "1:3: bad again")
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithMaxTokens
+//
+func TestWithMaxTokens(t *testing.T) {
+	nexter := LexString("a b c d", lexFields, WithMaxTokens(2))
+	expectNexterNext(t, nexter, TStart, "a", 1, 1)
+	expectNexterNext(t, nexter, TStart, "b", 1, 3)
+	expectNexterError(t, nexter, "1:6: too many tokens: limit of 2 exceeded")
+	expectNexterEOF(t, nexter)
+	// Exactly at the limit
+	//
+	nexter = LexString("a b", lexFields, WithMaxTokens(2))
+	expectNexterNext(t, nexter, TStart, "a", 1, 1)
+	expectNexterNext(t, nexter, TStart, "b", 1, 3)
+	expectNexterEOF(t, nexter)
+}
@@ -0,0 +1,148 @@
+/*
+Package highlight turns a lexer into a syntax highlighter.
+
+A Highlighter pairs a lexer.Fn with a table mapping token types to highlight classes (i.e. "keyword", "string",
+"comment"), producing styled spans that can be rendered as HTML, terminal output, etc.
+
+*/
+package highlight
+
+import (
+	"html"
+	"io"
+	"strings"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Span identifies a styled range of the input.
+// Offset and Length are in bytes, allowing the span text to be sliced directly from the input string.
+//
+type Span struct {
+	Offset int
+	Length int
+	Class  string
+}
+
+// Highlighter produces styled spans for inputs lexed by a lexer.Fn.
+//
+type Highlighter struct {
+	start   lexer.Fn
+	classes map[token.Type]string
+	opts    []lexer.Option
+}
+
+// New returns a Highlighter that lexes inputs via the start function, mapping the emitted token types to highlight
+// classes via the classes table.
+// Tokens whose types are not found in the table are not styled.
+// Options, if any, are passed to the lexer for each input.
+// The highlighter records its own line table for each input (see lexer.WithLineTable), in order to locate tokens
+// using the column and line terminator modes of the lexer (see lexer.WithColumns and lexer.WithLineTerminators);
+// Any line table specified via the options is not filled.
+//
+func New(start lexer.Fn, classes map[token.Type]string, opts ...lexer.Option) *Highlighter {
+	return &Highlighter{start: start, classes: classes, opts: opts}
+}
+
+// Spans lexes the input and returns the styled spans, in order.
+// Empty tokens, and tokens whose values cannot be located within the input (i.e. tokens emitted via EmitType), are
+// not included.
+// Lexer errors do not stop the highlighter; The input is lexed to completion and the first error (if any) is returned
+// along with the spans.
+//
+func (h *Highlighter) Spans(input string) ([]Span, error) {
+	var spans []Span
+	var firstErr error
+	lines := lexer.NewLineTable()
+	opts := append(h.opts[:len(h.opts):len(h.opts)], lexer.WithLineTable(lines))
+	tokens := lexer.LexString(input, h.start, opts...)
+	for tok, err := tokens.Next(); err != io.EOF; tok, err = tokens.Next() {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if tok == nil || tok.Value() == "" {
+			continue
+		}
+		class, ok := h.classes[tok.Type()]
+		if !ok {
+			continue
+		}
+		offset, ok := locate(input, lines, tok)
+		if !ok {
+			continue
+		}
+		spans = append(spans, Span{Offset: offset, Length: len(tok.Value()), Class: class})
+	}
+	return spans, firstErr
+}
+
+// Render lexes the input and writes it to w, calling style to write each styled span, and plain to write the
+// unstyled text between spans.
+// Returns the first lexer error (see Spans), or the first error returned from style or plain.
+//
+func (h *Highlighter) Render(w io.Writer, input string, style func(w io.Writer, class string, text string) error,
+	plain func(w io.Writer, text string) error) error {
+	spans, lexErr := h.Spans(input)
+	pos := 0
+	for _, span := range spans {
+		if span.Offset > pos {
+			if err := plain(w, input[pos:span.Offset]); err != nil {
+				return err
+			}
+		}
+		if err := style(w, span.Class, input[span.Offset:span.Offset+span.Length]); err != nil {
+			return err
+		}
+		pos = span.Offset + span.Length
+	}
+	if pos < len(input) {
+		if err := plain(w, input[pos:]); err != nil {
+			return err
+		}
+	}
+	return lexErr
+}
+
+// HTML lexes the input and writes it to w as escaped HTML, with each styled span wrapped in
+// `<span class="CLASS">...</span>`.
+//
+func (h *Highlighter) HTML(w io.Writer, input string) error {
+	return h.Render(w, input, func(w io.Writer, class string, text string) error {
+		_, err := io.WriteString(w, `<span class="`+html.EscapeString(class)+`">`+html.EscapeString(text)+`</span>`)
+		return err
+	}, func(w io.Writer, text string) error {
+		_, err := io.WriteString(w, html.EscapeString(text))
+		return err
+	})
+}
+
+// ANSI lexes the input and writes it to w, with each styled span wrapped in ANSI escape sequences for terminal output.
+// The styles table maps highlight classes to SGR parameters (i.e. "1;34" for bold blue).
+// Classes not found in the styles table are written unstyled.
+//
+func (h *Highlighter) ANSI(w io.Writer, input string, styles map[string]string) error {
+	plain := func(w io.Writer, text string) error {
+		_, err := io.WriteString(w, text)
+		return err
+	}
+	return h.Render(w, input, func(w io.Writer, class string, text string) error {
+		sgr, ok := styles[class]
+		if !ok {
+			return plain(w, text)
+		}
+		_, err := io.WriteString(w, "\x1b["+sgr+"m"+text+"\x1b[0m")
+		return err
+	}, plain)
+}
+
+// locate computes the byte offset of the token within the input, via the line table built while lexing, confirming
+// the token value is found there.
+//
+func locate(input string, lines *lexer.LineTable, tok token.Token) (int, bool) {
+	offset := lines.Offset(token.Start(tok))
+	if offset < 0 || offset > len(input) || !strings.HasPrefix(input[offset:], tok.Value()) {
+		return 0, false
+	}
+	return offset, true
+}
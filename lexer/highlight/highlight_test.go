@@ -0,0 +1,122 @@
+package highlight
+
+import (
+	"bytes"
+	"testing"
+	"unicode"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+const (
+	TWord token.Type = lexer.TStart + iota
+	TNumber
+	TSpace
+	TPunct
+)
+
+// lexWords emits words, numbers, spaces and punctuation, with '!' as an error
+//
+func lexWords(l *lexer.Lexer) lexer.Fn {
+	r := l.Next()
+	switch {
+	case r == '!':
+		l.EmitError("bang")
+	case unicode.IsDigit(r):
+		l.EmitToken(TNumber)
+	case unicode.IsSpace(r):
+		l.EmitToken(TSpace)
+	case !unicode.IsLetter(r):
+		l.EmitToken(TPunct)
+	default:
+		for l.CanPeek(1) && unicode.IsLetter(l.Peek(1)) {
+			l.Next()
+		}
+		l.EmitToken(TWord)
+	}
+	return lexWords
+}
+
+var classes = map[token.Type]string{
+	TWord:   "word",
+	TNumber: "number",
+}
+
+// expectSpans
+//
+func expectSpans(t *testing.T, spans []Span, expected []Span) {
+	if len(spans) != len(expected) {
+		t.Errorf("Expecting %d spans %v, received %d spans %v", len(expected), expected, len(spans), spans)
+		return
+	}
+	for i := range spans {
+		if spans[i] != expected[i] {
+			t.Errorf("Expecting span[%d] %v, received %v", i, expected[i], spans[i])
+		}
+	}
+}
+
+// TestSpans
+//
+func TestSpans(t *testing.T) {
+	h := New(lexWords, classes)
+	spans, err := h.Spans("héllo 1\nbye!")
+	if err == nil || err.Error() != "2:5: bang" {
+		t.Errorf("Highlighter.Spans() expecting error '2:5: bang', received '%v'", err)
+	}
+	expectSpans(t, spans, []Span{
+		{Offset: 0, Length: 6, Class: "word"},
+		{Offset: 7, Length: 1, Class: "number"},
+		{Offset: 9, Length: 3, Class: "word"},
+	})
+}
+
+// TestSpansModes confirms tokens are located regardless of the column and line terminator modes of the lexer
+//
+func TestSpansModes(t *testing.T) {
+	expected := []Span{
+		{Offset: 0, Length: 6, Class: "word"},
+		{Offset: 8, Length: 6, Class: "word"},
+		{Offset: 15, Length: 1, Class: "number"},
+		{Offset: 19, Length: 3, Class: "word"},
+	}
+	for _, columns := range []lexer.ColumnMode{lexer.ColumnRunes, lexer.ColumnBytes, lexer.ColumnWidth} {
+		for _, lines := range []lexer.LineMode{lexer.LineLF, lexer.LineCRLF, lexer.LineUnicode} {
+			h := New(lexWords, classes, lexer.WithColumns(columns), lexer.WithLineTerminators(lines))
+			spans, err := h.Spans("héllo\r\n世界 1\u2028bye")
+			if err != nil {
+				t.Errorf("Highlighter.Spans() expecting nil error, received '%v'", err)
+			}
+			expectSpans(t, spans, expected)
+		}
+	}
+}
+
+// TestHTML
+//
+func TestHTML(t *testing.T) {
+	h := New(lexWords, classes)
+	b := &bytes.Buffer{}
+	if err := h.HTML(b, "a<b 2"); err != nil {
+		t.Errorf("Highlighter.HTML() received unexpected error '%s'", err)
+	}
+	expected := `<span class="word">a</span>&lt;<span class="word">b</span> <span class="number">2</span>`
+	if b.String() != expected {
+		t.Errorf("Highlighter.HTML() expecting '%s', received '%s'", expected, b.String())
+	}
+}
+
+// TestANSI
+//
+func TestANSI(t *testing.T) {
+	h := New(lexWords, classes)
+	b := &bytes.Buffer{}
+	if err := h.ANSI(b, "a 2", map[string]string{"number": "1;34"}); err != nil {
+		t.Errorf("Highlighter.ANSI() received unexpected error '%s'", err)
+	}
+	expected := "a \x1b[1;34m2\x1b[0m"
+	if b.String() != expected {
+		t.Errorf("Highlighter.ANSI() expecting '%q', received '%q'", expected, b.String())
+	}
+}
@@ -0,0 +1,154 @@
+package lexer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// IdentProfile describes the identifier syntax of a language, for use with ScanIdent.
+// See the predefined profiles IdentC, IdentXID, IdentKebab and IdentSQL.
+//
+type IdentProfile struct {
+	Start    func(rune) bool // Runes that can start an identifier
+	Part     func(rune) bool // Runes that can continue an identifier
+	NoEnd    string          // Runes that cannot end an identifier, i.e. "-" for kebab-case
+	Quote    rune            // Opening and closing rune of quoted identifiers, 0 if not supported
+	Escape   bool            // Is a doubled Quote within a quoted identifier an escaped quote?
+	MaxRunes int             // Max runes in an identifier, 0 for no limit
+}
+
+// IdentC matches C-like identifiers: An ASCII letter or '_', followed by ASCII letters, digits and '_'.
+//
+var IdentC = &IdentProfile{
+	Start: func(r rune) bool { return isASCIILetter(r) || r == '_' },
+	Part:  func(r rune) bool { return isASCIILetter(r) || isASCIIDigit(r) || r == '_' },
+}
+
+// IdentXID matches Unicode identifiers, per the XID_Start and XID_Continue properties of Unicode Standard Annex #31.
+// NOTE: '_' is not an XID_Start rune; Languages allowing identifiers to start with '_' should use a custom profile.
+//
+var IdentXID = &IdentProfile{
+	Start: isXIDStart,
+	Part:  isXIDContinue,
+}
+
+// IdentKebab matches kebab-case identifiers: An ASCII letter, followed by ASCII letters, digits and '-', not ending
+// with '-', i.e. "max-width".
+//
+var IdentKebab = &IdentProfile{
+	Start: isASCIILetter,
+	Part:  func(r rune) bool { return isASCIILetter(r) || isASCIIDigit(r) || r == '-' },
+	NoEnd: "-",
+}
+
+// IdentSQL matches SQL identifiers: A letter or '_', followed by letters, digits, '_' and '$', or a double-quoted
+// identifier, with doubled quotes as escapes, i.e. "order ""details""".
+// See IdentProfile.Unquote.
+//
+var IdentSQL = &IdentProfile{
+	Start:  func(r rune) bool { return unicode.IsLetter(r) || r == '_' },
+	Part:   func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$' },
+	Quote:  '"',
+	Escape: true,
+}
+
+// ScanIdent matches an identifier per the specified profile, returning true if matched.
+// The matched runes are added to the previously-matched runes; Emit them via EmitToken, i.e. after checking for
+// keywords.
+// Quoted identifiers are matched including the quotes (see IdentProfile.Unquote).
+// Returns false, with no runes matched, if the next rune cannot start an identifier (i.e. a leading digit), or if a
+// quoted identifier is not terminated.
+// Identifiers longer than MaxRunes are matched up to MaxRunes, with the remaining runes left in the peek buffer.
+//
+func ScanIdent(l *Lexer, profile *IdentProfile) bool {
+	n := profile.scan(l)
+	for i := 0; i < n; i++ {
+		l.Next()
+	}
+	return n > 0
+}
+
+// scan peeks at the identifier, returning the number of runes in it, or 0 if not found.
+//
+func (p *IdentProfile) scan(l *Lexer) int {
+	if !l.CanPeek(1) {
+		return 0
+	}
+	if r := l.Peek(1); p.Quote != 0 && r == p.Quote {
+		return p.scanQuoted(l)
+	} else if !p.Start(r) {
+		return 0
+	}
+	n := 1
+	for (p.MaxRunes <= 0 || n < p.MaxRunes) && l.CanPeek(n+1) && p.Part(l.Peek(n+1)) {
+		n++
+	}
+	for n > 0 && strings.ContainsRune(p.NoEnd, l.Peek(n)) {
+		n--
+	}
+	return n
+}
+
+// scanQuoted peeks at a quoted identifier, returning the number of runes in it, including the quotes, or 0 if not
+// terminated.
+//
+func (p *IdentProfile) scanQuoted(l *Lexer) int {
+	for n := 2; l.CanPeek(n); n++ {
+		if l.Peek(n) != p.Quote {
+			continue
+		}
+		if p.Escape && l.CanPeek(n+1) && l.Peek(n+1) == p.Quote {
+			n++ // Escaped quote
+			continue
+		}
+		return n
+	}
+	return 0
+}
+
+// Unquote returns the name of the identifier s, as matched by ScanIdent, removing the quotes from quoted
+// identifiers, and un-escaping any doubled quotes.
+// Returns s if it is not quoted.
+//
+func (p *IdentProfile) Unquote(s string) string {
+	q := string(p.Quote)
+	if p.Quote == 0 || len(s) < 2*len(q) || !strings.HasPrefix(s, q) || !strings.HasSuffix(s, q) {
+		return s
+	}
+	s = s[len(q) : len(s)-len(q)]
+	if p.Escape {
+		s = strings.Replace(s, q+q, q, -1)
+	}
+	return s
+}
+
+// isASCIILetter
+//
+func isASCIILetter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// isASCIIDigit
+//
+func isASCIIDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isXIDStart approximates the XID_Start property via the ID_Start definition (UAX #31).
+//
+func isXIDStart(r rune) bool {
+	if unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space) {
+		return false
+	}
+	return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start)
+}
+
+// isXIDContinue approximates the XID_Continue property via the ID_Continue definition (UAX #31).
+//
+func isXIDContinue(r rune) bool {
+	if unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space) {
+		return false
+	}
+	return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start, unicode.Mn, unicode.Mc, unicode.Nd,
+		unicode.Pc, unicode.Other_ID_Continue)
+}
@@ -0,0 +1,68 @@
+package lexer
+
+import "testing"
+
+// TestScanIdent
+//
+func TestScanIdent(t *testing.T) {
+	tests := []struct {
+		profile *IdentProfile
+		input   string
+		ident   string
+	}{
+		{IdentC, "foo_1+", "foo_1"},
+		{IdentC, "_x", "_x"},
+		{IdentC, "1abc", ""},
+		{IdentC, "été", ""},
+		{IdentXID, "été2 x", "été2"},
+		{IdentXID, "x\u0301y", "x\u0301y"},
+		{IdentXID, "_x", ""},
+		{IdentXID, "a+b", "a"},
+		{IdentKebab, "max-width:", "max-width"},
+		{IdentKebab, "a-b-", "a-b"},
+		{IdentKebab, "-a", ""},
+		{IdentSQL, "total$1 ", "total$1"},
+		{IdentSQL, "$x", ""},
+		{IdentSQL, `"order ""details""" x`, `"order ""details"""`},
+		{IdentSQL, `"unterminated`, ""},
+		{IdentSQL, `""`, `""`},
+		{&IdentProfile{Start: isASCIILetter, Part: isASCIILetter, MaxRunes: 3}, "abcdef", "abc"},
+	}
+	for _, test := range tests {
+		fn := func(l *Lexer) Fn {
+			if ScanIdent(l, test.profile) {
+				l.EmitToken(TStart)
+			} else if l.PeekToken() != "" {
+				t.Errorf("ScanIdent('%s') matched runes on failure", test.input)
+			}
+			return nil
+		}
+		nexter := LexString(test.input, fn)
+		if test.ident == "" {
+			expectNexterEOF(t, nexter)
+			continue
+		}
+		expectNexterNext(t, nexter, TStart, test.ident, 1, 1)
+	}
+}
+
+// TestIdentUnquote
+//
+func TestIdentUnquote(t *testing.T) {
+	tests := []struct {
+		profile  *IdentProfile
+		ident    string
+		expected string
+	}{
+		{IdentSQL, `"order ""details"""`, `order "details"`},
+		{IdentSQL, `""`, ``},
+		{IdentSQL, `plain`, `plain`},
+		{IdentSQL, `"`, `"`},
+		{IdentC, `"x"`, `"x"`},
+	}
+	for _, test := range tests {
+		if s := test.profile.Unquote(test.ident); s != test.expected {
+			t.Errorf("IdentProfile.Unquote('%s') expecting '%s', received '%s'", test.ident, test.expected, s)
+		}
+	}
+}
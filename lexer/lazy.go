@@ -0,0 +1,54 @@
+package lexer
+
+import "unicode/utf8"
+
+// valueChunkSize is the minimum size of each chunk of the value buffer.
+//
+const valueChunkSize = 4096
+
+// valueBuffer holds the bytes of token values not yet built, allowing token values to be built on first use.
+// Values are appended to shared chunks, avoiding a string allocation per token; Once a chunk is full, a new chunk is
+// started, leaving the old chunk to be collected once all of its tokens are released.
+//
+type valueBuffer struct {
+	buf   []byte // Current chunk
+	start int    // Start of the value being written
+}
+
+// newValueBuffer
+//
+func newValueBuffer() *valueBuffer {
+	return &valueBuffer{buf: nil, start: 0}
+}
+
+// begin starts a new value.
+//
+func (b *valueBuffer) begin() {
+	b.start = len(b.buf)
+}
+
+// WriteRune implements runeWriter.WriteRune(), appending r to the current value.
+//
+func (b *valueBuffer) WriteRune(r rune) (int, error) {
+	if cap(b.buf)-len(b.buf) < utf8.UTFMax {
+		// Start a new chunk, moving the current value into it
+		//
+		size := valueChunkSize
+		if n := 2*(len(b.buf)-b.start) + utf8.UTFMax; n > size {
+			size = n
+		}
+		buf := make([]byte, len(b.buf)-b.start, size)
+		copy(buf, b.buf[b.start:])
+		b.buf, b.start = buf, 0
+	}
+	n := utf8.EncodeRune(b.buf[len(b.buf):len(b.buf)+utf8.UTFMax], r)
+	b.buf = b.buf[:len(b.buf)+n]
+	return n, nil
+}
+
+// end returns the bytes of the current value.
+// The returned slice is capped, so it is never written to by later values.
+//
+func (b *valueBuffer) end() []byte {
+	return b.buf[b.start:len(b.buf):len(b.buf)]
+}
@@ -0,0 +1,37 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestWithLazyValues
+//
+func TestWithLazyValues(t *testing.T) {
+	long := strings.Repeat("é", valueChunkSize) // Spans chunks
+	nexter := LexString("ab 字 "+long+" cd", lexFields, WithLazyValues())
+	tok, err := nexter.Next()
+	if err != nil {
+		t.Fatalf("Nexter.Next() returned error: %v", err)
+	}
+	if tok.(*_token).raw == nil {
+		t.Errorf("Token value expecting to be deferred until Value() called")
+	}
+	if tok.Value() != "ab" || tok.(*_token).raw != nil {
+		t.Errorf("Token.Value() expecting 'ab', received '%s'", tok.Value())
+	}
+	expectNexterNext(t, nexter, TStart, "字", 1, 4)
+	expectNexterNext(t, nexter, TStart, long, 1, 6)
+	expectNexterNext(t, nexter, TStart, "cd", 1, 7+valueChunkSize)
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithLazyValuesTrace
+//
+func TestWithLazyValuesTrace(t *testing.T) {
+	nexter := LexString("ab", lexFields, WithLazyValues(), WithTrace(func(TraceEvent) {}))
+	tok, _ := nexter.Next()
+	if tok.(*_token).raw != nil || tok.Value() != "ab" {
+		t.Errorf("Token value expecting to be built when tracing")
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Package lexdump implements the lexdump developer tool, which reads input and prints the emitted token stream with
+types, values, and positions, in table, JSON or newline-delimited JSON (streaming) form.
+
+Lexers are registered by name, allowing you to build a lexdump for your own lexers:
+
+	func main() {
+		lexdump.Register("mylang", lexMyLang, map[token.Type]string{TIdent: "IDENT", TNumber: "NUMBER"})
+		lexdump.Main()
+	}
+
+The cmd/lexdump program ships with a few built-in example lexers.
+
+*/
+package lexdump
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Lexer describes a registered lexer.
+//
+type Lexer struct {
+	Name  string                // Name used to select the lexer
+	Start lexer.Fn              // Starting lexer function
+	Types map[token.Type]string // Token type names, for display
+}
+
+// registry holds the registered lexers, keyed by name.
+//
+var registry = map[string]*Lexer{}
+
+// Register registers a lexer under the specified name.
+// The types table provides display names for token types; Types not found in the table are displayed by number.
+// Panics if the name is already registered.
+//
+func Register(name string, start lexer.Fn, types map[token.Type]string) {
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("lexdump.Register: lexer '%s' already registered", name))
+	}
+	registry[name] = &Lexer{Name: name, Start: start, Types: types}
+}
+
+// Lookup returns the lexer registered under the specified name, or nil if not found.
+//
+func Lookup(name string) *Lexer {
+	return registry[name]
+}
+
+// Names returns the names of the registered lexers, sorted.
+//
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Entry captures a single token (or error) from the token stream.
+//
+type Entry struct {
+	Type   token.Type `json:"type"`
+	Name   string     `json:"name"`
+	Value  string     `json:"value"`
+	Line   int        `json:"line"`
+	Column int        `json:"column"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// Collect drains the token stream, returning an entry for each token and error, in order.
+//
+func Collect(tokens token.Nexter, types map[token.Type]string) []Entry {
+	var entries []Entry
+	for tok, err := tokens.Next(); err != io.EOF; tok, err = tokens.Next() {
+		if err != nil {
+			entries = append(entries, errorEntry(err))
+		}
+		if tok != nil {
+			entries = append(entries, tokenEntry(tok, types))
+		}
+	}
+	return entries
+}
+
+// errorEntry returns the entry for an error.
+//
+func errorEntry(err error) Entry {
+	return Entry{Type: lexer.TLexErr, Name: "ERROR", Line: -1, Column: -1, Error: err.Error()}
+}
+
+// tokenEntry returns the entry for a token, named via the types table.
+//
+func tokenEntry(tok token.Token, types map[token.Type]string) Entry {
+	name, ok := types[tok.Type()]
+	if !ok {
+		name = fmt.Sprintf("%d", tok.Type())
+	}
+	return Entry{
+		Type:   tok.Type(),
+		Name:   name,
+		Value:  tok.Value(),
+		Line:   tok.Line(),
+		Column: tok.Column(),
+		Error:  "",
+	}
+}
+
+// WriteTable writes the entries to w as an aligned table, with quoted values.
+//
+func WriteTable(w io.Writer, entries []Entry) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "POS\tTYPE\tVALUE")
+	for _, e := range entries {
+		if e.Error != "" {
+			fmt.Fprintf(tw, "\t%s\t%s\n", e.Name, e.Error)
+		} else {
+			fmt.Fprintf(tw, "%d:%d\t%s\t%q\n", e.Line, e.Column, e.Name, e.Value)
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the entries to w as an indented JSON array.
+//
+func WriteJSON(w io.Writer, entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// WriteNDJSON drains the token stream, writing each token and error to w as a single line of JSON (newline-delimited
+// JSON), as soon as it is received.
+// Allows the token stream to be consumed incrementally by other tools, i.e. `lexdump -format ndjson ... | jq ...`.
+// Returns the first error writing to w.
+//
+func WriteNDJSON(w io.Writer, tokens token.Nexter, types map[token.Type]string) error {
+	enc := json.NewEncoder(w)
+	for tok, err := tokens.Next(); err != io.EOF; tok, err = tokens.Next() {
+		if err != nil {
+			if encErr := enc.Encode(errorEntry(err)); encErr != nil {
+				return encErr
+			}
+		}
+		if tok != nil {
+			if encErr := enc.Encode(tokenEntry(tok, types)); encErr != nil {
+				return encErr
+			}
+		}
+	}
+	return nil
+}
+
+// Run implements the lexdump command, using the specified arguments (excluding the program name) and streams.
+// Input is read from the named file, or from stdin if no file is named.
+// Returns the process exit code.
+//
+func Run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lexdump", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	name := flags.String("lexer", "", "name of the lexer to use (see -list)")
+	format := flags.String("format", "table", "output format: table, json or ndjson")
+	list := flags.Bool("list", false, "list the registered lexers")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: lexdump [-format table|json|ndjson] -lexer name [file]")
+		fmt.Fprintln(stderr, "       lexdump -list")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *list {
+		fmt.Fprintln(stdout, strings.Join(Names(), "\n"))
+		return 0
+	}
+	l := Lookup(*name)
+	if l == nil {
+		fmt.Fprintf(stderr, "lexdump: unknown lexer '%s' (available: %s)\n", *name, strings.Join(Names(), ", "))
+		return 2
+	}
+	write := WriteTable
+	stream := false
+	switch *format {
+	case "table":
+	case "json":
+		write = WriteJSON
+	case "ndjson":
+		stream = true
+	default:
+		fmt.Fprintf(stderr, "lexdump: unknown format '%s'\n", *format)
+		return 2
+	}
+	input := stdin
+	switch flags.NArg() {
+	case 0:
+	case 1:
+		f, err := os.Open(flags.Arg(0))
+		if err != nil {
+			fmt.Fprintf(stderr, "lexdump: %s\n", err)
+			return 1
+		}
+		defer f.Close()
+		input = f
+	default:
+		flags.Usage()
+		return 2
+	}
+	tokens := lexer.LexReader(input, l.Start)
+	var err error
+	if stream {
+		err = WriteNDJSON(stdout, tokens, l.Types)
+	} else {
+		err = write(stdout, Collect(tokens, l.Types))
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "lexdump: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+// Main runs the lexdump command using the process arguments and standard streams, then exits.
+//
+func Main() {
+	os.Exit(Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
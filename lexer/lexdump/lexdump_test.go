@@ -0,0 +1,100 @@
+package lexdump
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+const (
+	TChar token.Type = lexer.TStart + iota
+)
+
+// lexChars emits each rune as TChar, and '!' as an error
+//
+func lexChars(l *lexer.Lexer) lexer.Fn {
+	if l.Next() == '!' {
+		l.EmitError("bang")
+	} else {
+		l.EmitToken(TChar)
+	}
+	return lexChars
+}
+
+func init() {
+	Register("chars", lexChars, map[token.Type]string{TChar: "CHAR"})
+}
+
+// expectRun
+//
+func expectRun(t *testing.T, args []string, input string, code int, stdout string, stderr string) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	if c := Run(args, strings.NewReader(input), out, errOut); c != code {
+		t.Errorf("Run(%v) expecting exit code %d, received %d", args, code, c)
+	}
+	if out.String() != stdout {
+		t.Errorf("Run(%v) expecting stdout:\n%s\nreceived:\n%s", args, stdout, out.String())
+	}
+	if !strings.Contains(errOut.String(), stderr) {
+		t.Errorf("Run(%v) expecting stderr to contain '%s', received '%s'", args, stderr, errOut.String())
+	}
+}
+
+// TestRunTable
+//
+func TestRunTable(t *testing.T) {
+	expectRun(t, []string{"-lexer", "chars"}, "a\n!", 0, ""+
+		"POS  TYPE   VALUE\n"+
+		"1:1  CHAR   \"a\"\n"+
+		"1:2  CHAR   \"\\n\"\n"+
+		"     ERROR  2:2: bang\n", "")
+}
+
+// TestRunJSON
+//
+func TestRunJSON(t *testing.T) {
+	expectRun(t, []string{"-lexer", "chars", "-format", "json"}, "a", 0, ""+
+		"[\n"+
+		"  {\n"+
+		"    \"type\": 3,\n"+
+		"    \"name\": \"CHAR\",\n"+
+		"    \"value\": \"a\",\n"+
+		"    \"line\": 1,\n"+
+		"    \"column\": 1\n"+
+		"  }\n"+
+		"]\n", "")
+	expectRun(t, []string{"-lexer", "chars", "-format", "json"}, "", 0, "[]\n", "")
+}
+
+// TestRunNDJSON
+//
+func TestRunNDJSON(t *testing.T) {
+	expectRun(t, []string{"-lexer", "chars", "-format", "ndjson"}, "a!", 0, ""+
+		`{"type":3,"name":"CHAR","value":"a","line":1,"column":1}`+"\n"+
+		`{"type":0,"name":"ERROR","value":"","line":-1,"column":-1,"error":"1:3: bang"}`+"\n", "")
+	expectRun(t, []string{"-lexer", "chars", "-format", "ndjson"}, "", 0, "", "")
+}
+
+// TestRunErrors
+//
+func TestRunErrors(t *testing.T) {
+	expectRun(t, []string{"-list"}, "", 0, "chars\n", "")
+	expectRun(t, []string{"-lexer", "nope"}, "", 2, "", "unknown lexer 'nope' (available: chars)")
+	expectRun(t, []string{"-lexer", "chars", "-format", "xml"}, "", 2, "", "unknown format 'xml'")
+	expectRun(t, []string{"-lexer", "chars", "no-such-file"}, "", 1, "", "no-such-file")
+	expectRun(t, []string{"-bogus"}, "", 2, "", "usage: lexdump")
+}
+
+// TestRegisterDuplicate
+//
+func TestRegisterDuplicate(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "lexdump.Register: lexer 'chars' already registered" {
+			t.Errorf("Register() expecting panic, received '%v'", r)
+		}
+	}()
+	Register("chars", lexChars, nil)
+}
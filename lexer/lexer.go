@@ -1,15 +1,19 @@
 package lexer
 
 import (
-	"bufio"
 	"bytes"
 	"container/list"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"reflect"
 	"strings"
+	"time"
 	"unicode/utf8"
 
+	"github.com/tekwizely/go-parsing/lexer/diag"
+	"github.com/tekwizely/go-parsing/lexer/source"
 	"github.com/tekwizely/go-parsing/lexer/token"
 )
 
@@ -27,8 +31,8 @@ type Fn func(*Lexer) Fn
 // The lexer will auto-emit EOF before exiting if it has not already been emitted.
 // This is a convenience method, wrapping the input string in an io.RuneReader, then calling LexRuneReader().
 //
-func LexString(input string, start Fn) token.Nexter {
-	return LexRuneReader(strings.NewReader(input), start)
+func LexString(input string, start Fn, opts ...Option) token.Nexter {
+	return LexRuneReader(strings.NewReader(input), start, opts...)
 }
 
 // LexRuneReader initiates a lexer against the input io.RuneReader.
@@ -36,9 +40,10 @@ func LexString(input string, start Fn) token.Nexter {
 // Invalid runes in the input will be silently ignored and will not be available within the lexer.
 // The lexer will auto-emit EOF before exiting if it has not already been emitted.
 // LexRuneReader is the primary lexer entrypoint. All others are convenience methods that delegate to here.
+// Options, if any, are applied before lexing begins.
 //
-func LexRuneReader(input io.RuneReader, start Fn) token.Nexter {
-	l := newLexer(input, start)
+func LexRuneReader(input io.RuneReader, start Fn, opts ...Option) token.Nexter {
+	l := newLexer(input, start, opts)
 	return &tokenNexter{lexer: l}
 }
 
@@ -47,16 +52,17 @@ func LexRuneReader(input io.RuneReader, start Fn) token.Nexter {
 // Invalid runes in the input will be silently ignored and will not be available within the lexer.
 // The lexer will auto-emit EOF before exiting if it has not already been emitted.
 // This is a convenience method, wrapping the input io.Reader in an io.RuneReader, then calling LexRuneReader().
+// The input is read in chunks (see WithReadSize), decoding runes directly from the chunk buffer.
 // If the provided reader already implements io.RuneReader, it is used without wrapping.
 //
-func LexReader(input io.Reader, start Fn) token.Nexter {
+func LexReader(input io.Reader, start Fn, opts ...Option) token.Nexter {
 	var runeReader io.RuneReader
 	if r, ok := input.(io.RuneReader); ok {
 		runeReader = r
 	} else {
-		runeReader = bufio.NewReader(input)
+		runeReader = newChunkReader(input)
 	}
-	return LexRuneReader(runeReader, start)
+	return LexRuneReader(runeReader, start, opts...)
 }
 
 // LexRunes initiates a lexer against the input []rune.
@@ -65,8 +71,8 @@ func LexReader(input io.Reader, start Fn) token.Nexter {
 // The lexer will auto-emit EOF before exiting if it has not already been emitted.
 // This is a convenience method, wrapping the input []rune in an io.RuneReader, then calling LexRuneReader().
 //
-func LexRunes(input []rune, start Fn) token.Nexter {
-	return LexRuneReader(strings.NewReader(string(input)), start)
+func LexRunes(input []rune, start Fn, opts ...Option) token.Nexter {
+	return LexRuneReader(strings.NewReader(string(input)), start, opts...)
 }
 
 // LexBytes initiates a lexer against the input []byte.
@@ -75,8 +81,39 @@ func LexRunes(input []rune, start Fn) token.Nexter {
 // The lexer will auto-emit EOF before exiting if it has not already been emitted.
 // This is a convenience method, wrapping the input []byte in an io.RuneReader, then calling LexRuneReader().
 //
-func LexBytes(input []byte, start Fn) token.Nexter {
-	return LexRuneReader(bytes.NewReader(input), start)
+func LexBytes(input []byte, start Fn, opts ...Option) token.Nexter {
+	return LexRuneReader(bytes.NewReader(input), start, opts...)
+}
+
+// LexSource initiates a lexer against the content of the source file.
+// The returned token.Nexter can be used to retrieve emitted tokens.
+// Emitted tokens implement source.Positioner, identifying their position within the file's source.Set.
+// Token positions are mapped assuming columns count runes (the default, see WithColumns), and lines are terminated by
+// '\n' (the default, see WithLineTerminators).
+// This is a convenience method, calling LexString() with the file content, then wrapping the token.Nexter via
+// source.File.Tokens().
+//
+func LexSource(file *source.File, start Fn, opts ...Option) token.Nexter {
+	return file.Tokens(LexString(file.Content(), start, opts...))
+}
+
+// LexAll lexes the input string, returning all of the emitted tokens, excluding EOF.
+// Lexing stops at the first error, returning the tokens emitted before it, along with the error.
+// This is a convenience method that simply drains the token.Nexter returned by LexString().
+//
+func LexAll(input string, start Fn, opts ...Option) ([]token.Token, error) {
+	nexter := LexString(input, start, opts...)
+	var tokens []token.Token
+	for {
+		tok, err := nexter.Next()
+		if err == io.EOF {
+			return tokens, nil
+		}
+		if err != nil {
+			return tokens, err
+		}
+		tokens = append(tokens, tok)
+	}
 }
 
 // Lexer is passed into your Lexer.Fn functions and provides methods to inspect runes and match them to tokens.
@@ -84,17 +121,88 @@ func LexBytes(input []byte, start Fn) token.Nexter {
 // review/match.
 //
 type Lexer struct {
-	input     io.RuneReader // Source of runes
-	cache     *list.List    // Cache of fetched runes, including matched & peeked
-	matchTail *list.Element // Points to last matched element in the cache, nil if no runes matched yet
-	matchLen  int           // Len of match buffer.  Makes growPeek faster when no growth needed
-	line      int           // Input line number
-	column    int           // Input column number (relative to line)
-	nextFn    Fn            // the next lexing function to enter
-	output    *list.List    // Cache of emitted tokens ready for pickup by a parser
-	eof       bool          // Has EOF been reached on the input reader? NOTE Peek buffer may still have runes in it
-	eofOut    bool          // Has EOF been emitted to the output buffer?
-	markerID  int           // Incremented after each emit/clear - used to validate markers
+	input     io.RuneReader    // Source of runes
+	cache     *list.List       // Cache of fetched runes, including matched & peeked
+	matchTail *list.Element    // Points to last matched element in the cache, nil if no runes matched yet
+	matchLen  int              // Len of match buffer.  Makes growPeek faster when no growth needed
+	line      int              // Input line number
+	column    int              // Input column number (relative to line)
+	nextFn    Fn               // the next lexing function to enter
+	output    *outputRing      // Cache of emitted tokens ready for pickup by a parser
+	batch     int              // Min pending tokens before delivery - see WithOutputBuffer()
+	flushed   bool             // Is the current batch ready for delivery? - see Flush()
+	eof       bool             // Has EOF been reached on the input reader? NOTE Peek buffer may still have runes in it
+	eofOut    bool             // Has EOF been emitted to the output buffer?
+	markerID  int              // Incremented after each emit/clear - used to validate markers
+	matchID   int              // Incremented after each Truncate / Marker.Apply - used to validate token readers
+	context   interface{}      // User context value - see Context()
+	lenient   bool             // Suppress post-EOF usage panics - see WithLenient()
+	misuse    error            // First post-EOF usage suppressed in lenient mode - see MisuseError()
+	trace     func(TraceEvent) // Receives trace events - see WithTrace()
+	recorder  *Recorder        // Records function transitions - see WithRecorder()
+	loopLimit int              // Max consecutive calls without progress, 0 to disable - see WithLoopGuard()
+	loopErr   *LoopError       // Error emitted by the loop guard, nil if none - see WithLoopGuard()
+	idleCalls int              // Consecutive calls without progress
+	stats     *Stats           // Counters, nil if disabled - see WithStats()
+	reporter  progressFn       // Progress callback - see WithProgress()
+	bytesRead int64            // Bytes read from the input, for progress reporting
+	runesRead int64            // Runes read from the input, including invalid runes, for progress reporting
+	reportAt  int64            // bytesRead value that triggers the next progress report
+	gaps      gapMap           // Runes discarded via Skip/ClearTo, keyed by the preceding matched rune
+	skipped   int              // Incremented after each Skip/Discard, for progress detection
+	startPC   uintptr          // Code pointer of the start function, for detecting stalls - see WithUnknown()
+	unknown   bool             // Emit TUnknown when the start function stalls - see WithUnknown()
+	unknownFn Fn               // User fallback for when the start function stalls - see WithUnknown()
+	lastOut   *_token          // Last token emitted, excluding EOF - see LastEmitted()
+	clearEnd  token.Position   // Position following the runes last cleared - see clear()
+	columns   ColumnMode       // How the column advances - see WithColumns()
+	norm      Normalizer       // Normalizes the input - see WithNormalization()
+	normForm  string           // Name of the normalization form - see Normalization()
+	ctxInit   interface{}      // Initial user context value, restored on Reset() - see WithContext()
+	lineTable *LineTable       // Records line offsets of the input - see WithLineTable()
+	diags     *diag.Collector  // Receives warnings and errors - see WithDiagnostics()
+	maxErrors int              // Max errors before lexing stops, 0 for no limit - see WithMaxErrors()
+	errCount  int              // Errors emitted
+	timeout   time.Duration    // Max time to wait for the start of the next token - see WithReadTimeout()
+	profiler  *Profiler        // Records per-function time and per-type token counts - see WithProfiler()
+	arena     *Arena           // Allocates emitted tokens - see WithArena()
+	lazy      *valueBuffer     // Holds the bytes of token values not yet built, nil if disabled - see WithLazyValues()
+	readSize  int              // Chunk size for io.Reader inputs, 0 for the default - see WithReadSize()
+	lineMode  LineMode         // Recognized line terminators - see WithLineTerminators()
+	prevRune  rune             // Last rune advanced over, for recognizing "\r\n" - see WithLineTerminators()
+	prescans  []Prescan        // Hooks for consuming header constructs before lexing starts - see WithPrescan()
+	headers   []Header         // Headers consumed by the prescan hooks - see Headers()
+	scanned   bool             // Have the prescan hooks been run?
+	repl      bool             // Report errors at the end of the input as incomplete - see WithInteractive()
+	maxTokens int              // Max tokens emitted before lexing stops, 0 for no limit - see WithMaxTokens()
+	outCount  int              // Tokens emitted, excluding errors and EOF
+	panicCtx  bool             // Include the function and position in panic messages - see WithPanicContext()
+}
+
+// Context returns the user context value of the lexer.
+// The context allows state, such as interpolation depth or pragma flags, to travel with the lexer, instead of
+// requiring closures or global variables.
+// Returns nil if no context has been set.
+// See SetContext and WithContext.
+//
+func (l *Lexer) Context() interface{} {
+	return l.context
+}
+
+// SetContext sets the user context value of the lexer.
+// The context is never inspected by the lexer and can be set at any time, including after EOF is emitted.
+//
+func (l *Lexer) SetContext(ctx interface{}) {
+	l.context = ctx
+}
+
+// MisuseError returns an error describing the first post-EOF usage suppressed in lenient mode.
+// Returns nil if no usage has been suppressed, or if the lexer is not in lenient mode.
+// The same error is returned once by the token.Nexter, just before io.EOF.
+// See WithLenient.
+//
+func (l *Lexer) MisuseError() error {
+	return l.misuse
 }
 
 // CanPeek confirms if the requested number of runes are available in the peek buffer.
@@ -105,7 +213,7 @@ type Lexer struct {
 //
 func (l *Lexer) CanPeek(n int) bool {
 	if n < 1 {
-		panic("Lexer.CanPeek: range error")
+		l.raise("Lexer.CanPeek: range error")
 	}
 	// Nothing can be peeked after EOF emitted
 	//
@@ -120,19 +228,19 @@ func (l *Lexer) CanPeek(n int) bool {
 // See CanPeek to confirm a minimum number of runes are available in the peek buffer.
 // Panics if n < 1.
 // Panics if nth rune not available.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 //
 func (l *Lexer) Peek(n int) rune {
 	if n < 1 {
-		panic("Lexer.Peek: range error")
+		l.raise("Lexer.Peek: range error")
 	}
 	// Nothing can be peeked after EOF emitted
 	//
-	if l.eofOut {
-		panic("Lexer.Peek: No runes can be peeked after EOF is emitted")
+	if l.afterEOF("Lexer.Peek: No runes can be peeked after EOF is emitted") {
+		return 0
 	}
 	if !l.growPeek(n) {
-		panic("Lexer.Peek: No rune available")
+		l.raise("Lexer.Peek: No rune available")
 	}
 	// Elements guaranteed to exist
 	//
@@ -140,6 +248,7 @@ func (l *Lexer) Peek(n int) rune {
 	for ; n > 1; n-- {
 		e = e.Next()
 	}
+	l.traceEvent(TracePeek, e.Value.(rune), 0, "")
 	return e.Value.(rune)
 }
 
@@ -147,34 +256,195 @@ func (l *Lexer) Peek(n int) rune {
 // See CanPeek(1) to confirm if a rune is available.
 // See Peek(1) to review the rune before consuming it.
 // Panics if no rune available.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 //
 func (l *Lexer) Next() rune {
 	// Nothing can be returned after EOF emitted
 	//
-	if l.eofOut {
-		panic("Lexer.Next: No runes can be matched after EOF is emitted")
+	if l.afterEOF("Lexer.Next: No runes can be matched after EOF is emitted") {
+		return 0
 	}
 	if !l.growPeek(1) {
-		panic("Lexer.Next: No rune available")
+		l.raise("Lexer.Next: No rune available")
 	}
 	// Element guaranteed to exist
 	//
 	e := l.peekHead()
 	l.matchTail = e // Match next rune into token
 	l.matchLen++
+	l.traceEvent(TraceNext, e.Value.(rune), 0, "")
 	return e.Value.(rune)
 }
 
+// Truncate un-matches the last n matched runes, returning them to the front of the peek buffer.
+// Useful when a scan overshoots (i.e. a trailing '.' after a number) and you want to emit the shorter token.
+// Outstanding markers remain valid, but outstanding token readers are invalidated (see TokenReader).
+// Panics if n < 0 or n > the number of matched runes.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (l *Lexer) Truncate(n int) {
+	// Nothing can be truncated after EOF emitted
+	//
+	if l.afterEOF("Lexer.Truncate: No runes can be truncated after EOF is emitted") {
+		return
+	}
+	if n < 0 || n > l.matchLen {
+		l.raise("Lexer.Truncate: range error")
+	}
+	for ; n > 0; n-- {
+		l.matchTail = l.matchTail.Prev()
+		l.matchLen--
+	}
+	if l.matchLen == 0 {
+		l.matchTail = nil
+	}
+	l.matchID++ // Invalidate outstanding token readers
+}
+
+// Skip consumes and immediately discards the next n runes in the input.
+// The current match is not affected and outstanding markers remain valid, but the skipped runes cannot be recovered
+// by applying a marker.
+// Line/column tracking accounts for the skipped runes.
+// n is 1-based.
+// Panics if n < 1.
+// Panics if fewer than n runes are available.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (l *Lexer) Skip(n int) {
+	if n < 1 {
+		l.raise("Lexer.Skip: range error")
+	}
+	// Nothing can be skipped after EOF emitted
+	//
+	if l.afterEOF("Lexer.Skip: No runes can be skipped after EOF is emitted") {
+		return
+	}
+	if !l.growPeek(n) {
+		l.raise("Lexer.Skip: Not enough runes available")
+	}
+	l.discard(n)
+}
+
+// Discard consumes and immediately discards runes from the input for as long as match returns true, returning the
+// number of runes discarded.
+// As with Skip, the current match is not affected and outstanding markers remain valid.
+// Returns 0 if EOF already emitted.
+//
+func (l *Lexer) Discard(match func(rune) bool) int {
+	n := 0
+	for l.CanPeek(n+1) && match(l.Peek(n+1)) {
+		n++
+	}
+	if n > 0 {
+		l.discard(n)
+	}
+	return n
+}
+
+// SkipTo matches runes up to, but not including, the first rune that satisfies pred, or to the end of the input,
+// returning the number of runes matched.
+// Useful for error recovery (i.e. skipping to the end of the line after a bad literal).
+// The skipped runes are added to the previously-matched runes, with line/column positions tracked as usual, so the
+// whole region can be discarded via Clear(), surfaced via EmitToken(TUnknown), or reported via EmitErrorToken().
+// Panics if EOF already emitted (see WithLenient).
+//
+func (l *Lexer) SkipTo(pred func(rune) bool) int {
+	// Nothing can be matched after EOF emitted
+	//
+	if l.afterEOF("Lexer.SkipTo: No runes can be matched after EOF is emitted") {
+		return 0
+	}
+	n := 0
+	for l.CanPeek(1) && !pred(l.Peek(1)) {
+		l.Next()
+		n++
+	}
+	return n
+}
+
+// SkipToAny matches runes up to, but not including, the first rune found in set, or to the end of the input,
+// returning the number of runes matched.
+// See SkipTo for details.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (l *Lexer) SkipToAny(set string) int {
+	// Nothing can be matched after EOF emitted
+	//
+	if l.afterEOF("Lexer.SkipToAny: No runes can be matched after EOF is emitted") {
+		return 0
+	}
+	return l.SkipTo(func(r rune) bool { return strings.ContainsRune(set, r) })
+}
+
+// discard removes the next n runes from the peek buffer.
+// Assumes n runes are available.
+//
+func (l *Lexer) discard(n int) {
+	b := &strings.Builder{}
+	for ; n > 0; n-- {
+		e := l.peekHead()
+		b.WriteRune(e.Value.(rune))
+		l.cache.Remove(e)
+	}
+	l.addGap(l.matchTail, b.String())
+	l.skipped++
+}
+
+// TryPeek is a non-panicking variant of Peek.
+// n is 1-based.
+// Returns the nth rune and true if available, otherwise returns 0 and false.
+// Returns false if n < 1.
+// Returns false if EOF already emitted.
+//
+func (l *Lexer) TryPeek(n int) (rune, bool) {
+	if n < 1 || !l.CanPeek(n) {
+		return 0, false
+	}
+	return l.Peek(n), true
+}
+
+// TryNext is a non-panicking variant of Next.
+// Matches and returns the next rune and true if available, otherwise returns 0 and false.
+// Returns false if EOF already emitted.
+//
+func (l *Lexer) TryNext() (rune, bool) {
+	if !l.CanPeek(1) {
+		return 0, false
+	}
+	return l.Next(), true
+}
+
+// NotFollowedBy is a negative lookahead assertion: It calls match, which tries to match runes from the input, then
+// resets the lexer to its prior state, returning true if match returned false.
+// No runes are consumed, regardless of the result.
+// match must not emit or clear.
+// Panics if match emits or clears, as the lexer state can no longer be reset.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (l *Lexer) NotFollowedBy(match func(*Lexer) bool) bool {
+	// Nothing can be matched after EOF emitted
+	//
+	if l.afterEOF("Lexer.NotFollowedBy: No runes can be matched after EOF is emitted") {
+		return false
+	}
+	m := l.Marker()
+	matched := match(l)
+	if !m.Valid() {
+		l.raise("Lexer.NotFollowedBy: match function must not emit or clear")
+	}
+	m.Apply()
+	return !matched
+}
+
 // PeekToken allows you to inspect the currently matched rune sequence.
 // The value is returned as a string, same as EmitToken() would provide.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 //
 func (l *Lexer) PeekToken() string {
 	// Nothing can be peeked after EOF emitted
 	//
-	if l.eofOut {
-		panic("Lexer.PeekToken: No token peeks allowed after EOF is emitted")
+	if l.afterEOF("Lexer.PeekToken: No token peeks allowed after EOF is emitted") {
+		return ""
 	}
 	b := &strings.Builder{}
 	for n, e := 0, l.cache.Front(); n < l.matchLen; n, e = n+1, e.Next() {
@@ -183,57 +453,194 @@ func (l *Lexer) PeekToken() string {
 	return b.String()
 }
 
+// Pending returns the currently matched rune sequence (same as PeekToken), along with its starting position and the
+// position immediately following it, computed together from the same lexer state so they cannot drift apart.
+// The end position follows the same conventions as token.End: A newline advances to the next line, with a column of
+// 0.
+// Positions account for runes discarded within the match (see Skip), as well as the column mode (see WithColumns).
+// If no runes are matched, text is "" and end == start.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (l *Lexer) Pending() (text string, start token.Position, end token.Position) {
+	// Nothing can be peeked after EOF emitted
+	//
+	if l.afterEOF("Lexer.Pending: No token peeks allowed after EOF is emitted") {
+		return "", token.Position{}, token.Position{}
+	}
+	b := &strings.Builder{}
+	span := l.pendingSpan(b)
+	return b.String(), span.Start, span.End
+}
+
+// pendingSpan computes the span of the matched runes, without consuming them, writing the runes to text (if not nil).
+// Follows the same conventions as clearInto: A newline advances to the next line, with a column of 0, and runes
+// discarded between matched runes (see Skip) are advanced over.
+//
+func (l *Lexer) pendingSpan(text *strings.Builder) token.Span {
+	start := l.matchStart()
+	line, column := start.Line, start.Column
+	prev := l.prevRune
+	advance := func(r rune) {
+		if column == 0 {
+			column = 1
+		}
+		if newline, visible := l.lineMode.lineBreak(prev, r); newline {
+			line++
+			column = 0
+		} else if visible {
+			column += l.columns.width(r)
+		}
+		prev = r
+	}
+	for n, e := 0, l.cache.Front(); n < l.matchLen; n, e = n+1, e.Next() {
+		r := e.Value.(rune)
+		if text != nil {
+			text.WriteRune(r)
+		}
+		advance(r)
+		// Advance over any discarded runes between this rune and the next matched rune
+		//
+		if gap, ok := l.gaps[e]; ok && n < l.matchLen-1 {
+			for _, g := range gap {
+				advance(g)
+			}
+		}
+	}
+	return token.Span{Start: start, End: token.Position{Line: line, Column: column}}
+}
+
+// matchStart returns the starting position of the current match, as reported for emitted tokens, i.e. the column
+// following a newline is reported as 1, rather than 0.
+//
+func (l *Lexer) matchStart() token.Position {
+	line, column := l.line, l.column
+	if line == 0 {
+		line = 1
+	}
+	if column == 0 {
+		column = 1
+	}
+	return token.Position{Line: line, Column: column}
+}
+
+// LastEmitted returns the token most recently emitted by the lexer, including error tokens (TLexErr), allowing
+// context-sensitive decisions (i.e. regex-vs-divide) to consult the previous token.
+// Returns nil if no tokens have been emitted yet.
+// EOF is never returned, as no lexing happens after EOF is emitted.
+//
+func (l *Lexer) LastEmitted() token.Token {
+	if l.lastOut == nil {
+		return nil
+	}
+	return l.lastOut
+}
+
 // EmitToken emits a token of the specified type, along with all of the matched runes.
 // It is safe to emit TEof via this method.
 // If the type is TEof, then all previously-matched runes are discarded and this is treated as EmitEOF().
 // All outstanding markers are invalidated after this call.
 // See EmitEOF for more details on the effects of emitting EOF.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 //
 func (l *Lexer) EmitToken(t token.Type) {
 	// Nothing can be emitted after EOF emitted
 	//
-	if l.eofOut {
-		panic("Lexer.EmitToken: No further emits allowed after EOF is emitted")
+	if l.afterEOF("Lexer.EmitToken: No further emits allowed after EOF is emitted") {
+		return
 	}
 	l.emit(t, true)
 }
 
+// EmitTokenOn emits a token of the specified type, along with all of the previously-matched runes, on the specified
+// channel (see token.Channel).
+// Tokens on channels other than token.ChannelDefault flow through the token stream, but are skipped by parsers
+// unless made visible (see parser.WithChannels).
+// Emitting TEof via this method ignores the channel.
+// All outstanding markers are invalidated after this call.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (l *Lexer) EmitTokenOn(t token.Type, ch token.Channel) {
+	// Nothing can be emitted after EOF emitted
+	//
+	if l.afterEOF("Lexer.EmitTokenOn: No further emits allowed after EOF is emitted") {
+		return
+	}
+	if tok := l.emit(t, true); tok != nil && t != TEof {
+		tok.channel = ch
+	}
+}
+
+// EmitCooked emits a token of the specified type, along with all of the previously-matched runes, attaching the
+// cooked value v, i.e. the time.Time parsed from a date literal (see token.Cooked).
+// Cooked values are not captured by snapshots (see Snapshot).
+// Emitting TEof via this method ignores the value.
+// All outstanding markers are invalidated after this call.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (l *Lexer) EmitCooked(t token.Type, v interface{}) {
+	// Nothing can be emitted after EOF emitted
+	//
+	if l.afterEOF("Lexer.EmitCooked: No further emits allowed after EOF is emitted") {
+		return
+	}
+	if tok := l.emit(t, true); tok != nil && t != TEof {
+		tok.cooked = v
+	}
+}
+
 // EmitType emits a token of the specified type, discarding all previously-matched runes.
 // The emitted token will have a Text() value of "".
 // It is safe to emit TEof via this method.
 // All outstanding markers are invalidated after this call.
 // See EmitEOF for more details on the effects of emitting EOF.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 //
 func (l *Lexer) EmitType(t token.Type) {
 	// Nothing can be emitted after EOF emitted
 	//
-	if l.eofOut {
-		panic("Lexer.EmitType: No further emits allowed after EOF is emitted")
+	if l.afterEOF("Lexer.EmitType: No further emits allowed after EOF is emitted") {
+		return
 	}
 	l.emit(t, false)
 }
 
 // EmitError Emits a token of type TLexErr with the specified err string as the token text.
+// The token.Nexter returns the error as an *Error, preserving the raw message along with its position.
+// The error is also recorded by the diagnostics collector, if any (see WithDiagnostics).
+// Once the error limit is reached (see WithMaxErrors), further errors are discarded.
 // All outstanding markers are invalidated after this call.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 //
 func (l *Lexer) EmitError(err string) {
 	// Nothing can be emitted after EOF emitted
 	//
-	if l.eofOut {
-		panic("Lexer.EmitError: No further emits allowed after EOF is emitted")
+	if l.afterEOF("Lexer.EmitError: No further emits allowed after EOF is emitted") {
+		return
 	}
-	l.clear(false)
-	// TODO This is a tad kludgie - Think of a better way to inject a string into the standard emit flow.
-	err = fmt.Sprintf("%d:%d: %s", l.line, l.column, err)
-	l.output.PushBack(newToken(TLexErr, err, l.line, l.column))
+	l.reportError(err, false)
+}
+
+// EmitErrorToken Emits a token of type TLexErr with the specified err string as the token text, attaching the
+// previously-matched runes to the error, rather than discarding them.
+// The token.Nexter returns the error as an *Error, with Error.Text holding the matched runes, and Error.Pos /
+// Error.End spanning them, allowing messages to quote exactly what was seen.
+// The error is also recorded by the diagnostics collector, if any (see WithDiagnostics), spanning the matched runes.
+// Once the error limit is reached (see WithMaxErrors), further errors are discarded.
+// All outstanding markers are invalidated after this call.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (l *Lexer) EmitErrorToken(err string) {
+	// Nothing can be emitted after EOF emitted
+	//
+	if l.afterEOF("Lexer.EmitErrorToken: No further emits allowed after EOF is emitted") {
+		return
+	}
+	l.reportError(err, true)
 }
 
 // EmitErrorf Emits a token of type TLexErr with the formatted err string as the token text.
 // All outstanding markers are invalidated after this call.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 // This is a convenience method that simply sends the formatted string to EmitError().
 //
 func (l *Lexer) EmitErrorf(format string, args ...interface{}) {
@@ -246,7 +653,7 @@ func (l *Lexer) EmitErrorf(format string, args ...interface{}) {
 // No more reads to the underlying RuneReader will happen once EOF is emitted.
 // No more runes can be matched once EOF is emitted.
 // All outstanding markers are invalidated after this call.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 // This is a convenience method that simply calls EmitType(TEof).
 //
 func (l *Lexer) EmitEOF() {
@@ -255,20 +662,21 @@ func (l *Lexer) EmitEOF() {
 
 // Clear discards all previously-matched runes without emitting any tokens.
 // All outstanding markers are invalidated after this call.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 //
 func (l *Lexer) Clear() {
 	// Nothing can be cleared after EOF emitted
 	//
-	if l.eofOut {
-		panic("Lexer.Clear: No clears allowed after EOF is emitted")
+	if l.afterEOF("Lexer.Clear: No clears allowed after EOF is emitted") {
+		return
 	}
+	l.traceEvent(TraceClear, 0, 0, "")
 	l.clear(false)
 }
 
 // newLexer
 //
-func newLexer(reader io.RuneReader, start Fn) *Lexer {
+func newLexer(reader io.RuneReader, start Fn, opts []Option) *Lexer {
 	l := &Lexer{
 		input:     reader,
 		cache:     list.New(),
@@ -277,14 +685,155 @@ func newLexer(reader io.RuneReader, start Fn) *Lexer {
 		line:      0,
 		column:    0,
 		nextFn:    start,
-		output:    list.New(),
+		output:    newOutputRing(defaultOutputSize),
+		batch:     1,
+		flushed:   false,
 		eof:       false,
 		eofOut:    false,
 		markerID:  0,
+		matchID:   0,
+		context:   nil,
+		lenient:   false,
+		misuse:    nil,
+		trace:     nil,
+		recorder:  nil,
+		loopLimit: 0,
+		loopErr:   nil,
+		idleCalls: 0,
+		stats:     nil,
+		reporter:  nil,
+		bytesRead: 0,
+		runesRead: 0,
+		reportAt:  progressInterval,
+		gaps:      nil,
+		skipped:   0,
+		startPC:   reflect.ValueOf(start).Pointer(),
+		unknown:   false,
+		unknownFn: nil,
+		lastOut:   nil,
+		clearEnd:  token.Position{},
+		columns:   ColumnRunes,
+		norm:      nil,
+		normForm:  "",
+		ctxInit:   nil,
+		lineTable: nil,
+		diags:     nil,
+		maxErrors: 0,
+		errCount:  0,
+		timeout:   0,
+		profiler:  nil,
+		arena:     nil,
+		lazy:      nil,
+		readSize:  0,
+		lineMode:  LineLF,
+		prevRune:  0,
+		prescans:  nil,
+		headers:   nil,
+		scanned:   false,
+		repl:      false,
+		maxTokens: 0,
+		panicCtx:  false,
+		outCount:  0,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	l.ctxInit = l.context
+	if c, ok := l.input.(*chunkReader); ok && l.readSize > 0 {
+		c.size = l.readSize
+	}
+	if l.norm != nil {
+		l.normalize()
+	}
+	if l.timeout > 0 {
+		l.input = newTimeoutReader(l.input)
+	}
+	if l.lineTable != nil {
+		l.lineTable.reset(l.columns, l.lineMode)
 	}
 	return l
 }
 
+// Reset re-initializes the lexer to lex a new input, starting with the specified function, allowing the lexer to be
+// reused without re-allocating its internal buffers.
+// Options applied at creation remain in effect, and the user context is restored to its initial value (see
+// WithContext).
+// Counters (see Stats) accumulate across resets.
+// Any unread tokens are discarded, and all outstanding markers are invalidated.
+// Intended for reusing lexers between inputs; See Pool.
+//
+func (l *Lexer) Reset(input io.RuneReader, start Fn) {
+	l.input = input
+	l.cache.Init()
+	l.matchTail = nil
+	l.matchLen = 0
+	l.line = 0
+	l.column = 0
+	l.prevRune = 0
+	l.headers = nil
+	l.scanned = false
+	l.nextFn = start
+	l.output.reset()
+	l.flushed = false
+	l.eof = false
+	l.eofOut = false
+	l.markerID++ // Invalidate outstanding markers
+	l.context = l.ctxInit
+	l.misuse = nil
+	l.loopErr = nil
+	l.idleCalls = 0
+	l.bytesRead = 0
+	l.runesRead = 0
+	l.reportAt = progressInterval
+	l.gaps = nil
+	l.skipped = 0
+	l.startPC = reflect.ValueOf(start).Pointer()
+	l.lastOut = nil
+	l.errCount = 0
+	l.outCount = 0
+	if l.norm != nil {
+		l.normalize()
+	}
+	if l.timeout > 0 {
+		l.input = newTimeoutReader(l.input)
+	}
+	if l.lineTable != nil {
+		l.lineTable.reset(l.columns, l.lineMode)
+	}
+}
+
+// afterEOF confirms if EOF has already been emitted, for methods that are not allowed after EOF.
+// In lenient mode, the first such usage is recorded (see MisuseError) and true is returned, allowing the caller to
+// return a zero value instead.
+// Panics with msg if EOF already emitted and not in lenient mode.
+//
+func (l *Lexer) afterEOF(msg string) bool {
+	if !l.eofOut {
+		return false
+	}
+	if !l.lenient {
+		l.raise(msg)
+	}
+	if l.misuse == nil {
+		l.misuse = errors.New(msg)
+	}
+	return true
+}
+
+// raise panics with msg, suffixed with the current lexer function and position if WithPanicContext is set,
+// i.e. "Lexer.Peek: range error (in main.lexNumber at 3:14)".
+//
+func (l *Lexer) raise(msg string) {
+	if l.panicCtx {
+		if name := fnName(l.nextFn); name != "" {
+			msg += fmt.Sprintf(" (in %s at %d:%d)", name, l.line, l.column)
+		} else {
+			msg += fmt.Sprintf(" (at %d:%d)", l.line, l.column)
+		}
+	}
+	panic(msg)
+}
+
 // growPeek tries to ensure the peek buffer has Len() >= n, growing if needed, returning success or failure.
 // n is 1-based.
 //
@@ -304,6 +853,15 @@ func (l *Lexer) growPeek(n int) bool {
 		// Process any returned rune, regardless of err
 		//
 		if size > 0 {
+			l.bytesRead += int64(size)
+			l.runesRead++
+			if l.reporter != nil && l.bytesRead >= l.reportAt {
+				l.reporter(l.bytesRead, l.runesRead)
+				l.reportAt = l.bytesRead + progressInterval
+			}
+			if l.lineTable != nil {
+				l.lineTable.add(r, size)
+			}
 			// Skip rune errors
 			// TODO Log rune errors
 			//
@@ -312,6 +870,12 @@ func (l *Lexer) growPeek(n int) bool {
 				//
 				l.cache.PushBack(r)
 				peekLen++
+				if l.stats != nil {
+					l.stats.RunesRead++
+					if l.cache.Len() > l.stats.PeakBuffer {
+						l.stats.PeakBuffer = l.cache.Len()
+					}
+				}
 			}
 		}
 		// If there was an error, process it now
@@ -333,6 +897,11 @@ func (l *Lexer) growPeek(n int) bool {
 				log.Printf("non-EOF error returned from rune reader, treating as EOF: %v", err)
 				l.eof = true
 			}
+			// Final progress report
+			//
+			if l.reporter != nil {
+				l.reporter(l.bytesRead, l.runesRead)
+			}
 		}
 	}
 	return true
@@ -354,11 +923,12 @@ func (l *Lexer) peekHead() *list.Element {
 	return l.cache.Front()
 }
 
-// emit Emits a Token, optionally including the matched text.
+// emit Emits a Token, optionally including the matched text, returning the token emitted.
 // If token.Type is TEof, emitText is ignored and treated as false.
+// Returns nil if the token was dropped (see WithMaxTokens).
 // Panics if EOF already emitted.
 //
-func (l *Lexer) emit(typ token.Type, emitText bool) {
+func (l *Lexer) emit(typ token.Type, emitText bool) *_token {
 	// TODO Current tests show this will never be called. Maybe uncomment this once in awhile to confirm :)
 	// // Nothing can be emitted after EOF
 	// // NOTE: This check is a fail-safe and will likely never hit as all public methods check/panic explicitly.
@@ -367,9 +937,28 @@ func (l *Lexer) emit(typ token.Type, emitText bool) {
 	// 	panic("Lexer: No further emits allowed after EOF is emitted")
 	// }
 
-	// Fetch/clear the matched token
+	// Drop tokens beyond the token limit - see WithMaxTokens()
+	//
+	if typ != TEof {
+		l.outCount++
+		if l.maxTokens > 0 && l.outCount > l.maxTokens {
+			l.clear(false)
+			return nil
+		}
+	}
+	// Fetch/clear the matched token, deferring the value string if enabled (see WithLazyValues)
+	// Values are always built when tracing, as trace events include them.
 	//
-	value, line, column := l.clear(typ != TEof && emitText) // Force-discard on EOF
+	var value string
+	var raw []byte
+	var line, column int
+	if l.lazy != nil && l.trace == nil && typ != TEof && emitText {
+		l.lazy.begin()
+		line, column = l.clearInto(l.lazy)
+		raw = l.lazy.end()
+	} else {
+		value, line, column = l.clear(typ != TEof && emitText) // Force-discard on EOF
+	}
 	// If emitting EOF
 	//
 	if typ == TEof {
@@ -378,33 +967,115 @@ func (l *Lexer) emit(typ token.Type, emitText bool) {
 		// assert(l.matchLen == 0)
 		// assert(l.matchTail == nil)
 		l.cache.Init() // TODO May not be strictly necessary
+		l.gaps = nil
 		// Mark EOF
 		//
 		l.eof = true
 		l.eofOut = true
 	}
 
-	l.output.PushBack(newToken(typ, value, line, column))
+	l.traceEvent(TraceEmit, 0, typ, value)
+	l.countEmit(typ)
+	tok := l.newToken(typ, value, line, column)
+	tok.raw = raw
+	if typ != TEof && emitText {
+		tok.end = l.clearEnd
+	}
+	if typ != TEof {
+		l.lastOut = tok
+	}
+	l.output.PushBack(tok)
+	return tok
+}
+
+// reportError emits an error, applying the error limit (see WithMaxErrors).
+//
+func (l *Lexer) reportError(err string, keepText bool) {
+	if l.maxErrors > 0 && l.errCount >= l.maxErrors {
+		l.clear(false)
+		return
+	}
+	l.emitError(err, keepText)
+	l.errCount++
+	if l.errCount == l.maxErrors {
+		l.emitError(tooManyErrors, false)
+	}
+}
+
+// emitError emits a token of type TLexErr, attaching the matched runes to the error if keepText, otherwise discarding
+// them.
+//
+func (l *Lexer) emitError(err string, keepText bool) {
+	var e *Error
+	// Only an end of input already reached counts, as peeking could block waiting on interactive input
+	//
+	incomplete := l.repl && err != tooManyErrors && l.eof && l.cache.Len() == l.matchLen
+	if keepText {
+		span := l.matchSpan()
+		text, _, _ := l.clear(true)
+		e = &Error{Message: err, Pos: span.Start, Text: text, End: span.End}
+	} else {
+		l.clear(false)
+		e = &Error{Message: err, Pos: token.Position{Line: l.line, Column: l.column}}
+	}
+	// The token keeps the raw message, with the position stored separately - see Error
+	//
+	l.lastOut = l.newToken(TLexErr, err, e.Pos.Line, e.Pos.Column)
+	if keepText {
+		l.lastOut.end = e.End
+	}
+	if keepText || incomplete {
+		e.incomplete = incomplete
+		l.lastOut.err = e
+	}
+	l.traceEvent(TraceEmit, 0, TLexErr, e.Error())
+	l.countEmit(TLexErr)
+	l.output.PushBack(l.lastOut)
+	if l.diags != nil {
+		end := e.Pos
+		if keepText {
+			end = e.End
+		}
+		l.diags.Add(diag.Diagnostic{Severity: diag.Error, Span: token.Span{Start: e.Pos, End: end}, Message: err})
+	}
 }
 
 // clear discards the previously-matched runes, optionally returning them as a
 // string, along with their starting line/column within the input.
+// The position following the runes, excluding any skipped runes after them, is recorded in clearEnd.
 // All outstanding markers are invalidated after this call.
 //
 func (l *Lexer) clear(returnText bool) (string, int, int) {
-	// For saving matched runes
-	// Stays empty if !returnText
-	//
+	if !returnText {
+		line, column := l.clearInto(nil)
+		return "", line, column
+	}
 	b := &strings.Builder{}
+	line, column := l.clearInto(b)
+	return b.String(), line, column
+}
+
+// runeWriter receives the matched runes as they are cleared.
+//
+type runeWriter interface {
+	WriteRune(r rune) (int, error)
+}
+
+// clearInto discards the previously-matched runes, writing them to text (if not nil), returning their starting
+// line/column within the input.
+// All outstanding markers are invalidated after this call.
+//
+func (l *Lexer) clearInto(text runeWriter) (int, int) {
 	// Default values. Will update if matchLen > 0
 	//
 	line, column := l.line, l.column
+	l.clearEnd = token.Position{Line: line, Column: column}
 	first := true
 	for l.matchLen > 0 {
 		e := l.cache.Front()
 		r := e.Value.(rune)
-		if returnText {
-			b.WriteRune(r)
+		if text != nil {
+			_, _ = text.WriteRune(r)
 		}
 		// Adjust line/column for first line / new line
 		//
@@ -420,16 +1091,67 @@ func (l *Lexer) clear(returnText bool) (string, int, int) {
 			line, column = l.line, l.column
 			first = false
 		}
-		if r == '\n' {
-			l.line++
-			l.column = 0
-		} else {
-			l.column++
+		l.advance(r)
+		l.clearEnd = token.Position{Line: l.line, Column: l.column}
+		// Skip any discarded runes following this one
+		//
+		if gap, ok := l.gaps[e]; ok {
+			l.skip(gap)
+			delete(l.gaps, e)
 		}
 		l.cache.Remove(e)
 		l.matchLen--
 	}
 	l.matchTail = nil
 	l.markerID++ // Invalidate outstanding markers
-	return b.String(), line, column
+	return line, column
+}
+
+// gapMap records runes discarded (without being cleared) after a cached rune.
+//
+type gapMap map[*list.Element]string
+
+// addGap records runes discarded (without being cleared) after the element e, so that they can be skipped over for
+// line/column tracking once e is cleared.
+// If e is nil, the runes are skipped over immediately.
+//
+func (l *Lexer) addGap(e *list.Element, s string) {
+	if s == "" {
+		return
+	}
+	if e == nil {
+		l.skip(s)
+		return
+	}
+	if l.gaps == nil {
+		l.gaps = make(gapMap)
+	}
+	l.gaps[e] += s
+}
+
+// skip advances the line/column over runes that were discarded without being cleared.
+//
+func (l *Lexer) skip(s string) {
+	for _, r := range s {
+		if l.line == 0 {
+			l.line = 1
+		}
+		if l.column == 0 {
+			l.column = 1
+		}
+		l.advance(r)
+	}
+}
+
+// advance moves the line/column past r, advancing the column per the column mode (see WithColumns).
+//
+func (l *Lexer) advance(r rune) {
+	newline, visible := l.lineMode.lineBreak(l.prevRune, r)
+	if newline {
+		l.line++
+		l.column = 0
+	} else if visible {
+		l.column += l.columns.width(r)
+	}
+	l.prevRune = r
 }
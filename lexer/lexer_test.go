@@ -7,6 +7,7 @@ import (
 	"testing"
 	"unicode/utf8"
 
+	"github.com/tekwizely/go-parsing/lexer/source"
 	"github.com/tekwizely/go-parsing/lexer/token"
 )
 
@@ -122,6 +123,28 @@ func TestNilFn(t *testing.T) {
 	expectNexterEOF(t, nexter)
 }
 
+// TestLexAll
+//
+func TestLexAll(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		switch r := l.Next(); r {
+		case '!':
+			l.EmitError("bang")
+		default:
+			l.EmitToken(TStart)
+		}
+		return l.nextFn
+	}
+	tokens, err := LexAll("ab", fn)
+	if err != nil || len(tokens) != 2 || tokens[0].Value() != "a" || tokens[1].Value() != "b" {
+		t.Errorf("LexAll() expecting ([a b], nil), received (%v, %v)", tokens, err)
+	}
+	tokens, err = LexAll("a!b", fn)
+	if err == nil || err.Error() != "1:3: bang" || len(tokens) != 1 {
+		t.Errorf("LexAll() expecting ([a], '1:3: bang'), received (%v, %v)", tokens, err)
+	}
+}
+
 // TestLexerFnSkippedWhenNoCanPeek
 //
 func TestLexerFnSkippedWhenNoCanPeek(t *testing.T) {
@@ -157,6 +180,29 @@ func TestEmitEmptyToken(t *testing.T) {
 	expectNexterEOF(t, nexter)
 }
 
+// TestEmitTokenOn
+//
+func TestEmitTokenOn(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		l.Next()
+		l.EmitTokenOn(TStart, token.ChannelHidden)
+		l.Next()
+		l.EmitToken(TStart)
+		return nil
+	}
+	nexter := LexString("ab", fn)
+	for _, expected := range []token.Channel{token.ChannelHidden, token.ChannelDefault} {
+		tok, err := nexter.Next()
+		if err != nil {
+			t.Fatalf("Nexter.Next() returned error: %v", err)
+		}
+		if ch := token.ChannelOf(tok); ch != expected {
+			t.Errorf("ChannelOf('%s') expecting %d, received %d", tok.Value(), expected, ch)
+		}
+	}
+	expectNexterEOF(t, nexter)
+}
+
 // TestCanPeek
 //
 func TestCanPeek(t *testing.T) {
@@ -431,6 +477,25 @@ func TestMatchReader(t *testing.T) {
 	expectNexterEOF(t, nexter)
 }
 
+// TestMatchSource
+//
+func TestMatchSource(t *testing.T) {
+	files := source.NewSet()
+	files.AddFile("a.src", "ignored")
+	file := files.AddFile("b.src", "ab cd\n ef")
+	nexter := LexSource(file, lexFields)
+	for _, expected := range []string{"b.src:1:1", "b.src:1:4", "b.src:2:2"} {
+		tok, err := nexter.Next()
+		if err != nil {
+			t.Fatalf("Next() received unexpected error '%s'", err)
+		}
+		if pos := files.Position(source.PosOf(tok)).String(); pos != expected {
+			t.Errorf("LexSource() expecting position '%s', received '%s'", expected, pos)
+		}
+	}
+	expectNexterEOF(t, nexter)
+}
+
 // TestClear1
 //
 func TestClear1(t *testing.T) {
@@ -740,6 +805,92 @@ func TestPeekTokenAfterEOF(t *testing.T) {
 	expectNexterEOF(t, nexter)
 }
 
+// expectPending confirms Pending() == (text, start, end)
+//
+func expectPending(t *testing.T, l *Lexer, text string, start string, end string) {
+	s, b, e := l.Pending()
+	if s != text || b.String() != start || e.String() != end {
+		t.Errorf("Lexer.Pending() expecting (%q, %s, %s), received (%q, %s, %s)", text, start, end, s, b, e)
+	}
+}
+
+// TestPending
+//
+func TestPending(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		expectPending(t, l, "", "1:1", "1:1")
+		expectNextString(t, l, "ab\nc")
+		expectPending(t, l, "ab\nc", "1:1", "2:2")
+		l.EmitToken(TStart)
+		expectPending(t, l, "", "2:2", "2:2")
+		l.Next()
+		l.Skip(2)
+		l.Next()
+		expectPending(t, l, "dg", "2:2", "2:6")
+		l.Skip(1)
+		expectPending(t, l, "dg", "2:2", "2:6")
+		l.EmitToken(TStart)
+		l.Next()
+		tok := l.PeekToken()
+		text, start, end := l.Pending()
+		l.EmitToken(TStart)
+		if last := l.LastEmitted(); text != tok || start != token.Start(last) || end != token.End(last) {
+			t.Errorf("Lexer.Pending() expecting (%q, %s, %s), received (%q, %s, %s)",
+				last.Value(), token.Start(last), token.End(last), text, start, end)
+		}
+		l.EmitEOF()
+		assertPanic(t, func() {
+			l.Pending()
+		}, "Lexer.Pending: No token peeks allowed after EOF is emitted")
+		return nil
+	}
+	nexter := LexString("ab\ncdefghi", fn)
+	expectNexterNext(t, nexter, TStart, "ab\nc", 1, 1)
+	expectNexterNext(t, nexter, TStart, "dg", 2, 2)
+	expectNexterNext(t, nexter, TStart, "i", 2, 7)
+	expectNexterEOF(t, nexter)
+}
+
+// expectLastEmitted
+//
+func expectLastEmitted(t *testing.T, l *Lexer, typ token.Type, value string) {
+	tok := l.LastEmitted()
+	if tok == nil {
+		t.Errorf("Lexer.LastEmitted() expecting {%d, '%s'}, received nil", typ, value)
+	} else if tok.Type() != typ || tok.Value() != value {
+		t.Errorf("Lexer.LastEmitted() expecting {%d, '%s'}, received {%d, '%s'}", typ, value, tok.Type(), tok.Value())
+	}
+}
+
+// TestLastEmitted
+//
+func TestLastEmitted(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		if tok := l.LastEmitted(); tok != nil {
+			t.Errorf("Lexer.LastEmitted() expecting nil, received {%d, '%s'}", tok.Type(), tok.Value())
+		}
+		l.Next()
+		l.EmitToken(TStart)
+		expectLastEmitted(t, l, TStart, "1")
+		l.Next()
+		l.EmitType(TStart + 1)
+		expectLastEmitted(t, l, TStart+1, "")
+		l.EmitError("oops")
+		expectLastEmitted(t, l, TLexErr, "oops")
+		l.Next()
+		l.Clear()
+		expectLastEmitted(t, l, TLexErr, "oops")
+		l.EmitEOF()
+		expectLastEmitted(t, l, TLexErr, "oops")
+		return nil
+	}
+	nexter := LexString("123", fn)
+	expectNexterNext(t, nexter, TStart, "1", 1, 1)
+	expectNexterNext(t, nexter, TStart+1, "", 1, 2)
+	expectNexterError(t, nexter, "1:3: oops")
+	expectNexterEOF(t, nexter)
+}
+
 // TestEmitErrorAfterEOF
 //
 func TestEmitErrorAfterEOF(t *testing.T) {
@@ -769,6 +920,279 @@ func TestClearAfterEOF(t *testing.T) {
 	}, "Lexer.Clear: No clears allowed after EOF is emitted")
 }
 
+// TestTryPeekNext
+//
+func TestTryPeekNext(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		if r, ok := l.TryPeek(0); ok || r != 0 {
+			t.Error("Lexer.TryPeek(0) expecting (0, false)")
+		}
+		if r, ok := l.TryPeek(2); !ok || r != '2' {
+			t.Errorf("Lexer.TryPeek(2) expecting ('2', true), received ('%c', %t)", r, ok)
+		}
+		if r, ok := l.TryPeek(3); ok || r != 0 {
+			t.Error("Lexer.TryPeek(3) expecting (0, false)")
+		}
+		if r, ok := l.TryNext(); !ok || r != '1' {
+			t.Errorf("Lexer.TryNext() expecting ('1', true), received ('%c', %t)", r, ok)
+		}
+		if r, ok := l.TryNext(); !ok || r != '2' {
+			t.Errorf("Lexer.TryNext() expecting ('2', true), received ('%c', %t)", r, ok)
+		}
+		if r, ok := l.TryNext(); ok || r != 0 {
+			t.Error("Lexer.TryNext() expecting (0, false)")
+		}
+		expectPeekToken(t, l, "12")
+		l.EmitEOF()
+		if _, ok := l.TryPeek(1); ok {
+			t.Error("Lexer.TryPeek(1) expecting false after EOF")
+		}
+		if _, ok := l.TryNext(); ok {
+			t.Error("Lexer.TryNext() expecting false after EOF")
+		}
+		return nil
+	}
+	nexter := LexString("12", fn)
+	expectNexterEOF(t, nexter)
+}
+
+// TestTruncate
+//
+func TestTruncate(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		m := l.Marker()
+		nextString(l, "12.")
+		l.Truncate(1)
+		expectPeekToken(t, l, "12")
+		expectPeek(t, l, 1, '.')
+		l.Truncate(0)
+		expectPeekToken(t, l, "12")
+		assertPanic(t, func() { l.Truncate(3) }, "Lexer.Truncate: range error")
+		assertPanic(t, func() { l.Truncate(-1) }, "Lexer.Truncate: range error")
+		l.EmitToken(TStart)
+		expectMarkerValid(t, m, false)
+		m = l.Marker()
+		nextString(l, ".x")
+		l.Truncate(2)
+		expectPeekToken(t, l, "")
+		expectMarkerValid(t, m, true)
+		nextString(l, ".x")
+		l.EmitToken(TStart)
+		return nil
+	}
+	nexter := LexString("12.x", fn)
+	expectNexterNext(t, nexter, TStart, "12", 1, 1)
+	expectNexterNext(t, nexter, TStart, ".x", 1, 3)
+	expectNexterEOF(t, nexter)
+}
+
+// TestSkip
+//
+func TestSkip(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		// Drop a BOM
+		//
+		if l.Peek(1) == '\uFEFF' {
+			l.Skip(1)
+		}
+		expectNext(t, l, '#')
+		m := l.Marker()
+		l.Skip(2)
+		expectMarkerValid(t, m, true)
+		expectPeekToken(t, l, "#")
+		expectNext(t, l, 'a')
+		m.Apply()
+		expectPeekToken(t, l, "#")
+		expectNext(t, l, 'a')
+		l.EmitToken(TStart)
+		assertPanic(t, func() { l.Skip(0) }, "Lexer.Skip: range error")
+		assertPanic(t, func() { l.Skip(5) }, "Lexer.Skip: Not enough runes available")
+		l.Skip(1)
+		expectNext(t, l, 'b')
+		l.EmitToken(TStart)
+		return nil
+	}
+	nexter := LexString("\uFEFF#!\na\nb", fn)
+	expectNexterNext(t, nexter, TStart, "#a", 1, 2)
+	expectNexterNext(t, nexter, TStart, "b", 3, 1)
+	expectNexterEOF(t, nexter)
+}
+
+// TestDiscard
+//
+func TestDiscard(t *testing.T) {
+	isSpace := func(r rune) bool { return r == ' ' || r == '\n' }
+	fn := func(l *Lexer) Fn {
+		if n := l.Discard(isSpace); n != 2 {
+			t.Errorf("Lexer.Discard() expecting 2, received %d", n)
+		}
+		expectNext(t, l, 'a')
+		if n := l.Discard(isSpace); n != 2 {
+			t.Errorf("Lexer.Discard() expecting 2, received %d", n)
+		}
+		expectNext(t, l, 'b')
+		l.EmitToken(TStart)
+		if n := l.Discard(isSpace); n != 1 {
+			t.Errorf("Lexer.Discard() expecting 1, received %d", n)
+		}
+		l.EmitEOF()
+		if n := l.Discard(isSpace); n != 0 {
+			t.Errorf("Lexer.Discard() expecting 0 after EOF, received %d", n)
+		}
+		return nil
+	}
+	nexter := LexString(" \na \nb ", fn)
+	expectNexterNext(t, nexter, TStart, "ab", 2, 1)
+	expectNexterEOF(t, nexter)
+}
+
+// TestSkipTo
+//
+func TestSkipTo(t *testing.T) {
+	var fn Fn
+	fn = func(l *Lexer) Fn {
+		if l.Peek(1) == '"' {
+			// Bad literal, skip to end of line
+			//
+			l.Next()
+			if n := l.SkipTo(l.LineMode().IsTerminator); n != 3 {
+				t.Errorf("Lexer.SkipTo() expecting 3, received %d", n)
+			}
+			l.EmitToken(TUnknown)
+			l.MatchNewline()
+			l.Clear()
+			return fn
+		}
+		l.Next()
+		l.EmitToken(TStart)
+		return fn
+	}
+	nexter := LexString("a\"b c\nd", fn)
+	expectNexterNext(t, nexter, TStart, "a", 1, 1)
+	expectNexterNext(t, nexter, TUnknown, "\"b c", 1, 2)
+	expectNexterNext(t, nexter, TStart, "d", 2, 1)
+	expectNexterEOF(t, nexter)
+}
+
+// TestSkipToAny
+//
+func TestSkipToAny(t *testing.T) {
+	var fn Fn
+	fn = func(l *Lexer) Fn {
+		if l.SkipToAny(";}") > 0 {
+			l.Clear()
+			return fn
+		}
+		l.Next()
+		l.EmitToken(TStart)
+		return fn
+	}
+	nexter := LexString("abc;d}", fn)
+	expectNexterNext(t, nexter, TStart, ";", 1, 4)
+	expectNexterNext(t, nexter, TStart, "}", 1, 6)
+	expectNexterEOF(t, nexter)
+	// Skips to the end of the input
+	//
+	nexter = LexString("abc", fn)
+	expectNexterEOF(t, nexter)
+}
+
+// TestSkipToAfterEOF
+//
+func TestSkipToAfterEOF(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		l.EmitEOF()
+		assertPanic(t, func() { l.SkipTo(func(rune) bool { return true }) }, "Lexer.SkipTo: No runes can be matched after EOF is emitted")
+		assertPanic(t, func() { l.SkipToAny(";") }, "Lexer.SkipToAny: No runes can be matched after EOF is emitted")
+		return nil
+	}
+	expectNexterEOF(t, LexString("a", fn))
+}
+
+// TestSkipLoopGuard confirms skipping counts as progress
+//
+func TestSkipLoopGuard(t *testing.T) {
+	var fn Fn
+	fn = func(l *Lexer) Fn {
+		if l.PeekToken() == "" {
+			l.Next()
+		}
+		l.Skip(1)
+		return fn
+	}
+	nexter := LexString("abcdef", fn, WithLoopGuard(2))
+	expectNexterEOF(t, nexter)
+}
+
+// matchString returns a match function for NotFollowedBy that matches the runes of s
+//
+func matchString(s string) func(*Lexer) bool {
+	return func(l *Lexer) bool {
+		for _, r := range s {
+			if !l.CanPeek(1) || l.Peek(1) != r {
+				return false
+			}
+			l.Next()
+		}
+		return true
+	}
+}
+
+// TestNotFollowedBy
+//
+func TestNotFollowedBy(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		l.Next()
+		if l.NotFollowedBy(matchString("bc")) {
+			t.Error("Lexer.NotFollowedBy(\"bc\") expecting false")
+		}
+		if !l.NotFollowedBy(matchString("bd")) {
+			t.Error("Lexer.NotFollowedBy(\"bd\") expecting true")
+		}
+		if !l.NotFollowedBy(matchString("bcd")) {
+			t.Error("Lexer.NotFollowedBy(\"bcd\") expecting true")
+		}
+		expectPeekToken(t, l, "a")
+		assertPanic(t, func() {
+			l.NotFollowedBy(func(l *Lexer) bool { l.Clear(); return true })
+		}, "Lexer.NotFollowedBy: match function must not emit or clear")
+		l.Next()
+		l.Next()
+		l.EmitToken(TStart)
+		return nil
+	}
+	nexter := LexString("abc", fn)
+	expectNexterNext(t, nexter, TStart, "bc", 1, 2)
+	expectNexterEOF(t, nexter)
+}
+
+// TestContext
+//
+func TestContext(t *testing.T) {
+	fn1 := func(l *Lexer) Fn {
+		if ctx := l.Context(); ctx != 2 {
+			t.Errorf("Lexer.Context() expecting '2', received '%v'", ctx)
+		}
+		l.EmitEOF()
+		// Context still available after EOF
+		//
+		l.SetContext(3)
+		if ctx := l.Context(); ctx != 3 {
+			t.Errorf("Lexer.Context() expecting '3', received '%v'", ctx)
+		}
+		return nil
+	}
+	fn := func(l *Lexer) Fn {
+		if ctx := l.Context(); ctx != nil {
+			t.Errorf("Lexer.Context() expecting nil, received '%v'", ctx)
+		}
+		l.SetContext(2)
+		return fn1
+	}
+	nexter := LexString(".", fn)
+	expectNexterEOF(t, nexter)
+}
+
 // TestRuneReaderNonEOFError should log an error but otherwise behave as EOF
 //
 func TestRuneReaderNonEOFError(t *testing.T) {
@@ -784,3 +1208,22 @@ func TestRuneReaderNonEOFError(t *testing.T) {
 		t.Errorf("Lexer.growPeek received wrong log message: '%s'", log)
 	}
 }
+
+// BenchmarkLex measures core lexer throughput over a stream of single-rune tokens
+//
+func BenchmarkLex(b *testing.B) {
+	input := strings.Repeat("abcdefghij", 100)
+	var fn Fn
+	fn = func(l *Lexer) Fn {
+		l.Next()
+		l.EmitToken(TStart)
+		return fn
+	}
+	b.SetBytes(int64(len(input)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if count, _ := token.Drain(LexString(input, fn)); count != len(input) {
+			b.Fatalf("Drain expecting %d tokens, received %d", len(input), count)
+		}
+	}
+}
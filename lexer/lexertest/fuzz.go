@@ -0,0 +1,146 @@
+//go:build go1.18
+// +build go1.18
+
+package lexertest
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// FuzzLoopLimit is the loop guard limit (see lexer.WithLoopGuard) used when fuzzing.
+//
+const FuzzLoopLimit = 1000
+
+// FuzzOption configures the invariants checked by FuzzLex.
+//
+type FuzzOption func(*fuzzConfig)
+
+// fuzzConfig holds the options for FuzzLex.
+//
+type fuzzConfig struct {
+	allowed  []string
+	lossless bool
+}
+
+// AllowPanic allows panics whose message contains substr, for grammars that document panics on certain inputs.
+// Can be specified multiple times.
+//
+func AllowPanic(substr string) FuzzOption {
+	return func(c *fuzzConfig) {
+		c.allowed = append(c.allowed, substr)
+	}
+}
+
+// Lossless asserts that the token values reconstruct the input, for grammars that preserve trivia (whitespace,
+// comments, etc).
+// The check is skipped for inputs that are not valid UTF-8 or that produce errors, as error tokens replace the runes
+// they cover.
+//
+func Lossless() FuzzOption {
+	return func(c *fuzzConfig) {
+		c.lossless = true
+	}
+}
+
+// FuzzLex fuzzes the lexer starting with start, asserting that, for every input:
+//
+//  - The lexer does not panic (see AllowPanic)
+//  - The lexer does not loop without consuming input or emitting tokens (see FuzzLoopLimit)
+//  - Token positions are monotonically non-decreasing
+//  - Once io.EOF is returned, further calls continue to return io.EOF
+//  - Token values reconstruct the input (only if Lossless is specified)
+//
+// Add seed inputs via f.Add() (or AddCorpus) before calling FuzzLex:
+//
+//	func FuzzLexMyLang(f *testing.F) {
+//		f.Add("let x = 1")
+//		lexertest.FuzzLex(f, lexMyLang)
+//	}
+//
+func FuzzLex(f *testing.F, start lexer.Fn, opts ...FuzzOption) {
+	c := &fuzzConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		defer func() {
+			if r := recover(); r != nil && !c.isAllowed(r) {
+				t.Fatalf("lexertest: panic on input %q: %v", input, r)
+			}
+		}()
+		CheckTokens(t, input, lexer.LexString(input, start, lexer.WithLoopGuard(FuzzLoopLimit)), c.lossless)
+	})
+}
+
+// CheckTokens drains the token stream lexed from input, confirming that positions are monotonically non-decreasing,
+// that io.EOF is sticky, and that no runaway loop was detected (see lexer.WithLoopGuard).
+// If lossless is true, also confirms that the token values reconstruct input (see Lossless).
+//
+func CheckTokens(t *testing.T, input string, tokens token.Nexter, lossless bool) {
+	t.Helper()
+	var out strings.Builder
+	var last token.Position
+	failed := false
+	for tok, err := tokens.Next(); err != io.EOF; tok, err = tokens.Next() {
+		if err != nil {
+			if _, ok := err.(*lexer.LoopError); ok {
+				t.Fatalf("lexertest: %s on input %q", err, input)
+			}
+			failed = true
+		}
+		if tok == nil {
+			continue
+		}
+		pos := token.Start(tok)
+		if pos.Line < last.Line || (pos.Line == last.Line && pos.Column < last.Column) {
+			t.Fatalf("lexertest: token %q at %s precedes previous token at %s on input %q", tok.Value(), pos, last, input)
+		}
+		last = pos
+		out.WriteString(tok.Value())
+	}
+	if _, err := tokens.Next(); err != io.EOF {
+		t.Fatalf("lexertest: expecting io.EOF after io.EOF, received %v on input %q", err, input)
+	}
+	if lossless && !failed && utf8.ValidString(input) && out.String() != input {
+		t.Fatalf("lexertest: token values %q do not reconstruct input %q", out.String(), input)
+	}
+}
+
+// isAllowed confirms if the recovered panic value matches one of the allowed panics (see AllowPanic).
+//
+func (c *fuzzConfig) isAllowed(r interface{}) bool {
+	msg := fmt.Sprint(r)
+	for _, substr := range c.allowed {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddCorpus adds the contents of each file matching the glob pattern as a seed input, i.e. "testdata/*.txt".
+// Fails the fuzz test if the pattern is malformed or a file cannot be read.
+//
+func AddCorpus(f *testing.F, pattern string) {
+	f.Helper()
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		f.Fatalf("lexertest: %s", err)
+	}
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			f.Fatalf("lexertest: %s", err)
+		}
+		f.Add(string(b))
+	}
+}
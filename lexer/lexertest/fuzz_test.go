@@ -0,0 +1,15 @@
+//go:build go1.18
+// +build go1.18
+
+package lexertest
+
+import "testing"
+
+// FuzzWords
+//
+func FuzzWords(f *testing.F) {
+	AddCorpus(f, "testdata/*.txt")
+	f.Add("")
+	f.Add("a?b")
+	FuzzLex(f, lexWords, Lossless())
+}
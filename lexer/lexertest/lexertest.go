@@ -0,0 +1,176 @@
+/*
+Package lexertest provides golden-file snapshot testing for lexers.
+
+Golden lexes an input file and compares the resulting token stream against a golden file, failing the test
+with a line diff on any mismatch:
+
+	func TestLexMyLang(t *testing.T) {
+		lexertest.Golden(t, "testdata/input.txt", "testdata/input.golden", lexMyLang)
+	}
+
+Golden files are (re-)generated by running the tests with the -update flag:
+
+	$ go test -update
+
+Tokens are serialized using the lexdump table format, one token per line, with numeric types.
+
+FuzzLex (Go 1.18+) fuzzes a lexer while asserting common invariants, making it one line to fuzz any grammar:
+
+	func FuzzLexMyLang(f *testing.F) {
+		lexertest.AddCorpus(f, "testdata/*.txt")
+		lexertest.FuzzLex(f, lexMyLang, lexertest.Lossless())
+	}
+
+NOTE: lexertest registers the -update flag, so your test package should not define its own.
+
+*/
+package lexertest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/lexdump"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// update controls whether golden files are compared against (false) or re-written (true).
+//
+var update = flag.Bool("update", false, "update golden files")
+
+// maxDiffLines limits the number of diff lines reported on a mismatch.
+//
+const maxDiffLines = 50
+
+// Update confirms if the tests were run with the -update flag.
+//
+func Update() bool {
+	return *update
+}
+
+// Golden lexes the file at inputPath, starting with startFn, and compares the serialized token stream against the
+// file at goldenPath.
+// If the tests were run with -update, the golden file is (re-)written instead.
+//
+func Golden(t *testing.T, inputPath string, goldenPath string, startFn lexer.Fn) {
+	t.Helper()
+	input, err := os.Open(inputPath)
+	if err != nil {
+		t.Fatalf("lexertest: %s", err)
+	}
+	defer input.Close()
+	CompareGolden(t, goldenPath, Serialize(lexer.LexReader(input, startFn)))
+}
+
+// Serialize drains the token stream, returning the tokens (and errors) in the lexdump table format.
+//
+func Serialize(tokens token.Nexter) []byte {
+	var buf bytes.Buffer
+	_ = lexdump.WriteTable(&buf, lexdump.Collect(tokens, nil))
+	return buf.Bytes()
+}
+
+// CompareGolden compares got against the contents of the file at goldenPath, failing the test with a line diff on
+// any mismatch.
+// If the tests were run with -update, the golden file is (re-)written with got instead.
+//
+func CompareGolden(t *testing.T, goldenPath string, got []byte) {
+	t.Helper()
+	if *update {
+		if err := ioutil.WriteFile(goldenPath, got, 0644); err != nil {
+			t.Fatalf("lexertest: %s", err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("lexertest: %s (run with -update to create it)", err)
+	}
+	if !bytes.Equal(want, got) {
+		t.Errorf("lexertest: %s does not match (run with -update to regenerate it):\n%s", goldenPath, diff(string(want), string(got)))
+	}
+}
+
+// ExpectTokens drains the token stream, comparing the tokens against expected (see token.Equal), failing the test
+// with a token diff (see token.Diff) on any mismatch, or on any error returned from the stream.
+//
+//	lexertest.ExpectTokens(t, lexer.LexString("let x", lexMyLang), []token.Token{
+//		token.New(TLet, "let", 1, 1),
+//		token.New(TIdent, "x", 1, 5),
+//	})
+//
+func ExpectTokens(t *testing.T, tokens token.Nexter, expected []token.Token, opts ...token.EqualOption) {
+	t.Helper()
+	if msg := expectTokens(tokens, expected, opts); msg != "" {
+		t.Errorf("lexertest: %s", msg)
+	}
+}
+
+// expectTokens implements ExpectTokens, returning the failure message, or "" if the tokens match.
+//
+func expectTokens(tokens token.Nexter, expected []token.Token, opts []token.EqualOption) string {
+	var actual []token.Token
+	for tok, err := tokens.Next(); err != io.EOF; tok, err = tokens.Next() {
+		if err != nil {
+			return fmt.Sprintf("unexpected error after %d tokens: %s", len(actual), err)
+		}
+		if tok != nil {
+			actual = append(actual, tok)
+		}
+	}
+	if d := token.Diff(expected, actual, opts...); d != "" {
+		return "tokens do not match (- expected, + actual):\n" + d
+	}
+	return ""
+}
+
+// diff returns a line diff of want vs got, with removed lines prefixed by '-' and added lines prefixed by '+'.
+// Unchanged lines are omitted.
+//
+func diff(want string, got string) string {
+	a := strings.Split(want, "\n")
+	b := strings.Split(got, "\n")
+	// lcs[i][j] holds the length of the longest common subsequence of a[i:] and b[j:]
+	//
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "- "+a[i])
+			i++
+		default:
+			lines = append(lines, "+ "+b[j])
+			j++
+		}
+	}
+	if len(lines) > maxDiffLines {
+		lines = append(lines[:maxDiffLines], fmt.Sprintf("... (%d more)", len(lines)-maxDiffLines))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,125 @@
+package lexertest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+const (
+	TWord = lexer.TStart + iota
+	TNumber
+	TSpace
+	TPunct
+)
+
+// lexWords emits runs of letters, digits and whitespace as TWord, TNumber and TSpace, and all other runes as TPunct
+//
+func lexWords(l *lexer.Lexer) lexer.Fn {
+	var match func(rune) bool
+	typ := TPunct
+	switch r := l.Next(); {
+	case unicode.IsLetter(r):
+		typ, match = TWord, unicode.IsLetter
+	case unicode.IsDigit(r):
+		typ, match = TNumber, unicode.IsDigit
+	case unicode.IsSpace(r):
+		typ, match = TSpace, unicode.IsSpace
+	case r == '?':
+		l.EmitErrorf("unexpected '%c'", r)
+		return lexWords
+	}
+	for match != nil && l.CanPeek(1) && match(l.Peek(1)) {
+		l.Next()
+	}
+	l.EmitToken(typ)
+	return lexWords
+}
+
+// TestGolden
+//
+func TestGolden(t *testing.T) {
+	Golden(t, "testdata/words.txt", "testdata/words.golden", lexWords)
+}
+
+// TestGoldenUpdate
+//
+func TestGoldenUpdate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lexertest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	golden := filepath.Join(dir, "words.golden")
+	saved := *update
+	*update = true
+	Golden(t, "testdata/words.txt", golden, lexWords)
+	*update = saved
+	want, _ := ioutil.ReadFile("testdata/words.golden")
+	got, _ := ioutil.ReadFile(golden)
+	if string(got) != string(want) {
+		t.Errorf("Golden update expecting:\n%s\nreceived:\n%s", want, got)
+	}
+	Golden(t, "testdata/words.txt", golden, lexWords)
+}
+
+// TestSerializeError
+//
+func TestSerializeError(t *testing.T) {
+	got := string(Serialize(lexer.LexString("a?", lexWords)))
+	want := "" +
+		"POS  TYPE   VALUE\n" +
+		"1:1  3      \"a\"\n" +
+		"     ERROR  1:3: unexpected '?'\n"
+	if got != want {
+		t.Errorf("Serialize expecting:\n%s\nreceived:\n%s", want, got)
+	}
+}
+
+// TestDiff
+//
+func TestDiff(t *testing.T) {
+	got := diff("a\nb\nc\n", "a\nc\nd\n")
+	want := "- b\n+ d"
+	if got != want {
+		t.Errorf("diff expecting:\n%s\nreceived:\n%s", want, got)
+	}
+	if got = diff("a\n", "a\n"); got != "" {
+		t.Errorf("diff expecting no differences, received:\n%s", got)
+	}
+}
+
+// TestExpectTokens
+//
+func TestExpectTokens(t *testing.T) {
+	ExpectTokens(t, lexer.LexString("hi 42", lexWords), []token.Token{
+		token.New(TWord, "hi", 1, 1),
+		token.New(TSpace, " ", 1, 3),
+		token.New(TNumber, "42", 1, 4),
+	})
+	ExpectTokens(t, lexer.LexString("hi", lexWords), []token.Token{token.New(TWord, "hi", 9, 9)}, token.IgnorePositions())
+}
+
+// TestExpectTokensMismatch
+//
+func TestExpectTokensMismatch(t *testing.T) {
+	msg := expectTokens(lexer.LexString("hi 42", lexWords), []token.Token{
+		token.New(TWord, "hi", 1, 1),
+		token.New(TNumber, "42", 1, 4),
+	}, nil)
+	want := fmt.Sprintf("tokens do not match (- expected, + actual):\n  %d \"hi\" 1:1\n+ %d \" \" 1:3\n  %d \"42\" 1:4",
+		TWord, TSpace, TNumber)
+	if msg != want {
+		t.Errorf("expectTokens() expecting:\n%s\nreceived:\n%s", want, msg)
+	}
+	msg = expectTokens(lexer.LexString("hi?", lexWords), nil, nil)
+	if want = "unexpected error after 1 tokens: 1:4: unexpected '?'"; msg != want {
+		t.Errorf("expectTokens() expecting '%s', received '%s'", want, msg)
+	}
+}
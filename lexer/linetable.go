@@ -0,0 +1,163 @@
+package lexer
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// invalidByte stands in for each byte of an invalid rune within the LineTable text, preserving byte offsets.
+//
+const invalidByte = 0xFF
+
+// LineTable records the line structure of the input as it is read by the lexer, allowing consumers to convert
+// byte offsets to/from line/column positions after lexing (i.e. for diagnostics renderers and LSP conversions).
+// Positions follow the same conventions as the lexer, including the column mode (see WithColumns).
+// Offsets are relative to the start of the input, and include invalid runes (which are otherwise skipped by the
+// lexer).
+// See WithLineTable.
+//
+type LineTable struct {
+	mode  ColumnMode
+	lmode LineMode
+	text  []byte // Input read so far, with invalid runes replaced by invalidByte
+	lines []int  // Byte offset of the start of each line, lines[0] is line 1
+	prev  rune   // Last rune added, for recognizing "\r\n"
+}
+
+// NewLineTable returns a new, empty, LineTable.
+//
+func NewLineTable() *LineTable {
+	t := &LineTable{}
+	t.reset(ColumnRunes, LineLF)
+	return t
+}
+
+// LineTable returns the line table being built for the input, as specified via WithLineTable.
+// Returns nil if no line table is being built.
+//
+func (l *Lexer) LineTable() *LineTable {
+	return l.lineTable
+}
+
+// Len returns the number of bytes recorded.
+//
+func (t *LineTable) Len() int {
+	return len(t.text)
+}
+
+// Lines returns the number of lines recorded.
+// A trailing newline starts a new (empty) line.
+//
+func (t *LineTable) Lines() int {
+	return len(t.lines)
+}
+
+// LineStart returns the byte offset of the start of the specified line.
+// Returns -1 if the line is out of range.
+//
+func (t *LineTable) LineStart(line int) int {
+	if line < 1 || line > len(t.lines) {
+		return -1
+	}
+	return t.lines[line-1]
+}
+
+// LineText returns the text of the specified line, excluding the trailing line terminator.
+// Invalid runes are returned as "\xff" bytes.
+// Returns the empty string if the line is out of range.
+//
+func (t *LineTable) LineText(line int) string {
+	start := t.LineStart(line)
+	if start < 0 {
+		return ""
+	}
+	end := len(t.text)
+	if line < len(t.lines) {
+		end = t.lines[line]
+		// Exclude the line terminator
+		//
+		_, size := utf8.DecodeLastRune(t.text[start:end])
+		end -= size
+		if t.text[end] == '\n' && end > start && t.text[end-1] == '\r' && t.lmode&LineCRLF != 0 {
+			end--
+		}
+	}
+	return string(t.text[start:end])
+}
+
+// Position returns the line/column position of the specified byte offset.
+// An offset of Len() is valid, referring to the end of the input.
+// Returns an invalid position (-1:-1) if the offset is out of range.
+//
+func (t *LineTable) Position(offset int) token.Position {
+	if offset < 0 || offset > len(t.text) {
+		return token.Position{Line: -1, Column: -1}
+	}
+	line := sort.Search(len(t.lines), func(i int) bool { return t.lines[i] > offset })
+	column := 1
+	for s := t.text[t.lines[line-1]:offset]; len(s) > 0; {
+		r, size := utf8.DecodeRune(s)
+		s = s[size:]
+		if r != utf8.RuneError {
+			column += t.mode.width(r)
+		}
+	}
+	return token.Position{Line: line, Column: column}
+}
+
+// Offset returns the byte offset of the specified line/column position.
+// Line 0, and column 0, are treated as the start of the input, and the start of the line, respectively.
+// Returns -1 if the position is out of range.
+//
+func (t *LineTable) Offset(pos token.Position) int {
+	if pos.Line == 0 {
+		pos.Line = 1
+	}
+	start := t.LineStart(pos.Line)
+	if start < 0 || pos.Column < 0 {
+		return -1
+	}
+	text := t.LineText(pos.Line)
+	offset := 0
+	for column := 1; column < pos.Column; {
+		if offset >= len(text) {
+			return -1
+		}
+		r, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+		if r != utf8.RuneError {
+			column += t.mode.width(r)
+		}
+	}
+	return start + offset
+}
+
+// reset empties the table, for a new input.
+//
+func (t *LineTable) reset(mode ColumnMode, lmode LineMode) {
+	t.mode = mode
+	t.lmode = lmode
+	t.prev = 0
+	t.text = t.text[:0]
+	t.lines = append(t.lines[:0], 0)
+}
+
+// add records a rune read from the input.
+//
+func (t *LineTable) add(r rune, size int) {
+	if r == utf8.RuneError && size == 1 {
+		t.text = append(t.text, invalidByte)
+	} else {
+		t.text = append(t.text, string(r)...)
+	}
+	newline, visible := t.lmode.lineBreak(t.prev, r)
+	switch {
+	case newline:
+		t.lines = append(t.lines, len(t.text))
+	case !visible:
+		t.lines[len(t.lines)-1] = len(t.text) // The '\n' of "\r\n" belongs to the previous line
+	}
+	t.prev = r
+}
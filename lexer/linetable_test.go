@@ -0,0 +1,83 @@
+package lexer
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// lexAll consumes the entire input as a single token.
+//
+func lexAll(l *Lexer) Fn {
+	for l.CanPeek(1) {
+		l.Next()
+	}
+	l.EmitToken(TStart)
+	return nil
+}
+
+// TestWithLineTable
+//
+func TestWithLineTable(t *testing.T) {
+	lines := NewLineTable()
+	fn := func(l *Lexer) Fn {
+		if l.LineTable() != lines {
+			t.Error("Lexer.LineTable() expecting table from WithLineTable")
+		}
+		return lexAll(l)
+	}
+	nexter := LexString("ab\n字x\n\xffz", fn, WithLineTable(lines))
+	expectNexterNext(t, nexter, TStart, "ab\n字x\nz", 1, 1)
+	expectNexterEOF(t, nexter)
+	if n := lines.Len(); n != 10 {
+		t.Errorf("LineTable.Len() expecting 10, received %d", n)
+	}
+	if n := lines.Lines(); n != 3 {
+		t.Errorf("LineTable.Lines() expecting 3, received %d", n)
+	}
+	for line, match := range map[int]int{0: -1, 1: 0, 2: 3, 3: 8, 4: -1} {
+		if start := lines.LineStart(line); start != match {
+			t.Errorf("LineTable.LineStart(%d) expecting %d, received %d", line, match, start)
+		}
+	}
+	for line, match := range map[int]string{0: "", 1: "ab", 2: "字x", 3: "\xffz", 4: ""} {
+		if text := lines.LineText(line); text != match {
+			t.Errorf("LineTable.LineText(%d) expecting '%q', received '%q'", line, match, text)
+		}
+	}
+	for offset, match := range map[int]token.Position{-1: {Line: -1, Column: -1}, 0: {Line: 1, Column: 1}, 2: {Line: 1, Column: 3}, 3: {Line: 2, Column: 1}, 6: {Line: 2, Column: 2}, 9: {Line: 3, Column: 1}, 10: {Line: 3, Column: 2}, 11: {Line: -1, Column: -1}} {
+		if pos := lines.Position(offset); pos != match {
+			t.Errorf("LineTable.Position(%d) expecting %v, received %v", offset, match, pos)
+		}
+	}
+	for pos, match := range map[token.Position]int{{Line: 0, Column: 0}: 0, {Line: 1, Column: 3}: 2, {Line: 2, Column: 2}: 6, {Line: 2, Column: 3}: 7, {Line: 2, Column: 4}: -1, {Line: 3, Column: 2}: 10, {Line: 4, Column: 1}: -1} {
+		if offset := lines.Offset(pos); offset != match {
+			t.Errorf("LineTable.Offset(%v) expecting %d, received %d", pos, match, offset)
+		}
+	}
+}
+
+// TestLineTableColumns
+//
+func TestLineTableColumns(t *testing.T) {
+	lines := NewLineTable()
+	expectNexterNext(t, LexString("字x", lexAll, WithLineTable(lines), WithColumns(ColumnWidth)), TStart, "字x", 1, 1)
+	if pos := lines.Position(3); pos != (token.Position{Line: 1, Column: 3}) {
+		t.Errorf("LineTable.Position(3) expecting 1:3, received %v", pos)
+	}
+	if offset := lines.Offset(token.Position{Line: 1, Column: 3}); offset != 3 {
+		t.Errorf("LineTable.Offset(1:3) expecting 3, received %d", offset)
+	}
+}
+
+// TestLineTableNone
+//
+func TestLineTableNone(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		if l.LineTable() != nil {
+			t.Error("Lexer.LineTable() expecting nil")
+		}
+		return nil
+	}
+	expectNexterEOF(t, LexString("a", fn))
+}
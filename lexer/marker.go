@@ -1,6 +1,9 @@
 package lexer
 
-import "container/list"
+import (
+	"container/list"
+	"strings"
+)
 
 // Marker snapshots the state of the lexer to allow rewinding.
 //
@@ -9,6 +12,7 @@ import "container/list"
 //  - Lexer.Marker()
 //  - Marker.Valid()
 //  - marker.Apply()
+//  - Marker.ClearTo()
 //
 type Marker struct {
 	lexer     *Lexer
@@ -24,6 +28,7 @@ type Marker struct {
 // Use Marker.Apply() to reset the lexer state to the marker position.
 //
 func (l *Lexer) Marker() *Marker {
+	l.traceEvent(TraceMarker, 0, 0, "")
 	return &Marker{lexer: l, markerID: l.markerID, matchTail: l.matchTail, matchLen: l.matchLen, nextFn: l.nextFn}
 }
 
@@ -41,13 +46,61 @@ func (m *Marker) Valid() bool {
 // Use `return marker.Apply()` to tell the lexer to forward to the marked function.
 // Use Valid() to verify that a marker is still valid before using it.
 // It is safe to apply a marker multiple times, as long as it passes Valid().
+// Outstanding token readers are invalidated (see Lexer.TokenReader).
 // Panics if marker fails Valid() check.
 //
 func (m *Marker) Apply() Fn {
 	if !m.Valid() {
-		panic("Invalid marker")
+		m.lexer.raise("Invalid marker")
 	}
 	m.lexer.matchTail = m.matchTail
 	m.lexer.matchLen = m.matchLen
+	m.lexer.matchID++ // Invalidate outstanding token readers
+	m.lexer.traceEvent(TraceApply, 0, 0, "")
+	if m.lexer.stats != nil {
+		m.lexer.stats.MarkerApplies++
+	}
 	return m.nextFn
 }
+
+// ClearTo discards the runes matched since the marker was created, keeping any runes matched before it.
+// Unlike Apply, the discarded runes are not returned to the peek buffer, allowing you to commit part of a match and
+// retry the rest.
+// The next function is not changed.
+// All outstanding markers are invalidated after this call.
+// Panics if marker fails Valid() check.
+// Panics if runes matched before the marker have since been un-matched (via Apply of an earlier marker).
+//
+func (m *Marker) ClearTo() {
+	if !m.Valid() {
+		m.lexer.raise("Invalid marker")
+	}
+	l := m.lexer
+	if l.matchLen < m.matchLen {
+		l.raise("Marker.ClearTo: marker is ahead of the matched runes")
+	}
+	l.traceEvent(TraceClear, 0, 0, "")
+	// Remove the runes matched since the marker, along with any gaps that follow them
+	//
+	b := &strings.Builder{}
+	for n := l.matchLen - m.matchLen; n > 0; n-- {
+		e := m.head()
+		b.WriteRune(e.Value.(rune))
+		b.WriteString(l.gaps[e])
+		delete(l.gaps, e)
+		l.cache.Remove(e)
+	}
+	l.matchTail = m.matchTail
+	l.matchLen = m.matchLen
+	l.addGap(m.matchTail, b.String())
+	l.markerID++ // Invalidate outstanding markers
+}
+
+// head returns the first element following the marker position.
+//
+func (m *Marker) head() *list.Element {
+	if m.matchLen > 0 {
+		return m.matchTail.Next()
+	}
+	return m.lexer.cache.Front()
+}
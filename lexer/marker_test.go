@@ -126,3 +126,71 @@ func TestMarkerApplyNextFn(t *testing.T) {
 	nexter := LexString(".", fn2)
 	expectNexterEOF(t, nexter)
 }
+
+// nextString matches the runes of s without checking the matched token
+//
+func nextString(l *Lexer, s string) {
+	for range s {
+		l.Next()
+	}
+}
+
+// TestMarkerClearTo
+//
+func TestMarkerClearTo(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		expectNextString(t, l, "12")
+		m := l.Marker()
+		nextString(l, "3\nA")
+		m.ClearTo()
+		expectMarkerValid(t, m, false)
+		expectPeekToken(t, l, "12")
+		// Commit "12", retry the rest
+		//
+		nextString(l, "B")
+		m = l.Marker()
+		nextString(l, "C")
+		m.ClearTo()
+		expectPeekToken(t, l, "12B")
+		l.EmitToken(TString)
+		expectMatchEmitString(t, l, "D", TString)
+		return nil
+	}
+	nexter := LexString("123\nABCD", fn)
+	expectNexterNext(t, nexter, TString, "12B", 1, 1)
+	expectNexterNext(t, nexter, TString, "D", 2, 4)
+	expectNexterEOF(t, nexter)
+}
+
+// TestMarkerClearToStart
+//
+func TestMarkerClearToStart(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		m := l.Marker()
+		expectNextString(t, l, "12\n")
+		m.ClearTo()
+		expectPeekToken(t, l, "")
+		expectMatchEmitString(t, l, "AB", TString)
+		return nil
+	}
+	nexter := LexString("12\nAB", fn)
+	expectNexterNext(t, nexter, TString, "AB", 2, 1)
+	expectNexterEOF(t, nexter)
+}
+
+// TestMarkerClearToPanics
+//
+func TestMarkerClearToPanics(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		m1 := l.Marker()
+		expectNextString(t, l, "1")
+		m2 := l.Marker()
+		m1.Apply()
+		assertPanic(t, func() { m2.ClearTo() }, "Marker.ClearTo: marker is ahead of the matched runes")
+		l.Clear()
+		assertPanic(t, func() { m1.ClearTo() }, "Invalid marker")
+		return nil
+	}
+	nexter := LexString("12", fn)
+	expectNexterEOF(t, nexter)
+}
@@ -0,0 +1,69 @@
+package lexer
+
+// LineMode determines which runes the lexer recognizes as line terminators, when tracking line numbers.
+// Modes can be combined, i.e. LineCRLF | LineUnicode.
+// See WithLineTerminators.
+//
+type LineMode int
+
+const (
+	// LineLF recognizes '\n' as a line terminator (the default, always enabled).
+	//
+	LineLF LineMode = 0
+	// LineCRLF also recognizes '\r' as a line terminator, treating "\r\n" as a single line terminator.
+	//
+	LineCRLF LineMode = 1
+	// LineUnicode also recognizes NEL (U+0085), LINE SEPARATOR (U+2028) and PARAGRAPH SEPARATOR (U+2029) as line
+	// terminators.
+	//
+	LineUnicode LineMode = 2
+)
+
+// IsTerminator confirms if r is a line terminator, per the line mode.
+// NOTE: With LineCRLF, both '\r' and '\n' are terminators, but a '\n' following a '\r' does not start a new line.
+//
+func (m LineMode) IsTerminator(r rune) bool {
+	switch r {
+	case '\n':
+		return true
+	case '\r':
+		return m&LineCRLF != 0
+	case '\u0085', '\u2028', '\u2029':
+		return m&LineUnicode != 0
+	}
+	return false
+}
+
+// lineBreak confirms if r, following prev, starts a new line, and if not, whether r occupies a column.
+// The '\n' of a "\r\n" pair neither starts a new line nor occupies a column (see LineCRLF).
+//
+func (m LineMode) lineBreak(prev rune, r rune) (newline bool, visible bool) {
+	if r == '\n' && prev == '\r' && m&LineCRLF != 0 {
+		return false, false
+	}
+	if m.IsTerminator(r) {
+		return true, false
+	}
+	return false, true
+}
+
+// LineMode returns the line mode of the lexer, as specified via WithLineTerminators.
+//
+func (l *Lexer) LineMode() LineMode {
+	return l.lineMode
+}
+
+// MatchNewline matches the next line terminator, per the line mode of the lexer (see WithLineTerminators),
+// returning true if matched.
+// With LineCRLF, "\r\n" is matched as a single line terminator.
+// Returns false if no runes remain, or if the next rune is not a line terminator.
+//
+func (l *Lexer) MatchNewline() bool {
+	if !l.CanPeek(1) || !l.lineMode.IsTerminator(l.Peek(1)) {
+		return false
+	}
+	if l.Next() == '\r' && l.CanPeek(1) && l.Peek(1) == '\n' {
+		l.Next()
+	}
+	return true
+}
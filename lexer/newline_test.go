@@ -0,0 +1,106 @@
+package lexer
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// lexLetters emits each letter as a token, clearing all other runes
+//
+func lexLetters(l *Lexer) Fn {
+	if r := l.Next(); r >= 'a' && r <= 'z' {
+		l.EmitToken(TStart)
+	} else {
+		l.Clear()
+	}
+	return lexLetters
+}
+
+// TestWithLineTerminators
+//
+func TestWithLineTerminators(t *testing.T) {
+	input := "a\r\nb\rc\u2028d\u0085e\nf"
+	tests := []struct {
+		mode      LineMode
+		positions [][2]int
+	}{
+		{LineLF, [][2]int{{1, 1}, {2, 1}, {2, 3}, {2, 5}, {2, 7}, {3, 1}}},
+		{LineCRLF, [][2]int{{1, 1}, {2, 1}, {3, 1}, {3, 3}, {3, 5}, {4, 1}}},
+		{LineUnicode, [][2]int{{1, 1}, {2, 1}, {2, 3}, {3, 1}, {4, 1}, {5, 1}}},
+		{LineCRLF | LineUnicode, [][2]int{{1, 1}, {2, 1}, {3, 1}, {4, 1}, {5, 1}, {6, 1}}},
+	}
+	for _, test := range tests {
+		nexter := LexString(input, lexLetters, WithLineTerminators(test.mode))
+		for i, pos := range test.positions {
+			expectNexterNext(t, nexter, TStart, string(rune('a'+i)), pos[0], pos[1])
+		}
+		expectNexterEOF(t, nexter)
+	}
+}
+
+// TestWithLineTerminatorsEnd
+//
+func TestWithLineTerminatorsEnd(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		for l.CanPeek(1) {
+			l.Next()
+		}
+		l.EmitToken(TStart)
+		return nil
+	}
+	tests := []struct {
+		mode LineMode
+		end  string
+	}{
+		{LineLF, "2:6"},
+		{LineCRLF, "3:4"},
+		{LineUnicode, "3:2"},
+	}
+	for _, test := range tests {
+		tok, err := LexString("a\r\nb\rc\u2028d", fn, WithLineTerminators(test.mode)).Next()
+		if err != nil {
+			t.Fatalf("Nexter.Next() expecting token, received error '%s'", err)
+		}
+		if s := token.End(tok).String(); s != test.end {
+			t.Errorf("token.End() in mode %d expecting '%s', received '%s'", test.mode, test.end, s)
+		}
+	}
+}
+
+// TestMatchNewline
+//
+func TestMatchNewline(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		for l.CanPeek(1) {
+			if l.MatchNewline() {
+				l.EmitToken(TStart)
+			} else {
+				l.Next()
+				l.Clear()
+			}
+		}
+		return nil
+	}
+	nexter := LexString("\r\n\rx\n\u2028", fn, WithLineTerminators(LineCRLF))
+	expectNexterNext(t, nexter, TStart, "\r\n", 1, 1)
+	expectNexterNext(t, nexter, TStart, "\r", 2, 1)
+	expectNexterNext(t, nexter, TStart, "\n", 3, 2)
+	expectNexterEOF(t, nexter)
+}
+
+// TestLineTableTerminators
+//
+func TestLineTableTerminators(t *testing.T) {
+	lines := NewLineTable()
+	nexter := LexString("ab\r\nc\rd\u2028e", lexAll, WithLineTable(lines), WithLineTerminators(LineCRLF|LineUnicode))
+	expectNexterNext(t, nexter, TStart, "ab\r\nc\rd\u2028e", 1, 1)
+	for line, match := range map[int]string{1: "ab", 2: "c", 3: "d", 4: "e"} {
+		if text := lines.LineText(line); text != match {
+			t.Errorf("LineTable.LineText(%d) expecting %q, received %q", line, match, text)
+		}
+	}
+	if pos := lines.Position(5); pos != (token.Position{Line: 2, Column: 2}) {
+		t.Errorf("LineTable.Position(5) expecting 2:2, received %v", pos)
+	}
+}
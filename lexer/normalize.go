@@ -0,0 +1,66 @@
+package lexer
+
+import (
+	"bufio"
+	"io"
+	"unicode/utf8"
+)
+
+// Normalizer applies Unicode normalization to the lexer input, so that identifiers composed with combining
+// characters match their precomposed forms.
+// The forms in golang.org/x/text/unicode/norm (i.e. norm.NFC, norm.NFD) implement Normalizer.
+// See WithNormalization.
+//
+type Normalizer interface {
+
+	// Reader returns a reader that normalizes the runes read from r.
+	//
+	Reader(r io.Reader) io.Reader
+}
+
+// Normalization returns the name of the normalization form applied to the input (i.e. "NFC"), as specified via
+// WithNormalization.
+// Returns the empty string if the input is not normalized.
+//
+func (l *Lexer) Normalization() string {
+	return l.normForm
+}
+
+// normalize wraps the lexer input with the normalizer (see WithNormalization).
+//
+func (l *Lexer) normalize() {
+	r, ok := l.input.(io.Reader)
+	if !ok {
+		r = &runeBytesReader{input: l.input}
+	}
+	l.input = bufio.NewReader(l.norm.Reader(r))
+}
+
+// runeBytesReader exposes an io.RuneReader as an io.Reader, for inputs that do not already implement io.Reader.
+//
+type runeBytesReader struct {
+	input   io.RuneReader
+	pending []byte // Bytes of the last rune read that did not fit into the caller's buffer
+	buf     [utf8.UTFMax]byte
+}
+
+// Read implements io.Reader.Read().
+//
+func (r *runeBytesReader) Read(p []byte) (int, error) {
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	for n < len(p) {
+		c, _, err := r.input.ReadRune()
+		if err != nil {
+			if n > 0 && err == io.EOF {
+				return n, nil
+			}
+			return n, err
+		}
+		size := utf8.EncodeRune(r.buf[:], c)
+		copied := copy(p[n:], r.buf[:size])
+		r.pending = r.buf[copied:size]
+		n += copied
+	}
+	return n, nil
+}
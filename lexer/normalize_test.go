@@ -0,0 +1,93 @@
+package lexer
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// composeE is a Normalizer that composes 'e' + U+0301 into U+00E9, standing in for norm.NFC.
+//
+type composeE struct{}
+
+// Reader implements Normalizer.Reader().
+//
+func (composeE) Reader(r io.Reader) io.Reader {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		panic(err)
+	}
+	return strings.NewReader(strings.Replace(string(b), "e\u0301", "\u00e9", -1))
+}
+
+// runesOnly hides all methods of the wrapped reader other than ReadRune.
+//
+type runesOnly struct {
+	r io.RuneReader
+}
+
+// ReadRune implements io.RuneReader.ReadRune().
+//
+func (r runesOnly) ReadRune() (rune, int, error) {
+	return r.r.ReadRune()
+}
+
+// TestWithNormalization
+//
+func TestWithNormalization(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		if form := l.Normalization(); form != "NFC" {
+			t.Errorf("Lexer.Normalization() expecting 'NFC', received '%s'", form)
+		}
+		expectNextString(t, l, "caf\u00e9 caf\u00e9")
+		l.EmitToken(TStart)
+		return nil
+	}
+	input := "cafe\u0301 caf\u00e9"
+	nexter := LexString(input, fn, WithNormalization("NFC", composeE{}))
+	expectNexterNext(t, nexter, TStart, "caf\u00e9 caf\u00e9", 1, 1)
+	expectNexterEOF(t, nexter)
+	// Inputs that are not an io.Reader
+	//
+	nexter = LexRuneReader(runesOnly{strings.NewReader(input)}, fn, WithNormalization("NFC", composeE{}))
+	expectNexterNext(t, nexter, TStart, "caf\u00e9 caf\u00e9", 1, 1)
+	expectNexterEOF(t, nexter)
+}
+
+// TestNormalizationNone
+//
+func TestNormalizationNone(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		if form := l.Normalization(); form != "" {
+			t.Errorf("Lexer.Normalization() expecting '', received '%s'", form)
+		}
+		expectNextString(t, l, "e\u0301")
+		return nil
+	}
+	expectNexterEOF(t, LexString("e\u0301", fn))
+}
+
+// TestRuneBytesReader
+//
+func TestRuneBytesReader(t *testing.T) {
+	input := "a\u00e9\u5b57\U0001F600"
+	r := &runeBytesReader{input: strings.NewReader(input)}
+	// Read one byte at a time, forcing multi-byte runes to be split across reads
+	//
+	var b []byte
+	p := make([]byte, 1)
+	for {
+		n, err := r.Read(p)
+		b = append(b, p[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("runeBytesReader.Read() received unexpected error '%v'", err)
+		}
+	}
+	if string(b) != input {
+		t.Errorf("runeBytesReader expecting '%s', received '%s'", input, string(b))
+	}
+}
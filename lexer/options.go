@@ -0,0 +1,297 @@
+package lexer
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/tekwizely/go-parsing/lexer/diag"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Option configures a lexer at creation time.
+// Options are passed to the Lex* functions and are applied in order.
+//
+type Option func(*Lexer)
+
+// WithContext sets the initial user context value of the lexer.
+// See Lexer.Context() for details.
+//
+func WithContext(ctx interface{}) Option {
+	return func(l *Lexer) {
+		l.context = ctx
+	}
+}
+
+// WithLenient enables lenient mode, converting post-EOF usage panics into no-ops that return zero values.
+// The first such usage is recorded and can be retrieved via Lexer.MisuseError().
+// The same error is returned once by the token.Nexter, just before io.EOF, so callers learn the results are degraded.
+// This allows long-running services that run user-supplied lexer functions to survive a misplaced call after EOF.
+// Other usage errors (range errors, invalid markers, etc.) still panic.
+//
+func WithLenient() Option {
+	return func(l *Lexer) {
+		l.lenient = true
+	}
+}
+
+// WithTrace registers a function to receive trace events for lexer activity, including lexer function enter/exit,
+// Next/Peek, emits, marker create/apply, and Clear.
+// Intended for debugging lexer functions, i.e. "why did my lexer loop here".
+// See TraceEvent for details.
+//
+func WithTrace(fn func(event TraceEvent)) Option {
+	return func(l *Lexer) {
+		l.trace = fn
+	}
+}
+
+// WithRecorder records the sequence of lexer function transitions into the specified recorder.
+// See Recorder for details.
+//
+func WithRecorder(r *Recorder) Option {
+	return func(l *Lexer) {
+		l.recorder = r
+	}
+}
+
+// WithLoopGuard enables a watchdog that detects lexer functions returning repeatedly without consuming input or
+// emitting tokens (the classic infinite-loop bug in hand-written lexers).
+// After limit consecutive calls without progress, an error is emitted naming the function and position, and lexing is
+// terminated.
+// The error is returned by the token.Nexter as a *LoopError.
+// A limit <= 0 disables the guard.
+//
+func WithLoopGuard(limit int) Option {
+	return func(l *Lexer) {
+		l.loopLimit = limit
+	}
+}
+
+// WithStats directs the lexer to record its counters into the specified Stats, allowing them to be retrieved after
+// lexing completes (or published, i.e. via expvar.Publish).
+// See Stats for details.
+//
+func WithStats(s *Stats) Option {
+	return func(l *Lexer) {
+		if s.TokensByType == nil {
+			s.TokensByType = make(map[token.Type]int64)
+		}
+		l.stats = s
+	}
+}
+
+// WithProgress registers a function to receive progress reports as the input is read, allowing tools that lex large
+// inputs to render progress bars.
+// The function is called after every 64KiB of input read, and once more when the end of the input is reached.
+// bytesRead and runesRead are cumulative, and include invalid runes (which are otherwise skipped by the lexer).
+//
+func WithProgress(fn func(bytesRead, runesRead int64)) Option {
+	return func(l *Lexer) {
+		l.reporter = fn
+	}
+}
+
+// WithOutputBuffer enables output batching, sizing the output buffer to hold at least n tokens.
+// Emitted tokens are delivered in batches: The lexer continues calling lexer functions until at least n tokens are
+// pending, EOF is emitted, or Lexer.Flush() is called.
+// Lexer functions that emit many tokens in a single call (i.e. splitting a matched block) can then do so without
+// growing the buffer.
+// An n <= 1 disables batching, delivering tokens as soon as the lexer function that emitted them returns.
+//
+func WithOutputBuffer(n int) Option {
+	return func(l *Lexer) {
+		if n < 1 {
+			n = 1
+		}
+		l.batch = n
+		l.output = newOutputRing(n)
+	}
+}
+
+// WithUnknown enables the unknown-rune policy, preventing lexers from stalling on input that the start function does
+// not recognize.
+// When the start function returns itself without consuming input or emitting tokens, the lexer consumes one rune and
+// emits it as a TUnknown token.
+// Start functions that dispatch to other functions (or return nil) without consuming input are not affected.
+// If fallback is not nil, it is called instead, and the function it returns is called next.
+//
+func WithUnknown(fallback Fn) Option {
+	return func(l *Lexer) {
+		l.unknown = true
+		l.unknownFn = fallback
+	}
+}
+
+// WithColumns sets how the lexer advances the column number of emitted tokens: by rune (the default), by byte, or
+// by display width, allowing diagnostics to align with different editor conventions.
+// See ColumnMode for details.
+//
+func WithColumns(mode ColumnMode) Option {
+	return func(l *Lexer) {
+		l.columns = mode
+	}
+}
+
+// WithLineTerminators sets which runes the lexer recognizes as line terminators when tracking line numbers, i.e.
+// treating "\r\n" as a single line break (see LineCRLF), so that line numbers agree with other tools processing the
+// same files.
+// The mode also applies to line tables (see WithLineTable) and MatchNewline.
+// See LineMode for details.
+//
+func WithLineTerminators(mode LineMode) Option {
+	return func(l *Lexer) {
+		l.lineMode = mode
+	}
+}
+
+// WithNormalization applies Unicode normalization to the input before lexing, so that identifiers composed with
+// combining characters match their precomposed forms.
+// form names the normalization form (i.e. "NFC") and is reported via Lexer.Normalization().
+// See Normalizer for details.
+//
+func WithNormalization(form string, n Normalizer) Option {
+	return func(l *Lexer) {
+		l.normForm = form
+		l.norm = n
+	}
+}
+
+// WithLineTable records the line offsets of the input into t as it is read, allowing consumers to convert byte
+// offsets to/from line/column positions after lexing.
+// The table is emptied when lexing starts (including on Reset), and uses the column mode of the lexer.
+// See LineTable for details.
+//
+func WithLineTable(t *LineTable) Option {
+	return func(l *Lexer) {
+		l.lineTable = t
+	}
+}
+
+// WithDiagnostics records warnings (see EmitWarning) and errors (see EmitError) into the specified collector.
+// A single collector can be shared with the parser (see parser.WithDiagnostics).
+//
+func WithDiagnostics(c *diag.Collector) Option {
+	return func(l *Lexer) {
+		l.diags = c
+	}
+}
+
+// WithMaxErrors stops lexing once n errors have been emitted (see EmitError), protecting consumers from an avalanche
+// of errors on garbage input.
+// The nth error is followed by a final "too many errors" error, and EOF is emitted once the current lexer function
+// returns; Any further errors emitted by the function are discarded.
+// An n <= 0 disables the limit (the default).
+//
+func WithMaxErrors(n int) Option {
+	return func(l *Lexer) {
+		l.maxErrors = n
+	}
+}
+
+// WithReadTimeout limits how long the lexer waits for input, for interactive and network sources.
+// If the input blocks for longer than d while waiting for the start of the next token (i.e. the peek buffer is empty
+// between lexer function calls), the token.Nexter returns ErrReadTimeout; Calling Next() again resumes waiting, and no
+// input is lost.
+// Reads within a lexer function are not limited, as lexer functions cannot be interrupted.
+// A d <= 0 disables the timeout (the default).
+//
+func WithReadTimeout(d time.Duration) Option {
+	return func(l *Lexer) {
+		l.timeout = d
+	}
+}
+
+// WithProfiler records per-function cumulative time and per-type token counts into the specified profiler.
+// See Profiler for details.
+//
+func WithProfiler(p *Profiler) Option {
+	return func(l *Lexer) {
+		l.profiler = p
+	}
+}
+
+// WithArena allocates emitted tokens from the specified arena, which can be released all at once via the Release
+// method of the token.Nexter, once done with the tokens.
+// See Arena for details.
+//
+func WithArena(a *Arena) Option {
+	return func(l *Lexer) {
+		l.arena = a
+	}
+}
+
+// WithLazyValues defers building token value strings until Token.Value() is first called, so parsers that only
+// inspect Token.Type() for most tokens (i.e. punctuation, keywords) skip the string allocation entirely.
+// The matched text of each token is instead appended to a shared buffer; A retained token keeps its portion of the
+// buffer (up to 4KB) alive until its value is built.
+// Values are always built when tracing (see WithTrace).
+// Tokens with lazy values are not safe for concurrent use until Value() has been called.
+//
+func WithLazyValues() Option {
+	return func(l *Lexer) {
+		l.lazy = newValueBuffer()
+	}
+}
+
+// WithReadSize sets the size of the chunks read from io.Reader inputs (see LexReader), in bytes.
+// Larger chunks improve throughput on large files, at the cost of memory.
+// Sizes smaller than utf8.UTFMax are treated as utf8.UTFMax.
+// The default chunk size is 64KB.
+// Has no effect on io.RuneReader inputs.
+//
+func WithReadSize(n int) Option {
+	return func(l *Lexer) {
+		if n < utf8.UTFMax {
+			n = utf8.UTFMax
+		}
+		l.readSize = n
+	}
+}
+
+// WithPrescan adds hooks that run before the first lexer function is called, consuming header constructs at the start
+// of the input (i.e. shebang lines, encoding declarations and pragma comments) and recording them as headers,
+// accessible via Lexer.Headers and Lexer.Header.
+// The hooks are tried in order, repeatedly, until none of them match; Consumed runes are never seen by the lexer
+// functions.
+// See Prescan, and the Shebang, Directive and Encoding hooks.
+//
+func WithPrescan(hooks ...Prescan) Option {
+	return func(l *Lexer) {
+		l.prescans = append(l.prescans, hooks...)
+	}
+}
+
+// WithInteractive enables interactive mode, for REPLs reading the input a line at a time.
+// Errors emitted once the input is exhausted (i.e. an unterminated string) are reported as incomplete (see
+// Error.Incomplete and token.IsIncomplete), allowing REPLs to prompt for continuation lines, instead of reporting an
+// error.
+// The input counts as exhausted only once the lexer has tried to read past its end (i.e. CanPeek returned false);
+// Emitting an error never reads ahead, so never blocks waiting on further input.
+//
+func WithInteractive() Option {
+	return func(l *Lexer) {
+		l.repl = true
+	}
+}
+
+// WithMaxTokens stops lexing once more than n tokens have been emitted (excluding errors and EOF), a guardrail when
+// lexing untrusted input with pathological token densities.
+// Tokens beyond the limit are discarded, and once the current lexer function returns, a "too many tokens" error is
+// emitted (and recorded by the diagnostics collector, if any), followed by EOF.
+// An n <= 0 disables the limit (the default).
+//
+func WithMaxTokens(n int) Option {
+	return func(l *Lexer) {
+		l.maxTokens = n
+	}
+}
+
+// WithPanicContext suffixes the messages of usage panics (i.e. range errors, post-EOF usage, invalid markers) with the
+// current lexer function and the position of the current match, making misuse during development instantly
+// locatable, i.e. "Lexer.Peek: range error (in main.lexNumber at 3:14)".
+//
+func WithPanicContext() Option {
+	return func(l *Lexer) {
+		l.panicCtx = true
+	}
+}
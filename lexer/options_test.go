@@ -0,0 +1,167 @@
+package lexer
+
+import (
+	"io"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestWithContext
+//
+func TestWithContext(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		if ctx := l.Context(); ctx != "CTX" {
+			t.Errorf("Lexer.Context() expecting 'CTX', received '%v'", ctx)
+		}
+		return nil
+	}
+	nexter := LexString(".", fn, WithContext("CTX"))
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithLenient
+//
+func TestWithLenient(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		if l.MisuseError() != nil {
+			t.Error("Lexer.MisuseError() expecting nil before EOF")
+		}
+		l.EmitEOF()
+		if r := l.Peek(1); r != 0 {
+			t.Errorf("Lexer.Peek(1) expecting 0, received '%c'", r)
+		}
+		if r := l.Next(); r != 0 {
+			t.Errorf("Lexer.Next() expecting 0, received '%c'", r)
+		}
+		if s := l.PeekToken(); s != "" {
+			t.Errorf("Lexer.PeekToken() expecting '', received '%s'", s)
+		}
+		l.EmitToken(TStart)
+		l.EmitType(TStart)
+		l.EmitError("error")
+		l.EmitEOF()
+		l.Clear()
+		err := l.MisuseError()
+		if err == nil || err.Error() != "Lexer.Peek: No runes can be peeked after EOF is emitted" {
+			t.Errorf("Lexer.MisuseError() expecting first misuse, received '%v'", err)
+		}
+		return nil
+	}
+	nexter := LexString(".", fn, WithLenient())
+	// Misuse is reported from the nexter, once, before EOF
+	//
+	expectNexterError(t, nexter, "Lexer.Peek: No runes can be peeked after EOF is emitted")
+	expectNexterEOF(t, nexter)
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithLenientRangeError
+//
+func TestWithLenientRangeError(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		assertPanic(t, func() {
+			l.Peek(0)
+		}, "Lexer.Peek: range error")
+		return nil
+	}
+	nexter := LexString(".", fn, WithLenient())
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithInteractive
+//
+func TestWithInteractive(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		l.Next()
+		for l.CanPeek(1) && l.Peek(1) != '"' {
+			l.Next()
+		}
+		if !l.CanPeek(1) {
+			l.EmitError("unterminated string")
+			return nil
+		}
+		l.Next()
+		l.EmitError("unexpected string")
+		return nil
+	}
+	tests := []struct {
+		input      string
+		opts       []Option
+		incomplete bool
+	}{
+		{`"abc`, []Option{WithInteractive()}, true},
+		{`"abc`, nil, false},
+		{`"abc" x`, []Option{WithInteractive()}, false},
+	}
+	for _, test := range tests {
+		_, err := LexString(test.input, fn, test.opts...).Next()
+		if err == nil {
+			t.Fatalf("Nexter.Next() expecting error for '%s'", test.input)
+		}
+		if token.IsIncomplete(err) != test.incomplete {
+			t.Errorf("token.IsIncomplete('%s') expecting %v", test.input, test.incomplete)
+		}
+	}
+}
+
+// countingReader returns the runes, followed by io.EOF, counting the reads past the end
+//
+type countingReader struct {
+	runes []rune
+	after int
+}
+
+// ReadRune implements io.RuneReader.ReadRune().
+//
+func (r *countingReader) ReadRune() (rune, int, error) {
+	if len(r.runes) == 0 {
+		r.after++
+		return 0, 0, io.EOF
+	}
+	c := r.runes[0]
+	r.runes = r.runes[1:]
+	return c, 1, nil
+}
+
+// TestWithInteractiveNoPeek confirms emitting an error does not read ahead of the lexer
+//
+func TestWithInteractiveNoPeek(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		l.Next()
+		l.EmitErrorToken("unexpected character")
+		return nil
+	}
+	input := &countingReader{runes: []rune("x")}
+	_, err := LexRuneReader(input, fn, WithInteractive()).Next()
+	if err == nil || token.IsIncomplete(err) {
+		t.Errorf("Nexter.Next() expecting complete error, received '%v'", err)
+	}
+	if input.after != 0 {
+		t.Errorf("Nexter.Next() expecting no reads past the error, received %d", input.after)
+	}
+}
+
+// lexPanicPeek matches a token, then peeks out of range
+//
+func lexPanicPeek(l *Lexer) Fn {
+	l.Next()
+	l.EmitToken(TStart)
+	l.Next()
+	l.Peek(0)
+	return nil
+}
+
+// TestWithPanicContext
+//
+func TestWithPanicContext(t *testing.T) {
+	nexter := LexString("ab\ncd", lexPanicPeek, WithPanicContext())
+	assertPanic(t, func() {
+		_, _ = nexter.Next()
+	}, "Lexer.Peek: range error (in github.com/tekwizely/go-parsing/lexer.lexPanicPeek at 1:2)")
+
+	nexter = LexString("ab", lexPanicPeek)
+	assertPanic(t, func() {
+		_, _ = nexter.Next()
+	}, "Lexer.Peek: range error")
+}
@@ -0,0 +1,102 @@
+package lexer
+
+// defaultOutputSize is the initial capacity of the output ring.
+//
+const defaultOutputSize = 8
+
+// outputRing is a growable ring buffer of emitted tokens ready for pickup.
+// Unlike a list, emitting a token does not allocate once the ring has grown to fit the output of a lexer function.
+//
+type outputRing struct {
+	items []*_token // Ring storage, len(items) is the capacity of the ring
+	head  int       // Index of the front token
+	len   int       // Number of tokens in the ring
+}
+
+// newOutputRing
+//
+func newOutputRing(size int) *outputRing {
+	if size < defaultOutputSize {
+		size = defaultOutputSize
+	}
+	return &outputRing{
+		items: make([]*_token, size),
+		head:  0,
+		len:   0,
+	}
+}
+
+// Len returns the number of tokens in the ring.
+//
+func (r *outputRing) Len() int {
+	return r.len
+}
+
+// PushBack adds t to the back of the ring, growing the ring if full.
+//
+func (r *outputRing) PushBack(t *_token) {
+	if r.len == len(r.items) {
+		items := make([]*_token, 2*len(r.items))
+		n := copy(items, r.items[r.head:])
+		copy(items[n:], r.items[:r.head])
+		r.items = items
+		r.head = 0
+	}
+	r.items[(r.head+r.len)%len(r.items)] = t
+	r.len++
+}
+
+// Back returns the token at the back of the ring, i.e. the last token pushed.
+// Returns nil if the ring is empty.
+//
+func (r *outputRing) Back() *_token {
+	if r.len == 0 {
+		return nil
+	}
+	return r.items[(r.head+r.len-1)%len(r.items)]
+}
+
+// reset removes all tokens from the ring, retaining its capacity.
+//
+func (r *outputRing) reset() {
+	for ; r.len > 0; r.len-- {
+		r.items[r.head] = nil
+		r.head = (r.head + 1) % len(r.items)
+	}
+	r.head = 0
+}
+
+// PopFront removes and returns the token at the front of the ring.
+// Returns nil if the ring is empty.
+//
+func (r *outputRing) PopFront() *_token {
+	if r.len == 0 {
+		return nil
+	}
+	t := r.items[r.head]
+	r.items[r.head] = nil
+	r.head = (r.head + 1) % len(r.items)
+	r.len--
+	return t
+}
+
+// Flush ends the current output batch, delivering all pending tokens to the consumer before any further lexer
+// functions are called.
+// Only meaningful when batching is enabled (see WithOutputBuffer), as otherwise tokens are always delivered as soon as
+// the lexer function that emitted them returns.
+// Does nothing if no tokens are pending.
+//
+func (l *Lexer) Flush() {
+	if l.output.Len() > 0 {
+		l.flushed = true
+	}
+}
+
+// outputReady confirms if the pending tokens should be delivered to the consumer.
+// Tokens are delivered once a full batch is pending (see WithOutputBuffer), once Flush is called, or once EOF is
+// emitted.
+//
+func (l *Lexer) outputReady() bool {
+	n := l.output.Len()
+	return n > 0 && (n >= l.batch || l.flushed || l.eofOut)
+}
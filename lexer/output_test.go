@@ -0,0 +1,106 @@
+package lexer
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestOutputRing
+//
+func TestOutputRing(t *testing.T) {
+	r := newOutputRing(0)
+	if r.PopFront() != nil {
+		t.Error("outputRing.PopFront() expecting nil for empty ring")
+	}
+	if r.Back() != nil {
+		t.Error("outputRing.Back() expecting nil for empty ring")
+	}
+	// Wrap the ring around, then force it to grow
+	//
+	next := 0
+	for i := 0; i < 5; i++ {
+		r.PushBack(newToken(TStart, "", i, 0))
+	}
+	for i := 0; i < 3; i++ {
+		assertToken(t, r.PopFront(), TStart, "", next, 0, false)
+		next++
+	}
+	for i := 5; i < 20; i++ {
+		r.PushBack(newToken(TStart, "", i, 0))
+	}
+	if r.Len() != 17 {
+		t.Errorf("outputRing.Len() expecting 17, received %d", r.Len())
+	}
+	assertToken(t, r.Back(), TStart, "", 19, 0, false)
+	for r.Len() > 0 {
+		assertToken(t, r.PopFront(), TStart, "", next, 0, false)
+		next++
+	}
+	if next != 20 {
+		t.Errorf("outputRing expecting 20 tokens, received %d", next)
+	}
+}
+
+// countingLexer emits each rune as a token, counting the lexer function calls.
+// If flushOn is not 0, Flush is called after emitting it.
+//
+func countingLexer(calls *int, flushOn rune) Fn {
+	var fn Fn
+	fn = func(l *Lexer) Fn {
+		*calls++
+		r := l.Next()
+		l.EmitToken(TStart)
+		if r == flushOn {
+			l.Flush()
+		}
+		return fn
+	}
+	return fn
+}
+
+// expectNexterCalls
+//
+func expectNexterCalls(t *testing.T, nexter token.Nexter, value string, calls *int, match int) {
+	expectNexterNext(t, nexter, TStart, value, 1, int(value[0]-'a')+1)
+	if *calls != match {
+		t.Errorf("Lexer expecting %d function calls before delivering '%s', received %d", match, value, *calls)
+	}
+}
+
+// TestWithOutputBuffer
+//
+func TestWithOutputBuffer(t *testing.T) {
+	calls := 0
+	nexter := LexString("abcde", countingLexer(&calls, 0), WithOutputBuffer(3))
+	expectNexterCalls(t, nexter, "a", &calls, 3)
+	expectNexterCalls(t, nexter, "b", &calls, 3)
+	expectNexterCalls(t, nexter, "c", &calls, 3)
+	expectNexterCalls(t, nexter, "d", &calls, 5)
+	expectNexterCalls(t, nexter, "e", &calls, 5)
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithOutputBufferDisabled
+//
+func TestWithOutputBufferDisabled(t *testing.T) {
+	calls := 0
+	nexter := LexString("abc", countingLexer(&calls, 0), WithOutputBuffer(0))
+	expectNexterCalls(t, nexter, "a", &calls, 1)
+	expectNexterCalls(t, nexter, "b", &calls, 2)
+	expectNexterCalls(t, nexter, "c", &calls, 3)
+	expectNexterEOF(t, nexter)
+}
+
+// TestFlush
+//
+func TestFlush(t *testing.T) {
+	calls := 0
+	nexter := LexString("abcde", countingLexer(&calls, 'b'), WithOutputBuffer(3))
+	expectNexterCalls(t, nexter, "a", &calls, 2)
+	expectNexterCalls(t, nexter, "b", &calls, 2)
+	expectNexterCalls(t, nexter, "c", &calls, 5)
+	expectNexterCalls(t, nexter, "d", &calls, 5)
+	expectNexterCalls(t, nexter, "e", &calls, 5)
+	expectNexterEOF(t, nexter)
+}
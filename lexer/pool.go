@@ -0,0 +1,53 @@
+package lexer
+
+import (
+	"io"
+	"sync"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Pool caches lexers for reuse across inputs, so servers lexing many small payloads avoid allocating a new lexer for
+// each one.
+// All lexers in the pool share the options passed to NewPool; Avoid options that capture per-input state
+// (i.e. WithStats, WithRecorder) when using the pool concurrently.
+// A Pool is safe for concurrent use.
+//
+type Pool struct {
+	opts []Option
+	pool sync.Pool
+}
+
+// NewPool returns a new, empty, Pool whose lexers are configured with the specified options.
+//
+func NewPool(opts ...Option) *Pool {
+	return &Pool{opts: opts}
+}
+
+// Lex initiates a lexer, taken from the pool, against the input io.RuneReader.
+// The returned token.Nexter can be used to retrieve emitted tokens.
+// Return the nexter to the pool via Put() once done with it.
+//
+func (p *Pool) Lex(input io.RuneReader, start Fn) token.Nexter {
+	if t, ok := p.pool.Get().(*tokenNexter); ok {
+		t.lexer.Reset(input, start)
+		t.next = nil
+		t.eof = false
+		t.timedOut = false
+		t.misused = false
+		return t
+	}
+	return &tokenNexter{lexer: newLexer(input, start, p.opts), pool: p}
+}
+
+// Put returns the lexer backing the token.Nexter to the pool.
+// The nexter must not be used after this call.
+// Nexters not created via this pool's Lex() are ignored.
+//
+func (p *Pool) Put(nexter token.Nexter) {
+	if t, ok := nexter.(*tokenNexter); ok && t.pool == p {
+		t.lexer.input = nil // Release the input
+		t.lexer.output.reset()
+		p.pool.Put(t)
+	}
+}
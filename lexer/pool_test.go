@@ -0,0 +1,84 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestPool
+//
+func TestPool(t *testing.T) {
+	pool := NewPool(WithContext("CTX"))
+	fn := func(l *Lexer) Fn {
+		if ctx := l.Context(); ctx != "CTX" {
+			t.Errorf("Lexer.Context() expecting 'CTX', received '%v'", ctx)
+		}
+		l.SetContext("CHANGED")
+		for l.CanPeek(1) && l.Peek(1) != '\n' {
+			l.Next()
+		}
+		l.EmitToken(TStart)
+		if l.CanPeek(1) {
+			l.Next()
+			l.Clear()
+		}
+		return nil
+	}
+	for _, input := range []string{"abc\ndef", "xy", "1\n2"} {
+		nexter := pool.Lex(strings.NewReader(input), fn)
+		expectNexterNext(t, nexter, TStart, input[:strings.IndexAny(input+"\n", "\n")], 1, 1)
+		expectNexterEOF(t, nexter)
+		pool.Put(nexter)
+	}
+}
+
+// TestPoolPutUnread
+//
+func TestPoolPutUnread(t *testing.T) {
+	pool := NewPool()
+	nexter := pool.Lex(strings.NewReader("abc"), lexRunes)
+	expectNexterNext(t, nexter, TStart, "a", 1, 1)
+	pool.Put(nexter)
+	nexter = pool.Lex(strings.NewReader("xy"), lexRunes)
+	expectNexterNext(t, nexter, TStart, "x", 1, 1)
+	expectNexterNext(t, nexter, TStart, "y", 1, 2)
+	expectNexterEOF(t, nexter)
+	// Foreign nexters are ignored
+	//
+	pool.Put(LexString("abc", lexRunes))
+	NewPool().Put(nexter)
+}
+
+// lexRunes emits each rune as a token
+//
+func lexRunes(l *Lexer) Fn {
+	l.Next()
+	l.EmitToken(TStart)
+	return lexRunes
+}
+
+// TestReset
+//
+func TestReset(t *testing.T) {
+	var m *Marker
+	nexter := LexString("abc", func(l *Lexer) Fn {
+		l.Next()
+		m = l.Marker()
+		l.Next()
+		l.EmitToken(TStart)
+		return nil
+	}, WithOutputBuffer(4))
+	expectNexterNext(t, nexter, TStart, "ab", 1, 1)
+	tn := nexter.(*tokenNexter)
+	l := tn.lexer
+	l.Reset(strings.NewReader("xyz"), lexRunes)
+	tn.eof = false
+	expectMarkerValid(t, m, false)
+	if l.LastEmitted() != nil || l.line != 0 || l.column != 0 || l.output.Len() != 0 {
+		t.Error("Lexer.Reset() expecting initial state")
+	}
+	expectNexterNext(t, nexter, TStart, "x", 1, 1)
+	expectNexterNext(t, nexter, TStart, "y", 1, 2)
+	expectNexterNext(t, nexter, TStart, "z", 1, 3)
+	expectNexterEOF(t, nexter)
+}
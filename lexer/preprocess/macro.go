@@ -0,0 +1,220 @@
+package preprocess
+
+import (
+	"io"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// DefaultMaxDepth is the expansion depth limit used when MacroConfig.MaxDepth is not set.
+//
+const DefaultMaxDepth = 64
+
+// MacroConfig configures macro expansion (see NewMacros).
+// Ident is the token type of identifiers that may name macros.
+// LParen, RParen and Comma are the token types used to invoke function-like macros, i.e. 'F(a, b)'.
+// MaxDepth limits how deeply expansions can nest, guarding against recursive macros; Defaults to DefaultMaxDepth.
+//
+type MacroConfig struct {
+	Ident    token.Type
+	LParen   token.Type
+	RParen   token.Type
+	Comma    token.Type
+	MaxDepth int
+}
+
+// Macro is a named token sequence.
+// A Macro with nil Params is object-like, and is expanded wherever its name appears.
+// A Macro with non-nil Params (even if empty) is function-like, and is only expanded when its name is followed by an
+// argument list; Occurrences of each param within the body are replaced by the matching argument.
+//
+type Macro struct {
+	Name   string
+	Params []string
+	Body   []token.Token
+}
+
+// Macros is a table of macros, expanded lazily in the token stream via WithMacros.
+// Expansions are re-processed, so macros within a body (or an argument) are expanded as they are reached.
+// Macros can be (un)defined at any time, including from within hooks, allowing '#define'-style directives.
+//
+type Macros struct {
+	config MacroConfig
+	defs   map[string]*Macro
+}
+
+// Expanded is the token.Token implementation for tokens produced by macro expansion.
+// Type() and Value() come from the definition token; Line() and Column() report the use-site.
+// Origin() returns the definition token, see token.Provenance().
+//
+type Expanded struct {
+	Def   token.Token // Token within the macro body that this token was copied from
+	Use   token.Token // Macro name token at the use-site, which may itself be an *Expanded
+	Macro *Macro      // The expanded macro
+	Depth int         // Expansion depth, 1 for macros used directly in the input
+}
+
+// Type implements Token.Type().
+//
+func (t *Expanded) Type() token.Type {
+	return t.Def.Type()
+}
+
+// Value implements Token.Value().
+//
+func (t *Expanded) Value() string {
+	return t.Def.Value()
+}
+
+// Line implements Token.Line(), returning the line of the use-site.
+//
+func (t *Expanded) Line() int {
+	return t.Use.Line()
+}
+
+// Column implements Token.Column(), returning the column of the use-site.
+//
+func (t *Expanded) Column() int {
+	return t.Use.Column()
+}
+
+// Origin implements token.Originer, returning the definition token.
+//
+func (t *Expanded) Origin() token.Token {
+	return t.Def
+}
+
+// NewMacros returns a new, empty, macro table.
+//
+func NewMacros(config MacroConfig) *Macros {
+	if config.MaxDepth <= 0 {
+		config.MaxDepth = DefaultMaxDepth
+	}
+	return &Macros{config: config, defs: make(map[string]*Macro)}
+}
+
+// WithMacros enables macro expansion, using the specified macro table.
+// Registers a hook for the MacroConfig.Ident token type, replacing any hook registered before it.
+//
+func WithMacros(m *Macros) Option {
+	return WithHook(m.config.Ident, m.expand)
+}
+
+// Define adds (or replaces) a macro, returning it.
+// Pass nil params for an object-like macro.
+//
+func (m *Macros) Define(name string, params []string, body ...token.Token) *Macro {
+	macro := &Macro{Name: name, Params: params, Body: body}
+	m.defs[name] = macro
+	return macro
+}
+
+// Undefine removes the named macro, if defined.
+//
+func (m *Macros) Undefine(name string) {
+	delete(m.defs, name)
+}
+
+// Lookup returns the named macro, along with true, if defined.
+// Otherwise returns nil, false.
+//
+func (m *Macros) Lookup(name string) (*Macro, bool) {
+	macro, ok := m.defs[name]
+	return macro, ok
+}
+
+// expand is the hook that expands macro names.
+//
+func (m *Macros) expand(p *Preprocessor, tok token.Token) ([]token.Token, error) {
+	macro, ok := m.defs[tok.Value()]
+	if !ok {
+		return []token.Token{tok}, nil
+	}
+	depth := 1
+	if e, ok := tok.(*Expanded); ok {
+		depth = e.Depth + 1
+	}
+	if depth > m.config.MaxDepth {
+		return nil, errorf(tok, "macro '%s' exceeds max expansion depth %d", macro.Name, m.config.MaxDepth)
+	}
+	var args map[string][]token.Token
+	if macro.Params != nil {
+		// Function-like macros are only expanded when followed by an argument list
+		//
+		next, err := p.Read()
+		if err == io.EOF {
+			return []token.Token{tok}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if next.Type() != m.config.LParen {
+			p.Unread(next)
+			return []token.Token{tok}, nil
+		}
+		if args, err = m.readArgs(p, tok, macro); err != nil {
+			return nil, err
+		}
+	}
+	var out []token.Token
+	for _, def := range macro.Body {
+		if def.Type() == m.config.Ident {
+			if arg, ok := args[def.Value()]; ok {
+				out = append(out, arg...)
+				continue
+			}
+		}
+		out = append(out, &Expanded{Def: def, Use: tok, Macro: macro, Depth: depth})
+	}
+	// Re-process the expansion, expanding nested macros as they are reached
+	//
+	p.Unread(out...)
+	return nil, nil
+}
+
+// readArgs reads the arguments of a function-like macro, up to and including the closing paren, mapping them to
+// the macro params.
+//
+func (m *Macros) readArgs(p *Preprocessor, use token.Token, macro *Macro) (map[string][]token.Token, error) {
+	var args [][]token.Token
+	var arg []token.Token
+	depth := 0
+	for {
+		tok, err := p.Read()
+		if err == io.EOF {
+			return nil, errorf(use, "unterminated arguments for macro '%s'", macro.Name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch tok.Type() {
+		case m.config.LParen:
+			depth++
+		case m.config.RParen:
+			if depth == 0 {
+				args = append(args, arg)
+				// 'F()' is a call with no arguments
+				//
+				if len(macro.Params) == 0 && len(args) == 1 && len(args[0]) == 0 {
+					args = nil
+				}
+				if len(args) != len(macro.Params) {
+					return nil, errorf(use, "macro '%s' expects %d arguments, received %d", macro.Name, len(macro.Params), len(args))
+				}
+				params := make(map[string][]token.Token, len(args))
+				for i, param := range macro.Params {
+					params[param] = args[i]
+				}
+				return params, nil
+			}
+			depth--
+		case m.config.Comma:
+			if depth == 0 {
+				args = append(args, arg)
+				arg = nil
+				continue
+			}
+		}
+		arg = append(arg, tok)
+	}
+}
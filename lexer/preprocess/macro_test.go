@@ -0,0 +1,141 @@
+package preprocess
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// macros returns a macro table using the test token types.
+//
+func macros(maxDepth int) *Macros {
+	return NewMacros(MacroConfig{Ident: TWord, LParen: TLParen, RParen: TRParen, Comma: TComma, MaxDepth: maxDepth})
+}
+
+// body builds the tokens for a macro body, positioned on line 100.
+//
+func body(input string) []token.Token {
+	var tokens []token.Token
+	for _, word := range strings.Fields(input) {
+		tok, _ := words(word).Next()
+		tokens = append(tokens, token.New(tok.Type(), tok.Value(), 100, len(tokens)+1))
+	}
+	return tokens
+}
+
+// TestMacroObject
+//
+func TestMacroObject(t *testing.T) {
+	m := macros(0)
+	m.Define("PI", nil, body("3 . 14")...)
+	expectValues(t, New(words("a PI b"), WithMacros(m)), "a", "3", ".", "14", "b")
+}
+
+// TestMacroFunction
+//
+func TestMacroFunction(t *testing.T) {
+	m := macros(0)
+	m.Define("ADD", []string{"x", "y"}, body("x + y")...)
+	m.Define("NONE", []string{}, body("nothing")...)
+	expectValues(t, New(words("ADD ( a , ( b , c ) ) ADD b NONE ( ) NONE"), WithMacros(m)),
+		"a", "+", "(", "b", ",", "c", ")", "ADD", "b", "nothing", "NONE")
+	expectValues(t, New(words("ADD ( a ) b"), WithMacros(m)),
+		"error: 1:1: macro 'ADD' expects 2 arguments, received 1", "b")
+	expectValues(t, New(words("ADD ( a , b"), WithMacros(m)),
+		"error: 1:1: unterminated arguments for macro 'ADD'")
+}
+
+// TestMacroNested
+//
+func TestMacroNested(t *testing.T) {
+	m := macros(0)
+	m.Define("TWICE", []string{"x"}, body("x x")...)
+	m.Define("ONE", nil, body("1")...)
+	expectValues(t, New(words("TWICE ( ONE ) TWICE ( TWICE ( b ) )"), WithMacros(m)), "1", "1", "b", "b", "b", "b")
+}
+
+// TestMacroPositions
+//
+func TestMacroPositions(t *testing.T) {
+	m := macros(0)
+	inner := m.Define("INNER", nil, body("x")...)
+	outer := m.Define("OUTER", nil, body("y INNER")...)
+	p := New(words("a OUTER"), WithMacros(m))
+	if a := mustNext(t, p); a.Value() != "a" {
+		t.Errorf("Nexter.Next() expecting 'a', received '%s'", a.Value())
+	}
+
+	y := mustNext(t, p).(*Expanded)
+	if y.Line() != 1 || y.Column() != 2 || y.Def.Line() != 100 || y.Def.Column() != 1 || y.Macro != outer || y.Depth != 1 {
+		t.Errorf("Expanded expecting 'y' at 1:2 (def 100:1, OUTER, depth 1), received %d:%d (def %d:%d, %s, depth %d)",
+			y.Line(), y.Column(), y.Def.Line(), y.Def.Column(), y.Macro.Name, y.Depth)
+	}
+	x := mustNext(t, p).(*Expanded)
+	if x.Line() != 1 || x.Column() != 2 || x.Def.Line() != 100 || x.Def.Column() != 1 || x.Macro != inner || x.Depth != 2 {
+		t.Errorf("Expanded expecting 'x' at 1:2 (def 100:1, INNER, depth 2), received %d:%d (def %d:%d, %s, depth %d)",
+			x.Line(), x.Column(), x.Def.Line(), x.Def.Column(), x.Macro.Name, x.Depth)
+	}
+	if s := token.Provenance(x); s != "1:2 (from 100:1)" {
+		t.Errorf("token.Provenance() expecting '1:2 (from 100:1)', received '%s'", s)
+	}
+	if use := x.Use.(*Expanded); use.Value() != "INNER" || use.Def.Column() != 2 {
+		t.Errorf("Expanded.Use expecting 'INNER' (def 100:2), received '%s' (def %d:%d)", use.Value(), use.Def.Line(), use.Def.Column())
+	}
+	expectValues(t, p)
+}
+
+// TestMacroRecursion
+//
+func TestMacroRecursion(t *testing.T) {
+	m := macros(3)
+	m.Define("A", nil, body("a A")...)
+	expectValues(t, New(words("A b"), WithMacros(m)),
+		"a", "a", "a", "error: 1:1: macro 'A' exceeds max expansion depth 3", "b")
+}
+
+// TestMacroDefineDirective
+//
+func TestMacroDefineDirective(t *testing.T) {
+	m := macros(0)
+	// '#define NAME VALUE'
+	//
+	define := func(p *Preprocessor, tok token.Token) ([]token.Token, error) {
+		name, _ := p.Read()
+		value, _ := p.Read()
+		m.Define(name.Value(), nil, value)
+		return nil, nil
+	}
+	p := New(words("X #define X 1 X #if X a #endif"),
+		WithMacros(m),
+		WithHook(TDefine, define),
+		WithConditionals(Conditionals{
+			If:    TIf,
+			Else:  TElse,
+			EndIf: TEndIf,
+			Eval: func(p *Preprocessor, directive token.Token) (bool, error) {
+				name, err := p.Read()
+				if err != nil {
+					return false, err
+				}
+				_, ok := m.Lookup(name.Value())
+				return ok, nil
+			},
+		}),
+	)
+	expectValues(t, p, "X", "1", "a")
+	m.Undefine("X")
+	if _, ok := m.Lookup("X"); ok {
+		t.Error("Macros.Lookup() expecting false after Undefine")
+	}
+}
+
+// mustNext
+//
+func mustNext(t *testing.T, nexter token.Nexter) token.Token {
+	tok, err := nexter.Next()
+	if err != nil {
+		t.Fatalf("Nexter.Next() received unexpected error '%v'", err)
+	}
+	return tok
+}
@@ -0,0 +1,352 @@
+/*
+Package preprocess implements a token-rewriting stage that sits between a lexer and a parser.
+
+A Preprocessor wraps a token.Nexter and is itself a token.Nexter, so the parser is unaware of it:
+
+	tokens := preprocess.New(lexer.LexString(input, lexStart),
+		preprocess.WithConditionals(conds),
+		preprocess.WithPaste(TPaste, nil),
+	)
+	asts := parser.Parse(tokens, parseStart)
+
+Conditional Sections
+
+With WithConditionals, tokens between #if/#elif/#else/#endif-style directive tokens are dropped when their condition
+does not hold.
+Sections can be nested.
+
+Token Pasting
+
+With WithPaste, two tokens separated by a paste operator (i.e. '##') are combined into a single token.
+
+Hooks
+
+With WithHook, a user function is called for each token of a given type, replacing the token with zero or more
+tokens.
+Hooks can read ahead in the input via Preprocessor.Read(), and push tokens back for re-processing via
+Preprocessor.Unread().
+
+Macros
+
+With WithMacros, user-defined macros (see Macros) are expanded lazily in the token stream.
+Function-like macros take arguments, i.e. 'ADD(a, b)'.
+Expanded tokens (see Expanded) report the use-site as their position, while retaining the definition token.
+Recursive expansions are limited via MacroConfig.MaxDepth.
+
+Processing happens in order: Conditional sections are applied to the input, then hooks are called for the tokens in
+active sections, then pasting is applied to the result.
+
+*/
+package preprocess
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Hook is called for each token of the type it is registered for (see WithHook), returning the tokens to use in its
+// place.
+// Return nil to drop the token.
+// The returned tokens are not passed to hooks again; Use Preprocessor.Unread() to re-process tokens instead.
+//
+type Hook func(p *Preprocessor, tok token.Token) ([]token.Token, error)
+
+// PasteFn combines the left and right operands of a paste operator into a single token.
+//
+type PasteFn func(left token.Token, right token.Token) (token.Token, error)
+
+// Conditionals configures conditional sections (see WithConditionals).
+// If, Else and EndIf are the token types of the directives.
+// Elif is optional; Leave it as 0 if your input does not support it.
+// Eval is called for each If/Elif directive whose section could be active, and reports if the condition holds.
+// Eval can read the condition tokens that follow the directive via Preprocessor.Read().
+//
+type Conditionals struct {
+	If    token.Type
+	Elif  token.Type
+	Else  token.Type
+	EndIf token.Type
+	Eval  func(p *Preprocessor, directive token.Token) (bool, error)
+}
+
+// Option configures a preprocessor at creation time.
+//
+type Option func(*Preprocessor)
+
+// WithConditionals enables conditional sections.
+// See Conditionals for details.
+//
+func WithConditionals(c Conditionals) Option {
+	return func(p *Preprocessor) {
+		p.conds = &c
+	}
+}
+
+// WithPaste enables token pasting, combining the tokens on either side of each op token via fn.
+// If fn is nil, DefaultPaste is used.
+//
+func WithPaste(op token.Type, fn PasteFn) Option {
+	return func(p *Preprocessor) {
+		if fn == nil {
+			fn = DefaultPaste
+		}
+		p.paste = fn
+		p.pasteOp = op
+	}
+}
+
+// WithHook registers fn to be called for each token of the specified type.
+// Registering a second hook for the same type replaces the first.
+// See Hook for details.
+//
+func WithHook(typ token.Type, fn Hook) Option {
+	return func(p *Preprocessor) {
+		p.hooks[typ] = fn
+	}
+}
+
+// DefaultPaste combines two tokens into a token of the left token's type and position, whose value is the
+// concatenation of both values.
+// The left token is recorded as the origin of the result (see token.Originer).
+//
+func DefaultPaste(left token.Token, right token.Token) (token.Token, error) {
+	return token.Derive(left.Type(), left.Value()+right.Value(), left.Line(), left.Column(), left), nil
+}
+
+// Preprocessor is a token.Nexter that rewrites the tokens of another token.Nexter.
+// Create one via New().
+//
+type Preprocessor struct {
+	input   token.Nexter        // Source of tokens
+	eof     bool                // Has the input returned io.EOF?
+	unread  []token.Token       // Tokens pushed back via Unread(), read before the input
+	out     []token.Token       // Tokens returned from hooks, ready for pasting
+	conds   *Conditionals       // Conditional directive config, nil if disabled
+	frames  []frame             // Open conditional sections, innermost last
+	hooks   map[token.Type]Hook // Hooks by token type
+	paste   PasteFn             // Combines pasted tokens, nil if disabled
+	pasteOp token.Type          // Paste operator token type
+	peeked  bool                // Is there a processed token waiting in peekTok/peekErr?
+	peekTok token.Token         // Processed token read ahead, for pasting
+	peekErr error               // Error read ahead, for pasting
+}
+
+// frame tracks an open conditional section.
+//
+type frame struct {
+	directive token.Token // The If directive that opened the section
+	active    bool        // Is the current branch active?
+	taken     bool        // Has a branch been taken? If so, all further branches are inactive
+	elsed     bool        // Has the Else directive been seen?
+}
+
+// New returns a Preprocessor that reads tokens from input, configured via the specified options.
+// With no options, tokens are passed through unchanged.
+//
+func New(input token.Nexter, opts ...Option) *Preprocessor {
+	p := &Preprocessor{
+		input:   input,
+		eof:     false,
+		unread:  nil,
+		out:     nil,
+		conds:   nil,
+		frames:  nil,
+		hooks:   make(map[token.Type]Hook),
+		paste:   nil,
+		pasteOp: 0,
+		peeked:  false,
+		peekTok: nil,
+		peekErr: nil,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Next implements token.Nexter.Next(), returning the next processed token.
+// Errors from the input are passed through.
+// Directive errors (i.e. an Else without an If) are returned in place of a token, and processing continues.
+//
+func (p *Preprocessor) Next() (token.Token, error) {
+	tok, err := p.process()
+	if err != nil || p.paste == nil {
+		return tok, err
+	}
+	if tok.Type() == p.pasteOp {
+		return nil, errorf(tok, "paste operator '%s' missing left operand", tok.Value())
+	}
+	for {
+		op, err := p.peek()
+		if err != nil || op.Type() != p.pasteOp {
+			return tok, nil
+		}
+		p.peeked = false
+		right, err := p.process()
+		if err == io.EOF || (err == nil && right.Type() == p.pasteOp) {
+			return nil, errorf(op, "paste operator '%s' missing right operand", op.Value())
+		}
+		if err != nil {
+			return nil, err
+		}
+		if tok, err = p.paste(tok, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Read returns the next raw token, from the tokens pushed back via Unread(), or from the input.
+// Conditional sections, hooks and pasting are not applied.
+// Intended for use within hooks and Conditionals.Eval, i.e. to read the arguments of a directive.
+//
+func (p *Preprocessor) Read() (token.Token, error) {
+	if n := len(p.unread); n > 0 {
+		tok := p.unread[n-1]
+		p.unread = p.unread[:n-1]
+		return tok, nil
+	}
+	if p.eof {
+		return nil, io.EOF
+	}
+	tok, err := p.input.Next()
+	if err == io.EOF {
+		p.eof = true
+	}
+	return tok, err
+}
+
+// Unread pushes the specified tokens back onto the input, to be read (and processed) before any other tokens.
+// The tokens are read in the order given.
+// Intended for use within hooks, i.e. to re-process the result of an expansion.
+//
+func (p *Preprocessor) Unread(tokens ...token.Token) {
+	for i := len(tokens) - 1; i >= 0; i-- {
+		p.unread = append(p.unread, tokens[i])
+	}
+}
+
+// Active confirms if the current conditional section is active.
+// Always true if conditional sections are not enabled.
+//
+func (p *Preprocessor) Active() bool {
+	return len(p.frames) == 0 || p.frames[len(p.frames)-1].active
+}
+
+// peek reads ahead one processed token, for pasting.
+//
+func (p *Preprocessor) peek() (token.Token, error) {
+	if !p.peeked {
+		p.peekTok, p.peekErr = p.process()
+		p.peeked = true
+	}
+	return p.peekTok, p.peekErr
+}
+
+// process returns the next token after applying conditional sections and hooks.
+//
+func (p *Preprocessor) process() (token.Token, error) {
+	if p.peeked {
+		p.peeked = false
+		return p.peekTok, p.peekErr
+	}
+	for {
+		if len(p.out) > 0 {
+			tok := p.out[0]
+			p.out = p.out[1:]
+			return tok, nil
+		}
+		tok, err := p.Read()
+		if err == io.EOF && len(p.frames) > 0 {
+			directive := p.frames[0].directive
+			p.frames = nil
+			return nil, errorf(directive, "unterminated '%s'", directive.Value())
+		}
+		if err != nil {
+			return nil, err
+		}
+		if p.conds != nil {
+			if handled, err := p.directive(tok); handled || err != nil {
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		if !p.Active() {
+			continue
+		}
+		hook, ok := p.hooks[tok.Type()]
+		if !ok {
+			return tok, nil
+		}
+		out, err := hook(p, tok)
+		if err != nil {
+			return nil, err
+		}
+		p.out = append(p.out, out...)
+	}
+}
+
+// directive applies tok if it is a conditional directive, returning true if so.
+//
+func (p *Preprocessor) directive(tok token.Token) (bool, error) {
+	c := p.conds
+	switch typ := tok.Type(); {
+	case typ == c.If:
+		// When the enclosing section is inactive, mark the branch as taken so no other branch can become active
+		//
+		f := frame{directive: tok, active: false, taken: true, elsed: false}
+		if p.Active() {
+			cond, err := c.Eval(p, tok)
+			if err != nil {
+				return true, err
+			}
+			f.active, f.taken = cond, cond
+		}
+		p.frames = append(p.frames, f)
+	case typ == c.Elif && c.Elif != 0:
+		f := p.top()
+		if f == nil || f.elsed {
+			return true, errorf(tok, "unexpected '%s'", tok.Value())
+		}
+		f.active = false
+		if !f.taken {
+			cond, err := c.Eval(p, tok)
+			if err != nil {
+				return true, err
+			}
+			f.active, f.taken = cond, cond
+		}
+	case typ == c.Else:
+		f := p.top()
+		if f == nil || f.elsed {
+			return true, errorf(tok, "unexpected '%s'", tok.Value())
+		}
+		f.active, f.taken, f.elsed = !f.taken, true, true
+	case typ == c.EndIf:
+		if p.top() == nil {
+			return true, errorf(tok, "unexpected '%s'", tok.Value())
+		}
+		p.frames = p.frames[:len(p.frames)-1]
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// top returns the innermost open conditional section, or nil if none.
+//
+func (p *Preprocessor) top() *frame {
+	if len(p.frames) == 0 {
+		return nil
+	}
+	return &p.frames[len(p.frames)-1]
+}
+
+// errorf returns an error prefixed with the position of tok.
+//
+func errorf(tok token.Token, format string, args ...interface{}) error {
+	return fmt.Errorf("%d:%d: %s", tok.Line(), tok.Column(), fmt.Sprintf(format, args...))
+}
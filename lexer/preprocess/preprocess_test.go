@@ -0,0 +1,228 @@
+package preprocess
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+const (
+	TWord token.Type = iota + 1
+	TIf
+	TElif
+	TElse
+	TEndIf
+	TPaste
+	TUpper
+	TLParen
+	TRParen
+	TComma
+	TDefine
+)
+
+// words builds a token for each space-separated word, with the word's index as its column.
+// Directive words (i.e. '#if') and '##' are given their token types, all other words are TWord.
+//
+func words(input string) token.Nexter {
+	types := map[string]token.Type{"#if": TIf, "#elif": TElif, "#else": TElse, "#endif": TEndIf, "##": TPaste,
+		"(": TLParen, ")": TRParen, ",": TComma, "#define": TDefine}
+	var tokens []token.Token
+	for i, word := range strings.Fields(input) {
+		typ, ok := types[word]
+		if !ok {
+			typ = TWord
+		}
+		tokens = append(tokens, token.New(typ, word, 1, i+1))
+	}
+	return token.SliceNexter(tokens...)
+}
+
+// conditionals returns a Conditionals whose Eval reads the next token, checking if it is defined.
+//
+func conditionals(defined ...string) Conditionals {
+	return Conditionals{
+		If:    TIf,
+		Elif:  TElif,
+		Else:  TElse,
+		EndIf: TEndIf,
+		Eval: func(p *Preprocessor, directive token.Token) (bool, error) {
+			tok, err := p.Read()
+			if err != nil {
+				return false, errorf(directive, "missing condition")
+			}
+			for _, d := range defined {
+				if tok.Value() == d {
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+	}
+}
+
+// expectValues confirms the nexter emits tokens with the specified values, followed by io.EOF.
+// Errors are matched as "error: msg".
+//
+func expectValues(t *testing.T, nexter token.Nexter, match ...string) {
+	var values []string
+	for {
+		tok, err := nexter.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			values = append(values, "error: "+err.Error())
+		} else {
+			values = append(values, tok.Value())
+		}
+	}
+	if strings.Join(values, "|") != strings.Join(match, "|") {
+		t.Errorf("Preprocessor expecting %q, received %q", match, values)
+	}
+}
+
+// TestPassThrough
+//
+func TestPassThrough(t *testing.T) {
+	expectValues(t, New(words("a #if b ## c")), "a", "#if", "b", "##", "c")
+}
+
+// TestConditionals
+//
+func TestConditionals(t *testing.T) {
+	input := "a #if X b #elif Y c #else d #endif e"
+	expectValues(t, New(words(input), WithConditionals(conditionals("X"))), "a", "b", "e")
+	expectValues(t, New(words(input), WithConditionals(conditionals("Y"))), "a", "c", "e")
+	expectValues(t, New(words(input), WithConditionals(conditionals())), "a", "d", "e")
+	expectValues(t, New(words(input), WithConditionals(conditionals("X", "Y"))), "a", "b", "e")
+}
+
+// TestConditionalsNested
+//
+func TestConditionalsNested(t *testing.T) {
+	input := "#if X a #if Y b #else c #endif d #else #if Y e #else f #endif #endif g"
+	expectValues(t, New(words(input), WithConditionals(conditionals("X", "Y"))), "a", "b", "d", "g")
+	expectValues(t, New(words(input), WithConditionals(conditionals("X"))), "a", "c", "d", "g")
+	expectValues(t, New(words(input), WithConditionals(conditionals("Y"))), "e", "g")
+	expectValues(t, New(words(input), WithConditionals(conditionals())), "f", "g")
+}
+
+// TestConditionalsErrors
+//
+func TestConditionalsErrors(t *testing.T) {
+	expectValues(t, New(words("a #else b #endif c"), WithConditionals(conditionals())),
+		"a", "error: 1:2: unexpected '#else'", "b", "error: 1:4: unexpected '#endif'", "c")
+	expectValues(t, New(words("#if X a #else b #else c #endif"), WithConditionals(conditionals())),
+		"b", "error: 1:6: unexpected '#else'", "c")
+	expectValues(t, New(words("#if X a #else b #elif Y c #endif"), WithConditionals(conditionals("Y"))),
+		"b", "error: 1:6: unexpected '#elif'", "Y", "c")
+	expectValues(t, New(words("a #if X b #if Y"), WithConditionals(conditionals("X"))),
+		"a", "b", "error: 1:2: unterminated '#if'")
+	expectValues(t, New(words("a #if"), WithConditionals(conditionals())),
+		"a", "error: 1:2: missing condition")
+}
+
+// TestPaste
+//
+func TestPaste(t *testing.T) {
+	p := New(words("a ## b ## c d"), WithPaste(TPaste, nil))
+	tok, err := p.Next()
+	if err != nil || tok.Type() != TWord || tok.Value() != "abc" || tok.Line() != 1 || tok.Column() != 1 {
+		t.Errorf("Preprocessor.Next() expecting ({%d, 'abc', 1:1}, nil), received (%v, %v)", TWord, tok, err)
+	}
+	if o := token.Root(tok); o == tok || o.Value() != "a" {
+		t.Errorf("token.Root() expecting 'a', received '%s'", o.Value())
+	}
+	expectValues(t, p, "d")
+}
+
+// TestPasteFn
+//
+func TestPasteFn(t *testing.T) {
+	fn := func(left token.Token, right token.Token) (token.Token, error) {
+		if right.Value() == "x" {
+			return nil, errors.New("cannot paste x")
+		}
+		return token.New(TUpper, left.Value()+"_"+right.Value(), left.Line(), left.Column()), nil
+	}
+	expectValues(t, New(words("a ## b c ## x d"), WithPaste(TPaste, fn)), "a_b", "error: cannot paste x", "d")
+}
+
+// TestPasteErrors
+//
+func TestPasteErrors(t *testing.T) {
+	expectValues(t, New(words("## a"), WithPaste(TPaste, nil)),
+		"error: 1:1: paste operator '##' missing left operand", "a")
+	expectValues(t, New(words("a ##"), WithPaste(TPaste, nil)),
+		"error: 1:2: paste operator '##' missing right operand")
+	expectValues(t, New(words("a ## ## b"), WithPaste(TPaste, nil)),
+		"error: 1:2: paste operator '##' missing right operand", "b")
+}
+
+// TestHook
+//
+func TestHook(t *testing.T) {
+	hook := func(p *Preprocessor, tok token.Token) ([]token.Token, error) {
+		switch tok.Value() {
+		case "drop":
+			return nil, nil
+		case "twice":
+			return []token.Token{tok, tok}, nil
+		case "fail":
+			return nil, errors.New("hook failed")
+		}
+		return []token.Token{token.New(TUpper, strings.ToUpper(tok.Value()), tok.Line(), tok.Column())}, nil
+	}
+	expectValues(t, New(words("a drop twice fail b"), WithHook(TWord, hook)),
+		"A", "twice", "twice", "error: hook failed", "B")
+}
+
+// TestHookReadUnread
+//
+func TestHookReadUnread(t *testing.T) {
+	// 'swap' swaps the next two words, re-processing them
+	//
+	hook := func(p *Preprocessor, tok token.Token) ([]token.Token, error) {
+		if tok.Value() != "swap" {
+			return []token.Token{tok}, nil
+		}
+		first, err := p.Read()
+		if err != nil {
+			return nil, err
+		}
+		second, err := p.Read()
+		if err != nil {
+			return nil, err
+		}
+		p.Unread(second, first)
+		return nil, nil
+	}
+	expectValues(t, New(words("a swap b c d"), WithHook(TWord, hook)), "a", "c", "b", "d")
+	expectValues(t, New(words("swap swap a b c"), WithHook(TWord, hook)), "a", "c", "b")
+	expectValues(t, New(words("a swap"), WithHook(TWord, hook)), "a")
+}
+
+// TestHookInactive
+//
+func TestHookInactive(t *testing.T) {
+	calls := 0
+	hook := func(p *Preprocessor, tok token.Token) ([]token.Token, error) {
+		calls++
+		return []token.Token{tok}, nil
+	}
+	p := New(words("a #if X b c #endif ## d"), WithConditionals(conditionals()), WithHook(TWord, hook), WithPaste(TPaste, nil))
+	expectValues(t, p, "ad")
+	if calls != 2 {
+		t.Errorf("Hook expecting 2 calls, received %d", calls)
+	}
+}
+
+// TestInputError
+//
+func TestInputError(t *testing.T) {
+	err := errors.New("input error")
+	expectValues(t, New(token.ErrNexter(words("a b"), 1, err), WithPaste(TPaste, nil)), "a", "error: input error", "b")
+}
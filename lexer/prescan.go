@@ -0,0 +1,141 @@
+package lexer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Header captures a header construct (i.e. a shebang line, an encoding declaration or a pragma comment) consumed
+// before lexing starts.
+// See WithPrescan.
+//
+type Header struct {
+	Key    string // Header key, as returned by the Prescan hook, i.e. "shebang"
+	Value  string // Header value, as returned by the Prescan hook, i.e. "/usr/bin/env python3"
+	Text   string // The matched text, including any trailing newline
+	Line   int    // Line of the start of the header
+	Column int    // Column of the start of the header
+}
+
+// Prescan is a hook that matches a header construct at the current position of the input, before lexing starts.
+// Returns the header key and value, along with true if matched.
+// If matched, the matched runes are recorded as a Header and discarded, otherwise the lexer is reset to its state
+// before the hook was called, so hooks need not clean up after themselves.
+// CanPeek(1) is guaranteed to be true when a hook is called.
+// See WithPrescan.
+//
+type Prescan func(l *Lexer) (key string, value string, ok bool)
+
+// Headers returns the headers consumed before lexing started, in order.
+// See WithPrescan.
+//
+func (l *Lexer) Headers() []Header {
+	return l.headers
+}
+
+// Header returns the value of the first header with the specified key, along with true if found.
+// See WithPrescan.
+//
+func (l *Lexer) Header(key string) (string, bool) {
+	for _, h := range l.headers {
+		if h.Key == key {
+			return h.Value, true
+		}
+	}
+	return "", false
+}
+
+// prescan runs the prescan hooks until none of them match (see WithPrescan).
+//
+func (l *Lexer) prescan() {
+	l.scanned = true
+	for matched := true; matched && l.CanPeek(1); {
+		matched = false
+		for _, hook := range l.prescans {
+			m := l.Marker()
+			key, value, ok := hook(l)
+			if !ok {
+				m.Apply()
+				continue
+			}
+			text, line, column := l.clear(true)
+			l.headers = append(l.headers, Header{Key: key, Value: value, Text: text, Line: line, Column: column})
+			matched = true
+			break
+		}
+	}
+}
+
+// matchLine matches the remainder of the current line, including the newline (see MatchNewline), returning the text
+// matched, excluding the newline.
+//
+func matchLine(l *Lexer) string {
+	b := &strings.Builder{}
+	for l.CanPeek(1) && !l.lineMode.IsTerminator(l.Peek(1)) {
+		b.WriteRune(l.Next())
+	}
+	l.MatchNewline()
+	return b.String()
+}
+
+// matchPrefix matches prefix, returning true if matched.
+//
+func matchPrefix(l *Lexer, prefix string) bool {
+	for _, r := range prefix {
+		if !l.CanPeek(1) || l.Peek(1) != r {
+			return false
+		}
+		l.Next()
+	}
+	return true
+}
+
+// Shebang is a Prescan hook that matches a shebang line ("#!") at the very start of the input, recording it with the
+// key "shebang" and the interpreter line (excluding the "#!") as the value, i.e. "/usr/bin/env python3".
+//
+func Shebang(l *Lexer) (string, string, bool) {
+	if l.line > 1 || l.column > 1 || !matchPrefix(l, "#!") {
+		return "", "", false
+	}
+	return "shebang", strings.TrimSpace(matchLine(l)), true
+}
+
+// Directive returns a Prescan hook that matches lines starting with prefix (i.e. "#pragma " or "//go:"), recording
+// them with the specified key and the remainder of the line (trimmed) as the value.
+//
+func Directive(prefix string, key string) Prescan {
+	return func(l *Lexer) (string, string, bool) {
+		if !matchPrefix(l, prefix) {
+			return "", "", false
+		}
+		return key, strings.TrimSpace(matchLine(l)), true
+	}
+}
+
+// Encoding returns a Prescan hook that matches an encoding declaration (PEP 263 style) within a comment line starting
+// with commentPrefix, on the first two lines of the input, i.e. "# -*- coding: utf-8 -*-".
+// The declaration is recorded with the key "encoding" and the encoding name as the value, i.e. "utf-8".
+//
+func Encoding(commentPrefix string) Prescan {
+	return func(l *Lexer) (string, string, bool) {
+		if l.line > 2 || !matchPrefix(l, commentPrefix) {
+			return "", "", false
+		}
+		line := matchLine(l)
+		i := strings.Index(line, "coding")
+		if i < 0 || i+6 >= len(line) || (line[i+6] != ':' && line[i+6] != '=') {
+			return "", "", false
+		}
+		name := strings.TrimLeft(line[i+7:], " \t")
+		end := strings.IndexFunc(name, func(r rune) bool {
+			return !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.')
+		})
+		if end >= 0 {
+			name = name[:end]
+		}
+		if name == "" {
+			return "", "", false
+		}
+		return "encoding", name, true
+	}
+}
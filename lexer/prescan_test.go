@@ -0,0 +1,66 @@
+package lexer
+
+import "testing"
+
+// TestWithPrescan
+//
+func TestWithPrescan(t *testing.T) {
+	input := "#!/usr/bin/env calc \n# -*- coding: latin-1 -*-\n#pragma once\n#pragma pack\nab"
+	var headers []Header
+	fn := func(l *Lexer) Fn {
+		headers = l.Headers()
+		if v, ok := l.Header("pragma"); !ok || v != "once" {
+			t.Errorf("Lexer.Header(\"pragma\") expecting 'once', received '%s'", v)
+		}
+		if _, ok := l.Header("nope"); ok {
+			t.Error("Lexer.Header(\"nope\") expecting false")
+		}
+		return lexAll(l)
+	}
+	nexter := LexString(input, fn, WithPrescan(Shebang, Encoding("#"), Directive("#pragma ", "pragma")))
+	expectNexterNext(t, nexter, TStart, "ab", 5, 1)
+	expectNexterEOF(t, nexter)
+	expected := []Header{
+		{Key: "shebang", Value: "/usr/bin/env calc", Text: "#!/usr/bin/env calc \n", Line: 1, Column: 1},
+		{Key: "encoding", Value: "latin-1", Text: "# -*- coding: latin-1 -*-\n", Line: 2, Column: 1},
+		{Key: "pragma", Value: "once", Text: "#pragma once\n", Line: 3, Column: 1},
+		{Key: "pragma", Value: "pack", Text: "#pragma pack\n", Line: 4, Column: 1},
+	}
+	if len(headers) != len(expected) {
+		t.Fatalf("Lexer.Headers() expecting %d headers, received %v", len(expected), headers)
+	}
+	for i, h := range headers {
+		if h != expected[i] {
+			t.Errorf("Lexer.Headers()[%d] expecting %v, received %v", i, expected[i], h)
+		}
+	}
+}
+
+// TestPrescanNoMatch
+//
+func TestPrescanNoMatch(t *testing.T) {
+	var headers []Header
+	fn := func(l *Lexer) Fn {
+		headers = l.Headers()
+		return lexAll(l)
+	}
+	// Shebang only at the start, encoding only on the first two lines
+	//
+	nexter := LexString(" #!x\n# coding\n", fn, WithPrescan(Shebang, Encoding("#")))
+	expectNexterNext(t, nexter, TStart, " #!x\n# coding\n", 1, 1)
+	expectNexterEOF(t, nexter)
+	if len(headers) != 0 {
+		t.Errorf("Lexer.Headers() expecting none, received %v", headers)
+	}
+}
+
+// TestPrescanAll
+//
+func TestPrescanAll(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		t.Error("Lexer function called after prescan consumed all input")
+		return nil
+	}
+	nexter := LexString("#!sh", fn, WithPrescan(Shebang))
+	expectNexterEOF(t, nexter)
+}
@@ -0,0 +1,280 @@
+package lexer
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// FnProfile reports the profile of a single lexer function.
+//
+type FnProfile struct {
+	Fn     string        // Name of the lexer function, as reported by runtime.FuncForPC
+	Calls  int64         // Number of calls to the function
+	Tokens int64         // Tokens emitted by the function, including errors and EOF
+	Time   time.Duration // Cumulative time spent in the function, extrapolated from the sampled calls
+}
+
+// TokenProfile reports the number of tokens emitted for a single token type.
+//
+type TokenProfile struct {
+	Type  token.Type
+	Count int64
+}
+
+// fnSamples accumulates the profile of a single lexer function.
+//
+type fnSamples struct {
+	calls   int64
+	tokens  int64
+	sampled int64         // Calls that were timed
+	elapsed time.Duration // Total time of the timed calls
+}
+
+// Profiler records per-token-type counts and per-function cumulative time, to help find hot spots in lexer rules.
+// To keep the overhead low, only one in every N calls to each function is timed (see NewProfiler), with the
+// cumulative time extrapolated from the timed calls.
+// Reports can be written as CSV (see WriteCSV) or as a pprof profile (see WritePprof).
+// See WithProfiler.
+//
+type Profiler struct {
+	every   int64                  // Time one in every N calls
+	fns     map[uintptr]*fnSamples // Keyed by function code pointer
+	tokens  map[token.Type]int64   // Tokens emitted, per type
+	current *fnSamples             // Function currently being called, if any
+	start   time.Time              // Start of the current call, if timed
+	created time.Time              // Start of the profile
+}
+
+// NewProfiler returns a new Profiler that times one in every n calls to each lexer function.
+// An n < 1 is treated as 1, timing every call.
+//
+func NewProfiler(n int) *Profiler {
+	if n < 1 {
+		n = 1
+	}
+	return &Profiler{
+		every:   int64(n),
+		fns:     make(map[uintptr]*fnSamples),
+		tokens:  make(map[token.Type]int64),
+		current: nil,
+		start:   time.Time{},
+		created: time.Now(),
+	}
+}
+
+// Fns returns the profile of each lexer function called, ordered by decreasing time, then name.
+//
+func (p *Profiler) Fns() []FnProfile {
+	profiles := make([]FnProfile, 0, len(p.fns))
+	for pc, s := range p.fns {
+		name := ""
+		if f := runtime.FuncForPC(pc); f != nil {
+			name = f.Name()
+		}
+		elapsed := s.elapsed
+		if s.sampled > 0 {
+			elapsed = time.Duration(int64(s.elapsed) * s.calls / s.sampled)
+		}
+		profiles = append(profiles, FnProfile{Fn: name, Calls: s.calls, Tokens: s.tokens, Time: elapsed})
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Time != profiles[j].Time {
+			return profiles[i].Time > profiles[j].Time
+		}
+		return profiles[i].Fn < profiles[j].Fn
+	})
+	return profiles
+}
+
+// Tokens returns the number of tokens emitted for each token type, ordered by type.
+//
+func (p *Profiler) Tokens() []TokenProfile {
+	profiles := make([]TokenProfile, 0, len(p.tokens))
+	for typ, n := range p.tokens {
+		profiles = append(profiles, TokenProfile{Type: typ, Count: n})
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Type < profiles[j].Type })
+	return profiles
+}
+
+// WriteCSV writes the profile to w as CSV, with columns "kind,name,count,tokens,nanos".
+// Functions are reported with kind "fn" (see Fns), with count being the number of calls.
+// Token types follow with kind "token" (see Tokens), named by their numeric type, leaving the tokens and nanos columns
+// empty.
+//
+func (p *Profiler) WriteCSV(w io.Writer) error {
+	c := csv.NewWriter(w)
+	_ = c.Write([]string{"kind", "name", "count", "tokens", "nanos"})
+	for _, f := range p.Fns() {
+		_ = c.Write([]string{"fn", f.Fn, strconv.FormatInt(f.Calls, 10), strconv.FormatInt(f.Tokens, 10), strconv.FormatInt(int64(f.Time), 10)})
+	}
+	for _, t := range p.Tokens() {
+		_ = c.Write([]string{"token", strconv.Itoa(int(t.Type)), strconv.FormatInt(t.Count, 10), "", ""})
+	}
+	c.Flush()
+	return c.Error()
+}
+
+// WritePprof writes the function profiles to w as a gzipped pprof profile (profile.proto), viewable via
+// `go tool pprof`.
+// Each function is reported as a single-frame sample, with "calls", "tokens" and "time" (nanoseconds) values.
+//
+func (p *Profiler) WritePprof(w io.Writer) error {
+	fns := p.Fns()
+	b := &protoBuffer{}
+	table := &stringTable{index: map[string]int64{}}
+	table.add("")
+	for _, st := range [][2]string{{"calls", "count"}, {"tokens", "count"}, {"time", "nanoseconds"}} {
+		vt := &protoBuffer{}
+		vt.int64(1, table.add(st[0]))
+		vt.int64(2, table.add(st[1]))
+		b.message(1, vt) // sample_type
+	}
+	for i, f := range fns {
+		id := uint64(i + 1)
+		sample := &protoBuffer{}
+		sample.packed(1, []uint64{id})                                                // location_id
+		sample.packed(2, []uint64{uint64(f.Calls), uint64(f.Tokens), uint64(f.Time)}) // value
+		b.message(2, sample)
+	}
+	for i, f := range fns {
+		id := uint64(i + 1)
+		line := &protoBuffer{}
+		line.uint64(1, id) // function_id
+		location := &protoBuffer{}
+		location.uint64(1, id)
+		location.message(4, line)
+		b.message(4, location)
+		function := &protoBuffer{}
+		function.uint64(1, id)
+		function.int64(2, table.add(f.Fn)) // name
+		function.int64(3, table.add(f.Fn)) // system_name
+		b.message(5, function)
+	}
+	for _, s := range table.list {
+		b.bytes(6, []byte(s)) // string_table
+	}
+	b.int64(9, p.created.UnixNano())          // time_nanos
+	b.int64(10, int64(time.Since(p.created))) // duration_nanos
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write(b.buf); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// enter notes the start of a call to fn, timing one in every N calls.
+//
+func (p *Profiler) enter(fn Fn) {
+	pc := reflect.ValueOf(fn).Pointer()
+	s, ok := p.fns[pc]
+	if !ok {
+		s = &fnSamples{}
+		p.fns[pc] = s
+	}
+	s.calls++
+	p.current = s
+	if (s.calls-1)%p.every == 0 {
+		p.start = time.Now()
+	} else {
+		p.start = time.Time{}
+	}
+}
+
+// exit notes the end of the current call.
+//
+func (p *Profiler) exit() {
+	if s := p.current; s != nil && !p.start.IsZero() {
+		s.sampled++
+		s.elapsed += time.Since(p.start)
+	}
+	p.current = nil
+}
+
+// count notes the emit of a token of type typ.
+//
+func (p *Profiler) count(typ token.Type) {
+	p.tokens[typ]++
+	if p.current != nil {
+		p.current.tokens++
+	}
+}
+
+// stringTable builds the pprof string table.
+//
+type stringTable struct {
+	list  []string
+	index map[string]int64
+}
+
+// add returns the index of s, adding it to the table if needed.
+//
+func (t *stringTable) add(s string) int64 {
+	if i, ok := t.index[s]; ok {
+		return i
+	}
+	t.index[s] = int64(len(t.list))
+	t.list = append(t.list, s)
+	return t.index[s]
+}
+
+// protoBuffer encodes protocol buffer messages, supporting just enough of the wire format for pprof profiles.
+//
+type protoBuffer struct {
+	buf []byte
+}
+
+// varint appends x as a varint.
+//
+func (b *protoBuffer) varint(x uint64) {
+	for x >= 0x80 {
+		b.buf = append(b.buf, byte(x)|0x80)
+		x >>= 7
+	}
+	b.buf = append(b.buf, byte(x))
+}
+
+// uint64 appends a varint field.
+//
+func (b *protoBuffer) uint64(field int, x uint64) {
+	b.varint(uint64(field) << 3)
+	b.varint(x)
+}
+
+// int64 appends a varint field.
+//
+func (b *protoBuffer) int64(field int, x int64) {
+	b.uint64(field, uint64(x))
+}
+
+// bytes appends a length-delimited field.
+//
+func (b *protoBuffer) bytes(field int, x []byte) {
+	b.varint(uint64(field)<<3 | 2)
+	b.varint(uint64(len(x)))
+	b.buf = append(b.buf, x...)
+}
+
+// packed appends a packed repeated varint field.
+//
+func (b *protoBuffer) packed(field int, xs []uint64) {
+	p := &protoBuffer{}
+	for _, x := range xs {
+		p.varint(x)
+	}
+	b.bytes(field, p.buf)
+}
+
+// message appends an embedded message field.
+//
+func (b *protoBuffer) message(field int, m *protoBuffer) {
+	b.bytes(field, m.buf)
+}
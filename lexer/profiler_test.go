@@ -0,0 +1,58 @@
+package lexer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+// TestWithProfiler
+//
+func TestWithProfiler(t *testing.T) {
+	p := NewProfiler(2)
+	nexter := LexString("ab cd", lexFields, WithProfiler(p))
+	expectNexterNext(t, nexter, TStart, "ab", 1, 1)
+	expectNexterNext(t, nexter, TStart, "cd", 1, 4)
+	expectNexterEOF(t, nexter)
+	fns := p.Fns()
+	if len(fns) != 1 || fns[0].Fn != "github.com/tekwizely/go-parsing/lexer.lexFields" || fns[0].Calls != 3 || fns[0].Tokens != 2 {
+		t.Fatalf("Profiler.Fns() expecting lexFields x3 with 2 tokens, received %v", fns)
+	}
+	if s := p.fns[reflect.ValueOf(lexFields).Pointer()]; s.sampled != 2 {
+		t.Errorf("Profiler expecting 2 sampled calls, received %d", s.sampled)
+	}
+	tokens := p.Tokens()
+	if len(tokens) != 2 || tokens[0] != (TokenProfile{Type: TEof, Count: 1}) || tokens[1] != (TokenProfile{Type: TStart, Count: 2}) {
+		t.Errorf("Profiler.Tokens() expecting [{2 1} {3 2}], received %v", tokens)
+	}
+	// CSV
+	//
+	b := &bytes.Buffer{}
+	if err := p.WriteCSV(b); err != nil {
+		t.Fatalf("Profiler.WriteCSV() returned error: %v", err)
+	}
+	records, err := csv.NewReader(b).ReadAll()
+	if err != nil {
+		t.Fatalf("Profiler.WriteCSV() wrote invalid CSV: %v", err)
+	}
+	if len(records) != 4 || records[1][0] != "fn" || records[1][2] != "3" || records[3][0] != "token" || records[3][1] != "3" || records[3][2] != "2" {
+		t.Errorf("Profiler.WriteCSV() unexpected records: %v", records)
+	}
+	// pprof
+	//
+	b.Reset()
+	if err = p.WritePprof(b); err != nil {
+		t.Fatalf("Profiler.WritePprof() returned error: %v", err)
+	}
+	gz, err := gzip.NewReader(b)
+	if err != nil {
+		t.Fatalf("Profiler.WritePprof() wrote invalid gzip: %v", err)
+	}
+	data, err := ioutil.ReadAll(gz)
+	if err != nil || !bytes.Contains(data, []byte("lexer.lexFields")) || !bytes.Contains(data, []byte("nanoseconds")) {
+		t.Errorf("Profiler.WritePprof() unexpected profile: %q, %v", data, err)
+	}
+}
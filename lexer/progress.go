@@ -0,0 +1,9 @@
+package lexer
+
+// progressInterval is the number of bytes read between progress reports.
+//
+const progressInterval = 64 * 1024
+
+// progressFn receives progress reports - see WithProgress.
+//
+type progressFn func(bytesRead, runesRead int64)
@@ -0,0 +1,41 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+// lexSkip consumes all runes without emitting
+//
+func lexSkip(l *Lexer) Fn {
+	l.Next()
+	l.Clear()
+	return lexSkip
+}
+
+// TestWithProgress
+//
+func TestWithProgress(t *testing.T) {
+	var reports [][2]int64
+	progress := func(bytesRead, runesRead int64) {
+		reports = append(reports, [2]int64{bytesRead, runesRead})
+	}
+	// 2-byte runes
+	//
+	input := strings.Repeat("é", progressInterval)
+	nexter := LexString(input, lexSkip, WithProgress(progress))
+	expectNexterEOF(t, nexter)
+	expected := [][2]int64{
+		{progressInterval, progressInterval / 2},
+		{2 * progressInterval, progressInterval},
+		{2 * progressInterval, progressInterval},
+	}
+	if len(reports) != len(expected) {
+		t.Fatalf("WithProgress expecting reports %v, received %v", expected, reports)
+	}
+	for i := range expected {
+		if reports[i] != expected[i] {
+			t.Errorf("WithProgress expecting report[%d] %v, received %v", i, expected[i], reports[i])
+		}
+	}
+}
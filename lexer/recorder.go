@@ -0,0 +1,108 @@
+package lexer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Transition records a switch to a lexer function, along with the input position at the time of the switch.
+// Consecutive calls to the same function are recorded as a single transition.
+//
+type Transition struct {
+	Fn    string         // Name of the lexer function, as reported by runtime.FuncForPC
+	Pos   token.Position // Starting position of the current match when the function was first entered
+	Calls int            // Number of consecutive calls to the function
+}
+
+// String returns the transition in dump format: "line:column fn xCalls".
+//
+func (t Transition) String() string {
+	return fmt.Sprintf("%s %s x%d", t.Pos, t.Fn, t.Calls)
+}
+
+// Recorder records the sequence of lexer function transitions, making grammar-flow bugs visible.
+// See WithRecorder.
+//
+type Recorder struct {
+	transitions []Transition
+}
+
+// Transitions returns the recorded transitions, in order.
+//
+func (r *Recorder) Transitions() []Transition {
+	return r.transitions
+}
+
+// Dump writes the recorded transitions to w, one per line, in the format described by Transition.String().
+// The output can be read back via ReadTransitions.
+//
+func (r *Recorder) Dump(w io.Writer) error {
+	for _, t := range r.transitions {
+		if _, err := fmt.Fprintln(w, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record notes a call to the named function.
+//
+func (r *Recorder) record(fn string, pos token.Position) {
+	if n := len(r.transitions); n > 0 && r.transitions[n-1].Fn == fn {
+		r.transitions[n-1].Calls++
+		return
+	}
+	r.transitions = append(r.transitions, Transition{Fn: fn, Pos: pos, Calls: 1})
+}
+
+// ReadTransitions reads transitions written by Recorder.Dump, allowing a recorded flow to be replayed (i.e. compared
+// against another run).
+// Blank lines are ignored.
+//
+func ReadTransitions(r io.Reader) ([]Transition, error) {
+	var transitions []Transition
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		t, err := parseTransition(line)
+		if err != nil {
+			return transitions, fmt.Errorf("line %d: %s", n, err)
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, scanner.Err()
+}
+
+// parseTransition parses a single line written by Recorder.Dump.
+//
+func parseTransition(line string) (Transition, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 || !strings.HasPrefix(fields[2], "x") {
+		return Transition{}, fmt.Errorf("invalid transition '%s'", line)
+	}
+	pos := strings.SplitN(fields[0], ":", 2)
+	if len(pos) != 2 {
+		return Transition{}, fmt.Errorf("invalid position '%s'", fields[0])
+	}
+	lineNo, err := strconv.Atoi(pos[0])
+	if err != nil {
+		return Transition{}, fmt.Errorf("invalid position '%s'", fields[0])
+	}
+	column, err := strconv.Atoi(pos[1])
+	if err != nil {
+		return Transition{}, fmt.Errorf("invalid position '%s'", fields[0])
+	}
+	calls, err := strconv.Atoi(fields[2][1:])
+	if err != nil {
+		return Transition{}, fmt.Errorf("invalid call count '%s'", fields[2])
+	}
+	return Transition{Fn: fields[1], Pos: token.Position{Line: lineNo, Column: column}, Calls: calls}, nil
+}
@@ -0,0 +1,76 @@
+package lexer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// lexRecordLetter emits each letter, switching to lexRecordDigit on digits
+//
+func lexRecordLetter(l *Lexer) Fn {
+	if r := l.Peek(1); r >= '0' && r <= '9' {
+		return lexRecordDigit
+	}
+	l.Next()
+	l.EmitToken(TStart)
+	return lexRecordLetter
+}
+
+// lexRecordDigit emits each digit, switching to lexRecordLetter on letters
+//
+func lexRecordDigit(l *Lexer) Fn {
+	if r := l.Peek(1); r < '0' || r > '9' {
+		return lexRecordLetter
+	}
+	l.Next()
+	l.EmitToken(TStart)
+	return lexRecordDigit
+}
+
+// TestWithRecorder
+//
+func TestWithRecorder(t *testing.T) {
+	r := &Recorder{}
+	nexter := LexString("ab12c", lexRecordLetter, WithRecorder(r))
+	for _, err := nexter.Next(); err == nil; _, err = nexter.Next() {
+	}
+	b := &bytes.Buffer{}
+	if err := r.Dump(b); err != nil {
+		t.Errorf("Recorder.Dump() received unexpected error '%s'", err)
+	}
+	dump := strings.Replace(b.String(), "github.com/tekwizely/go-parsing/lexer.", "", -1)
+	expected := "1:1 lexRecordLetter x3\n1:3 lexRecordDigit x3\n1:5 lexRecordLetter x1\n"
+	if dump != expected {
+		t.Errorf("Recorder.Dump() expecting:\n%s\nreceived:\n%s", expected, dump)
+	}
+	transitions, err := ReadTransitions(strings.NewReader(b.String()))
+	if err != nil {
+		t.Errorf("ReadTransitions() received unexpected error '%s'", err)
+	}
+	if len(transitions) != len(r.Transitions()) {
+		t.Fatalf("ReadTransitions() expecting %d transitions, received %d", len(r.Transitions()), len(transitions))
+	}
+	for i, tr := range transitions {
+		if tr != r.Transitions()[i] {
+			t.Errorf("ReadTransitions() expecting transition '%s', received '%s'", r.Transitions()[i], tr)
+		}
+	}
+}
+
+// TestReadTransitionsError
+//
+func TestReadTransitionsError(t *testing.T) {
+	transitions, err := ReadTransitions(strings.NewReader("1:1 fn x1\n\n1:x fn x2\n"))
+	if err == nil || err.Error() != "line 3: invalid position '1:x'" {
+		t.Errorf("ReadTransitions() expecting error 'line 3: invalid position '1:x'', received '%v'", err)
+	}
+	if len(transitions) != 1 || transitions[0] != (Transition{Fn: "fn", Pos: token.Position{Line: 1, Column: 1}, Calls: 1}) {
+		t.Errorf("ReadTransitions() expecting 1 transition, received %v", transitions)
+	}
+	if _, err := ReadTransitions(strings.NewReader("1:1 fn 1\n")); err == nil {
+		t.Error("ReadTransitions() expecting error for invalid call count")
+	}
+}
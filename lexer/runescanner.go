@@ -0,0 +1,42 @@
+package lexer
+
+import (
+	"errors"
+	"io"
+	"unicode/utf8"
+)
+
+// Lexer implements io.RuneScanner, allowing rune-based helpers from other libraries to scan the input directly.
+//
+var _ io.RuneScanner = (*Lexer)(nil)
+
+// errUnreadRune is returned by UnreadRune when there is no matched rune to unread.
+//
+var errUnreadRune = errors.New("Lexer.UnreadRune: no matched rune to unread")
+
+// ReadRune implements io.RuneScanner.ReadRune(), matching the next rune (see Next) and returning it along with its
+// UTF-8 encoded size.
+// Returns io.EOF if no rune is available, or if EOF already emitted.
+// Allows rune-based helpers from other libraries (i.e. number scanners) to be pointed directly at the lexer's input,
+// with the runes they consume becoming part of the current match.
+//
+func (l *Lexer) ReadRune() (rune, int, error) {
+	r, ok := l.TryNext()
+	if !ok {
+		return 0, 0, io.EOF
+	}
+	return r, utf8.RuneLen(r), nil
+}
+
+// UnreadRune implements io.RuneScanner.UnreadRune(), un-matching the last matched rune (see Truncate) so that it is
+// returned by the next call to ReadRune.
+// Unlike the io.RuneScanner contract, any matched rune can be unread, not just the one returned by the last ReadRune.
+// Returns an error if no runes are matched, or if EOF already emitted.
+//
+func (l *Lexer) UnreadRune() error {
+	if l.eofOut || l.matchLen == 0 {
+		return errUnreadRune
+	}
+	l.Truncate(1)
+	return nil
+}
@@ -0,0 +1,63 @@
+package lexer
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// scanDigits is a rune-based helper, standing in for helpers from other libraries, that reads digits from rs,
+// unreading the rune that ends them.
+//
+func scanDigits(rs io.RuneScanner) string {
+	b := &strings.Builder{}
+	for {
+		r, _, err := rs.ReadRune()
+		if err != nil {
+			return b.String()
+		}
+		if r < '0' || r > '9' {
+			_ = rs.UnreadRune()
+			return b.String()
+		}
+		b.WriteRune(r)
+	}
+}
+
+// TestRuneScanner
+//
+func TestRuneScanner(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		if digits := scanDigits(l); digits != "123" {
+			t.Errorf("scanDigits(Lexer) expecting '123', received '%s'", digits)
+		}
+		l.EmitToken(TStart)
+		r, size, err := l.ReadRune()
+		if r != 'é' || size != 2 || err != nil {
+			t.Errorf("Lexer.ReadRune() expecting ('é', 2, nil), received ('%c', %d, %v)", r, size, err)
+		}
+		if err = l.UnreadRune(); err != nil {
+			t.Errorf("Lexer.UnreadRune() received unexpected error '%v'", err)
+		}
+		if err = l.UnreadRune(); err != errUnreadRune {
+			t.Errorf("Lexer.UnreadRune() expecting error '%v', received '%v'", errUnreadRune, err)
+		}
+		expectNextString(t, l, "é")
+		if r, size, err = l.ReadRune(); r != 0 || size != 0 || err != io.EOF {
+			t.Errorf("Lexer.ReadRune() expecting (0, 0, EOF), received ('%c', %d, %v)", r, size, err)
+		}
+		l.EmitToken(TStart + 1)
+		l.EmitEOF()
+		if _, _, err = l.ReadRune(); err != io.EOF {
+			t.Errorf("Lexer.ReadRune() after EOF expecting EOF, received %v", err)
+		}
+		if err = l.UnreadRune(); err != errUnreadRune {
+			t.Errorf("Lexer.UnreadRune() after EOF expecting error '%v', received '%v'", errUnreadRune, err)
+		}
+		return nil
+	}
+	nexter := LexString("123é", fn)
+	expectNexterNext(t, nexter, TStart, "123", 1, 1)
+	expectNexterNext(t, nexter, TStart+1, "é", 1, 4)
+	expectNexterEOF(t, nexter)
+}
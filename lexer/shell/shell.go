@@ -0,0 +1,271 @@
+/*
+Package shell lexes shell-like input into words, handling quotes, backslash escapes and variable references.
+
+The lexer emits the segments of each word, separated by whitespace (TSpace) tokens.
+Literal segments (TWord) carry their text, with quotes and escapes removed, as their cooked value (see token.Cooked),
+while variable references (TVar / TQuotedVar) carry the variable name:
+
+	tokens := lexer.LexString(`cp "$HOME/my file" ${DEST}`, shell.Start())
+
+	TWord      `cp`            "cp"
+	TSpace     ` `
+	TWord      `"`             ""
+	TQuotedVar `$HOME`         "HOME"
+	TWord      `/my file"`     "/my file"
+	TSpace     ` `
+	TVar       `${DEST}`       "DEST"
+
+Adjacent segments belong to the same word. Split joins them, expanding variables along the way:
+
+	words, err := shell.Split(`cp "$HOME/my file" ${DEST}`, os.Getenv)
+
+The supported syntax:
+
+  - Single quotes : Everything up to the closing quote is literal
+  - Double quotes : Backslash only escapes '$', '`', '"', '\' and newline; Variables are expanded
+  - Backslash     : Outside of quotes, escapes the next rune; A backslash-newline (line continuation) is removed
+  - Variables     : $name, ${name}, and the special parameters $0-$9, $?, $#, $$, $@, $*, $! and $-
+
+A '$' that does not start a variable reference is taken literally.
+Unterminated quotes, escapes and line continuations (at the end of the input) are reported as errors, which are
+incomplete in interactive mode (see lexer.WithInteractive), allowing REPLs to prompt for more input.
+
+*/
+package shell
+
+import (
+	"strings"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Token types emitted by the shell lexer.
+//
+const (
+	TSpace     token.Type = lexer.TStart + iota // Whitespace between words
+	TWord                                       // Literal word segment; Cooked value is the unquoted text
+	TVar                                        // Variable reference, i.e. $HOME or ${HOME}; Cooked value is the name
+	TQuotedVar                                  // Variable reference within double quotes; Cooked value is the name
+)
+
+// specials are the special parameters, referenced as $<rune>.
+//
+const specials = "0123456789?#$@*!-"
+
+// Start returns the start function of a new shell lexer.
+// The function carries the state of the word being lexed, so use a new one for each lexer.
+//
+func Start() lexer.Fn {
+	s := &scanner{}
+	return s.lexWord
+}
+
+// scanner holds the state of the word segment being lexed.
+//
+type scanner struct {
+	cooked  strings.Builder // Unquoted text of the segment
+	pending bool            // Have runes been matched for the segment?
+}
+
+// next matches the next rune, adding it to the segment.
+//
+func (s *scanner) next(l *lexer.Lexer) rune {
+	s.pending = true
+	return l.Next()
+}
+
+// flush emits the segment, if any runes have been matched for it.
+//
+func (s *scanner) flush(l *lexer.Lexer) {
+	if s.pending {
+		l.EmitCooked(TWord, s.cooked.String())
+		s.cooked.Reset()
+		s.pending = false
+	}
+}
+
+// lexWord lexes whitespace and unquoted word segments.
+//
+func (s *scanner) lexWord(l *lexer.Lexer) lexer.Fn {
+	if !s.pending && isSpace(l.Peek(1)) {
+		for l.CanPeek(1) && isSpace(l.Peek(1)) {
+			l.Next()
+		}
+		l.EmitToken(TSpace)
+		return s.lexWord
+	}
+	for l.CanPeek(1) {
+		switch r := l.Peek(1); r {
+		case ' ', '\t', '\n':
+			s.flush(l)
+			return s.lexWord
+		case '\'':
+			s.next(l)
+			for l.CanPeek(1) && l.Peek(1) != '\'' {
+				s.cooked.WriteRune(l.Next())
+			}
+			if !l.CanPeek(1) {
+				l.EmitError("unterminated single quote")
+				return nil
+			}
+			l.Next()
+		case '"':
+			s.next(l)
+			return s.lexDouble
+		case '\\':
+			pending := s.pending
+			s.next(l)
+			if !l.CanPeek(1) {
+				l.EmitError("unterminated escape")
+				return nil
+			}
+			if e := l.Next(); e != '\n' {
+				s.cooked.WriteRune(e)
+			} else if !l.CanPeek(1) {
+				l.EmitError("unterminated line continuation")
+				return nil
+			} else if !pending {
+				// Line continuation at the start of a segment, discard it
+				//
+				l.Clear()
+				s.pending = false
+			}
+		case '$':
+			if s.variable(l, TVar) {
+				return s.lexWord
+			}
+			s.cooked.WriteRune(s.next(l))
+		default:
+			s.cooked.WriteRune(s.next(l))
+		}
+	}
+	s.flush(l)
+	return nil
+}
+
+// lexDouble lexes the inside of a double-quoted string, up to and including the closing quote.
+//
+func (s *scanner) lexDouble(l *lexer.Lexer) lexer.Fn {
+	for l.CanPeek(1) {
+		switch r := l.Peek(1); r {
+		case '"':
+			s.next(l)
+			if !l.CanPeek(1) {
+				s.flush(l)
+				return nil
+			}
+			return s.lexWord
+		case '\\':
+			s.next(l)
+			if !l.CanPeek(1) {
+				break
+			}
+			if e := l.Peek(1); strings.ContainsRune("$`\"\\\n", e) {
+				if l.Next() != '\n' {
+					s.cooked.WriteRune(e)
+				}
+			} else {
+				s.cooked.WriteRune(r) // Not an escape, keep the backslash
+			}
+		case '$':
+			if s.variable(l, TQuotedVar) {
+				return s.lexDouble
+			}
+			s.cooked.WriteRune(s.next(l))
+		default:
+			s.cooked.WriteRune(s.next(l))
+		}
+	}
+	l.EmitError("unterminated double quote")
+	return nil
+}
+
+// variable matches a variable reference, emitting it (with its name as the cooked value) after the pending segment.
+// Returns false, with no runes matched, if the '$' does not start a variable reference.
+//
+func (s *scanner) variable(l *lexer.Lexer, typ token.Type) bool {
+	s.flush(l)
+	m := l.Marker()
+	l.Next()
+	switch {
+	case !l.CanPeek(1):
+	case l.Peek(1) == '{':
+		l.Next()
+		if lexer.ScanIdent(l, lexer.IdentC) && l.CanPeek(1) && l.Peek(1) == '}' {
+			l.Next()
+			name := l.PeekToken()
+			l.EmitCooked(typ, name[2:len(name)-1])
+			return true
+		}
+	case lexer.ScanIdent(l, lexer.IdentC):
+		l.EmitCooked(typ, l.PeekToken()[1:])
+		return true
+	case strings.ContainsRune(specials, l.Peek(1)):
+		l.EmitCooked(typ, string(l.Next()))
+		return true
+	}
+	m.Apply()
+	return false
+}
+
+// Split splits shell-like input into words, returning the first error encountered, if any.
+// Variables are expanded via env (i.e. os.Getenv), with the values of unquoted variables split on whitespace, as a
+// shell would.
+// If env is nil, variable references are kept as written.
+// Options are passed to the lexer, i.e. lexer.WithInteractive().
+//
+func Split(input string, env func(string) string, opts ...lexer.Option) ([]string, error) {
+	tokens, err := lexer.LexAll(input, Start(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	var words []string
+	word := &strings.Builder{}
+	inWord := false
+	end := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+	for _, tok := range tokens {
+		switch {
+		case tok.Type() == TSpace:
+			end()
+		case tok.Type() == TWord:
+			word.WriteString(token.Cooked(tok).(string))
+			inWord = true
+		case env == nil:
+			word.WriteString(tok.Value())
+			inWord = true
+		case tok.Type() == TQuotedVar:
+			word.WriteString(env(token.Cooked(tok).(string)))
+			inWord = true
+		case tok.Type() == TVar:
+			value := env(token.Cooked(tok).(string))
+			if strings.TrimLeftFunc(value, isSpace) != value {
+				end()
+			}
+			for i, field := range strings.FieldsFunc(value, isSpace) {
+				if i > 0 {
+					end()
+				}
+				word.WriteString(field)
+				inWord = true
+			}
+			if strings.TrimRightFunc(value, isSpace) != value {
+				end()
+			}
+		}
+	}
+	end()
+	return words, nil
+}
+
+// isSpace
+//
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n'
+}
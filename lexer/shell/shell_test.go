@@ -0,0 +1,129 @@
+package shell
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestLex
+//
+func TestLex(t *testing.T) {
+	tokens, err := lexer.LexAll(`cp "$HOME/my file" ${DEST}`, Start())
+	if err != nil {
+		t.Fatalf("LexAll() unexpected error: %v", err)
+	}
+	expected := []struct {
+		typ    token.Type
+		value  string
+		cooked interface{}
+	}{
+		{TWord, `cp`, "cp"},
+		{TSpace, ` `, nil},
+		{TWord, `"`, ""},
+		{TQuotedVar, `$HOME`, "HOME"},
+		{TWord, `/my file"`, "/my file"},
+		{TSpace, ` `, nil},
+		{TVar, `${DEST}`, "DEST"},
+	}
+	if len(tokens) != len(expected) {
+		t.Fatalf("LexAll() expecting %d tokens, received %d", len(expected), len(tokens))
+	}
+	for i, e := range expected {
+		tok := tokens[i]
+		if tok.Type() != e.typ || tok.Value() != e.value || token.Cooked(tok) != e.cooked {
+			t.Errorf("token %d expecting {%v '%s' %v}, received {%v '%s' %v}", i, e.typ, e.value, e.cooked, tok.Type(),
+				tok.Value(), token.Cooked(tok))
+		}
+	}
+}
+
+// TestSplit
+//
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{``, nil},
+		{`  a  b	c `, []string{"a", "b", "c"}},
+		{`'a b' "c d"`, []string{"a b", "c d"}},
+		{`a'b c'd`, []string{"ab cd"}},
+		{`'' ""`, []string{"", ""}},
+		{`a\ b \'c\"`, []string{"a b", `'c"`}},
+		{`"\$x \"y\" \\ \z"`, []string{`$x "y" \ \z`}},
+		{`'$x \n'`, []string{`$x \n`}},
+		{"a\\\nb \\\n c", []string{"ab", "c"}},
+		{"\"a\\\nb\"", []string{"ab"}},
+		{`$x ${y}z "$x" $ a$ ${ ${1x}`, []string{`$x`, `${y}z`, `$x`, `$`, `a$`, `${`, `${1x}`}},
+	}
+	for _, test := range tests {
+		words, err := Split(test.input, nil)
+		if err != nil {
+			t.Errorf("Split(%q) unexpected error: %v", test.input, err)
+		} else if !reflect.DeepEqual(words, test.expected) {
+			t.Errorf("Split(%q) expecting %q, received %q", test.input, test.expected, words)
+		}
+	}
+}
+
+// TestSplitEnv
+//
+func TestSplitEnv(t *testing.T) {
+	env := map[string]string{"A": "x", "B": " b1  b2 ", "C": "c1 c2", "1": "one", "?": "0"}
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{`$A ${A}y "$A"`, []string{"x", "xy", "x"}},
+		{`a$B"z"`, []string{"a", "b1", "b2", "z"}},
+		{`a${C}z`, []string{"ac1", "c2z"}},
+		{`"a $C z"`, []string{"a c1 c2 z"}},
+		{`$1$? $missing "$missing"`, []string{"one0", ""}},
+	}
+	for _, test := range tests {
+		words, err := Split(test.input, func(name string) string { return env[name] })
+		if err != nil {
+			t.Errorf("Split(%q) unexpected error: %v", test.input, err)
+		} else if !reflect.DeepEqual(words, test.expected) {
+			t.Errorf("Split(%q) expecting %q, received %q", test.input, test.expected, words)
+		}
+	}
+}
+
+// TestSplitError
+//
+func TestSplitError(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`a 'b`, "unterminated single quote"},
+		{`a "b`, "unterminated double quote"},
+		{`a "b\`, "unterminated double quote"},
+		{`a b\`, "unterminated escape"},
+		{"a b\\\n", "unterminated line continuation"},
+	}
+	for _, test := range tests {
+		words, err := Split(test.input, nil)
+		if err == nil {
+			t.Errorf("Split(%q) expecting error, received %q", test.input, words)
+		} else if !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("Split(%q) expecting error '%s', received '%v'", test.input, test.expected, err)
+		}
+	}
+}
+
+// TestInteractive
+//
+func TestInteractive(t *testing.T) {
+	for _, input := range []string{`echo "a`, `echo 'a`, `echo a\`, "echo a\\\n"} {
+		_, err := Split(input, nil, lexer.WithInteractive())
+		if !token.IsIncomplete(err) {
+			t.Errorf("Split(%q) expecting incomplete error, received %v", input, err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package lexer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// snapshotVersion identifies the snapshot format, allowing future formats to reject older snapshots.
+//
+const snapshotVersion = 1
+
+// lexerSnapshot is the serialized form of the lexer state - see Snapshot.
+//
+type lexerSnapshot struct {
+	Version   int
+	Runes     string         // Cached runes, matched runes first
+	MatchLen  int            // Number of matched runes at the front of Runes
+	Gaps      map[int]string // Runes discarded via Skip/ClearTo, keyed by the index of the preceding cached rune
+	Line      int
+	Column    int
+	PrevRune  rune `json:",omitempty"` // Last rune advanced over, for recognizing "\r\n"
+	EOF       bool // Has EOF been reached on the input?
+	EOFOut    bool // Has EOF been emitted?
+	Output    []snapshotToken
+	Flushed   bool
+	LastOut   *snapshotToken `json:",omitempty"`
+	BytesRead int64
+	RunesRead int64
+	ErrCount  int
+	OutCount  int      // Tokens emitted, for the token limit - see WithMaxTokens()
+	Headers   []Header `json:",omitempty"`
+	Scanned   bool
+}
+
+// snapshotToken is the serialized form of an emitted token.
+//
+type snapshotToken struct {
+	Type    int
+	Value   string
+	Line    int
+	Column  int
+	End     token.Position // Position following the value - see token.Ender
+	Channel int            `json:",omitempty"`
+	Error   *Error         `json:",omitempty"` // See EmitErrorToken()
+	Partial bool           `json:",omitempty"` // See WithInteractive()
+}
+
+// newSnapshotToken
+//
+func newSnapshotToken(t *_token) snapshotToken {
+	s := snapshotToken{Type: int(t.typ), Value: t.Value(), Line: t.line, Column: t.column, End: t.end, Channel: int(t.channel)}
+	if t.err != nil {
+		s.Error = t.err
+		s.Partial = t.err.incomplete
+	}
+	return s
+}
+
+// token restores the emitted token.
+//
+func (t snapshotToken) token() *_token {
+	tok := newToken(token.Type(t.Type), t.Value, t.Line, t.Column)
+	tok.end = t.End
+	tok.channel = token.Channel(t.Channel)
+	tok.err = t.Error
+	if tok.err != nil {
+		tok.err.incomplete = t.Partial
+	}
+	return tok
+}
+
+// Snapshot captures the state of the lexer, allowing long-running interactive sessions (REPLs, notebook kernels) to
+// checkpoint lexing and resume it later, possibly in another process, via RestoreSnapshot.
+// The snapshot includes the buffered input (matched and peeked runes), the line/column position, and any tokens
+// emitted but not yet delivered.
+// Lexer functions, the user context and the options are not captured, and are supplied again when restoring.
+// Input not yet read by the lexer is not captured either; it is the caller's responsibility to resume the input from
+// where the lexer left off.
+//
+func (l *Lexer) Snapshot() ([]byte, error) {
+	s := &lexerSnapshot{
+		Version:   snapshotVersion,
+		MatchLen:  l.matchLen,
+		Line:      l.line,
+		Column:    l.column,
+		PrevRune:  l.prevRune,
+		EOF:       l.eof,
+		EOFOut:    l.eofOut,
+		Flushed:   l.flushed,
+		BytesRead: l.bytesRead,
+		RunesRead: l.runesRead,
+		ErrCount:  l.errCount,
+		OutCount:  l.outCount,
+		Headers:   l.headers,
+		Scanned:   l.scanned,
+	}
+	runes := make([]rune, 0, l.cache.Len())
+	for i, e := 0, l.cache.Front(); e != nil; i, e = i+1, e.Next() {
+		runes = append(runes, e.Value.(rune))
+		if gap, ok := l.gaps[e]; ok {
+			if s.Gaps == nil {
+				s.Gaps = make(map[int]string)
+			}
+			s.Gaps[i] = gap
+		}
+	}
+	s.Runes = string(runes)
+	for i := 0; i < l.output.Len(); i++ {
+		s.Output = append(s.Output, newSnapshotToken(l.output.items[(l.output.head+i)%len(l.output.items)]))
+	}
+	if l.lastOut != nil {
+		t := newSnapshotToken(l.lastOut)
+		s.LastOut = &t
+	}
+	return json.Marshal(s)
+}
+
+// RestoreSnapshot restores the lexer state captured by Snapshot, resetting the lexer to resume lexing from the
+// snapshot, with the specified input and function.
+// The input provides the runes following those read by the lexer when the snapshot was taken, and is ignored if the
+// lexer had already reached the end of its input.
+// As with Reset, options applied at creation remain in effect, the user context is restored to its initial value (see
+// WithContext), and all outstanding markers are invalidated.
+// Returns an error, leaving the lexer unchanged, if the snapshot is invalid.
+//
+func (l *Lexer) RestoreSnapshot(data []byte, input io.RuneReader, next Fn) error {
+	s := &lexerSnapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return fmt.Errorf("Lexer.RestoreSnapshot: %v", err)
+	}
+	if s.Version != snapshotVersion {
+		return fmt.Errorf("Lexer.RestoreSnapshot: unsupported snapshot version %d", s.Version)
+	}
+	runes := []rune(s.Runes)
+	if s.MatchLen < 0 || s.MatchLen > len(runes) {
+		return fmt.Errorf("Lexer.RestoreSnapshot: match length %d out of range", s.MatchLen)
+	}
+	for i := range s.Gaps {
+		if i < 0 || i >= len(runes) {
+			return fmt.Errorf("Lexer.RestoreSnapshot: gap index %d out of range", i)
+		}
+	}
+	l.Reset(input, next)
+	for i, r := range runes {
+		e := l.cache.PushBack(r)
+		if i < s.MatchLen {
+			l.matchTail = e
+		}
+		if gap, ok := s.Gaps[i]; ok {
+			l.addGap(e, gap)
+		}
+	}
+	l.matchLen = s.MatchLen
+	l.line = s.Line
+	l.column = s.Column
+	l.headers = s.Headers
+	l.scanned = s.Scanned
+	l.prevRune = s.PrevRune
+	l.eof = s.EOF
+	l.eofOut = s.EOFOut
+	for _, t := range s.Output {
+		l.output.PushBack(t.token())
+	}
+	// Nexters stop once EOF is delivered, re-deliver it if needed
+	//
+	if l.eofOut && l.output.Len() == 0 {
+		l.output.PushBack(newToken(TEof, "", l.line, l.column))
+	}
+	l.flushed = s.Flushed
+	if s.LastOut != nil {
+		l.lastOut = s.LastOut.token()
+	}
+	l.bytesRead = s.BytesRead
+	l.runesRead = s.RunesRead
+	l.reportAt = l.bytesRead + progressInterval
+	l.errCount = s.ErrCount
+	l.outCount = s.OutCount
+	return nil
+}
+
+// LexSnapshot initiates a lexer that resumes lexing from a snapshot (see Lexer.Snapshot), against the remaining input
+// io.RuneReader, starting with the specified function.
+// The returned token.Nexter can be used to retrieve emitted tokens, starting with any tokens emitted but not yet
+// delivered when the snapshot was taken.
+// Returns an error if the snapshot is invalid.
+//
+func LexSnapshot(data []byte, input io.RuneReader, next Fn, opts ...Option) (token.Nexter, error) {
+	l := newLexer(input, next, opts)
+	if err := l.RestoreSnapshot(data, input, next); err != nil {
+		return nil, err
+	}
+	return &tokenNexter{lexer: l}, nil
+}
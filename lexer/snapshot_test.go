@@ -0,0 +1,87 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSnapshot
+//
+func TestSnapshot(t *testing.T) {
+	var data []byte
+	var err error
+	fn := func(l *Lexer) Fn {
+		l.Next()
+		l.Next()
+		l.EmitToken(TStart) // Pending
+		l.Next()
+		l.Clear()
+		l.Next()
+		l.CanPeek(2) // Buffer "d "
+		data, err = l.Snapshot()
+		return nil
+	}
+	nexter := LexString("ab cd ef", fn)
+	expectNexterNext(t, nexter, TStart, "ab", 1, 1)
+	if err != nil {
+		t.Fatalf("Lexer.Snapshot() returned error: %v", err)
+	}
+	// Resume with the input not yet read
+	//
+	nexter, err = LexSnapshot(data, strings.NewReader("ef"), lexFields)
+	if err != nil {
+		t.Fatalf("LexSnapshot() returned error: %v", err)
+	}
+	expectNexterNext(t, nexter, TStart, "ab", 1, 1)
+	expectNexterNext(t, nexter, TStart, "cd", 1, 4)
+	expectNexterNext(t, nexter, TStart, "ef", 1, 7)
+	expectNexterEOF(t, nexter)
+	// Tokens emitted before the snapshot count towards the token limit
+	//
+	nexter, err = LexSnapshot(data, strings.NewReader("ef"), lexFields, WithMaxTokens(2))
+	if err != nil {
+		t.Fatalf("LexSnapshot() returned error: %v", err)
+	}
+	expectNexterNext(t, nexter, TStart, "ab", 1, 1)
+	expectNexterNext(t, nexter, TStart, "cd", 1, 4)
+	expectNexterError(t, nexter, "1:9: too many tokens: limit of 2 exceeded")
+	expectNexterEOF(t, nexter)
+}
+
+// TestSnapshotAfterEOF
+//
+func TestSnapshotAfterEOF(t *testing.T) {
+	var data []byte
+	fn := func(l *Lexer) Fn {
+		l.Next()
+		l.EmitEOF()
+		data, _ = l.Snapshot()
+		return nil
+	}
+	expectNexterEOF(t, LexString("a", fn))
+	nexter, err := LexSnapshot(data, strings.NewReader("b"), lexFields)
+	if err != nil {
+		t.Fatalf("LexSnapshot() returned error: %v", err)
+	}
+	expectNexterEOF(t, nexter)
+}
+
+// TestSnapshotInvalid
+//
+func TestSnapshotInvalid(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{`{`, "Lexer.RestoreSnapshot: unexpected end of JSON input"},
+		{`{"Version":0}`, "Lexer.RestoreSnapshot: unsupported snapshot version 0"},
+		{`{"Version":1,"Runes":"a","MatchLen":2}`, "Lexer.RestoreSnapshot: match length 2 out of range"},
+		{`{"Version":1,"Runes":"a","Gaps":{"1":" "}}`, "Lexer.RestoreSnapshot: gap index 1 out of range"},
+	}
+	for _, test := range tests {
+		_, err := LexSnapshot([]byte(test.data), strings.NewReader(""), lexFields)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("LexSnapshot(%s) expecting error '%s', received '%v'", test.data, test.err, err)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+/*
+Package source provides a shared position space for multi-file inputs.
+
+Each File added to a Set is assigned a stable ID, along with a range of positions (Pos) within the set, allowing a
+single integer to identify a location in any file:
+
+	files := source.NewSet()
+	main := files.AddFile("main.src", mainText)
+	tokens := lexer.LexSource(main, lexStart)
+
+Virtual files, such as included files and macro expansions, record the position they were produced from, allowing
+diagnostics to report the full include / expansion stack:
+
+	inc := files.AddVirtual("util.src", utilText, source.PosOf(includeToken))
+	...
+	for _, p := range files.Stack(source.PosOf(tok)) {
+		fmt.Println(p) // i.e. "util.src:3:14", then "main.src:1:10"
+	}
+
+Positions are stable: Adding files never changes the positions (or IDs) of the files already in the set.
+
+*/
+package source
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Pos is a compact position within a Set, identifying both a file and a byte offset within it.
+// The zero value, NoPos, identifies no position.
+//
+type Pos int
+
+// NoPos is the zero Pos, identifying no position.
+//
+const NoPos Pos = 0
+
+// IsValid confirms if the position is not NoPos.
+//
+func (p Pos) IsValid() bool {
+	return p != NoPos
+}
+
+// Position is the expanded form of a Pos.
+// Lines and columns are 1-based, with columns counting runes (the lexer default).
+//
+type Position struct {
+	File   string // File name, possibly empty
+	ID     int    // File ID, 0 if the position is invalid
+	Offset int    // Byte offset within the file, 0-based
+	Line   int    // Line number, 1-based
+	Column int    // Column number, 1-based
+}
+
+// IsValid confirms if the position identifies a file.
+//
+func (p Position) IsValid() bool {
+	return p.ID > 0
+}
+
+// String returns the position formatted as "file:line:column", or "line:column" if the file is unnamed.
+// Returns "-" if the position is invalid.
+//
+func (p Position) String() string {
+	switch {
+	case !p.IsValid():
+		return "-"
+	case p.File == "":
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// File is a single input within a Set.
+// Files are immutable once added.
+//
+type File struct {
+	id      int
+	name    string
+	base    Pos    // Pos of offset 0
+	content string // File content
+	lines   []int  // Byte offset of the start of each line, lines[0] is line 1
+	from    Pos    // Position the file was produced from, NoPos if not virtual
+}
+
+// ID returns the ID of the file, unique within its Set.
+// IDs are assigned sequentially, starting from 1.
+//
+func (f *File) ID() int {
+	return f.id
+}
+
+// Name returns the name of the file.
+//
+func (f *File) Name() string {
+	return f.name
+}
+
+// Content returns the content of the file.
+//
+func (f *File) Content() string {
+	return f.content
+}
+
+// Size returns the size of the file content, in bytes.
+//
+func (f *File) Size() int {
+	return len(f.content)
+}
+
+// Base returns the Pos of the start of the file.
+//
+func (f *File) Base() Pos {
+	return f.base
+}
+
+// From returns the position the file was produced from (see Set.AddVirtual).
+// Returns NoPos if the file is not virtual.
+//
+func (f *File) From() Pos {
+	return f.from
+}
+
+// Pos returns the Pos of the specified byte offset.
+// Panics if the offset is not within the file (offset == Size() is allowed, representing the end of the file).
+//
+func (f *File) Pos(offset int) Pos {
+	if offset < 0 || offset > len(f.content) {
+		panic(fmt.Sprintf("source.File.Pos: offset %d out of range [0, %d]", offset, len(f.content)))
+	}
+	return f.base + Pos(offset)
+}
+
+// Offset returns the byte offset of the specified 1-based line/column position, with columns counting runes.
+// Invalid runes are not counted, matching the lexer.
+// Positions beyond the end of a line are clamped to the end of the line (before the newline), and positions beyond
+// the end of the file are clamped to the end of the file.
+//
+func (f *File) Offset(line int, column int) int {
+	if line < 1 {
+		return 0
+	}
+	if line > len(f.lines) {
+		return len(f.content)
+	}
+	offset := f.lines[line-1]
+	for col := 1; offset < len(f.content) && f.content[offset] != '\n'; {
+		r, size := utf8.DecodeRuneInString(f.content[offset:])
+		if r == utf8.RuneError && size == 1 {
+			offset++ // Skip invalid runes
+			continue
+		}
+		if col == column {
+			break
+		}
+		offset += size
+		col++
+	}
+	return offset
+}
+
+// Position returns the expanded form of the specified byte offset.
+//
+func (f *File) Position(offset int) Position {
+	line := sort.Search(len(f.lines), func(i int) bool { return f.lines[i] > offset })
+	column := 1
+	for i := f.lines[line-1]; i < offset; {
+		r, size := utf8.DecodeRuneInString(f.content[i:])
+		i += size
+		if r != utf8.RuneError || size > 1 {
+			column++
+		}
+	}
+	return Position{File: f.name, ID: f.id, Offset: offset, Line: line, Column: column}
+}
+
+// Set is a collection of files sharing a single position space.
+// A Set is not safe for concurrent modification.
+//
+type Set struct {
+	files []*File
+	next  Pos // Base of the next file added
+}
+
+// NewSet returns a new, empty, Set.
+//
+func NewSet() *Set {
+	return &Set{files: nil, next: 1}
+}
+
+// AddFile adds a file with the specified name and content to the set, returning the new file.
+//
+func (s *Set) AddFile(name string, content string) *File {
+	return s.AddVirtual(name, content, NoPos)
+}
+
+// AddVirtual adds a virtual file with the specified name and content to the set, recording the position it was
+// produced from (i.e. the include directive or macro use), and returns the new file.
+// See Stack.
+//
+func (s *Set) AddVirtual(name string, content string, from Pos) *File {
+	f := &File{
+		id:      len(s.files) + 1,
+		name:    name,
+		base:    s.next,
+		content: content,
+		lines:   lineStarts(content),
+		from:    from,
+	}
+	s.files = append(s.files, f)
+	s.next += Pos(len(content) + 1) // +1 so the end of the file has a unique Pos
+	return f
+}
+
+// Files returns the files in the set, in the order they were added.
+//
+func (s *Set) Files() []*File {
+	return s.files
+}
+
+// FileByID returns the file with the specified ID.
+// Returns nil if no such file exists.
+//
+func (s *Set) FileByID(id int) *File {
+	if id < 1 || id > len(s.files) {
+		return nil
+	}
+	return s.files[id-1]
+}
+
+// File returns the file containing the specified position.
+// Returns nil if the position is not within any file.
+//
+func (s *Set) File(pos Pos) *File {
+	if pos < 1 || pos >= s.next {
+		return nil
+	}
+	i := sort.Search(len(s.files), func(i int) bool { return s.files[i].base > pos })
+	return s.files[i-1]
+}
+
+// Position returns the expanded form of the specified position.
+// Returns the zero Position if the position is not within any file.
+//
+func (s *Set) Position(pos Pos) Position {
+	if f := s.File(pos); f != nil {
+		return f.Position(int(pos - f.base))
+	}
+	return Position{}
+}
+
+// Stack returns the expanded form of the specified position, followed by the positions its file was produced from
+// (see AddVirtual), innermost first, i.e. the include stack.
+// Returns nil if the position is not within any file.
+//
+func (s *Set) Stack(pos Pos) []Position {
+	var stack []Position
+	for f := s.File(pos); f != nil; f = s.File(pos) {
+		stack = append(stack, f.Position(int(pos-f.base)))
+		if f.from >= f.base {
+			break // Guard against self-referencing files
+		}
+		pos = f.from
+	}
+	return stack
+}
+
+// lineStarts returns the byte offset of the start of each line in the content.
+//
+func lineStarts(content string) []int {
+	lines := []int{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+// Positioner is implemented by tokens that know their position within a Set.
+// See File.Tokens.
+//
+type Positioner interface {
+
+	// Pos returns the position of the token within its Set.
+	//
+	Pos() Pos
+}
+
+// PosOf returns the position of tok within its Set.
+// Returns NoPos if tok does not implement Positioner.
+//
+func PosOf(tok token.Token) Pos {
+	if p, ok := tok.(Positioner); ok {
+		return p.Pos()
+	}
+	return NoPos
+}
+
+// Tokens wraps the token stream lexed from the file, such that each token implements Positioner.
+// Token line/column positions are assumed to be relative to the file, with columns counting runes (the lexer
+// default).
+// Other than Pos, tokens are unchanged, and retain any origin (see token.Originer).
+//
+func (f *File) Tokens(tokens token.Nexter) token.Nexter {
+	return &fileNexter{file: f, tokens: tokens}
+}
+
+// fileNexter is the token.Nexter returned by File.Tokens.
+//
+type fileNexter struct {
+	file   *File
+	tokens token.Nexter
+}
+
+// Next implements token.Nexter.Next().
+//
+func (n *fileNexter) Next() (token.Token, error) {
+	tok, err := n.tokens.Next()
+	if tok == nil {
+		return tok, err
+	}
+	pos := n.file.Pos(n.file.Offset(tok.Line(), tok.Column()))
+	return &fileToken{Token: tok, pos: pos}, err
+}
+
+// fileToken is a token with a position within a Set.
+//
+type fileToken struct {
+	token.Token
+	pos Pos
+}
+
+// Pos implements Positioner.Pos().
+//
+func (t *fileToken) Pos() Pos {
+	return t.pos
+}
+
+// End implements token.Ender.End(), passing through the end of the wrapped token (see token.End).
+//
+func (t *fileToken) End() token.Position {
+	return token.End(t.Token)
+}
+
+// Origin implements token.Originer.Origin(), passing through the origin of the wrapped token.
+//
+func (t *fileToken) Origin() token.Token {
+	return token.Origin(t.Token)
+}
@@ -0,0 +1,121 @@
+package source
+
+import (
+	"io"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestSetPositions
+//
+func TestSetPositions(t *testing.T) {
+	s := NewSet()
+	a := s.AddFile("a.src", "ab\ncd")
+	b := s.AddFile("b.src", "éx\n")
+	if a.ID() != 1 || b.ID() != 2 || s.FileByID(2) != b || s.FileByID(3) != nil {
+		t.Error("File IDs expecting 1, 2")
+	}
+	for _, test := range []struct {
+		pos      Pos
+		expected string
+	}{
+		{a.Pos(0), "a.src:1:1"},
+		{a.Pos(4), "a.src:2:2"},
+		{a.Pos(5), "a.src:2:3"},
+		{b.Pos(0), "b.src:1:1"},
+		{b.Pos(2), "b.src:1:2"},
+		{b.Pos(4), "b.src:2:1"},
+		{NoPos, "-"},
+		{b.Pos(4) + 1, "-"},
+	} {
+		if p := s.Position(test.pos).String(); p != test.expected {
+			t.Errorf("Set.Position(%d) expecting '%s', received '%s'", test.pos, test.expected, p)
+		}
+	}
+	// Adding files doesn't change existing positions
+	//
+	s.AddFile("", "xyz")
+	if p := s.Position(b.Pos(2)).String(); p != "b.src:1:2" {
+		t.Errorf("Set.Position() expecting 'b.src:1:2' after AddFile, received '%s'", p)
+	}
+}
+
+// TestFileOffset
+//
+func TestFileOffset(t *testing.T) {
+	f := NewSet().AddFile("f", "ab\né\xffx\n")
+	for _, test := range []struct {
+		line, column, expected int
+	}{
+		{1, 1, 0},
+		{1, 3, 2},
+		{1, 9, 2},
+		{2, 2, 6},
+		{2, 3, 7},
+		{3, 1, 8},
+		{4, 1, 8},
+		{0, 1, 0},
+	} {
+		if offset := f.Offset(test.line, test.column); offset != test.expected {
+			t.Errorf("File.Offset(%d, %d) expecting %d, received %d", test.line, test.column, test.expected, offset)
+		}
+	}
+}
+
+// TestStack
+//
+func TestStack(t *testing.T) {
+	s := NewSet()
+	main := s.AddFile("main.src", "include util\n")
+	util := s.AddVirtual("util.src", "x\ny", main.Pos(8))
+	macro := s.AddVirtual("", "z", util.Pos(2))
+	stack := s.Stack(macro.Pos(0))
+	expected := []string{"1:1", "util.src:2:1", "main.src:1:9"}
+	if len(stack) != len(expected) {
+		t.Fatalf("Set.Stack() expecting %d positions, received %v", len(expected), stack)
+	}
+	for i, p := range stack {
+		if p.String() != expected[i] {
+			t.Errorf("Set.Stack()[%d] expecting '%s', received '%s'", i, expected[i], p)
+		}
+	}
+	if s.Stack(NoPos) != nil {
+		t.Error("Set.Stack(NoPos) expecting nil")
+	}
+}
+
+// TestFileTokens
+//
+func TestFileTokens(t *testing.T) {
+	s := NewSet()
+	s.AddFile("other", "...")
+	f := s.AddFile("f", "one\n  two")
+	tokens := f.Tokens(token.SliceNexter(token.New(1, "one", 1, 1), token.New(1, "two", 2, 3)))
+	for _, expected := range []string{"f:1:1", "f:2:3"} {
+		tok, err := tokens.Next()
+		if err != nil {
+			t.Fatalf("Next() received unexpected error '%s'", err)
+		}
+		if p := s.Position(PosOf(tok)).String(); p != expected {
+			t.Errorf("PosOf() expecting '%s', received '%s'", expected, p)
+		}
+	}
+	if _, err := tokens.Next(); err != io.EOF {
+		t.Errorf("Next() expecting EOF, received '%v'", err)
+	}
+	if PosOf(token.New(1, "x", 1, 1)) != NoPos {
+		t.Error("PosOf() expecting NoPos for plain token")
+	}
+}
+
+// TestFilePosPanic
+//
+func TestFilePosPanic(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "source.File.Pos: offset 4 out of range [0, 3]" {
+			t.Errorf("File.Pos() expecting panic, received '%v'", r)
+		}
+	}()
+	NewSet().AddFile("f", "abc").Pos(4)
+}
@@ -0,0 +1,74 @@
+package lexer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Stats captures counters from a lexer, for performance tuning and production monitoring.
+// Counters are only recorded when enabled via WithStats, keeping the default path free of bookkeeping.
+// Stats are updated without synchronization; Read them from within lexer functions, or after lexing completes.
+// See Lexer.Stats() and WithStats.
+//
+type Stats struct {
+	RunesRead     int64                // Runes read from the input
+	TokensEmitted int64                // Tokens emitted, including errors and EOF
+	TokensByType  map[token.Type]int64 // Tokens emitted, per token type
+	PeakBuffer    int                  // Peak number of runes held in the rune buffer (matched + peeked)
+	MarkerApplies int64                // Markers applied via Marker.Apply()
+	FnCalls       int64                // Lexer function invocations
+}
+
+// Snapshot returns the counters as a flat map, with keys suitable for metrics systems (i.e. expvar, Prometheus).
+// Per-type token counts are keyed as "tokens_emitted_type_N".
+//
+func (s *Stats) Snapshot() map[string]int64 {
+	m := map[string]int64{
+		"runes_read":     s.RunesRead,
+		"tokens_emitted": s.TokensEmitted,
+		"peak_buffer":    int64(s.PeakBuffer),
+		"marker_applies": s.MarkerApplies,
+		"fn_calls":       s.FnCalls,
+	}
+	for typ, n := range s.TokensByType {
+		m[fmt.Sprintf("tokens_emitted_type_%d", typ)] = n
+	}
+	return m
+}
+
+// String returns the Snapshot() as JSON, allowing Stats to be published as an expvar.Var.
+//
+func (s *Stats) String() string {
+	b, _ := json.Marshal(s.Snapshot()) // Marshalling a map[string]int64 cannot fail
+	return string(b)
+}
+
+// Stats returns a copy of the lexer's current counters.
+// Counters are only recorded when enabled via WithStats, otherwise zero counters are returned.
+// See WithStats for retrieving counters after lexing completes.
+//
+func (l *Lexer) Stats() Stats {
+	if l.stats == nil {
+		return Stats{TokensByType: make(map[token.Type]int64)}
+	}
+	s := *l.stats
+	s.TokensByType = make(map[token.Type]int64, len(l.stats.TokensByType))
+	for typ, n := range l.stats.TokensByType {
+		s.TokensByType[typ] = n
+	}
+	return s
+}
+
+// countEmit updates the counters for an emitted token.
+//
+func (l *Lexer) countEmit(typ token.Type) {
+	if l.stats != nil {
+		l.stats.TokensEmitted++
+		l.stats.TokensByType[typ]++
+	}
+	if l.profiler != nil {
+		l.profiler.count(typ)
+	}
+}
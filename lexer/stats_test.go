@@ -0,0 +1,95 @@
+package lexer
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// lexStats emits each rune as TStart, applying a marker before each match
+//
+func lexStats(l *Lexer) Fn {
+	m := l.Marker()
+	l.Peek(1)
+	m.Apply()
+	l.Next()
+	l.EmitToken(TStart)
+	return lexStats
+}
+
+// TestWithStats
+//
+func TestWithStats(t *testing.T) {
+	stats := &Stats{}
+	nexter := LexString("abc", lexStats, WithStats(stats))
+	expectNexterNext(t, nexter, TStart, "a", 1, 1)
+	expectNexterNext(t, nexter, TStart, "b", 1, 2)
+	expectNexterNext(t, nexter, TStart, "c", 1, 3)
+	expectNexterEOF(t, nexter)
+	expected := map[string]int64{
+		"runes_read":            3,
+		"tokens_emitted":        4,
+		"tokens_emitted_type_2": 1,
+		"tokens_emitted_type_3": 3,
+		"peak_buffer":           1,
+		"marker_applies":        3,
+		"fn_calls":              3,
+	}
+	snapshot := stats.Snapshot()
+	if len(snapshot) != len(expected) {
+		t.Errorf("Stats.Snapshot() expecting %v, received %v", expected, snapshot)
+	}
+	for k, v := range expected {
+		if snapshot[k] != v {
+			t.Errorf("Stats.Snapshot()[%s] expecting %d, received %d", k, v, snapshot[k])
+		}
+	}
+	var decoded map[string]int64
+	if err := json.Unmarshal([]byte(stats.String()), &decoded); err != nil || decoded["fn_calls"] != 3 {
+		t.Errorf("Stats.String() expecting JSON snapshot, received '%s'", stats.String())
+	}
+}
+
+// TestStats
+//
+func TestStats(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		l.Peek(2)
+		l.Next()
+		l.EmitToken(TStart)
+		stats := l.Stats()
+		if stats.RunesRead != 2 || stats.PeakBuffer != 2 || stats.TokensByType[TStart] != 1 {
+			t.Errorf("Lexer.Stats() expecting (2, 2, 1), received (%d, %d, %d)",
+				stats.RunesRead, stats.PeakBuffer, stats.TokensByType[TStart])
+		}
+		// Returned stats is a copy
+		//
+		stats.TokensByType[TStart] = 99
+		if l.Stats().TokensByType[TStart] != 1 {
+			t.Error("Lexer.Stats() expecting copy of counters")
+		}
+		return nil
+	}
+	nexter := LexString("ab", fn, WithStats(&Stats{}))
+	expectNexterNext(t, nexter, TStart, "a", 1, 1)
+	expectNexterEOF(t, nexter)
+}
+
+// TestStatsDisabled
+//
+func TestStatsDisabled(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		l.Next()
+		l.EmitToken(TStart)
+		if l.stats != nil {
+			t.Error("Lexer.stats expecting nil without WithStats")
+		}
+		stats := l.Stats()
+		if stats.RunesRead != 0 || stats.TokensEmitted != 0 || len(stats.TokensByType) != 0 {
+			t.Errorf("Lexer.Stats() expecting zero counters, received '%s'", stats.String())
+		}
+		return nil
+	}
+	nexter := LexString("a", fn)
+	expectNexterNext(t, nexter, TStart, "a", 1, 1)
+	expectNexterEOF(t, nexter)
+}
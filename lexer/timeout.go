@@ -0,0 +1,90 @@
+package lexer
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrReadTimeout is returned by the token.Nexter when the input blocks for longer than the read timeout while
+// waiting for the start of the next token (see WithReadTimeout).
+// The lexer remains usable; Calling Next() again resumes waiting for input.
+//
+var ErrReadTimeout = errors.New("lexer: read timeout")
+
+// runeResult captures the result of a ReadRune call.
+//
+type runeResult struct {
+	r    rune
+	size int
+	err  error
+}
+
+// timeoutReader wraps the lexer input, allowing the lexer to wait for input with a timeout.
+// Reads are only moved to a background goroutine while waiting (see await); Otherwise they happen synchronously.
+// A read that times out is left in flight, and its result is returned by the next ReadRune, so no input is lost.
+//
+type timeoutReader struct {
+	input    io.RuneReader
+	results  chan runeResult // Receives the result of the read in flight
+	inFlight bool            // Is a background read in flight?
+	ready    *runeResult     // Result of a completed background read, not yet returned by ReadRune
+}
+
+// newTimeoutReader wraps the input for timed reads.
+//
+func newTimeoutReader(input io.RuneReader) *timeoutReader {
+	return &timeoutReader{input: input, results: make(chan runeResult, 1), inFlight: false, ready: nil}
+}
+
+// await waits up to d for the next rune to be available, returning ErrReadTimeout if it is not.
+//
+func (t *timeoutReader) await(d time.Duration) error {
+	if t.ready != nil {
+		return nil
+	}
+	if !t.inFlight {
+		t.inFlight = true
+		go func() {
+			r, size, err := t.input.ReadRune()
+			t.results <- runeResult{r: r, size: size, err: err}
+		}()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case res := <-t.results:
+		t.inFlight = false
+		t.ready = &res
+		return nil
+	case <-timer.C:
+		return ErrReadTimeout
+	}
+}
+
+// ReadRune implements io.RuneReader.ReadRune(), blocking until the next rune is available.
+//
+func (t *timeoutReader) ReadRune() (rune, int, error) {
+	if t.inFlight {
+		res := <-t.results
+		t.inFlight = false
+		t.ready = &res
+	}
+	if t.ready != nil {
+		res := *t.ready
+		t.ready = nil
+		return res.r, res.size, res.err
+	}
+	return t.input.ReadRune()
+}
+
+// awaitInput waits for the start of the next token, if the read timeout is enabled (see WithReadTimeout) and the peek
+// buffer is empty, returning ErrReadTimeout if no input arrives in time.
+//
+func (l *Lexer) awaitInput() error {
+	t, ok := l.input.(*timeoutReader)
+	if !ok || l.eof || l.cache.Len() > l.matchLen {
+		return nil
+	}
+	return t.await(l.timeout)
+}
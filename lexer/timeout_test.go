@@ -0,0 +1,52 @@
+package lexer
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestWithReadTimeout
+//
+func TestWithReadTimeout(t *testing.T) {
+	var fn Fn
+	fn = func(l *Lexer) Fn {
+		for l.CanPeek(1) && l.Peek(1) != '\n' {
+			l.Next()
+		}
+		l.EmitToken(TStart)
+		if l.CanPeek(1) {
+			l.Next()
+			l.Clear()
+		}
+		return fn
+	}
+	r, w := io.Pipe()
+	nexter := LexReader(r, fn, WithReadTimeout(10*time.Millisecond))
+	if _, err := nexter.Next(); err != ErrReadTimeout {
+		t.Fatalf("Nexter.Next() expecting ErrReadTimeout, received %v", err)
+	}
+	go func() {
+		_, _ = w.Write([]byte("abc\n"))
+		_ = w.Close()
+	}()
+	// Input arrives while the timed-out read is still in flight, retry until it does
+	//
+	next := func() (token.Token, error) {
+		for {
+			tok, err := nexter.Next()
+			if err != ErrReadTimeout {
+				return tok, err
+			}
+		}
+	}
+	tok, err := next()
+	if err != nil || tok.Value() != "abc" {
+		t.Fatalf("Nexter.Next() expecting 'abc', received %v, %v", tok, err)
+	}
+	if _, err = next(); err != io.EOF {
+		t.Fatalf("Nexter.Next() expecting EOF, received %v", err)
+	}
+}
@@ -23,16 +23,22 @@ const (
 // token is the internal structure that backs the lexer's Token.
 //
 type _token struct {
-	typ    token.Type
-	value  string
-	line   int
-	column int
+	typ     token.Type
+	value   string
+	raw     []byte // Bytes of the value, if not yet built - see WithLazyValues()
+	line    int
+	column  int
+	end     token.Position // Position following the value, as tracked by the lexer - see End()
+	channel token.Channel  // See EmitTokenOn()
+	err     *Error         // Error details, if emitted via EmitErrorToken()
+	cooked  interface{}    // Cooked value - see EmitCooked()
 }
 
 // newToken
 //
 func newToken(typ token.Type, value string, line int, column int) *_token {
-	return &_token{typ: typ, value: value, line: line, column: column}
+	return &_token{typ: typ, value: value, raw: nil, line: line, column: column, end: token.Position{Line: line, Column: column},
+		channel: token.ChannelDefault, err: nil, cooked: nil}
 }
 
 // Type implements Token.Type().
@@ -42,8 +48,13 @@ func (t *_token) Type() token.Type {
 }
 
 // Value implements Token.Value().
+// Lazy values are built on first call (see WithLazyValues).
 //
 func (t *_token) Value() string {
+	if t.raw != nil {
+		t.value = string(t.raw)
+		t.raw = nil
+	}
 	return t.value
 }
 
@@ -59,6 +70,26 @@ func (t *_token) Column() int {
 	return t.column
 }
 
+// End implements token.Ender.End(), returning the position following the matched runes, as tracked by the lexer (see
+// WithColumns and WithLineTerminators).
+// Tokens emitted without their matched runes (i.e. via EmitType) end where they start.
+//
+func (t *_token) End() token.Position {
+	return t.end
+}
+
+// Channel implements token.Channeler.Channel().
+//
+func (t *_token) Channel() token.Channel {
+	return t.channel
+}
+
+// Cooked implements token.Cooker.Cooked().
+//
+func (t *_token) Cooked() interface{} {
+	return t.cooked
+}
+
 // eof returns true if the token.Type == TEof.
 //
 func (t *_token) eof() bool { return TEof == t.typ }
@@ -0,0 +1,75 @@
+package token
+
+// Channel identifies the channel a token is emitted on, allowing tokens such as whitespace, comments and directives
+// to flow through the token stream while remaining invisible to grammar code.
+// Tokens that do not implement Channeler are on ChannelDefault.
+//
+type Channel int
+
+const (
+	// ChannelDefault is the channel of tokens visible to grammar code
+	//
+	ChannelDefault Channel = iota
+	// ChannelHidden is the channel of tokens ignored by grammar code, i.e. whitespace and comments
+	//
+	ChannelHidden
+	// ChannelDirective is the channel of tokens processed out-of-band, i.e. pragmas and preprocessor directives
+	//
+	ChannelDirective
+)
+
+// Channeler is an optional interface for tokens emitted on a channel other than ChannelDefault.
+//
+type Channeler interface {
+
+	// Channel returns the channel the token was emitted on.
+	//
+	Channel() Channel
+}
+
+// ChannelOf returns the channel of tok.
+// Returns ChannelDefault if tok does not implement Channeler.
+//
+func ChannelOf(tok Token) Channel {
+	if c, ok := tok.(Channeler); ok {
+		return c.Channel()
+	}
+	return ChannelDefault
+}
+
+// FilterChannels wraps a Nexter, skipping tokens that are not on one of the specified channels.
+// Errors are always passed through.
+//
+func FilterChannels(tokens Nexter, channels ...Channel) Nexter {
+	return &channelNexter{tokens: tokens, channels: channels}
+}
+
+// channelNexter is the Nexter implementation returned by FilterChannels.
+//
+type channelNexter struct {
+	tokens   Nexter
+	channels []Channel
+}
+
+// Next implements Nexter.Next().
+//
+func (n *channelNexter) Next() (Token, error) {
+	for {
+		tok, err := n.tokens.Next()
+		if tok == nil || err != nil || OnChannel(tok, n.channels...) {
+			return tok, err
+		}
+	}
+}
+
+// OnChannel confirms if tok is on one of the specified channels.
+//
+func OnChannel(tok Token, channels ...Channel) bool {
+	ch := ChannelOf(tok)
+	for _, c := range channels {
+		if c == ch {
+			return true
+		}
+	}
+	return false
+}
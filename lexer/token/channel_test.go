@@ -0,0 +1,51 @@
+package token
+
+import (
+	"io"
+	"testing"
+)
+
+// channelToken is a Token with a channel.
+//
+type channelToken struct {
+	Token
+	channel Channel
+}
+
+// Channel implements Channeler.Channel().
+//
+func (t *channelToken) Channel() Channel {
+	return t.channel
+}
+
+// TestChannelOf
+//
+func TestChannelOf(t *testing.T) {
+	if ch := ChannelOf(New(1, "a", 1, 1)); ch != ChannelDefault {
+		t.Errorf("ChannelOf() expecting ChannelDefault, received %d", ch)
+	}
+	if ch := ChannelOf(&channelToken{Token: New(1, "a", 1, 1), channel: ChannelHidden}); ch != ChannelHidden {
+		t.Errorf("ChannelOf() expecting ChannelHidden, received %d", ch)
+	}
+}
+
+// TestFilterChannels
+//
+func TestFilterChannels(t *testing.T) {
+	tokens := []Token{
+		New(1, "a", 1, 1),
+		&channelToken{Token: New(2, " ", 1, 2), channel: ChannelHidden},
+		&channelToken{Token: New(3, "#x", 1, 3), channel: ChannelDirective},
+		New(1, "b", 1, 5),
+	}
+	n := FilterChannels(SliceNexter(tokens...), ChannelDefault, ChannelDirective)
+	for _, expected := range []string{"a", "#x", "b"} {
+		tok, err := n.Next()
+		if err != nil || tok.Value() != expected {
+			t.Fatalf("Next() expecting '%s', received '%v', %v", expected, tok, err)
+		}
+	}
+	if _, err := n.Next(); err != io.EOF {
+		t.Errorf("Next() expecting io.EOF, received %v", err)
+	}
+}
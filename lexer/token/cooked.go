@@ -0,0 +1,22 @@
+package token
+
+// Cooker is an optional interface for tokens carrying a cooked value, parsed from the token text by the lexer, i.e.
+// the time.Time of a date literal, or the unescaped form of a string literal.
+//
+type Cooker interface {
+
+	// Cooked returns the cooked value of the token.
+	// Returns nil if the token has no cooked value.
+	//
+	Cooked() interface{}
+}
+
+// Cooked returns the cooked value of tok.
+// Returns nil if tok does not implement Cooker, or has no cooked value.
+//
+func Cooked(tok Token) interface{} {
+	if c, ok := tok.(Cooker); ok {
+		return c.Cooked()
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package token
+
+import "testing"
+
+// cookedToken is a Token with a cooked value.
+//
+type cookedToken struct {
+	Token
+	cooked interface{}
+}
+
+// Cooked implements Cooker.Cooked().
+//
+func (t *cookedToken) Cooked() interface{} {
+	return t.cooked
+}
+
+// TestCooked
+//
+func TestCooked(t *testing.T) {
+	if v := Cooked(New(1, "42", 1, 1)); v != nil {
+		t.Errorf("Cooked() expecting nil, received '%v'", v)
+	}
+	if v := Cooked(&cookedToken{Token: New(1, "42", 1, 1), cooked: 42}); v != 42 {
+		t.Errorf("Cooked() expecting 42, received '%v'", v)
+	}
+}
@@ -0,0 +1,154 @@
+package token
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EqualOption configures the comparison of tokens by Equal and Diff.
+//
+type EqualOption func(*equalConfig)
+
+// equalConfig holds the options for Equal and Diff.
+//
+type equalConfig struct {
+	ignorePositions bool
+}
+
+// IgnorePositions compares tokens by type and value only, ignoring their lines and columns.
+//
+func IgnorePositions() EqualOption {
+	return func(c *equalConfig) {
+		c.ignorePositions = true
+	}
+}
+
+// newEqualConfig applies the options.
+//
+func newEqualConfig(opts []EqualOption) *equalConfig {
+	c := &equalConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Equal confirms if the tokens have the same type, value, line and column (see IgnorePositions).
+// Two nil tokens are equal; A nil token is not equal to a non-nil token.
+//
+func Equal(a Token, b Token, opts ...EqualOption) bool {
+	return newEqualConfig(opts).equal(a, b)
+}
+
+// equal implements Equal.
+//
+func (c *equalConfig) equal(a Token, b Token) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if a.Type() != b.Type() || a.Value() != b.Value() {
+		return false
+	}
+	return c.ignorePositions || (a.Line() == b.Line() && a.Column() == b.Column())
+}
+
+// format describes the token for Diff, i.e. `5 "abc" 1:3`.
+//
+func (c *equalConfig) format(tok Token) string {
+	if tok == nil {
+		return "<nil>"
+	}
+	if c.ignorePositions {
+		return fmt.Sprintf("%d %q", tok.Type(), tok.Value())
+	}
+	return fmt.Sprintf("%d %q %d:%d", tok.Type(), tok.Value(), tok.Line(), tok.Column())
+}
+
+// diffContext is the number of unchanged tokens shown around each change by Diff.
+//
+const diffContext = 2
+
+// Diff returns a readable, unified-style, diff of the expected vs actual tokens, one token per line, described by
+// type, value and position (see IgnorePositions), i.e.:
+//
+//	  1 "let" 1:1
+//	- 2 "x" 1:5
+//	+ 2 "y" 1:5
+//	  3 "=" 1:7
+//
+// Tokens only in expected are prefixed with '-', tokens only in actual are prefixed with '+', and unchanged tokens
+// are prefixed with ' '.
+// Only the unchanged tokens near a change are shown; Runs of omitted tokens are marked with "...".
+// Returns "" if the token lists are equal.
+//
+func Diff(expected []Token, actual []Token, opts ...EqualOption) string {
+	c := newEqualConfig(opts)
+	a, b := expected, actual
+	// lcs[i][j] holds the length of the longest common subsequence of a[i:] and b[j:]
+	//
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if c.equal(a[i], b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	// Walk the table, collecting every line along with whether it is a change
+	//
+	var lines []string
+	var changed []bool
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && c.equal(a[i], b[j]):
+			lines = append(lines, "  "+c.format(b[j]))
+			changed = append(changed, false)
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "- "+c.format(a[i]))
+			changed = append(changed, true)
+			i++
+		default:
+			lines = append(lines, "+ "+c.format(b[j]))
+			changed = append(changed, true)
+			j++
+		}
+	}
+	// Keep the changes, along with the unchanged lines near them
+	//
+	keep := make([]bool, len(lines))
+	found := false
+	for n, ch := range changed {
+		if !ch {
+			continue
+		}
+		found = true
+		for k := n - diffContext; k <= n+diffContext; k++ {
+			if k >= 0 && k < len(lines) {
+				keep[k] = true
+			}
+		}
+	}
+	if !found {
+		return ""
+	}
+	var out []string
+	for n, line := range lines {
+		switch {
+		case keep[n]:
+			out = append(out, line)
+		case n == 0 || keep[n-1]:
+			out = append(out, "...")
+		}
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,79 @@
+package token
+
+import (
+	"testing"
+)
+
+// TestEqual
+//
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a        Token
+		b        Token
+		opts     []EqualOption
+		expected bool
+	}{
+		{New(1, "a", 1, 1), New(1, "a", 1, 1), nil, true},
+		{New(1, "a", 1, 1), New(2, "a", 1, 1), nil, false},
+		{New(1, "a", 1, 1), New(1, "b", 1, 1), nil, false},
+		{New(1, "a", 1, 1), New(1, "a", 1, 2), nil, false},
+		{New(1, "a", 1, 1), New(1, "a", 2, 1), nil, false},
+		{New(1, "a", 1, 1), New(1, "a", 3, 5), []EqualOption{IgnorePositions()}, true},
+		{New(1, "a", 1, 1), New(1, "b", 3, 5), []EqualOption{IgnorePositions()}, false},
+		{nil, nil, nil, true},
+		{New(1, "a", 1, 1), nil, nil, false},
+		{nil, New(1, "a", 1, 1), nil, false},
+	}
+	for i, test := range tests {
+		if Equal(test.a, test.b, test.opts...) != test.expected {
+			t.Errorf("Equal() test %d expecting %v", i, test.expected)
+		}
+	}
+}
+
+// TestDiff
+//
+func TestDiff(t *testing.T) {
+	expected := []Token{
+		New(1, "let", 1, 1), New(2, "x", 1, 5), New(3, "=", 1, 7), New(4, "1", 1, 9),
+		New(5, ";", 1, 10), New(1, "let", 2, 1), New(2, "y", 2, 5), New(3, "=", 2, 7), New(4, "2", 2, 9),
+	}
+	actual := []Token{
+		New(1, "let", 1, 1), New(2, "z", 1, 5), New(3, "=", 1, 7), New(4, "1", 1, 9),
+		New(5, ";", 1, 10), New(1, "let", 2, 1), New(2, "y", 2, 5), New(3, "=", 2, 7), New(4, "2", 2, 9), New(5, ";", 2, 10),
+	}
+	match := `  1 "let" 1:1
+- 2 "x" 1:5
++ 2 "z" 1:5
+  3 "=" 1:7
+  4 "1" 1:9
+...
+  3 "=" 2:7
+  4 "2" 2:9
++ 5 ";" 2:10`
+	if d := Diff(expected, actual); d != match {
+		t.Errorf("Diff() expecting:\n%s\nreceived:\n%s", match, d)
+	}
+	if d := Diff(expected, expected); d != "" {
+		t.Errorf("Diff() expecting '', received:\n%s", d)
+	}
+	if d := Diff(nil, nil); d != "" {
+		t.Errorf("Diff(nil, nil) expecting '', received:\n%s", d)
+	}
+}
+
+// TestDiffIgnorePositions
+//
+func TestDiffIgnorePositions(t *testing.T) {
+	expected := []Token{New(1, "a", 1, 1), New(2, "b", 1, 2)}
+	actual := []Token{New(1, "a", 5, 5), New(2, "b", 5, 6), New(3, "c", 5, 7)}
+	match := `  1 "a"
+  2 "b"
++ 3 "c"`
+	if d := Diff(expected, actual, IgnorePositions()); d != match {
+		t.Errorf("Diff() expecting:\n%s\nreceived:\n%s", match, d)
+	}
+	if d := Diff(expected, actual[:2], IgnorePositions()); d != "" {
+		t.Errorf("Diff() expecting '', received:\n%s", d)
+	}
+}
@@ -0,0 +1,22 @@
+package token
+
+// Incompleter is an optional interface for errors that may be caused by the input ending early, i.e. an unterminated
+// string or an unclosed block, rather than by invalid input.
+// Interactive tools (REPLs) can use this to prompt for continuation lines, instead of reporting an error.
+//
+type Incompleter interface {
+
+	// Incomplete confirms if the error was caused by the input ending early.
+	//
+	Incomplete() bool
+}
+
+// IsIncomplete confirms if err was caused by the input ending early (see Incompleter).
+// Returns false if err does not implement Incompleter.
+//
+func IsIncomplete(err error) bool {
+	if i, ok := err.(Incompleter); ok {
+		return i.Incomplete()
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package token
+
+import (
+	"errors"
+	"testing"
+)
+
+// incompleteError is an error that implements Incompleter.
+//
+type incompleteError bool
+
+// Error implements error.
+//
+func (e incompleteError) Error() string {
+	return "unterminated string"
+}
+
+// Incomplete implements Incompleter.Incomplete().
+//
+func (e incompleteError) Incomplete() bool {
+	return bool(e)
+}
+
+// TestIsIncomplete
+//
+func TestIsIncomplete(t *testing.T) {
+	if IsIncomplete(errors.New("invalid")) {
+		t.Error("IsIncomplete() expecting false for plain error")
+	}
+	if IsIncomplete(nil) {
+		t.Error("IsIncomplete() expecting false for nil")
+	}
+	if IsIncomplete(incompleteError(false)) {
+		t.Error("IsIncomplete() expecting false")
+	}
+	if !IsIncomplete(incompleteError(true)) {
+		t.Error("IsIncomplete() expecting true")
+	}
+}
@@ -0,0 +1,140 @@
+package token
+
+import "io"
+
+// New returns a simple Token with the specified type, value and position.
+// Use -1 for line and column if the position is not known.
+//
+func New(typ Type, value string, line int, column int) Token {
+	return &basicToken{typ: typ, value: value, line: line, column: column}
+}
+
+// basicToken is the Token implementation returned by New.
+//
+type basicToken struct {
+	typ    Type
+	value  string
+	line   int
+	column int
+}
+
+// Type implements Token.Type().
+//
+func (t *basicToken) Type() Type {
+	return t.typ
+}
+
+// Value implements Token.Value().
+//
+func (t *basicToken) Value() string {
+	return t.value
+}
+
+// Line implements Token.Line().
+//
+func (t *basicToken) Line() int {
+	return t.line
+}
+
+// Column implements Token.Column().
+//
+func (t *basicToken) Column() int {
+	return t.column
+}
+
+// SliceNexter returns a Nexter that emits the specified tokens, in order, followed by io.EOF.
+// Useful for unit-testing parsers without a lexer.
+//
+func SliceNexter(tokens ...Token) Nexter {
+	return &sliceNexter{tokens: tokens}
+}
+
+// TypesNexter returns a Nexter that emits a token for each of the specified types, in order, followed by io.EOF.
+// The tokens have empty values and no position (-1).
+//
+func TypesNexter(types ...Type) Nexter {
+	tokens := make([]Token, len(types))
+	for i, typ := range types {
+		tokens[i] = New(typ, "", -1, -1)
+	}
+	return &sliceNexter{tokens: tokens}
+}
+
+// sliceNexter is the Nexter implementation returned by SliceNexter and TypesNexter.
+//
+type sliceNexter struct {
+	tokens []Token
+}
+
+// Next implements Nexter.Next().
+//
+func (n *sliceNexter) Next() (Token, error) {
+	if len(n.tokens) == 0 {
+		return nil, io.EOF
+	}
+	tok := n.tokens[0]
+	n.tokens = n.tokens[1:]
+	return tok, nil
+}
+
+// ErrNexter wraps a Nexter, injecting err after the first n tokens have been emitted.
+// The error is returned once, with a nil token, after which the remaining tokens are emitted.
+// If the wrapped Nexter reaches io.EOF before n tokens are emitted, the error is never returned.
+// If err is io.EOF, the stream is truncated after n tokens.
+// Useful for testing how parsers handle lexer failures.
+//
+func ErrNexter(tokens Nexter, n int, err error) Nexter {
+	return &errNexter{tokens: tokens, remaining: n, err: err}
+}
+
+// errNexter is the Nexter implementation returned by ErrNexter.
+//
+type errNexter struct {
+	tokens    Nexter
+	remaining int
+	err       error
+	eof       bool
+}
+
+// Next implements Nexter.Next().
+//
+func (n *errNexter) Next() (Token, error) {
+	if n.eof {
+		return nil, io.EOF
+	}
+	if n.remaining == 0 && n.err != nil {
+		err := n.err
+		n.err = nil
+		if err == io.EOF {
+			n.eof = true
+		}
+		return nil, err
+	}
+	tok, err := n.tokens.Next()
+	if err == io.EOF {
+		n.eof = true
+	} else if tok != nil {
+		n.remaining--
+	}
+	return tok, err
+}
+
+// Drain consumes the Nexter until io.EOF, discarding the tokens.
+// Returns the number of tokens consumed, along with the first non-EOF error encountered (if any).
+// Draining continues past errors, as they may be recoverable.
+// Useful for benchmarking lexers end-to-end.
+//
+func Drain(n Nexter) (count int, err error) {
+	for {
+		tok, e := n.Next()
+		if e == io.EOF {
+			return count, err
+		}
+		if e != nil && err == nil {
+			err = e
+		}
+		if tok != nil {
+			count++
+		}
+	}
+}
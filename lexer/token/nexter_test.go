@@ -0,0 +1,104 @@
+package token
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+// expectNext
+//
+func expectNext(t *testing.T, n Nexter, typ Type, value string) {
+	tok, err := n.Next()
+	if err != nil {
+		t.Errorf("Nexter.Next() expecting no error, received '%s'", err)
+	} else if tok == nil {
+		t.Error("Nexter.Next() expecting token, received nil")
+	} else if tok.Type() != typ || tok.Value() != value {
+		t.Errorf("Nexter.Next() expecting token %d '%s', received %d '%s'", typ, value, tok.Type(), tok.Value())
+	}
+}
+
+// expectErr
+//
+func expectErr(t *testing.T, n Nexter, match error) {
+	tok, err := n.Next()
+	if err != match {
+		t.Errorf("Nexter.Next() expecting error '%v', received '%v'", match, err)
+	}
+	if tok != nil {
+		t.Errorf("Nexter.Next() expecting nil token, received '%s'", tok.Value())
+	}
+}
+
+// TestNew
+//
+func TestNew(t *testing.T) {
+	tok := New(2, "abc", 3, 4)
+	if tok.Type() != 2 || tok.Value() != "abc" || tok.Line() != 3 || tok.Column() != 4 {
+		t.Errorf("New returned wrong token: %d '%s' %d:%d", tok.Type(), tok.Value(), tok.Line(), tok.Column())
+	}
+}
+
+// TestSliceNexter
+//
+func TestSliceNexter(t *testing.T) {
+	n := SliceNexter(New(1, "a", 1, 1), New(2, "b", 1, 2))
+	expectNext(t, n, 1, "a")
+	expectNext(t, n, 2, "b")
+	expectErr(t, n, io.EOF)
+	expectErr(t, n, io.EOF)
+	expectErr(t, SliceNexter(), io.EOF)
+}
+
+// TestTypesNexter
+//
+func TestTypesNexter(t *testing.T) {
+	n := TypesNexter(1, 2)
+	tok, _ := n.Next()
+	if tok.Type() != 1 || tok.Value() != "" || Start(tok).IsValid() {
+		t.Errorf("TypesNexter returned wrong token: %d '%s' %s", tok.Type(), tok.Value(), Start(tok))
+	}
+	expectNext(t, n, 2, "")
+	expectErr(t, n, io.EOF)
+}
+
+// TestErrNexter
+//
+func TestErrNexter(t *testing.T) {
+	e := errors.New("fault")
+	n := ErrNexter(TypesNexter(1, 2, 3), 2, e)
+	expectNext(t, n, 1, "")
+	expectNext(t, n, 2, "")
+	expectErr(t, n, e)
+	expectNext(t, n, 3, "")
+	expectErr(t, n, io.EOF)
+	expectErr(t, n, io.EOF)
+
+	n = ErrNexter(TypesNexter(1), 0, e)
+	expectErr(t, n, e)
+	expectNext(t, n, 1, "")
+	expectErr(t, n, io.EOF)
+
+	n = ErrNexter(TypesNexter(1, 2), 1, io.EOF)
+	expectNext(t, n, 1, "")
+	expectErr(t, n, io.EOF)
+	expectErr(t, n, io.EOF)
+
+	n = ErrNexter(TypesNexter(1), 5, e)
+	expectNext(t, n, 1, "")
+	expectErr(t, n, io.EOF)
+}
+
+// TestDrain
+//
+func TestDrain(t *testing.T) {
+	e1, e2 := errors.New("one"), errors.New("two")
+	count, err := Drain(ErrNexter(ErrNexter(TypesNexter(1, 2, 3), 1, e1), 3, e2))
+	if count != 3 || err != e1 {
+		t.Errorf("Drain expecting (3, '%s'), received (%d, '%v')", e1, count, err)
+	}
+	if count, err = Drain(SliceNexter()); count != 0 || err != nil {
+		t.Errorf("Drain expecting (0, nil), received (%d, '%v')", count, err)
+	}
+}
@@ -0,0 +1,71 @@
+package token
+
+import "strings"
+
+// Originer is an optional interface for tokens that were produced from another token, i.e. by an include, a macro
+// expansion, or a rewriting stage.
+// Such tokens report their synthetic position via Line() and Column(), while Origin() leads back to the source.
+//
+type Originer interface {
+
+	// Origin returns the token this token was produced from.
+	// Returns nil if the token was not produced from another token.
+	//
+	Origin() Token
+}
+
+// Origin returns the token that tok was produced from.
+// Returns nil if tok does not implement Originer, or has no origin.
+//
+func Origin(tok Token) Token {
+	if o, ok := tok.(Originer); ok {
+		return o.Origin()
+	}
+	return nil
+}
+
+// Root follows the origin chain of tok, returning the original source token.
+// Returns tok if it has no origin.
+//
+func Root(tok Token) Token {
+	for o := Origin(tok); o != nil; o = Origin(tok) {
+		tok = o
+	}
+	return tok
+}
+
+// Provenance returns the position of tok, followed by the positions of its origin chain, for use in diagnostics.
+// Formatted as "line:column", with origins appended as " (from line:column, ...)".
+//
+func Provenance(tok Token) string {
+	var b strings.Builder
+	b.WriteString(Start(tok).String())
+	for o, sep := Origin(tok), " (from "; o != nil; o, sep = Origin(o), ", " {
+		b.WriteString(sep)
+		b.WriteString(Start(o).String())
+		if Origin(o) == nil {
+			b.WriteString(")")
+		}
+	}
+	return b.String()
+}
+
+// Derive returns a simple Token with the specified type, value and position, produced from origin.
+// See Originer for details.
+//
+func Derive(typ Type, value string, line int, column int, origin Token) Token {
+	return &derivedToken{basicToken: basicToken{typ: typ, value: value, line: line, column: column}, origin: origin}
+}
+
+// derivedToken is the Token implementation returned by Derive.
+//
+type derivedToken struct {
+	basicToken
+	origin Token
+}
+
+// Origin implements Originer.Origin().
+//
+func (t *derivedToken) Origin() Token {
+	return t.origin
+}
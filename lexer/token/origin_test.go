@@ -0,0 +1,56 @@
+package token
+
+import "testing"
+
+// TestDerive
+//
+func TestDerive(t *testing.T) {
+	src := New(2, "abc", 3, 4)
+	tok := Derive(5, "xyz", 1, 2, src)
+	if tok.Type() != 5 || tok.Value() != "xyz" || tok.Line() != 1 || tok.Column() != 2 {
+		t.Errorf("Derive returned wrong token: %d '%s' %d:%d", tok.Type(), tok.Value(), tok.Line(), tok.Column())
+	}
+	if o := Origin(tok); o != src {
+		t.Errorf("Origin() expecting source token, received '%v'", o)
+	}
+}
+
+// TestOrigin
+//
+func TestOrigin(t *testing.T) {
+	src := New(2, "abc", 3, 4)
+	if o := Origin(src); o != nil {
+		t.Errorf("Origin() expecting nil, received '%v'", o)
+	}
+	if r := Root(src); r != src {
+		t.Errorf("Root() expecting token, received '%v'", r)
+	}
+	mid := Derive(2, "abc", 10, 1, src)
+	tok := Derive(2, "abc", 1, 2, mid)
+	if r := Root(tok); r != src {
+		t.Errorf("Root() expecting source token, received '%v'", r)
+	}
+	if o := Origin(Derive(2, "", 1, 1, nil)); o != nil {
+		t.Errorf("Origin() expecting nil, received '%v'", o)
+	}
+}
+
+// TestProvenance
+//
+func TestProvenance(t *testing.T) {
+	src := New(2, "abc", 3, 4)
+	mid := Derive(2, "abc", 10, 1, src)
+	tests := []struct {
+		tok   Token
+		match string
+	}{
+		{src, "3:4"},
+		{mid, "10:1 (from 3:4)"},
+		{Derive(2, "abc", 1, 2, mid), "1:2 (from 10:1, 3:4)"},
+	}
+	for _, test := range tests {
+		if s := Provenance(test.tok); s != test.match {
+			t.Errorf("Provenance() expecting '%s', received '%s'", test.match, s)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package token
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Position captures a line/column location within the source input.
+// See Token.Line() and Token.Column() for details on the meaning of each field.
+//
+type Position struct {
+	Line   int
+	Column int
+}
+
+// IsValid confirms if the position is set.
+// Returns false if either the line or column is < 0.
+//
+func (p Position) IsValid() bool {
+	return p.Line >= 0 && p.Column >= 0
+}
+
+// String returns the position formatted as "line:column".
+//
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Span captures the range of source input covered by one or more tokens.
+// Start is inclusive, End is exclusive.
+//
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// String returns the span formatted as "line:column-line:column".
+//
+func (s Span) String() string {
+	return fmt.Sprintf("%s-%s", s.Start, s.End)
+}
+
+// Start returns the starting position of the token.
+//
+func Start(tok Token) Position {
+	return Position{Line: tok.Line(), Column: tok.Column()}
+}
+
+// Ender is an optional interface for tokens that record the position immediately following their value, as computed
+// by the token generator.
+// Tokens emitted by the lexer implement Ender, with the end position following the lexer's own line and column
+// tracking.
+//
+type Ender interface {
+
+	// End returns the position immediately following the token's value.
+	//
+	End() Position
+}
+
+// End returns the position immediately following the token's value.
+// If tok implements Ender, the recorded end position is returned.
+// Otherwise the position is computed by advancing Start(tok) over the runes of Value(), assuming columns count runes
+// and lines are terminated by '\n': A newline advances to the next line, with a column of 0.
+// If the token's position is not set, the (invalid) starting position is returned.
+//
+func End(tok Token) Position {
+	if e, ok := tok.(Ender); ok {
+		return e.End()
+	}
+	p := Start(tok)
+	if !p.IsValid() {
+		return p
+	}
+	for s := tok.Value(); len(s) > 0; {
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		if p.Line == 0 {
+			p.Line = 1
+		}
+		if r == '\n' {
+			p.Line++
+			p.Column = 0
+		} else {
+			if p.Column == 0 {
+				p.Column = 1
+			}
+			p.Column++
+		}
+	}
+	return p
+}
+
+// SpanOf returns the span from the start of the first token to the end of the last token.
+//
+func SpanOf(first Token, last Token) Span {
+	return Span{Start: Start(first), End: End(last)}
+}
@@ -0,0 +1,87 @@
+package token
+
+import "testing"
+
+// mockToken
+//
+type mockToken struct {
+	value  string
+	line   int
+	column int
+}
+
+func (t *mockToken) Type() Type {
+	return 0
+}
+func (t *mockToken) Value() string {
+	return t.value
+}
+func (t *mockToken) Line() int {
+	return t.line
+}
+func (t *mockToken) Column() int {
+	return t.column
+}
+
+// expectPosition
+//
+func expectPosition(t *testing.T, p Position, match string) {
+	if s := p.String(); s != match {
+		t.Errorf("Expecting position '%s', received '%s'", match, s)
+	}
+}
+
+// TestPositionIsValid
+//
+func TestPositionIsValid(t *testing.T) {
+	if !(Position{Line: 0, Column: 0}).IsValid() {
+		t.Error("Position{0, 0} expected to be valid")
+	}
+	if (Position{Line: -1, Column: 1}).IsValid() {
+		t.Error("Position{-1, 1} expected to be invalid")
+	}
+	if (Position{Line: 1, Column: -1}).IsValid() {
+		t.Error("Position{1, -1} expected to be invalid")
+	}
+}
+
+// TestEnd
+//
+func TestEnd(t *testing.T) {
+	expectPosition(t, End(&mockToken{value: "", line: 1, column: 1}), "1:1")
+	expectPosition(t, End(&mockToken{value: "abc", line: 1, column: 1}), "1:4")
+	expectPosition(t, End(&mockToken{value: "日本", line: 2, column: 3}), "2:5")
+	expectPosition(t, End(&mockToken{value: "a\n", line: 1, column: 5}), "2:0")
+	expectPosition(t, End(&mockToken{value: "a\nbc", line: 1, column: 5}), "2:3")
+	expectPosition(t, End(&mockToken{value: "abc", line: 0, column: 0}), "1:4")
+	expectPosition(t, End(&mockToken{value: "abc", line: -1, column: -1}), "-1:-1")
+}
+
+// enderToken is a mockToken that records its end position
+//
+type enderToken struct {
+	mockToken
+	end Position
+}
+
+func (t *enderToken) End() Position {
+	return t.end
+}
+
+// TestEndEnder
+//
+func TestEndEnder(t *testing.T) {
+	tok := &enderToken{mockToken: mockToken{value: "e\u0301x", line: 1, column: 1}, end: Position{Line: 1, Column: 3}}
+	expectPosition(t, End(tok), "1:3")
+	expectPosition(t, SpanOf(tok, tok).End, "1:3")
+}
+
+// TestSpanOf
+//
+func TestSpanOf(t *testing.T) {
+	first := &mockToken{value: "one", line: 1, column: 1}
+	last := &mockToken{value: "two", line: 2, column: 4}
+	if s := SpanOf(first, last).String(); s != "1:1-2:7" {
+		t.Errorf("SpanOf() expecting '1:1-2:7', received '%s'", s)
+	}
+}
@@ -0,0 +1,88 @@
+package token
+
+import "fmt"
+
+// Set is an immutable set of token types, backed by a bitset, allowing classes of tokens (i.e. "binary operators")
+// to be defined once and tested for membership cheaply.
+// The zero value is an empty set.
+//
+type Set struct {
+	bits []uint64
+}
+
+// NewSet returns a set containing the specified types.
+// Panics if any type is negative.
+//
+func NewSet(types ...Type) Set {
+	return Set{}.With(types...)
+}
+
+// Contains confirms if the set contains the type.
+// Always returns false for negative types.
+//
+func (s Set) Contains(t Type) bool {
+	if t < 0 {
+		return false
+	}
+	i := int(t) / 64
+	return i < len(s.bits) && s.bits[i]&(1<<(uint(t)%64)) != 0
+}
+
+// With returns a new set containing the types of s, along with the specified types.
+// Panics if any type is negative.
+//
+func (s Set) With(types ...Type) Set {
+	bits := append([]uint64(nil), s.bits...)
+	for _, t := range types {
+		if t < 0 {
+			panic(fmt.Sprintf("Set.With: negative type %d", t))
+		}
+		i := int(t) / 64
+		for len(bits) <= i {
+			bits = append(bits, 0)
+		}
+		bits[i] |= 1 << (uint(t) % 64)
+	}
+	return Set{bits: bits}
+}
+
+// Union returns a new set containing the types of s, along with the types of each of the other sets.
+//
+func (s Set) Union(others ...Set) Set {
+	bits := append([]uint64(nil), s.bits...)
+	for _, o := range others {
+		for len(bits) < len(o.bits) {
+			bits = append(bits, 0)
+		}
+		for i, b := range o.bits {
+			bits[i] |= b
+		}
+	}
+	return Set{bits: bits}
+}
+
+// Types returns the types within the set, in ascending order.
+//
+func (s Set) Types() []Type {
+	var types []Type
+	for i, b := range s.bits {
+		for j := 0; j < 64; j++ {
+			if b&(1<<uint(j)) != 0 {
+				types = append(types, Type(i*64+j))
+			}
+		}
+	}
+	return types
+}
+
+// Len returns the number of types within the set.
+//
+func (s Set) Len() int {
+	return len(s.Types())
+}
+
+// String returns the set formatted as "[t1 t2 ...]".
+//
+func (s Set) String() string {
+	return fmt.Sprint(s.Types())
+}
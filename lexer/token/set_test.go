@@ -0,0 +1,52 @@
+package token
+
+import "testing"
+
+// TestSet
+//
+func TestSet(t *testing.T) {
+	var empty Set
+	if empty.Contains(0) || empty.Len() != 0 {
+		t.Error("Set zero value expecting empty set")
+	}
+	s := NewSet(1, 3, 130)
+	for _, typ := range []Type{1, 3, 130} {
+		if !s.Contains(typ) {
+			t.Errorf("Set.Contains(%d) expecting true", typ)
+		}
+	}
+	for _, typ := range []Type{-1, 0, 2, 64, 129, 131, 1000} {
+		if s.Contains(typ) {
+			t.Errorf("Set.Contains(%d) expecting false", typ)
+		}
+	}
+	if str := s.String(); str != "[1 3 130]" {
+		t.Errorf("Set.String() expecting '[1 3 130]', received '%s'", str)
+	}
+	if s.Len() != 3 {
+		t.Errorf("Set.Len() expecting 3, received %d", s.Len())
+	}
+}
+
+// TestSetWithUnion
+//
+func TestSetWithUnion(t *testing.T) {
+	a := NewSet(1, 2)
+	b := a.With(70)
+	if a.Contains(70) {
+		t.Error("Set.With() expecting original set to be unchanged")
+	}
+	u := a.Union(NewSet(200), b)
+	if str := u.String(); str != "[1 2 70 200]" {
+		t.Errorf("Set.Union() expecting '[1 2 70 200]', received '%s'", str)
+	}
+	if str := a.String(); str != "[1 2]" {
+		t.Errorf("Set.Union() expecting original set to be unchanged, received '%s'", str)
+	}
+	defer func() {
+		if r := recover(); r != "Set.With: negative type -1" {
+			t.Errorf("Set.With(-1) expecting panic, received '%v'", r)
+		}
+	}()
+	NewSet(-1)
+}
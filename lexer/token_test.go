@@ -12,8 +12,8 @@ func assertToken(t *testing.T, tok *_token, typ token.Type, value string, line i
 	if tok.typ != typ {
 		t.Errorf("token.typ expecting '%d', received '%d'", typ, tok.typ)
 	}
-	if tok.value != value {
-		t.Errorf("token.value expecting '%s', received '%s'", value, tok.value)
+	if tok.Value() != value {
+		t.Errorf("token.value expecting '%s', received '%s'", value, tok.Value())
 	}
 	if line >= 0 && tok.line != line {
 		t.Errorf("token.line expecting '%d', received '%d'", line, tok.line)
@@ -1,7 +1,6 @@
 package lexer
 
 import (
-	"errors"
 	"io"
 
 	"github.com/tekwizely/go-parsing/lexer/token"
@@ -10,16 +9,28 @@ import (
 // tokenNexter is the internal structure that backs the lexer's token.Nexter.
 //
 type tokenNexter struct {
-	lexer *Lexer
-	next  token.Token
-	eof   bool
+	lexer    *Lexer
+	next     token.Token
+	eof      bool
+	pool     *Pool // Pool the nexter was taken from, nil if none - see Pool
+	timedOut bool  // Did the last hasNext() time out waiting for input? - see WithReadTimeout()
+	misused  bool  // Has the misuse error been returned? - see WithLenient()
 }
 
 // Next implements token.Nexter.Next().
 // We build on the previous HasNext/Next impl to keep changes minimal.
+// In lenient mode, any post-EOF usage is returned once, before io.EOF (see WithLenient).
 //
 func (t *tokenNexter) Next() (token.Token, error) {
 	if !t.hasNext() {
+		if t.timedOut {
+			t.timedOut = false
+			return nil, ErrReadTimeout
+		}
+		if t.lexer.misuse != nil && !t.misused {
+			t.misused = true
+			return nil, t.lexer.misuse
+		}
 		return nil, io.EOF
 	}
 	tok := t.next
@@ -27,7 +38,10 @@ func (t *tokenNexter) Next() (token.Token, error) {
 	// Error?
 	//
 	if tok.Type() == TLexErr {
-		return nil, errors.New(tok.Value())
+		if e := t.lexer.loopErr; e != nil && tok == e.tok {
+			return nil, e
+		}
+		return nil, newError(tok)
 	}
 	return tok, nil
 }
@@ -45,14 +59,51 @@ func (t *tokenNexter) hasNext() bool {
 	if t.eof {
 		return false
 	}
-	// If no tokens available, try to fetch some.
+	// If no tokens ready for delivery, try to fetch some.
 	//
-	for t.lexer.output.Len() == 0 {
+	for !t.lexer.outputReady() {
+		// Wait for input, if the read timeout is enabled
+		//
+		if t.lexer.nextFn != nil && t.lexer.awaitInput() != nil {
+			// Deliver any pending tokens before reporting the timeout
+			//
+			if t.lexer.output.Len() > 0 {
+				t.lexer.flushed = true
+				continue
+			}
+			t.timedOut = true
+			return false
+		}
 		// Anyone to call?
 		// Anything to scan?
 		//
 		if t.lexer.nextFn != nil && t.lexer.CanPeek(1) {
-			t.lexer.nextFn = t.lexer.nextFn(t.lexer)
+			// Consume any header constructs before the first call
+			//
+			if !t.lexer.scanned {
+				t.lexer.prescan()
+				continue
+			}
+			t.lexer.traceEvent(TraceEnter, 0, 0, "")
+			if t.lexer.recorder != nil {
+				t.lexer.recorder.record(fnName(t.lexer.nextFn), t.lexer.matchStart())
+			}
+			fn, before := t.lexer.nextFn, t.lexer.progress()
+			if t.lexer.stats != nil {
+				t.lexer.stats.FnCalls++
+			}
+			if t.lexer.profiler != nil {
+				t.lexer.profiler.enter(fn)
+			}
+			nextFn := fn(t.lexer)
+			if t.lexer.profiler != nil {
+				t.lexer.profiler.exit()
+			}
+			t.lexer.traceEvent(TraceExit, 0, 0, fnName(nextFn))
+			t.lexer.nextFn = t.lexer.guardUnknown(fn, before, nextFn)
+			t.lexer.guardLoop(fn, before)
+			t.lexer.guardErrors()
+			t.lexer.guardTokens()
 		} else
 		// Lexer Terminated or input at EOF, let's clean up.
 		// If EOF was never emitted, then emit it now.
@@ -63,8 +114,10 @@ func (t *tokenNexter) hasNext() bool {
 	}
 	// Consume the token.
 	// We'll either cache it or discard it.
+	// Any remaining tokens in the batch are delivered before calling more lexer functions.
 	//
-	tok := t.lexer.output.Remove(t.lexer.output.Front()).(*_token)
+	tok := t.lexer.output.PopFront()
+	t.lexer.flushed = t.lexer.output.Len() > 0
 	// Is the token EOF?
 	//
 	if tok.eof() {
@@ -0,0 +1,64 @@
+package lexer
+
+import (
+	"container/list"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// errTokenReaderInvalid is returned by the TokenReader once the matched runes have been emitted, cleared, truncated or
+// rewound.
+//
+var errTokenReaderInvalid = errors.New("Lexer.TokenReader: matched runes changed since the reader was created")
+
+// TokenReader returns an io.Reader over the currently matched rune sequence, as UTF-8 bytes.
+// Large matched regions (i.e. heredocs, embedded blobs) can be streamed to decoders without first materializing a
+// string via PeekToken.
+// The reader covers the runes matched at the time of the call, and remains valid until the next emit, clear, Truncate
+// or Marker.Apply, after which Read returns an error.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (l *Lexer) TokenReader() io.Reader {
+	// Nothing can be peeked after EOF emitted
+	//
+	if l.afterEOF("Lexer.TokenReader: No token peeks allowed after EOF is emitted") {
+		return strings.NewReader("")
+	}
+	return &tokenReader{lexer: l, markerID: l.markerID, matchID: l.matchID, next: l.cache.Front(), remaining: l.matchLen}
+}
+
+// tokenReader is the io.Reader returned by Lexer.TokenReader.
+//
+type tokenReader struct {
+	lexer     *Lexer
+	markerID  int           // Lexer markerID when created, used to detect emit/clear
+	matchID   int           // Lexer matchID when created, used to detect Truncate / Marker.Apply
+	next      *list.Element // Next rune to read
+	remaining int           // Runes remaining
+	pending   []byte        // Bytes of the last rune read that did not fit into the caller's buffer
+	buf       [utf8.UTFMax]byte
+}
+
+// Read implements io.Reader.Read().
+//
+func (r *tokenReader) Read(p []byte) (int, error) {
+	if r.lexer.markerID != r.markerID || r.lexer.matchID != r.matchID {
+		return 0, errTokenReaderInvalid
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	for n < len(p) && r.remaining > 0 {
+		size := utf8.EncodeRune(r.buf[:], r.next.Value.(rune))
+		copied := copy(p[n:], r.buf[:size])
+		r.pending = r.buf[copied:size]
+		n += copied
+		r.next = r.next.Next()
+		r.remaining--
+	}
+	if n == 0 && len(p) > 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,65 @@
+package lexer
+
+import (
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+// TestTokenReader
+//
+func TestTokenReader(t *testing.T) {
+	fn := func(l *Lexer) Fn {
+		expectNextString(t, l, "aé字")
+		r := l.TokenReader()
+		l.Next() // Runes matched after the call are not included
+		// Read one byte at a time, forcing multi-byte runes to be split across reads
+		//
+		var b []byte
+		p := make([]byte, 1)
+		for {
+			n, err := r.Read(p)
+			b = append(b, p[:n]...)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("TokenReader.Read() received unexpected error '%v'", err)
+			}
+		}
+		if s := string(b); s != "aé字" {
+			t.Errorf("TokenReader expecting '%s', received '%s'", "aé字", s)
+		}
+		if b, err := ioutil.ReadAll(l.TokenReader()); err != nil || string(b) != "aé字!" {
+			t.Errorf("TokenReader expecting ('aé字!', nil), received ('%s', %v)", string(b), err)
+		}
+		// Invalidated by truncate
+		//
+		r = l.TokenReader()
+		l.Truncate(1)
+		if _, err := r.Read(p); err != errTokenReaderInvalid {
+			t.Errorf("TokenReader.Read() expecting error '%v' after Truncate, received '%v'", errTokenReaderInvalid, err)
+		}
+		// Invalidated by marker apply
+		//
+		m := l.Marker()
+		l.Next()
+		r = l.TokenReader()
+		m.Apply()
+		if _, err := r.Read(p); err != errTokenReaderInvalid {
+			t.Errorf("TokenReader.Read() expecting error '%v' after Marker.Apply, received '%v'", errTokenReaderInvalid, err)
+		}
+		// Invalidated by emit
+		//
+		l.Next()
+		r = l.TokenReader()
+		l.EmitToken(TStart)
+		if _, err := r.Read(p); err != errTokenReaderInvalid {
+			t.Errorf("TokenReader.Read() expecting error '%v', received '%v'", errTokenReaderInvalid, err)
+		}
+		return nil
+	}
+	nexter := LexString("aé字!", fn)
+	expectNexterNext(t, nexter, TStart, "aé字!", 1, 1)
+	expectNexterEOF(t, nexter)
+}
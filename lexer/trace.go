@@ -0,0 +1,91 @@
+package lexer
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TraceKind identifies the lexer activity that generated a TraceEvent.
+//
+type TraceKind int
+
+// Trace event kinds.
+//
+const (
+	TraceEnter  TraceKind = iota // Lexer function entered
+	TraceExit                    // Lexer function exited
+	TraceNext                    // Rune matched via Next()
+	TracePeek                    // Rune reviewed via Peek()
+	TraceEmit                    // Token emitted (including errors and EOF)
+	TraceMarker                  // Marker created
+	TraceApply                   // Marker applied
+	TraceClear                   // Matched runes discarded via Clear()
+)
+
+var traceKindNames = [...]string{"Enter", "Exit", "Next", "Peek", "Emit", "Marker", "Apply", "Clear"}
+
+// String returns the name of the trace kind.
+//
+func (k TraceKind) String() string {
+	if k < 0 || int(k) >= len(traceKindNames) {
+		return fmt.Sprintf("TraceKind(%d)", int(k))
+	}
+	return traceKindNames[k]
+}
+
+// TraceEvent describes a single lexer activity, delivered to the function registered via WithTrace.
+//
+type TraceEvent struct {
+	Kind  TraceKind      // The activity that generated the event
+	Fn    string         // Name of the current lexer function
+	Pos   token.Position // Starting position of the current match (see Token.Line() and Token.Column())
+	Rune  rune           // The rune for Next and Peek events
+	Type  token.Type     // The token type for Emit events
+	Value string         // The token value for Emit events, the name of the returned function for Exit events
+}
+
+// String returns a single-line description of the event, suitable for logging.
+//
+func (e TraceEvent) String() string {
+	switch e.Kind {
+	case TraceNext, TracePeek:
+		return fmt.Sprintf("%s %s %q (%s)", e.Pos, e.Kind, e.Rune, e.Fn)
+	case TraceEmit:
+		return fmt.Sprintf("%s %s %d %q (%s)", e.Pos, e.Kind, e.Type, e.Value, e.Fn)
+	case TraceExit:
+		return fmt.Sprintf("%s %s (%s) -> %s", e.Pos, e.Kind, e.Fn, e.Value)
+	default:
+		return fmt.Sprintf("%s %s (%s)", e.Pos, e.Kind, e.Fn)
+	}
+}
+
+// traceEvent delivers an event to the trace function, if any.
+//
+func (l *Lexer) traceEvent(kind TraceKind, r rune, typ token.Type, value string) {
+	if l.trace == nil {
+		return
+	}
+	l.trace(TraceEvent{
+		Kind:  kind,
+		Fn:    fnName(l.nextFn),
+		Pos:   l.matchStart(),
+		Rune:  r,
+		Type:  typ,
+		Value: value,
+	})
+}
+
+// fnName returns the name of the lexer function, or "" if fn is nil.
+//
+func fnName(fn Fn) string {
+	if fn == nil {
+		return ""
+	}
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+// lexTraceB matches 'b', clearing it
+//
+func lexTraceB(l *Lexer) Fn {
+	l.Next()
+	l.Clear()
+	return nil
+}
+
+// lexTraceA matches 'a', using a marker to try (and fail) to match 'x'
+//
+func lexTraceA(l *Lexer) Fn {
+	l.Next()
+	m := l.Marker()
+	if l.Peek(1) != 'x' {
+		m.Apply()
+	}
+	l.EmitToken(TStart)
+	return lexTraceB
+}
+
+// TestWithTrace
+//
+func TestWithTrace(t *testing.T) {
+	var events []string
+	trace := func(e TraceEvent) {
+		s := e.String()
+		s = strings.Replace(s, "github.com/tekwizely/go-parsing/lexer.", "", -1)
+		events = append(events, s)
+	}
+	nexter := LexString("ab", lexTraceA, WithTrace(trace))
+	expectNexterNext(t, nexter, TStart, "a", 1, 1)
+	expectNexterEOF(t, nexter)
+	expected := []string{
+		"1:1 Enter (lexTraceA)",
+		"1:1 Next 'a' (lexTraceA)",
+		"1:1 Marker (lexTraceA)",
+		"1:1 Peek 'b' (lexTraceA)",
+		"1:1 Apply (lexTraceA)",
+		"1:2 Emit 3 \"a\" (lexTraceA)",
+		"1:2 Exit (lexTraceA) -> lexTraceB",
+		"1:2 Enter (lexTraceB)",
+		"1:2 Next 'b' (lexTraceB)",
+		"1:2 Clear (lexTraceB)",
+		"1:3 Exit (lexTraceB) -> ",
+		"1:3 Emit 2 \"\" ()",
+	}
+	if strings.Join(events, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expecting trace:\n%s\nreceived:\n%s", strings.Join(expected, "\n"), strings.Join(events, "\n"))
+	}
+}
+
+// TestTraceKindString
+//
+func TestTraceKindString(t *testing.T) {
+	if s := TraceClear.String(); s != "Clear" {
+		t.Errorf("TraceKind.String() expecting 'Clear', received '%s'", s)
+	}
+	if s := TraceKind(99).String(); s != "TraceKind(99)" {
+		t.Errorf("TraceKind.String() expecting 'TraceKind(99)', received '%s'", s)
+	}
+}
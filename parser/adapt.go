@@ -0,0 +1,126 @@
+package parser
+
+import "io"
+
+// Filter wraps an ASTNexter, skipping ASTs for which keep returns false.
+// Errors are always passed through.
+//
+func Filter(n ASTNexter, keep func(ast interface{}) bool) ASTNexter {
+	return &filterNexter{asts: n, keep: keep}
+}
+
+// filterNexter is the ASTNexter implementation returned by Filter.
+//
+type filterNexter struct {
+	asts ASTNexter
+	keep func(ast interface{}) bool
+}
+
+// Next implements ASTNexter.Next().
+//
+func (n *filterNexter) Next() (interface{}, error) {
+	for {
+		ast, err := n.asts.Next()
+		if ast == nil || err != nil || n.keep(ast) {
+			return ast, err
+		}
+	}
+}
+
+// Map wraps an ASTNexter, replacing each AST with the result of fn.
+// An error returned from fn is delivered in place of the AST; As with errors emitted by the parser, mapping continues
+// with the next AST.
+// Errors are always passed through.
+//
+func Map(n ASTNexter, fn func(ast interface{}) (interface{}, error)) ASTNexter {
+	return &mapNexter{asts: n, fn: fn}
+}
+
+// mapNexter is the ASTNexter implementation returned by Map.
+//
+type mapNexter struct {
+	asts ASTNexter
+	fn   func(ast interface{}) (interface{}, error)
+}
+
+// Next implements ASTNexter.Next().
+//
+func (n *mapNexter) Next() (interface{}, error) {
+	ast, err := n.asts.Next()
+	if ast == nil || err != nil {
+		return ast, err
+	}
+	return n.fn(ast)
+}
+
+// Collect consumes the ASTNexter until io.EOF, returning the ASTs along with the first non-EOF error encountered (if
+// any).
+// Unlike ParseAll, collecting continues past errors, as they may be recoverable.
+//
+func Collect(n ASTNexter) (asts []interface{}, err error) {
+	for {
+		ast, e := n.Next()
+		if e == io.EOF {
+			return asts, err
+		}
+		if e != nil && err == nil {
+			err = e
+		}
+		if ast != nil {
+			asts = append(asts, ast)
+		}
+	}
+}
+
+// Batch wraps an ASTNexter, grouping the ASTs into slices ([]interface{}) of up to size ASTs.
+// The final batch may be smaller.
+// An error ends the current batch early; The batch is delivered first (if not empty), followed by the error.
+// Panics if size < 1.
+//
+func Batch(n ASTNexter, size int) ASTNexter {
+	if size < 1 {
+		panic("Batch: size must be >= 1")
+	}
+	return &batchNexter{asts: n, size: size}
+}
+
+// batchNexter is the ASTNexter implementation returned by Batch.
+//
+type batchNexter struct {
+	asts    ASTNexter
+	size    int
+	pending error // Error to deliver after the current batch
+	eof     bool
+}
+
+// Next implements ASTNexter.Next().
+//
+func (n *batchNexter) Next() (interface{}, error) {
+	if n.pending != nil {
+		err := n.pending
+		n.pending = nil
+		return nil, err
+	}
+	var batch []interface{}
+	for !n.eof && len(batch) < n.size {
+		ast, err := n.asts.Next()
+		if err == io.EOF {
+			n.eof = true
+			break
+		}
+		if err != nil {
+			if len(batch) == 0 {
+				return nil, err
+			}
+			n.pending = err
+			return batch, nil
+		}
+		if ast != nil {
+			batch = append(batch, ast)
+		}
+	}
+	if len(batch) == 0 {
+		return nil, io.EOF
+	}
+	return batch, nil
+}
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// expectNexterBatch confirms Next() == ($match, nil)
+//
+func expectNexterBatch(t *testing.T, nexter ASTNexter, match ...interface{}) {
+	ast, err := nexter.Next()
+	if err != nil || !reflect.DeepEqual(ast, match) {
+		t.Errorf("Nexter.Next() expecting (%v, nil), received (%v, %v)", match, ast, err)
+	}
+}
+
+// TestFilter
+//
+func TestFilter(t *testing.T) {
+	nexter := Filter(Parse(positioned(TOne, TOne, TTwo, TOne), parseTee), func(ast interface{}) bool {
+		return ast != "b"
+	})
+	expectNexterNext(t, nexter, "a")
+	expectNexterError(t, nexter, "not one")
+	expectNexterNext(t, nexter, "d")
+	expectNexterEOF(t, nexter)
+}
+
+// TestMap
+//
+func TestMap(t *testing.T) {
+	nexter := Map(Parse(positioned(TOne, TTwo, TOne, TOne), parseTee), func(ast interface{}) (interface{}, error) {
+		if ast == "c" {
+			return nil, fmt.Errorf("bad %v", ast)
+		}
+		return strings.ToUpper(ast.(string)), nil
+	})
+	expectNexterNext(t, nexter, "A")
+	expectNexterError(t, nexter, "not one")
+	expectNexterError(t, nexter, "bad c")
+	expectNexterNext(t, nexter, "D")
+	expectNexterEOF(t, nexter)
+}
+
+// TestCollect
+//
+func TestCollect(t *testing.T) {
+	asts, err := Collect(Parse(positioned(TOne, TTwo, TOne, TThree), parseTee))
+	if !reflect.DeepEqual(asts, []interface{}{"a", "c"}) {
+		t.Errorf("Collect() expecting [a c], received %v", asts)
+	}
+	if err == nil || err.Error() != "not one" {
+		t.Errorf("Collect() expecting error 'not one', received '%v'", err)
+	}
+	asts, err = Collect(Parse(positioned(), parseTee))
+	if asts != nil || err != nil {
+		t.Errorf("Collect() expecting (nil, nil), received (%v, %v)", asts, err)
+	}
+}
+
+// TestBatch
+//
+func TestBatch(t *testing.T) {
+	nexter := Batch(Parse(positioned(TOne, TOne, TOne, TTwo, TOne, TOne, TOne), parseTee), 2)
+	expectNexterBatch(t, nexter, "a", "b")
+	expectNexterBatch(t, nexter, "c")
+	expectNexterError(t, nexter, "not one")
+	expectNexterBatch(t, nexter, "e", "f")
+	expectNexterBatch(t, nexter, "g")
+	expectNexterEOF(t, nexter)
+	expectNexterEOF(t, nexter)
+}
+
+// TestBatchError
+//
+func TestBatchError(t *testing.T) {
+	nexter := Batch(Parse(positioned(TTwo, TOne), parseTee), 2)
+	expectNexterError(t, nexter, "not one")
+	expectNexterBatch(t, nexter, "b")
+	expectNexterEOF(t, nexter)
+}
+
+// TestBatchSize
+//
+func TestBatchSize(t *testing.T) {
+	assertPanic(t, func() {
+		Batch(Parse(positioned(), parseTee), 0)
+	}, "Batch: size must be >= 1")
+}
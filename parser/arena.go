@@ -0,0 +1,125 @@
+package parser
+
+import "github.com/tekwizely/go-parsing/lexer/token"
+
+// defaultArenaSize is the number of entries in each arena slab, when not specified.
+//
+const defaultArenaSize = 1024
+
+// Arena is a bump allocator for the slices that make up ASTs (i.e. child lists and matched tokens), cutting GC
+// pressure for batch tools (i.e. compilers) that parse thousands of inputs.
+// Slices are carved from slabs, which are retained and reused once the arena is released, either directly via
+// Release, or via the Release method of the ASTNexter.
+// When enabled, the parser also allocates the slices returned from Matched() from the arena.
+// Slices allocated from the arena must not be used after it is released, and must not be appended to beyond their
+// length.
+// An Arena is not safe for concurrent use; Share it only between parsers used from the same goroutine.
+// See WithArena and Parser.Arena.
+//
+type Arena struct {
+	values    [][]interface{} // Slabs for Values()
+	valueSlab int             // Index of the current values slab
+	valueUsed int             // Entries used in the current values slab
+	tokens    [][]token.Token // Slabs for Tokens()
+	tokenSlab int             // Index of the current tokens slab
+	tokenUsed int             // Entries used in the current tokens slab
+	size      int             // Entries per slab
+	len       int             // Entries allocated since created or released
+}
+
+// NewArena returns a new, empty, Arena, allocating slices from slabs of the specified size.
+// Slices larger than the slab size are allocated directly, without using the arena.
+// A size < 1 uses a default size of 1024 entries.
+//
+func NewArena(size int) *Arena {
+	if size < 1 {
+		size = defaultArenaSize
+	}
+	return &Arena{size: size}
+}
+
+// Arena returns the arena specified via WithArena.
+// Returns nil if no arena is enabled.
+//
+func (p *Parser) Arena() *Arena {
+	return p.arena
+}
+
+// Len returns the number of slice entries allocated from the arena since it was created or last released.
+//
+func (a *Arena) Len() int {
+	return a.len
+}
+
+// Values returns a slice of n nil values, allocated from the arena.
+//
+func (a *Arena) Values(n int) []interface{} {
+	if n > a.size {
+		return make([]interface{}, n)
+	}
+	if len(a.values) == 0 {
+		a.values = append(a.values, make([]interface{}, a.size))
+	} else if a.valueUsed+n > a.size {
+		a.valueSlab++
+		a.valueUsed = 0
+		if a.valueSlab == len(a.values) {
+			a.values = append(a.values, make([]interface{}, a.size))
+		}
+	}
+	s := a.values[a.valueSlab][a.valueUsed : a.valueUsed+n : a.valueUsed+n]
+	a.valueUsed += n
+	a.len += n
+	return s
+}
+
+// Tokens returns a slice of n nil tokens, allocated from the arena.
+//
+func (a *Arena) Tokens(n int) []token.Token {
+	if n > a.size {
+		return make([]token.Token, n)
+	}
+	if len(a.tokens) == 0 {
+		a.tokens = append(a.tokens, make([]token.Token, a.size))
+	} else if a.tokenUsed+n > a.size {
+		a.tokenSlab++
+		a.tokenUsed = 0
+		if a.tokenSlab == len(a.tokens) {
+			a.tokens = append(a.tokens, make([]token.Token, a.size))
+		}
+	}
+	s := a.tokens[a.tokenSlab][a.tokenUsed : a.tokenUsed+n : a.tokenUsed+n]
+	a.tokenUsed += n
+	a.len += n
+	return s
+}
+
+// Release frees all slices allocated from the arena at once, retaining the slabs for reuse.
+// Slices allocated from the arena must not be used after this call.
+//
+func (a *Arena) Release() {
+	for i := 0; i < len(a.values) && i <= a.valueSlab; i++ {
+		slab := a.values[i]
+		for j := range slab {
+			slab[j] = nil // Release the referenced values
+		}
+	}
+	for i := 0; i < len(a.tokens) && i <= a.tokenSlab; i++ {
+		slab := a.tokens[i]
+		for j := range slab {
+			slab[j] = nil // Release the referenced tokens
+		}
+	}
+	a.valueSlab, a.valueUsed = 0, 0
+	a.tokenSlab, a.tokenUsed = 0, 0
+	a.len = 0
+}
+
+// Release frees all slices allocated from the parser's arena at once (see WithArena).
+// ASTs built from arena slices must not be used after this call.
+// Does nothing if the parser has no arena.
+//
+func (e *astNexter) Release() {
+	if e.parser.arena != nil {
+		e.parser.arena.Release()
+	}
+}
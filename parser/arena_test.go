@@ -0,0 +1,73 @@
+package parser
+
+import "testing"
+
+// parseArenaList emits the matched tokens along with their types, both allocated from the arena.
+//
+func parseArenaList(p *Parser) Fn {
+	for p.CanPeek(1) {
+		p.Next()
+	}
+	tokens := p.Matched()
+	types := p.Arena().Values(len(tokens))
+	for i, tok := range tokens {
+		types[i] = tok.Type()
+	}
+	p.Emit(types)
+	return nil
+}
+
+// TestWithArena
+//
+func TestWithArena(t *testing.T) {
+	a := NewArena(4)
+	nexter := Parse(mockLexer(TOne, TTwo, TThree), parseArenaList, WithArena(a))
+	ast, err := nexter.Next()
+	if err != nil {
+		t.Fatalf("ASTNexter.Next() returned error: %v", err)
+	}
+	expectNexterEOF(t, nexter)
+	types := ast.([]interface{})
+	if len(types) != 3 || types[0] != TOne || types[1] != TTwo || types[2] != TThree {
+		t.Errorf("ASTNexter.Next() expecting [1 2 3], received %v", types)
+	}
+	if a.Len() != 6 {
+		t.Errorf("Arena.Len() expecting 6, received %d", a.Len())
+	}
+	// Next allocation does not fit in the first slab
+	//
+	if s := a.Tokens(2); len(s) != 2 || cap(s) != 2 || len(a.tokens) != 2 {
+		t.Errorf("Arena.Tokens(2) expecting a new slab, received len %d cap %d in %d slabs", len(s), cap(s), len(a.tokens))
+	}
+	nexter.(interface{ Release() }).Release()
+	if a.Len() != 0 {
+		t.Errorf("Arena.Len() expecting 0 after Release(), received %d", a.Len())
+	}
+	if types[0] != nil {
+		t.Errorf("Arena.Release() expecting values to be released, received %v", types[0])
+	}
+	// Slabs are reused, oversized slices are allocated directly
+	//
+	if s := a.Values(5); len(s) != 5 || a.Len() != 0 {
+		t.Errorf("Arena.Values(5) expecting direct allocation, received len %d with Arena.Len() %d", len(s), a.Len())
+	}
+	if s := a.Tokens(3); len(s) != 3 || len(a.tokens) != 2 || &s[0] != &a.tokens[0][0] {
+		t.Errorf("Arena.Tokens(3) expecting first slab to be reused")
+	}
+}
+
+// TestMatchedWithoutArena
+//
+func TestMatchedWithoutArena(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.Next()
+		if tokens := p.Matched(); len(tokens) != 1 || tokens[0].Type() != TOne {
+			t.Errorf("Parser.Matched() expecting [TOne], received %v", tokens)
+		}
+		p.Emit("AST")
+		return nil
+	}
+	nexter := Parse(mockLexer(TOne), fn)
+	expectNexterNext(t, nexter, "AST")
+	nexter.(interface{ Release() }).Release() // No-op
+}
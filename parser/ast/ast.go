@@ -0,0 +1,54 @@
+/*
+Package ast provides utilities for working with the Abstract Syntax Trees emitted from the parser.
+
+The parser itself places no requirements on the values you emit.
+The utilities in this package operate on values that implement the Node interface,
+allowing passes such as desugaring and linting to be built on top of your AST.
+
+*/
+package ast
+
+import "reflect"
+
+// Node is implemented by AST nodes that expose their children for traversal and rewriting.
+//
+type Node interface {
+
+	// Children returns the child nodes, in order.
+	// Can return nil (or an empty slice) for leaf nodes.
+	// Entries within the slice may be nil, representing absent (optional) children.
+	//
+	Children() []Node
+
+	// WithChildren returns a node equivalent to the receiver, but with its children replaced.
+	// Implementations must not modify the receiver, as the original node may still be referenced
+	// by other trees (see Rewrite for details on structural sharing).
+	// The number of children may differ from the original when children have been inserted or deleted.
+	//
+	WithChildren(children []Node) Node
+}
+
+// same confirms if a and b are the same node.
+// Slice- and map-backed nodes are compared by identity (same backing array and length, or same map).
+// Nodes with other non-comparable dynamic types (i.e. structs holding a slice) are never considered the same.
+//
+func same(a Node, b Node) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb {
+		return false
+	}
+	if ta.Comparable() {
+		return a == b
+	}
+	switch ta.Kind() {
+	case reflect.Slice:
+		va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+		return va.Pointer() == vb.Pointer() && va.Len() == vb.Len()
+	case reflect.Map:
+		return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+	}
+	return false
+}
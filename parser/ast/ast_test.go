@@ -0,0 +1,127 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+// testNode is a minimal Node used in various tests
+//
+type testNode struct {
+	name     string
+	children []Node
+}
+
+func (n *testNode) Children() []Node {
+	return n.children
+}
+func (n *testNode) WithChildren(children []Node) Node {
+	return &testNode{name: n.name, children: children}
+}
+
+// node creates a testNode
+//
+func node(name string, children ...Node) *testNode {
+	return &testNode{name: name, children: children}
+}
+
+// dump renders a tree as "name(child,child)"
+//
+func dump(n Node) string {
+	if n == nil {
+		return "nil"
+	}
+	b := &strings.Builder{}
+	if tn, ok := n.(*testNode); ok {
+		b.WriteString(tn.name)
+	} else {
+		b.WriteString("list")
+	}
+	if children := n.Children(); len(children) > 0 {
+		b.WriteString("(")
+		for i, child := range children {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(dump(child))
+		}
+		b.WriteString(")")
+	}
+	return b.String()
+}
+
+// expectTree
+//
+func expectTree(t *testing.T, n Node, match string) {
+	if s := dump(n); s != match {
+		t.Errorf("Expecting tree '%s', received '%s'", match, s)
+	}
+}
+
+// expectSame
+//
+func expectSame(t *testing.T, a Node, b Node, match bool) {
+	if same(a, b) != match {
+		t.Errorf("Expecting same(%s, %s) to return '%t'", dump(a), dump(b), match)
+	}
+}
+
+// assertPanic
+//
+func assertPanic(t *testing.T, f func(), msg string) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("assertPanic: did not generate panic()")
+		} else if r != msg {
+			t.Errorf("assertPanic: recover() recieved message '%s' instead of '%s'", r, msg)
+		}
+	}()
+	f()
+}
+
+// valueNode is a non-comparable Node
+//
+type valueNode struct {
+	children []Node
+}
+
+func (n valueNode) Children() []Node {
+	return n.children
+}
+func (n valueNode) WithChildren(children []Node) Node {
+	return valueNode{children: children}
+}
+
+// listNode is a slice-backed Node
+//
+type listNode []Node
+
+func (n listNode) Children() []Node {
+	return n
+}
+func (n listNode) WithChildren(children []Node) Node {
+	return listNode(children)
+}
+
+// TestSame
+//
+func TestSame(t *testing.T) {
+	a := node("a")
+	expectSame(t, a, a, true)
+	expectSame(t, a, node("a"), false)
+	expectSame(t, nil, nil, true)
+	expectSame(t, a, nil, false)
+	if same(valueNode{}, valueNode{}) {
+		t.Error("Expecting non-comparable nodes to never be the same")
+	}
+	l := listNode{a, node("b")}
+	if !same(l, l) {
+		t.Error("Expecting slice-backed node to be the same as itself")
+	}
+	if same(l, listNode{a, l[1]}) {
+		t.Error("Expecting slice-backed nodes with different backing arrays to not be the same")
+	}
+	if same(l, l[:1]) {
+		t.Error("Expecting slice-backed nodes with different lengths to not be the same")
+	}
+}
@@ -0,0 +1,162 @@
+package ast
+
+// ApplyFn are user functions called by Apply for each node visited.
+// The return value controls the traversal; See Apply for details.
+//
+type ApplyFn func(*Cursor) bool
+
+// Cursor describes a node encountered during Apply and provides methods to edit the tree in place of that node.
+// Edits never modify existing nodes; Instead, the affected parents are rebuilt via Node.WithChildren().
+// A Cursor is only valid during the ApplyFn call it was passed to.
+//
+type Cursor struct {
+	parent  Node
+	index   int
+	node    Node
+	before  []Node
+	after   []Node
+	deleted bool
+}
+
+// Node returns the current node.
+// If the node has been replaced, the replacement is returned.
+// Returns nil if the node has been deleted.
+//
+func (c *Cursor) Node() Node {
+	if c.deleted {
+		return nil
+	}
+	return c.node
+}
+
+// Parent returns the parent of the current node, as it was before any edits to its children were applied.
+// Returns nil for the root node.
+//
+func (c *Cursor) Parent() Node {
+	return c.parent
+}
+
+// Index returns the index of the current node within the children of Parent().
+// Returns -1 for the root node.
+//
+func (c *Cursor) Index() int {
+	return c.index
+}
+
+// Replace replaces the current node with n.
+// When called from a pre-order ApplyFn, the children of n are traversed instead of the original children.
+// Panics if the current node has been deleted.
+//
+func (c *Cursor) Replace(n Node) {
+	if c.deleted {
+		panic("Cursor.Replace: node has been deleted")
+	}
+	c.node = n
+}
+
+// Delete removes the current node from its parent.
+// Panics if called on the root node.
+//
+func (c *Cursor) Delete() {
+	if c.parent == nil {
+		panic("Cursor.Delete: cannot delete root node")
+	}
+	c.deleted = true
+}
+
+// InsertBefore inserts n before the current node within its parent.
+// Inserted nodes are not traversed.
+// Panics if called on the root node.
+//
+func (c *Cursor) InsertBefore(n Node) {
+	if c.parent == nil {
+		panic("Cursor.InsertBefore: cannot insert around root node")
+	}
+	c.before = append(c.before, n)
+}
+
+// InsertAfter inserts n after the current node within its parent.
+// Multiple inserts appear in the order they were made.
+// Inserted nodes are not traversed.
+// Panics if called on the root node.
+//
+func (c *Cursor) InsertAfter(n Node) {
+	if c.parent == nil {
+		panic("Cursor.InsertAfter: cannot insert around root node")
+	}
+	c.after = append(c.after, n)
+}
+
+// Apply traverses the tree rooted at root, calling pre before, and post after, visiting the children of each node.
+// Either function may be nil.
+// If pre returns false, the children of the node (and post for the node) are skipped.
+// If post returns false, the traversal stops; Edits made up to that point are retained.
+// Nil children are not visited.
+// Any node whose children were edited is rebuilt via Node.WithChildren(), as are its ancestors.
+// Unchanged subtrees are shared between the original tree and the returned tree.
+// Returns the (possibly new) root.
+//
+func Apply(root Node, pre ApplyFn, post ApplyFn) Node {
+	if root == nil {
+		return nil
+	}
+	a := &applier{pre: pre, post: post}
+	c := &Cursor{index: -1, node: root}
+	a.apply(c)
+	return c.node
+}
+
+// applier holds the traversal state for Apply.
+//
+type applier struct {
+	pre     ApplyFn
+	post    ApplyFn
+	stopped bool
+}
+
+// apply visits the node under the cursor, along with its children.
+//
+func (a *applier) apply(c *Cursor) {
+	if a.pre != nil && !a.pre(c) {
+		return
+	}
+	if c.deleted || c.node == nil {
+		return
+	}
+	c.node = a.applyChildren(c.node)
+	if a.stopped {
+		return
+	}
+	if a.post != nil && !a.post(c) {
+		a.stopped = true
+	}
+}
+
+// applyChildren visits the children of parent, returning parent if no edits were made,
+// otherwise returning a rebuilt parent.
+//
+func (a *applier) applyChildren(parent Node) Node {
+	children := parent.Children()
+	out := make([]Node, 0, len(children))
+	changed := false
+	for i, child := range children {
+		if child == nil || a.stopped {
+			out = append(out, child)
+			continue
+		}
+		c := &Cursor{parent: parent, index: i, node: child}
+		a.apply(c)
+		out = append(out, c.before...)
+		if !c.deleted {
+			out = append(out, c.node)
+		}
+		out = append(out, c.after...)
+		if c.deleted || len(c.before) > 0 || len(c.after) > 0 || !same(c.node, child) {
+			changed = true
+		}
+	}
+	if !changed {
+		return parent
+	}
+	return parent.WithChildren(out)
+}
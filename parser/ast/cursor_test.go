@@ -0,0 +1,219 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+// nameIs returns an ApplyFn that calls fn when the current node has the specified name.
+//
+func nameIs(name string, fn func(c *Cursor)) ApplyFn {
+	return func(c *Cursor) bool {
+		if n, ok := c.Node().(*testNode); ok && n.name == name {
+			fn(c)
+		}
+		return true
+	}
+}
+
+// TestApplyNoop
+//
+func TestApplyNoop(t *testing.T) {
+	root := node("a", node("b"), node("c"))
+	result := Apply(root, nil, nil)
+	expectSame(t, result, root, true)
+}
+
+// TestApplyNilRoot
+//
+func TestApplyNilRoot(t *testing.T) {
+	if Apply(nil, nil, nil) != nil {
+		t.Error("Apply(nil) expecting nil")
+	}
+}
+
+// TestCursorParentIndex
+//
+func TestCursorParentIndex(t *testing.T) {
+	b := node("b", node("c"))
+	root := node("a", node("x"), b)
+	Apply(root, func(c *Cursor) bool {
+		switch c.Node().(*testNode).name {
+		case "a":
+			if c.Parent() != nil || c.Index() != -1 {
+				t.Error("Cursor expecting root to have nil parent and index -1")
+			}
+		case "c":
+			if c.Parent() != b || c.Index() != 0 {
+				t.Error("Cursor expecting 'c' to have parent 'b' and index 0")
+			}
+		case "b":
+			if c.Parent() != root || c.Index() != 1 {
+				t.Error("Cursor expecting 'b' to have parent 'a' and index 1")
+			}
+		}
+		return true
+	}, nil)
+}
+
+// TestCursorReplace
+//
+func TestCursorReplace(t *testing.T) {
+	root := node("a", node("b"), node("c"))
+	result := Apply(root, nameIs("b", func(c *Cursor) { c.Replace(node("x")) }), nil)
+	expectTree(t, result, "a(x,c)")
+	expectTree(t, root, "a(b,c)")
+}
+
+// TestCursorReplaceRoot
+//
+func TestCursorReplaceRoot(t *testing.T) {
+	root := node("a", node("b"))
+	result := Apply(root, nil, nameIs("a", func(c *Cursor) { c.Replace(node("x")) }))
+	expectTree(t, result, "x")
+}
+
+// TestCursorReplacePreTraversesReplacement
+//
+func TestCursorReplacePreTraversesReplacement(t *testing.T) {
+	root := node("a", node("b", node("c")))
+	var visited []string
+	Apply(root, func(c *Cursor) bool {
+		if n := c.Node().(*testNode); n.name == "b" {
+			c.Replace(node("x", node("y")))
+		}
+		visited = append(visited, c.Node().(*testNode).name)
+		return true
+	}, nil)
+	if s := strings.Join(visited, ","); s != "a,x,y" {
+		t.Errorf("Apply expecting to visit 'a,x,y', visited '%s'", s)
+	}
+}
+
+// TestCursorDelete
+//
+func TestCursorDelete(t *testing.T) {
+	root := node("a", node("b"), node("c"), node("d"))
+	result := Apply(root, nameIs("c", func(c *Cursor) {
+		c.Delete()
+		if c.Node() != nil {
+			t.Error("Cursor.Node() expecting nil after Delete()")
+		}
+	}), nil)
+	expectTree(t, result, "a(b,d)")
+}
+
+// TestCursorDeleteRoot
+//
+func TestCursorDeleteRoot(t *testing.T) {
+	assertPanic(t, func() {
+		Apply(node("a"), func(c *Cursor) bool {
+			c.Delete()
+			return true
+		}, nil)
+	}, "Cursor.Delete: cannot delete root node")
+}
+
+// TestCursorReplaceAfterDelete
+//
+func TestCursorReplaceAfterDelete(t *testing.T) {
+	assertPanic(t, func() {
+		Apply(node("a", node("b")), nameIs("b", func(c *Cursor) {
+			c.Delete()
+			c.Replace(node("x"))
+		}), nil)
+	}, "Cursor.Replace: node has been deleted")
+}
+
+// TestCursorInsert
+//
+func TestCursorInsert(t *testing.T) {
+	root := node("a", node("b"), node("c"))
+	result := Apply(root, nameIs("b", func(c *Cursor) {
+		c.InsertBefore(node("w"))
+		c.InsertAfter(node("x"))
+		c.InsertAfter(node("y"))
+	}), nil)
+	expectTree(t, result, "a(w,b,x,y,c)")
+}
+
+// TestCursorInsertNotTraversed
+//
+func TestCursorInsertNotTraversed(t *testing.T) {
+	root := node("a", node("b"))
+	Apply(root, func(c *Cursor) bool {
+		n := c.Node().(*testNode)
+		if n.name == "x" {
+			t.Error("Apply should not visit inserted nodes")
+		}
+		if n.name == "b" {
+			c.InsertAfter(node("x"))
+		}
+		return true
+	}, nil)
+}
+
+// TestCursorInsertRoot
+//
+func TestCursorInsertRoot(t *testing.T) {
+	assertPanic(t, func() {
+		Apply(node("a"), func(c *Cursor) bool {
+			c.InsertBefore(node("x"))
+			return true
+		}, nil)
+	}, "Cursor.InsertBefore: cannot insert around root node")
+	assertPanic(t, func() {
+		Apply(node("a"), func(c *Cursor) bool {
+			c.InsertAfter(node("x"))
+			return true
+		}, nil)
+	}, "Cursor.InsertAfter: cannot insert around root node")
+}
+
+// TestApplyPreSkipsChildren
+//
+func TestApplyPreSkipsChildren(t *testing.T) {
+	root := node("a", node("b", node("c")), node("d"))
+	var visited []string
+	Apply(root, func(c *Cursor) bool {
+		n := c.Node().(*testNode)
+		visited = append(visited, n.name)
+		return n.name != "b"
+	}, func(c *Cursor) bool {
+		if c.Node().(*testNode).name == "b" {
+			t.Error("Apply should not call post when pre returns false")
+		}
+		return true
+	})
+	if s := strings.Join(visited, ","); s != "a,b,d" {
+		t.Errorf("Apply expecting to visit 'a,b,d', visited '%s'", s)
+	}
+}
+
+// TestApplyPostStops
+//
+func TestApplyPostStops(t *testing.T) {
+	root := node("a", node("b"), node("c"), node("d"))
+	result := Apply(root, nil, func(c *Cursor) bool {
+		n := c.Node().(*testNode)
+		switch n.name {
+		case "b":
+			c.Replace(node("x"))
+			return false
+		case "c", "d":
+			t.Errorf("Apply should not visit '%s' after post returns false", n.name)
+		}
+		return true
+	})
+	// Edits before the stop are retained
+	//
+	expectTree(t, result, "a(x,c,d)")
+}
+
+// TestApplyNilChildren
+//
+func TestApplyNilChildren(t *testing.T) {
+	root := node("a", nil, node("b"))
+	result := Apply(root, nameIs("b", func(c *Cursor) { c.Replace(node("x")) }), nil)
+	expectTree(t, result, "a(nil,x)")
+}
@@ -0,0 +1,194 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// EditKind identifies the kind of an Edit.
+//
+type EditKind int
+
+// Edit kinds
+//
+const (
+	Insert  EditKind = iota + 1 // A node present only in the new tree
+	Delete                      // A node present only in the old tree
+	Replace                     // A node that differs (other than in its children) between the trees
+)
+
+// String implements fmt.Stringer, i.e. "insert".
+//
+func (k EditKind) String() string {
+	switch k {
+	case Insert:
+		return "insert"
+	case Delete:
+		return "delete"
+	case Replace:
+		return "replace"
+	}
+	return fmt.Sprintf("EditKind(%d)", int(k))
+}
+
+// Edit describes a single difference between two trees.
+// Paths are the child indices leading from the root to the node, i.e. []int{1, 0} is the first child of the second
+// child of the root; The root itself has an empty path.
+//
+type Edit struct {
+	Kind    EditKind
+	OldPath []int // Path of Old within the old tree; nil for Insert
+	NewPath []int // Path of New within the new tree; nil for Delete
+	Old     Node  // Subtree removed from the old tree; nil for Insert (or an absent child being replaced)
+	New     Node  // Subtree added to the new tree; nil for Delete (or a child being replaced by an absent child)
+}
+
+// String implements fmt.Stringer, i.e. "replace [0 1] -> [0 1]".
+//
+func (e Edit) String() string {
+	switch e.Kind {
+	case Insert:
+		return fmt.Sprintf("%s %v", e.Kind, e.NewPath)
+	case Delete:
+		return fmt.Sprintf("%s %v", e.Kind, e.OldPath)
+	}
+	return fmt.Sprintf("%s %v -> %v", e.Kind, e.OldPath, e.NewPath)
+}
+
+// Diff compares the trees rooted at a and b, returning the edits that transform a into b, in pre-order.
+// Returns nil if the trees are equal.
+//
+// Two nodes are equal if they are the same node (see Rewrite for details on structural sharing), or if they have the
+// same dynamic type, are reflect.DeepEqual once their children are removed (via Node.WithChildren(nil)), and their
+// children are equal.
+// Any positions recorded within your nodes are therefore compared as well.
+//
+// The children of matching nodes are aligned via a longest common subsequence of equal (non-nil) children;
+// The remaining children between aligned pairs are compared index by index, with any excess reported as Insert or
+// Delete edits.
+// A pair of nodes that differ other than in their children is reported as a single Replace edit, covering their
+// subtrees.
+//
+func Diff(a Node, b Node) []Edit {
+	d := &differ{}
+	d.diff(a, b, []int{}, []int{})
+	return d.edits
+}
+
+// differ collects the edits for Diff.
+//
+type differ struct {
+	edits []Edit
+}
+
+// diff compares the nodes at the given paths.
+//
+func (d *differ) diff(a Node, b Node, aPath []int, bPath []int) {
+	if same(a, b) {
+		return
+	}
+	if a == nil || b == nil || !shallowEqual(a, b) {
+		d.edits = append(d.edits, Edit{Kind: Replace, OldPath: aPath, NewPath: bPath, Old: a, New: b})
+		return
+	}
+	d.diffChildren(a.Children(), b.Children(), aPath, bPath)
+}
+
+// diffChildren aligns the children of two matching nodes, comparing the unaligned children.
+//
+func (d *differ) diffChildren(as []Node, bs []Node, aPath []int, bPath []int) {
+	i, j := 0, 0
+	for _, pair := range align(as, bs) {
+		d.diffGap(as, bs, i, pair[0], j, pair[1], aPath, bPath)
+		i, j = pair[0]+1, pair[1]+1
+	}
+	d.diffGap(as, bs, i, len(as), j, len(bs), aPath, bPath)
+}
+
+// diffGap compares as[i:iEnd] with bs[j:jEnd] index by index, reporting any excess as deleted or inserted.
+//
+func (d *differ) diffGap(as []Node, bs []Node, i int, iEnd int, j int, jEnd int, aPath []int, bPath []int) {
+	for ; i < iEnd && j < jEnd; i, j = i+1, j+1 {
+		d.diff(as[i], bs[j], extend(aPath, i), extend(bPath, j))
+	}
+	for ; i < iEnd; i++ {
+		d.edits = append(d.edits, Edit{Kind: Delete, OldPath: extend(aPath, i), Old: as[i]})
+	}
+	for ; j < jEnd; j++ {
+		d.edits = append(d.edits, Edit{Kind: Insert, NewPath: extend(bPath, j), New: bs[j]})
+	}
+}
+
+// align returns the index pairs of a longest common subsequence of equal nodes within as and bs.
+// Absent (nil) children are never aligned, as they are only meaningful at their original index.
+//
+func align(as []Node, bs []Node) [][2]int {
+	// lengths[i][j] is the length of the LCS of as[i:] and bs[j:]
+	//
+	lengths := make([][]int, len(as)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(bs)+1)
+	}
+	for i := len(as) - 1; i >= 0; i-- {
+		for j := len(bs) - 1; j >= 0; j-- {
+			switch {
+			case as[i] != nil && equal(as[i], bs[j]):
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] >= lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+	var pairs [][2]int
+	for i, j := 0, 0; i < len(as) && j < len(bs); {
+		switch {
+		case as[i] != nil && equal(as[i], bs[j]):
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
+
+// equal confirms if the trees rooted at a and b are equal.
+//
+func equal(a Node, b Node) bool {
+	if same(a, b) {
+		return true
+	}
+	if a == nil || b == nil || !shallowEqual(a, b) {
+		return false
+	}
+	as, bs := a.Children(), b.Children()
+	if len(as) != len(bs) {
+		return false
+	}
+	for i := range as {
+		if !equal(as[i], bs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// shallowEqual confirms if a and b are equal, ignoring their children.
+//
+func shallowEqual(a Node, b Node) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	return reflect.DeepEqual(a.WithChildren(nil), b.WithChildren(nil))
+}
+
+// extend returns a copy of path with index appended.
+//
+func extend(path []int, index int) []int {
+	return append(append(make([]int, 0, len(path)+1), path...), index)
+}
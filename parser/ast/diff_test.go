@@ -0,0 +1,97 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// expectEdits compares edits against match, rendered as "kind paths old new", one per line
+//
+func expectEdits(t *testing.T, edits []Edit, match ...string) {
+	var lines []string
+	for _, e := range edits {
+		lines = append(lines, fmt.Sprintf("%s %s %s", e, dump(e.Old), dump(e.New)))
+	}
+	if s, m := strings.Join(lines, "\n"), strings.Join(match, "\n"); s != m {
+		t.Errorf("Expecting edits:\n%s\nreceived:\n%s", m, s)
+	}
+}
+
+// TestDiffEqual
+//
+func TestDiffEqual(t *testing.T) {
+	a := node("a", node("b", node("c")), nil, node("d"))
+	expectEdits(t, Diff(a, a))
+	expectEdits(t, Diff(a, node("a", node("b", node("c")), nil, node("d"))))
+	expectEdits(t, Diff(nil, nil))
+}
+
+// TestDiffReplace
+//
+func TestDiffReplace(t *testing.T) {
+	a := node("a", node("b", node("c")), node("d"))
+	b := node("a", node("b", node("x", node("y"))), node("d"))
+	expectEdits(t, Diff(a, b), "replace [0 0] -> [0 0] c x(y)")
+	expectEdits(t, Diff(a, node("z")), "replace [] -> [] a(b(c),d) z")
+	expectEdits(t, Diff(a, nil), "replace [] -> [] a(b(c),d) nil")
+}
+
+// TestDiffInsertDelete
+//
+func TestDiffInsertDelete(t *testing.T) {
+	a := node("a", node("b"), node("c"), node("d"))
+	expectEdits(t, Diff(a, node("a", node("b"), node("x"), node("c"), node("d"))), "insert [1] nil x")
+	expectEdits(t, Diff(a, node("a", node("b"), node("d"))), "delete [1] c nil")
+	expectEdits(t, Diff(a, node("a", node("c"), node("d"), node("e"))),
+		"delete [0] b nil",
+		"insert [2] nil e",
+	)
+}
+
+// TestDiffNested compares the unaligned children index by index, keeping paths into both trees
+//
+func TestDiffNested(t *testing.T) {
+	a := node("a", node("b"), node("c", node("d"), node("e")), node("f"))
+	b := node("a", node("x"), node("b"), node("c", node("e"), node("g")), node("f"))
+	expectEdits(t, Diff(a, b),
+		"insert [0] nil x",
+		"delete [1 0] d nil",
+		"insert [2 1] nil g",
+	)
+}
+
+// TestDiffAbsentChildren
+//
+func TestDiffAbsentChildren(t *testing.T) {
+	a := node("a", node("b"), nil)
+	expectEdits(t, Diff(a, node("a", node("b"), node("c"))), "replace [1] -> [1] nil c")
+	expectEdits(t, Diff(a, node("a", nil, nil)), "replace [0] -> [0] b nil")
+}
+
+// TestDiffValueNodes confirms non-comparable nodes are compared by value
+//
+func TestDiffValueNodes(t *testing.T) {
+	a := valueNode{children: []Node{node("b")}}
+	expectEdits(t, Diff(a, valueNode{children: []Node{node("b")}}))
+	expectEdits(t, Diff(a, valueNode{children: []Node{node("c")}}), "replace [0] -> [0] b c")
+	if edits := Diff(a, node("b")); len(edits) != 1 || edits[0].Kind != Replace {
+		t.Errorf("Expecting nodes of different types to be replaced, received %v", edits)
+	}
+}
+
+// TestDiffRewrite confirms a diff of a rewritten tree contains only the rewritten nodes
+//
+func TestDiffRewrite(t *testing.T) {
+	root := node("a", node("b", node("c")), node("d", node("c")))
+	result := Rewrite(root, func(n Node) Node {
+		if n.(*testNode).name == "c" {
+			return node("x")
+		}
+		return n
+	})
+	expectEdits(t, Diff(root, result),
+		"replace [0 0] -> [0 0] c x",
+		"replace [1 0] -> [1 0] c x",
+	)
+}
@@ -0,0 +1,24 @@
+package ast
+
+// Rewrite transforms the tree rooted at node by calling fn on every node, children first (post-order).
+// fn receives each node after its children have been rewritten, and returns the node to use in its place.
+// Return the node unchanged to keep it.
+// Returning nil removes the node from its parent; Returning nil for the root causes Rewrite to return nil.
+// Structural sharing is preserved: Subtrees that fn leaves unchanged are shared with the original tree,
+// and only nodes along the path to a change are rebuilt (via Node.WithChildren()).
+// Sharing relies on recognizing unchanged nodes: Pointer, slice and map-backed nodes are recognized by identity, and
+// other comparable nodes by ==, but nodes of other non-comparable types (i.e. structs holding a slice) are never
+// recognized, so every node along the path from such a node to the root is rebuilt; Use pointer nodes to avoid this.
+// The original tree is never modified.
+//
+func Rewrite(node Node, fn func(Node) Node) Node {
+	return Apply(node, nil, func(c *Cursor) bool {
+		n := fn(c.Node())
+		if n == nil && c.Parent() != nil {
+			c.Delete()
+		} else {
+			c.Replace(n)
+		}
+		return true
+	})
+}
@@ -0,0 +1,128 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRewriteIdentity
+//
+func TestRewriteIdentity(t *testing.T) {
+	root := node("a", node("b", node("c")), node("d"))
+	result := Rewrite(root, func(n Node) Node { return n })
+	expectSame(t, result, root, true)
+}
+
+// TestRewriteReplace
+//
+func TestRewriteReplace(t *testing.T) {
+	root := node("a", node("b", node("c")), node("d"))
+	result := Rewrite(root, func(n Node) Node {
+		if n.(*testNode).name == "c" {
+			return node("x")
+		}
+		return n
+	})
+	expectTree(t, result, "a(b(x),d)")
+	// Original untouched
+	//
+	expectTree(t, root, "a(b(c),d)")
+}
+
+// TestRewriteSharing
+//
+func TestRewriteSharing(t *testing.T) {
+	d := node("d", node("e"))
+	root := node("a", node("b", node("c")), d)
+	result := Rewrite(root, func(n Node) Node {
+		if n.(*testNode).name == "c" {
+			return node("x")
+		}
+		return n
+	})
+	expectSame(t, result.Children()[1], d, true)
+	expectSame(t, result.Children()[0], root.Children()[0], false)
+}
+
+// TestRewriteSharingSliceNode
+//
+func TestRewriteSharingSliceNode(t *testing.T) {
+	d := listNode{node("e")}
+	root := listNode{listNode{node("c")}, d}
+	result := Rewrite(root, func(n Node) Node {
+		if tn, ok := n.(*testNode); ok && tn.name == "c" {
+			return node("x")
+		}
+		return n
+	})
+	expectSame(t, result.Children()[1], d, true)
+	expectSame(t, result.Children()[0], root[0], false)
+	expectSame(t, Rewrite(root, func(n Node) Node { return n }), root, true)
+}
+
+// TestRewriteDelete
+//
+func TestRewriteDelete(t *testing.T) {
+	root := node("a", node("b"), node("c"), node("d"))
+	result := Rewrite(root, func(n Node) Node {
+		if n.(*testNode).name == "c" {
+			return nil
+		}
+		return n
+	})
+	expectTree(t, result, "a(b,d)")
+}
+
+// TestRewriteDeleteRoot
+//
+func TestRewriteDeleteRoot(t *testing.T) {
+	root := node("a", node("b"))
+	result := Rewrite(root, func(n Node) Node {
+		if n.(*testNode).name == "a" {
+			return nil
+		}
+		return n
+	})
+	expectTree(t, result, "nil")
+}
+
+// TestRewritePostOrder
+//
+func TestRewritePostOrder(t *testing.T) {
+	root := node("a", node("b", node("c")), node("d"))
+	var order []string
+	Rewrite(root, func(n Node) Node {
+		order = append(order, n.(*testNode).name)
+		return n
+	})
+	if s := strings.Join(order, ","); s != "c,b,d,a" {
+		t.Errorf("Rewrite expecting post-order 'c,b,d,a', received '%s'", s)
+	}
+}
+
+// TestRewriteSeesRewrittenChildren
+//
+func TestRewriteSeesRewrittenChildren(t *testing.T) {
+	root := node("a", node("b"), node("c"))
+	result := Rewrite(root, func(n Node) Node {
+		tn := n.(*testNode)
+		switch tn.name {
+		case "b":
+			return nil
+		case "a":
+			if len(tn.children) != 1 {
+				t.Errorf("Rewrite expecting 1 child, received %d", len(tn.children))
+			}
+		}
+		return n
+	})
+	expectTree(t, result, "a(c)")
+}
+
+// TestRewriteNil
+//
+func TestRewriteNil(t *testing.T) {
+	if Rewrite(nil, func(n Node) Node { return n }) != nil {
+		t.Error("Rewrite(nil) expecting nil")
+	}
+}
@@ -1,6 +1,10 @@
 package parser
 
-import "io"
+import (
+	"io"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
 
 // ASTNexter is returned by the Parse function and provides a means of retrieving ASTs emitted from the parser.
 //
@@ -18,21 +22,39 @@ type ASTNexter interface {
 // astNexter is the internal structure that backs the parser's ASTNexter.
 //
 type astNexter struct {
-	parser *Parser
-	next   interface{}
-	eof    bool
+	parser  *Parser
+	next    interface{}
+	eof     bool
+	pool    *Pool // Pool the nexter was taken from, nil if none - see Pool
+	misused bool  // Has the misuse error been returned? - see WithLenient()
 }
 
 // Next implements ASTNexter.Next().
 // We build on the previous HasNext/Next impl to keep changes minimal.
+// In lenient mode, any post-EOF usage is returned once, before io.EOF (see WithLenient).
 //
 func (e *astNexter) Next() (interface{}, error) {
 	if !e.hasNext() {
+		if e.parser.misuse != nil && !e.misused {
+			e.misused = true
+			return nil, e.parser.misuse
+		}
 		return nil, io.EOF
 	}
-	tok := e.next
+	ast := e.next
 	e.next = nil
-	return tok, nil
+	// Error?
+	//
+	if err, ok := ast.(*emitError); ok {
+		return nil, err.err
+	}
+	return ast, nil
+}
+
+// emitError wraps errors emitted via Parser.EmitError, distinguishing them from emitted ASTs.
+//
+type emitError struct {
+	err error
 }
 
 // hasNext Initiates calls to Parser.Fn functions and is the primary entry point for retrieving ASTs from the parser.
@@ -48,14 +70,31 @@ func (e *astNexter) hasNext() bool {
 	if e.eof {
 		return false
 	}
-	// If no ASTs available, try to fetch some.
+	// If no ASTs ready for delivery, try to fetch some.
 	//
-	for e.parser.output.Len() == 0 {
+	for !e.parser.outputReady() {
 		// Anyone to call?
 		// Any tokens to scan?
 		//
 		if e.parser.nextFn != nil && e.parser.CanPeek(1) {
-			e.parser.nextFn = e.parser.nextFn(e.parser)
+			e.parser.traceEvent(TraceEnter, nil, nil, "")
+			if e.parser.recorder != nil {
+				e.parser.recorder.record(e.parser.fnName(e.parser.nextFn), token.Start(e.parser.peekHead().Value.(token.Token)))
+			}
+			if e.parser.coverage != nil {
+				e.parser.coverage.hit(CoverFn, fnName(e.parser.nextFn))
+			}
+			fn, before := e.parser.nextFn, e.parser.progress()
+			if e.parser.stats != nil {
+				e.parser.stats.FnCalls++
+			}
+			e.parser.enterFn(fn)
+			nextFn := e.parser.callFn(fn)
+			e.parser.exitFn()
+			e.parser.traceEvent(TraceExit, nil, nil, e.parser.fnName(nextFn))
+			e.parser.nextFn = nextFn
+			e.parser.guardLoop(fn, before)
+			e.parser.guardErrors()
 		} else
 		// Parser Terminated, let's clean up.
 		// If EOF was never emitted, then emit it now.
@@ -66,8 +105,10 @@ func (e *astNexter) hasNext() bool {
 	}
 	// Consume the AST.
 	// We'll either cache it or discard it.
+	// Any remaining ASTs in the batch are delivered before calling more parser functions.
 	//
-	emit := e.parser.output.Remove(e.parser.output.Front())
+	emit := e.parser.output.PopFront()
+	e.parser.flushed = e.parser.output.Len() > 0
 	// Is if EOF?
 	//
 	if emit == nil {
@@ -81,3 +122,23 @@ func (e *astNexter) hasNext() bool {
 	e.next = emit
 	return true
 }
+
+// Drain consumes the ASTNexter until io.EOF, discarding the ASTs.
+// Returns the number of ASTs consumed, along with the first non-EOF error encountered (if any).
+// Draining continues past errors, as they may be recoverable.
+// Useful for benchmarking parsers end-to-end.
+//
+func Drain(n ASTNexter) (count int, err error) {
+	for {
+		ast, e := n.Next()
+		if e == io.EOF {
+			return count, err
+		}
+		if e != nil && err == nil {
+			err = e
+		}
+		if ast != nil {
+			count++
+		}
+	}
+}
@@ -3,6 +3,8 @@ package parser
 import (
 	"io"
 	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
 )
 
 // expectNexterEOF confirms Next() == (nil, io.EOF)
@@ -41,24 +43,23 @@ func expectNexterNext(t *testing.T, nexter ASTNexter, match string) {
 	}
 }
 
-// Disabled per deadcode nag
-// // expectNexterError confirms Next() == (nil, "$errMsg")
-// //
-// func expectNexterError(t *testing.T, nexter ASTNexter, errMsg string) {
-// 	ast, err := nexter.Next()
-// 	// Used switch per go-critic ifElseChain nag
-// 	//
-// 	switch {
-// 	case err == nil && ast == nil:
-// 		t.Errorf("Nexter.Next() expecting (nil, '%s'), received (nil, nil)", errMsg)
-// 	case err == nil && ast != nil:
-// 		t.Errorf("Nexter.Next() expecting (nil, '%s'), received ('%v', nil)", errMsg, ast)
-// 	case err != nil && ast != nil:
-// 		t.Errorf("Nexter.Next() expecting (nil, '%s'), received ('%v', '%s')", errMsg, ast, err.Error())
-// 	case err != nil && ast == nil && err.Error() != errMsg:
-// 		t.Errorf("Nexter.Next() expecting (nil, '%s'), received (nil, '%s')", errMsg, err.Error())
-// 	}
-// }
+// expectNexterError confirms Next() == (nil, "$errMsg")
+//
+func expectNexterError(t *testing.T, nexter ASTNexter, errMsg string) {
+	ast, err := nexter.Next()
+	// Used switch per go-critic ifElseChain nag
+	//
+	switch {
+	case err == nil && ast == nil:
+		t.Errorf("Nexter.Next() expecting (nil, '%s'), received (nil, nil)", errMsg)
+	case err == nil && ast != nil:
+		t.Errorf("Nexter.Next() expecting (nil, '%s'), received ('%v', nil)", errMsg, ast)
+	case err != nil && ast != nil:
+		t.Errorf("Nexter.Next() expecting (nil, '%s'), received ('%v', '%s')", errMsg, ast, err.Error())
+	case err != nil && ast == nil && err.Error() != errMsg:
+		t.Errorf("Nexter.Next() expecting (nil, '%s'), received (nil, '%s')", errMsg, err.Error())
+	}
+}
 
 // TestNexterHasNext1
 //
@@ -106,3 +107,47 @@ func TestNexterNextAfterEOF(t *testing.T) {
 	//
 	expectNexterEOF(t, nexter)
 }
+
+// TestDrain
+//
+func TestDrain(t *testing.T) {
+	var fn Fn
+	fn = func(p *Parser) Fn {
+		switch p.Next().Type() {
+		case TOne:
+			p.EmitError("error one")
+		case TTwo:
+			p.Emit("TTwo")
+		case TThree:
+			p.EmitError("error three")
+		}
+		return fn
+	}
+	count, err := Drain(Parse(mockLexer(TTwo, TOne, TTwo, TThree, TTwo), fn))
+	if count != 3 || err == nil || err.Error() != "error one" {
+		t.Errorf("Drain expecting (3, 'error one'), received (%d, '%v')", count, err)
+	}
+	if count, err = Drain(Parse(mockLexer(), nil)); count != 0 || err != nil {
+		t.Errorf("Drain expecting (0, nil), received (%d, '%v')", count, err)
+	}
+}
+
+// BenchmarkParse measures core parser throughput over a stream of single-token ASTs
+//
+func BenchmarkParse(b *testing.B) {
+	types := make([]token.Type, 1000)
+	for i := range types {
+		types[i] = TOne
+	}
+	var fn Fn
+	fn = func(p *Parser) Fn {
+		p.Emit(p.Next())
+		return fn
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if count, _ := Drain(Parse(mockLexer(types...), fn)); count != len(types) {
+			b.Fatalf("Drain expecting %d ASTs, received %d", len(types), count)
+		}
+	}
+}
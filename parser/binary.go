@@ -0,0 +1,89 @@
+package parser
+
+import "github.com/tekwizely/go-parsing/lexer/token"
+
+// Operator describes a binary operator, for parsing operator chains via ParseBinary.
+//
+type Operator struct {
+	Type  token.Type // Token type of the operator
+	Prec  int        // Precedence, higher binds tighter, i.e. '*' = 2, '+' = 1
+	Right bool       // Right-associative, i.e. a ^ b ^ c == a ^ (b ^ c), otherwise left-associative
+}
+
+// Binary is the default node built by ParseBinary, when no build function is specified.
+//
+type Binary struct {
+	Op    token.Token // The operator token
+	Left  interface{} // The left operand
+	Right interface{} // The right operand
+}
+
+// ParseBinary parses a chain of operands separated by binary operators, i.e. `a op b op c`, folding them into nodes
+// according to the precedence and associativity of each operator.
+// Left-associative operators fold to the left, i.e. a - b - c == (a - b) - c, avoiding the subtly right-associative
+// results of naive recursive schemes.
+// Operands are parsed via operand, and nodes are built via build, which can return an error to stop parsing (i.e. a
+// division by zero when evaluating).
+// If build is nil, a *Binary is built for each operator.
+// The chain ends at the first token that is not one of the specified operators.
+// Returns the first error from operand or build; Tokens matched before the error remain matched (see Marker).
+// Panics if EOF already emitted (see WithLenient).
+//
+func (p *Parser) ParseBinary(operand func(*Parser) (interface{}, error), ops []Operator,
+	build func(op token.Token, left interface{}, right interface{}) (interface{}, error)) (interface{}, error) {
+	// Nothing can be matched after EOF emitted
+	//
+	if p.afterEOF("Parser.ParseBinary: No tokens can be matched after EOF is emitted") {
+		return nil, nil
+	}
+	if build == nil {
+		build = buildBinary
+	}
+	return p.climb(operand, ops, build, 0)
+}
+
+// climb parses operands and operators with a precedence of at least minPrec (precedence climbing).
+//
+func (p *Parser) climb(operand func(*Parser) (interface{}, error), ops []Operator,
+	build func(op token.Token, left interface{}, right interface{}) (interface{}, error), minPrec int) (interface{}, error) {
+	left, err := operand(p)
+	if err != nil {
+		return nil, err
+	}
+	for p.CanPeek(1) {
+		op, ok := findOperator(ops, p.PeekType(1))
+		if !ok || op.Prec < minPrec {
+			break
+		}
+		tok := p.Next()
+		next := op.Prec + 1
+		if op.Right {
+			next = op.Prec
+		}
+		var right interface{}
+		if right, err = p.climb(operand, ops, build, next); err != nil {
+			return nil, err
+		}
+		if left, err = build(tok, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// findOperator returns the operator for the token type.
+//
+func findOperator(ops []Operator, typ token.Type) (Operator, bool) {
+	for _, op := range ops {
+		if op.Type == typ {
+			return op, true
+		}
+	}
+	return Operator{}, false
+}
+
+// buildBinary builds a *Binary.
+//
+func buildBinary(op token.Token, left interface{}, right interface{}) (interface{}, error) {
+	return &Binary{Op: op, Left: left, Right: right}, nil
+}
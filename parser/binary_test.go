@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// binaryOps defines TTwo as left-associative, and TThree as right-associative with higher precedence
+//
+var binaryOps = []Operator{
+	{Type: TTwo, Prec: 1},
+	{Type: TThree, Prec: 2, Right: true},
+}
+
+// binaryOperand matches TOne, returning its value
+//
+func binaryOperand(p *Parser) (interface{}, error) {
+	if tok, ok := p.Expect(TOne); ok {
+		return tok.Value(), nil
+	}
+	return nil, p.Furthest()
+}
+
+// binaryString builds a parenthesized string
+//
+func binaryString(op token.Token, left interface{}, right interface{}) (interface{}, error) {
+	return fmt.Sprintf("(%v%s%v)", left, op.Value(), right), nil
+}
+
+// parseBinary emits each operator chain
+//
+func parseBinary(p *Parser) Fn {
+	v, err := p.ParseBinary(binaryOperand, binaryOps, binaryString)
+	if err != nil {
+		p.EmitError(err.Error())
+		return nil
+	}
+	p.Emit(v)
+	return nil
+}
+
+// TestParseBinaryLeft
+//
+func TestParseBinaryLeft(t *testing.T) {
+	nexter := Parse(positioned(TOne, TTwo, TOne, TTwo, TOne), parseBinary)
+	expectNexterNext(t, nexter, "((abc)de)")
+	expectNexterEOF(t, nexter)
+}
+
+// TestParseBinaryRight
+//
+func TestParseBinaryRight(t *testing.T) {
+	nexter := Parse(positioned(TOne, TThree, TOne, TThree, TOne), parseBinary)
+	expectNexterNext(t, nexter, "(ab(cde))")
+	expectNexterEOF(t, nexter)
+}
+
+// TestParseBinaryPrecedence
+//
+func TestParseBinaryPrecedence(t *testing.T) {
+	nexter := Parse(positioned(TOne, TTwo, TOne, TThree, TOne, TTwo, TOne), parseBinary)
+	expectNexterNext(t, nexter, "((ab(cde))fg)")
+	expectNexterEOF(t, nexter)
+}
+
+// TestParseBinaryError
+//
+func TestParseBinaryError(t *testing.T) {
+	nexter := Parse(positioned(TOne, TTwo, TTwo), parseBinary)
+	expectNexterError(t, nexter, "1:5: expected [1], found 2 'c'")
+	expectNexterEOF(t, nexter)
+}
+
+// TestParseBinaryDefault
+//
+func TestParseBinaryDefault(t *testing.T) {
+	var node interface{}
+	nexter := Parse(positioned(TOne, TTwo, TOne), func(p *Parser) Fn {
+		var err error
+		if node, err = p.ParseBinary(binaryOperand, binaryOps, nil); err != nil {
+			p.EmitError(err.Error())
+		}
+		return nil
+	})
+	expectNexterEOF(t, nexter)
+	b, ok := node.(*Binary)
+	if !ok || b.Op.Value() != "b" || b.Left != "a" || b.Right != "c" {
+		t.Errorf("ParseBinary() expecting Binary{b, a, c}, received %#v", node)
+	}
+}
+
+// TestParseBinaryBuildError
+//
+func TestParseBinaryBuildError(t *testing.T) {
+	nexter := Parse(positioned(TOne, TTwo, TOne), func(p *Parser) Fn {
+		_, err := p.ParseBinary(binaryOperand, binaryOps, func(token.Token, interface{}, interface{}) (interface{}, error) {
+			return nil, errors.New("build failed")
+		})
+		p.EmitError(err.Error())
+		return nil
+	})
+	expectNexterError(t, nexter, "build failed")
+	expectNexterEOF(t, nexter)
+}
@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Checkpoint is a named save point, built on a Marker, that also records the position and rule stack at the time it
+// was created.
+// Checkpoints are intended for complex speculative parsing, where it helps to list and inspect the outstanding save
+// points by name.
+//
+// Checkpoints are cleaned up automatically when superseded:
+//
+//  - Creating a checkpoint replaces any outstanding checkpoint with the same name
+//  - Rolling back to a checkpoint discards any checkpoints created after it
+//  - All checkpoints are discarded when markers are invalidated, i.e. after the next Emit() or Clear()
+//
+type Checkpoint struct {
+	name     string
+	marker   *Marker
+	pos      token.Position
+	frames   []ruleFrame
+	released bool
+}
+
+// Checkpoint creates a named checkpoint at the current parser state.
+// Any outstanding checkpoint with the same name is discarded.
+//
+func (p *Parser) Checkpoint(name string) *Checkpoint {
+	c := &Checkpoint{
+		name:   name,
+		marker: p.Marker(),
+		pos:    p.pos(),
+		frames: append([]ruleFrame(nil), p.frames...),
+	}
+	checkpoints := p.activeCheckpoints()
+	for i, old := range checkpoints {
+		if old.name == name {
+			old.released = true
+			checkpoints = append(checkpoints[:i], checkpoints[i+1:]...)
+			break
+		}
+	}
+	p.saved = append(checkpoints, c)
+	return c
+}
+
+// Checkpoints returns the outstanding checkpoints, in the order they were created.
+//
+func (p *Parser) Checkpoints() []*Checkpoint {
+	return append([]*Checkpoint(nil), p.activeCheckpoints()...)
+}
+
+// activeCheckpoints returns the outstanding checkpoints, discarding them if markers have been invalidated since they
+// were created.
+//
+func (p *Parser) activeCheckpoints() []*Checkpoint {
+	if len(p.saved) > 0 && !p.saved[0].marker.Valid() {
+		for _, c := range p.saved {
+			c.released = true
+		}
+		p.saved = p.saved[:0]
+	}
+	return p.saved
+}
+
+// Name returns the name of the checkpoint.
+//
+func (c *Checkpoint) Name() string {
+	return c.name
+}
+
+// Pos returns the position of the next token at the time the checkpoint was created, or the end of the last token if
+// there were no further tokens.
+//
+func (c *Checkpoint) Pos() token.Position {
+	return c.pos
+}
+
+// Matched returns the number of tokens that were matched at the time the checkpoint was created.
+//
+func (c *Checkpoint) Matched() int {
+	return c.marker.matchLen
+}
+
+// RuleStack returns the names of the rules on the rule stack at the time the checkpoint was created, outermost first.
+//
+func (c *Checkpoint) RuleStack() []string {
+	names := make([]string, len(c.frames))
+	for i, f := range c.frames {
+		names[i] = f.name
+	}
+	return names
+}
+
+// Valid confirms if the checkpoint is still outstanding.
+// If Valid returns true, you can safely roll back to the checkpoint via Checkpoint.Rollback().
+//
+func (c *Checkpoint) Valid() bool {
+	return !c.released && c.marker.Valid()
+}
+
+// Rollback resets the parser state, including the rule stack, to the checkpoint.
+// Any checkpoints created after this one are discarded; This checkpoint remains outstanding, and can be rolled back to
+// again.
+// Returns the Parser.Fn that was stored at the time the checkpoint was created (see Marker.Apply).
+// Panics if checkpoint fails Valid() check.
+//
+func (c *Checkpoint) Rollback() Fn {
+	p := c.marker.parser
+	if !c.Valid() {
+		p.raise("Checkpoint.Rollback: Invalid checkpoint '" + c.name + "'")
+	}
+	checkpoints := p.activeCheckpoints()
+	for i, cp := range checkpoints {
+		if cp == c {
+			for _, later := range checkpoints[i+1:] {
+				later.released = true
+			}
+			p.saved = checkpoints[:i+1]
+			break
+		}
+	}
+	p.frames = append(p.frames[:0], c.frames...)
+	return c.marker.Apply()
+}
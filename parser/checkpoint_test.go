@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// describeCheckpoints renders the outstanding checkpoints as "name@pos[matched](rules)", comma-separated
+//
+func describeCheckpoints(p *Parser) string {
+	var s []string
+	for _, c := range p.Checkpoints() {
+		s = append(s, fmt.Sprintf("%s@%s[%d](%s)", c.Name(), c.Pos(), c.Matched(), strings.Join(c.RuleStack(), " ")))
+	}
+	return strings.Join(s, ",")
+}
+
+// expectCheckpoints
+//
+func expectCheckpoints(t *testing.T, p *Parser, match string) {
+	if s := describeCheckpoints(p); s != match {
+		t.Errorf("Parser.Checkpoints() expecting '%s', received '%s'", match, s)
+	}
+}
+
+// TestCheckpoint
+//
+func TestCheckpoint(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		start := p.Checkpoint("start")
+		p.EnterRule("header")
+		p.Next()
+		header := p.Checkpoint("after-header")
+		p.Next()
+		p.Checkpoint("after-body")
+		expectCheckpoints(t, p, "start@1:1[0](),after-header@1:3[1](header),after-body@1:5[2](header)")
+
+		// Rolling back discards later checkpoints, restoring the rule stack
+		//
+		p.ExitRule()
+		header.Rollback()
+		expectCheckpoints(t, p, "start@1:1[0](),after-header@1:3[1](header)")
+		if got := strings.Join(p.RuleStack(), ","); got != "header" {
+			t.Errorf("Checkpoint.Rollback() expecting rule stack 'header', received '%s'", got)
+		}
+		expectMatched(t, p, TOne)
+
+		// Same name replaces
+		//
+		p.Next()
+		p.Checkpoint("start")
+		if start.Valid() {
+			t.Error("Checkpoint.Valid() expecting replaced checkpoint to be invalid")
+		}
+		expectCheckpoints(t, p, "after-header@1:3[1](header),start@1:5[2](header)")
+
+		// Rollback can be repeated
+		//
+		header.Rollback()
+		header.Rollback()
+		expectMatched(t, p, TOne)
+		expectCheckpoints(t, p, "after-header@1:3[1](header)")
+
+		p.Emit("AST")
+		if header.Valid() {
+			t.Error("Checkpoint.Valid() expecting checkpoint to be invalid after Emit()")
+		}
+		expectCheckpoints(t, p, "")
+		assertPanic(t, func() {
+			header.Rollback()
+		}, "Checkpoint.Rollback: Invalid checkpoint 'after-header'")
+		return nil
+	}
+	nexter := Parse(positioned(TOne, TTwo, TThree), fn)
+	expectNexterNext(t, nexter, "AST")
+	expectNexterEOF(t, nexter)
+}
+
+// TestCheckpointEnd confirms a checkpoint at the end of the input is positioned at the end of the last token
+//
+func TestCheckpointEnd(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.Next()
+		if pos := p.Checkpoint("end").Pos().String(); pos != "1:2" {
+			t.Errorf("Checkpoint.Pos() expecting '1:2', received '%s'", pos)
+		}
+		p.Emit("AST")
+		return nil
+	}
+	nexter := Parse(positioned(TOne), fn)
+	expectNexterNext(t, nexter, "AST")
+	expectNexterEOF(t, nexter)
+}
@@ -0,0 +1,85 @@
+package main
+
+//
+//	parsetrace runs a lexer + parser pair with tracing enabled and prints the parser function timeline.
+//
+//	Usage:
+//
+//		parsetrace [-format timeline|dot] [-all] -pair name [file]
+//		parsetrace -list
+//
+//	Built-in pairs:
+//
+//		sum - Parses sums of numbers, i.e. "1 + 22 + 333", emitting each number
+//
+//	To trace your own parsers, register them with the parsetrace package from your own main():
+//
+//		parsetrace.Register("mylang", lexMyLang, parseMyLang)
+//		parsetrace.Main()
+//
+
+import (
+	"unicode"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+	"github.com/tekwizely/go-parsing/parser/parsetrace"
+)
+
+// We define our lexer tokens starting from the pre-defined START token
+//
+const (
+	TNumber token.Type = lexer.TStart + iota
+	TPlus
+)
+
+func main() {
+	parsetrace.Register("sum", lexSum, parseNumber)
+	parsetrace.Main()
+}
+
+// lexSum emits runs of digits as TNumber and '+' as TPlus, discarding whitespace
+//
+func lexSum(l *lexer.Lexer) lexer.Fn {
+	switch r := l.Next(); {
+	case unicode.IsDigit(r):
+		for l.CanPeek(1) && unicode.IsDigit(l.Peek(1)) {
+			l.Next()
+		}
+		l.EmitToken(TNumber)
+	case r == '+':
+		l.EmitToken(TPlus)
+	case unicode.IsSpace(r):
+		l.Clear()
+	default:
+		l.EmitErrorf("unexpected character '%c'", r)
+	}
+	return lexSum
+}
+
+// parseNumber emits a number, then switches to parsePlus
+//
+func parseNumber(p *parser.Parser) parser.Fn {
+	if !p.CanPeek(1) || p.PeekType(1) != TNumber {
+		p.EmitError("expecting number")
+		return nil
+	}
+	p.Emit(p.Next().Value())
+	return parsePlus
+}
+
+// parsePlus matches a '+', then switches to parseNumber
+//
+func parsePlus(p *parser.Parser) parser.Fn {
+	if !p.CanPeek(1) {
+		return nil
+	}
+	if p.PeekType(1) != TPlus {
+		p.EmitError("expecting '+'")
+		return nil
+	}
+	p.Next()
+	p.Clear()
+	return parseNumber
+}
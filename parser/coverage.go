@@ -0,0 +1,184 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// CoverKind identifies the kind of a coverage point.
+//
+type CoverKind int
+
+// Coverage point kinds.
+//
+const (
+	CoverFn     CoverKind = iota // A parser function call, recorded automatically
+	CoverPoint                   // A named point within a parser function, recorded via Parser.Cover()
+	CoverExpect                  // A satisfied expectation, recorded via Parser.Expect()
+	CoverAlt                     // A successful alternative, recorded via Parser.OneOf()
+)
+
+// String returns the kind name, i.e. "fn", "point", "expect" or "alt".
+//
+func (k CoverKind) String() string {
+	switch k {
+	case CoverFn:
+		return "fn"
+	case CoverExpect:
+		return "expect"
+	case CoverAlt:
+		return "alt"
+	}
+	return "point"
+}
+
+// CoverEntry captures the hit count of a single coverage point.
+//
+type CoverEntry struct {
+	Kind CoverKind
+	Name string
+	Hits int
+}
+
+// coverKey identifies a coverage point.
+//
+type coverKey struct {
+	kind CoverKind
+	name string
+}
+
+// Coverage records which parser functions and named points fired across one or more parses, allowing grammar
+// authors to find dead or untested productions.
+// Parser functions are recorded automatically; Use Parser.Cover() to record named points, such as alternatives
+// within a function.
+// Satisfied expectations (see Parser.Expect) and successful alternatives (see Parser.OneOf) are also recorded
+// automatically.
+// Declare the functions, points, expectations and alternatives you expect to fire (see DeclareFns, Declare,
+// DeclareExpects and DeclareAlts) so that those never hit can be reported as untested.
+// A single Coverage can be shared across parses (see WithCoverage) and is safe for concurrent use.
+//
+type Coverage struct {
+	mu   sync.Mutex
+	hits map[coverKey]int
+}
+
+// NewCoverage returns a new, empty, Coverage.
+//
+func NewCoverage() *Coverage {
+	return &Coverage{hits: make(map[coverKey]int)}
+}
+
+// DeclareFns declares parser functions that are expected to be called.
+//
+func (c *Coverage) DeclareFns(fns ...Fn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, fn := range fns {
+		c.declare(coverKey{kind: CoverFn, name: fnName(fn)})
+	}
+}
+
+// Declare declares named points that are expected to be recorded via Parser.Cover().
+//
+func (c *Coverage) Declare(names ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range names {
+		c.declare(coverKey{kind: CoverPoint, name: name})
+	}
+}
+
+// DeclareExpects declares token sets that are expected to be satisfied via Parser.Expect().
+//
+func (c *Coverage) DeclareExpects(sets ...token.Set) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, set := range sets {
+		c.declare(coverKey{kind: CoverExpect, name: set.String()})
+	}
+}
+
+// DeclareAlts declares alternatives that are expected to succeed via Parser.OneOf().
+//
+func (c *Coverage) DeclareAlts(fns ...func(*Parser) (interface{}, error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, fn := range fns {
+		c.declare(coverKey{kind: CoverAlt, name: altName(fn)})
+	}
+}
+
+// declare registers the key with zero hits, if not already present.
+//
+func (c *Coverage) declare(key coverKey) {
+	if _, ok := c.hits[key]; !ok {
+		c.hits[key] = 0
+	}
+}
+
+// hit records a hit of the coverage point.
+//
+func (c *Coverage) hit(kind CoverKind, name string) {
+	c.mu.Lock()
+	c.hits[coverKey{kind: kind, name: name}]++
+	c.mu.Unlock()
+}
+
+// Entries returns all declared and recorded coverage points, sorted by kind then name.
+//
+func (c *Coverage) Entries() []CoverEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := make([]CoverEntry, 0, len(c.hits))
+	for key, hits := range c.hits {
+		entries = append(entries, CoverEntry{Kind: key.kind, Name: key.name, Hits: hits})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind < entries[j].Kind
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// Untested returns the declared coverage points that were never hit, sorted by kind then name.
+//
+func (c *Coverage) Untested() []CoverEntry {
+	var untested []CoverEntry
+	for _, e := range c.Entries() {
+		if e.Hits == 0 {
+			untested = append(untested, e)
+		}
+	}
+	return untested
+}
+
+// Report writes the coverage points to w as an aligned table, flagging untested points.
+//
+func (c *Coverage) Report(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tNAME\tHITS\t")
+	for _, e := range c.Entries() {
+		flag := ""
+		if e.Hits == 0 {
+			flag = "UNTESTED"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", e.Kind, e.Name, e.Hits, flag)
+	}
+	return tw.Flush()
+}
+
+// Cover records a hit of the named coverage point.
+// Does nothing if the parser has no coverage (see WithCoverage).
+//
+func (p *Parser) Cover(name string) {
+	if p.coverage != nil {
+		p.coverage.hit(CoverPoint, name)
+	}
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// parseCoverOne matches TOne tokens, covering the branch taken, switching to parseCoverTwo otherwise
+//
+func parseCoverOne(p *Parser) Fn {
+	if p.PeekType(1) != TOne {
+		p.Cover("one:other")
+		return parseCoverTwo
+	}
+	p.Cover("one:match")
+	p.Next()
+	p.Clear()
+	return parseCoverOne
+}
+
+// parseCoverTwo matches any token, then switches to parseCoverOne
+//
+func parseCoverTwo(p *Parser) Fn {
+	p.Next()
+	p.Clear()
+	return parseCoverOne
+}
+
+// parseCoverDead is never called
+//
+func parseCoverDead(_ *Parser) Fn {
+	return nil
+}
+
+// TestWithCoverage
+//
+func TestWithCoverage(t *testing.T) {
+	c := NewCoverage()
+	c.DeclareFns(parseCoverOne, parseCoverTwo, parseCoverDead)
+	c.Declare("one:match", "one:other", "one:never")
+	expectNexterEOF(t, Parse(mockLexer(TOne, TTwo), parseCoverOne, WithCoverage(c)))
+	expectNexterEOF(t, Parse(mockLexer(TOne), parseCoverOne, WithCoverage(c)))
+	var entries []string
+	for _, e := range c.Entries() {
+		entries = append(entries, fmt.Sprintf("%s %s %d", e.Kind, strings.TrimPrefix(e.Name, "github.com/tekwizely/go-parsing/parser."), e.Hits))
+	}
+	expected := "" +
+		"fn parseCoverDead 0\n" +
+		"fn parseCoverOne 3\n" +
+		"fn parseCoverTwo 1\n" +
+		"point one:match 2\n" +
+		"point one:never 0\n" +
+		"point one:other 1"
+	if received := strings.Join(entries, "\n"); received != expected {
+		t.Errorf("Coverage.Entries() expecting:\n%s\nreceived:\n%s", expected, received)
+	}
+	b := &bytes.Buffer{}
+	if err := c.Report(b); err != nil {
+		t.Errorf("Coverage.Report() received unexpected error '%s'", err)
+	}
+	if report := b.String(); !strings.HasPrefix(report, "KIND") || strings.Count(report, "UNTESTED") != 2 {
+		t.Errorf("Coverage.Report() expecting header and 2 untested entries, received:\n%s", report)
+	}
+	untested := c.Untested()
+	if len(untested) != 2 || untested[0].Kind != CoverFn || untested[1] != (CoverEntry{Kind: CoverPoint, Name: "one:never", Hits: 0}) {
+		t.Errorf("Coverage.Untested() received wrong entries: %v", untested)
+	}
+}
+
+// TestCoverWithoutCoverage
+//
+func TestCoverWithoutCoverage(t *testing.T) {
+	expectNexterEOF(t, Parse(mockLexer(TOne, TTwo), parseCoverOne))
+}
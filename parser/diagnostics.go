@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/tekwizely/go-parsing/lexer/diag"
+)
+
+// Diagnostics returns the collector receiving diagnostics from the parser, as specified via WithDiagnostics.
+// Returns nil if diagnostics are not being collected.
+//
+func (p *Parser) Diagnostics() *diag.Collector {
+	return p.diags
+}
+
+// EmitWarning reports a warning with the specified text, covering the previously-matched tokens.
+// Unlike EmitError, nothing is emitted and the matched tokens are retained, allowing parsing to continue normally;
+// The warning is recorded by the diagnostics collector (see WithDiagnostics), and is dropped if there is none.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (p *Parser) EmitWarning(msg string) {
+	// Nothing can be emitted after EOF emitted
+	//
+	if p.afterEOF("Parser.EmitWarning: No further emits allowed after EOF is emitted") {
+		return
+	}
+	if p.diags != nil {
+		p.diags.Add(diag.Diagnostic{Severity: diag.Warning, Span: p.matchSpan(), Message: msg})
+	}
+}
+
+// EmitWarningf reports a warning with the formatted text, covering the previously-matched tokens.
+// Panics if EOF already emitted (see WithLenient).
+// This is a convenience method that simply sends the formatted string to EmitWarning().
+//
+func (p *Parser) EmitWarningf(format string, args ...interface{}) {
+	p.EmitWarning(fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/diag"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestWithDiagnostics
+//
+func TestWithDiagnostics(t *testing.T) {
+	c := diag.NewCollector()
+	fn := func(p *Parser) Fn {
+		if p.Diagnostics() != c {
+			t.Error("Parser.Diagnostics() expecting collector from WithDiagnostics")
+		}
+		p.Next()
+		p.EmitWarningf("'%s' is deprecated", p.Last().Value())
+		p.Next()
+		p.Emit("AST")
+		p.Next()
+		p.EmitError("bad")
+		p.Expect(TOne)
+		p.EmitFurthest()
+		return nil
+	}
+	nexter := Parse(positioned(TOne, TTwo, TThree, TTwo), fn, WithDiagnostics(c))
+	expectNexterNext(t, nexter, "AST")
+	expectNexterError(t, nexter, "bad")
+	expectNexterError(t, nexter, "1:7: expected [1], found 2 'd'")
+	expectNexterEOF(t, nexter)
+	span := func(start int, end int) token.Span {
+		return token.Span{Start: token.Position{Line: 1, Column: start}, End: token.Position{Line: 1, Column: end}}
+	}
+	expected := []diag.Diagnostic{
+		{Severity: diag.Warning, Span: span(1, 2), Message: "'a' is deprecated"},
+		{Severity: diag.Error, Span: span(5, 6), Message: "bad"},
+		{Severity: diag.Error, Span: span(7, 7), Message: "expected [1], found 2 'd'"},
+	}
+	diags := c.Diagnostics()
+	if len(diags) != len(expected) {
+		t.Fatalf("Collector.Diagnostics() expecting %d diagnostics, received %d", len(expected), len(diags))
+	}
+	for i, d := range diags {
+		if d != expected[i] {
+			t.Errorf("Collector.Diagnostics()[%d] expecting '%v', received '%v'", i, expected[i], d)
+		}
+	}
+}
+
+// TestEmitWarningWithoutDiagnostics
+//
+func TestEmitWarningWithoutDiagnostics(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		if p.Diagnostics() != nil {
+			t.Error("Parser.Diagnostics() expecting nil")
+		}
+		p.Next()
+		p.EmitWarning("dropped")
+		p.Emit("AST")
+		p.EmitEOF()
+		assertPanic(t, func() {
+			p.EmitWarning("after EOF")
+		}, "Parser.EmitWarning: No further emits allowed after EOF is emitted")
+		return nil
+	}
+	nexter := Parse(mockLexer(TOne), fn)
+	expectNexterNext(t, nexter, "AST")
+	expectNexterEOF(t, nexter)
+}
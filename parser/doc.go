@@ -12,7 +12,107 @@ Initiating a Parser
 
 	// Parse initiates a parser against the input token stream.
 	//
-	func Parse(tokens token.Nexter, start parser.Fn) ASTNexter
+	func Parse(tokens token.Nexter, start parser.Fn, opts ...parser.Option) ASTNexter
+
+
+Parser Options
+
+Parse accepts optional configuration via parser.Option values:
+
+	// WithContext sets the initial user context value of the parser.
+	//
+	func WithContext(ctx interface{}) parser.Option
+
+	// WithLenient converts post-EOF usage panics into no-ops that return zero values.
+	// The first such usage is recorded and can be retrieved via Parser.MisuseError().
+	// The same error is returned once by the ASTNexter, just before io.EOF.
+	//
+	func WithLenient() parser.Option
+
+	// WithTrace registers a function to receive trace events for parser activity (function enter/exit, Next/Peek,
+	// emits, markers, Clear), including positions.
+	//
+	func WithTrace(fn func(event TraceEvent)) parser.Option
+
+	// WithRecorder records the sequence of parser function transitions (with positions), for dumping via Recorder.Dump().
+	//
+	func WithRecorder(r *Recorder) parser.Option
+
+	// WithLoopGuard aborts with an error, naming the function and position, after limit consecutive parser
+	// function calls without consuming tokens or emitting ASTs.
+	//
+	func WithLoopGuard(limit int) parser.Option
+
+	// WithStats records counters (see Stats) into the specified Stats, for retrieval after parsing completes.
+	// Stats can be published via expvar, or retrieved from within your parser functions via Parser.Stats().
+	//
+	func WithStats(s *Stats) parser.Option
+
+	// WithCoverage records parser function calls, and named points (see Parser.Cover), into the specified coverage.
+	// Coverage can be shared across parses, reporting untested functions and points via Coverage.Untested().
+	//
+	func WithCoverage(c *Coverage) parser.Option
+
+	// WithOutputBuffer batches emitted ASTs, delivering them once at least n are pending (or on EOF / Flush()),
+	// allowing parser functions that emit many ASTs in a single call to do so without growing the buffer.
+	//
+	func WithOutputBuffer(n int) parser.Option
+
+	// WithDiagnostics records warnings (see Parser.EmitWarning) and errors into the specified collector, which can be
+	// shared with the lexer (see the lexer/diag package).
+	//
+	func WithDiagnostics(c *diag.Collector) parser.Option
+
+	// WithMaxErrors stops parsing once n errors have been emitted, following the last with a "too many errors" error.
+	//
+	func WithMaxErrors(n int) parser.Option
+
+	// WithLexErrors passes non-EOF errors from the lexer (i.e. *lexer.Error) through the ASTNexter, as if emitted via
+	// EmitError, and continues parsing; By default, the first such error is logged and treated as the end of the input.
+	//
+	func WithLexErrors() parser.Option
+
+	// WithEOFToken surfaces the end of the input as a real, peekable, token of the specified type (i.e. lexer.TEof),
+	// so grammars can assert that the entire input was consumed via Expect(typ).
+	//
+	func WithEOFToken(typ token.Type) parser.Option
+
+	// WithArena enables an Arena for allocating the slices that make up your ASTs (see Parser.Arena()), cutting GC
+	// pressure for batch tools; Release the arena all at once via the Release() method of the ASTNexter, once done with
+	// the ASTs.
+	//
+	func WithArena(a *Arena) parser.Option
+
+	// WithChannels specifies the token channels visible to your parser functions (see token.Channel); Tokens on other
+	// channels are skipped. By default, only token.ChannelDefault is visible.
+	//
+	func WithChannels(channels ...token.Channel) parser.Option
+
+	// WithInteractive enables interactive mode for REPLs; Errors emitted once the input is exhausted (i.e. an unclosed
+	// block), along with incomplete errors from the lexer, are reported as incomplete - see token.IsIncomplete().
+	//
+	func WithInteractive() parser.Option
+
+	// WithMaxDepth limits the depth of the rule stack (see Parser.EnterRule()), protecting services parsing untrusted
+	// input from stack exhaustion; Once exceeded, a *DepthError is emitted and parsing is terminated.
+	//
+	func WithMaxDepth(n int) parser.Option
+
+Interactive mode allows REPLs to distinguish "incomplete" input from "invalid" input (see token.IsIncomplete),
+prompting for continuation lines instead of reporting an error.
+
+
+Reusing Parsers
+
+Servers parsing many small payloads can reuse parsers via a Pool, avoiding per-input allocation of the parser
+machinery:
+
+	pool := parser.NewPool(opts...)
+	asts := pool.Parse(tokens, start)
+	// ... consume ASTs ...
+	pool.Put(asts)
+
+Pooled parsers are re-initialized via Parser.Reset(), which retains the options and internal buffers.
 
 
 Parser Functions
@@ -40,6 +140,53 @@ This alleviates the need to manage complex looping / restart logic.
 Simply return from your method after (possibly) emitting an AST, and the Parser will manage the looping.
 
 
+Parser User Context
+
+State, such as symbol tables and options, can travel with the parser, instead of requiring closures or global
+variables:
+
+	// Context returns the user context value of the parser.
+	//
+	func (p *Parser) Context() interface{}
+
+	// SetContext sets the user context value of the parser.
+	//
+	func (p *Parser) SetContext(ctx interface{})
+
+
+Naming Parser Functions
+
+Parser functions can be given rule names, which are used in place of function names in traces, recordings and
+loop-guard errors.
+Helper functions that are called directly can push (and pop) rule names onto a rule stack:
+
+	// Rule names the parser function fn, returning fn.
+	//
+	func (p *Parser) Rule(name string, fn Fn) Fn
+
+	// EnterRule pushes a rule onto the rule stack, recording the position of the next token.
+	//
+	func (p *Parser) EnterRule(name string)
+
+	// ExitRule pops the innermost rule from the rule stack.
+	//
+	func (p *Parser) ExitRule()
+
+	// RuleName returns the name of the innermost rule on the rule stack.
+	//
+	func (p *Parser) RuleName() string
+
+	// RuleStack returns the names of the rules on the rule stack, outermost first.
+	//
+	func (p *Parser) RuleStack() []string
+
+Errors emitted via EmitError are suffixed with the chain of rules, along with the position of the innermost rule,
+i.e. "unexpected ')' while parsing argument-list of call at 3:14".
+
+The rule stack also enforces the depth limit (see WithMaxDepth): Once exceeded, the parser function is aborted,
+unwinding any recursion, and a *DepthError is emitted.
+
+
 Switching Parser Context
 
 Switching contexts is as easy as returning a reference to another `Parser.Fn`.
@@ -69,6 +216,66 @@ Your Parser function receives a `*Parser` when called and can use the following
 	//
 	func (p *Parser) Next() token.Token
 
+Defensive code paths can use the non-panicking variants, which report availability instead of requiring
+CanPeek-then-Peek pairs:
+
+	// TryPeek is a non-panicking variant of Peek.
+	//
+	func (p *Parser) TryPeek(n int) (token.Token, bool)
+
+	// TryPeekType is a non-panicking variant of PeekType.
+	//
+	func (p *Parser) TryPeekType(n int) (token.Type, bool)
+
+	// TryNext is a non-panicking variant of Next.
+	//
+	func (p *Parser) TryNext() (token.Token, bool)
+
+Multi-token lookahead can be checked against a pattern in a single call, replacing chained CanPeek/PeekType conditions:
+
+	// PeekTypes allows you to look ahead at the types of the next n tokens without consuming them.
+	//
+	func (p *Parser) PeekTypes(n int) []token.Type
+
+	// Match confirms if the next len(pattern) tokens are available and match the pattern types, in order.
+	// Use TAny within the pattern to match any token type.
+	//
+	func (p *Parser) Match(pattern ...token.Type) bool
+
+	// NotFollowedBy is a negative lookahead assertion: It confirms that the next len(types) tokens do not match the
+	// types, in order.
+	//
+	func (p *Parser) NotFollowedBy(types ...token.Type) bool
+
+Classes of tokens (i.e. "statement starters" or "binary operators") can be defined once as a token.Set and tested
+cheaply:
+
+	// PeekIn confirms if the nth token is available and its type is within the set.
+	//
+	func (p *Parser) PeekIn(n int, set token.Set) bool
+
+	// AcceptIn matches the next token if it is available and its type is within the set.
+	//
+	func (p *Parser) AcceptIn(set token.Set) (token.Token, bool)
+
+Once matched, tokens can be reviewed without any caller-side bookkeeping:
+
+	// Last returns the most-recently matched token.
+	//
+	func (p *Parser) Last() token.Token
+
+	// Matched returns the tokens matched since the last Emit or Clear, in order.
+	//
+	func (p *Parser) Matched() []token.Token
+
+	// Index returns the index of the next token in the input, i.e. the number of tokens matched or discarded before it.
+	//
+	func (p *Parser) Index() int
+
+	// Pos returns the position of the next token in the input.
+	//
+	func (p *Parser) Pos() token.Position
+
 
 Emitting ASTs
 
@@ -79,6 +286,74 @@ Once you've processed one or more tokens, and built up an abstract syntax tree,
 	//
 	func (p * Parser) Emit(ast interface{})
 
+If you would like your ASTs to carry positional information, EmitSpanned attaches the span covering all of the
+matched tokens, either via the SpanSetter interface, or by wrapping the AST in a *Spanned:
+
+	// EmitSpanned emits an AST, attaching the span covering all of the previously-matched tokens.
+	//
+	func (p *Parser) EmitSpanned(ast interface{})
+
+When output batching is enabled (see WithOutputBuffer), you can deliver the pending ASTs without waiting for a full
+batch:
+
+	// Flush ends the current output batch, delivering all pending ASTs before any further parser functions are called.
+	//
+	func (p *Parser) Flush()
+
+
+Emitting Errors
+
+If your parser encounters something it does not expect, you can emit an error, which will be returned from the
+ASTNexter in place of an AST:
+
+	// EmitError emits an error with the specified err string as the error text.
+	//
+	func (p *Parser) EmitError(err string)
+
+	// EmitErrorf emits an error with the formatted err string as the error text.
+	//
+	func (p *Parser) EmitErrorf(format string, args ...interface{})
+
+Non-fatal issues, such as deprecated syntax, can be reported as warnings without emitting anything, allowing parsing
+to continue normally; Warnings are recorded by the diagnostics collector (see WithDiagnostics):
+
+	// EmitWarning reports a warning with the specified text, covering the previously-matched tokens.
+	//
+	func (p *Parser) EmitWarning(msg string)
+
+	// EmitWarningf reports a warning with the formatted text, covering the previously-matched tokens.
+	//
+	func (p *Parser) EmitWarningf(format string, args ...interface{})
+
+When backtracking across alternatives, the most helpful error is usually the one from the furthest point reached.
+Expect records the types expected at the current Index whenever it fails to match, accumulating them across attempts:
+
+	// Expect matches the next token if its type is one of the specified types, returning the token along with true.
+	// Otherwise, the types are recorded as expected at the current index.
+	//
+	func (p *Parser) Expect(types ...token.Type) (token.Token, bool)
+
+	// EmitFurthest emits the furthest failure (see Furthest) as an error, i.e. "1:5: expected [2 3], found 4 'x'".
+	//
+	func (p *Parser) EmitFurthest()
+
+OneOf standardizes the ordered-choice pattern, trying each alternative in turn and resetting the parser after each
+failure.
+If all alternatives fail, the failures they return (typically via Furthest) are aggregated into the furthest failure:
+
+	// OneOf tries each of the alternatives in order, returning the result of the first to succeed (return a nil error).
+	//
+	func (p *Parser) OneOf(fns ...func(*Parser) (interface{}, error)) (interface{}, error)
+
+ParseBinary parses chains of operands separated by binary operators, i.e. `a op b op c`, folding them according to
+the precedence and associativity of each operator (see Operator):
+
+	// ParseBinary parses a chain of operands separated by binary operators, folding them into nodes via build.
+	// If build is nil, a *Binary is built for each operator.
+	//
+	func (p *Parser) ParseBinary(operand func(*Parser) (interface{}, error), ops []Operator,
+		build func(op token.Token, left interface{}, right interface{}) (interface{}, error)) (interface{}, error)
+
 
 Discarding Matched Tokens
 
@@ -119,6 +394,23 @@ you can use this pattern:
 
 	return marker.Apply(); // Resets the parser and returns control to the saved Parser.Fn
 
+To commit part of a match and retry the rest, you can instead discard just the tokens matched since the marker:
+
+	// ClearTo discards the tokens matched since the marker was created, keeping any tokens matched before it.
+	//
+	func (m *Marker) ClearTo()
+
+For complex speculative parsing, named checkpoints record the position and rule stack along with a marker, and can be
+listed, inspected and rolled back to.  Checkpoints are discarded automatically when superseded:
+
+	// Checkpoint creates a named checkpoint at the current parser state.
+	//
+	func (p *Parser) Checkpoint(name string) *Checkpoint
+
+	// Rollback resets the parser state, including the rule stack, to the checkpoint.
+	//
+	func (c *Checkpoint) Rollback() parser.Fn
+
 
 Retrieving Emitted ASTs
 
@@ -133,6 +425,82 @@ parser:
 		Next() (interface{}, error)
 	}
 
+When you just want all of the ASTs, or the first error, ParseAll drains the ASTNexter for you:
+
+	asts, err := parser.ParseAll(tokens, parseStart)
+
+For single-document grammars, ParseOne returns the root AST along with the diagnostics recorded while parsing:
+
+	root, diags, err := parser.ParseOne(tokens, parseStart)
+
+To stream the results to other tools, WriteNDJSON writes each AST and error as a single line of JSON, as soon as it
+is received:
+
+	err := parser.WriteNDJSON(os.Stdout, parser.Parse(tokens, parseStart))
+
+
+Snapshots
+
+As with the lexer, the parser can be checkpointed via Snapshot, and resumed later via ParseSnapshot (or
+RestoreSnapshot):
+
+	// Within a parser function
+	//
+	data, err := p.Snapshot()
+
+	// Later, with the tokens not yet read by the parser
+	//
+	nexter, err := parser.ParseSnapshot(data, remaining, parseStart)
+
+Snapshots capture the buffered tokens (by type, value and position), and any errors emitted but not yet delivered.
+As ASTs cannot be captured, Snapshot returns an error if any emitted ASTs are pending delivery.
+
+
+Event-Based Parsing
+
+For streaming consumers (indexers, highlighters, etc) that never need a full tree, ParseEvents delivers events to a
+user-provided handler instead of building ASTs:
+
+	// ParseEvents initiates a parser against the input token stream, delivering events to the handler.
+	//
+	func ParseEvents(tokens token.Nexter, start parser.Fn, handler EventHandler) error
+
+Your parser functions generate events using the following methods, with matched tokens delivered (via
+EventHandler.Token) as they are committed:
+
+	// BeginNode commits all previously-matched tokens, then signals the start of a node of the specified kind.
+	//
+	func (p *Parser) BeginNode(kind string)
+
+	// EndNode commits all previously-matched tokens, then signals the end of a node of the specified kind.
+	//
+	func (p *Parser) EndNode(kind string)
+
+Errors emitted via EmitError are delivered to the handler via EventHandler.Error, as a diag.Diagnostic covering the
+matched tokens.
+In lenient mode (see WithLenient), ParseEvents returns the misuse error, if any.
+
+
+Incremental Parsing
+
+For editors and other tools that re-parse after small edits, a NodeCache allows subtrees unaffected by an edit to be
+reused instead of re-parsed:
+
+	// WithNodeCache enables incremental parsing, using the specified cache to store and reuse ASTs.
+	//
+	func WithNodeCache(c *NodeCache) parser.Option
+
+	// CacheNode records ast in the node cache, covering the tokens matched since the marker m was created.
+	//
+	func (p *Parser) CacheNode(kind string, m *Marker, ast interface{})
+
+	// Reuse looks for a cached AST of the specified kind starting at the next token.
+	//
+	func (p *Parser) Reuse(kind string) (interface{}, bool)
+
+After editing the input, call NodeCache.Invalidate(Edit) for each edit, then re-lex and re-parse with the same cache.
+Nodes whose spans touch an edit are discarded, and nodes that follow an edit are re-positioned.
+
 
 Example Programs
 
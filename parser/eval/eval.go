@@ -0,0 +1,573 @@
+/*
+Package eval builds evaluators for small, embedded, expression languages on top of the parser package.
+
+A Language describes the tokens of the expression language: the binary operators (parsed via Parser.ParseBinary),
+the prefix operators, the operand tokens, and optionally grouping parentheses and function calls.
+Each operator and operand has a handler, which computes the value of the expression at that node.
+
+Compile parses a token stream into an Expr, which can then be evaluated any number of times against an environment
+value of your choosing:
+
+	lang := &eval.Language{
+		Binary: []eval.Binary{
+			{Type: TPlus, Prec: 1, Fn: eval.Floats(add)},
+			{Type: TMultiply, Prec: 2, Fn: eval.Floats(multiply)},
+		},
+		Unary:    []eval.Unary{{Type: TMinus, Prec: 3, Fn: eval.Float(negate)}},
+		Operands: []eval.Operand{{Type: TNumber, Fn: number}, {Type: TId, Fn: variable}},
+		Open:     TOpenParen,
+		Close:    TCloseParen,
+	}
+	expr, err := eval.Compile(lang, lexer.LexString("-x * (y + 1)", lex))
+	...
+	value, err := expr.Eval(vars)
+
+Values are untyped (interface{}); Handlers can use the typed adapters (i.e. Floats, Bools), which return a *TypeError
+when an operand has an unexpected type.
+
+Errors returned from handlers are annotated with the position of the token that produced them, i.e.
+"1:5: division by zero", via *Error.
+
+*/
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// BinaryFn computes the value of a binary operator expression from the values of its operands.
+//
+type BinaryFn func(x interface{}, y interface{}) (interface{}, error)
+
+// UnaryFn computes the value of a prefix operator expression from the value of its operand.
+//
+type UnaryFn func(x interface{}) (interface{}, error)
+
+// OperandFn computes the value of an operand token, i.e. a number literal or a variable, within the environment
+// passed to Expr.Eval.
+//
+type OperandFn func(tok token.Token, env interface{}) (interface{}, error)
+
+// Func computes the value of a function call from the values of its arguments.
+//
+type Func func(args []interface{}) (interface{}, error)
+
+// Binary describes a binary operator.
+//
+type Binary struct {
+	Type  token.Type // Token type of the operator
+	Prec  int        // Precedence, higher binds tighter, i.e. '*' = 2, '+' = 1
+	Right bool       // Right-associative, i.e. a ^ b ^ c == a ^ (b ^ c), otherwise left-associative
+	Fn    BinaryFn   // Computes the value; Both operands are always evaluated first
+}
+
+// Unary describes a prefix operator.
+// The operand of a prefix operator includes any binary operators with a higher precedence, i.e. with '-' at 3 and
+// '^' at 4, -2 ^ 2 == -(2 ^ 2), whereas with '*' at 2, -2 * 3 == (-2) * 3.
+//
+type Unary struct {
+	Type token.Type // Token type of the operator
+	Prec int        // Precedence, relative to the binary operators
+	Fn   UnaryFn    // Computes the value
+}
+
+// Operand describes an operand token.
+//
+type Operand struct {
+	Type token.Type // Token type of the operand
+	Fn   OperandFn  // Computes the value, when evaluated
+}
+
+// Language describes an expression language.
+// A Language should not be modified once used to compile expressions, with the exception of Funcs, which are looked
+// up when evaluated.
+//
+type Language struct {
+	Binary   []Binary
+	Unary    []Unary
+	Operands []Operand
+	Open     token.Type            // Token type of '(', enables grouping and function calls; 0 to disable
+	Close    token.Type            // Token type of ')'
+	Comma    token.Type            // Token type separating function arguments; 0 for single-argument functions
+	Funcs    map[string]Func       // Functions, called as name(args), where name is an operand token; nil to disable
+	Names    map[token.Type]string // Token names for syntax errors, i.e. "expected number, found ')'"
+}
+
+// Error annotates an error with the position of the token that produced it.
+//
+type Error struct {
+	Pos token.Position
+	Err error
+}
+
+// Error implements error, i.e. "1:5: division by zero".
+//
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Err)
+}
+
+// Unwrap returns the wrapped error.
+//
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// TypeError is returned by the typed adapters when a value does not have the expected type.
+//
+type TypeError struct {
+	Expected string      // Name of the expected type, i.e. "float64"
+	Value    interface{} // The value received
+}
+
+// Error implements error, i.e. "expected float64, received string".
+//
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("expected %s, received %T", e.Expected, e.Value)
+}
+
+// ErrEmpty is returned by Compile when the token stream is empty.
+//
+var ErrEmpty = errors.New("empty expression")
+
+// Expr is a compiled expression.
+//
+type Expr struct {
+	root node
+	lang *Language
+}
+
+// Eval evaluates the expression within env, which is passed to each OperandFn.
+// Errors returned from handlers are returned as *Error, annotated with the position of the operator, operand or
+// function name.
+//
+func (e *Expr) Eval(env interface{}) (interface{}, error) {
+	return e.root.eval(e.lang, env)
+}
+
+// Pos returns the position of the leftmost operand or operator of the expression.
+//
+func (e *Expr) Pos() token.Position {
+	return e.root.pos()
+}
+
+// Compile parses the tokens into an Expr.
+// Syntax errors are returned as *Error, worded via Language.Names, i.e.
+// "1:3: expected number or '(', found end of input".
+// In interactive mode (see parser.WithInteractive), syntax errors at the end of the input remain incomplete.
+// Errors from the token stream are returned as-is.
+// Returns ErrEmpty if there are no tokens.
+//
+func Compile(lang *Language, tokens token.Nexter, opts ...parser.Option) (*Expr, error) {
+	c := newCompiler(lang)
+	opts = append([]parser.Option{parser.WithLexErrors()}, opts...)
+	asts, err := parser.ParseAll(tokens, c.parse, opts...)
+	if err != nil {
+		return nil, c.describe(err)
+	}
+	if len(asts) == 0 {
+		return nil, ErrEmpty
+	}
+	return &Expr{root: asts[0].(node), lang: lang}, nil
+}
+
+// Eval compiles the tokens and evaluates the expression within env.
+// See Compile and Expr.Eval.
+//
+func Eval(lang *Language, tokens token.Nexter, env interface{}) (interface{}, error) {
+	expr, err := Compile(lang, tokens)
+	if err != nil {
+		return nil, err
+	}
+	return expr.Eval(env)
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Typed Adapters
+// ---------------------------------------------------------------------------------------------------------------------
+
+// Floats adapts fn to a BinaryFn, returning a *TypeError unless both operands are float64.
+//
+func Floats(fn func(x float64, y float64) (interface{}, error)) BinaryFn {
+	return func(x interface{}, y interface{}) (interface{}, error) {
+		a, ok := x.(float64)
+		if !ok {
+			return nil, &TypeError{Expected: "float64", Value: x}
+		}
+		b, ok := y.(float64)
+		if !ok {
+			return nil, &TypeError{Expected: "float64", Value: y}
+		}
+		return fn(a, b)
+	}
+}
+
+// Float adapts fn to a UnaryFn, returning a *TypeError unless the operand is float64.
+//
+func Float(fn func(x float64) (interface{}, error)) UnaryFn {
+	return func(x interface{}) (interface{}, error) {
+		a, ok := x.(float64)
+		if !ok {
+			return nil, &TypeError{Expected: "float64", Value: x}
+		}
+		return fn(a)
+	}
+}
+
+// Bools adapts fn to a BinaryFn, returning a *TypeError unless both operands are bool.
+//
+func Bools(fn func(x bool, y bool) (interface{}, error)) BinaryFn {
+	return func(x interface{}, y interface{}) (interface{}, error) {
+		a, ok := x.(bool)
+		if !ok {
+			return nil, &TypeError{Expected: "bool", Value: x}
+		}
+		b, ok := y.(bool)
+		if !ok {
+			return nil, &TypeError{Expected: "bool", Value: y}
+		}
+		return fn(a, b)
+	}
+}
+
+// Bool adapts fn to a UnaryFn, returning a *TypeError unless the operand is bool.
+//
+func Bool(fn func(x bool) (interface{}, error)) UnaryFn {
+	return func(x interface{}) (interface{}, error) {
+		a, ok := x.(bool)
+		if !ok {
+			return nil, &TypeError{Expected: "bool", Value: x}
+		}
+		return fn(a)
+	}
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Nodes
+// ---------------------------------------------------------------------------------------------------------------------
+
+// node is implemented by all compiled expression nodes.
+//
+type node interface {
+	eval(lang *Language, env interface{}) (interface{}, error)
+	pos() token.Position
+}
+
+// operandNode is an operand token.
+//
+type operandNode struct {
+	tok token.Token
+	fn  OperandFn
+}
+
+// unaryNode is a prefix operator expression.
+//
+type unaryNode struct {
+	op token.Token
+	fn UnaryFn
+	x  node
+}
+
+// binaryNode is a binary operator expression.
+//
+type binaryNode struct {
+	op token.Token
+	fn BinaryFn
+	x  node
+	y  node
+}
+
+// callNode is a function call.
+//
+type callNode struct {
+	name token.Token
+	args []node
+}
+
+// eval implements node.eval().
+//
+func (n *operandNode) eval(_ *Language, env interface{}) (interface{}, error) {
+	v, err := n.fn(n.tok, env)
+	return v, annotate(n.tok, err)
+}
+
+// pos implements node.pos().
+//
+func (n *operandNode) pos() token.Position {
+	return token.Start(n.tok)
+}
+
+// eval implements node.eval().
+//
+func (n *unaryNode) eval(lang *Language, env interface{}) (interface{}, error) {
+	x, err := n.x.eval(lang, env)
+	if err != nil {
+		return nil, err
+	}
+	v, err := n.fn(x)
+	return v, annotate(n.op, err)
+}
+
+// pos implements node.pos().
+//
+func (n *unaryNode) pos() token.Position {
+	return token.Start(n.op)
+}
+
+// eval implements node.eval().
+//
+func (n *binaryNode) eval(lang *Language, env interface{}) (interface{}, error) {
+	x, err := n.x.eval(lang, env)
+	if err != nil {
+		return nil, err
+	}
+	y, err := n.y.eval(lang, env)
+	if err != nil {
+		return nil, err
+	}
+	v, err := n.fn(x, y)
+	return v, annotate(n.op, err)
+}
+
+// pos implements node.pos().
+//
+func (n *binaryNode) pos() token.Position {
+	return n.x.pos()
+}
+
+// eval implements node.eval().
+//
+func (n *callNode) eval(lang *Language, env interface{}) (interface{}, error) {
+	fn, ok := lang.Funcs[n.name.Value()]
+	if !ok {
+		return nil, annotate(n.name, fmt.Errorf("function '%s' not defined", n.name.Value()))
+	}
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		var err error
+		if args[i], err = arg.eval(lang, env); err != nil {
+			return nil, err
+		}
+	}
+	v, err := fn(args)
+	return v, annotate(n.name, err)
+}
+
+// pos implements node.pos().
+//
+func (n *callNode) pos() token.Position {
+	return token.Start(n.name)
+}
+
+// annotate wraps err with the position of tok, unless err is nil or already annotated.
+//
+func annotate(tok token.Token, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*Error); ok {
+		return err
+	}
+	return &Error{Pos: token.Start(tok), Err: err}
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Compiler
+// ---------------------------------------------------------------------------------------------------------------------
+
+// errSyntax is returned when a token is not matched; The details are recorded by the parser (see Parser.Furthest).
+//
+var errSyntax = errors.New("syntax error")
+
+// compiler holds the lookup tables built from a Language.
+//
+type compiler struct {
+	lang     *Language
+	ops      []parser.Operator                // All binary operators, for ParseBinary
+	binary   map[token.Type]BinaryFn          // Binary operator handlers
+	unary    map[token.Type]Unary             // Prefix operators
+	tighter  map[token.Type][]parser.Operator // Binary operators binding tighter than each prefix operator
+	operands map[token.Type]OperandFn         // Operand handlers
+	starts   []token.Type                     // Token types that can start an operand
+	closers  []token.Type                     // Token types that can follow a function argument
+}
+
+// newCompiler builds the lookup tables for lang.
+//
+func newCompiler(lang *Language) *compiler {
+	c := &compiler{
+		lang:     lang,
+		binary:   map[token.Type]BinaryFn{},
+		unary:    map[token.Type]Unary{},
+		tighter:  map[token.Type][]parser.Operator{},
+		operands: map[token.Type]OperandFn{},
+	}
+	for _, b := range lang.Binary {
+		c.ops = append(c.ops, parser.Operator{Type: b.Type, Prec: b.Prec, Right: b.Right})
+		c.binary[b.Type] = b.Fn
+	}
+	for _, u := range lang.Unary {
+		c.unary[u.Type] = u
+		c.starts = append(c.starts, u.Type)
+		for _, op := range c.ops {
+			if op.Prec > u.Prec {
+				c.tighter[u.Type] = append(c.tighter[u.Type], op)
+			}
+		}
+	}
+	for _, o := range lang.Operands {
+		c.operands[o.Type] = o.Fn
+		c.starts = append(c.starts, o.Type)
+	}
+	if lang.Open != 0 {
+		c.starts = append(c.starts, lang.Open)
+	}
+	if lang.Comma != 0 {
+		c.closers = append(c.closers, lang.Comma)
+	}
+	c.closers = append(c.closers, lang.Close)
+	return c
+}
+
+// parse parses a single expression, which must consume all of the tokens.
+// Syntax errors are reported via the furthest failure (see Parser.EmitFurthest).
+//
+func (c *compiler) parse(p *parser.Parser) parser.Fn {
+	x, err := c.parseExpr(p, c.ops)
+	if err == nil && p.CanPeek(1) {
+		p.Expect()
+		err = errSyntax
+	}
+	if err == errSyntax {
+		p.EmitFurthest()
+		return nil
+	}
+	if err != nil {
+		p.EmitError(err.Error())
+		return nil
+	}
+	p.Emit(x)
+	return nil // One pass
+}
+
+// parseExpr parses a chain of operands separated by the binary operators in ops.
+//
+func (c *compiler) parseExpr(p *parser.Parser, ops []parser.Operator) (node, error) {
+	x, err := p.ParseBinary(c.parseOperand, ops, c.build)
+	if err != nil {
+		return nil, err
+	}
+	return x.(node), nil
+}
+
+// build builds a binary operator node, for use with ParseBinary.
+//
+func (c *compiler) build(op token.Token, left interface{}, right interface{}) (interface{}, error) {
+	return &binaryNode{op: op, fn: c.binary[op.Type()], x: left.(node), y: right.(node)}, nil
+}
+
+// parseOperand parses [ unary_op operand | '(' expr ')' | name '(' args ')' | operand ], for use with ParseBinary.
+//
+func (c *compiler) parseOperand(p *parser.Parser) (interface{}, error) {
+	tok, ok := p.Expect(c.starts...)
+	if !ok {
+		return nil, errSyntax
+	}
+
+	// Prefix operator
+	//
+	if u, ok := c.unary[tok.Type()]; ok {
+		x, err := c.parseExpr(p, c.tighter[u.Type])
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: tok, fn: u.Fn, x: x}, nil
+	}
+
+	// Group
+	//
+	if tok.Type() == c.lang.Open {
+		x, err := c.parseExpr(p, c.ops)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.Expect(c.lang.Close); !ok {
+			return nil, errSyntax
+		}
+		return x, nil
+	}
+
+	// Function call
+	//
+	if c.lang.Funcs != nil && c.lang.Open != 0 && p.CanPeek(1) && p.PeekType(1) == c.lang.Open {
+		p.Next() // Skip '('
+		return c.parseCall(p, tok)
+	}
+
+	return &operandNode{tok: tok, fn: c.operands[tok.Type()]}, nil
+}
+
+// parseCall parses the arguments of a function call [ ( expr ( ',' expr )* )? ')' ].
+//
+func (c *compiler) parseCall(p *parser.Parser, name token.Token) (node, error) {
+	call := &callNode{name: name}
+	if p.CanPeek(1) && p.PeekType(1) == c.lang.Close {
+		p.Next()
+		return call, nil
+	}
+	for {
+		x, err := c.parseExpr(p, c.ops)
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, x)
+		tok, ok := p.Expect(c.closers...)
+		if !ok {
+			return nil, errSyntax
+		}
+		if tok.Type() == c.lang.Close {
+			return call, nil
+		}
+	}
+}
+
+// describe rewords parser failures as *Error, using Language.Names.
+// Incomplete errors remain incomplete.
+//
+func (c *compiler) describe(err error) error {
+	if e, ok := err.(*parser.IncompleteError); ok {
+		return &parser.IncompleteError{Err: c.describe(e.Err)}
+	}
+	f, ok := err.(*parser.Failure)
+	if !ok {
+		return err
+	}
+	found := "end of input"
+	if f.Found != nil {
+		found = c.name(f.Found.Type())
+		if _, ok := c.operands[f.Found.Type()]; ok {
+			found += fmt.Sprintf(" '%s'", f.Found.Value())
+		}
+	}
+	if f.Expected.Len() == 0 {
+		return &Error{Pos: f.Pos, Err: fmt.Errorf("unexpected %s", found)}
+	}
+	var expected []string
+	for _, t := range f.Expected.Types() {
+		expected = append(expected, c.name(t))
+	}
+	return &Error{Pos: f.Pos, Err: fmt.Errorf("expected %s, found %s", strings.Join(expected, " or "), found)}
+}
+
+// name returns the name of the token type, falling back to the type number.
+//
+func (c *compiler) name(typ token.Type) string {
+	if name, ok := c.lang.Names[typ]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", typ)
+}
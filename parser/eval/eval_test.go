@@ -0,0 +1,288 @@
+package eval
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// Test tokens
+//
+const (
+	TNumber token.Type = lexer.TStart + iota
+	TId
+	TPlus
+	TMinus
+	TMultiply
+	TDivide
+	TPower
+	TEquals
+	TNot
+	TOpen
+	TClose
+	TComma
+)
+
+// singles maps single-character operators to their token types
+//
+var singles = map[rune]token.Type{
+	'+': TPlus, '-': TMinus, '*': TMultiply, '/': TDivide, '^': TPower, '=': TEquals, '!': TNot,
+	'(': TOpen, ')': TClose, ',': TComma,
+}
+
+// lex lexes numbers, ids and single-character operators, skipping spaces
+//
+func lex(l *lexer.Lexer) lexer.Fn {
+	r := l.Next()
+	switch {
+	case r == ' ':
+		l.Clear()
+	case singles[r] != 0:
+		l.EmitToken(singles[r])
+	case r >= '0' && r <= '9':
+		for l.CanPeek(1) && (l.Peek(1) >= '0' && l.Peek(1) <= '9' || l.Peek(1) == '.') {
+			l.Next()
+		}
+		l.EmitToken(TNumber)
+	case r >= 'a' && r <= 'z':
+		for l.CanPeek(1) && l.Peek(1) >= 'a' && l.Peek(1) <= 'z' {
+			l.Next()
+		}
+		l.EmitToken(TId)
+	default:
+		l.EmitErrorToken("unexpected character")
+		return nil
+	}
+	return lex
+}
+
+// number parses a number literal
+//
+func number(tok token.Token, _ interface{}) (interface{}, error) {
+	return strconv.ParseFloat(tok.Value(), 64)
+}
+
+// variable looks up an id in the environment
+//
+func variable(tok token.Token, env interface{}) (interface{}, error) {
+	switch tok.Value() {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if v, ok := env.(map[string]interface{})[tok.Value()]; ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("variable '%s' not defined", tok.Value())
+}
+
+// equals compares any two values
+//
+func equals(x interface{}, y interface{}) (interface{}, error) {
+	return x == y, nil
+}
+
+// testLang is a small calculator language, with booleans and functions
+//
+var testLang = &Language{
+	Binary: []Binary{
+		{Type: TEquals, Prec: 1, Fn: equals},
+		{Type: TPlus, Prec: 2, Fn: Floats(func(x float64, y float64) (interface{}, error) {
+			return x + y, nil
+		})},
+		{Type: TMinus, Prec: 2, Fn: Floats(func(x float64, y float64) (interface{}, error) {
+			return x - y, nil
+		})},
+		{Type: TMultiply, Prec: 3, Fn: Floats(func(x float64, y float64) (interface{}, error) {
+			return x * y, nil
+		})},
+		{Type: TDivide, Prec: 3, Fn: Floats(func(x float64, y float64) (interface{}, error) {
+			if y == 0 {
+				return nil, errors.New("division by zero")
+			}
+			return x / y, nil
+		})},
+		{Type: TPower, Prec: 5, Right: true, Fn: Floats(func(x float64, y float64) (interface{}, error) {
+			return math.Pow(x, y), nil
+		})},
+	},
+	Unary: []Unary{
+		{Type: TMinus, Prec: 4, Fn: Float(func(x float64) (interface{}, error) {
+			return -x, nil
+		})},
+		{Type: TNot, Prec: 4, Fn: Bool(func(x bool) (interface{}, error) {
+			return !x, nil
+		})},
+	},
+	Operands: []Operand{{Type: TNumber, Fn: number}, {Type: TId, Fn: variable}},
+	Open:     TOpen,
+	Close:    TClose,
+	Comma:    TComma,
+	Funcs: map[string]Func{
+		"max": func(args []interface{}) (interface{}, error) {
+			if len(args) == 0 {
+				return nil, errors.New("max: no arguments")
+			}
+			max := math.Inf(-1)
+			for _, arg := range args {
+				f, ok := arg.(float64)
+				if !ok {
+					return nil, &TypeError{Expected: "float64", Value: arg}
+				}
+				max = math.Max(max, f)
+			}
+			return max, nil
+		},
+	},
+	Names: map[token.Type]string{
+		TNumber: "number", TId: "id", TPlus: "'+'", TMinus: "'-'", TMultiply: "'*'", TDivide: "'/'", TPower: "'^'",
+		TEquals: "'='", TNot: "'!'", TOpen: "'('", TClose: "')'", TComma: "','",
+	},
+}
+
+// vars is the environment for the tests
+//
+var vars = map[string]interface{}{"x": 3.0, "s": "str"}
+
+// TestEval
+//
+func TestEval(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"1 + 2 * 3", 7.0},
+		{"8 - 4 - 2", 2.0},
+		{"2 ^ 3 ^ 2", 512.0},
+		{"-2 ^ 2", -4.0},
+		{"-2 * 3", -6.0},
+		{"--x", 3.0},
+		{"(1 + 2) * x", 9.0},
+		{"max(1, x * 2, 4)", 6.0},
+		{"max(max(1), 2)", 2.0},
+		{"1 + 2 = x", true},
+		{"!(1 = 2)", true},
+		{"!true = false", true},
+	}
+	for _, test := range tests {
+		v, err := Eval(testLang, lexer.LexString(test.input, lex), vars)
+		if err != nil {
+			t.Errorf("Eval(%q) unexpected error: %v", test.input, err)
+			continue
+		}
+		if v != test.expected {
+			t.Errorf("Eval(%q) expecting %v, received %v", test.input, test.expected, v)
+		}
+	}
+}
+
+// TestCompileReuse
+//
+func TestCompileReuse(t *testing.T) {
+	expr, err := Compile(testLang, lexer.LexString("  x * 2", lex))
+	if err != nil {
+		t.Fatalf("Compile() unexpected error: %v", err)
+	}
+	if pos := expr.Pos().String(); pos != "1:3" {
+		t.Errorf("Expr.Pos() expecting 1:3, received %s", pos)
+	}
+	for _, x := range []float64{1, 5} {
+		v, err := expr.Eval(map[string]interface{}{"x": x})
+		if err != nil || v != x*2 {
+			t.Errorf("Expr.Eval(x=%v) expecting %v, received %v, %v", x, x*2, v, err)
+		}
+	}
+}
+
+// TestRuntimeErrors
+//
+func TestRuntimeErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 + 4 / 0", "1:7: division by zero"},
+		{"1 + y", "1:5: variable 'y' not defined"},
+		{"2 * s", "1:3: expected float64, received string"},
+		{"-true", "1:1: expected float64, received bool"},
+		{"!1", "1:1: expected bool, received float64"},
+		{"max()", "1:1: max: no arguments"},
+		{"max(1, s)", "1:1: expected float64, received string"},
+		{"min(1)", "1:1: function 'min' not defined"},
+		{"max(1, 2 / 0)", "1:10: division by zero"},
+	}
+	for _, test := range tests {
+		_, err := Eval(testLang, lexer.LexString(test.input, lex), vars)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("Eval(%q) expecting error '%s', received '%v'", test.input, test.expected, err)
+			continue
+		}
+		if _, ok := err.(*Error); !ok {
+			t.Errorf("Eval(%q) expecting *Error, received %T", test.input, err)
+		}
+	}
+}
+
+// TestSyntaxErrors
+//
+func TestSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 +", "1:4: expected number or id or '-' or '!' or '(', found end of input"},
+		{"1 2", "1:3: unexpected number '2'"},
+		{"(1", "1:3: expected ')', found end of input"},
+		{"max(1 2)", "1:7: expected ')' or ',', found number '2'"},
+		{"max(1,)", "1:7: expected number or id or '-' or '!' or '(', found ')'"},
+		{"1 $", "1:3: unexpected character"},
+		{"1 + $ 2", "1:5: unexpected character"},
+		{"max($, 1)", "1:5: unexpected character"},
+	}
+	for _, test := range tests {
+		_, err := Compile(testLang, lexer.LexString(test.input, lex))
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("Compile(%q) expecting error '%s', received '%v'", test.input, test.expected, err)
+		}
+	}
+}
+
+// TestSyntaxErrorsUnnamed
+//
+func TestSyntaxErrorsUnnamed(t *testing.T) {
+	lang := *testLang
+	lang.Names = nil
+	_, err := Compile(&lang, lexer.LexString("(1", lex))
+	expected := fmt.Sprintf("1:3: expected %d, found end of input", TClose)
+	if err == nil || err.Error() != expected {
+		t.Errorf("Compile() expecting error '%s', received '%v'", expected, err)
+	}
+}
+
+// TestEmpty
+//
+func TestEmpty(t *testing.T) {
+	if _, err := Compile(testLang, lexer.LexString("   ", lex)); err != ErrEmpty {
+		t.Errorf("Compile() expecting ErrEmpty, received '%v'", err)
+	}
+}
+
+// TestIncomplete
+//
+func TestIncomplete(t *testing.T) {
+	_, err := Compile(testLang, lexer.LexString("max(1,", lex), parser.WithInteractive())
+	if !token.IsIncomplete(err) {
+		t.Fatalf("Compile() expecting incomplete error, received '%v'", err)
+	}
+	if !strings.HasPrefix(err.Error(), "1:7: expected number") {
+		t.Errorf("Compile() expecting named error, received '%v'", err)
+	}
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"io"
+
+	"github.com/tekwizely/go-parsing/lexer/diag"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// EventHandler receives events from a parser initiated via ParseEvents.
+// Events are delivered in the order they occur, as your Parser.Fn functions execute.
+//
+type EventHandler interface {
+
+	// BeginNode signals the start of a node of the specified kind.
+	//
+	BeginNode(kind string)
+
+	// Token delivers a matched token, in input order.
+	//
+	Token(tok token.Token)
+
+	// EndNode signals the end of a node of the specified kind.
+	//
+	EndNode(kind string)
+
+	// Error delivers a diagnostic describing an error emitted via Parser.EmitError (or passed through from the input,
+	// see WithLexErrors), covering the tokens matched when the error was emitted.
+	//
+	Error(d diag.Diagnostic)
+}
+
+// ParseEvents initiates a parser against the input token stream, delivering events to the handler instead of
+// building ASTs.
+// Your Parser.Fn functions generate events via Parser.BeginNode() and Parser.EndNode(), with matched tokens delivered
+// as they are committed.
+// Any ASTs emitted via Emit() are discarded.
+// ParseEvents returns once the parser has emitted EOF.
+// The parser will auto-emit EOF before exiting it if has not already been emitted.
+// Options, if any, are applied before parsing begins.
+// Returns the misuse error suppressed in lenient mode (see WithLenient), if any, otherwise nil.
+//
+func ParseEvents(tokens token.Nexter, start Fn, handler EventHandler, opts ...Option) error {
+	p := newParser(tokens, start, opts)
+	p.events = handler
+	nexter := &astNexter{parser: p}
+	var result error
+	for _, err := nexter.Next(); err != io.EOF; _, err = nexter.Next() {
+		// Discard emitted ASTs, keeping the first error
+		//
+		if err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// BeginNode commits all previously-matched tokens, then signals the start of a node of the specified kind.
+// When parsing via ParseEvents, the committed tokens are delivered to the EventHandler via Token(), followed by
+// BeginNode(kind).
+// Otherwise, the matched tokens are simply discarded.
+// All outstanding markers are invalidated after this call.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (p *Parser) BeginNode(kind string) {
+	// No events after EOF emitted
+	//
+	if p.afterEOF("Parser.BeginNode: No further events allowed after EOF is emitted") {
+		return
+	}
+	p.commit()
+	if p.events != nil {
+		p.events.BeginNode(kind)
+	}
+}
+
+// EndNode commits all previously-matched tokens, then signals the end of a node of the specified kind.
+// When parsing via ParseEvents, the committed tokens are delivered to the EventHandler via Token(), followed by
+// EndNode(kind).
+// Otherwise, the matched tokens are simply discarded.
+// All outstanding markers are invalidated after this call.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (p *Parser) EndNode(kind string) {
+	// No events after EOF emitted
+	//
+	if p.afterEOF("Parser.EndNode: No further events allowed after EOF is emitted") {
+		return
+	}
+	p.commit()
+	if p.events != nil {
+		p.events.EndNode(kind)
+	}
+}
+
+// commit delivers the matched tokens to the event handler (if any), then consumes them.
+// All outstanding markers are invalidated after this call.
+//
+func (p *Parser) commit() {
+	if p.events != nil {
+		for n, e := 0, p.cache.Front(); n < p.matchLen; n, e = n+1, e.Next() {
+			p.events.Token(e.Value.(token.Token))
+		}
+	}
+	p.clear()
+}
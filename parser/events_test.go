@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/diag"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// recordingHandler records events as strings
+//
+type recordingHandler struct {
+	events []string
+}
+
+func (h *recordingHandler) BeginNode(kind string) {
+	h.events = append(h.events, "begin:"+kind)
+}
+func (h *recordingHandler) Token(tok token.Token) {
+	h.events = append(h.events, fmt.Sprintf("token:%d", tok.Type()))
+}
+func (h *recordingHandler) EndNode(kind string) {
+	h.events = append(h.events, "end:"+kind)
+}
+func (h *recordingHandler) Error(d diag.Diagnostic) {
+	h.events = append(h.events, fmt.Sprintf("error:%s:%s", d.Span, d.Message))
+}
+
+// expectEvents
+//
+func expectEvents(t *testing.T, h *recordingHandler, match string) {
+	if s := strings.Join(h.events, " "); s != match {
+		t.Errorf("EventHandler expecting events '%s', received '%s'", match, s)
+	}
+}
+
+// TestParseEvents
+//
+func TestParseEvents(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.BeginNode("list")
+		p.Next()
+		p.BeginNode("item")
+		p.Next()
+		p.EndNode("item")
+		p.Next()
+		p.EndNode("list")
+		return nil
+	}
+	h := &recordingHandler{}
+	ParseEvents(mockLexer(TOne, TTwo, TThree), fn, h)
+	expectEvents(t, h, "begin:list token:1 begin:item token:2 end:item token:3 end:list")
+}
+
+// TestParseEventsMarker confirms only committed tokens are delivered
+//
+func TestParseEventsMarker(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.BeginNode("root")
+		m := p.Marker()
+		p.Next()
+		p.Next()
+		m.Apply()
+		p.Next()
+		p.EndNode("root")
+		return nil
+	}
+	h := &recordingHandler{}
+	ParseEvents(mockLexer(TOne, TTwo), fn, h)
+	expectEvents(t, h, "begin:root token:1 end:root")
+}
+
+// TestParseEventsClear confirms cleared tokens are not delivered
+//
+func TestParseEventsClear(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.Next()
+		p.Clear()
+		p.BeginNode("root")
+		p.Next()
+		p.EndNode("root")
+		return nil
+	}
+	h := &recordingHandler{}
+	ParseEvents(mockLexer(TOne, TTwo), fn, h)
+	expectEvents(t, h, "begin:root token:2 end:root")
+}
+
+// TestParseEventsError
+//
+func TestParseEventsError(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.BeginNode("root")
+		p.Next()
+		p.EmitError("ERROR")
+		p.EndNode("root")
+		return nil
+	}
+	h := &recordingHandler{}
+	ParseEvents(positioned(TOne), fn, h)
+	expectEvents(t, h, "begin:root error:1:1-1:2:ERROR end:root")
+}
+
+// TestParseEventsMisuse confirms the misuse error suppressed in lenient mode is returned
+//
+func TestParseEventsMisuse(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.EmitEOF()
+		p.BeginNode("root")
+		return nil
+	}
+	err := ParseEvents(mockLexer(TOne), fn, &recordingHandler{}, WithLenient())
+	if err == nil || err.Error() != "Parser.BeginNode: No further events allowed after EOF is emitted" {
+		t.Errorf("ParseEvents() expecting misuse error, received '%v'", err)
+	}
+}
+
+// TestParseEventsEmitDiscarded
+//
+func TestParseEventsEmitDiscarded(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.Next()
+		p.Emit("AST")
+		if p.CanPeek(1) {
+			p.BeginNode("root")
+			p.Next()
+			p.EndNode("root")
+		}
+		return nil
+	}
+	h := &recordingHandler{}
+	ParseEvents(mockLexer(TOne, TTwo), fn, h)
+	expectEvents(t, h, "begin:root token:2 end:root")
+}
+
+// TestNodeEventsWithoutHandler confirms events are ignored, and tokens consumed, outside of ParseEvents
+//
+func TestNodeEventsWithoutHandler(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.BeginNode("root")
+		m := p.Marker()
+		p.Next()
+		p.EndNode("root")
+		expectMarkerValid(t, m, false)
+		expectPeekType(t, p, 1, TTwo)
+		p.Next()
+		p.Emit("TTwo")
+		return nil
+	}
+	nexter := Parse(mockLexer(TOne, TTwo), fn)
+	expectNexterNext(t, nexter, "TTwo")
+	expectNexterEOF(t, nexter)
+}
+
+// TestNodeEventsAfterEOF
+//
+func TestNodeEventsAfterEOF(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.EmitEOF()
+		assertPanic(t, func() {
+			p.BeginNode("root")
+		}, "Parser.BeginNode: No further events allowed after EOF is emitted")
+		assertPanic(t, func() {
+			p.EndNode("root")
+		}, "Parser.EndNode: No further events allowed after EOF is emitted")
+		return nil
+	}
+	ParseEvents(mockLexer(TOne), fn, &recordingHandler{})
+}
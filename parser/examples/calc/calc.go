@@ -1,18 +1,20 @@
 package main
 
 //
-//	Input is read from STDIN
+//	Input is read from STDIN, a line at a time
 //
-//	The input expression is matched against the following pattern:
+//	Each input line is matched against the following pattern:
 //
 //	input_exp:
-//	( id '=' )? general_exp
+//		( id '=' )? general_exp
 //	general_exp:
-//		operand ( operator operand )?
+//		unary_exp ( operator unary_exp )*
+//	unary_exp:
+//		'-' unary_exp | operand
 //	operand:
-//		number | id | '(' general_exp ')'
+//		number | id | id '(' general_exp ')' | history | '(' general_exp ')'
 //	operator:
-//		'+' | '-' | '*' | '/'
+//		'+' | '-' | '*' | '/' | '^'
 //	number:
 //		digit+ ( '.' digit+ )?
 //	digit:
@@ -21,11 +23,34 @@ package main
 //		alpha ( alpha | digit )*
 //	alpha:
 //		['a'..'z'] | ['A'..'Z']
+//	history:
+//		'$' digit+
 //
-//	Precedence is as expected, with '*' and '/' have higher precedence
-//	than '+' and '-', as follows:
+//	Precedence is as expected, with '^' having the highest precedence, followed by unary '-', then '*' and '/', and
+//	then '+' and '-', as follows:
 //
 //	1 + 2 * 3 - 4 / 5  ==  1 + (2 * 3) - (4 / 5)
+//	-2 ^ 2             ==  -(2 ^ 2)
+//
+//	Operators of the same precedence are left-associative, except for '^', which is right-associative, as follows:
+//
+//	8 - 4 - 2  ==  (8 - 4) - 2
+//	2 ^ 3 ^ 2  ==  2 ^ (3 ^ 2)
+//
+//	The following functions are available:
+//
+//	sqrt, sin, cos, tan, abs, ln, exp
+//
+//	Each result is numbered, and can be referenced in later lines via its history number, i.e. $1:
+//
+//	> 1 + 2
+//	$1 = 3
+//	> x = $1 * 2
+//	x = 6
+//	> sqrt(x + 3)
+//	$2 = 3
+//
+//	Lines ending mid-expression are continued on the next line.
 //
 
 import (
@@ -33,66 +58,208 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/tekwizely/go-parsing/lexer"
 	"github.com/tekwizely/go-parsing/lexer/token"
 	"github.com/tekwizely/go-parsing/parser"
 )
 
-// We define our lexer tokens starting from the pre-defined EOF token
+// We define our lexer tokens starting from the pre-defined START token
 //
 const (
 	TId token.Type = lexer.TStart + iota
 	TNumber
+	THistory
 	TPlus
 	TMinus
 	TMultiply
 	TDivide
+	TPower
 	TEquals
 	TOpenParen
 	TCloseParen
 )
 
-// To store variables
+// Single-character tokens
 //
-var vars = map[string]float64{}
+var singleChars = []byte{'+', '-', '*', '/', '^', '=', '(', ')'}
 
-// Single-character tokens
+var singleTokens = []token.Type{TPlus, TMinus, TMultiply, TDivide, TPower, TEquals, TOpenParen, TCloseParen}
+
+// Token names, for error messages
 //
-var singleChars = []byte{'+', '-', '*', '/', '=', '(', ')'}
+var tokenNames = map[token.Type]string{
+	TId:         "id",
+	TNumber:     "number",
+	THistory:    "history",
+	TPlus:       "'+'",
+	TMinus:      "'-'",
+	TMultiply:   "'*'",
+	TDivide:     "'/'",
+	TPower:      "'^'",
+	TEquals:     "'='",
+	TOpenParen:  "'('",
+	TCloseParen: "')'",
+}
 
-var singleTokens = []token.Type{TPlus, TMinus, TMultiply, TDivide, TEquals, TOpenParen, TCloseParen}
+// functions are the built-in functions.
+//
+var functions = map[string]func(float64) float64{
+	"sqrt": math.Sqrt,
+	"sin":  math.Sin,
+	"cos":  math.Cos,
+	"tan":  math.Tan,
+	"abs":  math.Abs,
+	"ln":   math.Log,
+	"exp":  math.Exp,
+}
 
 // main
 //
 func main() {
-	// Create a buffered reader from STDIN
+	// Create a scanner to read lines from STDIN
 	//
-	stdin := bufio.NewReader(os.Stdin)
+	stdin := bufio.NewScanner(os.Stdin)
+
+	// To store variables and results
+	// Shared across all input lines
+	//
+	calc := NewCalc()
 
 	// Read each line of input
 	//
-	for input, _, err := stdin.ReadLine(); err == nil; input, _, err = stdin.ReadLine() {
+	input := ""
+	prompt("> ")
+	for stdin.Scan() {
+		input += stdin.Text() + "\n"
+
 		// Anything to process?
 		//
-		if len(input) > 0 {
-			// Create a new lexer to turn the input text into tokens
-			//
-			tokens := lexer.LexBytes(input, lex)
-
-			// Create a new parser that feeds off the lexer and generates expression values
-			//
-			values := parser.Parse(tokens, parse)
-
-			// Loop over parser emits
-			//
-			for value, parseErr := values.Next(); parseErr == nil; value, parseErr = values.Next() {
-				fmt.Printf("%v\n", value)
-			}
+		if strings.TrimSpace(input) == "" {
+			input = ""
+			prompt("> ")
+			continue
+		}
+
+		// Incomplete input (i.e. "1 +") is continued on the next line
+		//
+		result, err := calc.Eval(input)
+		if token.IsIncomplete(err) {
+			prompt("| ")
+			continue
+		}
+		if err != nil {
+			fmt.Println("error:", err)
+		} else {
+			fmt.Println(result)
+		}
+		input = ""
+		prompt("> ")
+	}
+	if input != "" {
+		fmt.Println("error: unexpected end of input")
+	}
+}
+
+// prompt prints the prompt, if stdin is a terminal.
+//
+func prompt(p string) {
+	if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		fmt.Print(p)
+	}
+}
+
+// Calc holds the variables and results of the calculator.
+//
+type Calc struct {
+	Vars    map[string]float64
+	History []float64 // Results, referenced as $1, $2, ...
+}
+
+// NewCalc returns a new calculator.
+//
+func NewCalc() *Calc {
+	return &Calc{Vars: map[string]float64{}}
+}
+
+// Result is the result of evaluating a line of input.
+//
+type Result struct {
+	Var   string // Variable assigned, "" for expressions
+	N     int    // History number of expression results
+	Value float64
+}
+
+// String implements fmt.Stringer, i.e. "x = 3" or "$1 = 3".
+//
+func (r *Result) String() string {
+	if r.Var != "" {
+		return fmt.Sprintf("%s = %v", r.Var, r.Value)
+	}
+	return fmt.Sprintf("$%d = %v", r.N, r.Value)
+}
+
+// Eval parses and evaluates a line of input.
+// Expression results are added to the history, and assignments update the variables.
+// Syntax errors at the end of the input are incomplete (see token.IsIncomplete), as the input may be continued.
+//
+func (c *Calc) Eval(input string) (*Result, error) {
+	// Create a new lexer to turn the input text into tokens
+	//
+	tokens := lexer.LexString(input, lex)
+
+	// Create a new parser that feeds off the lexer and generates the statement
+	//
+	ast, _, err := parser.ParseOne(tokens, parse, parser.WithInteractive(), parser.WithLexErrors())
+	if err != nil {
+		return nil, describe(err)
+	}
+
+	// Evaluate the statement
+	//
+	s := ast.(*Statement)
+	value, err := s.Expr.Eval(c)
+	if err != nil {
+		return nil, err
+	}
+	if s.Var != "" {
+		c.Vars[s.Var] = value
+		return &Result{Var: s.Var, Value: value}, nil
+	}
+	c.History = append(c.History, value)
+	return &Result{N: len(c.History), Value: value}, nil
+}
+
+// describe rewords parser failures with token names, i.e. "1:5: expected ')', found end of input".
+// Incomplete errors remain incomplete.
+//
+func describe(err error) error {
+	if e, ok := err.(*parser.IncompleteError); ok {
+		return &parser.IncompleteError{Err: describe(e.Err)}
+	}
+	f, ok := err.(*parser.Failure)
+	if !ok {
+		return err
+	}
+	found := "end of input"
+	if f.Found != nil {
+		found = tokenNames[f.Found.Type()]
+		if t := f.Found.Type(); t == TId || t == TNumber || t == THistory {
+			found += fmt.Sprintf(" '%s'", f.Found.Value())
 		}
 	}
+	if f.Expected.Len() == 0 {
+		return fmt.Errorf("%s: unexpected %s", f.Pos, found)
+	}
+	var expected []string
+	for _, t := range f.Expected.Types() {
+		expected = append(expected, tokenNames[t])
+	}
+	return fmt.Errorf("%s: expected %s, found %s", f.Pos, strings.Join(expected, " or "), found)
 }
 
 // lex is the starting (and only) StateFn for lexing the input into tokens
@@ -124,12 +291,24 @@ func lex(l *lexer.Lexer) lexer.Fn {
 	case tryMatchID(l):
 		l.EmitToken(TId)
 
+	// History
+	//
+	case tryMatchRune(l, '$'):
+		if !tryMatchDigit(l) {
+			l.EmitErrorToken("expecting history number after '$'")
+			return nil
+		}
+		for tryMatchDigit(l) {
+			// Nothing to do, rune already matched
+		}
+		l.EmitToken(THistory)
+
 	// Unknown
 	//
 	default:
-		r := l.Next()
-		l.Clear()
-		fmt.Printf("Unknown Character: '%c'\n", r)
+		l.Next()
+		l.EmitErrorToken("unknown character")
+		return nil
 	}
 
 	// See you again soon!
@@ -140,7 +319,7 @@ func lex(l *lexer.Lexer) lexer.Fn {
 //
 func tryMatchWhitespace(l *lexer.Lexer) bool {
 	if l.CanPeek(1) {
-		if r := l.Peek(1); r == ' ' || r == '\t' {
+		if r := l.Peek(1); r == ' ' || r == '\t' || r == '\r' || r == '\n' {
 			l.Next()
 			return true
 		}
@@ -227,182 +406,276 @@ func tryMatchID(l *lexer.Lexer) bool {
 	return false
 }
 
-// parse tries to parse an expression from the lexed tokens.
-// Delegates to either parseEvaluation or parseAssignment.
-//
-func parse(p *parser.Parser) parser.Fn {
+// ---------------------------------------------------------------------------------------------------------------------
+// AST
+// ---------------------------------------------------------------------------------------------------------------------
 
-	switch {
+// Statement is a line of input.
+//
+type Statement struct {
+	Var  string // Variable assigned, "" for expressions
+	Expr Expr
+}
 
-	// Assignment
+// Expr is implemented by all expressions.
+//
+type Expr interface {
+	// Eval evaluates the expression.
 	//
-	case p.CanPeek(3) && p.PeekType(1) == TId && p.PeekType(2) == TEquals:
-		return parseAssignment
+	Eval(c *Calc) (float64, error)
+}
 
-	// Evaluation
-	//
-	default:
-		return parseEvaluation
-	}
+// Number is a number.
+//
+type Number struct {
+	Value float64
 }
 
-// parseAssignment evaluates an expression and stores the results in the specified variable.
-// The assignment will be in the form [ ID '=' expression ].
-// Assumes "ID '='" has been peek-matched by root parser.
+// Var is a variable reference.
 //
-func parseAssignment(p *parser.Parser) parser.Fn {
-	tID := p.Next()
-	p.Next() // Skip '='
-	if value, err := parseGeneralExpression(p); err == nil {
-		// Should be at end of input
-		//
-		if !p.CanPeek(1) {
-			vars[tID.Value()] = value
-		} else {
-			fmt.Println("Expecting Operator")
-		}
-	} else {
-		fmt.Println(err.Error())
-	}
-	return nil // One pass
+type Var struct {
+	Pos  token.Position
+	Name string
 }
 
-// parseEvaluation parses a general experssion and emits the computed result.
+// History is a history reference, i.e. $1.
 //
-func parseEvaluation(p *parser.Parser) parser.Fn {
-	if value, err := parseGeneralExpression(p); err == nil {
-		// Should be at end of input
-		//
-		if !p.CanPeek(1) {
-			p.Emit(value)
-		} else {
-			fmt.Println("Expecting Operator")
-		}
-	} else {
-		fmt.Println(err.Error())
-	}
-	return nil // One pass
+type History struct {
+	Pos token.Position
+	N   int
 }
 
-// parseGeneralExpression is the starting point for parsing a General Expression.
-// It is basically a pass-through to parseAdditiveExpression, but it feels cleaner.
+// Negate is a unary '-' expression.
 //
-func parseGeneralExpression(p *parser.Parser) (f float64, err error) {
-	return parseAdditiveExpression(p)
+type Negate struct {
+	X Expr
 }
 
-// parseAdditiveExpression parses [ expression ( ( '+' | '-' ) expression )? ].
+// Binary is a binary operator expression.
 //
-func parseAdditiveExpression(p *parser.Parser) (f float64, err error) {
+type Binary struct {
+	Op    token.Token
+	Left  Expr
+	Right Expr
+}
 
-	var a float64
-	if f, err = parseMultiplicitiveExpression(p); err == nil && p.CanPeek(1) {
+// Call is a function call.
+//
+type Call struct {
+	Pos  token.Position
+	Name string
+	Arg  Expr
+}
 
-		switch p.PeekType(1) {
+// Eval implements Expr.Eval().
+//
+func (n *Number) Eval(*Calc) (float64, error) {
+	return n.Value, nil
+}
 
-		// Add (+)
-		//
-		case TPlus:
-			p.Next() // Skip '+'
-			if a, err = parseAdditiveExpression(p); err == nil {
-				f += a
-			}
+// Eval implements Expr.Eval().
+//
+func (v *Var) Eval(c *Calc) (float64, error) {
+	if f, ok := c.Vars[v.Name]; ok {
+		return f, nil
+	}
+	return 0, fmt.Errorf("%s: id '%s' not defined", v.Pos, v.Name)
+}
 
-		// Subtract (-)
-		//
-		case TMinus:
-			p.Next() // Skip '-'
-			if a, err = parseAdditiveExpression(p); err == nil {
-				f -= a
-			}
+// Eval implements Expr.Eval().
+//
+func (h *History) Eval(c *Calc) (float64, error) {
+	if h.N < 1 || h.N > len(c.History) {
+		return 0, fmt.Errorf("%s: no result $%d", h.Pos, h.N)
+	}
+	return c.History[h.N-1], nil
+}
+
+// Eval implements Expr.Eval().
+//
+func (n *Negate) Eval(c *Calc) (float64, error) {
+	f, err := n.X.Eval(c)
+	return -f, err
+}
+
+// Eval implements Expr.Eval().
+//
+func (b *Binary) Eval(c *Calc) (float64, error) {
+	x, err := b.Left.Eval(c)
+	if err != nil {
+		return 0, err
+	}
+	y, err := b.Right.Eval(c)
+	if err != nil {
+		return 0, err
+	}
+	switch b.Op.Type() {
+	case TPlus:
+		return x + y, nil
+	case TMinus:
+		return x - y, nil
+	case TMultiply:
+		return x * y, nil
+	case TDivide:
+		if y == 0 {
+			return 0, fmt.Errorf("%s: division by zero", token.Start(b.Op))
 		}
+		return x / y, nil
+	default:
+		return math.Pow(x, y), nil
 	}
+}
 
-	return
+// Eval implements Expr.Eval().
+//
+func (f *Call) Eval(c *Calc) (float64, error) {
+	fn, ok := functions[f.Name]
+	if !ok {
+		return 0, fmt.Errorf("%s: function '%s' not defined", f.Pos, f.Name)
+	}
+	x, err := f.Arg.Eval(c)
+	if err != nil {
+		return 0, err
+	}
+	return fn(x), nil
 }
 
-// parseMultiplicitiveExpression parses [ expression ( ( '*' | '/' ) expression )? ].
+// ---------------------------------------------------------------------------------------------------------------------
+// Parser
+// ---------------------------------------------------------------------------------------------------------------------
+
+// errSyntax is returned when a token is not matched; The details are recorded by the parser (see Parser.Furthest).
 //
-func parseMultiplicitiveExpression(p *parser.Parser) (f float64, err error) {
+var errSyntax = errors.New("syntax error")
 
-	var m float64
-	if f, err = parseOperand(p); err == nil && p.CanPeek(1) {
+// parse tries to parse a statement from the lexed tokens.
+// Syntax errors are reported via the furthest failure (see Parser.EmitFurthest).
+//
+func parse(p *parser.Parser) parser.Fn {
+	s := &Statement{}
 
-		switch p.PeekType(1) {
+	// Assignment
+	//
+	if p.Match(TId, TEquals) {
+		s.Var = p.Next().Value()
+		p.Next() // Skip '='
+	}
 
-		// Multiply (*)
-		//
-		case TMultiply:
-			p.Next() // Skip '*'
-			if m, err = parseMultiplicitiveExpression(p); err == nil {
-				f *= m
-			}
+	var err error
+	if s.Expr, err = parseGeneralExpression(p); err == errSyntax {
+		p.EmitFurthest()
+		return nil
+	} else if err != nil {
+		p.EmitError(err.Error())
+		return nil
+	}
 
-		// Divide (/)
-		//
-		case TDivide:
-			p.Next() // Skip '/'
-			if m, err = parseMultiplicitiveExpression(p); err == nil {
-				f /= m
-			}
-		}
+	// Should be at end of input
+	//
+	if p.CanPeek(1) {
+		p.Expect()
+		p.EmitFurthest()
+		return nil
 	}
+	p.Emit(s)
+	return nil // One pass
+}
 
-	return
+// operators defines the precedence of the binary operators.
+// All operators are left-associative, i.e. 8 - 4 - 2 == (8 - 4) - 2, except for '^', which is right-associative,
+// i.e. 2 ^ 3 ^ 2 == 2 ^ (3 ^ 2)
+//
+var operators = []parser.Operator{
+	{Type: TPlus, Prec: 1},
+	{Type: TMinus, Prec: 1},
+	{Type: TMultiply, Prec: 2},
+	{Type: TDivide, Prec: 2},
+	{Type: TPower, Prec: 3, Right: true},
 }
 
-// parseOperand parses [ id | number | '(' expression ')' ].
+// powerOperators are the operators binding tighter than unary '-', i.e. -2 ^ 2 == -(2 ^ 2)
 //
-func parseOperand(p *parser.Parser) (f float64, err error) {
+var powerOperators = operators[4:]
 
-	// EOF
-	//
-	if !p.CanPeek(1) {
-		return 0, errors.New("Unexpected EOF - Expecting operand")
+// parseGeneralExpression parses [ unary_exp ( operator unary_exp )* ].
+// The operator chain is folded according to the precedence of each operator.
+//
+func parseGeneralExpression(p *parser.Parser) (Expr, error) {
+	v, err := p.ParseBinary(parseOperandValue, operators, build)
+	if err != nil {
+		return nil, err
 	}
+	return v.(Expr), nil
+}
 
-	m := p.Marker()
+// parseOperandValue parses an operand, for use with ParseBinary.
+//
+func parseOperandValue(p *parser.Parser) (interface{}, error) {
+	x, err := parseOperand(p)
+	return x, err
+}
 
-	switch p.PeekType(1) {
+// build builds a binary operator expression.
+//
+func build(op token.Token, left interface{}, right interface{}) (interface{}, error) {
+	return &Binary{Op: op, Left: left.(Expr), Right: right.(Expr)}, nil
+}
 
-	// ID
+// parseOperand parses [ '-' unary_exp | id | id '(' expression ')' | number | history | '(' expression ')' ].
+//
+func parseOperand(p *parser.Parser) (Expr, error) {
+	tok, ok := p.Expect(TMinus, TId, TNumber, THistory, TOpenParen)
+	if !ok {
+		return nil, errSyntax
+	}
+
+	switch tok.Type() {
+
+	// '-' unary_exp
 	//
-	case TId:
-		var id = p.Next().Value()
-		var ok bool
-		if f, ok = vars[id]; !ok {
-			err = fmt.Errorf("id '%s' not defined", id)
+	case TMinus:
+		x, err := p.ParseBinary(parseOperandValue, powerOperators, build)
+		if err != nil {
+			return nil, err
 		}
+		return &Negate{X: x.(Expr)}, nil
 
-	// Number
+	// ID, or function call
 	//
-	case TNumber:
-		n := p.Next().Value()
-		if f, err = strconv.ParseFloat(n, 64); err != nil {
-			fmt.Printf("Error parsing number '%s': %s", n, err.Error())
+	case TId:
+		if _, ok = p.Expect(TOpenParen); !ok {
+			return &Var{Pos: token.Start(tok), Name: tok.Value()}, nil
+		}
+		x, err := parseGeneralExpression(p)
+		if err != nil {
+			return nil, err
 		}
+		if _, ok = p.Expect(TCloseParen); !ok {
+			return nil, errSyntax
+		}
+		return &Call{Pos: token.Start(tok), Name: tok.Value(), Arg: x}, nil
 
-	// '(' Expresson ')'
+	// Number
 	//
-	case TOpenParen:
-		p.Next() // Skip '('
-		if f, err = parseGeneralExpression(p); err == nil {
-			if p.CanPeek(1) && p.PeekType(1) == TCloseParen {
-				p.Next() // Skip ')'
-			} else {
-				err = errors.New("Unbalanced Paren")
-			}
-		}
+	case TNumber:
+		f, err := strconv.ParseFloat(tok.Value(), 64)
+		return &Number{Value: f}, err
 
-	// Unknown
+	// History
 	//
-	default:
-		err = errors.New("Expecting operand")
+	case THistory:
+		n, err := strconv.Atoi(tok.Value()[1:])
+		return &History{Pos: token.Start(tok), N: n}, err
 	}
 
+	// '(' Expresson ')'
+	//
+	x, err := parseGeneralExpression(p)
 	if err != nil {
-		m.Apply()
+		return nil, err
 	}
-
-	return
+	if _, ok = p.Expect(TCloseParen); !ok {
+		return nil, errSyntax
+	}
+	return x, nil
 }
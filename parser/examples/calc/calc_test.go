@@ -0,0 +1,99 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestEval
+//
+func TestEval(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"42", 42},
+		{"1 + 2 * 3 - 4 / 5", 6.2},
+		{"8 - 4 - 2", 2},
+		{"2 ^ 3 ^ 2", 512},
+		{"-2 ^ 2", -4},
+		{"2 ^ -1", 0.5},
+		{"--3 - -3", 6},
+		{"-(1 + 2) * 2", -6},
+		{"sqrt(16) + abs(-1) + sin(0) + cos(0)", 6},
+		{"exp(ln(2))", 2},
+		{"(1 +\n 2)", 3},
+	}
+	for _, test := range tests {
+		result, err := NewCalc().Eval(test.input)
+		if err != nil {
+			t.Errorf("Eval(%q) unexpected error: %v", test.input, err)
+		} else if result.Value != test.expected {
+			t.Errorf("Eval(%q) expecting %v, received %v", test.input, test.expected, result.Value)
+		}
+	}
+}
+
+// TestHistory confirms results and variables are shared across lines.
+//
+func TestHistory(t *testing.T) {
+	c := NewCalc()
+	lines := []struct {
+		input    string
+		expected string
+	}{
+		{"1 + 2", "$1 = 3"},
+		{"x = $1 * 2", "x = 6"},
+		{"x + $1", "$2 = 9"},
+		{"x = x + 1", "x = 7"},
+		{"$2 - x", "$3 = 2"},
+	}
+	for _, line := range lines {
+		result, err := c.Eval(line.input)
+		if err != nil {
+			t.Fatalf("Eval(%q) unexpected error: %v", line.input, err)
+		}
+		if result.String() != line.expected {
+			t.Errorf("Eval(%q) expecting '%s', received '%s'", line.input, line.expected, result)
+		}
+	}
+}
+
+// TestEvalError
+//
+func TestEvalError(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 2", "1:3: unexpected number '2'"},
+		{"1 + )", "1:5: expected id or number or history or '-' or '(', found ')'"},
+		{"sqrt(1 2)", "1:8: expected ')', found number '2'"},
+		{"1 # 2", "1:3: unknown character"},
+		{"$x", "1:1: expecting history number after '$'"},
+		{"y + 1", "1:1: id 'y' not defined"},
+		{"foo(1)", "1:1: function 'foo' not defined"},
+		{"$1", "1:1: no result $1"},
+		{"1 / (2 - 2)", "1:3: division by zero"},
+	}
+	for _, test := range tests {
+		_, err := NewCalc().Eval(test.input)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("Eval(%q) expecting error '%s', received '%v'", test.input, test.expected, err)
+		} else if token.IsIncomplete(err) {
+			t.Errorf("Eval(%q) expecting complete error, received incomplete", test.input)
+		}
+	}
+}
+
+// TestIncomplete confirms input ending mid-expression is incomplete, allowing it to be continued.
+//
+func TestIncomplete(t *testing.T) {
+	tests := []string{"1 +", "(1", "x =", "sqrt(2", "-"}
+	for _, input := range tests {
+		if _, err := NewCalc().Eval(input + "\n"); !token.IsIncomplete(err) {
+			t.Errorf("Eval(%q) expecting incomplete error, received %v", input, err)
+		}
+	}
+}
@@ -0,0 +1,882 @@
+package main
+
+//
+//	Usage: dot [-s] < input.dot
+//
+//	The graph is read from STDIN, parsed, and re-printed from its AST, reproducing the input exactly, including its
+//	whitespace and comments.
+//	With -s, the statements are listed instead, along with their positions and the comments preceding them:
+//
+//	$ printf 'digraph {\n  /* entry */ a -> b [color=red]\n  b; // done\n}\n' | dot -s
+//	2:15: a -> b [ color = red ]
+//	      /* entry */
+//	3:3: b ;
+//
+//	Graphs are matched against the following pattern (a subset of the DOT language):
+//
+//	graph     : 'strict'? ( 'graph' | 'digraph' ) ID? '{' stmt* '}'
+//	stmt      : ( node_stmt | edge_stmt | attr_stmt | ID '=' ID | subgraph ) ';'?
+//	node_stmt : ID attr_list*
+//	edge_stmt : ( ID | subgraph ) ( edge_op ( ID | subgraph ) )+ attr_list*
+//	attr_stmt : ( 'graph' | 'node' | 'edge' ) attr_list+
+//	attr_list : '[' ( ID '=' ID ( ';' | ',' )? )* ']'
+//	subgraph  : ( 'subgraph' ID? )? '{' stmt* '}'
+//	edge_op   : '->' in a digraph, '--' in a graph
+//
+//	IDs are identifiers, numerals, double-quoted strings or <html> strings; Keywords are case-insensitive.
+//	Comments are /* block */, // line or # line comments.
+//
+//	Whitespace and comments (trivia) are emitted by the lexer on token.ChannelHidden, and attached to the following
+//	token by the attachTrivia Nexter, sitting between the lexer and the parser; The parser never sees the trivia, but
+//	each token in the AST carries the trivia preceding it, so the AST can reproduce the input (see Print).
+//
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// We define our lexer tokens starting from the pre-defined START token
+//
+const (
+	TID       token.Type = lexer.TStart + iota // Cooked value is the unquoted text
+	TStrict                                    // 'strict'
+	TGraph                                     // 'graph'
+	TDigraph                                   // 'digraph'
+	TSubgraph                                  // 'subgraph'
+	TNode                                      // 'node'
+	TEdge                                      // 'edge'
+	TLBrace                                    // '{'
+	TRBrace                                    // '}'
+	TLBracket                                  // '['
+	TRBracket                                  // ']'
+	TEquals                                    // '='
+	TSemi                                      // ';'
+	TComma                                     // ','
+	TArrow                                     // '->'
+	TDashDash                                  // '--'
+	TSpace                                     // Trivia
+	TComment                                   // Trivia
+)
+
+// Token names, for error messages
+//
+var tokenNames = map[token.Type]string{
+	TID:       "ID",
+	TStrict:   "'strict'",
+	TGraph:    "'graph'",
+	TDigraph:  "'digraph'",
+	TSubgraph: "'subgraph'",
+	TNode:     "'node'",
+	TEdge:     "'edge'",
+	TLBrace:   "'{'",
+	TRBrace:   "'}'",
+	TLBracket: "'['",
+	TRBracket: "']'",
+	TEquals:   "'='",
+	TSemi:     "';'",
+	TComma:    "','",
+	TArrow:    "'->'",
+	TDashDash: "'--'",
+}
+
+// keywords map the (lower-cased) keywords to their types.
+//
+var keywords = map[string]token.Type{
+	"strict":   TStrict,
+	"graph":    TGraph,
+	"digraph":  TDigraph,
+	"subgraph": TSubgraph,
+	"node":     TNode,
+	"edge":     TEdge,
+}
+
+// symbols map the single-rune symbols to their types.
+//
+var symbols = map[rune]token.Type{
+	'{': TLBrace,
+	'}': TRBrace,
+	'[': TLBracket,
+	']': TRBracket,
+	'=': TEquals,
+	';': TSemi,
+	',': TComma,
+}
+
+// main
+//
+func main() {
+	list := flag.Bool("s", false, "list the statements")
+	flag.Parse()
+	input, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		panic(err)
+	}
+	g, err := Parse(string(input))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	if !*list {
+		if err = Print(w, g); err != nil {
+			panic(err)
+		}
+		return
+	}
+	var walkStmts func(stmts []Stmt)
+	walkStmts = func(stmts []Stmt) {
+		for _, s := range stmts {
+			first := First(s)
+			fmt.Fprintf(w, "%s: %s\n", token.Start(first), Compact(s))
+			for _, c := range first.Comments() {
+				fmt.Fprintf(w, "%s%s\n", strings.Repeat(" ", len(token.Start(first).String())+2), c)
+			}
+			if sub, ok := s.(*Subgraph); ok {
+				walkStmts(sub.Stmts)
+			}
+		}
+	}
+	walkStmts(g.Stmts)
+}
+
+// Parse parses the graph.
+//
+func Parse(input string) (*Graph, error) {
+	tokens := &attachTrivia{tokens: lexer.LexString(input, lexDot)}
+	ast, err := parser.Parse(tokens, parseGraph, parser.WithLexErrors()).Next()
+	if f, ok := err.(*parser.Failure); ok {
+		return nil, describe(f)
+	}
+	// The parser is never called if the input has no tokens, other than trivia
+	//
+	if err == io.EOF {
+		return nil, errors.New("no graph found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	g := ast.(*Graph)
+	g.Trailing = tokens.leading
+	return g, nil
+}
+
+// describe rewords a parser failure with token names, i.e. "1:5: expected '{', found ']'".
+//
+func describe(f *parser.Failure) error {
+	found := "end of input"
+	if f.Found != nil {
+		found = tokenNames[f.Found.Type()]
+		if f.Found.Type() == TID {
+			found += fmt.Sprintf(" '%s'", f.Found.Value())
+		}
+	}
+	if f.Expected.Len() == 0 {
+		return fmt.Errorf("%s: unexpected %s", f.Pos, found)
+	}
+	var expected []string
+	for _, t := range f.Expected.Types() {
+		expected = append(expected, tokenNames[t])
+	}
+	return fmt.Errorf("%s: expected %s, found %s", f.Pos, strings.Join(expected, " or "), found)
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Trivia
+// ---------------------------------------------------------------------------------------------------------------------
+
+// Token is a token, along with the trivia (whitespace and comments) preceding it.
+//
+type Token struct {
+	token.Token
+	Leading []token.Token
+}
+
+// Cooked implements token.Cooker, forwarding to the wrapped token.
+//
+func (t *Token) Cooked() interface{} {
+	return token.Cooked(t.Token)
+}
+
+// Comments returns the text of the comments preceding the token.
+//
+func (t *Token) Comments() []string {
+	var comments []string
+	for _, tok := range t.Leading {
+		if tok.Type() == TComment {
+			comments = append(comments, tok.Value())
+		}
+	}
+	return comments
+}
+
+// attachTrivia wraps a Nexter, attaching the tokens on token.ChannelHidden to the following token, returning each
+// token as a *Token.
+// The trivia following the last token is left in leading once the end of the input is reached.
+//
+type attachTrivia struct {
+	tokens  token.Nexter
+	leading []token.Token
+}
+
+// Next implements token.Nexter.Next().
+//
+func (n *attachTrivia) Next() (token.Token, error) {
+	for {
+		tok, err := n.tokens.Next()
+		if err != nil {
+			return nil, err
+		}
+		if token.OnChannel(tok, token.ChannelHidden) {
+			n.leading = append(n.leading, tok)
+			continue
+		}
+		t := &Token{Token: tok, Leading: n.leading}
+		n.leading = nil
+		return t, nil
+	}
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// AST
+// ---------------------------------------------------------------------------------------------------------------------
+
+// Node is implemented by all AST nodes.
+//
+type Node interface {
+
+	// walk calls fn for each token of the node, in order.
+	//
+	walk(fn func(*Token))
+}
+
+// Stmt is implemented by statements: *NodeStmt, *EdgeStmt, *AttrStmt, *Assign and *Subgraph.
+//
+type Stmt interface {
+	Node
+	setSemi(semi *Token)
+}
+
+// Operand is implemented by the operands of an edge statement: *NodeRef and *Subgraph.
+//
+type Operand interface {
+	Node
+}
+
+// Graph is the root of the AST.
+//
+type Graph struct {
+	Strict   *Token // nil if not strict
+	Kind     *Token // 'graph' or 'digraph'
+	ID       *Token // nil if anonymous
+	Open     *Token
+	Stmts    []Stmt
+	Close    *Token
+	Trailing []token.Token // Trivia following the graph
+}
+
+// NodeStmt declares a node, i.e. "a [shape=box]".
+//
+type NodeStmt struct {
+	ID    *Token
+	Attrs []*AttrList
+	Semi  *Token
+}
+
+// EdgeStmt declares edges, i.e. "a -> b -> c [color=red]".
+//
+type EdgeStmt struct {
+	Operands []Operand
+	Ops      []*Token // Ops[i] is between Operands[i] and Operands[i+1]
+	Attrs    []*AttrList
+	Semi     *Token
+}
+
+// AttrStmt sets default attributes, i.e. "node [shape=box]".
+//
+type AttrStmt struct {
+	Kind  *Token // 'graph', 'node' or 'edge'
+	Attrs []*AttrList
+	Semi  *Token
+}
+
+// Assign sets a graph attribute, i.e. "rankdir = LR".
+//
+type Assign struct {
+	Name  *Token
+	Eq    *Token
+	Value *Token
+	Semi  *Token
+}
+
+// Subgraph is a subgraph, i.e. "subgraph cluster_0 { a b }" or "{ a b }".
+//
+type Subgraph struct {
+	Keyword *Token // nil if anonymous
+	ID      *Token // nil if anonymous
+	Open    *Token
+	Stmts   []Stmt
+	Close   *Token
+	Semi    *Token
+}
+
+// NodeRef is a node within an edge statement.
+//
+type NodeRef struct {
+	ID *Token
+}
+
+// AttrList is a bracketed list of attributes, i.e. "[color=red, shape=box]".
+//
+type AttrList struct {
+	Open  *Token
+	Attrs []*Attr
+	Close *Token
+}
+
+// Attr is an attribute, i.e. "color=red".
+//
+type Attr struct {
+	Name  *Token
+	Eq    *Token
+	Value *Token
+	Sep   *Token // ';' or ',', nil if none
+}
+
+// ID returns the unquoted text of an ID token, i.e. "a b" for "\"a b\"".
+//
+func ID(t *Token) string {
+	return token.Cooked(t).(string)
+}
+
+// visit calls fn with each non-nil token, in order.
+//
+func visit(fn func(*Token), tokens ...*Token) {
+	for _, t := range tokens {
+		if t != nil {
+			fn(t)
+		}
+	}
+}
+
+// walkAll calls walk on each node, in order.
+//
+func walkAll(fn func(*Token), stmts []Stmt) {
+	for _, s := range stmts {
+		s.walk(fn)
+	}
+}
+
+// walkAttrs calls walk on each attribute list, in order.
+//
+func walkAttrs(fn func(*Token), lists []*AttrList) {
+	for _, l := range lists {
+		l.walk(fn)
+	}
+}
+
+// walk implements Node.walk().
+//
+func (g *Graph) walk(fn func(*Token)) {
+	visit(fn, g.Strict, g.Kind, g.ID, g.Open)
+	walkAll(fn, g.Stmts)
+	visit(fn, g.Close)
+}
+
+// walk implements Node.walk().
+//
+func (s *NodeStmt) walk(fn func(*Token)) {
+	visit(fn, s.ID)
+	walkAttrs(fn, s.Attrs)
+	visit(fn, s.Semi)
+}
+
+// walk implements Node.walk().
+//
+func (s *EdgeStmt) walk(fn func(*Token)) {
+	for i, o := range s.Operands {
+		if i > 0 {
+			visit(fn, s.Ops[i-1])
+		}
+		o.walk(fn)
+	}
+	walkAttrs(fn, s.Attrs)
+	visit(fn, s.Semi)
+}
+
+// walk implements Node.walk().
+//
+func (s *AttrStmt) walk(fn func(*Token)) {
+	visit(fn, s.Kind)
+	walkAttrs(fn, s.Attrs)
+	visit(fn, s.Semi)
+}
+
+// walk implements Node.walk().
+//
+func (s *Assign) walk(fn func(*Token)) {
+	visit(fn, s.Name, s.Eq, s.Value, s.Semi)
+}
+
+// walk implements Node.walk().
+//
+func (s *Subgraph) walk(fn func(*Token)) {
+	visit(fn, s.Keyword, s.ID, s.Open)
+	walkAll(fn, s.Stmts)
+	visit(fn, s.Close, s.Semi)
+}
+
+// walk implements Node.walk().
+//
+func (n *NodeRef) walk(fn func(*Token)) {
+	visit(fn, n.ID)
+}
+
+// walk implements Node.walk().
+//
+func (l *AttrList) walk(fn func(*Token)) {
+	visit(fn, l.Open)
+	for _, a := range l.Attrs {
+		visit(fn, a.Name, a.Eq, a.Value, a.Sep)
+	}
+	visit(fn, l.Close)
+}
+
+// setSemi implements Stmt.setSemi().
+//
+func (s *NodeStmt) setSemi(semi *Token) {
+	s.Semi = semi
+}
+
+// setSemi implements Stmt.setSemi().
+//
+func (s *EdgeStmt) setSemi(semi *Token) {
+	s.Semi = semi
+}
+
+// setSemi implements Stmt.setSemi().
+//
+func (s *AttrStmt) setSemi(semi *Token) {
+	s.Semi = semi
+}
+
+// setSemi implements Stmt.setSemi().
+//
+func (s *Assign) setSemi(semi *Token) {
+	s.Semi = semi
+}
+
+// setSemi implements Stmt.setSemi().
+//
+func (s *Subgraph) setSemi(semi *Token) {
+	s.Semi = semi
+}
+
+// Print writes the graph, reconstructed from the tokens (and trivia) of its AST, to w.
+// Unless the AST has been modified, the output is identical to the input.
+//
+func Print(w io.Writer, g *Graph) error {
+	var err error
+	write := func(tok token.Token) {
+		if err == nil {
+			_, err = io.WriteString(w, tok.Value())
+		}
+	}
+	g.walk(func(t *Token) {
+		for _, trivia := range t.Leading {
+			write(trivia)
+		}
+		write(t.Token)
+	})
+	for _, trivia := range g.Trailing {
+		write(trivia)
+	}
+	return err
+}
+
+// Compact returns the tokens of the node separated by single spaces, without trivia, i.e. "a -> b [ color = red ]".
+//
+func Compact(n Node) string {
+	var values []string
+	n.walk(func(t *Token) {
+		values = append(values, t.Value())
+	})
+	return strings.Join(values, " ")
+}
+
+// First returns the first token of the node.
+//
+func First(n Node) *Token {
+	var first *Token
+	n.walk(func(t *Token) {
+		if first == nil {
+			first = t
+		}
+	})
+	return first
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Lexer
+// ---------------------------------------------------------------------------------------------------------------------
+
+// lexDot lexes DOT tokens, emitting trivia on token.ChannelHidden.
+//
+func lexDot(l *lexer.Lexer) lexer.Fn {
+	r := l.Peek(1)
+	r2 := rune(0)
+	if l.CanPeek(2) {
+		r2 = l.Peek(2)
+	}
+	switch {
+	case isSpace(r):
+		for l.CanPeek(1) && isSpace(l.Peek(1)) {
+			l.Next()
+		}
+		l.EmitTokenOn(TSpace, token.ChannelHidden)
+	case r == '#' || r == '/' && r2 == '/':
+		for l.CanPeek(1) && l.Peek(1) != '\n' {
+			l.Next()
+		}
+		l.EmitTokenOn(TComment, token.ChannelHidden)
+	case r == '/' && r2 == '*':
+		return lexBlockComment
+	case symbols[r] != 0:
+		l.Next()
+		l.EmitToken(symbols[r])
+	case r == '-' && r2 == '>':
+		l.Next()
+		l.Next()
+		l.EmitToken(TArrow)
+	case r == '-' && r2 == '-':
+		l.Next()
+		l.Next()
+		l.EmitToken(TDashDash)
+	case r == '"':
+		return lexString
+	case r == '<':
+		return lexHTML
+	case r == '-' || r == '.' || isDigit(r):
+		return lexNumeral
+	case isIdentStart(r):
+		for l.CanPeek(1) && (isIdentStart(l.Peek(1)) || isDigit(l.Peek(1))) {
+			l.Next()
+		}
+		text := l.PeekToken()
+		if t, ok := keywords[strings.ToLower(text)]; ok {
+			l.EmitCooked(t, text)
+		} else {
+			l.EmitCooked(TID, text)
+		}
+	default:
+		l.Next()
+		l.EmitErrorToken("unexpected character")
+		return nil
+	}
+	return lexDot
+}
+
+// lexBlockComment lexes a block comment, i.e. "/* comment */".
+//
+func lexBlockComment(l *lexer.Lexer) lexer.Fn {
+	l.Next() // '/'
+	l.Next() // '*'
+	for l.CanPeek(2) && !(l.Peek(1) == '*' && l.Peek(2) == '/') {
+		l.Next()
+	}
+	if !l.CanPeek(2) {
+		l.EmitErrorToken("unterminated comment")
+		return nil
+	}
+	l.Next()
+	l.Next()
+	l.EmitTokenOn(TComment, token.ChannelHidden)
+	return lexDot
+}
+
+// lexString lexes a double-quoted string, i.e. "a \"b\"".
+// As with Graphviz, only \" is unescaped, while an escaped newline continues the string onto the next line.
+//
+func lexString(l *lexer.Lexer) lexer.Fn {
+	l.Next() // Opening quote
+	for l.CanPeek(1) && l.Peek(1) != '"' {
+		if l.Next() == '\\' && l.CanPeek(1) {
+			l.Next()
+		}
+	}
+	if !l.CanPeek(1) {
+		l.EmitErrorToken("unterminated string")
+		return nil
+	}
+	l.Next() // Closing quote
+	text := l.PeekToken()
+	l.EmitCooked(TID, unescape.Replace(text[1:len(text)-1]))
+	return lexDot
+}
+
+// unescape replaces the escapes within strings
+//
+var unescape = strings.NewReplacer(`\"`, `"`, "\\\n", "", "\\\r\n", "")
+
+// lexHTML lexes an HTML string, i.e. "<<b>bold</b>>", which contains balanced angle brackets.
+//
+func lexHTML(l *lexer.Lexer) lexer.Fn {
+	l.Next() // '<'
+	for depth := 1; depth > 0; {
+		if !l.CanPeek(1) {
+			l.EmitErrorToken("unterminated HTML string")
+			return nil
+		}
+		switch l.Next() {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		}
+	}
+	text := l.PeekToken()
+	l.EmitCooked(TID, text[1:len(text)-1])
+	return lexDot
+}
+
+// lexNumeral lexes a numeral, i.e. "-1.5" or ".5".
+//
+func lexNumeral(l *lexer.Lexer) lexer.Fn {
+	if l.Peek(1) == '-' {
+		l.Next()
+	}
+	digits := 0
+	for l.CanPeek(1) && isDigit(l.Peek(1)) {
+		l.Next()
+		digits++
+	}
+	if l.CanPeek(1) && l.Peek(1) == '.' {
+		l.Next()
+		for l.CanPeek(1) && isDigit(l.Peek(1)) {
+			l.Next()
+			digits++
+		}
+	}
+	if digits == 0 {
+		l.EmitErrorToken("invalid numeral")
+		return nil
+	}
+	l.EmitCooked(TID, l.PeekToken())
+	return lexDot
+}
+
+// isSpace
+//
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+// isDigit
+//
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isIdentStart
+//
+func isIdentStart(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || r >= 0x80
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Parser
+// ---------------------------------------------------------------------------------------------------------------------
+
+// parseGraph emits a *Graph.
+// The edge operator of the graph, TArrow or TDashDash, is kept in the parser context.
+//
+func parseGraph(p *parser.Parser) parser.Fn {
+	g, ok := graph(p)
+	if !ok {
+		p.EmitFurthest()
+		return nil
+	}
+	p.Emit(g)
+	return nil
+}
+
+// graph parses a graph, returning false if invalid (see Parser.Furthest).
+//
+func graph(p *parser.Parser) (*Graph, bool) {
+	g := &Graph{}
+	g.Strict = expect(p, TStrict)
+	if g.Kind = expect(p, TGraph, TDigraph); g.Kind == nil {
+		return nil, false
+	}
+	p.SetContext(TDashDash)
+	if g.Kind.Type() == TDigraph {
+		p.SetContext(TArrow)
+	}
+	g.ID = expect(p, TID)
+	if g.Open = expect(p, TLBrace); g.Open == nil {
+		return nil, false
+	}
+	var ok bool
+	if g.Stmts, g.Close, ok = stmts(p); !ok {
+		return nil, false
+	}
+	// Should be at end of input
+	//
+	if p.CanPeek(1) {
+		p.Expect()
+		return nil, false
+	}
+	return g, true
+}
+
+// stmts parses [ ( stmt ';'? )* '}' ].
+//
+func stmts(p *parser.Parser) ([]Stmt, *Token, bool) {
+	var list []Stmt
+	for {
+		if close := expect(p, TRBrace); close != nil {
+			return list, close, true
+		}
+		s, ok := stmt(p)
+		if !ok {
+			return nil, nil, false
+		}
+		s.setSemi(expect(p, TSemi))
+		list = append(list, s)
+	}
+}
+
+// stmt parses a statement, without its optional ';'.
+//
+func stmt(p *parser.Parser) (Stmt, bool) {
+	first := expect(p, TID, TGraph, TNode, TEdge, TSubgraph, TLBrace)
+	if first == nil {
+		return nil, false
+	}
+	switch first.Type() {
+	case TGraph, TNode, TEdge:
+		attrs, ok := attrLists(p)
+		if ok && len(attrs) == 0 {
+			p.Expect(TLBracket)
+			ok = false
+		}
+		return &AttrStmt{Kind: first, Attrs: attrs}, ok
+	case TID:
+		if eq := expect(p, TEquals); eq != nil {
+			value := expect(p, TID)
+			return &Assign{Name: first, Eq: eq, Value: value}, value != nil
+		}
+		return edgeOrNode(p, &NodeRef{ID: first})
+	}
+	sub, ok := subgraph(p, first)
+	if !ok {
+		return nil, false
+	}
+	return edgeOrNode(p, sub)
+}
+
+// edgeOrNode parses the remainder of an edge statement, following its first operand, if an edge operator follows.
+// Otherwise returns the operand as a node statement, or subgraph.
+//
+func edgeOrNode(p *parser.Parser, first Operand) (Stmt, bool) {
+	op := p.Context().(token.Type)
+	if !p.Match(op) {
+		if sub, ok := first.(*Subgraph); ok {
+			return sub, true
+		}
+		// Records the edge operator as expected, in case no attribute list follows
+		//
+		p.Expect(op)
+		attrs, ok := attrLists(p)
+		return &NodeStmt{ID: first.(*NodeRef).ID, Attrs: attrs}, ok
+	}
+	edge := &EdgeStmt{Operands: []Operand{first}}
+	for tok := expect(p, op); tok != nil; tok = expect(p, op) {
+		edge.Ops = append(edge.Ops, tok)
+		next, ok := operand(p)
+		if !ok {
+			return nil, false
+		}
+		edge.Operands = append(edge.Operands, next)
+	}
+	var ok bool
+	edge.Attrs, ok = attrLists(p)
+	return edge, ok
+}
+
+// operand parses [ ID | subgraph ].
+//
+func operand(p *parser.Parser) (Operand, bool) {
+	first := expect(p, TID, TSubgraph, TLBrace)
+	if first == nil {
+		return nil, false
+	}
+	if first.Type() == TID {
+		return &NodeRef{ID: first}, true
+	}
+	return subgraph(p, first)
+}
+
+// subgraph parses the remainder of a subgraph, following its first token ('subgraph' or '{').
+//
+func subgraph(p *parser.Parser, first *Token) (*Subgraph, bool) {
+	p.EnterRule("subgraph")
+	defer p.ExitRule()
+	sub := &Subgraph{Open: first}
+	if first.Type() == TSubgraph {
+		sub.Keyword, sub.Open = first, nil
+		sub.ID = expect(p, TID)
+		if sub.Open = expect(p, TLBrace); sub.Open == nil {
+			return nil, false
+		}
+	}
+	var ok bool
+	sub.Stmts, sub.Close, ok = stmts(p)
+	return sub, ok
+}
+
+// attrLists parses [ attr_list* ].
+//
+func attrLists(p *parser.Parser) ([]*AttrList, bool) {
+	var lists []*AttrList
+	for open := expect(p, TLBracket); open != nil; open = expect(p, TLBracket) {
+		list := &AttrList{Open: open}
+		for list.Close = expect(p, TRBracket); list.Close == nil; list.Close = expect(p, TRBracket) {
+			a := &Attr{}
+			if a.Name = expect(p, TID); a.Name == nil {
+				return nil, false
+			}
+			if a.Eq = expect(p, TEquals); a.Eq == nil {
+				return nil, false
+			}
+			if a.Value = expect(p, TID); a.Value == nil {
+				return nil, false
+			}
+			a.Sep = expect(p, TSemi, TComma)
+			list.Attrs = append(list.Attrs, a)
+		}
+		lists = append(lists, list)
+	}
+	return lists, true
+}
+
+// expect calls Parser.Expect, returning the matched token as a *Token, or nil if not matched.
+//
+func expect(p *parser.Parser, types ...token.Type) *Token {
+	tok, ok := p.Expect(types...)
+	if !ok {
+		return nil
+	}
+	return tok.(*Token)
+}
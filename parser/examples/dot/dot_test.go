@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestRoundTrip confirms that printing the AST reproduces the input exactly.
+//
+func TestRoundTrip(t *testing.T) {
+	tests := []string{
+		`graph{}`,
+		"digraph G {\n  a -> b -> c;\n  b -> d [color=red]\n}\n",
+		"  /* leading */ strict DiGraph \"my graph\" {\r\n\tnode [shape=box; label=\"a \\\"b\\\"\"]\r\n}  // trailing\r\n",
+		"graph {\n# preprocessor\nrankdir=LR\nsubgraph cluster_0 { x -- {y z} } -- w\n-1.5 -- .5 -- <<b>html</b>>\nété -- _x1\n}",
+		"digraph {\n  a [\n    color = red ,\n    shape = box\n  ] [ style = dashed ] ;\n  edge [ ] ;\n}\n\n",
+	}
+	for _, input := range tests {
+		g, err := Parse(input)
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", input, err)
+			continue
+		}
+		b := &bytes.Buffer{}
+		if err = Print(b, g); err != nil {
+			t.Errorf("Print() unexpected error: %v", err)
+		}
+		if b.String() != input {
+			t.Errorf("Print(Parse(%q)) received %q", input, b.String())
+		}
+	}
+}
+
+// TestAST
+//
+func TestAST(t *testing.T) {
+	input := "digraph {\n  // The start\n  /* block */ \"a b\" -> {c d} [label=<<i>x</i>>];\n  rank = same\n}\n"
+	g, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	if len(g.Stmts) != 2 {
+		t.Fatalf("Parse() expecting 2 statements, received %d", len(g.Stmts))
+	}
+	edge, ok := g.Stmts[0].(*EdgeStmt)
+	if !ok {
+		t.Fatalf("Parse() expecting *EdgeStmt, received %T", g.Stmts[0])
+	}
+	if c := Compact(edge); c != `"a b" -> { c d } [ label = <<i>x</i>> ] ;` {
+		t.Errorf("Compact() received %q", c)
+	}
+	if comments := First(edge).Comments(); strings.Join(comments, "|") != "// The start|/* block */" {
+		t.Errorf("Comments() received %q", comments)
+	}
+	if id := ID(edge.Operands[0].(*NodeRef).ID); id != "a b" {
+		t.Errorf("ID() expecting 'a b', received %q", id)
+	}
+	if id := ID(edge.Attrs[0].Attrs[0].Value); id != "<i>x</i>" {
+		t.Errorf("ID() expecting '<i>x</i>', received %q", id)
+	}
+	if sub, ok := edge.Operands[1].(*Subgraph); !ok || len(sub.Stmts) != 2 || sub.Keyword != nil {
+		t.Errorf("Parse() expecting anonymous subgraph with 2 statements, received %#v", edge.Operands[1])
+	}
+	if a, ok := g.Stmts[1].(*Assign); !ok || ID(a.Name) != "rank" || ID(a.Value) != "same" || a.Semi != nil {
+		t.Errorf("Parse() expecting rank = same, received %#v", g.Stmts[1])
+	}
+	if len(g.Trailing) != 1 || g.Trailing[0].Value() != "\n" {
+		t.Errorf("Parse() expecting trailing newline, received %v", g.Trailing)
+	}
+}
+
+// TestModify confirms that edits to the AST are reflected by Print, with the trivia preserved.
+//
+func TestModify(t *testing.T) {
+	g, err := Parse("graph G {\n  a -- b // edge\n}\n")
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+	// Drop the graph ID, keeping the trivia of the '{'
+	//
+	g.ID = nil
+	b := &bytes.Buffer{}
+	_ = Print(b, g)
+	if b.String() != "graph {\n  a -- b // edge\n}\n" {
+		t.Errorf("Print() received %q", b.String())
+	}
+}
+
+// TestErrors
+//
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{``, "no graph found"},
+		{"  // just a comment\n", "no graph found"},
+		{`graph`, "1:6: expected ID or '{', found end of input"},
+		{`digraph { a -- b }`, "1:13: expected ID or 'graph' or 'subgraph' or 'node' or 'edge' or '{' or '}' or '[' or '=' or ';' or '->', found '--'"},
+		{`digraph { a [x=] }`, "1:16: expected ID, found ']'"},
+		{`digraph { node }`, "1:16: expected '[', found '}'"},
+		{`digraph { a -> }`, "1:16: expected ID or 'subgraph' or '{', found '}'"},
+		{`digraph { } x`, "1:13: unexpected ID 'x'"},
+		{`digraph { a /* x`, "1:13: unterminated comment"},
+		{`digraph { "a }`, "1:11: unterminated string"},
+		{`digraph { <a }`, "1:11: unterminated HTML string"},
+		{`digraph { - }`, "1:11: invalid numeral"},
+		{`digraph { @ }`, "1:11: unexpected character"},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.input)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("Parse(%q) expecting error '%s', received '%v'", test.input, test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,557 @@
+package main
+
+//
+//	Input is read from STDIN
+//
+//	The input is parsed as a single JSON value (RFC 8259), and printed as an AST, with the span of each node:
+//
+//	$ echo '{"a": [1, true]}' | json
+//	1:1-1:17 Object
+//	  1:2-1:16 Member "a"
+//	    1:7-1:16 Array
+//	      1:8-1:9 Number 1
+//	      1:11-1:15 Bool true
+//
+//	Errors are reported with their position:
+//
+//	$ echo '{"a" 1}' | json
+//	1:6: expected ':', found number 1
+//
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/escape"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// We define our lexer tokens starting from the pre-defined START token
+//
+const (
+	TObjectStart token.Type = lexer.TStart + iota
+	TObjectEnd
+	TArrayStart
+	TArrayEnd
+	TComma
+	TColon
+	TString // Cooked value is the decoded string
+	TNumber // Cooked value is the float64 value
+	TTrue
+	TFalse
+	TNull
+)
+
+// Single-character tokens
+//
+var singleTokens = map[rune]token.Type{
+	'{': TObjectStart,
+	'}': TObjectEnd,
+	'[': TArrayStart,
+	']': TArrayEnd,
+	',': TComma,
+	':': TColon,
+}
+
+// Literal tokens
+//
+var literalTokens = map[string]token.Type{
+	"true":  TTrue,
+	"false": TFalse,
+	"null":  TNull,
+}
+
+// Token names, for error messages
+//
+var tokenNames = map[token.Type]string{
+	TObjectStart: "'{'",
+	TObjectEnd:   "'}'",
+	TArrayStart:  "'['",
+	TArrayEnd:    "']'",
+	TComma:       "','",
+	TColon:       "':'",
+	TString:      "string",
+	TNumber:      "number",
+	TTrue:        "true",
+	TFalse:       "false",
+	TNull:        "null",
+}
+
+// Max nesting depth of arrays and objects
+//
+const maxDepth = 512
+
+// main
+//
+func main() {
+	input, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		panic(err)
+	}
+	value, err := Parse(string(input))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Print(Dump(value))
+}
+
+// Parse parses the input as a single JSON value.
+// Returns a *SyntaxError if the input is invalid.
+//
+func Parse(input string) (Value, error) {
+	tokens := lexer.LexString(input, lexJSON)
+	value, err := parser.Parse(tokens, parseJSON, parser.WithMaxDepth(maxDepth), parser.WithLexErrors()).Next()
+	if err == io.EOF {
+		// No tokens
+		//
+		return nil, &SyntaxError{Pos: token.Position{Line: 1, Column: 1}, Message: "expected value, found end of input"}
+	}
+	if err != nil {
+		return nil, newSyntaxError(err)
+	}
+	return value.(Value), nil
+}
+
+// SyntaxError describes invalid input.
+//
+type SyntaxError struct {
+	Pos     token.Position // Position of the error
+	Message string         // The error message, i.e. "expected ':', found ','"
+	Text    string         // The offending text, if any, i.e. `\q` for an unknown escape sequence
+}
+
+// Error implements error, i.e. "1:5: expected ':', found ','" or "1:3: unknown escape sequence `\q`".
+//
+func (e *SyntaxError) Error() string {
+	if e.Text != "" {
+		return fmt.Sprintf("%s: %s `%s`", e.Pos, e.Message, e.Text)
+	}
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// newSyntaxError converts a lexer or parser error into a SyntaxError.
+//
+func newSyntaxError(err error) *SyntaxError {
+	switch e := err.(type) {
+	case *lexer.Error:
+		return &SyntaxError{Pos: e.Pos, Message: e.Message, Text: e.Text}
+	case *parser.Failure:
+		found := "end of input"
+		if e.Found != nil {
+			found = tokenNames[e.Found.Type()]
+			if t := e.Found.Type(); t == TString || t == TNumber {
+				found += " " + e.Found.Value() // i.e. `number 42` or `string "abc"`
+			}
+		}
+		var expected []string
+		for _, t := range e.Expected.Types() {
+			expected = append(expected, tokenNames[t])
+		}
+		if len(expected) == 0 {
+			return &SyntaxError{Pos: e.Pos, Message: "unexpected " + found}
+		}
+		return &SyntaxError{Pos: e.Pos, Message: fmt.Sprintf("expected %s, found %s", describe(expected), found)}
+	case *parser.DepthError:
+		return &SyntaxError{Pos: e.Pos, Message: fmt.Sprintf("maximum nesting depth of %d exceeded", e.Limit)}
+	}
+	return &SyntaxError{Message: err.Error()}
+}
+
+// describe joins the names of the expected tokens, i.e. "'}' or ','".
+//
+func describe(names []string) string {
+	if len(names) > 2 {
+		return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+	}
+	return strings.Join(names, " or ")
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// AST
+// ---------------------------------------------------------------------------------------------------------------------
+
+// Value is implemented by all JSON values.
+//
+type Value interface {
+	parser.SpanSetter
+
+	// Span returns the span of the input covered by the value.
+	//
+	Span() token.Span
+}
+
+// node holds the span of a node.
+//
+type node struct {
+	span token.Span
+}
+
+// Span implements Value.Span().
+//
+func (n *node) Span() token.Span {
+	return n.span
+}
+
+// SetSpan implements parser.SpanSetter.SetSpan().
+//
+func (n *node) SetSpan(span token.Span) {
+	n.span = span
+}
+
+// Object is a JSON object.
+//
+type Object struct {
+	node
+	Members []*Member
+}
+
+// Member is a key/value pair within a JSON object.
+//
+type Member struct {
+	node
+	Key   *String
+	Value Value
+}
+
+// Array is a JSON array.
+//
+type Array struct {
+	node
+	Elements []Value
+}
+
+// String is a JSON string.
+//
+type String struct {
+	node
+	Value string // The decoded value
+}
+
+// Number is a JSON number.
+//
+type Number struct {
+	node
+	Value float64
+	Text  string // The number as written, i.e. "1e3"
+}
+
+// Bool is a JSON true or false.
+//
+type Bool struct {
+	node
+	Value bool
+}
+
+// Null is a JSON null.
+//
+type Null struct {
+	node
+}
+
+// Dump formats the AST, one node per line, with the span of each node.
+//
+func Dump(v Value) string {
+	b := &strings.Builder{}
+	dump(b, v, "")
+	return b.String()
+}
+
+// dump formats the AST rooted at v.
+//
+func dump(b *strings.Builder, v Value, indent string) {
+	fmt.Fprintf(b, "%s%s ", indent, v.Span())
+	switch n := v.(type) {
+	case *Object:
+		b.WriteString("Object\n")
+		for _, m := range n.Members {
+			fmt.Fprintf(b, "%s  %s Member %q\n", indent, m.Span(), m.Key.Value)
+			dump(b, m.Value, indent+"    ")
+		}
+	case *Array:
+		b.WriteString("Array\n")
+		for _, e := range n.Elements {
+			dump(b, e, indent+"  ")
+		}
+	case *String:
+		fmt.Fprintf(b, "String %q\n", n.Value)
+	case *Number:
+		fmt.Fprintf(b, "Number %s\n", n.Text)
+	case *Bool:
+		fmt.Fprintf(b, "Bool %t\n", n.Value)
+	case *Null:
+		b.WriteString("Null\n")
+	}
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Lexer
+// ---------------------------------------------------------------------------------------------------------------------
+
+// lexJSON
+//
+func lexJSON(l *lexer.Lexer) lexer.Fn {
+	r := l.Peek(1)
+	switch {
+	case isSpace(r):
+		l.Discard(isSpace)
+	case singleTokens[r] != 0:
+		l.Next()
+		l.EmitToken(singleTokens[r])
+	case r == '"':
+		return lexString
+	case r == '-' || isDigit(r):
+		return lexNumber
+	case isLetter(r):
+		for l.CanPeek(1) && isLetter(l.Peek(1)) {
+			l.Next()
+		}
+		if t, ok := literalTokens[l.PeekToken()]; ok {
+			l.EmitToken(t)
+		} else {
+			l.EmitErrorToken("invalid literal")
+			return nil
+		}
+	default:
+		l.Next()
+		l.EmitErrorToken("unexpected character")
+		return nil
+	}
+	return lexJSON
+}
+
+// lexString peeks the string, validating it before it is matched, so errors can be positioned at the offending rune.
+//
+func lexString(l *lexer.Lexer) lexer.Fn {
+	text := &strings.Builder{}
+	n := 1 // Opening quote
+	for {
+		if !l.CanPeek(n + 1) {
+			fail(l, 0, 1, "unterminated string")
+			return nil
+		}
+		n++
+		r := l.Peek(n)
+		switch {
+		case r == '"':
+			value, err := escape.JSON.Decode(text.String())
+			if err != nil {
+				e := err.(*escape.Error)
+				fail(l, e.Column, utf8.RuneCountInString(e.Seq), e.Message)
+				return nil
+			}
+			for ; n > 0; n-- {
+				l.Next()
+			}
+			l.EmitCooked(TString, value)
+			return lexJSON
+		case r < 0x20:
+			fail(l, n-1, 1, "invalid character in string")
+			return nil
+		case r == '\\' && l.CanPeek(n+1):
+			text.WriteRune(r)
+			n++
+			r = l.Peek(n)
+		}
+		text.WriteRune(r)
+	}
+}
+
+// lexNumber matches a number, per the JSON grammar: -?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?
+//
+func lexNumber(l *lexer.Lexer) lexer.Fn {
+	accept(l, "-")
+	valid := accept(l, "0") || acceptDigits(l)
+	if valid && accept(l, ".") {
+		valid = acceptDigits(l)
+	}
+	if valid && accept(l, "eE") {
+		accept(l, "+-")
+		valid = acceptDigits(l)
+	}
+	if !valid {
+		l.EmitErrorToken("invalid number")
+		return nil
+	}
+	value, err := strconv.ParseFloat(l.PeekToken(), 64)
+	if err != nil {
+		l.EmitErrorToken("number out of range")
+		return nil
+	}
+	l.EmitCooked(TNumber, value)
+	return lexJSON
+}
+
+// fail emits an error for the length runes following the skip runes, such that the error is positioned at the
+// offending text.
+//
+func fail(l *lexer.Lexer, skip int, length int, msg string) {
+	if skip > 0 {
+		l.Skip(skip)
+	}
+	for ; length > 0 && l.CanPeek(1); length-- {
+		l.Next()
+	}
+	l.EmitErrorToken(msg)
+}
+
+// accept matches the next rune if it is in set, returning true if matched.
+//
+func accept(l *lexer.Lexer, set string) bool {
+	if l.CanPeek(1) && strings.ContainsRune(set, l.Peek(1)) {
+		l.Next()
+		return true
+	}
+	return false
+}
+
+// acceptDigits matches 1 or more digits, returning true if matched.
+//
+func acceptDigits(l *lexer.Lexer) bool {
+	n := 0
+	for accept(l, "0123456789") {
+		n++
+	}
+	return n > 0
+}
+
+// isSpace
+//
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+// isDigit
+//
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isLetter
+//
+func isLetter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Parser
+// ---------------------------------------------------------------------------------------------------------------------
+
+// valueTypes are the tokens that can start a value.
+//
+var valueTypes = []token.Type{TObjectStart, TArrayStart, TString, TNumber, TTrue, TFalse, TNull}
+
+// parseJSON parses a single value, followed by the end of the input.
+//
+func parseJSON(p *parser.Parser) parser.Fn {
+	v, ok := value(p)
+	if ok && p.CanPeek(1) {
+		p.Expect() // Nothing expected
+		ok = false
+	}
+	if !ok {
+		p.EmitFurthest()
+		return nil
+	}
+	p.EmitSpanned(v)
+	return nil
+}
+
+// value parses a value, returning false if invalid (see Parser.Furthest).
+//
+func value(p *parser.Parser) (Value, bool) {
+	tok, ok := p.Expect(valueTypes...)
+	if !ok {
+		return nil, false
+	}
+	var v Value
+	switch tok.Type() {
+	case TObjectStart:
+		return object(p, tok)
+	case TArrayStart:
+		return array(p, tok)
+	case TString:
+		v = &String{Value: token.Cooked(tok).(string)}
+	case TNumber:
+		v = &Number{Value: token.Cooked(tok).(float64), Text: tok.Value()}
+	case TTrue, TFalse:
+		v = &Bool{Value: tok.Type() == TTrue}
+	case TNull:
+		v = &Null{}
+	}
+	v.SetSpan(token.SpanOf(tok, tok))
+	return v, true
+}
+
+// object parses the remainder of an object, following the opening '{'.
+//
+func object(p *parser.Parser, open token.Token) (Value, bool) {
+	p.EnterRule("object")
+	defer p.ExitRule()
+	obj := &Object{}
+	end, ok := p.Expect(TObjectEnd)
+	for !ok {
+		m, valid := member(p)
+		if !valid {
+			return nil, false
+		}
+		obj.Members = append(obj.Members, m)
+		if end, ok = p.Expect(TObjectEnd); !ok {
+			if _, valid = p.Expect(TComma); !valid {
+				return nil, false
+			}
+		}
+	}
+	obj.SetSpan(token.SpanOf(open, end))
+	return obj, true
+}
+
+// member parses a key/value pair within an object.
+//
+func member(p *parser.Parser) (*Member, bool) {
+	key, ok := p.Expect(TString)
+	if !ok {
+		return nil, false
+	}
+	if _, ok = p.Expect(TColon); !ok {
+		return nil, false
+	}
+	v, ok := value(p)
+	if !ok {
+		return nil, false
+	}
+	m := &Member{Key: &String{Value: token.Cooked(key).(string)}, Value: v}
+	m.Key.SetSpan(token.SpanOf(key, key))
+	m.SetSpan(token.Span{Start: token.Start(key), End: v.Span().End})
+	return m, true
+}
+
+// array parses the remainder of an array, following the opening '['.
+//
+func array(p *parser.Parser, open token.Token) (Value, bool) {
+	p.EnterRule("array")
+	defer p.ExitRule()
+	arr := &Array{}
+	end, ok := p.Expect(TArrayEnd)
+	for !ok {
+		v, valid := value(p)
+		if !valid {
+			return nil, false
+		}
+		arr.Elements = append(arr.Elements, v)
+		if end, ok = p.Expect(TArrayEnd); !ok {
+			if _, valid = p.Expect(TComma); !valid {
+				return nil, false
+			}
+		}
+	}
+	arr.SetSpan(token.SpanOf(open, end))
+	return arr, true
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParse
+//
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`null`, "1:1-1:5 Null\n"},
+		{` -1.5e3 `, "1:2-1:8 Number -1.5e3\n"},
+		{`"a\tb\u00e9\ud83d\ude00"`, "1:1-1:25 String \"a\\tb\u00e9\U0001f600\"\n"},
+		{`[]`, "1:1-1:3 Array\n"},
+		{`{}`, "1:1-1:3 Object\n"},
+		{
+			"{\"a\": [1, true],\n \"b\": {\"c\": null}}",
+			"1:1-2:19 Object\n" +
+				"  1:2-1:16 Member \"a\"\n" +
+				"    1:7-1:16 Array\n" +
+				"      1:8-1:9 Number 1\n" +
+				"      1:11-1:15 Bool true\n" +
+				"  2:2-2:18 Member \"b\"\n" +
+				"    2:7-2:18 Object\n" +
+				"      2:8-2:17 Member \"c\"\n" +
+				"        2:13-2:17 Null\n",
+		},
+	}
+	for _, test := range tests {
+		value, err := Parse(test.input)
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", test.input, err)
+		} else if dump := Dump(value); dump != test.expected {
+			t.Errorf("Parse(%q) expecting:\n%s\nreceived:\n%s", test.input, test.expected, dump)
+		}
+	}
+}
+
+// TestParseError
+//
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{``, "1:1: expected value, found end of input"},
+		{`{"a" 1}`, "1:6: expected ':', found number 1"},
+		{`{"a": 1,}`, "1:9: expected string, found '}'"},
+		{`[1 2]`, "1:4: expected ']' or ',', found number 2"},
+		{`[1,`, "1:4: expected '{', '[', string, number, true, false or null, found end of input"},
+		{`{"a": 1} 2`, "1:10: unexpected number 2"},
+		{`"abc`, "1:1: unterminated string `\"`"},
+		{`"ab\qc"`, "1:4: unknown escape sequence `\\q`"},
+		{`"ab\u12"`, "1:4: invalid unicode escape `\\u12`"},
+		{"[\"a\tb\"]", "1:4: invalid character in string `\t`"},
+		{`[1.]`, "1:2: invalid number `1.`"},
+		{`[-]`, "1:2: invalid number `-`"},
+		{`[01]`, "1:3: expected ']' or ',', found number 1"},
+		{`1e999`, "1:1: number out of range `1e999`"},
+		{`[nul]`, "1:2: invalid literal `nul`"},
+		{`[1, @]`, "1:5: unexpected character `@`"},
+		{strings.Repeat("[", maxDepth+1), "1:514: maximum nesting depth of 512 exceeded"},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.input)
+		if err == nil {
+			t.Errorf("Parse(%q) expecting error '%s'", test.input, test.expected)
+		} else if _, ok := err.(*SyntaxError); !ok {
+			t.Errorf("Parse(%q) expecting *SyntaxError, received %T", test.input, err)
+		} else if err.Error() != test.expected {
+			t.Errorf("Parse(%q) expecting error '%s', received '%v'", test.input, test.expected, err)
+		}
+	}
+}
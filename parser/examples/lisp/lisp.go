@@ -0,0 +1,371 @@
+package main
+
+//
+//	Input is read from STDIN
+//
+//	Each s-expression (datum) in the input is read into a cons-cell AST, and printed, along with its position:
+//
+//	$ echo "(define (sq x) (* x x)) '(1 . 2) ; comment" | lisp
+//	1:1: (define (sq x) (* x x))
+//	1:25: (quote (1 . 2))
+//
+//	The reader:
+//
+//	datum  : atom | list | '\'' datum
+//	list   : '(' ( datum+ ( '.' datum )? )? ')'
+//	atom   : NUMBER | STRING | SYMBOL
+//
+//	Nesting is limited via parser.WithMaxDepth, guarding against stack exhaustion on hostile input.
+//
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/escape"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// We define our lexer tokens starting from the pre-defined START token
+//
+const (
+	TOpen   token.Type = lexer.TStart + iota // '('
+	TClose                                   // ')'
+	TQuote                                   // '\''
+	TDot                                     // '.'
+	TNumber                                  // Cooked value is the int64 or float64 value
+	TString                                  // Cooked value is the decoded string
+	TSymbol                                  // Any other run of runes
+)
+
+// Token names, for error messages
+//
+var tokenNames = map[token.Type]string{
+	TOpen:   "'('",
+	TClose:  "')'",
+	TQuote:  "quote",
+	TDot:    "'.'",
+	TNumber: "number",
+	TString: "string",
+	TSymbol: "symbol",
+}
+
+// MaxDepth is the default nesting limit.
+//
+const MaxDepth = 1000
+
+// main
+//
+func main() {
+	input, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		panic(err)
+	}
+	data, err := Read(string(input), MaxDepth)
+	for _, d := range data {
+		fmt.Printf("%s: %s\n", d.Pos(), d)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// Read reads the s-expressions in the input, with nesting limited to maxDepth (0 for no limit).
+// Returns the data read before the first error, if any, along with the error.
+//
+func Read(input string, maxDepth int) ([]Value, error) {
+	tokens := lexer.LexString(input, lexDatum)
+	asts, err := parser.ParseAll(tokens, parseDatum, parser.WithMaxDepth(maxDepth), parser.WithLexErrors())
+	var data []Value
+	for _, ast := range asts {
+		data = append(data, ast.(Value))
+	}
+	if f, ok := err.(*parser.Failure); ok {
+		err = describe(f)
+	}
+	return data, err
+}
+
+// describe rewords a parser failure with token names, i.e. "1:5: expected ')', found end of input".
+//
+func describe(f *parser.Failure) error {
+	found := "end of input"
+	if f.Found != nil {
+		found = fmt.Sprintf("%s '%s'", tokenNames[f.Found.Type()], f.Found.Value())
+	}
+	if f.Expected.Len() == 0 {
+		return fmt.Errorf("%s: unexpected %s", f.Pos, found)
+	}
+	var expected []string
+	for _, t := range f.Expected.Types() {
+		expected = append(expected, tokenNames[t])
+	}
+	return fmt.Errorf("%s: expected %s, found %s", f.Pos, strings.Join(expected, " or "), found)
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// AST
+// ---------------------------------------------------------------------------------------------------------------------
+
+// Value is implemented by all values.
+//
+type Value interface {
+	fmt.Stringer
+
+	// Pos returns the position of the value within the input.
+	//
+	Pos() token.Position
+}
+
+// pos holds the position of a value.
+//
+type pos token.Position
+
+// Pos implements Value.Pos().
+//
+func (p pos) Pos() token.Position {
+	return token.Position(p)
+}
+
+// Cons is a cons cell; Lists are chains of cells, ending in Nil.
+// The position of the first cell of a list is the position of its '(', while the position of the other cells is the
+// position of their Car.
+//
+type Cons struct {
+	pos
+	Car Value
+	Cdr Value
+}
+
+// Nil is the empty list "()", positioned at its '(', or the end of a list, positioned at its ')'.
+//
+type Nil struct {
+	pos
+}
+
+// Symbol is a symbol.
+//
+type Symbol struct {
+	pos
+	Name string
+}
+
+// Number is an integer (int64) or real (float64).
+//
+type Number struct {
+	pos
+	Value interface{}
+}
+
+// String is a string.
+//
+type String struct {
+	pos
+	Value string
+}
+
+// String implements fmt.Stringer, i.e. "(a b . c)", with quoted data printed in their long form "(quote x)".
+//
+func (c *Cons) String() string {
+	b := &strings.Builder{}
+	b.WriteByte('(')
+	b.WriteString(c.Car.String())
+	v := c.Cdr
+	for cell, ok := v.(*Cons); ok; cell, ok = v.(*Cons) {
+		b.WriteString(" " + cell.Car.String())
+		v = cell.Cdr
+	}
+	if _, ok := v.(*Nil); !ok {
+		b.WriteString(" . " + v.String())
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// String implements fmt.Stringer.
+//
+func (n *Nil) String() string {
+	return "()"
+}
+
+// String implements fmt.Stringer.
+//
+func (s *Symbol) String() string {
+	return s.Name
+}
+
+// String implements fmt.Stringer.
+//
+func (n *Number) String() string {
+	return fmt.Sprint(n.Value)
+}
+
+// String implements fmt.Stringer.
+//
+func (s *String) String() string {
+	return strconv.Quote(s.Value)
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Lexer
+// ---------------------------------------------------------------------------------------------------------------------
+
+// delimiters end symbols and numbers
+//
+const delimiters = "()'\";"
+
+// lexDatum
+//
+func lexDatum(l *lexer.Lexer) lexer.Fn {
+	switch r := l.Peek(1); {
+	case isSpace(r):
+		l.Discard(isSpace)
+	case r == ';':
+		l.Discard(func(r rune) bool { return r != '\n' })
+	case r == '(':
+		l.Next()
+		l.EmitToken(TOpen)
+	case r == ')':
+		l.Next()
+		l.EmitToken(TClose)
+	case r == '\'':
+		l.Next()
+		l.EmitToken(TQuote)
+	case r == '"':
+		return lexString
+	default:
+		for l.CanPeek(1) && !isSpace(l.Peek(1)) && !strings.ContainsRune(delimiters, l.Peek(1)) {
+			l.Next()
+		}
+		text := l.PeekToken()
+		if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+			l.EmitCooked(TNumber, i)
+		} else if f, err := strconv.ParseFloat(text, 64); err == nil {
+			l.EmitCooked(TNumber, f)
+		} else if text == "." {
+			l.EmitToken(TDot)
+		} else {
+			l.EmitToken(TSymbol)
+		}
+	}
+	return lexDatum
+}
+
+// lexString lexes a string, with C-style escapes.
+//
+func lexString(l *lexer.Lexer) lexer.Fn {
+	l.Next() // Opening quote
+	for l.CanPeek(1) && l.Peek(1) != '"' {
+		if l.Next() == '\\' && l.CanPeek(1) {
+			l.Next()
+		}
+	}
+	if !l.CanPeek(1) {
+		l.EmitError("unterminated string")
+		return nil
+	}
+	l.Next() // Closing quote
+	text := l.PeekToken()
+	s, err := escape.C.Decode(text[1 : len(text)-1])
+	if err != nil {
+		l.EmitErrorToken(err.(*escape.Error).Message)
+		return nil
+	}
+	l.EmitCooked(TString, s)
+	return lexDatum
+}
+
+// isSpace
+//
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Parser
+// ---------------------------------------------------------------------------------------------------------------------
+
+// parseDatum emits the next datum.
+//
+func parseDatum(p *parser.Parser) parser.Fn {
+	v, ok := datum(p)
+	if !ok {
+		p.EmitFurthest()
+		return nil
+	}
+	p.Emit(v)
+	return parseDatum
+}
+
+// datum parses a datum, returning false if invalid (see Parser.Furthest).
+//
+func datum(p *parser.Parser) (Value, bool) {
+	tok, ok := p.Expect(TOpen, TQuote, TNumber, TString, TSymbol)
+	if !ok {
+		return nil, false
+	}
+	at := pos(token.Start(tok))
+	switch tok.Type() {
+	case TOpen:
+		return list(p, at)
+	case TQuote:
+		// 'x => (quote x)
+		//
+		v, ok := datum(p)
+		if !ok {
+			return nil, false
+		}
+		end := &Nil{pos: pos(token.End(p.Last()))}
+		return &Cons{pos: at, Car: &Symbol{pos: at, Name: "quote"}, Cdr: &Cons{pos: pos(v.Pos()), Car: v, Cdr: end}}, true
+	case TNumber:
+		return &Number{pos: at, Value: token.Cooked(tok)}, true
+	case TString:
+		return &String{pos: at, Value: token.Cooked(tok).(string)}, true
+	}
+	return &Symbol{pos: at, Name: tok.Value()}, true
+}
+
+// list parses the remainder of a list, following the opening '('.
+//
+func list(p *parser.Parser, at pos) (Value, bool) {
+	p.EnterRule("list")
+	defer p.ExitRule()
+	var head Value
+	tail := &head // Where to link the next cell
+	for {
+		if end, ok := p.Expect(TClose); ok {
+			*tail = &Nil{pos: pos(token.Start(end))}
+			if _, empty := head.(*Nil); empty {
+				head.(*Nil).pos = at
+			}
+			return head, true
+		}
+		if head != nil {
+			if _, ok := p.Expect(TDot); ok {
+				v, ok := datum(p)
+				if !ok {
+					return nil, false
+				}
+				*tail = v
+				_, ok = p.Expect(TClose)
+				return head, ok
+			}
+		}
+		v, ok := datum(p)
+		if !ok {
+			return nil, false
+		}
+		cell := &Cons{pos: pos(v.Pos()), Car: v}
+		if head == nil {
+			cell.pos = at
+		}
+		*tail = cell
+		tail = &cell.Cdr
+	}
+}
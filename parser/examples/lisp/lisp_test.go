@@ -0,0 +1,115 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// TestRead
+//
+func TestRead(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{``, nil},
+		{`; just a comment`, nil},
+		{`a 42 -1.5 "s\t\"q\""`, []string{`1:1 a`, `1:3 42`, `1:6 -1.5`, `1:11 "s\t\"q\""`}},
+		{`() (())`, []string{`1:1 ()`, `1:4 (())`}},
+		{"(define (sq x)\n  (* x x))", []string{`1:1 (define (sq x) (* x x))`}},
+		{`(a . b) (a b . c) (a . (b . (c . ())))`, []string{`1:1 (a . b)`, `1:9 (a b . c)`, `1:19 (a b c)`}},
+		{`'a '(1 'b)`, []string{`1:1 (quote a)`, `1:4 (quote (1 (quote b)))`}},
+		{`+ ... a.b 1+`, []string{`1:1 +`, `1:3 ...`, `1:7 a.b`, `1:11 1+`}},
+	}
+	for _, test := range tests {
+		data, err := Read(test.input, MaxDepth)
+		if err != nil {
+			t.Errorf("Read(%q) unexpected error: %v", test.input, err)
+			continue
+		}
+		var received []string
+		for _, d := range data {
+			received = append(received, d.Pos().String()+" "+d.String())
+		}
+		if strings.Join(received, "\n") != strings.Join(test.expected, "\n") {
+			t.Errorf("Read(%q) expecting %q, received %q", test.input, test.expected, received)
+		}
+	}
+}
+
+// TestPositions
+//
+func TestPositions(t *testing.T) {
+	data, err := Read("(a\n (b)\n )", 0)
+	if err != nil || len(data) != 1 {
+		t.Fatalf("Read() expecting 1 datum, received %d (%v)", len(data), err)
+	}
+	list := data[0].(*Cons)
+	second := list.Cdr.(*Cons)
+	end := second.Cdr.(*Nil)
+	for _, test := range []struct {
+		value    Value
+		expected string
+	}{
+		{list, "1:1"},
+		{list.Car, "1:2"},
+		{second, "2:2"},
+		{second.Car, "2:2"},
+		{second.Car.(*Cons).Car, "2:3"},
+		{second.Car.(*Cons).Cdr, "2:4"},
+		{end, "3:2"},
+	} {
+		if pos := test.value.Pos().String(); pos != test.expected {
+			t.Errorf("%s expecting position %s, received %s", test.value, test.expected, pos)
+		}
+	}
+}
+
+// TestReadError
+//
+func TestReadError(t *testing.T) {
+	tests := []struct {
+		input    string
+		count    int
+		expected string
+	}{
+		{`a (b`, 1, "1:5: expected '(' or ')' or quote or '.' or number or string or symbol, found end of input"},
+		{`)`, 0, "1:1: expected '(' or quote or number or string or symbol, found ')' ')'"},
+		{`(. a)`, 0, "1:2: expected '(' or ')' or quote or number or string or symbol, found '.' '.'"},
+		{`(a . b c)`, 0, "1:8: expected ')', found symbol 'c'"},
+		{`'`, 0, "1:2: expected '(' or quote or number or string or symbol, found end of input"},
+		{`a "b`, 1, "1:5: unterminated string"},
+		{`"\q"`, 0, "1:1: unknown escape sequence"},
+	}
+	for _, test := range tests {
+		data, err := Read(test.input, MaxDepth)
+		if len(data) != test.count {
+			t.Errorf("Read(%q) expecting %d data, received %d", test.input, test.count, len(data))
+		}
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("Read(%q) expecting error '%s', received '%v'", test.input, test.expected, err)
+		}
+	}
+}
+
+// TestDepth
+//
+func TestDepth(t *testing.T) {
+	deep := strings.Repeat("(", 5000) + strings.Repeat(")", 5000)
+	if _, err := Read(deep, 0); err != nil {
+		t.Errorf("Read() unexpected error with no depth limit: %v", err)
+	}
+	if _, err := Read(deep, 5000); err != nil {
+		t.Errorf("Read() unexpected error at depth limit: %v", err)
+	}
+	_, err := Read("ok "+deep, 100)
+	e, ok := err.(*parser.DepthError)
+	if !ok {
+		t.Fatalf("Read() expecting *parser.DepthError, received %T (%v)", err, err)
+	}
+	if e.Limit != 100 || len(e.Rules) != 101 || e.Pos.Column != 105 {
+		t.Errorf("Read() expecting depth error at 1:105, received %v", e)
+	}
+}
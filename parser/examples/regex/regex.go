@@ -0,0 +1,1001 @@
+package main
+
+//
+//	Usage: regex <pattern> <string>...
+//
+//	The pattern is compiled to an NFA, and each string is checked for a match against the entire pattern:
+//
+//	$ regex '(a|b)*c{2,}' abcc ab cccc
+//	abcc: match
+//	ab: no match
+//	cccc: match
+//
+//	Patterns are matched against the following grammar:
+//
+//	alternation : concat ( '|' concat )*
+//	concat      : repeat*
+//	repeat      : atom ( '*' | '+' | '?' | '{n}' | '{n,}' | '{n,m}' )?
+//	atom        : CHAR | '.' | class | '(' alternation ')' | '(?:' alternation ')'
+//	class       : '[' '^'? ( CHAR ( '-' CHAR )? )+ ']'
+//
+//	Escapes \d, \w and \s (and their negations \D, \W and \S) match digits, word characters and spaces, while \n, \t and
+//	\r match newline, tab and carriage return; Any other punctuation can be escaped to match itself, i.e. \. or \{
+//
+//	The parsed tree is passed through two visitors (see the parser/ast package):
+//
+//	  * numberGroups numbers the capturing groups, in order, via ast.Apply
+//	  * simplify flattens nested alternations and concatenations, and drops no-op repetitions, via ast.Rewrite
+//
+//	The tests print the resulting trees via the parsertest pretty-printer.
+//
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+	"github.com/tekwizely/go-parsing/parser/ast"
+)
+
+// We define our lexer tokens starting from the pre-defined START token
+//
+const (
+	TChar    token.Type = lexer.TStart + iota // Cooked value is the rune
+	TAny                                      // '.'
+	TClass                                    // Cooked value is the *Class
+	TRepeat                                   // Cooked value is the repetition
+	TPipe                                     // '|'
+	TOpen                                     // '('
+	TOpenNC                                   // '(?:'
+	TClose                                    // ')'
+	TNewline                                  // Separates patterns
+)
+
+// Token classes
+//
+var (
+	atomStart = token.NewSet(TChar, TAny, TClass, TOpen, TOpenNC)
+	newline   = token.NewSet(TNewline)
+)
+
+// MaxDepth limits the nesting of groups.
+//
+const MaxDepth = 1000
+
+// MaxRepeat limits the counts within repetitions, i.e. a{1000}
+//
+const MaxRepeat = 1000
+
+// MaxInsts limits the size of the compiled NFA, guarding against nested repetitions, i.e. (a{1000}){1000}
+//
+const MaxInsts = 10000
+
+// main
+//
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Printf("usage: %s <pattern> <string>...\n", os.Args[0])
+		os.Exit(2)
+	}
+	re, err := Compile(os.Args[1])
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
+	for _, s := range os.Args[2:] {
+		if re.MatchString(s) {
+			fmt.Printf("%s: match\n", s)
+		} else {
+			fmt.Printf("%s: no match\n", s)
+		}
+	}
+}
+
+// Regexp is a compiled pattern.
+//
+type Regexp struct {
+	expr string
+	tree ast.Node
+	prog []inst
+}
+
+// Compile parses the pattern, returning a Regexp that can be used to match strings.
+// Patterns cannot contain a newline; Use \n instead.
+//
+func Compile(expr string) (*Regexp, error) {
+	if i := strings.IndexByte(expr, '\n'); i >= 0 {
+		return nil, fmt.Errorf("1:%d: newline in pattern", utf8.RuneCountInString(expr[:i])+1)
+	}
+	var tree ast.Node = &Empty{}
+	// The parser is never called without input
+	//
+	if expr != "" {
+		tokens := lexer.LexString(expr, lexPattern)
+		root, _, err := parser.ParseOne(tokens, parsePatterns, parser.WithMaxDepth(MaxDepth), parser.WithLexErrors())
+		if err != nil {
+			return nil, err
+		}
+		tree = root.(ast.Node)
+	}
+	c := &compiler{}
+	c.compile(tree)
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.emit(inst{op: opMatch})
+	return &Regexp{expr: expr, tree: tree, prog: c.prog}, nil
+}
+
+// String returns the source pattern.
+//
+func (re *Regexp) String() string {
+	return re.expr
+}
+
+// Tree returns the syntax tree of the pattern.
+//
+func (re *Regexp) Tree() ast.Node {
+	return re.tree
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// AST
+// ---------------------------------------------------------------------------------------------------------------------
+
+// Alt matches any of its alternatives.
+//
+type Alt struct {
+	Alts []ast.Node
+}
+
+// Concat matches each of its items, in order.
+//
+type Concat struct {
+	Items []ast.Node
+}
+
+// Repeat matches Sub at least Min times, and at most Max times (-1 for no limit).
+//
+type Repeat struct {
+	Sub ast.Node
+	Min int
+	Max int
+}
+
+// Group is a parenthesized sub-pattern; Capturing groups are numbered from 1, in order of their '('.
+//
+type Group struct {
+	Sub   ast.Node
+	Index int // 0 if non-capturing
+}
+
+// Literal matches a single rune.
+//
+type Literal struct {
+	Rune rune
+}
+
+// Any matches any rune, including newline.
+//
+type Any struct{}
+
+// Range is an inclusive range of runes.
+//
+type Range struct {
+	Lo, Hi rune
+}
+
+// Class matches any rune within its ranges, or any rune outside its ranges if negated.
+//
+type Class struct {
+	Ranges  []Range
+	Negated bool
+}
+
+// Empty matches the empty string.
+//
+type Empty struct{}
+
+// Children implements ast.Node.Children().
+//
+func (a *Alt) Children() []ast.Node {
+	return a.Alts
+}
+
+// Children implements ast.Node.Children().
+//
+func (c *Concat) Children() []ast.Node {
+	return c.Items
+}
+
+// Children implements ast.Node.Children().
+//
+func (r *Repeat) Children() []ast.Node {
+	return []ast.Node{r.Sub}
+}
+
+// Children implements ast.Node.Children().
+//
+func (g *Group) Children() []ast.Node {
+	return []ast.Node{g.Sub}
+}
+
+// Children implements ast.Node.Children().
+//
+func (l *Literal) Children() []ast.Node {
+	return nil
+}
+
+// Children implements ast.Node.Children().
+//
+func (a *Any) Children() []ast.Node {
+	return nil
+}
+
+// Children implements ast.Node.Children().
+//
+func (c *Class) Children() []ast.Node {
+	return nil
+}
+
+// Children implements ast.Node.Children().
+//
+func (e *Empty) Children() []ast.Node {
+	return nil
+}
+
+// WithChildren implements ast.Node.WithChildren().
+//
+func (a *Alt) WithChildren(children []ast.Node) ast.Node {
+	return &Alt{Alts: children}
+}
+
+// WithChildren implements ast.Node.WithChildren().
+//
+func (c *Concat) WithChildren(children []ast.Node) ast.Node {
+	return &Concat{Items: children}
+}
+
+// WithChildren implements ast.Node.WithChildren().
+//
+func (r *Repeat) WithChildren(children []ast.Node) ast.Node {
+	return &Repeat{Sub: only(children), Min: r.Min, Max: r.Max}
+}
+
+// WithChildren implements ast.Node.WithChildren().
+//
+func (g *Group) WithChildren(children []ast.Node) ast.Node {
+	return &Group{Sub: only(children), Index: g.Index}
+}
+
+// WithChildren implements ast.Node.WithChildren().
+//
+func (l *Literal) WithChildren([]ast.Node) ast.Node {
+	return l
+}
+
+// WithChildren implements ast.Node.WithChildren().
+//
+func (a *Any) WithChildren([]ast.Node) ast.Node {
+	return a
+}
+
+// WithChildren implements ast.Node.WithChildren().
+//
+func (c *Class) WithChildren([]ast.Node) ast.Node {
+	return c
+}
+
+// WithChildren implements ast.Node.WithChildren().
+//
+func (e *Empty) WithChildren([]ast.Node) ast.Node {
+	return e
+}
+
+// only returns the single child of a Repeat or Group, with a deleted child treated as Empty.
+//
+func only(children []ast.Node) ast.Node {
+	if len(children) == 0 || children[0] == nil {
+		return &Empty{}
+	}
+	return children[0]
+}
+
+// String implements fmt.Stringer, labelling the node for the pretty-printer.
+//
+func (a *Alt) String() string {
+	return "alt"
+}
+
+// String implements fmt.Stringer, labelling the node for the pretty-printer.
+//
+func (c *Concat) String() string {
+	return "concat"
+}
+
+// String implements fmt.Stringer, labelling the node for the pretty-printer, i.e. "repeat {2,5}".
+//
+func (r *Repeat) String() string {
+	switch {
+	case r.Min == 0 && r.Max == -1:
+		return "repeat *"
+	case r.Min == 1 && r.Max == -1:
+		return "repeat +"
+	case r.Min == 0 && r.Max == 1:
+		return "repeat ?"
+	case r.Min == r.Max:
+		return fmt.Sprintf("repeat {%d}", r.Min)
+	case r.Max == -1:
+		return fmt.Sprintf("repeat {%d,}", r.Min)
+	}
+	return fmt.Sprintf("repeat {%d,%d}", r.Min, r.Max)
+}
+
+// String implements fmt.Stringer, labelling the node for the pretty-printer.
+//
+func (g *Group) String() string {
+	if g.Index == 0 {
+		return "group"
+	}
+	return "group " + strconv.Itoa(g.Index)
+}
+
+// String implements fmt.Stringer, labelling the node for the pretty-printer.
+//
+func (l *Literal) String() string {
+	return "literal " + strconv.QuoteRune(l.Rune)
+}
+
+// String implements fmt.Stringer, labelling the node for the pretty-printer.
+//
+func (a *Any) String() string {
+	return "any"
+}
+
+// String implements fmt.Stringer, labelling the node for the pretty-printer, i.e. "class [^0-9a]".
+//
+func (c *Class) String() string {
+	b := &strings.Builder{}
+	b.WriteString("class [")
+	if c.Negated {
+		b.WriteByte('^')
+	}
+	for _, r := range c.Ranges {
+		b.WriteString(classRune(r.Lo))
+		if r.Hi != r.Lo {
+			b.WriteString("-" + classRune(r.Hi))
+		}
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// String implements fmt.Stringer, labelling the node for the pretty-printer.
+//
+func (e *Empty) String() string {
+	return "empty"
+}
+
+// classRune formats a rune within a class, escaping it as needed.
+//
+func classRune(r rune) string {
+	if strings.ContainsRune(`\]-^`, r) {
+		return `\` + string(r)
+	}
+	q := strconv.QuoteRune(r)
+	return q[1 : len(q)-1]
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Lexer
+// ---------------------------------------------------------------------------------------------------------------------
+
+// repetition is the cooked value of a TRepeat token.
+//
+type repetition struct {
+	min, max int // max is -1 for no limit
+}
+
+// quantifiers map the single-rune repetitions to their counts.
+//
+var quantifiers = map[rune]repetition{
+	'*': {0, -1},
+	'+': {1, -1},
+	'?': {0, 1},
+}
+
+// perlClasses map the class escapes to their (non-negated) ranges.
+//
+var perlClasses = map[rune][]Range{
+	'd': {{'0', '9'}},
+	'w': {{'0', '9'}, {'A', 'Z'}, {'_', '_'}, {'a', 'z'}},
+	's': {{'\t', '\n'}, {'\f', '\r'}, {' ', ' '}},
+}
+
+// escapes map the control escapes to their runes.
+//
+var escapes = map[rune]rune{'n': '\n', 't': '\t', 'r': '\r', 'f': '\f'}
+
+// lexPattern lexes patterns, one per line.
+//
+func lexPattern(l *lexer.Lexer) lexer.Fn {
+	switch r := l.Next(); r {
+	case '\n':
+		l.EmitToken(TNewline)
+	case '.':
+		l.EmitToken(TAny)
+	case '|':
+		l.EmitToken(TPipe)
+	case ')':
+		l.EmitToken(TClose)
+	case '(':
+		if l.CanPeek(2) && l.Peek(1) == '?' && l.Peek(2) == ':' {
+			l.Next()
+			l.Next()
+			l.EmitToken(TOpenNC)
+		} else {
+			l.EmitToken(TOpen)
+		}
+	case '*', '+', '?':
+		l.EmitCooked(TRepeat, quantifiers[r])
+	case '{':
+		return lexRepeat
+	case '[':
+		return lexClass
+	case '\\':
+		class, r, ok := escape(l)
+		switch {
+		case !ok:
+			return nil
+		case class != nil:
+			l.EmitCooked(TClass, class)
+		default:
+			l.EmitCooked(TChar, r)
+		}
+	default:
+		l.EmitCooked(TChar, r)
+	}
+	return lexPattern
+}
+
+// escape lexes the remainder of an escape, following the '\', returning either a class or a rune.
+// Returns false if invalid, after emitting an error.
+//
+func escape(l *lexer.Lexer) (*Class, rune, bool) {
+	if !l.CanPeek(1) {
+		l.EmitErrorToken("trailing backslash")
+		return nil, 0, false
+	}
+	r := l.Next()
+	lower := r | 0x20 // ASCII lower-case
+	switch {
+	case perlClasses[lower] != nil:
+		return &Class{Ranges: perlClasses[lower], Negated: r != lower}, 0, true
+	case escapes[r] != 0:
+		return nil, escapes[r], true
+	case r < utf8.RuneSelf && !isAlnum(r):
+		return nil, r, true
+	}
+	l.EmitErrorToken("invalid escape")
+	return nil, 0, false
+}
+
+// lexRepeat lexes the remainder of a counted repetition, following the '{', i.e. "2,5}".
+//
+func lexRepeat(l *lexer.Lexer) lexer.Fn {
+	var rep repetition
+	var ok bool
+	rep.min, ok = count(l)
+	rep.max = rep.min
+	if ok && l.CanPeek(1) && l.Peek(1) == ',' {
+		l.Next()
+		rep.max = -1
+		if l.CanPeek(1) && l.Peek(1) != '}' {
+			rep.max, ok = count(l)
+		}
+	}
+	if !ok || !l.CanPeek(1) || l.Next() != '}' {
+		l.EmitErrorToken("invalid repetition")
+		return nil
+	}
+	if rep.min > MaxRepeat || rep.max > MaxRepeat {
+		l.EmitErrorToken("repetition count too large")
+		return nil
+	}
+	if rep.max != -1 && rep.max < rep.min {
+		l.EmitErrorToken("invalid repetition range")
+		return nil
+	}
+	l.EmitCooked(TRepeat, rep)
+	return lexPattern
+}
+
+// count lexes a repetition count, returning false if there are no digits.
+// Counts above MaxRepeat are clamped to MaxRepeat+1.
+//
+func count(l *lexer.Lexer) (int, bool) {
+	n, digits := 0, 0
+	for l.CanPeek(1) && isDigit(l.Peek(1)) {
+		n = n*10 + int(l.Next()-'0')
+		if n > MaxRepeat {
+			n = MaxRepeat + 1
+		}
+		digits++
+	}
+	return n, digits > 0
+}
+
+// lexClass lexes the remainder of a class, following the '[', i.e. "^a-z_]".
+// A ']' immediately following the '[' (or '[^') is a literal.
+//
+func lexClass(l *lexer.Lexer) lexer.Fn {
+	class := &Class{}
+	if l.CanPeek(1) && l.Peek(1) == '^' {
+		l.Next()
+		class.Negated = true
+	}
+	for first := true; l.CanPeek(1) && (first || l.Peek(1) != ']'); first = false {
+		lo, ok := classChar(l, class)
+		if !ok {
+			return nil
+		}
+		if lo < 0 {
+			continue // Class escape
+		}
+		hi := lo
+		if l.CanPeek(2) && l.Peek(1) == '-' && l.Peek(2) != ']' {
+			l.Next()
+			if hi, ok = classChar(l, class); !ok {
+				return nil
+			}
+			if hi < lo {
+				l.EmitErrorToken("invalid class range")
+				return nil
+			}
+		}
+		class.Ranges = append(class.Ranges, Range{lo, hi})
+	}
+	if !l.CanPeek(1) {
+		l.EmitErrorToken("missing ']'")
+		return nil
+	}
+	l.Next()
+	l.EmitCooked(TClass, class)
+	return lexPattern
+}
+
+// classChar lexes a rune within a class, returning -1 after adding the ranges of a class escape, i.e. \d, to the class.
+// Returns false if invalid, after emitting an error.
+//
+func classChar(l *lexer.Lexer, class *Class) (rune, bool) {
+	r := l.Next()
+	if r != '\\' {
+		return r, true
+	}
+	escaped, r, ok := escape(l)
+	switch {
+	case !ok:
+		return 0, false
+	case escaped == nil:
+		return r, true
+	case escaped.Negated:
+		l.EmitErrorToken("negated escape within class")
+		return 0, false
+	}
+	class.Ranges = append(class.Ranges, escaped.Ranges...)
+	return -1, true
+}
+
+// isDigit
+//
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isAlnum
+//
+func isAlnum(r rune) bool {
+	return isDigit(r) || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Parser
+// ---------------------------------------------------------------------------------------------------------------------
+
+// parsePatterns emits the tree of each pattern, one per line.
+//
+func parsePatterns(p *parser.Parser) parser.Fn {
+	node, ok := alternation(p)
+	// Only the end of the line can follow
+	//
+	if ok && p.CanPeek(1) && !p.PeekIn(1, newline) {
+		ok = false
+	}
+	if !ok {
+		p.EmitError(syntaxError(p))
+		// Skip the rest of the line
+		//
+		for p.CanPeek(1) && !p.PeekIn(1, newline) {
+			p.Next()
+		}
+		p.AcceptIn(newline)
+		p.Clear()
+		return parsePatterns
+	}
+	p.AcceptIn(newline)
+	p.Emit(simplify(numberGroups(node)))
+	return parsePatterns
+}
+
+// syntaxError describes the next token, which failed to match.
+//
+func syntaxError(p *parser.Parser) string {
+	msg := "missing argument to repetition operator"
+	switch last := p.Last(); {
+	case !p.CanPeek(1) || p.PeekIn(1, newline):
+		msg = "missing ')'"
+	case p.PeekType(1) == TClose:
+		msg = "unmatched ')'"
+	case last != nil && last.Type() == TRepeat:
+		msg = "invalid nested repetition operator"
+	}
+	return fmt.Sprintf("%s: %s", p.Pos(), msg)
+}
+
+// alternation parses [ concat ( '|' concat )* ].
+//
+func alternation(p *parser.Parser) (ast.Node, bool) {
+	var alts []ast.Node
+	for {
+		c, ok := concat(p)
+		if !ok {
+			return nil, false
+		}
+		alts = append(alts, c)
+		if _, ok = p.AcceptIn(token.NewSet(TPipe)); !ok {
+			break
+		}
+	}
+	if len(alts) == 1 {
+		return alts[0], true
+	}
+	return &Alt{Alts: alts}, true
+}
+
+// concat parses [ repeat* ].
+//
+func concat(p *parser.Parser) (ast.Node, bool) {
+	var items []ast.Node
+	for p.PeekIn(1, atomStart) {
+		node, ok := repeat(p)
+		if !ok {
+			return nil, false
+		}
+		items = append(items, node)
+	}
+	switch len(items) {
+	case 0:
+		return &Empty{}, true
+	case 1:
+		return items[0], true
+	}
+	return &Concat{Items: items}, true
+}
+
+// repeat parses [ atom repetition? ].
+//
+func repeat(p *parser.Parser) (ast.Node, bool) {
+	node, ok := atom(p)
+	if !ok {
+		return nil, false
+	}
+	if tok, ok := p.AcceptIn(token.NewSet(TRepeat)); ok {
+		rep := token.Cooked(tok).(repetition)
+		node = &Repeat{Sub: node, Min: rep.min, Max: rep.max}
+	}
+	return node, true
+}
+
+// atom parses [ CHAR | '.' | class | '(' alternation ')' | '(?:' alternation ')' ].
+//
+func atom(p *parser.Parser) (ast.Node, bool) {
+	tok := p.Next()
+	switch tok.Type() {
+	case TChar:
+		return &Literal{Rune: token.Cooked(tok).(rune)}, true
+	case TAny:
+		return &Any{}, true
+	case TClass:
+		return token.Cooked(tok).(*Class), true
+	}
+	p.EnterRule("group")
+	defer p.ExitRule()
+	sub, ok := alternation(p)
+	if !ok {
+		return nil, false
+	}
+	if _, ok = p.AcceptIn(token.NewSet(TClose)); !ok {
+		return nil, false
+	}
+	// Capturing groups are numbered later, see numberGroups
+	//
+	g := &Group{Sub: sub}
+	if tok.Type() == TOpen {
+		g.Index = -1
+	}
+	return g, true
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Visitors
+// ---------------------------------------------------------------------------------------------------------------------
+
+// numberGroups numbers the capturing groups, in order of their '(', via a pre-order traversal.
+//
+func numberGroups(tree ast.Node) ast.Node {
+	n := 0
+	return ast.Apply(tree, func(c *ast.Cursor) bool {
+		if g, ok := c.Node().(*Group); ok && g.Index != 0 {
+			n++
+			c.Replace(&Group{Sub: g.Sub, Index: n})
+		}
+		return true
+	}, nil)
+}
+
+// simplify flattens nested alternations and concatenations, drops empty items from concatenations, and replaces
+// no-op repetitions, i.e. x{1}, with their sub-pattern, via a post-order traversal.
+// Non-capturing groups are replaced with their sub-pattern, allowing them to be flattened.
+//
+func simplify(tree ast.Node) ast.Node {
+	return ast.Rewrite(tree, func(node ast.Node) ast.Node {
+		switch n := node.(type) {
+		case *Group:
+			if n.Index == 0 {
+				return n.Sub
+			}
+		case *Repeat:
+			if _, empty := n.Sub.(*Empty); empty || n.Min == 1 && n.Max == 1 {
+				return n.Sub
+			}
+		case *Alt:
+			var alts []ast.Node
+			for _, alt := range n.Alts {
+				if inner, ok := alt.(*Alt); ok {
+					alts = append(alts, inner.Alts...)
+				} else {
+					alts = append(alts, alt)
+				}
+			}
+			if len(alts) != len(n.Alts) {
+				return &Alt{Alts: alts}
+			}
+		case *Concat:
+			var items []ast.Node
+			for _, item := range n.Items {
+				switch inner := item.(type) {
+				case *Concat:
+					items = append(items, inner.Items...)
+				case *Empty:
+				default:
+					items = append(items, item)
+				}
+			}
+			switch {
+			case len(items) == 0:
+				return &Empty{}
+			case len(items) == 1:
+				return items[0]
+			case len(items) != len(n.Items):
+				return &Concat{Items: items}
+			}
+		}
+		return node
+	})
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// NFA
+// ---------------------------------------------------------------------------------------------------------------------
+
+// opcode identifies an NFA instruction.
+//
+type opcode int
+
+// NFA instructions
+//
+const (
+	opRune  opcode = iota // Consume a rune accepted by m, continuing at the next instruction
+	opSplit               // Continue at both x and y
+	opJmp                 // Continue at x
+	opMatch               // Match the input, if fully consumed
+)
+
+// inst is an NFA instruction; The NFA is a program, with each instruction a state.
+//
+type inst struct {
+	op   opcode
+	m    matcher
+	x, y int
+}
+
+// matcher is implemented by the nodes that consume a rune.
+//
+type matcher interface {
+	matches(r rune) bool
+}
+
+// matches implements matcher.
+//
+func (l *Literal) matches(r rune) bool {
+	return r == l.Rune
+}
+
+// matches implements matcher.
+//
+func (a *Any) matches(rune) bool {
+	return true
+}
+
+// matches implements matcher.
+//
+func (c *Class) matches(r rune) bool {
+	for _, rng := range c.Ranges {
+		if r >= rng.Lo && r <= rng.Hi {
+			return !c.Negated
+		}
+	}
+	return c.Negated
+}
+
+// compiler compiles a tree into an NFA program, via Thompson's construction.
+//
+type compiler struct {
+	prog []inst
+	err  error
+}
+
+// emit appends the instruction, returning its index.
+//
+func (c *compiler) emit(i inst) int {
+	if len(c.prog) == MaxInsts {
+		c.err = errors.New("pattern too large")
+	}
+	c.prog = append(c.prog, i)
+	return len(c.prog) - 1
+}
+
+// compile appends the instructions for the node.
+//
+func (c *compiler) compile(node ast.Node) {
+	if c.err != nil {
+		return
+	}
+	switch n := node.(type) {
+	case matcher:
+		c.emit(inst{op: opRune, m: n})
+	case *Group:
+		c.compile(n.Sub)
+	case *Concat:
+		for _, item := range n.Items {
+			c.compile(item)
+		}
+	case *Alt:
+		// split L1, L2; L1: alt1; jmp end; L2: split ...; Ln: altn; end:
+		//
+		var jumps []int
+		for i, alt := range n.Alts {
+			if i == len(n.Alts)-1 {
+				c.compile(alt)
+				break
+			}
+			split := c.emit(inst{op: opSplit})
+			c.prog[split].x = len(c.prog)
+			c.compile(alt)
+			jumps = append(jumps, c.emit(inst{op: opJmp}))
+			c.prog[split].y = len(c.prog)
+		}
+		for _, j := range jumps {
+			c.prog[j].x = len(c.prog)
+		}
+	case *Repeat:
+		for i := 0; i < n.Min && c.err == nil; i++ {
+			c.compile(n.Sub)
+		}
+		if n.Max == -1 {
+			// L: split body, end; body: sub; jmp L; end:
+			//
+			loop := c.emit(inst{op: opSplit})
+			c.prog[loop].x = len(c.prog)
+			c.compile(n.Sub)
+			c.emit(inst{op: opJmp, x: loop})
+			c.prog[loop].y = len(c.prog)
+			return
+		}
+		// Each optional copy can skip to the end: split body, end; body: sub; ...; end:
+		//
+		var splits []int
+		for i := n.Min; i < n.Max && c.err == nil; i++ {
+			split := c.emit(inst{op: opSplit})
+			c.prog[split].x = len(c.prog)
+			splits = append(splits, split)
+			c.compile(n.Sub)
+		}
+		for _, s := range splits {
+			c.prog[s].y = len(c.prog)
+		}
+	}
+}
+
+// MatchString confirms if the entire string matches the pattern.
+// The NFA is simulated by tracking the set of states reachable after each rune, so matching takes time linear in the
+// length of the string.
+//
+func (re *Regexp) MatchString(s string) bool {
+	clist, nlist := newStates(len(re.prog)), newStates(len(re.prog))
+	re.add(clist, 0)
+	for _, r := range s {
+		nlist.clear()
+		for _, pc := range clist.pcs {
+			if i := re.prog[pc]; i.op == opRune && i.m.matches(r) {
+				re.add(nlist, pc+1)
+			}
+		}
+		clist, nlist = nlist, clist
+		if len(clist.pcs) == 0 {
+			return false
+		}
+	}
+	for _, pc := range clist.pcs {
+		if re.prog[pc].op == opMatch {
+			return true
+		}
+	}
+	return false
+}
+
+// add adds the state, following splits and jumps, to the set.
+//
+func (re *Regexp) add(set *states, pc int) {
+	if set.has[pc] {
+		return
+	}
+	set.has[pc] = true
+	set.pcs = append(set.pcs, pc)
+	switch i := re.prog[pc]; i.op {
+	case opSplit:
+		re.add(set, i.x)
+		re.add(set, i.y)
+	case opJmp:
+		re.add(set, i.x)
+	}
+}
+
+// states is a set of NFA states, in the order they were added.
+// Splits and jumps are included, so that they are only followed once per rune.
+//
+type states struct {
+	pcs []int
+	has []bool
+}
+
+// newStates
+//
+func newStates(n int) *states {
+	return &states{has: make([]bool, n)}
+}
+
+// clear empties the set.
+//
+func (s *states) clear() {
+	for _, pc := range s.pcs {
+		s.has[pc] = false
+	}
+	s.pcs = s.pcs[:0]
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/parser/parsertest"
+)
+
+// TestGolden pretty-prints the tree of each pattern, including the syntax errors.
+//
+func TestGolden(t *testing.T) {
+	parsertest.Golden(t, "testdata/patterns.txt", "testdata/patterns.golden", lexPattern, parsePatterns)
+}
+
+// TestMatch
+//
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		matched   []string
+		unmatched []string
+	}{
+		{``, []string{``}, []string{`a`}},
+		{`abc`, []string{`abc`}, []string{`ab`, `abcd`, ``}},
+		{`a|bc|`, []string{`a`, `bc`, ``}, []string{`b`, `abc`}},
+		{`(a|b)*c{2,}`, []string{`abcc`, `cccc`, `cc`}, []string{`ab`, `abc`}},
+		{`a+b?`, []string{`a`, `aab`}, []string{`b`, `abb`}},
+		{`x{2}`, []string{`xx`}, []string{`x`, `xxx`}},
+		{`x{1,3}`, []string{`x`, `xxx`}, []string{``, `xxxx`}},
+		{`.\.`, []string{`a.`, `..`}, []string{`ab`}},
+		{`[a-c\d]+`, []string{`abc123`}, []string{`abcd`}},
+		{`[^a-c]`, []string{`d`, "\n"}, []string{`a`}},
+		{`[]a]*`, []string{`]a]`}, []string{`b`}},
+		{`\w+\s\S\D`, []string{`a_1 !x`}, []string{`a_1 !1`}},
+		{`[a-]+`, []string{`a-a`}, []string{`b`}},
+		{`(a*)*b`, []string{`b`, `aaab`}, []string{`aaa`}},
+		{`h.llo`, []string{"h\u00e9llo"}, []string{"h\u00e9\u00e9llo"}},
+	}
+	for _, test := range tests {
+		re, err := Compile(test.pattern)
+		if err != nil {
+			t.Errorf("Compile(%q) unexpected error: %v", test.pattern, err)
+			continue
+		}
+		for _, s := range test.matched {
+			if !re.MatchString(s) {
+				t.Errorf("%q.MatchString(%q) expecting match", test.pattern, s)
+			}
+		}
+		for _, s := range test.unmatched {
+			if re.MatchString(s) {
+				t.Errorf("%q.MatchString(%q) expecting no match", test.pattern, s)
+			}
+		}
+	}
+}
+
+// TestCompileError
+//
+func TestCompileError(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{`ab(c`, "1:5: missing ')'"},
+		{`a)b`, "1:2: unmatched ')'"},
+		{`|+`, "1:2: missing argument to repetition operator"},
+		{`a*?`, "1:3: invalid nested repetition operator"},
+		{`ab\`, "1:3: trailing backslash"},
+		{`\q`, "1:1: invalid escape"},
+		{`[z-a]`, "1:1: invalid class range"},
+		{`[\D]`, "1:1: negated escape within class"},
+		{`[ab`, "1:1: missing ']'"},
+		{`a{2`, "1:2: invalid repetition"},
+		{`a{3,2}`, "1:2: invalid repetition range"},
+		{`a{1001}`, "1:2: repetition count too large"},
+		{`(a{1000}){1000}`, "pattern too large"},
+		{"a\nb", "1:2: newline in pattern"},
+	}
+	for _, test := range tests {
+		_, err := Compile(test.pattern)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("Compile(%q) expecting error '%s', received '%v'", test.pattern, test.expected, err)
+		}
+	}
+}
+
+// TestDepth
+//
+func TestDepth(t *testing.T) {
+	if _, err := Compile(strings.Repeat("(", 100) + strings.Repeat(")", 100)); err != nil {
+		t.Errorf("Compile() unexpected error: %v", err)
+	}
+	if _, err := Compile(strings.Repeat("(", 5000) + strings.Repeat(")", 5000)); err == nil {
+		t.Errorf("Compile() expecting depth error")
+	}
+}
+
+// TestLinear confirms that matching does not backtrack.
+//
+func TestLinear(t *testing.T) {
+	re, err := Compile(strings.Repeat("a?", 30) + strings.Repeat("a", 30))
+	if err != nil {
+		t.Fatalf("Compile() unexpected error: %v", err)
+	}
+	if !re.MatchString(strings.Repeat("a", 30)) {
+		t.Errorf("MatchString() expecting match")
+	}
+}
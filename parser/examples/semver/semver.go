@@ -0,0 +1,807 @@
+package main
+
+//
+//	Usage: semver <constraint> <version>...
+//
+//	Each version is checked against the constraint:
+//
+//	$ semver '>=1.2.x <2.0.0 || 3.*' 1.2.0 1.9.9-beta 2.0.0 3.4.5
+//	1.2.0: yes
+//	1.9.9-beta: yes
+//	2.0.0: no
+//	3.4.5: yes
+//
+//	Constraints are matched against the following pattern:
+//
+//	constraint : range ( '||' range )*
+//	range      : partial ' - ' partial | term ( ' ' term )*
+//	term       : ( '=' | '<' | '<=' | '>' | '>=' | '~' | '^' )? partial
+//	partial    : part ( '.' part ( '.' part prerelease? build? )? )?
+//	part       : NUMBER | 'x' | 'X' | '*'
+//	prerelease : '-' ident ( '.' ident )*
+//	build      : '+' ident ( '.' ident )*
+//	ident      : [0-9A-Za-z-]+
+//
+//	Terms within a range are separated by spaces, and must all match; At least one range must match.
+//	Parts following a wildcard must also be wildcards, i.e. 1.x.x, and missing parts are wildcards, i.e. 1.2 == 1.2.x
+//
+//	The terms mean (following npm):
+//
+//	1.2.3   : =1.2.3
+//	1.2.x   : >=1.2.0 <1.3.0
+//	<=1.2   : <1.3.0
+//	>1.2    : >=1.3.0
+//	~1.2.3  : >=1.2.3 <1.3.0 (patch updates)
+//	^1.2.3  : >=1.2.3 <2.0.0 (updates not changing the left-most non-zero part)
+//	^0.2.3  : >=0.2.3 <0.3.0
+//	1 - 2.3 : >=1.0.0 <2.4.0
+//
+//	Versions are ordered per the semver precedence rules, with prerelease versions ordered before their release.
+//
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// We define our lexer tokens starting from the pre-defined START token
+//
+const (
+	TNumber   token.Type = lexer.TStart + iota // Cooked value is the int value
+	TIdent                                     // Prerelease / build identifier
+	TWildcard                                  // 'x', 'X' or '*'
+	TDot
+	TDash
+	TPlus
+	TSpace
+	TOr
+	TEq
+	TLt
+	TLe
+	TGt
+	TGe
+	TTilde
+	TCaret
+)
+
+// Token names, for error messages
+//
+var tokenNames = map[token.Type]string{
+	TNumber:   "number",
+	TIdent:    "identifier",
+	TWildcard: "wildcard",
+	TDot:      "'.'",
+	TDash:     "'-'",
+	TPlus:     "'+'",
+	TSpace:    "space",
+	TOr:       "'||'",
+	TEq:       "'='",
+	TLt:       "'<'",
+	TLe:       "'<='",
+	TGt:       "'>'",
+	TGe:       "'>='",
+	TTilde:    "'~'",
+	TCaret:    "'^'",
+}
+
+// Token classes
+//
+var (
+	operators = token.NewSet(TEq, TLt, TLe, TGt, TGe, TTilde, TCaret)
+	parts     = token.NewSet(TNumber, TWildcard)
+	idents    = token.NewSet(TNumber, TIdent)
+	termStart = operators.Union(parts)
+	spaces    = token.NewSet(TSpace)
+)
+
+// main
+//
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Printf("usage: %s <constraint> <version>...\n", os.Args[0])
+		os.Exit(2)
+	}
+	c, err := ParseConstraint(os.Args[1])
+	if err != nil {
+		fmt.Println("error:", err)
+		os.Exit(1)
+	}
+	for _, arg := range os.Args[2:] {
+		v, err := ParseVersion(arg)
+		switch {
+		case err != nil:
+			fmt.Printf("%s: error: %s\n", arg, err)
+		case c.Matches(v):
+			fmt.Printf("%s: yes\n", arg)
+		default:
+			fmt.Printf("%s: no\n", arg)
+		}
+	}
+}
+
+// ParseConstraint parses a constraint, i.e. ">=1.2.x <2.0.0 || 3.*".
+// An empty constraint matches any version, as with npm.
+//
+func ParseConstraint(input string) (*Constraint, error) {
+	if strings.TrimSpace(input) == "" {
+		input = "*"
+	}
+	ast, err := parse(input, parseConstraint)
+	if err != nil {
+		return nil, err
+	}
+	return ast.(*Constraint), nil
+}
+
+// ParseVersion parses a full version, i.e. "1.2.3-beta.1+build.5".
+//
+func ParseVersion(input string) (*Version, error) {
+	ast, err := parse(input, parseVersion)
+	if err != nil {
+		return nil, err
+	}
+	return ast.(*Version), nil
+}
+
+// parse parses the input via start, which emits a single AST.
+//
+func parse(input string, start parser.Fn) (interface{}, error) {
+	// The parser is never called without input
+	//
+	if input == "" {
+		return nil, errors.New("1:1: empty input")
+	}
+	tokens := lexer.LexString(input, lexMain)
+	ast, _, err := parser.ParseOne(tokens, start, parser.WithLexErrors())
+	if f, ok := err.(*parser.Failure); ok {
+		return nil, describe(f)
+	}
+	return ast, err
+}
+
+// describe rewords a parser failure with token names, i.e. "1:5: expected number or wildcard, found end of input".
+//
+func describe(f *parser.Failure) error {
+	found := "end of input"
+	if f.Found != nil {
+		found = tokenNames[f.Found.Type()]
+		if t := f.Found.Type(); t == TNumber || t == TIdent {
+			found += fmt.Sprintf(" '%s'", f.Found.Value())
+		}
+	}
+	if f.Expected.Len() == 0 {
+		return fmt.Errorf("%s: unexpected %s", f.Pos, found)
+	}
+	var expected []string
+	for _, t := range f.Expected.Types() {
+		expected = append(expected, tokenNames[t])
+	}
+	return fmt.Errorf("%s: expected %s, found %s", f.Pos, strings.Join(expected, " or "), found)
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// AST
+// ---------------------------------------------------------------------------------------------------------------------
+
+// pos holds the position of a node.
+//
+type pos token.Position
+
+// Pos returns the position of the node within the input.
+//
+func (p pos) Pos() token.Position {
+	return token.Position(p)
+}
+
+// Constraint is a list of ranges, matching versions matched by any of the ranges.
+//
+type Constraint struct {
+	pos
+	Ranges []*Range
+}
+
+// Range is a list of terms, matching versions matched by all of the terms.
+//
+type Range struct {
+	pos
+	Terms []Term
+}
+
+// Term is implemented by Comparator and Hyphen.
+//
+type Term interface {
+	fmt.Stringer
+	Pos() token.Position
+
+	// Matches confirms if the version satisfies the term.
+	//
+	Matches(v *Version) bool
+}
+
+// Comparator compares versions against a partial version, i.e. ">=1.2" or "~1.2.3".
+//
+type Comparator struct {
+	pos
+	Op      string // "", "=", "<", "<=", ">", ">=", "~" or "^"
+	Version *Partial
+}
+
+// Hyphen is a hyphen range, i.e. "1.2 - 2.3.4".
+//
+type Hyphen struct {
+	pos
+	From *Partial
+	To   *Partial
+}
+
+// Wildcard marks a part that is missing, or a wildcard, i.e. the minor and patch parts of "1" or "1.x".
+//
+const Wildcard = -1
+
+// Partial is a version with optional wildcards, i.e. "1.2.x".
+// Only versions without wildcards can have a prerelease or build.
+//
+type Partial struct {
+	pos
+	Major, Minor, Patch int // Wildcard if missing
+	Pre, Build          []string
+}
+
+// Version is a full version, i.e. "1.2.3-beta.1+build.5".
+//
+type Version struct {
+	pos
+	Major, Minor, Patch int
+	Pre, Build          []string
+}
+
+// String implements fmt.Stringer.
+//
+func (c *Constraint) String() string {
+	ranges := make([]string, len(c.Ranges))
+	for i, r := range c.Ranges {
+		ranges[i] = r.String()
+	}
+	return strings.Join(ranges, " || ")
+}
+
+// String implements fmt.Stringer.
+//
+func (r *Range) String() string {
+	terms := make([]string, len(r.Terms))
+	for i, t := range r.Terms {
+		terms[i] = t.String()
+	}
+	return strings.Join(terms, " ")
+}
+
+// String implements fmt.Stringer.
+//
+func (c *Comparator) String() string {
+	return c.Op + c.Version.String()
+}
+
+// String implements fmt.Stringer.
+//
+func (h *Hyphen) String() string {
+	return h.From.String() + " - " + h.To.String()
+}
+
+// String implements fmt.Stringer, with wildcards as 'x', i.e. "1.x.x".
+//
+func (p *Partial) String() string {
+	s := make([]string, 3)
+	for i, n := range []int{p.Major, p.Minor, p.Patch} {
+		s[i] = "x"
+		if n != Wildcard {
+			s[i] = strconv.Itoa(n)
+		}
+	}
+	return strings.Join(s, ".") + suffix(p.Pre, p.Build)
+}
+
+// String implements fmt.Stringer.
+//
+func (v *Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch) + suffix(v.Pre, v.Build)
+}
+
+// suffix returns the prerelease and build suffix, i.e. "-beta.1+build.5".
+//
+func suffix(pre []string, build []string) string {
+	s := ""
+	if len(pre) > 0 {
+		s += "-" + strings.Join(pre, ".")
+	}
+	if len(build) > 0 {
+		s += "+" + strings.Join(build, ".")
+	}
+	return s
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Matching
+// ---------------------------------------------------------------------------------------------------------------------
+
+// Matches confirms if the version is matched by any of the ranges.
+//
+func (c *Constraint) Matches(v *Version) bool {
+	for _, r := range c.Ranges {
+		if r.Matches(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches confirms if the version is matched by all of the terms.
+//
+func (r *Range) Matches(v *Version) bool {
+	for _, t := range r.Terms {
+		if !t.Matches(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches implements Term.Matches().
+//
+func (c *Comparator) Matches(v *Version) bool {
+	p := c.Version
+	floor, ceil := p.floor(), p.bump(len(p.parts())-1)
+	switch c.Op {
+	case "", "=":
+		return v.Compare(floor) >= 0 && (p.full() && v.Compare(floor) == 0 || !p.full() && below(v, ceil))
+	case "<":
+		return v.Compare(floor) < 0
+	case "<=":
+		return p.full() && v.Compare(floor) <= 0 || !p.full() && below(v, ceil)
+	case ">":
+		return p.full() && v.Compare(floor) > 0 || !p.full() && ceil != nil && v.Compare(ceil) >= 0
+	case ">=":
+		return v.Compare(floor) >= 0
+	case "~":
+		return v.Compare(floor) >= 0 && below(v, p.bump(min(len(p.parts())-1, 1)))
+	}
+	// '^' bumps the left-most non-zero part, or the last part if all are zero
+	//
+	parts := p.parts()
+	i := 0
+	for i < len(parts)-1 && parts[i] == 0 {
+		i++
+	}
+	return v.Compare(floor) >= 0 && below(v, p.bump(min(i, len(parts)-1)))
+}
+
+// Matches implements Term.Matches().
+//
+func (h *Hyphen) Matches(v *Version) bool {
+	if v.Compare(h.From.floor()) < 0 {
+		return false
+	}
+	if h.To.full() {
+		return v.Compare(h.To.floor()) <= 0
+	}
+	return below(v, h.To.bump(len(h.To.parts())-1))
+}
+
+// parts returns the parts preceding the first wildcard.
+//
+func (p *Partial) parts() []int {
+	parts := []int{p.Major, p.Minor, p.Patch}
+	for i, n := range parts {
+		if n == Wildcard {
+			return parts[:i]
+		}
+	}
+	return parts
+}
+
+// full confirms if the partial has no wildcards.
+//
+func (p *Partial) full() bool {
+	return p.Patch != Wildcard
+}
+
+// floor returns the lowest version matching the partial, i.e. 1.2.0 for 1.2.x
+//
+func (p *Partial) floor() *Version {
+	parts := append(p.parts(), 0, 0, 0)
+	return &Version{pos: p.pos, Major: parts[0], Minor: parts[1], Patch: parts[2], Pre: p.Pre}
+}
+
+// bump returns the version with the ith part incremented, and the following parts zeroed, i.e. 1.3.0 for 1.2.x with
+// i == 1.
+// Returns nil for i < 0, as for "*", for which there is no such version.
+//
+func (p *Partial) bump(i int) *Version {
+	if i < 0 {
+		return nil
+	}
+	parts := append(p.parts()[:i+1:i+1], 0, 0)
+	parts[i]++
+	return &Version{pos: p.pos, Major: parts[0], Minor: parts[1], Patch: parts[2]}
+}
+
+// below confirms if v is below the ceiling, with a nil ceiling being unbounded.
+//
+func below(v *Version, ceil *Version) bool {
+	return ceil == nil || v.Compare(ceil) < 0
+}
+
+// min
+//
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Compare compares versions by precedence, returning -1, 0 or 1, as v is lower than, equal to, or higher than o.
+// Build metadata is ignored.
+//
+func (v *Version) Compare(o *Version) int {
+	if c := compareInts(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareInts(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareInts(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+	// A prerelease is lower than its release
+	//
+	switch {
+	case len(v.Pre) == 0 && len(o.Pre) == 0:
+		return 0
+	case len(v.Pre) == 0:
+		return 1
+	case len(o.Pre) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.Pre) && i < len(o.Pre); i++ {
+		if c := compareIdents(v.Pre[i], o.Pre[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(v.Pre), len(o.Pre))
+}
+
+// compareIdents compares prerelease identifiers; Numeric identifiers are compared numerically, and are lower than
+// alphanumeric identifiers, which are compared lexically.
+//
+func compareIdents(a string, b string) int {
+	an, aerr := strconv.Atoi(a)
+	bn, berr := strconv.Atoi(b)
+	switch {
+	case aerr == nil && berr == nil:
+		return compareInts(an, bn)
+	case aerr == nil:
+		return -1
+	case berr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareInts
+//
+func compareInts(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Lexer
+// ---------------------------------------------------------------------------------------------------------------------
+
+// lexMain lexes constraints and versions.
+//
+func lexMain(l *lexer.Lexer) lexer.Fn {
+	switch r := l.Next(); {
+	case r == ' ' || r == '\t':
+		l.Discard(func(r rune) bool { return r == ' ' || r == '\t' })
+		l.EmitToken(TSpace)
+	case isDigit(r):
+		for l.CanPeek(1) && isDigit(l.Peek(1)) {
+			l.Next()
+		}
+		return lexNumber(l, lexMain)
+	case r == 'x' || r == 'X' || r == '*':
+		l.EmitToken(TWildcard)
+	case r == '.':
+		l.EmitToken(TDot)
+	case r == '-' && (!l.CanPeek(1) || l.Peek(1) != ' '):
+		l.EmitToken(TDash)
+		return lexPrerelease
+	case r == '-':
+		l.EmitToken(TDash)
+	case r == '+':
+		l.EmitToken(TPlus)
+		return lexBuild
+	case r == '|' && l.CanPeek(1) && l.Peek(1) == '|':
+		l.Next()
+		l.EmitToken(TOr)
+	case r == '=':
+		l.EmitToken(TEq)
+	case r == '~':
+		l.EmitToken(TTilde)
+	case r == '^':
+		l.EmitToken(TCaret)
+	case r == '<' || r == '>':
+		t := TLt
+		if r == '>' {
+			t = TGt
+		}
+		if l.CanPeek(1) && l.Peek(1) == '=' {
+			l.Next()
+			t++ // TLe, TGe
+		}
+		l.EmitToken(t)
+	default:
+		l.EmitErrorToken("unexpected character")
+		return nil
+	}
+	return lexMain
+}
+
+// lexPrerelease lexes the dot-separated identifiers of a prerelease.
+//
+func lexPrerelease(l *lexer.Lexer) lexer.Fn {
+	switch r := l.Peek(1); {
+	case r == '.':
+		l.Next()
+		l.EmitToken(TDot)
+	case r == '+':
+		l.Next()
+		l.EmitToken(TPlus)
+		return lexBuild
+	case isIdent(r):
+		numeric := true
+		for l.CanPeek(1) && isIdent(l.Peek(1)) {
+			if !isDigit(l.Next()) {
+				numeric = false
+			}
+		}
+		if numeric {
+			return lexNumber(l, lexPrerelease)
+		}
+		l.EmitToken(TIdent)
+	default:
+		return lexMain
+	}
+	return lexPrerelease
+}
+
+// lexBuild lexes the dot-separated identifiers of a build.
+// Build identifiers are never compared, so are always emitted as TIdent, allowing leading zeros.
+//
+func lexBuild(l *lexer.Lexer) lexer.Fn {
+	switch r := l.Peek(1); {
+	case r == '.':
+		l.Next()
+		l.EmitToken(TDot)
+	case isIdent(r):
+		for l.CanPeek(1) && isIdent(l.Peek(1)) {
+			l.Next()
+		}
+		l.EmitToken(TIdent)
+	default:
+		return lexMain
+	}
+	return lexBuild
+}
+
+// lexNumber emits the matched digits as a number, returning next.
+// Numbers cannot have leading zeros.
+//
+func lexNumber(l *lexer.Lexer, next lexer.Fn) lexer.Fn {
+	text := l.PeekToken()
+	n, err := strconv.Atoi(text)
+	switch {
+	case len(text) > 1 && text[0] == '0':
+		l.EmitErrorToken("number has leading zero")
+		return nil
+	case err != nil:
+		l.EmitErrorToken("number out of range")
+		return nil
+	}
+	l.EmitCooked(TNumber, n)
+	return next
+}
+
+// isDigit
+//
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isIdent
+//
+func isIdent(r rune) bool {
+	return isDigit(r) || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-'
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Parser
+// ---------------------------------------------------------------------------------------------------------------------
+
+// parseConstraint emits a *Constraint.
+//
+func parseConstraint(p *parser.Parser) parser.Fn {
+	c, ok := constraint(p)
+	if !ok {
+		p.EmitFurthest()
+		return nil
+	}
+	p.Emit(c)
+	return nil
+}
+
+// parseVersion emits a *Version.
+//
+func parseVersion(p *parser.Parser) parser.Fn {
+	v, ok := partial(p, false)
+	if ok && !v.full() {
+		_, ok = p.Expect(TDot)
+	}
+	if ok && p.CanPeek(1) {
+		_, ok = p.Expect()
+	}
+	if !ok {
+		p.EmitFurthest()
+		return nil
+	}
+	p.Emit(&Version{pos: v.pos, Major: v.Major, Minor: v.Minor, Patch: v.Patch, Pre: v.Pre, Build: v.Build})
+	return nil
+}
+
+// constraint parses a constraint, ignoring leading and trailing spaces, returning false if invalid (see
+// Parser.Furthest).
+//
+func constraint(p *parser.Parser) (*Constraint, bool) {
+	skipSpace(p)
+	c := &Constraint{pos: pos(p.Pos())}
+	for {
+		r, ok := parseRange(p)
+		if !ok {
+			return nil, false
+		}
+		c.Ranges = append(c.Ranges, r)
+		skipSpace(p)
+		if _, ok = p.Expect(TOr); !ok {
+			break
+		}
+		skipSpace(p)
+	}
+	// Should be at end of input
+	//
+	if p.CanPeek(1) {
+		p.Expect()
+		return nil, false
+	}
+	return c, true
+}
+
+// parseRange parses [ partial ' - ' partial | term ( ' ' term )* ].
+//
+func parseRange(p *parser.Parser) (*Range, bool) {
+	r := &Range{pos: pos(p.Pos())}
+	first, ok := term(p)
+	if !ok {
+		return nil, false
+	}
+	// Hyphen range, only when the first term has no operator
+	//
+	if first.Op == "" && p.Match(TSpace, TDash, TSpace) {
+		p.Next()
+		p.Next()
+		p.Next()
+		to, ok := partial(p, true)
+		if !ok {
+			return nil, false
+		}
+		r.Terms = []Term{&Hyphen{pos: first.pos, From: first.Version, To: to}}
+		return r, true
+	}
+	r.Terms = append(r.Terms, first)
+	for p.Match(TSpace) && p.PeekIn(2, termStart) {
+		p.Next()
+		t, ok := term(p)
+		if !ok {
+			return nil, false
+		}
+		r.Terms = append(r.Terms, t)
+	}
+	return r, true
+}
+
+// term parses [ operator? partial ], allowing spaces after the operator.
+//
+func term(p *parser.Parser) (*Comparator, bool) {
+	c := &Comparator{pos: pos(p.Pos())}
+	if op, ok := p.Expect(operators.Types()...); ok {
+		c.Op = op.Value()
+		skipSpace(p)
+	}
+	var ok bool
+	c.Version, ok = partial(p, true)
+	return c, ok
+}
+
+// partial parses [ part ( '.' part ( '.' part prerelease? build? )? )? ].
+// Parts following a wildcard must also be wildcards.
+//
+func partial(p *parser.Parser, wildcards bool) (*Partial, bool) {
+	v := &Partial{pos: pos(p.Pos()), Major: Wildcard, Minor: Wildcard, Patch: Wildcard}
+	expected := parts
+	if !wildcards {
+		expected = token.NewSet(TNumber)
+	}
+	for i, part := range []*int{&v.Major, &v.Minor, &v.Patch} {
+		if i > 0 {
+			if _, ok := p.Expect(TDot); !ok {
+				return v, true
+			}
+		}
+		tok, ok := p.Expect(expected.Types()...)
+		if !ok {
+			return nil, false
+		}
+		if tok.Type() == TWildcard {
+			expected = token.NewSet(TWildcard)
+			continue
+		}
+		*part = token.Cooked(tok).(int)
+	}
+	if !v.full() {
+		return v, true
+	}
+	ok := true
+	if _, dash := p.Expect(TDash); dash {
+		if v.Pre, ok = identifiers(p); !ok {
+			return nil, false
+		}
+	}
+	if _, plus := p.Expect(TPlus); plus {
+		v.Build, ok = identifiers(p)
+	}
+	return v, ok
+}
+
+// identifiers parses [ ident ( '.' ident )* ].
+//
+func identifiers(p *parser.Parser) ([]string, bool) {
+	var ids []string
+	for {
+		tok, ok := p.Expect(idents.Types()...)
+		if !ok {
+			return nil, false
+		}
+		ids = append(ids, tok.Value())
+		if _, ok = p.Expect(TDot); !ok {
+			return ids, true
+		}
+	}
+}
+
+// skipSpace skips the next token if it is a space.
+//
+func skipSpace(p *parser.Parser) {
+	p.AcceptIn(spaces)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"testing"
+)
+
+// TestParseConstraint
+//
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`1.2.3`, `1.2.3`},
+		{`  >=1.2.x <2.0.0 || 3.*  `, `>=1.2.x <2.0.0 || 3.x.x`},
+		{`*`, `x.x.x`},
+		{` `, `x.x.x`},
+		{`>= 1 ||<2`, `>=1.x.x || <2.x.x`},
+		{`~1.2 ^0.0.1-rc.1+b.01`, `~1.2.x ^0.0.1-rc.1+b.01`},
+		{`1.2 - 2.3.4-beta`, `1.2.x - 2.3.4-beta`},
+		{`1.0.0-alpha-1.x`, `1.0.0-alpha-1.x`},
+	}
+	for _, test := range tests {
+		c, err := ParseConstraint(test.input)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q) unexpected error: %v", test.input, err)
+			continue
+		}
+		if c.String() != test.expected {
+			t.Errorf("ParseConstraint(%q) expecting %q, received %q", test.input, test.expected, c.String())
+		}
+	}
+}
+
+// TestAST
+//
+func TestAST(t *testing.T) {
+	c, err := ParseConstraint("1 - 2 || >=3.1")
+	if err != nil {
+		t.Fatalf("ParseConstraint() unexpected error: %v", err)
+	}
+	if len(c.Ranges) != 2 {
+		t.Fatalf("ParseConstraint() expecting 2 ranges, received %d", len(c.Ranges))
+	}
+	h, ok := c.Ranges[0].Terms[0].(*Hyphen)
+	if !ok || h.From.Major != 1 || h.From.Minor != Wildcard || h.To.Major != 2 {
+		t.Errorf("ParseConstraint() expecting hyphen 1 - 2, received %v", c.Ranges[0].Terms[0])
+	}
+	cmp, ok := c.Ranges[1].Terms[0].(*Comparator)
+	if !ok || cmp.Op != ">=" || cmp.Version.Minor != 1 {
+		t.Errorf("ParseConstraint() expecting comparator >=3.1, received %v", c.Ranges[1].Terms[0])
+	}
+	if pos := cmp.Pos().String(); pos != "1:10" {
+		t.Errorf("ParseConstraint() expecting comparator at 1:10, received %s", pos)
+	}
+}
+
+// TestMatches
+//
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		constraint string
+		matched    []string
+		unmatched  []string
+	}{
+		{`1.2.3`, []string{`1.2.3`, `1.2.3+build`}, []string{`1.2.4`, `1.2.3-rc`}},
+		{`1.2`, []string{`1.2.0`, `1.2.99`}, []string{`1.1.9`, `1.3.0`}},
+		{`*`, []string{`0.0.0`, `99.0.0`}, nil},
+		{`>=1.2.x <2.0.0 || 3.*`, []string{`1.2.0`, `1.9.9-beta`, `3.4.5`}, []string{`1.1.0`, `2.0.0`, `4.0.0`}},
+		{`>1.2`, []string{`1.3.0`}, []string{`1.2.9`}},
+		{`>1.2.3`, []string{`1.2.4`}, []string{`1.2.3`}},
+		{`<=1.2`, []string{`1.2.9`}, []string{`1.3.0`}},
+		{`<1.2`, []string{`1.1.9`}, []string{`1.2.0`}},
+		{`~1.2.3`, []string{`1.2.3`, `1.2.9`}, []string{`1.2.2`, `1.3.0`}},
+		{`~1`, []string{`1.0.0`, `1.9.0`}, []string{`2.0.0`}},
+		{`^1.2.3`, []string{`1.2.3`, `1.9.0`}, []string{`1.2.2`, `2.0.0`}},
+		{`^0.2.3`, []string{`0.2.3`, `0.2.9`}, []string{`0.3.0`}},
+		{`^0.0.3`, []string{`0.0.3`}, []string{`0.0.4`}},
+		{`^0.0`, []string{`0.0.9`}, []string{`0.1.0`}},
+		{`1 - 2.3`, []string{`1.0.0`, `2.3.9`}, []string{`0.9.9`, `2.4.0`}},
+		{`1.2.3 - 2.3.4`, []string{`2.3.4`}, []string{`2.3.5`}},
+		{`>=1.0.0-alpha`, []string{`1.0.0-alpha`, `1.0.0-alpha.1`, `1.0.0-beta`, `1.0.0`}, []string{`1.0.0-1`}},
+	}
+	for _, test := range tests {
+		c, err := ParseConstraint(test.constraint)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q) unexpected error: %v", test.constraint, err)
+			continue
+		}
+		for _, list := range []struct {
+			versions []string
+			expected bool
+		}{{test.matched, true}, {test.unmatched, false}} {
+			for _, version := range list.versions {
+				v, err := ParseVersion(version)
+				if err != nil {
+					t.Errorf("ParseVersion(%q) unexpected error: %v", version, err)
+					continue
+				}
+				if c.Matches(v) != list.expected {
+					t.Errorf("%q.Matches(%q) expecting %v", test.constraint, version, list.expected)
+				}
+			}
+		}
+	}
+}
+
+// TestCompare
+//
+func TestCompare(t *testing.T) {
+	ordered := []string{
+		`1.0.0-alpha`, `1.0.0-alpha.1`, `1.0.0-alpha.beta`, `1.0.0-beta`, `1.0.0-beta.2`, `1.0.0-beta.11`,
+		`1.0.0-rc.1`, `1.0.0`, `1.0.1`, `1.1.0`, `2.0.0`,
+	}
+	for i := 1; i < len(ordered); i++ {
+		a, _ := ParseVersion(ordered[i-1])
+		b, _ := ParseVersion(ordered[i])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("Compare() expecting %s < %s", a, b)
+		}
+	}
+	a, _ := ParseVersion("1.0.0+a")
+	b, _ := ParseVersion("1.0.0+b")
+	if a.Compare(b) != 0 {
+		t.Errorf("Compare() expecting build metadata to be ignored")
+	}
+}
+
+// TestErrors
+//
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`<1 >`, "1:5: expected number or wildcard, found end of input"},
+		{`>=`, "1:3: expected number or wildcard, found end of input"},
+		{`1.x.2`, "1:5: expected wildcard, found number '2'"},
+		{`1.2.3-`, "1:7: expected number or identifier, found end of input"},
+		{`1.2.3 ||`, "1:9: expected number or wildcard or '=' or '<' or '<=' or '>' or '>=' or '~' or '^', found end of input"},
+		{`1.2 - `, "1:7: expected number or wildcard, found end of input"},
+		{`1.2 &`, "1:5: unexpected character"},
+		{`01.2`, "1:1: number has leading zero"},
+		{`99999999999999999999`, "1:1: number out of range"},
+	}
+	for _, test := range tests {
+		_, err := ParseConstraint(test.input)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("ParseConstraint(%q) expecting error '%s', received '%v'", test.input, test.expected, err)
+		}
+	}
+}
+
+// TestParseVersion
+//
+func TestParseVersion(t *testing.T) {
+	v, err := ParseVersion("1.2.3-beta.1+build.5")
+	if err != nil {
+		t.Fatalf("ParseVersion() unexpected error: %v", err)
+	}
+	if v.Major != 1 || v.Minor != 2 || v.Patch != 3 || len(v.Pre) != 2 || len(v.Build) != 2 {
+		t.Errorf("ParseVersion() received %#v", v)
+	}
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{``, "1:1: empty input"},
+		{`1.2`, "1:4: expected '.', found end of input"},
+		{`1.x.0`, "1:3: expected number, found wildcard"},
+		{`>1.2.3`, "1:1: expected number, found '>'"},
+		{`1.2.3 `, "1:6: expected '-' or '+', found space"},
+	}
+	for _, test := range tests {
+		_, err := ParseVersion(test.input)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("ParseVersion(%q) expecting error '%s', received '%v'", test.input, test.expected, err)
+		}
+	}
+}
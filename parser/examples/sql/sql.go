@@ -0,0 +1,891 @@
+package main
+
+//
+//	Input is read from STDIN
+//
+//	The input is a script of SELECT statements, separated by ';'.
+//	Each statement is printed in a canonical form, with keywords upper-cased and binary expressions parenthesized:
+//
+//	$ echo "select name, count(*) as n from users u where not u.age < 18 and u.name <> 'bob' order by n desc" | sql
+//	1:1: SELECT name, count(*) AS n FROM users AS u WHERE ((NOT (u.age < 18)) AND (u.name <> 'bob')) ORDER BY n DESC
+//
+//	The statements are matched against the following pattern:
+//
+//	script    : statement? ( ';' statement? )*
+//	statement : SELECT DISTINCT? columns FROM table ( WHERE expr )? ( ORDER BY order ( ',' order )* )? ( LIMIT NUMBER )?
+//	columns   : '*' | expr alias? ( ',' expr alias? )*
+//	table     : IDENT alias?
+//	alias     : AS? IDENT
+//	order     : expr ( ASC | DESC )?
+//	expr      : operand ( op operand )*
+//	operand   : NOT expr | '-' operand | NUMBER | STRING | NULL | TRUE | FALSE | '(' expr ')'
+//	          | IDENT ( '.' IDENT )? | IDENT '(' ( '*' | ( expr ( ',' expr )* )? ) ')'
+//
+//	Keywords are case-insensitive; Identifiers can be double-quoted, i.e. "order", to use keywords as names.
+//	Operator precedence, from loosest to tightest, is:
+//
+//	OR
+//	AND
+//	NOT (applies to the comparison that follows it)
+//	=  <>  !=  <  <=  >  >=
+//	+  -
+//	*  /
+//
+//	Errors do not stop the parse: The parser recovers at the next ';', so all of the statements are checked, and all
+//	of the errors are reported in input order.
+//
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// We define our lexer tokens starting from the pre-defined START token
+//
+const (
+	TIdent  token.Type = lexer.TStart + iota // Cooked value is the name, unquoted
+	TNumber                                  // Integer or decimal
+	TString                                  // Cooked value is the string, unquoted
+	TComma
+	TDot
+	TStar // Select-all, count(*) and multiply
+	TOpen
+	TClose
+	TSemi
+	TPlus
+	TMinus
+	TSlash
+	TEq
+	TNe
+	TLt
+	TLe
+	TGt
+	TGe
+	TSelect // Keywords follow
+	TDistinct
+	TFrom
+	TWhere
+	TOrder
+	TBy
+	TAsc
+	TDesc
+	TLimit
+	TAs
+	TAnd
+	TOr
+	TNot
+	TNull
+	TTrue
+	TFalse
+)
+
+// keywords maps the upper-cased keywords to their token types.
+//
+var keywords = map[string]token.Type{
+	"SELECT":   TSelect,
+	"DISTINCT": TDistinct,
+	"FROM":     TFrom,
+	"WHERE":    TWhere,
+	"ORDER":    TOrder,
+	"BY":       TBy,
+	"ASC":      TAsc,
+	"DESC":     TDesc,
+	"LIMIT":    TLimit,
+	"AS":       TAs,
+	"AND":      TAnd,
+	"OR":       TOr,
+	"NOT":      TNot,
+	"NULL":     TNull,
+	"TRUE":     TTrue,
+	"FALSE":    TFalse,
+}
+
+// symbols maps the punctuation and operators to their token types.
+//
+var symbols = map[string]token.Type{
+	",":  TComma,
+	".":  TDot,
+	"*":  TStar,
+	"(":  TOpen,
+	")":  TClose,
+	";":  TSemi,
+	"+":  TPlus,
+	"-":  TMinus,
+	"/":  TSlash,
+	"=":  TEq,
+	"<>": TNe,
+	"!=": TNe,
+	"<":  TLt,
+	"<=": TLe,
+	">":  TGt,
+	">=": TGe,
+}
+
+// Token names, for error messages; Keywords are named by their spelling
+//
+var tokenNames = map[token.Type]string{
+	TIdent:  "identifier",
+	TNumber: "number",
+	TString: "string",
+	TComma:  "','",
+	TDot:    "'.'",
+	TStar:   "'*'",
+	TOpen:   "'('",
+	TClose:  "')'",
+	TSemi:   "';'",
+	TPlus:   "'+'",
+	TMinus:  "'-'",
+	TSlash:  "'/'",
+	TEq:     "'='",
+	TNe:     "'<>'",
+	TLt:     "'<'",
+	TLe:     "'<='",
+	TGt:     "'>'",
+	TGe:     "'>='",
+}
+
+// MaxErrors is the number of errors reported before giving up.
+//
+const MaxErrors = 10
+
+// MaxDepth is the nesting limit for expressions.
+//
+const MaxDepth = 200
+
+// main
+//
+func main() {
+	input, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		panic(err)
+	}
+	statements, errs := Parse(string(input))
+	for _, s := range statements {
+		fmt.Printf("%s: %s\n", s.Pos(), s)
+	}
+	for _, err := range errs {
+		fmt.Println(err)
+	}
+	if len(errs) > 0 {
+		os.Exit(1)
+	}
+}
+
+// Parse parses the script, returning the valid statements, along with the errors found in the invalid ones, if any.
+// Errors are sorted by position.
+//
+func Parse(input string) ([]*Select, []error) {
+	tokens := lexer.LexString(input, lexSQL)
+	nexter := parser.Parse(tokens, parseScript, parser.WithMaxErrors(MaxErrors), parser.WithMaxDepth(MaxDepth),
+		parser.WithLexErrors())
+	var statements []*Select
+	var errs []error
+	var lexPos token.Position // Position of the last lexer error
+	for {
+		ast, err := nexter.Next()
+		if err == io.EOF {
+			break
+		}
+		if e, ok := err.(*lexer.Error); ok {
+			lexPos = e.Pos
+		}
+		if f, ok := err.(*parser.Failure); ok {
+			// Input truncated by a lexer error (i.e. an unterminated string) is already reported
+			//
+			if f.Found != nil || !truncated(f.Pos, lexPos) {
+				errs = append(errs, describe(f))
+			}
+		} else if err != nil {
+			errs = append(errs, err)
+		} else {
+			statements = append(statements, ast.(*Select))
+		}
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		a, aok := errPos(errs[i])
+		b, bok := errPos(errs[j])
+		return aok && (!bok || a.Line < b.Line || a.Line == b.Line && a.Column < b.Column)
+	})
+	return statements, errs
+}
+
+// Error is a syntax error.
+//
+type Error struct {
+	Pos     token.Position
+	Message string
+}
+
+// Error implements error, i.e. "1:8: expected FROM, found ';'".
+//
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// errPos returns the position of err, if known.
+//
+func errPos(err error) (token.Position, bool) {
+	switch e := err.(type) {
+	case *Error:
+		return e.Pos, true
+	case *lexer.Error:
+		return e.Pos, true
+	case *parser.DepthError:
+		return e.Pos, true
+	}
+	return token.Position{}, false
+}
+
+// describe rewords a parser failure with token names.
+//
+func describe(f *parser.Failure) error {
+	found := "end of input"
+	if f.Found != nil {
+		found = tokenName(f.Found.Type())
+		if t := f.Found.Type(); t == TIdent || t == TNumber || t == TString {
+			found += fmt.Sprintf(" '%s'", f.Found.Value())
+		}
+	}
+	if f.Expected.Len() == 0 {
+		return &Error{Pos: f.Pos, Message: "unexpected " + found}
+	}
+	var expected []string
+	for _, t := range f.Expected.Types() {
+		expected = append(expected, tokenName(t))
+	}
+	return &Error{Pos: f.Pos, Message: fmt.Sprintf("expected %s, found %s", strings.Join(expected, " or "), found)}
+}
+
+// tokenName returns the name of the token type, for error messages.
+//
+func tokenName(t token.Type) string {
+	if name, ok := tokenNames[t]; ok {
+		return name
+	}
+	return spell(t)
+}
+
+// spell returns the SQL text of the keyword or operator type t, i.e. "AND" or "<=".
+//
+func spell(t token.Type) string {
+	for k, v := range keywords {
+		if v == t {
+			return k
+		}
+	}
+	return strings.Trim(tokenNames[t], "'")
+}
+
+// truncated confirms if the end of the input, at pos, was reached before the lexer error at lexPos, i.e. if no
+// tokens followed the error.
+//
+func truncated(pos token.Position, lexPos token.Position) bool {
+	return pos.Line < lexPos.Line || pos.Line == lexPos.Line && pos.Column <= lexPos.Column
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// AST
+// ---------------------------------------------------------------------------------------------------------------------
+
+// pos holds the position of a node.
+//
+type pos token.Position
+
+// Pos returns the position of the node within the input.
+//
+func (p pos) Pos() token.Position {
+	return token.Position(p)
+}
+
+// Expr is implemented by all expressions.
+//
+type Expr interface {
+	fmt.Stringer
+	Pos() token.Position
+}
+
+// Select is a SELECT statement.
+//
+type Select struct {
+	pos
+	Distinct bool
+	Columns  []*Column // nil for '*'
+	From     *Table
+	Where    Expr // nil if none
+	OrderBy  []*Order
+	Limit    *Number // nil if none
+}
+
+// Column is a result column.
+//
+type Column struct {
+	pos
+	Expr  Expr
+	Alias string // "" if none
+}
+
+// Table is the table selected from.
+//
+type Table struct {
+	pos
+	Name  string
+	Alias string // "" if none
+}
+
+// Order is an ORDER BY term.
+//
+type Order struct {
+	pos
+	Expr Expr
+	Desc bool
+}
+
+// Binary is a binary operator expression, positioned at its operator.
+//
+type Binary struct {
+	pos
+	Op    token.Type
+	Left  Expr
+	Right Expr
+}
+
+// Unary is a NOT or '-' expression.
+//
+type Unary struct {
+	pos
+	Op token.Type
+	X  Expr
+}
+
+// Ref is a column reference, i.e. "name" or "u.name".
+//
+type Ref struct {
+	pos
+	Table string // "" if unqualified
+	Name  string
+}
+
+// Call is a function call, i.e. "count(*)".
+//
+type Call struct {
+	pos
+	Name string
+	Star bool // Called with '*'
+	Args []Expr
+}
+
+// Number is a numeric literal.
+//
+type Number struct {
+	pos
+	Text string
+}
+
+// String is a string literal.
+//
+type String struct {
+	pos
+	Value string
+}
+
+// Literal is NULL, TRUE or FALSE.
+//
+type Literal struct {
+	pos
+	Type token.Type
+}
+
+// String implements fmt.Stringer, returning the statement in canonical form.
+//
+func (s *Select) String() string {
+	b := &strings.Builder{}
+	b.WriteString("SELECT ")
+	if s.Distinct {
+		b.WriteString("DISTINCT ")
+	}
+	if s.Columns == nil {
+		b.WriteString("*")
+	}
+	for i, c := range s.Columns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(c.String())
+	}
+	b.WriteString(" FROM " + s.From.String())
+	if s.Where != nil {
+		b.WriteString(" WHERE " + s.Where.String())
+	}
+	for i, o := range s.OrderBy {
+		if i == 0 {
+			b.WriteString(" ORDER BY ")
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(o.String())
+	}
+	if s.Limit != nil {
+		b.WriteString(" LIMIT " + s.Limit.String())
+	}
+	return b.String()
+}
+
+// String implements fmt.Stringer.
+//
+func (c *Column) String() string {
+	if c.Alias == "" {
+		return c.Expr.String()
+	}
+	return c.Expr.String() + " AS " + quoteIdent(c.Alias)
+}
+
+// String implements fmt.Stringer.
+//
+func (t *Table) String() string {
+	if t.Alias == "" {
+		return quoteIdent(t.Name)
+	}
+	return quoteIdent(t.Name) + " AS " + quoteIdent(t.Alias)
+}
+
+// String implements fmt.Stringer.
+//
+func (o *Order) String() string {
+	if o.Desc {
+		return o.Expr.String() + " DESC"
+	}
+	return o.Expr.String()
+}
+
+// String implements fmt.Stringer.
+//
+func (b *Binary) String() string {
+	return fmt.Sprintf("(%s %s %s)", b.Left, spell(b.Op), b.Right)
+}
+
+// String implements fmt.Stringer.
+//
+func (u *Unary) String() string {
+	if u.Op == TNot {
+		return fmt.Sprintf("(NOT %s)", u.X)
+	}
+	return "-" + u.X.String()
+}
+
+// String implements fmt.Stringer.
+//
+func (r *Ref) String() string {
+	if r.Table == "" {
+		return quoteIdent(r.Name)
+	}
+	return quoteIdent(r.Table) + "." + quoteIdent(r.Name)
+}
+
+// String implements fmt.Stringer.
+//
+func (c *Call) String() string {
+	if c.Star {
+		return quoteIdent(c.Name) + "(*)"
+	}
+	args := make([]string, len(c.Args))
+	for i, a := range c.Args {
+		args[i] = a.String()
+	}
+	return quoteIdent(c.Name) + "(" + strings.Join(args, ", ") + ")"
+}
+
+// String implements fmt.Stringer.
+//
+func (n *Number) String() string {
+	return n.Text
+}
+
+// String implements fmt.Stringer.
+//
+func (s *String) String() string {
+	return "'" + strings.Replace(s.Value, "'", "''", -1) + "'"
+}
+
+// String implements fmt.Stringer.
+//
+func (l *Literal) String() string {
+	return spell(l.Type)
+}
+
+// quoteIdent quotes the name if it is not a valid unquoted identifier, or if it is a keyword.
+//
+func quoteIdent(name string) string {
+	valid := name != ""
+	for i, r := range name {
+		if i == 0 && !lexer.IdentSQL.Start(r) || i > 0 && !lexer.IdentSQL.Part(r) {
+			valid = false
+		}
+	}
+	if _, keyword := keywords[strings.ToUpper(name)]; valid && !keyword {
+		return name
+	}
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Lexer
+// ---------------------------------------------------------------------------------------------------------------------
+
+// lexSQL
+//
+func lexSQL(l *lexer.Lexer) lexer.Fn {
+	switch r := l.Peek(1); {
+	case unicode.IsSpace(r):
+		l.Discard(unicode.IsSpace)
+	case r == '-' && l.CanPeek(2) && l.Peek(2) == '-':
+		l.Discard(func(r rune) bool { return r != '\n' }) // Comment
+	case r == '\'':
+		return lexString
+	case isDigit(r):
+		lexNumber(l)
+	case lexer.ScanIdent(l, lexer.IdentSQL):
+		// Quoted identifiers are never keywords
+		//
+		text := l.PeekToken()
+		if t, ok := keywords[strings.ToUpper(text)]; ok && text[0] != '"' {
+			l.EmitToken(t)
+		} else {
+			l.EmitCooked(TIdent, lexer.IdentSQL.Unquote(text))
+		}
+	case r == '"':
+		l.Next()
+		l.EmitErrorToken("unterminated quoted identifier")
+		return nil
+	default:
+		lexSymbol(l)
+	}
+	return lexSQL
+}
+
+// lexNumber lexes an integer or decimal.
+//
+func lexNumber(l *lexer.Lexer) {
+	for l.CanPeek(1) && isDigit(l.Peek(1)) {
+		l.Next()
+	}
+	if l.CanPeek(2) && l.Peek(1) == '.' && isDigit(l.Peek(2)) {
+		l.Next()
+		for l.CanPeek(1) && isDigit(l.Peek(1)) {
+			l.Next()
+		}
+	}
+	l.EmitToken(TNumber)
+}
+
+// lexSymbol lexes punctuation and operators, preferring two-rune operators, i.e. "<=".
+// Unknown runes are reported, and skipped, allowing lexing to continue.
+//
+func lexSymbol(l *lexer.Lexer) {
+	if l.CanPeek(2) {
+		if t, ok := symbols[string([]rune{l.Peek(1), l.Peek(2)})]; ok {
+			l.Next()
+			l.Next()
+			l.EmitToken(t)
+			return
+		}
+	}
+	if t, ok := symbols[string(l.Next())]; ok {
+		l.EmitToken(t)
+	} else {
+		l.EmitErrorToken("unexpected character")
+	}
+}
+
+// lexString lexes a single-quoted string, with doubled quotes as escaped quotes.
+//
+func lexString(l *lexer.Lexer) lexer.Fn {
+	text := &strings.Builder{}
+	m := l.Marker()
+	l.Next() // Opening quote
+	for {
+		switch {
+		case !l.CanPeek(1):
+			// Position the error at the opening quote
+			//
+			m.Apply()
+			l.Next()
+			l.EmitErrorToken("unterminated string")
+			return nil
+		case l.Peek(1) != '\'':
+			text.WriteRune(l.Next())
+		case l.CanPeek(2) && l.Peek(2) == '\'':
+			l.Next()
+			text.WriteRune(l.Next())
+		default:
+			l.Next() // Closing quote
+			l.EmitCooked(TString, text.String())
+			return lexSQL
+		}
+	}
+}
+
+// isDigit
+//
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Parser
+// ---------------------------------------------------------------------------------------------------------------------
+
+// errSyntax is returned by the grammar functions when a token is not matched; The details are recorded by the parser
+// (see Parser.Furthest).
+//
+var errSyntax = errors.New("syntax error")
+
+// operators defines the precedence of the binary operators, loosest first.
+// All operators are left-associative, i.e. a - b - c == (a - b) - c
+//
+var operators = []parser.Operator{
+	{Type: TOr, Prec: 1},
+	{Type: TAnd, Prec: 2},
+	{Type: TEq, Prec: 3},
+	{Type: TNe, Prec: 3},
+	{Type: TLt, Prec: 3},
+	{Type: TLe, Prec: 3},
+	{Type: TGt, Prec: 3},
+	{Type: TGe, Prec: 3},
+	{Type: TPlus, Prec: 4},
+	{Type: TMinus, Prec: 4},
+	{Type: TStar, Prec: 5},
+	{Type: TSlash, Prec: 5},
+}
+
+// notOperators are the operators binding tighter than NOT, i.e. NOT a = b == NOT (a = b)
+//
+var notOperators = operators[2:]
+
+// parseScript emits the next statement.
+// On error, the error is emitted, and the parser recovers by skipping to the end of the statement.
+//
+func parseScript(p *parser.Parser) parser.Fn {
+	// Empty statement
+	//
+	if p.PeekType(1) == TSemi {
+		p.Next()
+		p.Clear()
+		return parseScript
+	}
+	s, err := statement(p)
+	if err == nil {
+		if _, ok := p.Expect(TSemi); ok || !p.CanPeek(1) {
+			p.Emit(s)
+			return parseScript
+		}
+	}
+	p.EmitFurthest()
+	skipStatement(p)
+	return parseScript
+}
+
+// skipStatement discards the tokens up to, and including, the next ';'.
+//
+func skipStatement(p *parser.Parser) {
+	for p.CanPeek(1) {
+		if p.Next().Type() == TSemi {
+			break
+		}
+	}
+	p.Clear()
+}
+
+// statement parses a SELECT statement.
+//
+func statement(p *parser.Parser) (*Select, error) {
+	tok, ok := p.Expect(TSelect)
+	if !ok {
+		return nil, errSyntax
+	}
+	s := &Select{pos: pos(token.Start(tok))}
+	_, s.Distinct = p.Expect(TDistinct)
+	if _, ok = p.Expect(TStar); !ok {
+		for {
+			c, err := column(p)
+			if err != nil {
+				return nil, err
+			}
+			s.Columns = append(s.Columns, c)
+			if _, ok = p.Expect(TComma); !ok {
+				break
+			}
+		}
+	}
+	if _, ok = p.Expect(TFrom); !ok {
+		return nil, errSyntax
+	}
+	if tok, ok = p.Expect(TIdent); !ok {
+		return nil, errSyntax
+	}
+	s.From = &Table{pos: pos(token.Start(tok)), Name: token.Cooked(tok).(string)}
+	if s.From.Alias, ok = alias(p); !ok {
+		return nil, errSyntax
+	}
+	var err error
+	if _, ok = p.Expect(TWhere); ok {
+		if s.Where, err = expr(p); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok = p.Expect(TOrder); ok {
+		if _, ok = p.Expect(TBy); !ok {
+			return nil, errSyntax
+		}
+		for {
+			o := &Order{}
+			if o.Expr, err = expr(p); err != nil {
+				return nil, err
+			}
+			o.pos = pos(o.Expr.Pos())
+			if tok, ok = p.Expect(TAsc, TDesc); ok {
+				o.Desc = tok.Type() == TDesc
+			}
+			s.OrderBy = append(s.OrderBy, o)
+			if _, ok = p.Expect(TComma); !ok {
+				break
+			}
+		}
+	}
+	if _, ok = p.Expect(TLimit); ok {
+		if tok, ok = p.Expect(TNumber); !ok {
+			return nil, errSyntax
+		}
+		s.Limit = &Number{pos: pos(token.Start(tok)), Text: tok.Value()}
+	}
+	return s, nil
+}
+
+// column parses a result column.
+//
+func column(p *parser.Parser) (*Column, error) {
+	x, err := expr(p)
+	if err != nil {
+		return nil, err
+	}
+	c := &Column{pos: pos(x.Pos()), Expr: x}
+	var ok bool
+	if c.Alias, ok = alias(p); !ok {
+		return nil, errSyntax
+	}
+	return c, nil
+}
+
+// alias parses an optional alias, returning "" if none, or false if invalid.
+//
+func alias(p *parser.Parser) (string, bool) {
+	_, as := p.Expect(TAs)
+	if tok, ok := p.Expect(TIdent); ok {
+		return token.Cooked(tok).(string), true
+	}
+	return "", !as
+}
+
+// expr parses an expression, folding the operator chain according to the precedence of each operator.
+//
+func expr(p *parser.Parser) (Expr, error) {
+	x, err := p.ParseBinary(operand, operators, build)
+	if err != nil {
+		return nil, err
+	}
+	return x.(Expr), nil
+}
+
+// build builds a *Binary, for use with ParseBinary.
+//
+func build(op token.Token, left interface{}, right interface{}) (interface{}, error) {
+	return &Binary{pos: pos(token.Start(op)), Op: op.Type(), Left: left.(Expr), Right: right.(Expr)}, nil
+}
+
+// operand parses an operand, for use with ParseBinary.
+//
+func operand(p *parser.Parser) (interface{}, error) {
+	p.EnterRule("operand")
+	defer p.ExitRule()
+	tok, ok := p.Expect(TIdent, TNumber, TString, TNull, TTrue, TFalse, TNot, TMinus, TOpen)
+	if !ok {
+		return nil, errSyntax
+	}
+	at := pos(token.Start(tok))
+	switch tok.Type() {
+	case TIdent:
+		return ref(p, tok)
+	case TNumber:
+		return &Number{pos: at, Text: tok.Value()}, nil
+	case TString:
+		return &String{pos: at, Value: token.Cooked(tok).(string)}, nil
+	case TNot:
+		x, err := p.ParseBinary(operand, notOperators, build)
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{pos: at, Op: TNot, X: x.(Expr)}, nil
+	case TMinus:
+		x, err := operand(p)
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{pos: at, Op: TMinus, X: x.(Expr)}, nil
+	case TOpen:
+		x, err := expr(p)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok = p.Expect(TClose); !ok {
+			return nil, errSyntax
+		}
+		return x, nil
+	}
+	return &Literal{pos: at, Type: tok.Type()}, nil
+}
+
+// ref parses a column reference or function call, following its first identifier.
+//
+func ref(p *parser.Parser, tok token.Token) (Expr, error) {
+	at := pos(token.Start(tok))
+	name := token.Cooked(tok).(string)
+	if _, ok := p.Expect(TDot); ok {
+		if tok, ok = p.Expect(TIdent); !ok {
+			return nil, errSyntax
+		}
+		return &Ref{pos: at, Table: name, Name: token.Cooked(tok).(string)}, nil
+	}
+	if _, ok := p.Expect(TOpen); !ok {
+		return &Ref{pos: at, Name: name}, nil
+	}
+	c := &Call{pos: at, Name: name}
+	if _, ok := p.Expect(TStar); ok {
+		c.Star = true
+	} else if _, ok = p.Expect(TClose); ok {
+		return c, nil
+	} else {
+		for {
+			x, err := expr(p)
+			if err != nil {
+				return nil, err
+			}
+			c.Args = append(c.Args, x)
+			if _, ok = p.Expect(TComma); !ok {
+				break
+			}
+		}
+	}
+	if _, ok := p.Expect(TClose); !ok {
+		return nil, errSyntax
+	}
+	return c, nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// TestParse
+//
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{``, nil},
+		{`;; -- nothing`, nil},
+		{`SELECT * FROM t`, []string{`1:1: SELECT * FROM t`}},
+		{`select Distinct a, B as "Order" From t;`, []string{`1:1: SELECT DISTINCT a, B AS "Order" FROM t`}},
+		{`SELECT a FROM t; SELECT b FROM "select" s`, []string{`1:1: SELECT a FROM t`, `1:18: SELECT b FROM "select" AS s`}},
+		{"SELECT a + b * c - d / 2 FROM t", []string{`1:1: SELECT ((a + (b * c)) - (d / 2)) FROM t`}},
+		{"SELECT (a + b) * -c FROM t", []string{`1:1: SELECT ((a + b) * -c) FROM t`}},
+		{"SELECT a FROM t WHERE a = 1 OR b <> 2 AND NOT c != 3", []string{`1:1: SELECT a FROM t WHERE ((a = 1) OR ((b <> 2) AND (NOT (c <> 3))))`}},
+		{"SELECT a FROM t WHERE NOT a AND b", []string{`1:1: SELECT a FROM t WHERE ((NOT a) AND b)`}},
+		{"SELECT a FROM t WHERE x <= 1.5 AND y >= NULL OR z > TRUE AND w < false", []string{`1:1: SELECT a FROM t WHERE (((x <= 1.5) AND (y >= NULL)) OR ((z > TRUE) AND (w < FALSE)))`}},
+		{"SELECT count(*), max(t.a, 'it''s'), now() FROM t", []string{`1:1: SELECT count(*), max(t.a, 'it''s'), now() FROM t`}},
+		{"SELECT a FROM t ORDER BY a, b asc, c DESC LIMIT 10", []string{`1:1: SELECT a FROM t ORDER BY a, b, c DESC LIMIT 10`}},
+	}
+	for _, test := range tests {
+		statements, errs := Parse(test.input)
+		if len(errs) > 0 {
+			t.Errorf("Parse(%q) unexpected errors: %v", test.input, errs)
+			continue
+		}
+		var received []string
+		for _, s := range statements {
+			received = append(received, s.Pos().String()+": "+s.String())
+		}
+		if strings.Join(received, "\n") != strings.Join(test.expected, "\n") {
+			t.Errorf("Parse(%q) expecting %q, received %q", test.input, test.expected, received)
+		}
+	}
+}
+
+// TestParseErrors confirms that parsing recovers at the end of each invalid statement, reporting all of the errors.
+//
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		count    int
+		expected []string
+	}{
+		{`SELECT FROM t`, 0, []string{
+			"1:8: expected identifier or number or string or '*' or '(' or '-' or DISTINCT or NOT or NULL or TRUE or FALSE, found FROM",
+		}},
+		{`SELECT a b c FROM t; SELECT 1 FROM t; FROM t; SELECT a FROM t WHERE`, 1, []string{
+			"1:12: expected ',' or FROM, found identifier 'c'",
+			"1:39: expected SELECT, found FROM",
+			"1:68: expected identifier or number or string or '(' or '-' or NOT or NULL or TRUE or FALSE, found end of input",
+		}},
+		{`SELECT a FROM t ORDER a; SELECT (a FROM t; SELECT a AS FROM t`, 0, []string{
+			"1:23: expected BY, found identifier 'a'",
+			"1:36: expected '.' or '(' or ')', found FROM",
+			"1:56: expected identifier, found FROM",
+		}},
+		{`SELECT a # FROM t; SELECT "x FROM t`, 1, []string{
+			"1:10: unexpected character",
+			"1:27: unterminated quoted identifier",
+		}},
+		{`SELECT 'abc FROM t`, 0, []string{
+			"1:8: unterminated string",
+		}},
+	}
+	for _, test := range tests {
+		statements, errs := Parse(test.input)
+		if len(statements) != test.count {
+			t.Errorf("Parse(%q) expecting %d statements, received %d", test.input, test.count, len(statements))
+		}
+		var received []string
+		for _, err := range errs {
+			received = append(received, err.Error())
+		}
+		if strings.Join(received, "\n") != strings.Join(test.expected, "\n") {
+			t.Errorf("Parse(%q) expecting errors %q, received %q", test.input, test.expected, received)
+		}
+	}
+}
+
+// TestMaxErrors
+//
+func TestMaxErrors(t *testing.T) {
+	_, errs := Parse(strings.Repeat("SELECT;", MaxErrors+5))
+	if len(errs) != MaxErrors+1 || errs[MaxErrors].Error() != "too many errors" {
+		t.Errorf("Parse() expecting %d errors followed by 'too many errors', received %q", MaxErrors, errs)
+	}
+}
+
+// TestDepth
+//
+func TestDepth(t *testing.T) {
+	if _, errs := Parse("SELECT " + strings.Repeat("-(", 50) + "1" + strings.Repeat(")", 50) + " FROM t"); len(errs) > 0 {
+		t.Errorf("Parse() unexpected errors: %v", errs)
+	}
+	_, errs := Parse("SELECT " + strings.Repeat("NOT ", MaxDepth) + "a FROM t")
+	if len(errs) != 1 {
+		t.Fatalf("Parse() expecting 1 error, received %v", errs)
+	}
+	if _, ok := errs[0].(*parser.DepthError); !ok {
+		t.Errorf("Parse() expecting *parser.DepthError, received %T (%v)", errs[0], errs[0])
+	}
+}
@@ -0,0 +1,769 @@
+package main
+
+//
+//	Usage: template <template-file> [<data-file.json>]
+//
+//	Renders the template, with the JSON object in the data file (if any) as its data:
+//
+//	Hello {{ user.name | upper }}!
+//	{{ if user.admin }}You are an admin.{{ else }}You are a guest.{{ end }}
+//	{{ range item in items }}- {{ "{{ item.name }} costs {{ item.price }}" }}
+//	{{ end }}
+//
+//	The template is raw text, with {{ expr }} islands.
+//	Strings within islands can contain islands of their own, i.e. "Hi {{ name }}", which can contain strings of their
+//	own, and so on.
+//
+//	The lexer switches modes (text -> island -> string -> island -> ...) via a stack of lexer functions, stored in
+//	the lexer context, pushing the function to return to on entering a mode, and popping it on leaving the mode.
+//
+//	The grammar:
+//
+//	template : nodes
+//	nodes    : ( TEXT | tag )*
+//	tag      : '{{' ( if | range | expr '}}' )
+//	if       : 'if' expr '}}' nodes ( '{{' 'else' '}}' nodes )? '{{' 'end' '}}'
+//	range    : 'range' IDENT 'in' expr '}}' nodes '{{' 'end' '}}'
+//	expr     : operand ( ( '==' | '!=' ) operand )? ( '|' IDENT )*
+//	operand  : path | NUMBER | string
+//	path     : IDENT ( '.' IDENT )*
+//	string   : '"' nodes '"'
+//
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// We define our lexer tokens starting from the pre-defined START token
+//
+const (
+	TText   token.Type = lexer.TStart + iota // Cooked value is the text, with escapes removed
+	TOpen                                    // '{{'
+	TClose                                   // '}}'
+	TQuote                                   // '"'
+	TIdent                                   // Letters, digits and '_', starting with a letter or '_'
+	TNumber                                  // Cooked value is the float64 value
+	TDot                                     // '.'
+	TPipe                                    // '|'
+	TEq                                      // '=='
+	TNe                                      // '!='
+	TIf                                      // 'if'
+	TElse                                    // 'else'
+	TEnd                                     // 'end'
+	TRange                                   // 'range'
+	TIn                                      // 'in'
+)
+
+// Keyword tokens
+//
+var keywords = map[string]token.Type{
+	"if":    TIf,
+	"else":  TElse,
+	"end":   TEnd,
+	"range": TRange,
+	"in":    TIn,
+}
+
+// Token names, for error messages
+//
+var tokenNames = map[token.Type]string{
+	TText:   "text",
+	TOpen:   "'{{'",
+	TClose:  "'}}'",
+	TQuote:  "'\"'",
+	TIdent:  "identifier",
+	TNumber: "number",
+	TDot:    "'.'",
+	TPipe:   "'|'",
+	TEq:     "'=='",
+	TNe:     "'!='",
+	TIf:     "'if'",
+	TElse:   "'else'",
+	TEnd:    "'end'",
+	TRange:  "'range'",
+	TIn:     "'in'",
+}
+
+// main
+//
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Printf("usage: %s <template-file> [<data-file.json>]\n", os.Args[0])
+		return
+	}
+	input, err := ioutil.ReadFile(os.Args[1])
+	if err != nil {
+		panic(err)
+	}
+	data := map[string]interface{}{}
+	if len(os.Args) > 2 {
+		var b []byte
+		if b, err = ioutil.ReadFile(os.Args[2]); err == nil {
+			err = json.Unmarshal(b, &data)
+		}
+		if err != nil {
+			panic(err)
+		}
+	}
+	t, err := Parse(string(input))
+	if err == nil {
+		var out string
+		if out, err = t.Render(data); err == nil {
+			fmt.Print(out)
+			return
+		}
+	}
+	fmt.Printf("%s:%v\n", os.Args[1], err)
+	os.Exit(1)
+}
+
+// Template is a parsed template.
+//
+type Template struct {
+	Nodes []Node
+}
+
+// Parse parses the template.
+// Returns a *Error if the template is invalid.
+//
+func Parse(input string) (*Template, error) {
+	tokens := lexer.LexString(input, lexText, lexer.WithContext(&modes{}))
+	ast, err := parser.Parse(tokens, parseTemplate, parser.WithLexErrors()).Next()
+	if err == io.EOF {
+		return &Template{}, nil // Empty template
+	}
+	if err != nil {
+		return nil, newError(err)
+	}
+	return ast.(*Template), nil
+}
+
+// Error describes an invalid template, or a failure while rendering it.
+//
+type Error struct {
+	Pos     token.Position // Position of the error
+	Message string         // The error message, i.e. "expected '}}', found identifier"
+}
+
+// Error implements error, i.e. "1:5: expected '}}', found identifier".
+//
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// newError converts a lexer or parser error into an Error.
+//
+func newError(err error) *Error {
+	switch e := err.(type) {
+	case *lexer.Error:
+		return &Error{Pos: e.Pos, Message: e.Message}
+	case *parser.Failure:
+		found := "end of input"
+		if e.Found != nil {
+			found = tokenNames[e.Found.Type()]
+		}
+		var expected []string
+		for _, t := range e.Expected.Types() {
+			expected = append(expected, tokenNames[t])
+		}
+		if len(expected) == 0 {
+			return &Error{Pos: e.Pos, Message: "unexpected " + found}
+		}
+		return &Error{Pos: e.Pos, Message: fmt.Sprintf("expected %s, found %s", strings.Join(expected, " or "), found)}
+	}
+	return &Error{Message: err.Error()}
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Lexer
+// ---------------------------------------------------------------------------------------------------------------------
+
+// modes is the stack of lexer functions to return to when leaving the current mode, stored in the lexer context.
+//
+type modes []lexer.Fn
+
+// pushFn enters the mode lexed by fn, returning to ret when the mode is left (see popFn).
+// Returns fn.
+//
+func pushFn(l *lexer.Lexer, ret lexer.Fn, fn lexer.Fn) lexer.Fn {
+	stack := l.Context().(*modes)
+	*stack = append(*stack, ret)
+	return fn
+}
+
+// popFn leaves the current mode, returning the function to return to (see pushFn).
+//
+func popFn(l *lexer.Lexer) lexer.Fn {
+	stack := l.Context().(*modes)
+	fn := (*stack)[len(*stack)-1]
+	*stack = (*stack)[:len(*stack)-1]
+	return fn
+}
+
+// lexText lexes raw text, up to an island.
+//
+func lexText(l *lexer.Lexer) lexer.Fn {
+	text := &strings.Builder{}
+	for l.CanPeek(1) && !isOpen(l) {
+		text.WriteRune(l.Next())
+	}
+	if text.Len() > 0 {
+		l.EmitCooked(TText, text.String())
+	}
+	if !l.CanPeek(1) {
+		return nil
+	}
+	l.Next()
+	l.Next()
+	l.EmitToken(TOpen)
+	return pushFn(l, lexText, lexIsland)
+}
+
+// lexString lexes the text of a string, up to an island or the closing quote.
+// Backslash escapes the next rune.
+//
+func lexString(l *lexer.Lexer) lexer.Fn {
+	text := &strings.Builder{}
+	for l.CanPeek(1) && !isOpen(l) && l.Peek(1) != '"' {
+		r := l.Next()
+		if r == '\\' && l.CanPeek(1) {
+			r = l.Next()
+		}
+		text.WriteRune(r)
+	}
+	if text.Len() > 0 {
+		l.EmitCooked(TText, text.String())
+	}
+	switch {
+	case !l.CanPeek(1):
+		l.EmitError("unterminated string")
+		return nil
+	case l.Peek(1) == '"':
+		l.Next()
+		l.EmitToken(TQuote)
+		return popFn(l)
+	}
+	l.Next()
+	l.Next()
+	l.EmitToken(TOpen)
+	return pushFn(l, lexString, lexIsland)
+}
+
+// lexIsland lexes the inside of an island, up to and including the closing '}}'.
+//
+func lexIsland(l *lexer.Lexer) lexer.Fn {
+	r := l.Peek(1)
+	switch {
+	case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+		l.Discard(func(r rune) bool { return r == ' ' || r == '\t' || r == '\n' || r == '\r' })
+	case r == '}' && l.CanPeek(2) && l.Peek(2) == '}':
+		l.Next()
+		l.Next()
+		l.EmitToken(TClose)
+		return popFn(l)
+	case r == '"':
+		l.Next()
+		l.EmitToken(TQuote)
+		return pushFn(l, lexIsland, lexString)
+	case lexer.ScanIdent(l, lexer.IdentC):
+		if t, ok := keywords[l.PeekToken()]; ok {
+			l.EmitToken(t)
+		} else {
+			l.EmitToken(TIdent)
+		}
+	case r >= '0' && r <= '9':
+		for l.CanPeek(1) && (l.Peek(1) >= '0' && l.Peek(1) <= '9' || l.Peek(1) == '.') {
+			l.Next()
+		}
+		f, err := strconv.ParseFloat(l.PeekToken(), 64)
+		if err != nil {
+			l.EmitErrorToken("invalid number")
+			return nil
+		}
+		l.EmitCooked(TNumber, f)
+	case r == '.':
+		l.Next()
+		l.EmitToken(TDot)
+	case r == '|':
+		l.Next()
+		l.EmitToken(TPipe)
+	case (r == '=' || r == '!') && l.CanPeek(2) && l.Peek(2) == '=':
+		l.Next()
+		l.Next()
+		if r == '=' {
+			l.EmitToken(TEq)
+		} else {
+			l.EmitToken(TNe)
+		}
+	default:
+		l.Next()
+		l.EmitErrorToken("unexpected character")
+		return nil
+	}
+	return lexIsland
+}
+
+// isOpen confirms if the next runes open an island.
+//
+func isOpen(l *lexer.Lexer) bool {
+	return l.Peek(1) == '{' && l.CanPeek(2) && l.Peek(2) == '{'
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// AST
+// ---------------------------------------------------------------------------------------------------------------------
+
+// Node is implemented by template nodes, and expressions.
+//
+type Node interface {
+
+	// Pos returns the position of the node within the template.
+	//
+	Pos() token.Position
+}
+
+// pos holds the position of a node.
+//
+type pos token.Position
+
+// Pos implements Node.Pos().
+//
+func (p pos) Pos() token.Position {
+	return token.Position(p)
+}
+
+// Text is raw text.
+//
+type Text struct {
+	pos
+	Text string
+}
+
+// Output is an island outputting the value of an expression, i.e. {{ name }}.
+//
+type Output struct {
+	pos
+	Expr Node
+}
+
+// If is a conditional block, i.e. {{ if x }}...{{ else }}...{{ end }}.
+//
+type If struct {
+	pos
+	Cond Node
+	Then []Node
+	Else []Node
+}
+
+// Range is a loop block, i.e. {{ range item in items }}...{{ end }}.
+//
+type Range struct {
+	pos
+	Var  string
+	Over Node
+	Body []Node
+}
+
+// Path is a (possibly dotted) name, i.e. user.name.
+//
+type Path struct {
+	pos
+	Names []string
+}
+
+// Number is a number literal.
+//
+type Number struct {
+	pos
+	Value float64
+}
+
+// String is a string literal, with its text and islands.
+//
+type String struct {
+	pos
+	Parts []Node
+}
+
+// Compare is a comparison, i.e. a == b.
+//
+type Compare struct {
+	pos
+	Op    token.Type // TEq or TNe
+	Left  Node
+	Right Node
+}
+
+// Filter applies a filter to an expression, i.e. name | upper.
+//
+type Filter struct {
+	pos
+	Name string
+	Expr Node
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Parser
+// ---------------------------------------------------------------------------------------------------------------------
+
+// parseTemplate parses the whole template.
+//
+func parseTemplate(p *parser.Parser) parser.Fn {
+	list, ok := nodes(p)
+	if ok && p.CanPeek(1) {
+		p.Expect() // Unexpected {{ else }} or {{ end }}
+		ok = false
+	}
+	if !ok {
+		p.EmitFurthest()
+		return nil
+	}
+	p.Emit(&Template{Nodes: list})
+	return nil
+}
+
+// nodes parses text and tags, up to the end of the input, a closing quote, or a {{ else }} or {{ end }} tag, which
+// is left for the caller to match.
+//
+func nodes(p *parser.Parser) ([]Node, bool) {
+	var list []Node
+	for p.CanPeek(1) {
+		switch p.PeekType(1) {
+		case TText:
+			tok := p.Next()
+			list = append(list, &Text{pos: start(tok), Text: token.Cooked(tok).(string)})
+			continue
+		case TOpen:
+		default:
+			return list, true
+		}
+		// Peek past the '{{', leaving {{ else }} and {{ end }} for the caller
+		//
+		m := p.Marker()
+		open := p.Next()
+		var n Node
+		ok := false
+		next, _ := p.TryPeekType(1)
+		switch next {
+		case TElse, TEnd:
+			m.Apply()
+			return list, true
+		case TIf:
+			n, ok = ifBlock(p, open)
+		case TRange:
+			n, ok = rangeBlock(p, open)
+		default:
+			var e Node
+			if e, ok = expr(p); ok {
+				n = &Output{pos: start(open), Expr: e}
+				_, ok = p.Expect(TClose)
+			}
+		}
+		if !ok {
+			return nil, false
+		}
+		list = append(list, n)
+	}
+	return list, true
+}
+
+// ifBlock parses an if block, following the opening '{{'.
+//
+func ifBlock(p *parser.Parser, open token.Token) (Node, bool) {
+	p.EnterRule("if")
+	defer p.ExitRule()
+	p.Next() // 'if'
+	n := &If{pos: start(open)}
+	var ok bool
+	if n.Cond, ok = expr(p); !ok {
+		return nil, false
+	}
+	if !expect(p, TClose) {
+		return nil, false
+	}
+	if n.Then, ok = nodes(p); !ok || !expect(p, TOpen) {
+		return nil, false
+	}
+	if _, isElse := p.Expect(TElse); isElse {
+		if !expect(p, TClose) {
+			return nil, false
+		}
+		if n.Else, ok = nodes(p); !ok || !expect(p, TOpen) {
+			return nil, false
+		}
+	}
+	return n, expect(p, TEnd, TClose)
+}
+
+// rangeBlock parses a range block, following the opening '{{'.
+//
+func rangeBlock(p *parser.Parser, open token.Token) (Node, bool) {
+	p.EnterRule("range")
+	defer p.ExitRule()
+	p.Next() // 'range'
+	n := &Range{pos: start(open)}
+	name, ok := p.Expect(TIdent)
+	if !ok || !expect(p, TIn) {
+		return nil, false
+	}
+	n.Var = name.Value()
+	if n.Over, ok = expr(p); !ok || !expect(p, TClose) {
+		return nil, false
+	}
+	if n.Body, ok = nodes(p); !ok {
+		return nil, false
+	}
+	return n, expect(p, TOpen, TEnd, TClose)
+}
+
+// expr parses an expression, with optional comparison and filters.
+//
+func expr(p *parser.Parser) (Node, bool) {
+	e, ok := operand(p)
+	if !ok {
+		return nil, false
+	}
+	if op, ok := p.Expect(TEq, TNe); ok {
+		right, ok := operand(p)
+		if !ok {
+			return nil, false
+		}
+		e = &Compare{pos: start(op), Op: op.Type(), Left: e, Right: right}
+	}
+	for {
+		if _, ok = p.Expect(TPipe); !ok {
+			return e, true
+		}
+		name, ok := p.Expect(TIdent)
+		if !ok {
+			return nil, false
+		}
+		e = &Filter{pos: start(name), Name: name.Value(), Expr: e}
+	}
+}
+
+// operand parses a path, number or string.
+//
+func operand(p *parser.Parser) (Node, bool) {
+	tok, ok := p.Expect(TIdent, TNumber, TQuote)
+	if !ok {
+		return nil, false
+	}
+	switch tok.Type() {
+	case TNumber:
+		return &Number{pos: start(tok), Value: token.Cooked(tok).(float64)}, true
+	case TQuote:
+		s := &String{pos: start(tok)}
+		if s.Parts, ok = nodes(p); !ok || !expect(p, TQuote) {
+			return nil, false
+		}
+		return s, true
+	}
+	path := &Path{pos: start(tok), Names: []string{tok.Value()}}
+	for {
+		if _, ok = p.Expect(TDot); !ok {
+			return path, true
+		}
+		name, ok := p.Expect(TIdent)
+		if !ok {
+			return nil, false
+		}
+		path.Names = append(path.Names, name.Value())
+	}
+}
+
+// expect matches the specified sequence of tokens, returning false if not matched (see Parser.Furthest).
+//
+func expect(p *parser.Parser, types ...token.Type) bool {
+	for _, t := range types {
+		if _, ok := p.Expect(t); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// start returns the position of tok.
+//
+func start(tok token.Token) pos {
+	return pos(token.Start(tok))
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Rendering
+// ---------------------------------------------------------------------------------------------------------------------
+
+// Render renders the template with the specified data, reassembling the raw text with the output of each island.
+// Returns a *Error if rendering fails, i.e. an undefined name.
+// Filters: upper, lower, trim, len.
+//
+func (t *Template) Render(data map[string]interface{}) (string, error) {
+	r := &renderer{scopes: []map[string]interface{}{data}}
+	if err := r.nodes(t.Nodes); err != nil {
+		return "", err
+	}
+	return r.out.String(), nil
+}
+
+// renderer holds the state of a render.
+//
+type renderer struct {
+	out    strings.Builder
+	scopes []map[string]interface{} // Variable scopes, innermost last
+}
+
+// nodes renders the nodes to the output.
+//
+func (r *renderer) nodes(list []Node) error {
+	for _, n := range list {
+		switch n := n.(type) {
+		case *Text:
+			r.out.WriteString(n.Text)
+		case *Output:
+			v, err := r.eval(n.Expr)
+			if err != nil {
+				return err
+			}
+			r.out.WriteString(format(v))
+		case *If:
+			v, err := r.eval(n.Cond)
+			if err != nil {
+				return err
+			}
+			branch := n.Else
+			if truthy(v) {
+				branch = n.Then
+			}
+			if err = r.nodes(branch); err != nil {
+				return err
+			}
+		case *Range:
+			v, err := r.eval(n.Over)
+			if err != nil {
+				return err
+			}
+			items, ok := v.([]interface{})
+			if !ok && v != nil {
+				return &Error{Pos: n.Over.Pos(), Message: fmt.Sprintf("cannot range over %s", format(v))}
+			}
+			for _, item := range items {
+				r.scopes = append(r.scopes, map[string]interface{}{n.Var: item})
+				err = r.nodes(n.Body)
+				r.scopes = r.scopes[:len(r.scopes)-1]
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// eval evaluates an expression.
+//
+func (r *renderer) eval(n Node) (interface{}, error) {
+	switch n := n.(type) {
+	case *Number:
+		return n.Value, nil
+	case *String:
+		// Render the parts of the string into a fresh output
+		//
+		sub := &renderer{scopes: r.scopes}
+		if err := sub.nodes(n.Parts); err != nil {
+			return nil, err
+		}
+		return sub.out.String(), nil
+	case *Path:
+		return r.lookup(n)
+	case *Compare:
+		left, err := r.eval(n.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := r.eval(n.Right)
+		if err != nil {
+			return nil, err
+		}
+		return (format(left) == format(right)) == (n.Op == TEq), nil
+	case *Filter:
+		v, err := r.eval(n.Expr)
+		if err != nil {
+			return nil, err
+		}
+		switch n.Name {
+		case "upper":
+			return strings.ToUpper(format(v)), nil
+		case "lower":
+			return strings.ToLower(format(v)), nil
+		case "trim":
+			return strings.TrimSpace(format(v)), nil
+		case "len":
+			if items, ok := v.([]interface{}); ok {
+				return float64(len(items)), nil
+			}
+			return float64(len([]rune(format(v)))), nil
+		}
+		return nil, &Error{Pos: n.Pos(), Message: fmt.Sprintf("unknown filter '%s'", n.Name)}
+	}
+	return nil, &Error{Pos: n.Pos(), Message: "invalid expression"}
+}
+
+// lookup resolves a path, searching the scopes from the innermost out for its first name.
+//
+func (r *renderer) lookup(path *Path) (interface{}, error) {
+	var v interface{}
+	found := false
+	for i := len(r.scopes) - 1; i >= 0 && !found; i-- {
+		v, found = r.scopes[i][path.Names[0]]
+	}
+	for i := 1; found && i < len(path.Names); i++ {
+		var m map[string]interface{}
+		if m, found = v.(map[string]interface{}); found {
+			v, found = m[path.Names[i]]
+		}
+	}
+	if !found {
+		return nil, &Error{Pos: path.Pos(), Message: fmt.Sprintf("undefined: %s", strings.Join(path.Names, "."))}
+	}
+	return v, nil
+}
+
+// format formats a value for output.
+//
+func format(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// truthy confirms if a value is true: Not nil, false, 0, "" or an empty list.
+//
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+)
+
+// testData is the data used to render the test templates.
+//
+var testData = map[string]interface{}{
+	"name":  "bob",
+	"admin": false,
+	"user":  map[string]interface{}{"name": "Ann", "age": float64(42)},
+	"items": []interface{}{"tea", "pie"},
+	"none":  []interface{}{},
+}
+
+// TestRender
+//
+func TestRender(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{``, ""},
+		{`plain { text } }}`, "plain { text } }}"},
+		{`Hi {{ name }}!`, "Hi bob!"},
+		{`{{user.name|upper}} is {{ user.age }}`, "ANN is 42"},
+		{`{{ "  x  " | trim | upper }}{{ items | len }}{{ 1.5 }}`, "X21.5"},
+		{`{{ if admin }}a{{ else }}b{{ end }}{{ if user }}c{{ end }}`, "bc"},
+		{`{{ if name == "bob" }}yes{{ end }}{{ if user.age != 42 }}no{{ end }}`, "yes"},
+		{`{{ range i in none }}x{{ end }}`, ""},
+		{`{{ range i in items }}[{{ i }}]{{ end }}`, "[tea][pie]"},
+		{`{{ range i in items }}{{ range j in items }}{{ i }}{{ j }} {{ end }}{{ end }}`, "teatea teapie pietea piepie "},
+		{`{{ "say \"{{ name }}\"" }}`, `say "bob"`},
+		{`{{ "a {{ "b {{ "c {{ user.name }}" }}" }}" }}`, "a b c Ann"},
+		{`{{ if "{{ admin }}" == "false" }}ok{{ end }}`, "ok"},
+	}
+	for _, test := range tests {
+		tmpl, err := Parse(test.input)
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", test.input, err)
+			continue
+		}
+		out, err := tmpl.Render(testData)
+		if err != nil {
+			t.Errorf("Render(%q) unexpected error: %v", test.input, err)
+		} else if out != test.expected {
+			t.Errorf("Render(%q) expecting %q, received %q", test.input, test.expected, out)
+		}
+	}
+}
+
+// TestParseError
+//
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`a {{ name`, "1:10: expected '}}' or '.' or '|' or '==' or '!=', found end of input"},
+		{`{{ if name }}a`, "1:15: expected '{{', found end of input"},
+		{`{{ if name }}a{{ else }}b{{ else }}`, "1:29: expected 'end', found 'else'"},
+		{`a{{ end }}`, "1:2: unexpected '{{'"},
+		{`{{ range in items }}{{ end }}`, "1:10: expected identifier, found 'in'"},
+		{`{{ "abc }}`, "1:11: unterminated string"},
+		{`{{ name # }}`, "1:9: unexpected character"},
+		{`{{ 1.2.3 }}`, "1:4: invalid number"},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.input)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("Parse(%q) expecting error '%s', received '%v'", test.input, test.expected, err)
+		}
+	}
+}
+
+// TestRenderError
+//
+func TestRenderError(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`ab {{ missing }}`, "1:7: undefined: missing"},
+		{`{{ user.missing }}`, "1:4: undefined: user.missing"},
+		{`{{ name.first }}`, "1:4: undefined: name.first"},
+		{"\n{{ name | nope }}", "2:11: unknown filter 'nope'"},
+		{`{{ range i in name }}{{ end }}`, "1:15: cannot range over bob"},
+		{`{{ "x {{ missing }}" }}`, "1:10: undefined: missing"},
+	}
+	for _, test := range tests {
+		tmpl, err := Parse(test.input)
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", test.input, err)
+			continue
+		}
+		_, err = tmpl.Render(testData)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("Render(%q) expecting error '%s', received '%v'", test.input, test.expected, err)
+		}
+	}
+}
+
+// TestModes confirms the lexer mode stack is unwound once the input is lexed.
+//
+func TestModes(t *testing.T) {
+	stack := &modes{}
+	if _, err := lexer.LexAll(`a {{ "b {{ "c {{ d }}" }}" }} e`, lexText, lexer.WithContext(stack)); err != nil {
+		t.Fatalf("LexAll() unexpected error: %v", err)
+	}
+	if len(*stack) != 0 {
+		t.Errorf("mode stack expecting empty, received %d entries", len(*stack))
+	}
+}
@@ -0,0 +1,746 @@
+package main
+
+//
+//	Usage: url <url>...
+//
+//	Each url is parsed into its components, or its errors are rendered with carets marking the offending text:
+//
+//	$ url 'http://user@example.com:8080/a%20b?q=1#top' 'http://ex ample.com:99999/a%zz'
+//	scheme:   http
+//	user:     user
+//	host:     example.com
+//	port:     8080
+//	path:     /a b
+//	query:    q=1
+//	fragment: top
+//
+//	error: invalid character ' ' in host
+//	 --> 1:10
+//	  |
+//	1 | http://ex ample.com:99999/a%zz
+//	  |          ^
+//
+//	error: port out of range
+//	 --> 1:21
+//	  |
+//	1 | http://ex ample.com:99999/a%zz
+//	  |                     ^^^^^
+//
+//	error: invalid percent-escape
+//	 --> 1:28
+//	  |
+//	1 | http://ex ample.com:99999/a%zz
+//	  |                            ^^^
+//
+//	URLs are matched against the following pattern (a subset of RFC 3986):
+//
+//	url       : scheme ':' ( '//' authority )? path ( '?' query )? ( '#' fragment )?
+//	authority : ( userinfo '@' )? host ( ':' port )?
+//	host      : '[' IPv6 ']' | IPv4 | reg-name
+//
+//	The lexer validates the characters and percent-escapes of each component, emitting errors that cover exactly the
+//	offending text, and carries on; The parser assembles the components, reporting structural errors (i.e. an IPv4
+//	address with too few parts) against the spans of the tokens involved.
+//	All errors are gathered by a diag.Collector shared by the lexer and the parser, and rendered by Render.
+//
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/diag"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// We define our lexer tokens starting from the pre-defined START token
+//
+const (
+	TScheme   token.Type = lexer.TStart + iota
+	TColon               // ':'
+	TSlashes             // '//'
+	TUserinfo            // Cooked value is the decoded text
+	TAt                  // '@'
+	THost                // Cooked value is the decoded text
+	TOctet               // IPv4 octet, cooked value is the int value (nil if out of range)
+	TDot                 // '.', within an IPv4 address
+	TIPv6                // Cooked value is the address, without brackets
+	TPort                // Cooked value is the int value
+	TPath                // Cooked value is the decoded text
+	TQuestion            // '?'
+	TQuery               // Cooked value is the raw text
+	THash                // '#'
+	TFragment            // Cooked value is the decoded text
+)
+
+// Token names, for error messages
+//
+var tokenNames = map[token.Type]string{
+	TScheme:   "scheme",
+	TColon:    "':'",
+	TSlashes:  "'//'",
+	TUserinfo: "userinfo",
+	TAt:       "'@'",
+	THost:     "host",
+	TOctet:    "IPv4 octet",
+	TDot:      "'.'",
+	TIPv6:     "IPv6 address",
+	TPort:     "port",
+	TPath:     "path",
+	TQuestion: "'?'",
+	TQuery:    "query",
+	THash:     "'#'",
+	TFragment: "fragment",
+}
+
+// Token classes
+//
+var (
+	userinfoTypes = token.NewSet(TUserinfo)
+	hostTypes     = token.NewSet(THost)
+	pathTypes     = token.NewSet(TPath)
+	queryTypes    = token.NewSet(TQuery)
+	fragmentTypes = token.NewSet(TFragment)
+	dots          = token.NewSet(TDot)
+)
+
+// main
+//
+func main() {
+	for i, arg := range os.Args[1:] {
+		if i > 0 {
+			fmt.Println()
+		}
+		u, diags := Parse(arg)
+		if len(diags) > 0 {
+			Render(os.Stdout, arg, diags)
+			continue
+		}
+		for _, c := range []struct{ name, value string }{
+			{"scheme", u.Scheme}, {"user", u.User}, {"host", u.Host}, {"port", u.Port},
+			{"path", u.Path}, {"query", u.RawQuery}, {"fragment", u.Fragment},
+		} {
+			if c.value != "" {
+				fmt.Printf("%-9s %s\n", c.name+":", c.value)
+			}
+		}
+	}
+}
+
+// URL is a parsed URL.
+// Components are decoded, except for the query, which is kept raw as its structure (i.e. "a=1&b=2") is application
+// specific.
+//
+type URL struct {
+	Scheme       string
+	HasAuthority bool   // True if the URL has an authority, i.e. "http://host", even if empty, i.e. "file:///"
+	User         string // Userinfo, i.e. "user:password"
+	Host         string // Host name, IPv4 address, or IPv6 address (without brackets)
+	Port         string
+	Path         string
+	RawQuery     string
+	Fragment     string
+}
+
+// String returns the URL, with components escaped as needed.
+// An empty query or fragment is omitted.
+//
+func (u *URL) String() string {
+	b := &strings.Builder{}
+	b.WriteString(u.Scheme + ":")
+	if u.HasAuthority {
+		b.WriteString("//")
+		if u.User != "" {
+			b.WriteString(escape(u.User, isUserinfo) + "@")
+		}
+		if strings.ContainsRune(u.Host, ':') {
+			b.WriteString("[" + u.Host + "]")
+		} else {
+			b.WriteString(escape(u.Host, isRegName))
+		}
+		if u.Port != "" {
+			b.WriteString(":" + u.Port)
+		}
+	}
+	b.WriteString(escape(u.Path, isPath))
+	if u.RawQuery != "" {
+		b.WriteString("?" + u.RawQuery)
+	}
+	if u.Fragment != "" {
+		b.WriteString("#" + escape(u.Fragment, isQuery))
+	}
+	return b.String()
+}
+
+// escape percent-escapes the bytes of s that are not allowed.
+//
+func escape(s string, allowed func(rune) bool) string {
+	b := &strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x80 && c != '%' && allowed(rune(c)) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// Parse parses the URL, returning the diagnostics reported, sorted by position.
+// Returns a nil URL if any errors are reported.
+//
+func Parse(input string) (*URL, []diag.Diagnostic) {
+	// The parser is never called without input
+	//
+	if input == "" {
+		start := token.Position{Line: 1, Column: 1}
+		d := diag.Diagnostic{Severity: diag.Error, Span: token.Span{Start: start, End: start}, Message: "empty URL"}
+		return nil, []diag.Diagnostic{d}
+	}
+	diags := diag.NewCollector()
+	tokens := &skipErrors{tokens: lexer.LexString(input, lexScheme, lexer.WithDiagnostics(diags))}
+	ast, _, _ := parser.ParseOne(tokens, parseURL, parser.WithDiagnostics(diags))
+	d := diags.Diagnostics()
+	sort.SliceStable(d, func(i, j int) bool {
+		a, b := d[i].Span.Start, d[j].Span.Start
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	if diags.HasErrors() {
+		return nil, d
+	}
+	return ast.(*URL), d
+}
+
+// skipErrors skips the errors emitted by the lexer, which are already recorded by the diagnostics collector.
+//
+type skipErrors struct {
+	tokens token.Nexter
+}
+
+// Next implements token.Nexter.Next().
+//
+func (n *skipErrors) Next() (token.Token, error) {
+	for {
+		tok, err := n.tokens.Next()
+		if err == nil || err == io.EOF {
+			return tok, err
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Renderer
+// ---------------------------------------------------------------------------------------------------------------------
+
+// Render writes the diagnostics to w, quoting the line of the input containing each, with carets marking the span:
+//
+//	error: invalid percent-escape
+//	 --> 1:21
+//	  |
+//	1 | http://example.com/a%zzb
+//	  |                     ^^^
+//
+// Spans covering no input are marked with a single caret, while spans continuing past the line are marked to its end.
+// Tabs preceding the span are kept, so that the carets line up with the quoted text.
+//
+func Render(w io.Writer, input string, diags []diag.Diagnostic) {
+	lines := strings.Split(input, "\n")
+	for i, d := range diags {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		start, end := d.Span.Start, d.Span.End
+		number := strconv.Itoa(start.Line)
+		gutter := strings.Repeat(" ", len(number))
+		fmt.Fprintf(w, "%s: %s\n", d.Severity, d.Message)
+		fmt.Fprintf(w, "%s--> %s\n", gutter, start)
+		if start.Line < 1 || start.Line > len(lines) {
+			continue
+		}
+		line := []rune(strings.TrimSuffix(lines[start.Line-1], "\r"))
+		// Columns are 1-based rune counts, with the end exclusive
+		//
+		from, to := start.Column-1, end.Column-1
+		if end.Line != start.Line || to > len(line) {
+			to = len(line)
+		}
+		if from > len(line) {
+			from = len(line)
+		}
+		if to <= from {
+			to = from + 1
+		}
+		indent := make([]rune, from)
+		for j := range indent {
+			indent[j] = ' '
+			if line[j] == '\t' {
+				indent[j] = '\t'
+			}
+		}
+		fmt.Fprintf(w, "%s |\n", gutter)
+		fmt.Fprintf(w, "%s | %s\n", number, string(line))
+		fmt.Fprintf(w, "%s | %s%s\n", gutter, string(indent), strings.Repeat("^", to-from))
+	}
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Lexer
+// ---------------------------------------------------------------------------------------------------------------------
+
+// component lexes a component of the URL, up to any of the end runes, validating its characters and percent-escapes.
+// Runs of valid text are emitted as separate tokens, around each escape and invalid character, allowing the errors to
+// cover exactly the offending text; The parser joins the runs.
+//
+type component struct {
+	name    string
+	typ     token.Type
+	allowed func(rune) bool
+	decode  bool     // Decode percent-escapes into the cooked values
+	end     string   // Runes ending the component
+	next    lexer.Fn // Lexes the rune ending the component
+}
+
+// Components
+//
+var (
+	userinfo = &component{name: "userinfo", typ: TUserinfo, allowed: isUserinfo, decode: true, end: "@"}
+	regName  = &component{name: "host", typ: THost, allowed: isRegName, decode: true, end: ":/?#"}
+	path     = &component{name: "path", typ: TPath, allowed: isPath, decode: true, end: "?#"}
+	query    = &component{name: "query", typ: TQuery, allowed: isQuery, end: "#"}
+	fragment = &component{name: "fragment", typ: TFragment, allowed: isQuery, decode: true}
+)
+
+// init links the components, avoiding initialization loops.
+//
+func init() {
+	userinfo.next = lexAt
+	regName.next = lexPort
+	path.next = lexQuery
+	query.next = lexFragment
+}
+
+// lex implements lexer.Fn.
+//
+func (c *component) lex(l *lexer.Lexer) lexer.Fn {
+	switch r := l.Peek(1); {
+	case strings.ContainsRune(c.end, r):
+		return c.next
+	case r == '%':
+		c.percent(l)
+	case c.allowed(r):
+		for l.CanPeek(1) && l.Peek(1) != '%' && c.allowed(l.Peek(1)) && !strings.ContainsRune(c.end, l.Peek(1)) {
+			l.Next()
+		}
+		l.EmitCooked(c.typ, l.PeekToken())
+	default:
+		l.Next()
+		l.EmitErrorToken(fmt.Sprintf("invalid character %q in %s", r, c.name))
+	}
+	return c.lex
+}
+
+// percent lexes a percent-escape, i.e. "%2F".
+// An invalid escape is reported covering the '%' and the (up to) two runes following it, stopping at the end of the
+// component.
+//
+func (c *component) percent(l *lexer.Lexer) {
+	l.Next() // '%'
+	valid := true
+	for i := 0; i < 2; i++ {
+		if !l.CanPeek(1) || strings.ContainsRune(c.end+"/?#", l.Peek(1)) {
+			valid = false
+			break
+		}
+		if !isHex(l.Next()) {
+			valid = false
+		}
+	}
+	if !valid {
+		l.EmitErrorToken("invalid percent-escape")
+		return
+	}
+	text := l.PeekToken()
+	if !c.decode {
+		l.EmitCooked(c.typ, text)
+		return
+	}
+	b, _ := strconv.ParseUint(text[1:], 16, 8)
+	l.EmitCooked(c.typ, string([]byte{byte(b)}))
+}
+
+// lexScheme lexes the scheme, and the ':' following it.
+// Errors in the scheme end the lexing, as the rest of the input cannot be reliably interpreted.
+//
+func lexScheme(l *lexer.Lexer) lexer.Fn {
+	if r := l.Peek(1); !isAlpha(r) {
+		l.Next()
+		l.EmitErrorToken("scheme must start with a letter")
+		return nil
+	}
+	for l.CanPeek(1) && isScheme(l.Peek(1)) {
+		l.Next()
+	}
+	switch {
+	case !l.CanPeek(1):
+		l.EmitErrorToken("missing scheme")
+		return nil
+	case l.Peek(1) != ':':
+		l.Clear()
+		r := l.Next()
+		l.EmitErrorToken(fmt.Sprintf("invalid character %q in scheme", r))
+		return nil
+	}
+	l.EmitToken(TScheme)
+	l.Next()
+	l.EmitToken(TColon)
+	return lexHier
+}
+
+// lexHier lexes the '//' introducing the authority, if present.
+//
+func lexHier(l *lexer.Lexer) lexer.Fn {
+	if l.CanPeek(2) && l.Peek(1) == '/' && l.Peek(2) == '/' {
+		l.Next()
+		l.Next()
+		l.EmitToken(TSlashes)
+		return lexAuthority
+	}
+	return path.lex
+}
+
+// lexAuthority looks ahead for the '@' ending the userinfo, if present.
+//
+func lexAuthority(l *lexer.Lexer) lexer.Fn {
+	for n := 1; l.CanPeek(n) && !strings.ContainsRune("/?#", l.Peek(n)); n++ {
+		if l.Peek(n) == '@' {
+			return userinfo.lex
+		}
+	}
+	return lexHost
+}
+
+// lexAt lexes the '@' ending the userinfo.
+//
+func lexAt(l *lexer.Lexer) lexer.Fn {
+	l.Next()
+	l.EmitToken(TAt)
+	return lexHost
+}
+
+// lexHost looks ahead to determine the form of the host.
+// Hosts consisting of only digits and dots are IPv4 addresses.
+//
+func lexHost(l *lexer.Lexer) lexer.Fn {
+	if l.Peek(1) == '[' {
+		return lexIPv6
+	}
+	n := 1
+	for l.CanPeek(n) && (isDigit(l.Peek(n)) || l.Peek(n) == '.') {
+		n++
+	}
+	if n > 1 && (!l.CanPeek(n) || strings.ContainsRune(":/?#", l.Peek(n))) {
+		return lexIPv4
+	}
+	return regName.lex
+}
+
+// lexIPv4 lexes the octets of an IPv4 address, and the dots separating them.
+//
+func lexIPv4(l *lexer.Lexer) lexer.Fn {
+	switch r := l.Peek(1); {
+	case r == '.':
+		l.Next()
+		l.EmitToken(TDot)
+	case isDigit(r):
+		for l.CanPeek(1) && isDigit(l.Peek(1)) {
+			l.Next()
+		}
+		n, err := strconv.Atoi(l.PeekToken())
+		if err != nil || n > 255 {
+			l.EmitErrorToken("IPv4 octet out of range")
+			// Stand in for the octet, so the parser does not report it as missing
+			//
+			l.EmitType(TOctet)
+			break
+		}
+		l.EmitCooked(TOctet, n)
+	default:
+		return lexPort
+	}
+	return lexIPv4
+}
+
+// lexIPv6 lexes a bracketed IPv6 address, i.e. "[::1]".
+//
+func lexIPv6(l *lexer.Lexer) lexer.Fn {
+	l.Next() // '['
+	for l.CanPeek(1) && !strings.ContainsRune("]/?#", l.Peek(1)) {
+		l.Next()
+	}
+	if !l.CanPeek(1) || l.Peek(1) != ']' {
+		l.EmitErrorToken("missing ']' in host")
+		return lexPort
+	}
+	l.Next()
+	text := l.PeekToken()
+	addr := text[1 : len(text)-1]
+	if ip := net.ParseIP(addr); ip == nil || !strings.ContainsRune(addr, ':') {
+		l.EmitErrorToken("invalid IPv6 address")
+		return lexPort
+	}
+	l.EmitCooked(TIPv6, addr)
+	return lexPort
+}
+
+// lexPort lexes the port, if present.
+// Any text following the host, other than the port, is reported as invalid.
+//
+func lexPort(l *lexer.Lexer) lexer.Fn {
+	if l.Peek(1) == ':' {
+		l.Next()
+		l.EmitToken(TColon)
+		for l.CanPeek(1) && isDigit(l.Peek(1)) {
+			l.Next()
+		}
+		if text := l.PeekToken(); text != "" {
+			n, err := strconv.Atoi(text)
+			if err != nil || n > 65535 {
+				l.EmitErrorToken("port out of range")
+			} else {
+				l.EmitCooked(TPort, n)
+			}
+		}
+	}
+	if l.CanPeek(1) && !strings.ContainsRune("/?#", l.Peek(1)) {
+		for l.CanPeek(1) && !strings.ContainsRune("/?#", l.Peek(1)) {
+			l.Next()
+		}
+		l.EmitErrorToken("invalid text after host")
+	}
+	if !l.CanPeek(1) {
+		return nil
+	}
+	return path.lex
+}
+
+// lexQuery lexes the '?' introducing the query, if present.
+//
+func lexQuery(l *lexer.Lexer) lexer.Fn {
+	if l.Peek(1) == '?' {
+		l.Next()
+		l.EmitToken(TQuestion)
+		return query.lex
+	}
+	return lexFragment
+}
+
+// lexFragment lexes the '#' introducing the fragment.
+//
+func lexFragment(l *lexer.Lexer) lexer.Fn {
+	l.Next()
+	l.EmitToken(THash)
+	return fragment.lex
+}
+
+// isAlpha
+//
+func isAlpha(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// isDigit
+//
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isHex
+//
+func isHex(r rune) bool {
+	return isDigit(r) || r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F'
+}
+
+// isScheme
+//
+func isScheme(r rune) bool {
+	return isAlpha(r) || isDigit(r) || r == '+' || r == '-' || r == '.'
+}
+
+// isUnreserved
+//
+func isUnreserved(r rune) bool {
+	return isAlpha(r) || isDigit(r) || strings.ContainsRune("-._~", r)
+}
+
+// isSubDelim
+//
+func isSubDelim(r rune) bool {
+	return strings.ContainsRune("!$&'()*+,;=", r)
+}
+
+// isRegName
+//
+func isRegName(r rune) bool {
+	return isUnreserved(r) || isSubDelim(r)
+}
+
+// isUserinfo
+//
+func isUserinfo(r rune) bool {
+	return isRegName(r) || r == ':'
+}
+
+// isPath
+//
+func isPath(r rune) bool {
+	return isUserinfo(r) || r == '@' || r == '/'
+}
+
+// isQuery, also used for fragments
+//
+func isQuery(r rune) bool {
+	return isPath(r) || r == '?'
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Parser
+// ---------------------------------------------------------------------------------------------------------------------
+
+// parseURL emits a *URL.
+//
+func parseURL(p *parser.Parser) parser.Fn {
+	u := &URL{}
+	if !parseScheme(p, u) || !parseAuthority(p, u) {
+		return nil
+	}
+	u.Path = join(p, pathTypes)
+	if _, ok := p.Expect(TQuestion); ok {
+		u.RawQuery = join(p, queryTypes)
+	}
+	if _, ok := p.Expect(THash); ok {
+		u.Fragment = join(p, fragmentTypes)
+	}
+	// Should be at end of input
+	//
+	if p.CanPeek(1) {
+		p.Expect()
+		fail(p)
+		return nil
+	}
+	p.Emit(u)
+	return nil
+}
+
+// parseScheme parses [ scheme ':' ].
+//
+func parseScheme(p *parser.Parser, u *URL) bool {
+	scheme, ok := p.Expect(TScheme)
+	if ok {
+		_, ok = p.Expect(TColon)
+	}
+	if !ok {
+		fail(p)
+		return false
+	}
+	u.Scheme = scheme.Value()
+	return true
+}
+
+// parseAuthority parses [ ( '//' ( userinfo '@' )? host ( ':' port )? )? ].
+//
+func parseAuthority(p *parser.Parser, u *URL) bool {
+	if _, ok := p.Expect(TSlashes); !ok {
+		return true
+	}
+	u.HasAuthority = true
+	if p.PeekIn(1, userinfoTypes) {
+		u.User = join(p, userinfoTypes)
+		if _, ok := p.Expect(TAt); !ok {
+			fail(p)
+			return false
+		}
+	}
+	switch {
+	case p.PeekIn(1, hostTypes):
+		u.Host = join(p, hostTypes)
+	case p.Match(TIPv6):
+		u.Host = token.Cooked(p.Next()).(string)
+	case p.Match(TOctet) || p.Match(TDot):
+		if !parseIPv4(p, u) {
+			return false
+		}
+	}
+	if _, ok := p.Expect(TColon); ok {
+		if port, ok := p.Expect(TPort); ok {
+			u.Port = port.Value()
+		}
+	}
+	return true
+}
+
+// parseIPv4 parses [ octet ( '.' octet )* ], confirming the address has 4 octets.
+//
+func parseIPv4(p *parser.Parser, u *URL) bool {
+	var first, last token.Token
+	var octets []string
+	for {
+		tok, ok := p.Expect(TOctet)
+		if !ok {
+			fail(p)
+			return false
+		}
+		if first == nil {
+			first = tok
+		}
+		last = tok
+		octets = append(octets, tok.Value())
+		if _, ok = p.AcceptIn(dots); !ok {
+			break
+		}
+	}
+	if len(octets) != 4 {
+		p.Diagnostics().Report(diag.Error, token.SpanOf(first, last), "IPv4 address must have 4 octets, found %d",
+			len(octets))
+		return false
+	}
+	u.Host = strings.Join(octets, ".")
+	return true
+}
+
+// join matches the consecutive tokens of a component, returning their joined cooked values.
+//
+func join(p *parser.Parser, types token.Set) string {
+	var s []string
+	for tok, ok := p.AcceptIn(types); ok; tok, ok = p.AcceptIn(types) {
+		s = append(s, token.Cooked(tok).(string))
+	}
+	return strings.Join(s, "")
+}
+
+// fail reports the furthest failure, with token names, i.e. "expected IPv4 octet, found '.'".
+// The failure covers the token found, or no input if at the end of the input.
+//
+func fail(p *parser.Parser) {
+	f := p.Furthest()
+	found := "end of input"
+	span := token.Span{Start: f.Pos, End: f.Pos}
+	if f.Found != nil {
+		found = tokenNames[f.Found.Type()]
+		span = token.SpanOf(f.Found, f.Found)
+	}
+	msg := "unexpected " + found
+	if f.Expected.Len() > 0 {
+		var expected []string
+		for _, t := range f.Expected.Types() {
+			expected = append(expected, tokenNames[t])
+		}
+		msg = fmt.Sprintf("expected %s, found %s", strings.Join(expected, " or "), found)
+	}
+	p.Diagnostics().Add(diag.Diagnostic{Severity: diag.Error, Span: span, Message: msg})
+}
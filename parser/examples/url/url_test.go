@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestParse
+//
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected URL
+		str      string
+	}{
+		{`http://example.com`, URL{Scheme: "http", HasAuthority: true, Host: "example.com"}, ``},
+		{`HTTPS://u%40x:pw@Example.com:8443/a%20b/c?q=%20&r=1#frag%21`,
+			URL{Scheme: "HTTPS", HasAuthority: true, User: "u@x:pw", Host: "Example.com", Port: "8443", Path: "/a b/c",
+				RawQuery: "q=%20&r=1", Fragment: "frag!"},
+			`HTTPS://u%40x:pw@Example.com:8443/a%20b/c?q=%20&r=1#frag!`},
+		{`file:///etc/hosts`, URL{Scheme: "file", HasAuthority: true, Path: "/etc/hosts"}, ``},
+		{`mailto:someone@example.com`, URL{Scheme: "mailto", Path: "someone@example.com"}, ``},
+		{`http://192.168.0.1:80`, URL{Scheme: "http", HasAuthority: true, Host: "192.168.0.1", Port: "80"}, ``},
+		{`http://[::1]/x`, URL{Scheme: "http", HasAuthority: true, Host: "::1", Path: "/x"}, ``},
+		{`http://1.2.3.4a/`, URL{Scheme: "http", HasAuthority: true, Host: "1.2.3.4a", Path: "/"}, ``},
+		{`urn:isbn:0451450523?#`, URL{Scheme: "urn", Path: "isbn:0451450523"}, `urn:isbn:0451450523`},
+		{`x:/%E2%82%AC`, URL{Scheme: "x", Path: "/\u20ac"}, ``},
+	}
+	for _, test := range tests {
+		u, diags := Parse(test.input)
+		if len(diags) > 0 {
+			t.Errorf("Parse(%q) unexpected errors: %v", test.input, diags)
+			continue
+		}
+		if *u != test.expected {
+			t.Errorf("Parse(%q) expecting %+v, received %+v", test.input, test.expected, *u)
+		}
+		str := test.str
+		if str == "" {
+			str = test.input
+		}
+		if u.String() != str {
+			t.Errorf("Parse(%q).String() expecting %q, received %q", test.input, str, u.String())
+		}
+	}
+}
+
+// TestErrors confirms the message and span of each error.
+//
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{``, []string{"1:1-1:1 empty URL"}},
+		{`example.com`, []string{"1:1-1:12 missing scheme"}},
+		{`1http:`, []string{"1:1-1:2 scheme must start with a letter"}},
+		{`ht tp://x`, []string{"1:3-1:4 invalid character ' ' in scheme"}},
+		{`http://a/%zz/%4`, []string{"1:10-1:13 invalid percent-escape", "1:14-1:16 invalid percent-escape"}},
+		{`http://a/%z/`, []string{"1:10-1:12 invalid percent-escape"}},
+		{`http://a b<c/`, []string{"1:9-1:10 invalid character ' ' in host", "1:11-1:12 invalid character '<' in host"}},
+		{`http://a/"q"?x y#z^`, []string{
+			"1:10-1:11 invalid character '\"' in path",
+			"1:12-1:13 invalid character '\"' in path",
+			"1:15-1:16 invalid character ' ' in query",
+			"1:19-1:20 invalid character '^' in fragment",
+		}},
+		{`http://256.1.1.1/`, []string{"1:8-1:11 IPv4 octet out of range"}},
+		{`http://1.2.3/`, []string{"1:8-1:13 IPv4 address must have 4 octets, found 3"}},
+		{`http://1..2/`, []string{"1:10-1:11 expected IPv4 octet, found '.'"}},
+		{`http://[1:2:x]/`, []string{"1:8-1:15 invalid IPv6 address"}},
+		{`http://[::1/`, []string{"1:8-1:12 missing ']' in host"}},
+		{`http://a:65536/`, []string{"1:10-1:15 port out of range"}},
+		{`http://a:80x/`, []string{"1:12-1:13 invalid text after host"}},
+	}
+	for _, test := range tests {
+		u, diags := Parse(test.input)
+		if u != nil {
+			t.Errorf("Parse(%q) expecting nil URL", test.input)
+		}
+		var received []string
+		for _, d := range diags {
+			received = append(received, d.Span.String()+" "+d.Message)
+		}
+		if strings.Join(received, "\n") != strings.Join(test.expected, "\n") {
+			t.Errorf("Parse(%q) expecting %q, received %q", test.input, test.expected, received)
+		}
+	}
+}
+
+// TestRender
+//
+func TestRender(t *testing.T) {
+	input := "http://ex ample.com:99999/a%zz"
+	_, diags := Parse(input)
+	expected := `error: invalid character ' ' in host
+ --> 1:10
+  |
+1 | http://ex ample.com:99999/a%zz
+  |          ^
+
+error: port out of range
+ --> 1:21
+  |
+1 | http://ex ample.com:99999/a%zz
+  |                     ^^^^^
+
+error: invalid percent-escape
+ --> 1:28
+  |
+1 | http://ex ample.com:99999/a%zz
+  |                            ^^^
+`
+	b := &bytes.Buffer{}
+	Render(b, input, diags)
+	if b.String() != expected {
+		t.Errorf("Render() expecting:\n%s\nreceived:\n%s", expected, b.String())
+	}
+}
+
+// TestRenderTabs confirms carets line up with tabs, and that zero-width spans at the end of a line are marked.
+//
+func TestRenderTabs(t *testing.T) {
+	_, diags := Parse("http://1.2.3.")
+	input := "\tline one\n\tx\tyz"
+	for i := range diags {
+		diags[i].Span.Start.Line, diags[i].Span.End.Line = 2, 2
+	}
+	b := &bytes.Buffer{}
+	Render(b, input, diags)
+	expected := "error: expected IPv4 octet, found end of input\n --> 2:14\n  |\n2 | \tx\tyz\n  | \t \t  ^\n"
+	if b.String() != expected {
+		t.Errorf("Render() expecting %q, received %q", expected, b.String())
+	}
+}
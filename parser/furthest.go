@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Failure describes the furthest point in the input where the parser failed to match an expected token, along with
+// all of the token types expected there.
+// Reporting errors from the furthest failure, rather than from the start of the last alternative attempted, produces
+// much better messages for grammars that backtrack.
+// Failure implements error.
+// See Parser.Expect and Parser.Furthest.
+//
+type Failure struct {
+	Index    int            // Index of the failed token - see Parser.Index()
+	Pos      token.Position // Position of the failed token, or the end of the input if Found is nil
+	Found    token.Token    // The token found, nil if the end of the input was reached
+	Expected token.Set      // The token types expected, accumulated across all attempts at Index
+}
+
+// Error implements error, i.e. "1:5: expected [2 3], found 4 'x'".
+//
+func (f *Failure) Error() string {
+	return fmt.Sprintf("%s: %s", f.Pos, f.message())
+}
+
+// message describes the failure, without its position.
+//
+func (f *Failure) message() string {
+	found := "EOF"
+	if f.Found != nil {
+		found = fmt.Sprintf("%d '%s'", f.Found.Type(), f.Found.Value())
+	}
+	if f.Expected.Len() == 0 {
+		return "unexpected " + found
+	}
+	return fmt.Sprintf("expected %s, found %s", f.Expected, found)
+}
+
+// Expect matches the next token if its type is one of the specified types, returning the token along with true.
+// Otherwise, the types are recorded as expected at the current index, and nil, false is returned.
+// Expectations at the furthest index reached are accumulated across backtracking attempts (see Furthest).
+// Satisfied expectations are recorded as coverage points (see WithCoverage), named by their types, i.e. "[2 3]".
+// Panics if EOF already emitted (see WithLenient).
+//
+func (p *Parser) Expect(types ...token.Type) (token.Token, bool) {
+	// Nothing can be matched after EOF emitted
+	//
+	if p.afterEOF("Parser.Expect: No tokens can be matched after EOF is emitted") {
+		return nil, false
+	}
+	set := token.NewSet(types...)
+	if p.growPeek(1) && set.Contains(p.peekHead().Value.(token.Token).Type()) {
+		if p.coverage != nil {
+			p.coverage.hit(CoverExpect, set.String())
+		}
+		return p.Next(), true
+	}
+	index := p.Index()
+	switch {
+	case p.furthest == nil || index > p.furthest.Index:
+		p.furthest = p.failure(set)
+	case index == p.furthest.Index:
+		p.furthest.Expected = p.furthest.Expected.Union(set)
+	}
+	return nil, false
+}
+
+// Furthest returns the failure recorded at the furthest index reached via Expect.
+// Returns nil if no failures are recorded.
+//
+func (p *Parser) Furthest() *Failure {
+	return p.furthest
+}
+
+// EmitFurthest emits the furthest failure (see Furthest) as an error, and then forgets it, allowing parsing to
+// recover and continue.
+// If no failures are recorded, a failure describing the next token is emitted instead.
+// The error returned from the ASTNexter is the *Failure.
+// All previously-matched tokens are discarded.
+// All outstanding markers are invalidated after this call.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (p *Parser) EmitFurthest() {
+	// Nothing can be emitted after EOF emitted
+	//
+	if p.afterEOF("Parser.EmitFurthest: No further emits allowed after EOF is emitted") {
+		return
+	}
+	f := p.furthest
+	if f == nil {
+		f = p.failure(token.Set{})
+	}
+	p.furthest = nil
+	p.emitError(f)
+}
+
+// failure describes the next token, expecting the specified types.
+//
+func (p *Parser) failure(expected token.Set) *Failure {
+	f := &Failure{Index: p.Index(), Pos: p.Pos(), Expected: expected}
+	if p.growPeek(1) {
+		f.Found = p.peekHead().Value.(token.Token)
+	}
+	return f
+}
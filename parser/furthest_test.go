@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// parseAlternatives tries 'one two', 'one three one' and 'one three three', emitting the furthest failure if none
+// match.
+//
+func parseAlternatives(p *Parser) Fn {
+	for _, alt := range [][]token.Type{{TOne, TTwo}, {TOne, TThree, TOne}, {TOne, TThree, TThree}} {
+		m := p.Marker()
+		matched := true
+		for _, typ := range alt {
+			if _, ok := p.Expect(typ); !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			p.Emit("AST")
+			return parseAlternatives
+		}
+		m.Apply()
+	}
+	p.EmitFurthest()
+	return nil
+}
+
+// positioned returns a token for each type, with values 'a', 'b', ..., positioned on line 1, 2 columns apart.
+//
+func positioned(types ...token.Type) token.Nexter {
+	tokens := make([]token.Token, len(types))
+	for i, typ := range types {
+		tokens[i] = token.New(typ, string(rune('a'+i)), 1, 2*i+1)
+	}
+	return token.SliceNexter(tokens...)
+}
+
+// TestFurthest
+//
+func TestFurthest(t *testing.T) {
+	nexter := Parse(positioned(TOne, TTwo, TOne, TThree, TTwo), parseAlternatives)
+	expectNexterNext(t, nexter, "AST")
+	expectNexterError(t, nexter, "1:9: expected [1 3], found 2 'e'")
+	expectNexterEOF(t, nexter)
+}
+
+// TestFurthestEOF
+//
+func TestFurthestEOF(t *testing.T) {
+	nexter := Parse(positioned(TOne, TThree), parseAlternatives)
+	expectNexterError(t, nexter, "1:4: expected [1 3], found EOF")
+	expectNexterEOF(t, nexter)
+}
+
+// TestFurthestNone
+//
+func TestFurthestNone(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		if p.Furthest() != nil {
+			t.Error("Parser.Furthest() expecting nil")
+		}
+		p.EmitFurthest()
+		return nil
+	}
+	nexter := Parse(positioned(TTwo), fn)
+	expectNexterError(t, nexter, "1:1: unexpected 2 'a'")
+	expectNexterEOF(t, nexter)
+}
+
+// TestFurthestValue
+//
+func TestFurthestValue(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.Expect(TTwo)
+		p.Next()
+		p.Expect(TOne)
+		p.Expect(TThree)
+		f := p.Furthest()
+		if f == nil || f.Index != 1 || f.Found.Value() != "b" || f.Expected.String() != "[1 3]" {
+			t.Errorf("Parser.Furthest() received unexpected failure %#v", f)
+		}
+		p.EmitFurthest()
+		if p.Furthest() != nil {
+			t.Error("Parser.Furthest() expecting nil after EmitFurthest()")
+		}
+		return nil
+	}
+	nexter := Parse(positioned(TOne, TTwo), fn)
+	ast, err := nexter.Next()
+	if _, ok := err.(*Failure); !ok || ast != nil {
+		t.Errorf("Nexter.Next() expecting (nil, *Failure), received (%v, %v)", ast, err)
+	}
+	expectNexterEOF(t, nexter)
+}
+
+// TestExpectCoverage
+//
+func TestExpectCoverage(t *testing.T) {
+	c := NewCoverage()
+	c.DeclareExpects(token.NewSet(TOne), token.NewSet(TTwo))
+	expectNexterEOF(t, Parse(positioned(TOne), func(p *Parser) Fn {
+		p.Expect(TOne)
+		return nil
+	}, WithCoverage(c)))
+	untested := c.Untested()
+	if len(untested) != 1 || untested[0] != (CoverEntry{Kind: CoverExpect, Name: "[2]", Hits: 0}) {
+		t.Errorf("Coverage.Untested() received wrong entries: %v", untested)
+	}
+}
@@ -12,7 +12,9 @@ require (
 )
 
 // For Local testing against changes that aren't upstream
+// The parser depends on lexer changes not yet tagged, so these stay enabled until the next release;
+// To release: tag lexer/token and lexer, update the require versions above via go get, then comment these out.
 //
-//replace github.com/tekwizely/go-parsing/lexer => ../lexer
+replace github.com/tekwizely/go-parsing/lexer => ../lexer
 
-//replace github.com/tekwizely/go-parsing/lexer/token => ../lexer/token
+replace github.com/tekwizely/go-parsing/lexer/token => ../lexer/token
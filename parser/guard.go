@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"container/list"
+	"fmt"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// progress snapshots the parser state, for detecting parser functions that neither consume tokens nor emit ASTs.
+//
+type progress struct {
+	front    *list.Element
+	matchLen int
+	outLen   int
+}
+
+// progress returns a snapshot of the parser state.
+//
+func (p *Parser) progress() progress {
+	return progress{front: p.cache.Front(), matchLen: p.matchLen, outLen: p.output.Len()}
+}
+
+// guardLoop checks if the parser function fn made progress since the snapshot was taken.
+// If the loop guard is enabled (see WithLoopGuard) and too many consecutive calls have made no progress, an error is
+// emitted naming the function and the position of the next token, and parsing is terminated.
+//
+func (p *Parser) guardLoop(fn Fn, before progress) {
+	if p.loopLimit <= 0 || p.eofOut {
+		return
+	}
+	if p.progress() != before {
+		p.idleCalls = 0
+		return
+	}
+	p.idleCalls++
+	if p.idleCalls >= p.loopLimit {
+		pos := token.Position{Line: -1, Column: -1}
+		if p.growPeek(1) {
+			pos = token.Start(p.peekHead().Value.(token.Token))
+		}
+		p.emitError(&LoopError{Fn: p.fnName(fn), Calls: p.idleCalls, Pos: pos, context: p.ruleContext()})
+		p.EmitEOF()
+		p.nextFn = nil
+	}
+}
+
+// LoopError is the error emitted when the loop guard terminates parsing (see WithLoopGuard).
+//
+type LoopError struct {
+	Fn    string         // Name of the parser function (or rule - see Parser.Rule) that made no progress
+	Calls int            // Consecutive calls without progress
+	Pos   token.Position // Position of the next token, -1:-1 if none
+
+	context string // The rule context, if any - see Parser.ruleContext()
+}
+
+// Error implements error, i.e. "3:14: runaway loop detected: main.parseExpr returned 100 times without consuming
+// tokens or emitting ASTs".
+//
+func (e *LoopError) Error() string {
+	return fmt.Sprintf("%s: runaway loop detected: %s returned %d times without consuming tokens or emitting ASTs%s",
+		e.Pos, e.Fn, e.Calls, e.context)
+}
+
+// tooManyErrors is the error emitted once the error limit is reached (see WithMaxErrors).
+//
+const tooManyErrors = "too many errors"
+
+// guardErrors terminates parsing once the error limit is reached (see WithMaxErrors).
+//
+func (p *Parser) guardErrors() {
+	if p.maxErrors > 0 && p.errCount >= p.maxErrors && !p.eofOut {
+		p.EmitEOF()
+		p.nextFn = nil
+	}
+}
+
+// DepthError is the error emitted when the rule stack exceeds the depth limit (see WithMaxDepth).
+//
+type DepthError struct {
+	Limit int            // The depth limit
+	Rules []string       // The rule stack, outermost first - see Parser.RuleStack()
+	Pos   token.Position // Position of the innermost rule
+}
+
+// Error implements error, i.e. "3:14: maximum depth of 100 exceeded while parsing expr".
+//
+func (e *DepthError) Error() string {
+	return fmt.Sprintf("%s: maximum depth of %d exceeded while parsing %s", e.Pos, e.Limit, e.Rules[len(e.Rules)-1])
+}
+
+// depthAbort is the panic value used to unwind recursive parser functions once the depth limit is exceeded.
+//
+type depthAbort struct {
+	err *DepthError
+}
+
+// guardDepth aborts the parser function if the rule stack exceeds the depth limit (see WithMaxDepth).
+//
+func (p *Parser) guardDepth() {
+	if p.maxDepth > 0 && len(p.frames) > p.maxDepth {
+		pos := p.frames[len(p.frames)-1].pos
+		panic(depthAbort{err: &DepthError{Limit: p.maxDepth, Rules: p.RuleStack(), Pos: pos}})
+	}
+}
+
+// callFn calls the parser function fn, returning the next parser function.
+// If fn is aborted by the depth limit (see WithMaxDepth), the error is emitted, parsing is terminated, and nil is
+// returned.
+// Other panics are passed through.
+//
+func (p *Parser) callFn(fn Fn) (next Fn) {
+	if p.maxDepth > 0 {
+		defer func() {
+			if r := recover(); r != nil {
+				abort, ok := r.(depthAbort)
+				if !ok {
+					panic(r)
+				}
+				if !p.eofOut {
+					p.emitError(abort.err)
+					p.EmitEOF()
+				}
+				next = nil
+			}
+		}()
+	}
+	return fn(p)
+}
@@ -0,0 +1,147 @@
+package parser
+
+import "testing"
+
+// parseStuck never consumes tokens
+//
+func parseStuck(p *Parser) Fn {
+	p.Peek(1)
+	return parseStuck
+}
+
+// TestWithLoopGuard
+//
+func TestWithLoopGuard(t *testing.T) {
+	nexter := Parse(mockPosLexer(), parseStuck, WithLoopGuard(10))
+	expectNexterError(t, nexter,
+		"1:1: runaway loop detected: github.com/tekwizely/go-parsing/parser.parseStuck returned 10 times without consuming tokens or emitting ASTs")
+	expectNexterEOF(t, nexter)
+}
+
+// TestLoopError
+//
+func TestLoopError(t *testing.T) {
+	_, err := Parse(mockPosLexer(), parseStuck, WithLoopGuard(10)).Next()
+	e, ok := err.(*LoopError)
+	if !ok {
+		t.Fatalf("Nexter.Next() expecting *LoopError, received %T", err)
+	}
+	if e.Calls != 10 || e.Pos.String() != "1:1" {
+		t.Errorf("LoopError expecting 10 calls at 1:1, received %d calls at %s", e.Calls, e.Pos)
+	}
+	_, err = Parse(mockPosLexer(), func(p *Parser) Fn {
+		p.EmitError("runaway loop detected")
+		return nil
+	}, WithLoopGuard(10)).Next()
+	if _, ok := err.(*LoopError); ok {
+		t.Errorf("Nexter.Next() expecting user error, received *LoopError '%s'", err)
+	}
+}
+
+// TestWithLoopGuardProgress
+//
+func TestWithLoopGuardProgress(t *testing.T) {
+	calls := 0
+	var fn Fn
+	fn = func(p *Parser) Fn {
+		calls++
+		// Make progress every other call
+		//
+		if calls%2 == 0 {
+			p.Next()
+			if !p.CanPeek(1) {
+				p.Emit("done")
+			}
+		}
+		return fn
+	}
+	nexter := Parse(mockPosLexer(), fn, WithLoopGuard(2))
+	expectNexterNext(t, nexter, "done")
+	expectNexterEOF(t, nexter)
+}
+
+// parseErrors emits each token as an error, emitting two errors for TTwo
+//
+func parseErrors(p *Parser) Fn {
+	typ := p.Next().Type()
+	p.EmitErrorf("bad %d", typ)
+	if typ == TTwo {
+		p.EmitError("bad again")
+	}
+	return parseErrors
+}
+
+// TestWithMaxErrors
+//
+func TestWithMaxErrors(t *testing.T) {
+	nexter := Parse(mockLexer(TOne, TOne, TOne), parseErrors, WithMaxErrors(2))
+	expectNexterError(t, nexter, "bad 1")
+	expectNexterError(t, nexter, "bad 1")
+	expectNexterError(t, nexter, "too many errors")
+	expectNexterEOF(t, nexter)
+	// Errors past the limit within the same function are discarded
+	//
+	nexter = Parse(mockLexer(TOne, TTwo), parseErrors, WithMaxErrors(2))
+	expectNexterError(t, nexter, "bad 1")
+	expectNexterError(t, nexter, "bad 2")
+	expectNexterError(t, nexter, "too many errors")
+	expectNexterEOF(t, nexter)
+	// No limit
+	//
+	nexter = Parse(mockLexer(TOne, TTwo), parseErrors)
+	expectNexterError(t, nexter, "bad 1")
+	expectNexterError(t, nexter, "bad 2")
+	expectNexterError(t, nexter, "bad again")
+	expectNexterEOF(t, nexter)
+}
+
+// parseNested matches nested TOne ... TTwo pairs, recursively.
+//
+func parseNested(p *Parser) {
+	p.EnterRule("nested")
+	defer p.ExitRule()
+	p.Next()
+	if p.CanPeek(1) && p.PeekType(1) == TOne {
+		parseNested(p)
+	}
+	if p.CanPeek(1) && p.PeekType(1) == TTwo {
+		p.Next()
+	}
+}
+
+// TestWithMaxDepth
+//
+func TestWithMaxDepth(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		parseNested(p)
+		p.Emit("nested")
+		return nil
+	}
+	nexter := Parse(positioned(TOne, TOne, TTwo, TTwo), fn, WithMaxDepth(2))
+	expectNexterNext(t, nexter, "nested")
+	expectNexterEOF(t, nexter)
+	nexter = Parse(positioned(TOne, TOne, TOne, TTwo, TTwo, TTwo), fn, WithMaxDepth(2))
+	_, err := nexter.Next()
+	e, ok := err.(*DepthError)
+	if !ok {
+		t.Fatalf("Nexter.Next() expecting *DepthError, received %T", err)
+	}
+	if e.Limit != 2 || len(e.Rules) != 3 {
+		t.Errorf("DepthError expecting limit 2 and 3 rules, received %d and %v", e.Limit, e.Rules)
+	}
+	if s := e.Error(); s != "1:5: maximum depth of 2 exceeded while parsing nested" {
+		t.Errorf("DepthError.Error() received '%s'", s)
+	}
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithMaxDepthPanic
+//
+func TestWithMaxDepthPanic(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		panic("other")
+	}
+	assertPanic(t, func() {
+		Parse(positioned(TOne), fn, WithMaxDepth(2)).Next()
+	}, "other")
+}
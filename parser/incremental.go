@@ -0,0 +1,176 @@
+package parser
+
+import "github.com/tekwizely/go-parsing/lexer/token"
+
+// NodeCache stores ASTs from a previous parse, keyed by kind and token range, allowing subtrees that are unaffected
+// by an edit to be reused when re-parsing the edited input.
+//
+// To parse incrementally:
+//
+//  - Create a cache via NewNodeCache() and pass it to Parse via WithNodeCache().
+//  - Within your parser functions, record subtrees via Parser.CacheNode() and try Parser.Reuse() before parsing them.
+//  - After editing the input, call NodeCache.Invalidate() for each edit, then re-lex and re-parse with the same cache.
+//
+// Tokens must carry valid positions (see Token.Line() and Token.Column()); Nodes with invalid positions are not cached.
+// A NodeCache is not safe for concurrent use.
+//
+type NodeCache struct {
+	nodes map[cacheKey]*cachedNode
+}
+
+// cacheKey identifies a cached node by its kind and starting position.
+//
+type cacheKey struct {
+	kind  string
+	start token.Position
+}
+
+// cachedNode stores a cached AST along with the token range it covers.
+//
+type cachedNode struct {
+	span   token.Span
+	tokens int
+	ast    interface{}
+}
+
+// Edit describes a change to the input text, for invalidating a NodeCache.
+// Start and OldEnd bound the replaced text within the old input.
+// Start and NewEnd bound the replacement text within the new input.
+//
+type Edit struct {
+	Start  token.Position
+	OldEnd token.Position
+	NewEnd token.Position
+}
+
+// NewNodeCache returns a new, empty, NodeCache.
+//
+func NewNodeCache() *NodeCache {
+	return &NodeCache{nodes: make(map[cacheKey]*cachedNode)}
+}
+
+// Len returns the number of cached nodes.
+//
+func (c *NodeCache) Len() int {
+	return len(c.nodes)
+}
+
+// Invalidate removes all cached nodes whose spans intersect (or touch) the edit.
+// Nodes following the edit are re-positioned to reflect the new input.
+// When a re-positioned AST is a *Spanned, it is replaced with a re-positioned copy; When it implements SpanSetter,
+// SetSpan() is called with the new span.
+// NOTE: Spans nested within a re-positioned AST are not adjusted.
+//
+func (c *NodeCache) Invalidate(e Edit) {
+	nodes := make(map[cacheKey]*cachedNode, len(c.nodes))
+	for key, node := range c.nodes {
+		// Drop nodes that intersect the edit, including nodes that merely touch it, as the tokens on either side may
+		// now lex differently
+		//
+		if !before(node.span.End, e.Start) && !before(e.OldEnd, node.span.Start) {
+			continue
+		}
+		// Shift nodes that follow the edit
+		//
+		if !before(node.span.Start, e.OldEnd) {
+			span := token.Span{Start: shift(node.span.Start, e), End: shift(node.span.End, e)}
+			ast := node.ast
+			switch a := ast.(type) {
+			case *Spanned:
+				ast = &Spanned{AST: a.AST, Span: span}
+			case SpanSetter:
+				a.SetSpan(span)
+			}
+			key.start = span.Start
+			node = &cachedNode{span: span, tokens: node.tokens, ast: ast}
+		}
+		nodes[key] = node
+	}
+	c.nodes = nodes
+}
+
+// CacheNode records ast in the node cache, covering the tokens matched since the marker m was created.
+// If m is nil, ast covers all currently-matched tokens.
+// Does nothing if the parser has no node cache (see WithNodeCache), if no tokens are covered, or if the covered
+// tokens do not have valid positions.
+// Panics if m is not nil and fails Marker.Valid().
+// Panics if EOF already emitted (see WithLenient).
+//
+func (p *Parser) CacheNode(kind string, m *Marker, ast interface{}) {
+	// Nothing can be cached after EOF emitted
+	//
+	if p.afterEOF("Parser.CacheNode: No caching allowed after EOF is emitted") {
+		return
+	}
+	from := 0
+	if m != nil {
+		if !m.Valid() {
+			p.raise("Invalid marker")
+		}
+		from = m.matchLen
+	}
+	n := p.matchLen - from
+	if p.nodes == nil || n < 1 {
+		return
+	}
+	e := p.cache.Front()
+	for i := 0; i < from; i++ {
+		e = e.Next()
+	}
+	span := token.SpanOf(e.Value.(token.Token), p.matchTail.Value.(token.Token))
+	if !span.Start.IsValid() || !span.End.IsValid() {
+		return
+	}
+	p.nodes.nodes[cacheKey{kind: kind, start: span.Start}] = &cachedNode{span: span, tokens: n, ast: ast}
+}
+
+// Reuse looks for a cached AST of the specified kind starting at the next token.
+// If found, and the upcoming tokens still align with the cached span, the covered tokens are matched and the cached
+// AST is returned, along with true.
+// Otherwise no tokens are matched and nil, false is returned.
+// Always returns nil, false if the parser has no node cache (see WithNodeCache).
+// Panics if EOF already emitted (see WithLenient).
+//
+func (p *Parser) Reuse(kind string) (interface{}, bool) {
+	// Nothing can be reused after EOF emitted
+	//
+	if p.afterEOF("Parser.Reuse: No tokens can be matched after EOF is emitted") {
+		return nil, false
+	}
+	if p.nodes == nil || !p.growPeek(1) {
+		return nil, false
+	}
+	e := p.peekHead()
+	start := token.Start(e.Value.(token.Token))
+	if !start.IsValid() {
+		return nil, false
+	}
+	node, ok := p.nodes.nodes[cacheKey{kind: kind, start: start}]
+	if !ok || !p.growPeek(node.tokens) {
+		return nil, false
+	}
+	for n := node.tokens; n > 1; n-- {
+		e = e.Next()
+	}
+	if token.End(e.Value.(token.Token)) != node.span.End {
+		return nil, false
+	}
+	p.matchTail = e
+	p.matchLen += node.tokens
+	return node.ast, true
+}
+
+// before confirms if position a comes before position b.
+//
+func before(a token.Position, b token.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+}
+
+// shift re-positions pos, which follows the edit, to reflect the new input.
+//
+func shift(pos token.Position, e Edit) token.Position {
+	if pos.Line == e.OldEnd.Line {
+		return token.Position{Line: e.NewEnd.Line, Column: e.NewEnd.Column + pos.Column - e.OldEnd.Column}
+	}
+	return token.Position{Line: pos.Line + e.NewEnd.Line - e.OldEnd.Line, Column: pos.Column}
+}
@@ -0,0 +1,156 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// incrementalFn returns a parser function that emits each token as a "stmt" node, reusing cached nodes when possible,
+// and counting the number of fresh (non-reused) nodes.
+//
+func incrementalFn(fresh *int) Fn {
+	var fn Fn
+	fn = func(p *Parser) Fn {
+		if ast, ok := p.Reuse("stmt"); ok {
+			p.Emit(ast)
+			return fn
+		}
+		ast := &Spanned{AST: p.Next().Value(), Span: p.matchSpan()}
+		p.CacheNode("stmt", nil, ast)
+		p.Emit(ast)
+		*fresh++
+		return fn
+	}
+	return fn
+}
+
+// expectIncremental parses the tokens with the cache, confirming the emitted values and number of fresh nodes.
+//
+func expectIncremental(t *testing.T, cache *NodeCache, tokens []*posToken, values []string, fresh int) {
+	count := 0
+	nexter := Parse(&posNexter{tokens: tokens}, incrementalFn(&count), WithNodeCache(cache))
+	for _, value := range values {
+		ast, err := nexter.Next()
+		if err != nil {
+			t.Fatalf("ASTNexter.Next() received unexpected error '%s'", err)
+		}
+		if v := ast.(*Spanned).AST; v != value {
+			t.Errorf("ASTNexter.Next() expecting '%s', received '%v'", value, v)
+		}
+	}
+	expectNexterEOF(t, nexter)
+	if count != fresh {
+		t.Errorf("Expecting %d fresh nodes, received %d", fresh, count)
+	}
+}
+
+// TestIncrementalReuse
+//
+func TestIncrementalReuse(t *testing.T) {
+	cache := NewNodeCache()
+	expectIncremental(t, cache, []*posToken{
+		{typ: TOne, value: "one", line: 1, column: 1},
+		{typ: TTwo, value: "two\n", line: 1, column: 5},
+		{typ: TThree, value: "three", line: 2, column: 1},
+	}, []string{"one", "two\n", "three"}, 3)
+	if cache.Len() != 3 {
+		t.Errorf("NodeCache.Len() expecting 3, received %d", cache.Len())
+	}
+	// Replace "two" with "TWO!"
+	//
+	cache.Invalidate(Edit{
+		Start:  token.Position{Line: 1, Column: 5},
+		OldEnd: token.Position{Line: 1, Column: 8},
+		NewEnd: token.Position{Line: 1, Column: 9},
+	})
+	if cache.Len() != 2 {
+		t.Errorf("NodeCache.Len() expecting 2, received %d", cache.Len())
+	}
+	expectIncremental(t, cache, []*posToken{
+		{typ: TOne, value: "one", line: 1, column: 1},
+		{typ: TTwo, value: "TWO!\n", line: 1, column: 5},
+		{typ: TThree, value: "three", line: 2, column: 1},
+	}, []string{"one", "TWO!\n", "three"}, 1)
+}
+
+// TestIncrementalShift
+//
+func TestIncrementalShift(t *testing.T) {
+	cache := NewNodeCache()
+	expectIncremental(t, cache, []*posToken{
+		{typ: TOne, value: "a", line: 1, column: 1},
+		{typ: TTwo, value: "b", line: 1, column: 3},
+		{typ: TThree, value: "c\n", line: 1, column: 5},
+		{typ: TThree, value: "d", line: 2, column: 1},
+	}, []string{"a", "b", "c\n", "d"}, 4)
+	// Replace "b" with "bb\nb"
+	//
+	cache.Invalidate(Edit{
+		Start:  token.Position{Line: 1, Column: 3},
+		OldEnd: token.Position{Line: 1, Column: 4},
+		NewEnd: token.Position{Line: 2, Column: 2},
+	})
+	count := 0
+	nexter := Parse(&posNexter{tokens: []*posToken{
+		{typ: TOne, value: "a", line: 1, column: 1},
+		{typ: TTwo, value: "bb\nb", line: 1, column: 3},
+		{typ: TThree, value: "c\n", line: 2, column: 3},
+		{typ: TThree, value: "d", line: 3, column: 1},
+	}}, incrementalFn(&count), WithNodeCache(cache))
+	var asts []*Spanned
+	for ast, err := nexter.Next(); err == nil; ast, err = nexter.Next() {
+		asts = append(asts, ast.(*Spanned))
+	}
+	if count != 1 {
+		t.Errorf("Expecting 1 fresh node, received %d", count)
+	}
+	if len(asts) != 4 {
+		t.Fatalf("Expecting 4 ASTs, received %d", len(asts))
+	}
+	expectSpan(t, asts[2].Span, "2:3-3:0")
+	expectSpan(t, asts[3].Span, "3:1-3:2")
+}
+
+// TestIncrementalSpanSetter
+//
+func TestIncrementalSpanSetter(t *testing.T) {
+	cache := NewNodeCache()
+	node := &spanNode{}
+	fn := func(p *Parser) Fn {
+		p.Next()
+		p.Next()
+		m := p.Marker()
+		p.Next()
+		p.CacheNode("three", m, node)
+		return nil
+	}
+	nexter := Parse(mockPosLexer(), fn, WithNodeCache(cache))
+	expectNexterEOF(t, nexter)
+	// Insert a line after "one"
+	//
+	cache.Invalidate(Edit{
+		Start:  token.Position{Line: 1, Column: 4},
+		OldEnd: token.Position{Line: 1, Column: 4},
+		NewEnd: token.Position{Line: 2, Column: 0},
+	})
+	if cache.Len() != 1 {
+		t.Errorf("NodeCache.Len() expecting 1, received %d", cache.Len())
+	}
+	expectSpan(t, node.span, "3:1-3:6")
+}
+
+// TestReuseWithoutCache
+//
+func TestReuseWithoutCache(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.Next()
+		p.CacheNode("stmt", nil, "one")
+		if ast, ok := p.Reuse("stmt"); ok || ast != nil {
+			t.Error("Parser.Reuse() expecting (nil, false)")
+		}
+		return nil
+	}
+	nexter := Parse(mockPosLexer(), fn)
+	expectNexterEOF(t, nexter)
+}
@@ -0,0 +1,71 @@
+package parser
+
+import "github.com/tekwizely/go-parsing/lexer/token"
+
+// IncompleteError wraps errors emitted once the input is exhausted in interactive mode (see WithInteractive),
+// signaling that the input may simply be incomplete (i.e. an unclosed block), rather than invalid.
+// Implements token.Incompleter, allowing REPLs to detect it via token.IsIncomplete(err), and prompt for continuation
+// lines.
+//
+type IncompleteError struct {
+	Err error // The error emitted by the parser
+}
+
+// Error implements error, returning the message of the wrapped error.
+//
+func (e *IncompleteError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+//
+func (e *IncompleteError) Unwrap() error {
+	return e.Err
+}
+
+// Incomplete implements token.Incompleter.Incomplete(), always returning true.
+//
+func (e *IncompleteError) Incomplete() bool {
+	return true
+}
+
+// deferIncomplete records err as pending, to be reported before EOF is emitted, if err is incomplete and the parser
+// is in interactive mode, returning true if recorded.
+//
+func (p *Parser) deferIncomplete(err error) bool {
+	if !p.repl || !token.IsIncomplete(err) {
+		return false
+	}
+	p.pending = err
+	return true
+}
+
+// incomplete wraps err as an IncompleteError if no tokens remain beyond the matched tokens, clearing any pending
+// incomplete error from the lexer once an incomplete error is reported.
+//
+func (p *Parser) incomplete(err error) error {
+	if !token.IsIncomplete(err) && !p.hasMore() {
+		err = &IncompleteError{Err: err}
+	}
+	if token.IsIncomplete(err) {
+		p.pending = nil
+	}
+	return err
+}
+
+// hasMore confirms if any tokens remain beyond the matched tokens, ignoring the EOF token (see WithEOFToken).
+// Decides from the tokens already read, as reading more could block on interactive input; Until EOF has been read,
+// tokens are assumed to remain.
+//
+func (p *Parser) hasMore() bool {
+	peekLen := p.cache.Len() - p.matchLen
+	switch {
+	case !p.eof:
+		return true
+	case peekLen == 0:
+		return false
+	case p.eofToken && peekLen == 1:
+		return p.peekHead().Value.(token.Token).Type() != p.eofType
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// lexIncomplete is an incomplete error, as returned from a lexer in interactive mode.
+//
+type lexIncomplete struct{}
+
+// Error implements error.
+//
+func (e *lexIncomplete) Error() string {
+	return "1:7: unterminated string"
+}
+
+// Incomplete implements token.Incompleter.Incomplete().
+//
+func (e *lexIncomplete) Incomplete() bool {
+	return true
+}
+
+// blockingNexter returns its tokens, then blocks until released, as with a lexer waiting on interactive input.
+//
+type blockingNexter struct {
+	tokens  token.Nexter
+	release chan struct{}
+}
+
+// Next implements token.Nexter.Next().
+//
+func (n *blockingNexter) Next() (token.Token, error) {
+	tok, err := n.tokens.Next()
+	if err == io.EOF {
+		<-n.release
+	}
+	return tok, err
+}
+
+// parsePair expects TOne followed by TTwo.
+//
+func parsePair(p *Parser) Fn {
+	if _, ok := p.Expect(TOne); !ok {
+		p.EmitFurthest()
+		return nil
+	}
+	if _, ok := p.Expect(TTwo); !ok {
+		p.EmitError("expecting TTwo")
+		return nil
+	}
+	p.Emit("pair")
+	return parsePair
+}
+
+// TestWithInteractive
+//
+func TestWithInteractive(t *testing.T) {
+	tests := []struct {
+		tokens     []token.Type
+		incomplete bool
+	}{
+		{[]token.Type{TOne}, true},
+		{[]token.Type{TOne, TThree}, false},
+		{[]token.Type{TOne, TThree, TOne}, false},
+	}
+	for _, test := range tests {
+		nexter := Parse(mockLexer(test.tokens...), parsePair, WithInteractive())
+		_, err := nexter.Next()
+		if err == nil {
+			t.Fatalf("Nexter.Next() expecting error for %v", test.tokens)
+		}
+		if token.IsIncomplete(err) != test.incomplete {
+			t.Errorf("token.IsIncomplete(%v) expecting %v", test.tokens, test.incomplete)
+		}
+	}
+	// Not interactive
+	//
+	_, err := Parse(mockLexer(TOne), parsePair).Next()
+	if err == nil || token.IsIncomplete(err) {
+		t.Errorf("Nexter.Next() expecting complete error, received %v", err)
+	}
+}
+
+// TestWithInteractiveFailure
+//
+func TestWithInteractiveFailure(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.Expect(TOne)
+		p.Expect(TTwo)
+		p.EmitFurthest()
+		return nil
+	}
+	_, err := Parse(positioned(TOne), fn, WithInteractive()).Next()
+	e, ok := err.(*IncompleteError)
+	if !ok {
+		t.Fatalf("Nexter.Next() expecting *IncompleteError, received %T", err)
+	}
+	if _, ok := e.Unwrap().(*Failure); !ok {
+		t.Errorf("IncompleteError.Unwrap() expecting *Failure, received %T", e.Unwrap())
+	}
+}
+
+// TestWithInteractiveLexer
+//
+func TestWithInteractiveLexer(t *testing.T) {
+	tokens := token.ErrNexter(mockLexer(TOne, TTwo), 2, &lexIncomplete{})
+	nexter := Parse(tokens, parsePair, WithInteractive())
+	expectNexterNext(t, nexter, "pair")
+	expectNexterError(t, nexter, "1:7: unterminated string")
+	expectNexterEOF(t, nexter)
+	// Parser errors at the end of the input take precedence
+	//
+	tokens = token.ErrNexter(mockLexer(TOne), 1, &lexIncomplete{})
+	nexter = Parse(tokens, parsePair, WithInteractive())
+	expectNexterError(t, nexter, "expecting TTwo")
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithInteractiveBlocking confirms errors are reported without reading past the tokens already read
+//
+func TestWithInteractiveBlocking(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.Expect(TOne)
+		p.EmitError("unexpected TOne")
+		return nil
+	}
+	tokens := &blockingNexter{tokens: mockLexer(TOne), release: make(chan struct{})}
+	defer close(tokens.release)
+	errs := make(chan error, 1)
+	go func() {
+		_, err := Parse(tokens, fn, WithInteractive()).Next()
+		errs <- err
+	}()
+	select {
+	case err := <-errs:
+		if err == nil || err.Error() != "unexpected TOne" {
+			t.Errorf("Nexter.Next() expecting 'unexpected TOne', received %v", err)
+		}
+		if token.IsIncomplete(err) {
+			t.Errorf("token.IsIncomplete(%v) expecting false", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Nexter.Next() blocked reading past the matched tokens")
+	}
+}
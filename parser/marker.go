@@ -1,6 +1,10 @@
 package parser
 
-import "container/list"
+import (
+	"container/list"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
 
 // Marker snapshots the state of the parser to allow rewinding.
 //
@@ -9,6 +13,7 @@ import "container/list"
 //  - Parser.Marker()
 //  - Marker.Valid()
 //  - Marker.Apply()
+//  - Marker.ClearTo()
 //
 type Marker struct {
 	parser    *Parser
@@ -24,6 +29,7 @@ type Marker struct {
 // Use Marker.Apply() to reset the parser state to the marker position.
 //
 func (p *Parser) Marker() *Marker {
+	p.traceEvent(TraceMarker, nil, nil, "")
 	return &Marker{parser: p, markerID: p.markerID, matchTail: p.matchTail, matchLen: p.matchLen, nextFn: p.nextFn}
 }
 
@@ -45,9 +51,48 @@ func (m *Marker) Valid() bool {
 //
 func (m *Marker) Apply() Fn {
 	if !m.Valid() {
-		panic("Invalid marker")
+		m.parser.raise("Invalid marker")
 	}
 	m.parser.matchTail = m.matchTail
 	m.parser.matchLen = m.matchLen
+	m.parser.traceEvent(TraceApply, nil, nil, "")
+	if m.parser.stats != nil {
+		m.parser.stats.MarkerApplies++
+	}
 	return m.nextFn
 }
+
+// ClearTo discards the tokens matched since the marker was created, keeping any tokens matched before it.
+// Unlike Apply, the discarded tokens are not returned to the peek buffer, allowing you to commit part of a match and
+// retry the rest.
+// The next function is not changed.
+// All outstanding markers are invalidated after this call.
+// Panics if marker fails Valid() check.
+// Panics if tokens matched before the marker have since been un-matched (via Apply of an earlier marker).
+//
+func (m *Marker) ClearTo() {
+	if !m.Valid() {
+		m.parser.raise("Invalid marker")
+	}
+	p := m.parser
+	if p.matchLen < m.matchLen {
+		p.raise("Marker.ClearTo: marker is ahead of the matched tokens")
+	}
+	p.traceEvent(TraceClear, nil, nil, "")
+	// Remember the last token, for Last()
+	//
+	if m.matchLen == 0 && p.matchLen > 0 {
+		p.last = p.matchTail.Value.(token.Token)
+	}
+	for n := p.matchLen - m.matchLen; n > 0; n-- {
+		if m.matchLen > 0 {
+			p.cache.Remove(m.matchTail.Next())
+		} else {
+			p.cache.Remove(p.cache.Front())
+		}
+		p.discarded++
+	}
+	p.matchTail = m.matchTail
+	p.matchLen = m.matchLen
+	p.markerID++ // Invalidate outstanding markers
+}
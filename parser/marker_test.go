@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -136,3 +137,52 @@ func TestMarkerApplyNextFn(t *testing.T) {
 	nexter := Parse(tokens, fn2)
 	expectNexterEOF(t, nexter)
 }
+
+// TestMarkerClearTo
+//
+func TestMarkerClearTo(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		expectNext(t, p, TOne, "")
+		m := p.Marker()
+		expectNext(t, p, TTwo, "")
+		expectNext(t, p, TThree, "")
+		m.ClearTo()
+		expectMarkerValid(t, m, false)
+		if matched := p.Matched(); len(matched) != 1 || matched[0].Type() != TOne {
+			t.Errorf("Parser.Matched() expecting [TOne] after ClearTo(), received %v", matched)
+		}
+		// Retry the rest
+		//
+		expectNext(t, p, TOne, "")
+		p.Emit(fmt.Sprint(len(p.Matched())))
+		m = p.Marker()
+		expectNext(t, p, TTwo, "")
+		m.ClearTo()
+		if last := p.Last(); last == nil || last.Type() != TTwo {
+			t.Error("Parser.Last() expecting TTwo after ClearTo() from start")
+		}
+		expectNext(t, p, TThree, "")
+		p.Emit(fmt.Sprint(len(p.Matched())))
+		return nil
+	}
+	nexter := Parse(mockLexer(TOne, TTwo, TThree, TOne, TTwo, TThree), fn)
+	expectNexterNext(t, nexter, "2")
+	expectNexterNext(t, nexter, "1")
+	expectNexterEOF(t, nexter)
+}
+
+// TestMarkerClearToPanics
+//
+func TestMarkerClearToPanics(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		m1 := p.Marker()
+		p.Next()
+		m2 := p.Marker()
+		m1.Apply()
+		assertPanic(t, func() { m2.ClearTo() }, "Marker.ClearTo: marker is ahead of the matched tokens")
+		p.Clear()
+		assertPanic(t, func() { m1.ClearTo() }, "Invalid marker")
+		return nil
+	}
+	expectNexterEOF(t, Parse(mockLexer(TOne, TTwo), fn))
+}
@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// ndjsonLine is a single line written by WriteNDJSON.
+//
+type ndjsonLine struct {
+	AST   interface{} `json:"ast,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// WriteNDJSON consumes the ASTNexter until io.EOF, writing each AST and error to w as a single line of JSON
+// (newline-delimited JSON), as soon as it is received, i.e:
+//
+//	{"ast":{"op":"+","left":1,"right":2}}
+//	{"error":"1:5: expected [2 3], found 4 'x'"}
+//
+// Allows parse results to be consumed incrementally by other tools, i.e. `myparser file.src | jq ...`.
+// ASTs are encoded via encoding/json, so should be JSON-marshalable.
+// Returns the first error encoding an AST or writing to w.
+//
+func WriteNDJSON(w io.Writer, n ASTNexter) error {
+	enc := json.NewEncoder(w)
+	for {
+		ast, err := n.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if encErr := enc.Encode(ndjsonLine{Error: err.Error()}); encErr != nil {
+				return encErr
+			}
+		}
+		if ast != nil {
+			if encErr := enc.Encode(ndjsonLine{AST: ast}); encErr != nil {
+				return encErr
+			}
+		}
+	}
+}
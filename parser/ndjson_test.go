@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// parseNDJSON emits a map for TOne, and an error otherwise
+//
+func parseNDJSON(p *Parser) Fn {
+	if p.Next().Type() == TOne {
+		p.Emit(map[string]int{"one": 1})
+	} else {
+		p.EmitError("not one")
+	}
+	return parseNDJSON
+}
+
+// TestWriteNDJSON
+//
+func TestWriteNDJSON(t *testing.T) {
+	b := &bytes.Buffer{}
+	if err := WriteNDJSON(b, Parse(mockLexer(TOne, TTwo), parseNDJSON)); err != nil {
+		t.Errorf("WriteNDJSON() received unexpected error '%s'", err)
+	}
+	expected := `{"ast":{"one":1}}` + "\n" + `{"error":"not one"}` + "\n"
+	if b.String() != expected {
+		t.Errorf("WriteNDJSON() expecting:\n%s\nreceived:\n%s", expected, b.String())
+	}
+}
+
+// TestWriteNDJSONEncodeError
+//
+func TestWriteNDJSONEncodeError(t *testing.T) {
+	nexter := Parse(mockLexer(TOne), func(p *Parser) Fn {
+		p.Next()
+		p.Emit(func() {})
+		return nil
+	})
+	if err := WriteNDJSON(&bytes.Buffer{}, nexter); err == nil {
+		t.Error("WriteNDJSON() expecting encoding error")
+	}
+}
+
+// failWriter fails every write
+//
+type failWriter struct{}
+
+// Write
+//
+func (failWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+// TestWriteNDJSONWriteError
+//
+func TestWriteNDJSONWriteError(t *testing.T) {
+	if err := WriteNDJSON(failWriter{}, Parse(mockLexer(TOne), parseNDJSON)); err == nil || err.Error() != "write failed" {
+		t.Errorf("WriteNDJSON() expecting 'write failed', received '%v'", err)
+	}
+}
@@ -0,0 +1,90 @@
+package parser
+
+import "io"
+
+// Observe wraps an ASTNexter, calling fn with each AST as it is delivered, i.e. to log, count or cache ASTs while they
+// continue to flow to the consumer.
+// Errors and io.EOF are passed through without calling fn.
+//
+func Observe(n ASTNexter, fn func(ast interface{})) ASTNexter {
+	return &observeNexter{asts: n, fn: fn}
+}
+
+// observeNexter is the ASTNexter implementation returned by Observe.
+//
+type observeNexter struct {
+	asts ASTNexter
+	fn   func(ast interface{})
+}
+
+// Next implements ASTNexter.Next().
+//
+func (n *observeNexter) Next() (interface{}, error) {
+	ast, err := n.asts.Next()
+	if err == nil && ast != nil {
+		n.fn(ast)
+	}
+	return ast, err
+}
+
+// Tee splits an ASTNexter into two, each delivering every AST and error from n, in order.
+// Results read by one nexter are buffered until read by the other; Consuming both in lock-step buffers at most one
+// result.
+// The returned nexters share state and are not safe for concurrent use.
+//
+func Tee(n ASTNexter) (ASTNexter, ASTNexter) {
+	t := &tee{asts: n}
+	return &teeNexter{tee: t, side: 0}, &teeNexter{tee: t, side: 1}
+}
+
+// tee is the state shared by the nexters returned by Tee.
+//
+type tee struct {
+	asts ASTNexter
+	buf  []teeResult // Results read from asts, not yet delivered to both sides
+	base int         // Index of buf[0], counting from the first result
+	next [2]int      // Index of the next result, per side
+	eof  bool        // Has asts returned io.EOF?
+}
+
+// teeResult is a result read from the source nexter.
+//
+type teeResult struct {
+	ast interface{}
+	err error
+}
+
+// teeNexter is one side of a tee.
+//
+type teeNexter struct {
+	tee  *tee
+	side int
+}
+
+// Next implements ASTNexter.Next().
+//
+func (n *teeNexter) Next() (interface{}, error) {
+	t := n.tee
+	i := t.next[n.side] - t.base
+	if i == len(t.buf) {
+		if t.eof {
+			return nil, io.EOF
+		}
+		ast, err := t.asts.Next()
+		if err == io.EOF {
+			t.eof = true
+			return nil, io.EOF
+		}
+		t.buf = append(t.buf, teeResult{ast: ast, err: err})
+	}
+	r := t.buf[i]
+	t.next[n.side]++
+	// Discard results delivered to both sides
+	//
+	for len(t.buf) > 0 && t.next[0] > t.base && t.next[1] > t.base {
+		t.buf[0] = teeResult{}
+		t.buf = t.buf[1:]
+		t.base++
+	}
+	return r.ast, r.err
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"testing"
+)
+
+// parseTee emits the value of TOne tokens, and an error otherwise
+//
+func parseTee(p *Parser) Fn {
+	if tok := p.Next(); tok.Type() == TOne {
+		p.Emit(tok.Value())
+	} else {
+		p.EmitError("not one")
+	}
+	return parseTee
+}
+
+// TestObserve
+//
+func TestObserve(t *testing.T) {
+	var seen []interface{}
+	nexter := Observe(Parse(positioned(TOne, TTwo, TOne), parseTee), func(ast interface{}) {
+		seen = append(seen, ast)
+	})
+	expectNexterNext(t, nexter, "a")
+	expectNexterError(t, nexter, "not one")
+	expectNexterNext(t, nexter, "c")
+	expectNexterEOF(t, nexter)
+	expectNexterEOF(t, nexter)
+	if len(seen) != 2 || seen[0] != "a" || seen[1] != "c" {
+		t.Errorf("Observe() expecting [a c], received %v", seen)
+	}
+}
+
+// TestTeeLockStep
+//
+func TestTeeLockStep(t *testing.T) {
+	a, b := Tee(Parse(positioned(TOne, TTwo, TOne), parseTee))
+	for _, n := range []ASTNexter{a, b} {
+		expectNexterNext(t, n, "a")
+	}
+	for _, n := range []ASTNexter{b, a} {
+		expectNexterError(t, n, "not one")
+	}
+	for _, n := range []ASTNexter{a, b} {
+		expectNexterNext(t, n, "c")
+	}
+	if buffered := len(a.(*teeNexter).tee.buf); buffered != 0 {
+		t.Errorf("Tee() expecting empty buffer, received %d results", buffered)
+	}
+	for _, n := range []ASTNexter{a, b, a, b} {
+		expectNexterEOF(t, n)
+	}
+}
+
+// TestTeeBuffered
+//
+func TestTeeBuffered(t *testing.T) {
+	a, b := Tee(Parse(positioned(TOne, TTwo, TOne), parseTee))
+	expectNexterNext(t, a, "a")
+	expectNexterError(t, a, "not one")
+	expectNexterNext(t, a, "c")
+	expectNexterEOF(t, a)
+	if buffered := len(a.(*teeNexter).tee.buf); buffered != 3 {
+		t.Errorf("Tee() expecting 3 buffered results, received %d", buffered)
+	}
+	expectNexterNext(t, b, "a")
+	expectNexterError(t, b, "not one")
+	expectNexterNext(t, b, "c")
+	expectNexterEOF(t, b)
+	expectNexterEOF(t, a)
+}
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"reflect"
+	"runtime"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// OneOf tries each of the alternatives in order, returning the result of the first to succeed (return a nil error).
+// Tokens matched by the successful alternative remain matched.
+// After each failed alternative, the parser is reset to its state before the alternative was tried (see Marker).
+// If all alternatives fail, the failures (*Failure) returned from the alternatives are aggregated, returning the
+// failure at the furthest index, along with all of the token types expected there, i.e.
+// "1:5: expected [2 3], found 4 'x'".
+// If no alternative returns a *Failure, the error from the last alternative is returned instead.
+// Alternatives must not emit or clear; If an alternative fails after invalidating the marker, OneOf returns its error
+// immediately.
+// Successful alternatives are recorded as coverage points (see WithCoverage), named by their function names.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (p *Parser) OneOf(fns ...func(*Parser) (interface{}, error)) (interface{}, error) {
+	// Nothing can be matched after EOF emitted
+	//
+	if p.afterEOF("Parser.OneOf: No tokens can be matched after EOF is emitted") {
+		return nil, nil
+	}
+	var (
+		m        = p.Marker()
+		furthest *Failure
+		last     error
+	)
+	for _, fn := range fns {
+		v, err := fn(p)
+		if err == nil {
+			if p.coverage != nil {
+				p.coverage.hit(CoverAlt, altName(fn))
+			}
+			return v, nil
+		}
+		if !m.Valid() {
+			return nil, err
+		}
+		m.Apply()
+		last = err
+		if f, ok := err.(*Failure); ok {
+			switch {
+			case furthest == nil || f.Index > furthest.Index:
+				furthest = &Failure{Index: f.Index, Pos: f.Pos, Found: f.Found, Expected: f.Expected}
+			case f.Index == furthest.Index:
+				furthest.Expected = furthest.Expected.Union(f.Expected)
+			}
+		}
+	}
+	if furthest != nil {
+		return nil, furthest
+	}
+	if last == nil {
+		return nil, p.failure(token.Set{})
+	}
+	return nil, last
+}
+
+// altName returns the name of the alternative function.
+//
+func altName(fn func(*Parser) (interface{}, error)) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+)
+
+// altOneTwo matches 'one two'
+//
+func altOneTwo(p *Parser) (interface{}, error) {
+	if _, ok := p.Expect(TOne); !ok {
+		return nil, p.Furthest()
+	}
+	if _, ok := p.Expect(TTwo); !ok {
+		return nil, p.Furthest()
+	}
+	return "12", nil
+}
+
+// altOneThree matches 'one three'
+//
+func altOneThree(p *Parser) (interface{}, error) {
+	if _, ok := p.Expect(TOne); !ok {
+		return nil, p.Furthest()
+	}
+	if _, ok := p.Expect(TThree); !ok {
+		return nil, p.Furthest()
+	}
+	return "13", nil
+}
+
+// altFail always fails, after matching a token
+//
+func altFail(p *Parser) (interface{}, error) {
+	p.Next()
+	return nil, errors.New("alt failed")
+}
+
+// parseOneOf emits the result of OneOf, or its error
+//
+func parseOneOf(p *Parser) Fn {
+	v, err := p.OneOf(altOneTwo, altFail, altOneThree)
+	if err != nil {
+		p.EmitErrorf("%v", err)
+		return nil
+	}
+	p.Emit(v)
+	return parseOneOf
+}
+
+// TestOneOf
+//
+func TestOneOf(t *testing.T) {
+	c := NewCoverage()
+	c.DeclareAlts(altOneTwo, altOneThree, altFail)
+	nexter := Parse(positioned(TOne, TTwo, TOne, TThree, TOne, TOne), parseOneOf, WithCoverage(c))
+	expectNexterNext(t, nexter, "12")
+	expectNexterNext(t, nexter, "13")
+	expectNexterError(t, nexter, "1:11: expected [2 3], found 1 'f'")
+	expectNexterEOF(t, nexter)
+	untested := c.Untested()
+	if len(untested) != 1 || untested[0].Kind != CoverAlt || untested[0].Name != altName(altFail) {
+		t.Errorf("Coverage.Untested() expecting altFail, received %v", untested)
+	}
+}
+
+// TestOneOfLastError
+//
+func TestOneOfLastError(t *testing.T) {
+	nexter := Parse(mockLexer(TOne), func(p *Parser) Fn {
+		if _, err := p.OneOf(altFail, altFail); err != nil {
+			p.EmitError(err.Error())
+		}
+		if p.Next().Type() != TOne {
+			p.EmitError("marker not applied")
+		}
+		return nil
+	})
+	expectNexterError(t, nexter, "alt failed")
+	expectNexterEOF(t, nexter)
+}
@@ -0,0 +1,233 @@
+package parser
+
+import (
+	"github.com/tekwizely/go-parsing/lexer/diag"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Option configures a parser at creation time.
+// Options are passed to Parse (and related functions) and are applied in order.
+//
+type Option func(*Parser)
+
+// WithContext sets the initial user context value of the parser.
+// See Parser.Context() for details.
+//
+func WithContext(ctx interface{}) Option {
+	return func(p *Parser) {
+		p.context = ctx
+	}
+}
+
+// WithLenient enables lenient mode, converting post-EOF usage panics into no-ops that return zero values.
+// The first such usage is recorded and can be retrieved via Parser.MisuseError().
+// The same error is returned once by the ASTNexter, just before io.EOF, so callers learn the results are degraded.
+// This allows long-running services that run user-supplied parser functions to survive a misplaced call after EOF.
+// Other usage errors (range errors, invalid markers, etc.) still panic.
+//
+func WithLenient() Option {
+	return func(p *Parser) {
+		p.lenient = true
+	}
+}
+
+// WithNodeCache enables incremental parsing, using the specified cache to store and reuse ASTs.
+// See NodeCache for details.
+//
+func WithNodeCache(c *NodeCache) Option {
+	return func(p *Parser) {
+		p.nodes = c
+	}
+}
+
+// WithTrace registers a function to receive trace events for parser activity, including parser function enter/exit,
+// Next/Peek, emits, marker create/apply, and Clear.
+// Intended for debugging parser functions.
+// See TraceEvent for details.
+//
+func WithTrace(fn func(event TraceEvent)) Option {
+	return func(p *Parser) {
+		p.trace = fn
+	}
+}
+
+// WithRecorder records the sequence of parser function transitions into the specified recorder.
+// See Recorder for details.
+//
+func WithRecorder(r *Recorder) Option {
+	return func(p *Parser) {
+		p.recorder = r
+	}
+}
+
+// WithLoopGuard enables a watchdog that detects parser functions returning repeatedly without consuming tokens or
+// emitting ASTs.
+// After limit consecutive calls without progress, an error is emitted naming the function and position, and parsing
+// is terminated.
+// The error is emitted as a *LoopError.
+// A limit <= 0 disables the guard.
+//
+func WithLoopGuard(limit int) Option {
+	return func(p *Parser) {
+		p.loopLimit = limit
+	}
+}
+
+// WithStats directs the parser to record its counters into the specified Stats, allowing them to be retrieved after
+// parsing completes (or published, i.e. via expvar.Publish).
+// See Stats for details.
+//
+func WithStats(s *Stats) Option {
+	return func(p *Parser) {
+		if s.TokensByType == nil {
+			s.TokensByType = make(map[token.Type]int64)
+		}
+		p.stats = s
+	}
+}
+
+// WithCoverage records parser function calls, and named points (see Parser.Cover), into the specified coverage.
+// See Coverage for details.
+//
+func WithCoverage(c *Coverage) Option {
+	return func(p *Parser) {
+		p.coverage = c
+	}
+}
+
+// WithOutputBuffer enables output batching, sizing the output buffer to hold at least n ASTs.
+// Emitted ASTs are delivered in batches: The parser continues calling parser functions until at least n ASTs are
+// pending, EOF is emitted, or Parser.Flush() is called.
+// Parser functions that emit many ASTs in a single call can then do so without growing the buffer.
+// An n <= 1 disables batching, delivering ASTs as soon as the parser function that emitted them returns.
+// NOTE: Has no effect when parsing via ParseEvents, as events are delivered immediately.
+//
+func WithOutputBuffer(n int) Option {
+	return func(p *Parser) {
+		if n < 1 {
+			n = 1
+		}
+		p.batch = n
+		p.output = newOutputRing(n)
+	}
+}
+
+// WithDiagnostics records warnings (see EmitWarning) and errors (see EmitError) into the specified collector.
+// A single collector can be shared with the lexer (see lexer.WithDiagnostics).
+//
+func WithDiagnostics(c *diag.Collector) Option {
+	return func(p *Parser) {
+		p.diags = c
+	}
+}
+
+// WithMaxErrors stops parsing once n errors have been emitted (see EmitError), protecting consumers from an avalanche
+// of errors on garbage input.
+// The nth error is followed by a final "too many errors" error, and EOF is emitted once the current parser function
+// returns; Any further errors emitted by the function are discarded.
+// An n <= 0 disables the limit (the default).
+//
+func WithMaxErrors(n int) Option {
+	return func(p *Parser) {
+		p.maxErrors = n
+	}
+}
+
+// WithLexErrors passes non-EOF errors returned by the token.Nexter (i.e. *lexer.Error) through the ASTNexter, as if
+// emitted via EmitError, and continues reading tokens, allowing parsing to continue past lexical errors.
+// Errors are delivered as they are read, so may precede ASTs emitted from the tokens read before them.
+// Matched tokens are not discarded, and the errors are not recorded with the diagnostics collector (see
+// WithDiagnostics), as the lexer records its own errors (see lexer.WithDiagnostics).
+// Passed errors count toward the error limit (see WithMaxErrors).
+// If the token.Nexter returns the same error again without returning a token in between (i.e.
+// lexer.ErrReadTimeout), it is not making progress, and the error is treated as below.
+// Without this option, the first such error is logged, and treated as the end of the input.
+//
+func WithLexErrors() Option {
+	return func(p *Parser) {
+		p.lexErrs = true
+	}
+}
+
+// WithEOFToken surfaces the end of the input as a real, peekable, token of the specified type (i.e. lexer.TEof), so
+// grammars can assert that the entire input was consumed via Expect(typ), instead of the indirect !CanPeek(1) idiom.
+// The token has an empty value, and is positioned at the end of the last token in the input.
+// NOTE: Parser functions are called for as long as tokens remain, so your grammar must match (or clear) the EOF token.
+//
+func WithEOFToken(typ token.Type) Option {
+	return func(p *Parser) {
+		p.eofToken = true
+		p.eofType = typ
+	}
+}
+
+// WithArena enables an arena for allocating the slices that make up your ASTs (see Parser.Arena), which can be
+// released all at once via the Release method of the ASTNexter, once done with the ASTs.
+// See Arena for details.
+//
+func WithArena(a *Arena) Option {
+	return func(p *Parser) {
+		p.arena = a
+	}
+}
+
+// WithChannels specifies the token channels visible to your parser functions (see token.Channel).
+// Tokens on other channels (i.e. whitespace and comments emitted on token.ChannelHidden) are skipped as they are read
+// from the input, and are not included in Stats.
+// By default, only token.ChannelDefault is visible.
+//
+func WithChannels(channels ...token.Channel) Option {
+	return func(p *Parser) {
+		p.channels = channels
+	}
+}
+
+// WithInteractive enables interactive mode, for REPLs reading the input a line at a time.
+// Errors emitted once the input is exhausted (i.e. an unclosed block) are wrapped as an *IncompleteError, and
+// incomplete errors returned from the lexer (see lexer.WithInteractive) are reported before EOF, instead of being
+// treated as EOF, allowing REPLs to distinguish "incomplete" from "invalid" via token.IsIncomplete(err), and prompt
+// for continuation lines.
+//
+func WithInteractive() Option {
+	return func(p *Parser) {
+		p.repl = true
+	}
+}
+
+// WithMaxDepth limits the depth of the rule stack (see Parser.EnterRule), protecting services parsing untrusted,
+// deeply-nested, input from stack exhaustion in recursive parser functions.
+// Once the limit is exceeded, the parser function is aborted, a *DepthError is emitted, and parsing is terminated.
+// An n <= 0 disables the limit (the default).
+//
+func WithMaxDepth(n int) Option {
+	return func(p *Parser) {
+		p.maxDepth = n
+	}
+}
+
+// WithPositionChecks enables position checks, for catching position bugs in lexers (and AST builders) early, i.e. in
+// tests.
+// As tokens are read from the input, each token is confirmed to neither precede nor overlap the previous token, and as
+// ASTs are emitted, the span of each AST (see Spanner and Spanned) is confirmed to lie within the span of the matched
+// tokens.
+// Failed checks panic, describing the offending token or AST.
+// Tokens with invalid positions, and ASTs emitted without matched tokens, are not checked.
+//
+// NOTE: Token streams that legitimately revisit positions (i.e. macro expansions) will fail the checks.
+//
+func WithPositionChecks() Option {
+	return func(p *Parser) {
+		p.checks = true
+	}
+}
+
+// WithPanicContext suffixes the messages of usage panics (i.e. range errors, post-EOF usage, invalid markers) with the
+// current parser function (or rule stack, see Parser.EnterRule) and position, making misuse during development
+// instantly locatable, i.e. "Parser.Peek: range error (in main.parseList at 3:14)".
+// The position is that of the next token, if already peeked, otherwise the end of the last token matched.
+//
+func WithPanicContext() Option {
+	return func(p *Parser) {
+		p.panicCtx = true
+	}
+}
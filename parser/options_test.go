@@ -0,0 +1,280 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TestWithContext
+//
+func TestWithContext(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		if ctx := p.Context(); ctx != "CTX" {
+			t.Errorf("Parser.Context() expecting 'CTX', received '%v'", ctx)
+		}
+		return nil
+	}
+	tokens := mockLexer(TOne)
+	nexter := Parse(tokens, fn, WithContext("CTX"))
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithLenient
+//
+func TestWithLenient(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		if p.MisuseError() != nil {
+			t.Error("Parser.MisuseError() expecting nil before EOF")
+		}
+		p.EmitEOF()
+		if tok := p.Peek(1); tok != nil {
+			t.Errorf("Parser.Peek(1) expecting nil, received '%v'", tok)
+		}
+		if typ := p.PeekType(1); typ != 0 {
+			t.Errorf("Parser.PeekType(1) expecting 0, received '%d'", typ)
+		}
+		if tok := p.Next(); tok != nil {
+			t.Errorf("Parser.Next() expecting nil, received '%v'", tok)
+		}
+		if tok := p.Last(); tok != nil {
+			t.Errorf("Parser.Last() expecting nil, received '%v'", tok)
+		}
+		if tokens := p.Matched(); len(tokens) != 0 {
+			t.Errorf("Parser.Matched() expecting empty slice, received '%v'", tokens)
+		}
+		p.Emit("AST")
+		p.EmitSpanned("AST")
+		p.EmitError("error")
+		p.BeginNode("node")
+		p.EndNode("node")
+		p.EmitEOF()
+		p.Clear()
+		err := p.MisuseError()
+		if err == nil || err.Error() != "Parser.Peek: No tokens can be peeked after EOF is emitted" {
+			t.Errorf("Parser.MisuseError() expecting first misuse, received '%v'", err)
+		}
+		return nil
+	}
+	tokens := mockLexer(TOne)
+	nexter := Parse(tokens, fn, WithLenient())
+	// Misuse is reported from the nexter, once, before EOF
+	//
+	expectNexterError(t, nexter, "Parser.Peek: No tokens can be peeked after EOF is emitted")
+	expectNexterEOF(t, nexter)
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithLenientRangeError
+//
+func TestWithLenientRangeError(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		assertPanic(t, func() {
+			p.Peek(0)
+		}, "Parser.Peek: range error")
+		return nil
+	}
+	tokens := mockLexer(TOne)
+	nexter := Parse(tokens, fn, WithLenient())
+	expectNexterEOF(t, nexter)
+}
+
+// parseConcat emits the values of all tokens as a single AST
+//
+func parseConcat(p *Parser) Fn {
+	var b strings.Builder
+	for p.CanPeek(1) {
+		b.WriteString(p.Next().Value())
+	}
+	p.Emit(b.String())
+	return nil
+}
+
+// TestWithLexErrors
+//
+func TestWithLexErrors(t *testing.T) {
+	tokens := token.ErrNexter(token.SliceNexter(
+		token.New(TOne, "a", 1, 1),
+		token.New(TTwo, "b", 1, 3),
+		token.New(TThree, "c", 1, 5),
+	), 1, errors.New("1:2: unexpected character"))
+	nexter := Parse(tokens, parseConcat, WithLexErrors())
+	// The error is delivered as it is read, without discarding the matched tokens
+	//
+	expectNexterError(t, nexter, "1:2: unexpected character")
+	expectNexterNext(t, nexter, "abc")
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithLexErrorsMaxErrors
+//
+func TestWithLexErrorsMaxErrors(t *testing.T) {
+	tokens := token.ErrNexter(token.ErrNexter(token.SliceNexter(
+		token.New(TOne, "a", 1, 1),
+		token.New(TTwo, "b", 1, 3),
+		token.New(TThree, "c", 1, 5),
+	), 1, errors.New("1:2: unexpected character")), 2, errors.New("1:4: unexpected character"))
+	nexter := Parse(tokens, parseConcat, WithLexErrors(), WithMaxErrors(1))
+	expectNexterError(t, nexter, "1:2: unexpected character")
+	expectNexterError(t, nexter, "too many errors")
+	expectNexterNext(t, nexter, "abc")
+	expectNexterEOF(t, nexter)
+}
+
+// stuckNexter returns the same error forever, as an input that is not making progress
+//
+type stuckNexter struct {
+	err error
+}
+
+// Next implements token.Nexter.Next().
+//
+func (n stuckNexter) Next() (token.Token, error) {
+	return nil, n.err
+}
+
+// TestWithLexErrorsRepeated confirms an error returned again, without a token in between, is treated as the end of
+// the input, instead of looping forever.
+//
+func TestWithLexErrorsRepeated(t *testing.T) {
+	nexter := Parse(stuckNexter{err: errors.New("read timeout")}, parseConcat, WithLexErrors())
+	expectNexterError(t, nexter, "read timeout")
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithEOFToken
+//
+func TestWithEOFToken(t *testing.T) {
+	const TEof = TThree
+	fn := func(p *Parser) Fn {
+		for p.PeekType(1) == TOne {
+			p.Next()
+		}
+		if tok, ok := p.Expect(TEof); !ok {
+			p.EmitFurthest()
+		} else if tok.Value() != "" || token.Start(tok) != (token.Position{Line: 1, Column: 4}) {
+			t.Errorf("Parser.Expect(TEof) received unexpected token {%d, '%s'} at %d:%d", tok.Type(), tok.Value(), tok.Line(), tok.Column())
+		} else {
+			p.Emit("AST")
+			if p.CanPeek(1) {
+				t.Error("Parser.CanPeek(1) expecting false after EOF token")
+			}
+		}
+		return nil
+	}
+	nexter := Parse(positioned(TOne, TOne), fn, WithEOFToken(TEof))
+	expectNexterNext(t, nexter, "AST")
+	expectNexterEOF(t, nexter)
+	nexter = Parse(positioned(TOne, TTwo), fn, WithEOFToken(TEof))
+	expectNexterError(t, nexter, "1:3: expected [3], found 2 'b'")
+	expectNexterEOF(t, nexter)
+	// Empty input
+	//
+	nexter = Parse(positioned(), func(p *Parser) Fn {
+		if p.PeekType(1) != TEof {
+			t.Errorf("Parser.PeekType(1) expecting %d, received %d", TEof, p.PeekType(1))
+		}
+		p.Next()
+		p.Emit("empty")
+		return nil
+	}, WithEOFToken(TEof))
+	expectNexterNext(t, nexter, "empty")
+	expectNexterEOF(t, nexter)
+}
+
+// channelToken is a Token with a channel.
+//
+type channelToken struct {
+	token.Token
+	channel token.Channel
+}
+
+// Channel implements token.Channeler.Channel().
+//
+func (t *channelToken) Channel() token.Channel {
+	return t.channel
+}
+
+// TestWithChannels
+//
+func TestWithChannels(t *testing.T) {
+	tokens := func() token.Nexter {
+		return token.SliceNexter(
+			token.New(TOne, "a", 1, 1),
+			&channelToken{Token: token.New(TTwo, " ", 1, 2), channel: token.ChannelHidden},
+			&channelToken{Token: token.New(TThree, "#x", 1, 3), channel: token.ChannelDirective},
+			token.New(TOne, "b", 1, 5),
+		)
+	}
+	fn := func(p *Parser) Fn {
+		var values []string
+		for p.CanPeek(1) {
+			values = append(values, p.Next().Value())
+		}
+		p.Emit(strings.Join(values, ","))
+		return nil
+	}
+	nexter := Parse(tokens(), fn)
+	expectNexterNext(t, nexter, "a,b")
+	expectNexterEOF(t, nexter)
+	nexter = Parse(tokens(), fn, WithChannels(token.ChannelDefault, token.ChannelDirective))
+	expectNexterNext(t, nexter, "a,#x,b")
+	expectNexterEOF(t, nexter)
+}
+
+// parsePanicPeek matches a token, then peeks out of range
+//
+func parsePanicPeek(p *Parser) Fn {
+	p.Next()
+	p.Peek(0)
+	return nil
+}
+
+// parsePanicAfterEOF matches a token after emitting EOF
+//
+func parsePanicAfterEOF(p *Parser) Fn {
+	p.Next()
+	p.EmitEOF()
+	p.Next()
+	return nil
+}
+
+// TestWithPanicContext
+//
+func TestWithPanicContext(t *testing.T) {
+	nexter := Parse(positioned(TOne, TTwo), parsePanicPeek, WithPanicContext())
+	assertPanic(t, func() {
+		_, _ = nexter.Next()
+	}, "Parser.Peek: range error (in github.com/tekwizely/go-parsing/parser.parsePanicPeek at 1:2)")
+
+	nexter = Parse(positioned(TOne, TTwo), func(p *Parser) Fn {
+		p.EnterRule("call")
+		p.Next()
+		p.EnterRule("argument-list")
+		p.CanPeek(1)
+		p.Marker()
+		m := p.Marker()
+		p.Clear()
+		m.Apply()
+		return nil
+	}, WithPanicContext())
+	assertPanic(t, func() {
+		_, _ = nexter.Next()
+	}, "Invalid marker (in argument-list of call at 1:3)")
+
+	nexter = Parse(positioned(TOne), parsePanicAfterEOF, WithPanicContext())
+	assertPanic(t, func() {
+		_, _ = nexter.Next()
+	}, "Parser.Next: No tokens can be matched after EOF is emitted (in github.com/tekwizely/go-parsing/parser.parsePanicAfterEOF)")
+}
+
+// TestWithoutPanicContext
+//
+func TestWithoutPanicContext(t *testing.T) {
+	nexter := Parse(positioned(TOne, TTwo), parsePanicPeek)
+	assertPanic(t, func() {
+		_, _ = nexter.Next()
+	}, "Parser.Peek: range error")
+}
@@ -0,0 +1,93 @@
+package parser
+
+// defaultOutputSize is the initial capacity of the output ring.
+//
+const defaultOutputSize = 8
+
+// outputRing is a growable ring buffer of emitted ASTs ready for pickup, with nil signifying EOF.
+// Unlike a list, emitting an AST does not allocate once the ring has grown to fit the output of a parser function.
+//
+type outputRing struct {
+	items []interface{} // Ring storage, len(items) is the capacity of the ring
+	head  int           // Index of the front AST
+	len   int           // Number of ASTs in the ring
+}
+
+// newOutputRing
+//
+func newOutputRing(size int) *outputRing {
+	if size < defaultOutputSize {
+		size = defaultOutputSize
+	}
+	return &outputRing{
+		items: make([]interface{}, size),
+		head:  0,
+		len:   0,
+	}
+}
+
+// Len returns the number of tokens in the ring.
+//
+func (r *outputRing) Len() int {
+	return r.len
+}
+
+// PushBack adds ast to the back of the ring, growing the ring if full.
+// ast may be nil, signifying EOF.
+//
+func (r *outputRing) PushBack(ast interface{}) {
+	if r.len == len(r.items) {
+		items := make([]interface{}, 2*len(r.items))
+		n := copy(items, r.items[r.head:])
+		copy(items[n:], r.items[:r.head])
+		r.items = items
+		r.head = 0
+	}
+	r.items[(r.head+r.len)%len(r.items)] = ast
+	r.len++
+}
+
+// reset removes all ASTs from the ring, retaining its capacity.
+//
+func (r *outputRing) reset() {
+	for ; r.len > 0; r.len-- {
+		r.items[r.head] = nil
+		r.head = (r.head + 1) % len(r.items)
+	}
+	r.head = 0
+}
+
+// PopFront removes and returns the AST at the front of the ring.
+// Returns nil if the ring is empty.
+//
+func (r *outputRing) PopFront() interface{} {
+	if r.len == 0 {
+		return nil
+	}
+	ast := r.items[r.head]
+	r.items[r.head] = nil
+	r.head = (r.head + 1) % len(r.items)
+	r.len--
+	return ast
+}
+
+// Flush ends the current output batch, delivering all pending ASTs to the consumer before any further parser
+// functions are called.
+// Only meaningful when batching is enabled (see WithOutputBuffer), as otherwise ASTs are always delivered as soon as
+// the parser function that emitted them returns.
+// Does nothing if no ASTs are pending.
+//
+func (p *Parser) Flush() {
+	if p.output.Len() > 0 {
+		p.flushed = true
+	}
+}
+
+// outputReady confirms if the pending ASTs should be delivered to the consumer.
+// ASTs are delivered once a full batch is pending (see WithOutputBuffer), once Flush is called, or once EOF is
+// emitted.
+//
+func (p *Parser) outputReady() bool {
+	n := p.output.Len()
+	return n > 0 && (n >= p.batch || p.flushed || p.eofOut)
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"strconv"
+	"testing"
+)
+
+// TestOutputRing
+//
+func TestOutputRing(t *testing.T) {
+	r := newOutputRing(0)
+	if r.PopFront() != nil {
+		t.Error("outputRing.PopFront() expecting nil for empty ring")
+	}
+	// Wrap the ring around, then force it to grow
+	//
+	next := 0
+	for i := 0; i < 5; i++ {
+		r.PushBack(i)
+	}
+	for i := 0; i < 3; i++ {
+		if ast := r.PopFront(); ast != next {
+			t.Errorf("outputRing.PopFront() expecting %d, received %v", next, ast)
+		}
+		next++
+	}
+	for i := 5; i < 20; i++ {
+		r.PushBack(i)
+	}
+	if r.Len() != 17 {
+		t.Errorf("outputRing.Len() expecting 17, received %d", r.Len())
+	}
+	for r.Len() > 0 {
+		if ast := r.PopFront(); ast != next {
+			t.Errorf("outputRing.PopFront() expecting %d, received %v", next, ast)
+		}
+		next++
+	}
+	if next != 20 {
+		t.Errorf("outputRing expecting 20 ASTs, received %d", next)
+	}
+}
+
+// countingParser emits the index of each token as an AST, counting the parser function calls.
+// If flushOn is not 0, Flush is called after emitting the AST for that index.
+//
+func countingParser(calls *int, flushOn int) Fn {
+	var fn Fn
+	fn = func(p *Parser) Fn {
+		*calls++
+		p.Next()
+		p.Emit(strconv.Itoa(*calls))
+		if *calls == flushOn {
+			p.Flush()
+		}
+		return fn
+	}
+	return fn
+}
+
+// expectNexterCalls
+//
+func expectNexterCalls(t *testing.T, nexter ASTNexter, match string, calls *int, callsMatch int) {
+	expectNexterNext(t, nexter, match)
+	if *calls != callsMatch {
+		t.Errorf("Parser expecting %d function calls before delivering '%s', received %d", callsMatch, match, *calls)
+	}
+}
+
+// TestWithOutputBuffer
+//
+func TestWithOutputBuffer(t *testing.T) {
+	calls := 0
+	nexter := Parse(mockLexer(TStart, TStart, TStart, TStart, TStart), countingParser(&calls, 0), WithOutputBuffer(3))
+	expectNexterCalls(t, nexter, "1", &calls, 3)
+	expectNexterCalls(t, nexter, "2", &calls, 3)
+	expectNexterCalls(t, nexter, "3", &calls, 3)
+	expectNexterCalls(t, nexter, "4", &calls, 5)
+	expectNexterCalls(t, nexter, "5", &calls, 5)
+	expectNexterEOF(t, nexter)
+}
+
+// TestWithOutputBufferDisabled
+//
+func TestWithOutputBufferDisabled(t *testing.T) {
+	calls := 0
+	nexter := Parse(mockLexer(TStart, TStart, TStart), countingParser(&calls, 0), WithOutputBuffer(0))
+	expectNexterCalls(t, nexter, "1", &calls, 1)
+	expectNexterCalls(t, nexter, "2", &calls, 2)
+	expectNexterCalls(t, nexter, "3", &calls, 3)
+	expectNexterEOF(t, nexter)
+}
+
+// TestFlush
+//
+func TestFlush(t *testing.T) {
+	calls := 0
+	nexter := Parse(mockLexer(TStart, TStart, TStart, TStart, TStart), countingParser(&calls, 2), WithOutputBuffer(3))
+	expectNexterCalls(t, nexter, "1", &calls, 2)
+	expectNexterCalls(t, nexter, "2", &calls, 2)
+	expectNexterCalls(t, nexter, "3", &calls, 5)
+	expectNexterCalls(t, nexter, "4", &calls, 5)
+	expectNexterCalls(t, nexter, "5", &calls, 5)
+	expectNexterEOF(t, nexter)
+}
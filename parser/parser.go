@@ -2,12 +2,19 @@ package parser
 
 import (
 	"container/list"
+	"errors"
+	"fmt"
 	"io"
 	"log"
 
+	"github.com/tekwizely/go-parsing/lexer/diag"
 	"github.com/tekwizely/go-parsing/lexer/token"
 )
 
+// TAny is a wildcard token type for Match, matching any token.
+//
+const TAny token.Type = -1
+
 // Fn are user functions that scan tokens and emit ASTs.
 // Functions are allowed to emit multiple ASTs within a single call-back.
 // The parser executes functions in a continuous loop until either the function returns nil or emits an EOF value.
@@ -19,26 +26,127 @@ type Fn func(*Parser) Fn
 // Parse initiates a parser against the input token stream.
 // The returned ASTNexter can be used to retrieve emitted ASTs.
 // The parser will auto-emit EOF before exiting it if has not already been emitted.
+// Options, if any, are applied before parsing begins.
 //
-func Parse(tokens token.Nexter, start Fn) ASTNexter {
-	p := newParser(tokens, start)
+func Parse(tokens token.Nexter, start Fn, opts ...Option) ASTNexter {
+	p := newParser(tokens, start, opts)
 	return &astNexter{parser: p}
 }
 
+// ParseAll parses the input token stream, returning all of the emitted ASTs.
+// Parsing stops at the first error, returning the ASTs emitted before it, along with the error.
+// This is a convenience method that simply drains the ASTNexter returned by Parse().
+//
+func ParseAll(tokens token.Nexter, start Fn, opts ...Option) ([]interface{}, error) {
+	nexter := Parse(tokens, start, opts...)
+	var asts []interface{}
+	for {
+		ast, err := nexter.Next()
+		if err == io.EOF {
+			return asts, nil
+		}
+		if err != nil {
+			return asts, err
+		}
+		asts = append(asts, ast)
+	}
+}
+
+// ParseOne parses the input token stream for grammars that produce exactly one root AST, returning the AST along
+// with the diagnostics recorded while parsing (see WithDiagnostics).
+// If no collector is specified via WithDiagnostics, one is created for the parse.
+// Returns the first error emitted, if any; Otherwise returns an error if no ASTs, or more than one AST, are emitted.
+// The first AST emitted (if any) is always returned, even alongside an error.
+//
+func ParseOne(tokens token.Nexter, start Fn, opts ...Option) (interface{}, []diag.Diagnostic, error) {
+	p := newParser(tokens, start, opts)
+	if p.diags == nil {
+		p.diags = diag.NewCollector()
+	}
+	nexter := &astNexter{parser: p}
+	var root interface{}
+	var first error
+	count := 0
+	for {
+		ast, err := nexter.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		if count == 0 {
+			root = ast
+		}
+		count++
+	}
+	if first == nil && count != 1 {
+		first = fmt.Errorf("ParseOne: expecting 1 AST, received %d", count)
+	}
+	return root, p.diags.Diagnostics(), first
+}
+
 // Parser is passed into your Parser.Fn functions and provides methods to inspect tokens and emit ASTs.
 // When your Parser.Fn is called, the parser guarantees that 'CanPeek(1) == true`, ensuring there is at least one token
 // to review/match.
 //
 type Parser struct {
-	input     token.Nexter  // Source of lexer tokens
-	cache     *list.List    // Cache of fetched lexer tokens, including matched & peeked
-	matchTail *list.Element // Points to last matched element in the cache, nil if no tokens matched yet
-	matchLen  int           // Len of peek buffer.  Makes growPeek faster when no growth needed
-	nextFn    Fn            // the next parsing function to enter
-	output    *list.List    // Cache of emitted ASTs ready for pickup
-	eof       bool          // Has EOF been reached on the input tokens? NOTE Peek buffer may still have tokens in it
-	eofOut    bool          // Has EOF been emitted to the output buffer?
-	markerID  int           // Incremented after each emit/clear - used to validate markers
+	input     token.Nexter     // Source of lexer tokens
+	cache     *list.List       // Cache of fetched lexer tokens, including matched & peeked
+	matchTail *list.Element    // Points to last matched element in the cache, nil if no tokens matched yet
+	matchLen  int              // Len of peek buffer.  Makes growPeek faster when no growth needed
+	nextFn    Fn               // the next parsing function to enter
+	output    *outputRing      // Cache of emitted ASTs ready for pickup
+	batch     int              // Min pending ASTs before delivery - see WithOutputBuffer()
+	flushed   bool             // Is the current batch ready for delivery? - see Flush()
+	eof       bool             // Has EOF been reached on the input tokens? NOTE Peek buffer may still have tokens in it
+	eofOut    bool             // Has EOF been emitted to the output buffer?
+	markerID  int              // Incremented after each emit/clear - used to validate markers
+	events    EventHandler     // Receives events when parsing via ParseEvents, nil otherwise
+	last      token.Token      // Last token discarded via emit/clear - see Last()
+	context   interface{}      // User context value - see Context()
+	lenient   bool             // Suppress post-EOF usage panics - see WithLenient()
+	misuse    error            // First post-EOF usage suppressed in lenient mode - see MisuseError()
+	nodes     *NodeCache       // Cache of reusable ASTs for incremental parsing - see WithNodeCache()
+	trace     func(TraceEvent) // Receives trace events - see WithTrace()
+	recorder  *Recorder        // Records function transitions - see WithRecorder()
+	loopLimit int              // Max consecutive calls without progress, 0 to disable - see WithLoopGuard()
+	idleCalls int              // Consecutive calls without progress
+	stats     *Stats           // Counters, nil if disabled - see WithStats()
+	coverage  *Coverage        // Records coverage - see WithCoverage()
+	ctxInit   interface{}      // Initial user context value, restored on Reset() - see WithContext()
+	discarded int              // Tokens discarded via emit/clear - see Index()
+	furthest  *Failure         // Failure at the furthest index reached - see Furthest()
+	diags     *diag.Collector  // Receives warnings and errors - see WithDiagnostics()
+	maxErrors int              // Max errors before parsing stops, 0 for no limit - see WithMaxErrors()
+	errCount  int              // Errors emitted
+	lexErrs   bool             // Pass non-EOF errors from the input through as emitted errors - see WithLexErrors()
+	lexErr    error            // Last error passed through, cleared once a token is read - see passError()
+	eofToken  bool             // Add a token of type eofType at the end of the input - see WithEOFToken()
+	eofType   token.Type       // Type of the EOF token - see WithEOFToken()
+	arena     *Arena           // Allocates slices for ASTs - see WithArena()
+	rules     ruleNames        // Rule names, keyed by function code pointer - see Rule()
+	frames    []ruleFrame      // Rule stack - see EnterRule()
+	channels  []token.Channel  // Visible token channels - see WithChannels()
+	repl      bool             // Report errors at the end of the input as incomplete - see WithInteractive()
+	pending   error            // Incomplete error returned from the lexer, not yet reported - see WithInteractive()
+	maxDepth  int              // Max rule stack depth, 0 for no limit - see WithMaxDepth()
+	panicCtx  bool             // Include the function / rule and position in panic messages - see WithPanicContext()
+	checks    bool             // Check token and AST positions - see WithPositionChecks()
+	prevRead  token.Token      // Last token read from the input, for position checks - see WithPositionChecks()
+	saved     []*Checkpoint    // Outstanding checkpoints, in creation order - see Checkpoint()
+}
+
+// MisuseError returns an error describing the first post-EOF usage suppressed in lenient mode.
+// Returns nil if no usage has been suppressed, or if the parser is not in lenient mode.
+// The same error is returned once by the ASTNexter, just before io.EOF.
+// See WithLenient.
+//
+func (p *Parser) MisuseError() error {
+	return p.misuse
 }
 
 // CanPeek confirms if the requested number of tokens are available in the peek buffer.
@@ -49,7 +157,7 @@ type Parser struct {
 //
 func (p *Parser) CanPeek(n int) bool {
 	if n < 1 {
-		panic("Parser.CanPeek: range error")
+		p.raise("Parser.CanPeek: range error")
 	}
 	// Nothing can be peeked after EOF emitted
 	//
@@ -64,19 +172,19 @@ func (p *Parser) CanPeek(n int) bool {
 // See CanPeek to confirm a minimum number of tokens are available in the peek buffer.
 // Panics if n < 1.
 // Panics if nth token not available.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 //
 func (p *Parser) Peek(n int) token.Token {
 	if n < 1 {
-		panic("Parser.Peek: range error")
+		p.raise("Parser.Peek: range error")
 	}
 	// Nothing can be peeked after EOF
 	//
-	if p.eofOut {
-		panic("Parser.Peek: No tokens can be peeked after EOF is emitted")
+	if p.afterEOF("Parser.Peek: No tokens can be peeked after EOF is emitted") {
+		return nil
 	}
 	if !p.growPeek(n) {
-		panic("Parser.Peek: No token available")
+		p.raise("Parser.Peek: No token available")
 	}
 	// Elements guaranteed to exist
 	//
@@ -84,6 +192,7 @@ func (p *Parser) Peek(n int) token.Token {
 	for ; n > 1; n-- {
 		e = e.Next()
 	}
+	p.traceEvent(TracePeek, e.Value.(token.Token), nil, "")
 	return e.Value.(token.Token)
 }
 
@@ -92,60 +201,308 @@ func (p *Parser) Peek(n int) token.Token {
 // See CanPeek to confirm a minimum number of tokens are available in the peek buffer.
 // Panics if n < 1.
 // Panics if nth token not available.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 // This is mostly a convenience method that calls Peek(n), returning the token type.
 //
 func (p *Parser) PeekType(n int) token.Type {
-	return p.Peek(n).Type()
+	tok := p.Peek(n)
+	// Peek returns nil after EOF in lenient mode
+	//
+	if tok == nil {
+		return 0
+	}
+	return tok.Type()
+}
+
+// PeekTypes allows you to look ahead at the types of the next n tokens without consuming them.
+// n is 1-based.
+// If fewer than n tokens are available, only the available types are returned.
+// Returns nil if EOF already emitted.
+// Panics if n < 1.
+//
+func (p *Parser) PeekTypes(n int) []token.Type {
+	if n < 1 {
+		p.raise("Parser.PeekTypes: range error")
+	}
+	// Nothing can be peeked after EOF emitted
+	//
+	if p.eofOut {
+		return nil
+	}
+	p.growPeek(n)
+	types := make([]token.Type, 0, n)
+	for e := p.peekHead(); e != nil && len(types) < n; e = e.Next() {
+		p.traceEvent(TracePeek, e.Value.(token.Token), nil, "")
+		types = append(types, e.Value.(token.Token).Type())
+	}
+	return types
+}
+
+// Match confirms if the next len(pattern) tokens are available and match the pattern types, in order.
+// Use TAny within the pattern to match any token type.
+// No tokens are consumed.
+// Returns true for an empty pattern.
+// Returns false if EOF already emitted (and pattern is not empty).
+//
+func (p *Parser) Match(pattern ...token.Type) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+	types := p.PeekTypes(len(pattern))
+	if len(types) < len(pattern) {
+		return false
+	}
+	for i, typ := range pattern {
+		if typ != TAny && typ != types[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// NotFollowedBy is a negative lookahead assertion: It confirms that the next len(types) tokens do not match the types,
+// in order.
+// Use TAny within types to match any token type.
+// No tokens are consumed.
+// Returns true if fewer than len(types) tokens are available.
+// Returns false for an empty types list, as the empty sequence always follows.
+// This is a convenience method that returns !Match(types...).
+//
+func (p *Parser) NotFollowedBy(types ...token.Type) bool {
+	return !p.Match(types...)
+}
+
+// PeekIn confirms if the nth token is available and its type is within the set.
+// n is 1-based.
+// No tokens are consumed.
+// Returns false if EOF already emitted.
+// Panics if n < 1.
+//
+func (p *Parser) PeekIn(n int, set token.Set) bool {
+	if n < 1 {
+		p.raise("Parser.PeekIn: range error")
+	}
+	return p.CanPeek(n) && set.Contains(p.PeekType(n))
+}
+
+// AcceptIn matches the next token if it is available and its type is within the set.
+// Returns the matched token and true, otherwise returns nil and false, with no tokens consumed.
+// Returns false if EOF already emitted.
+//
+func (p *Parser) AcceptIn(set token.Set) (token.Token, bool) {
+	if !p.PeekIn(1, set) {
+		return nil, false
+	}
+	return p.Next(), true
 }
 
 // Next matches and returns the next token in the input.
 // See CanPeek(1) to confirm if a token is available.
 // See Peek(1) and PeekType(1) to review the token before consuming it.
 // Panics if no token available.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 //
 func (p *Parser) Next() token.Token {
 	// Nothing can be peeked after EOF
 	//
-	if p.eofOut {
-		panic("Parser.Next: No tokens can be matched after EOF is emitted")
+	if p.afterEOF("Parser.Next: No tokens can be matched after EOF is emitted") {
+		return nil
 	}
 	if !p.growPeek(1) { // Cache next emit. 1-based
-		panic("Parser.Next: No token available")
+		p.raise("Parser.Next: No token available")
 	}
 	// Element guaranteed to exist
 	//
 	e := p.peekHead()
 	p.matchTail = e // Match peek into token
 	p.matchLen++
+	p.traceEvent(TraceNext, e.Value.(token.Token), nil, "")
 	return e.Value.(token.Token)
 }
 
+// TryPeek is a non-panicking variant of Peek.
+// n is 1-based.
+// Returns the nth token and true if available, otherwise returns nil and false.
+// Returns false if n < 1.
+// Returns false if EOF already emitted.
+//
+func (p *Parser) TryPeek(n int) (token.Token, bool) {
+	if n < 1 || !p.CanPeek(n) {
+		return nil, false
+	}
+	return p.Peek(n), true
+}
+
+// TryPeekType is a non-panicking variant of PeekType.
+// n is 1-based.
+// Returns the type of the nth token and true if available, otherwise returns 0 and false.
+// Returns false if n < 1.
+// Returns false if EOF already emitted.
+//
+func (p *Parser) TryPeekType(n int) (token.Type, bool) {
+	if tok, ok := p.TryPeek(n); ok {
+		return tok.Type(), true
+	}
+	return 0, false
+}
+
+// TryNext is a non-panicking variant of Next.
+// Matches and returns the next token and true if available, otherwise returns nil and false.
+// Returns false if EOF already emitted.
+//
+func (p *Parser) TryNext() (token.Token, bool) {
+	if !p.CanPeek(1) {
+		return nil, false
+	}
+	return p.Next(), true
+}
+
+// Context returns the user context value of the parser.
+// The context allows state, such as symbol tables and options, to travel with the parser, instead of requiring
+// closures or global variables.
+// Returns nil if no context has been set.
+// See SetContext and WithContext.
+//
+func (p *Parser) Context() interface{} {
+	return p.context
+}
+
+// SetContext sets the user context value of the parser.
+// The context is never inspected by the parser and can be set at any time, including after EOF is emitted.
+//
+func (p *Parser) SetContext(ctx interface{}) {
+	p.context = ctx
+}
+
+// Last returns the most-recently matched token.
+// If no tokens are currently matched, the last token discarded via Emit or Clear is returned instead.
+// Returns nil if no tokens have been matched yet.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (p *Parser) Last() token.Token {
+	// Nothing can be inspected after EOF emitted
+	//
+	if p.afterEOF("Parser.Last: No token inspection allowed after EOF is emitted") {
+		return nil
+	}
+	if p.matchLen > 0 {
+		return p.matchTail.Value.(token.Token)
+	}
+	return p.last
+}
+
+// Matched returns the tokens matched since the last Emit or Clear, in order.
+// The returned slice is a copy and can be retained (until the arena is released, if enabled - see WithArena).
+// Returns an empty slice if no tokens are currently matched.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (p *Parser) Matched() []token.Token {
+	// Nothing can be inspected after EOF emitted
+	//
+	if p.afterEOF("Parser.Matched: No token inspection allowed after EOF is emitted") {
+		return []token.Token{}
+	}
+	var tokens []token.Token
+	if p.arena != nil {
+		tokens = p.arena.Tokens(p.matchLen)
+	} else {
+		tokens = make([]token.Token, p.matchLen)
+	}
+	for n, e := 0, p.cache.Front(); n < p.matchLen; n, e = n+1, e.Next() {
+		tokens[n] = e.Value.(token.Token)
+	}
+	return tokens
+}
+
+// Index returns the index of the next token in the input, i.e. the number of tokens matched or discarded before it.
+// The index increases as tokens are matched, and only decreases when matched tokens are returned to the peek buffer
+// via Marker.Apply, making it suitable for memoization, ranking errors (i.e. furthest failure wins), and progress
+// reporting.
+// Index is 0-based, and is reset to 0 by Reset.
+//
+func (p *Parser) Index() int {
+	return p.discarded + p.matchLen
+}
+
+// Pos returns the position of the next token in the input.
+// If no tokens remain, the end position of the last token matched or discarded is returned instead.
+// Returns the zero Position if the input contains no tokens.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (p *Parser) Pos() token.Position {
+	// Nothing can be inspected after EOF emitted
+	//
+	if p.afterEOF("Parser.Pos: No token inspection allowed after EOF is emitted") {
+		return token.Position{}
+	}
+	return p.pos()
+}
+
+// pos returns the position of the next token in the input - see Pos().
+//
+func (p *Parser) pos() token.Position {
+	if p.growPeek(1) {
+		return token.Start(p.peekHead().Value.(token.Token))
+	}
+	if last := p.Last(); last != nil {
+		return token.End(last)
+	}
+	return token.Position{}
+}
+
 // Emit emits an AST.
 // All previously-matched tokens are discarded.
 // It is safe to emit nil via this method.
 // If the emit value is nil, then this is treated as EmitEOF().
 // All outstanding markers are invalidated after this call.
 // See EmitEOF for more details on the effects of emitting EOF.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 //
 func (p *Parser) Emit(ast interface{}) {
 	// Nothing can be emitted after EOF emitted
 	//
-	if p.eofOut {
-		panic("Parser.Emit: No further emits allowed after EOF is emitted")
+	if p.afterEOF("Parser.Emit: No further emits allowed after EOF is emitted") {
+		return
 	}
 	p.emit(ast)
 }
 
+// EmitError emits an error with the specified err string as the error text.
+// The error is returned from the ASTNexter in place of an AST.
+// When parsing via ParseEvents, the error is instead delivered to the EventHandler.
+// The error is also recorded by the diagnostics collector, if any (see WithDiagnostics), covering the
+// previously-matched tokens.
+// Once the error limit is reached (see WithMaxErrors), further errors are discarded.
+// All previously-matched tokens are discarded.
+// All outstanding markers are invalidated after this call.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (p *Parser) EmitError(err string) {
+	// Nothing can be emitted after EOF emitted
+	//
+	if p.afterEOF("Parser.EmitError: No further emits allowed after EOF is emitted") {
+		return
+	}
+	p.emitError(errors.New(err + p.ruleContext()))
+}
+
+// EmitErrorf emits an error with the formatted err string as the error text.
+// All previously-matched tokens are discarded.
+// All outstanding markers are invalidated after this call.
+// Panics if EOF already emitted (see WithLenient).
+// This is a convenience method that simply sends the formatted string to EmitError().
+//
+func (p *Parser) EmitErrorf(format string, args ...interface{}) {
+	p.EmitError(fmt.Sprintf(format, args...))
+}
+
 // EmitEOF emits a nil, discarding previously-matched tokens.
 // You will likely never need to call this directly, as Parse will auto-emit EOF (nil) before exiting,
 // if not already emitted.
 // No more reads to the underlying Lexer will happen once EOF is emitted.
 // No more tokens can be matched once EOF is emitted.
 // All outstanding markers are invalidated after this call.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 // This is a convenience method that simply calls Emit(nil).
 //
 func (p *Parser) EmitEOF() {
@@ -154,31 +511,174 @@ func (p *Parser) EmitEOF() {
 
 // Clear discards all previously-matched tokens without emitting any ASTs.
 // All outstanding markers are invalidated after this call.
-// Panics if EOF already emitted.
+// Panics if EOF already emitted (see WithLenient).
 //
 func (p *Parser) Clear() {
 	// Nothing can be cleared after EOF emitted
 	//
-	if p.eofOut {
-		panic("Parser.Clear: No clears allowed after EOF is emitted")
+	if p.afterEOF("Parser.Clear: No clears allowed after EOF is emitted") {
+		return
 	}
+	p.traceEvent(TraceClear, nil, nil, "")
 	p.clear()
 }
 
 // newParser
 //
-func newParser(tokens token.Nexter, start Fn) *Parser {
-	return &Parser{
+func newParser(tokens token.Nexter, start Fn, opts []Option) *Parser {
+	p := &Parser{
 		input:     tokens,
 		cache:     list.New(),
 		matchTail: nil,
 		matchLen:  0,
 		nextFn:    start,
-		output:    list.New(),
+		output:    newOutputRing(defaultOutputSize),
+		batch:     1,
+		flushed:   false,
 		eof:       false,
 		eofOut:    false,
 		markerID:  0,
+		events:    nil,
+		last:      nil,
+		context:   nil,
+		lenient:   false,
+		misuse:    nil,
+		nodes:     nil,
+		trace:     nil,
+		recorder:  nil,
+		loopLimit: 0,
+		idleCalls: 0,
+		stats:     nil,
+		coverage:  nil,
+		ctxInit:   nil,
+		discarded: 0,
+		furthest:  nil,
+		diags:     nil,
+		maxErrors: 0,
+		errCount:  0,
+		lexErrs:   false,
+		lexErr:    nil,
+		eofToken:  false,
+		eofType:   0,
+		arena:     nil,
+		rules:     nil,
+		frames:    nil,
+		channels:  []token.Channel{token.ChannelDefault},
+		repl:      false,
+		pending:   nil,
+		maxDepth:  0,
+		panicCtx:  false,
+		checks:    false,
+		prevRead:  nil,
+		saved:     nil,
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.ctxInit = p.context
+	return p
+}
+
+// Reset re-initializes the parser to parse a new token stream, starting with the specified function, allowing the
+// parser to be reused without re-allocating its internal buffers.
+// Options applied at creation remain in effect, and the user context is restored to its initial value (see
+// WithContext).
+// Counters (see Stats) accumulate across resets.
+// Any unread ASTs are discarded, and all outstanding markers are invalidated.
+// Intended for reusing parsers between inputs; See Pool.
+//
+func (p *Parser) Reset(tokens token.Nexter, start Fn) {
+	p.input = tokens
+	p.cache.Init()
+	p.matchTail = nil
+	p.matchLen = 0
+	p.nextFn = start
+	p.output.reset()
+	p.flushed = false
+	p.eof = false
+	p.eofOut = false
+	p.markerID++ // Invalidate outstanding markers
+	p.last = nil
+	p.context = p.ctxInit
+	p.misuse = nil
+	p.idleCalls = 0
+	p.discarded = 0
+	p.furthest = nil
+	p.errCount = 0
+	p.lexErr = nil
+	p.frames = p.frames[:0]
+	p.pending = nil
+	p.prevRead = nil
+	p.saved = nil
+}
+
+// afterEOF confirms if EOF has already been emitted, for methods that are not allowed after EOF.
+// In lenient mode, the first such usage is recorded (see MisuseError) and true is returned, allowing the caller to
+// return a zero value instead.
+// Panics with msg if EOF already emitted and not in lenient mode.
+//
+func (p *Parser) afterEOF(msg string) bool {
+	if !p.eofOut {
+		return false
+	}
+	if !p.lenient {
+		p.raise(msg)
+	}
+	if p.misuse == nil {
+		p.misuse = errors.New(msg)
+	}
+	return true
+}
+
+// raise panics with msg, suffixed with the current parser function (or rule) and position if WithPanicContext is set,
+// i.e. "Parser.Peek: range error (in argument-list of call at 3:14)".
+//
+func (p *Parser) raise(msg string) {
+	if p.panicCtx {
+		msg += p.panicContext()
+	}
+	panic(msg)
+}
+
+// panicContext describes the current parser function (or rule) and position, for panic messages (see raise).
+// Unlike Pos, the position is computed without reading from the input: The start of the next token, if already
+// peeked, otherwise the end of the last token matched (or discarded).
+//
+func (p *Parser) panicContext() string {
+	name := p.fnName(p.nextFn)
+	if len(p.frames) > 0 {
+		name = p.ruleChain()
+	}
+	pos := token.Position{Line: -1, Column: -1}
+	switch {
+	case p.cache.Len() > p.matchLen:
+		pos = token.Start(p.peekHead().Value.(token.Token))
+	case p.matchLen > 0:
+		pos = token.End(p.matchTail.Value.(token.Token))
+	case p.last != nil:
+		pos = token.End(p.last)
+	}
+	switch {
+	case name != "" && pos.Line > 0:
+		return fmt.Sprintf(" (in %s at %s)", name, pos)
+	case name != "":
+		return fmt.Sprintf(" (in %s)", name)
+	case pos.Line > 0:
+		return fmt.Sprintf(" (at %s)", pos)
+	}
+	return ""
+}
+
+// visible confirms if tok is on one of the visible channels (see WithChannels).
+//
+func (p *Parser) visible(tok token.Token) bool {
+	ch := token.ChannelOf(tok)
+	for _, c := range p.channels {
+		if c == ch {
+			return true
+		}
+	}
+	return false
 }
 
 // growPeek tries to ensure the peek buffer has Len() >= n, growing if needed, returning success or failure.
@@ -197,11 +697,27 @@ func (p *Parser) growPeek(n int) bool {
 		// Fetch next token from input
 		//
 		token, err := p.input.Next()
+		if token != nil && p.checks {
+			p.checkToken(token)
+		}
+		// Skip tokens on channels not visible to the grammar - see WithChannels()
+		//
+		if token != nil && !p.visible(token) {
+			token = nil
+		}
 		// Process any returned token, regardless of er
 		//
 		if token != nil {
 			p.cache.PushBack(token)
 			peekLen++
+			p.lexErr = nil
+			if p.stats != nil {
+				p.stats.TokensRead++
+				p.stats.TokensByType[token.Type()]++
+				if p.cache.Len() > p.stats.PeakBuffer {
+					p.stats.PeakBuffer = p.cache.Len()
+				}
+			}
 		}
 		// If there was an error, process it now
 		//
@@ -211,22 +727,56 @@ func (p *Parser) growPeek(n int) bool {
 			//
 			case io.EOF:
 				p.eof = true
+				peekLen += p.pushEOFToken()
 
 			// NON-EOF Error
 			//
 			default:
+				// In interactive mode, incomplete errors are reported before EOF is emitted
+				//
+				if p.deferIncomplete(err) {
+					p.eof = true
+					peekLen += p.pushEOFToken()
+					break
+				}
+				// Pass the error through, if enabled, and keep reading - see WithLexErrors()
+				//
+				if p.lexErrs && p.passError(err) {
+					break
+				}
 				// For lack of a better plan, treat as EOF for now
 				// TODO Think about how to handle non-EOF errors.
 				// TODO Expose upstream?
 				//
 				log.Printf("non-EOF error returned from lexer, treating as EOF: %v", err)
 				p.eof = true
+				peekLen += p.pushEOFToken()
 			}
 		}
 	}
 	return true
 }
 
+// pushEOFToken adds the EOF token to the peek buffer, if enabled (see WithEOFToken), returning the number of tokens
+// added.
+// The token is positioned at the end of the last token read, if any.
+//
+func (p *Parser) pushEOFToken() int {
+	if !p.eofToken {
+		return 0
+	}
+	line, column := 1, 1
+	if e := p.cache.Back(); e != nil {
+		pos := token.End(e.Value.(token.Token))
+		line, column = pos.Line, pos.Column
+	} else if p.last != nil {
+		pos := token.End(p.last)
+		line, column = pos.Line, pos.Column
+	}
+	p.cache.PushBack(token.New(p.eofType, "", line, column))
+	return 1
+}
+
 // peekHead computes the peek buffer head as a function of the matchTail.
 //
 func (p *Parser) peekHead() *list.Element {
@@ -251,13 +801,20 @@ func (p *Parser) emit(ast interface{}) {
 	// NOTE: This check is a fail-safe and will likely never hit as all public methods check/panic explicitly.
 	//
 	if p.eofOut {
-		panic("Parser: No further emits allowed after EOF is emitted")
+		p.raise("Parser: No further emits allowed after EOF is emitted")
 	}
+	p.traceEvent(TraceEmit, nil, ast, "")
 	// If emitting EOF
 	//
 	if ast == nil {
+		// Report any incomplete error from the lexer - see WithInteractive()
+		//
+		if p.pending != nil {
+			p.reportError(p.pending)
+		}
 		// Clear the peek buffer, discarding matched tokens
 		//
+		p.discarded += p.matchLen
 		p.matchTail = nil
 		p.matchLen = 0
 		p.cache.Init()
@@ -273,21 +830,113 @@ func (p *Parser) emit(ast interface{}) {
 		//
 		p.output.PushBack(nil)
 	} else {
+		if p.checks {
+			p.checkAST(ast)
+		}
 		p.clear()
 
+		if p.stats != nil {
+			p.stats.ASTsEmitted++
+		}
 		p.output.PushBack(ast)
 	}
 }
 
+// emitError emits an error, discarding the matched tokens.
+// Once the error limit is reached (see WithMaxErrors), the error is followed by a final "too many errors" error, and
+// further errors are discarded.
+//
+func (p *Parser) emitError(err error) {
+	if p.maxErrors > 0 && p.errCount >= p.maxErrors {
+		p.clear()
+		return
+	}
+	p.reportError(err)
+	p.errCount++
+	if p.errCount == p.maxErrors {
+		p.reportError(errors.New(tooManyErrors))
+	}
+}
+
+// reportError emits an error, discarding the matched tokens, and records it with the diagnostics collector.
+//
+func (p *Parser) reportError(err error) {
+	p.traceEvent(TraceError, nil, nil, err.Error())
+	if p.stats != nil {
+		p.stats.ErrorsEmitted++
+	}
+	d := p.diagnostic(err)
+	if p.diags != nil {
+		p.diags.Add(d)
+	}
+	if p.repl {
+		err = p.incomplete(err)
+	}
+	p.clear()
+	if p.events != nil {
+		p.events.Error(d)
+	} else {
+		p.output.PushBack(&emitError{err: err})
+	}
+}
+
+// diagnostic describes err as an error diagnostic, covering the matched tokens, or positioned at the failed token if
+// err is a *Failure.
+//
+func (p *Parser) diagnostic(err error) diag.Diagnostic {
+	if f, ok := err.(*Failure); ok {
+		return diag.Diagnostic{Severity: diag.Error, Span: token.Span{Start: f.Pos, End: f.Pos}, Message: f.message()}
+	}
+	return diag.Diagnostic{Severity: diag.Error, Span: p.matchSpan(), Message: err.Error()}
+}
+
+// passError delivers an error returned by the input, without discarding the matched tokens (see WithLexErrors).
+// Passed errors count toward the error limit (see WithMaxErrors), but are not recorded with the diagnostics collector.
+// Returns false, without delivering the error, if it repeats the last passed error with no token read in between, as
+// the input is not making progress (i.e. lexer.ErrReadTimeout).
+//
+func (p *Parser) passError(err error) bool {
+	if p.lexErr != nil && p.lexErr.Error() == err.Error() {
+		return false
+	}
+	p.lexErr = err
+	if p.maxErrors > 0 && p.errCount >= p.maxErrors {
+		return true
+	}
+	p.deliverError(err)
+	p.errCount++
+	if p.errCount == p.maxErrors {
+		p.deliverError(errors.New(tooManyErrors))
+	}
+	return true
+}
+
+// deliverError delivers an error to the event handler (see ParseEvents), or queues it for the ASTNexter.
+//
+func (p *Parser) deliverError(err error) {
+	p.traceEvent(TraceError, nil, nil, err.Error())
+	if p.events != nil {
+		p.events.Error(p.diagnostic(err))
+	} else {
+		p.output.PushBack(&emitError{err: err})
+	}
+}
+
 // clear consumes the matched tokens.
 // All outstanding markers are invalidated after this call.
 //
 func (p *Parser) clear() {
+	// Remember the last token, for Last()
+	//
+	if p.matchLen > 0 {
+		p.last = p.matchTail.Value.(token.Token)
+	}
 	// Discard tokens
 	//
 	for p.matchLen > 0 {
 		p.cache.Remove(p.cache.Front())
 		p.matchLen--
+		p.discarded++
 	}
 	// Invalidate outstanding markers
 	//
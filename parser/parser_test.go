@@ -2,11 +2,11 @@ package parser
 
 import (
 	"errors"
-	"io"
 	"log"
 	"strings"
 	"testing"
 
+	"github.com/tekwizely/go-parsing/lexer/diag"
 	"github.com/tekwizely/go-parsing/lexer/token"
 )
 
@@ -19,55 +19,16 @@ const (
 	TThree
 )
 
-// mockToken creates a token.Token from a token.Type
-//
-type mockToken struct {
-	typ token.Type
-}
-
-func (t *mockToken) Type() token.Type {
-	return t.typ
-}
-func (t *mockToken) Value() string {
-	return ""
-}
-func (t *mockToken) Line() int {
-	return -1
-}
-func (t *mockToken) Column() int {
-	return -1
-}
-
-// mockNexter creates a token.Nexter from a list of token.Type
-//
-type mockNexter struct {
-	tokens []token.Type
-	i      int
-	err    error
-}
-
-func (n *mockNexter) Next() (token.Token, error) {
-	if n.err != nil {
-		return nil, n.err
-	}
-	if n.i >= cap(n.tokens) {
-		return nil, io.EOF
-	}
-	t := n.tokens[n.i]
-	n.i++
-	return &mockToken{typ: t}, nil
-}
-
 // mockLexer
 //
 func mockLexer(tokens ...token.Type) token.Nexter {
-	return &mockNexter{tokens: tokens}
+	return token.TypesNexter(tokens...)
 }
 
 // mockLexerErr
 //
 func mockLexerErr(err error) token.Nexter {
-	return &mockNexter{err: err}
+	return token.ErrNexter(token.SliceNexter(), 0, err)
 }
 
 // assertPanic
@@ -140,6 +101,67 @@ func TestNilFn(t *testing.T) {
 	expectNexterEOF(t, nexter)
 }
 
+// TestParseAll
+//
+func TestParseAll(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		if p.Next().Type() == TTwo {
+			p.EmitError("two")
+		} else {
+			p.Emit("AST")
+		}
+		return p.nextFn
+	}
+	asts, err := ParseAll(mockLexer(TOne, TOne), fn)
+	if err != nil || len(asts) != 2 {
+		t.Errorf("ParseAll() expecting ([AST AST], nil), received (%v, %v)", asts, err)
+	}
+	asts, err = ParseAll(mockLexer(TOne, TTwo, TOne), fn)
+	if err == nil || err.Error() != "two" || len(asts) != 1 {
+		t.Errorf("ParseAll() expecting ([AST], 'two'), received (%v, %v)", asts, err)
+	}
+}
+
+// TestParseOne
+//
+func TestParseOne(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		switch p.Next().Type() {
+		case TTwo:
+			p.EmitWarning("two")
+		case TThree:
+			p.EmitError("three")
+			return p.nextFn
+		}
+		p.Emit("AST")
+		return p.nextFn
+	}
+	ast, diags, err := ParseOne(mockLexer(TTwo), fn)
+	if ast != "AST" || len(diags) != 1 || diags[0].Message != "two" || err != nil {
+		t.Errorf("ParseOne() expecting (AST, [two], nil), received (%v, %v, %v)", ast, diags, err)
+	}
+	ast, diags, err = ParseOne(mockLexer(TOne, TThree, TOne), fn)
+	if ast != "AST" || len(diags) != 1 || diags[0].Message != "three" || err == nil || err.Error() != "three" {
+		t.Errorf("ParseOne() expecting (AST, [three], three), received (%v, %v, %v)", ast, diags, err)
+	}
+	ast, _, err = ParseOne(mockLexer(TOne, TOne), fn)
+	if ast != "AST" || err == nil || err.Error() != "ParseOne: expecting 1 AST, received 2" {
+		t.Errorf("ParseOne() expecting (AST, [], 'ParseOne: expecting 1 AST, received 2'), received (%v, %v)", ast, err)
+	}
+	ast, _, err = ParseOne(mockLexer(), fn)
+	if ast != nil || err == nil || err.Error() != "ParseOne: expecting 1 AST, received 0" {
+		t.Errorf("ParseOne() expecting (nil, [], 'ParseOne: expecting 1 AST, received 0'), received (%v, %v)", ast, err)
+	}
+	// Shared collector
+	//
+	c := diag.NewCollector()
+	c.Report(diag.Info, token.Span{}, "lexer")
+	_, diags, _ = ParseOne(mockLexer(TTwo), fn, WithDiagnostics(c))
+	if len(diags) != 2 || diags[0].Message != "lexer" {
+		t.Errorf("ParseOne() expecting diagnostics [lexer two], received %v", diags)
+	}
+}
+
 func TestParserFnSkipedWhenNoCanPeek(t *testing.T) {
 	fn := func(p *Parser) Fn {
 		t.Error("Parser should not call Parser.Fn when CanPeek(1) == false")
@@ -480,6 +502,344 @@ func TestEmitAfterEOF(t *testing.T) {
 	}, "Parser.Emit: No further emits allowed after EOF is emitted")
 }
 
+// TestEmitError
+//
+func TestEmitError(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		m := p.Marker()
+		expectNext(t, p, TOne, "")
+		p.EmitError("ERROR")
+		expectMarkerValid(t, m, false)
+		expectNext(t, p, TTwo, "")
+		p.Emit("TTwo")
+		return nil
+	}
+	tokens := mockLexer(TOne, TTwo)
+	nexter := Parse(tokens, fn)
+	expectNexterError(t, nexter, "ERROR")
+	expectNexterNext(t, nexter, "TTwo")
+	expectNexterEOF(t, nexter)
+}
+
+// TestEmitErrorf
+//
+func TestEmitErrorf(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.EmitErrorf("ERROR: %s %d", "Error", 1)
+		return nil
+	}
+	tokens := mockLexer(TOne)
+	nexter := Parse(tokens, fn)
+	expectNexterError(t, nexter, "ERROR: Error 1")
+	expectNexterEOF(t, nexter)
+}
+
+// TestEmitErrorAfterEOF
+//
+func TestEmitErrorAfterEOF(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.EmitEOF()
+		p.EmitError("ERROR")
+		return nil
+	}
+	tokens := mockLexer(TOne)
+	nexter := Parse(tokens, fn)
+	assertPanic(t, func() {
+		_, _ = nexter.Next()
+	}, "Parser.EmitError: No further emits allowed after EOF is emitted")
+}
+
+// expectLast
+//
+func expectLast(t *testing.T, p *Parser, match token.Type) {
+	if tok := p.Last(); tok == nil {
+		t.Errorf("Parser.Last() expecting Token.Type '%d', received nil", match)
+	} else if tok.Type() != match {
+		t.Errorf("Parser.Last() expecting Token.Type '%d', received '%d'", match, tok.Type())
+	}
+}
+
+// expectMatched
+//
+func expectMatched(t *testing.T, p *Parser, match ...token.Type) {
+	tokens := p.Matched()
+	if len(tokens) != len(match) {
+		t.Errorf("Parser.Matched() expecting %d tokens, received %d", len(match), len(tokens))
+		return
+	}
+	for i, tok := range tokens {
+		if tok.Type() != match[i] {
+			t.Errorf("Parser.Matched()[%d] expecting Token.Type '%d', received '%d'", i, match[i], tok.Type())
+		}
+	}
+}
+
+// TestLastMatched
+//
+func TestLastMatched(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		if p.Last() != nil {
+			t.Error("Parser.Last() expecting nil before any tokens matched")
+		}
+		expectMatched(t, p)
+		m := p.Marker()
+		p.Next()
+		expectLast(t, p, TOne)
+		expectMatched(t, p, TOne)
+		p.Next()
+		expectLast(t, p, TTwo)
+		expectMatched(t, p, TOne, TTwo)
+		m.Apply()
+		if p.Last() != nil {
+			t.Error("Parser.Last() expecting nil after marker reset")
+		}
+		p.Next()
+		p.Next()
+		p.Emit("AST")
+		// Last survives Emit()
+		//
+		expectLast(t, p, TTwo)
+		expectMatched(t, p)
+		p.Next()
+		expectLast(t, p, TThree)
+		p.Clear()
+		expectLast(t, p, TThree)
+		return nil
+	}
+	tokens := mockLexer(TOne, TTwo, TThree)
+	nexter := Parse(tokens, fn)
+	expectNexterNext(t, nexter, "AST")
+	expectNexterEOF(t, nexter)
+}
+
+// TestLastMatchedAfterEOF
+//
+func TestLastMatchedAfterEOF(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.EmitEOF()
+		assertPanic(t, func() {
+			p.Last()
+		}, "Parser.Last: No token inspection allowed after EOF is emitted")
+		assertPanic(t, func() {
+			p.Matched()
+		}, "Parser.Matched: No token inspection allowed after EOF is emitted")
+		return nil
+	}
+	tokens := mockLexer(TOne)
+	nexter := Parse(tokens, fn)
+	expectNexterEOF(t, nexter)
+}
+
+// expectIndexPos
+//
+func expectIndexPos(t *testing.T, p *Parser, index int, line int, column int) {
+	if i := p.Index(); i != index {
+		t.Errorf("Parser.Index() expecting %d, received %d", index, i)
+	}
+	if pos := p.Pos(); pos != (token.Position{Line: line, Column: column}) {
+		t.Errorf("Parser.Pos() expecting %d:%d, received %v", line, column, pos)
+	}
+}
+
+// TestIndexPos
+//
+func TestIndexPos(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		expectIndexPos(t, p, 0, 1, 1)
+		m := p.Marker()
+		p.Next()
+		expectIndexPos(t, p, 1, 1, 5)
+		p.Next()
+		expectIndexPos(t, p, 2, 2, 1)
+		m.Apply()
+		expectIndexPos(t, p, 0, 1, 1)
+		p.Next()
+		p.Emit("AST")
+		expectIndexPos(t, p, 1, 1, 5)
+		m = p.Marker()
+		p.Next()
+		m.ClearTo()
+		expectIndexPos(t, p, 2, 2, 1)
+		p.Next()
+		expectIndexPos(t, p, 3, 2, 2)
+		p.Clear()
+		expectIndexPos(t, p, 3, 2, 2)
+		p.EmitEOF()
+		if i := p.Index(); i != 3 {
+			t.Errorf("Parser.Index() expecting 3 after EOF, received %d", i)
+		}
+		assertPanic(t, func() {
+			p.Pos()
+		}, "Parser.Pos: No token inspection allowed after EOF is emitted")
+		return nil
+	}
+	tokens := token.SliceNexter(token.New(TOne, "one", 1, 1), token.New(TTwo, "two", 1, 5), token.New(TThree, "3", 2, 1))
+	nexter := Parse(tokens, fn)
+	expectNexterNext(t, nexter, "AST")
+	expectNexterEOF(t, nexter)
+}
+
+// TestTryPeekNext
+//
+func TestTryPeekNext(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		if tok, ok := p.TryPeek(0); ok || tok != nil {
+			t.Error("Parser.TryPeek(0) expecting (nil, false)")
+		}
+		if typ, ok := p.TryPeekType(2); !ok || typ != TTwo {
+			t.Errorf("Parser.TryPeekType(2) expecting (%d, true), received (%d, %t)", TTwo, typ, ok)
+		}
+		if tok, ok := p.TryPeek(3); ok || tok != nil {
+			t.Error("Parser.TryPeek(3) expecting (nil, false)")
+		}
+		if _, ok := p.TryPeekType(3); ok {
+			t.Error("Parser.TryPeekType(3) expecting false")
+		}
+		if tok, ok := p.TryNext(); !ok || tok.Type() != TOne {
+			t.Errorf("Parser.TryNext() expecting (%d, true)", TOne)
+		}
+		if tok, ok := p.TryNext(); !ok || tok.Type() != TTwo {
+			t.Errorf("Parser.TryNext() expecting (%d, true)", TTwo)
+		}
+		if tok, ok := p.TryNext(); ok || tok != nil {
+			t.Error("Parser.TryNext() expecting (nil, false)")
+		}
+		p.EmitEOF()
+		if _, ok := p.TryPeek(1); ok {
+			t.Error("Parser.TryPeek(1) expecting false after EOF")
+		}
+		if _, ok := p.TryNext(); ok {
+			t.Error("Parser.TryNext() expecting false after EOF")
+		}
+		return nil
+	}
+	tokens := mockLexer(TOne, TTwo)
+	nexter := Parse(tokens, fn)
+	expectNexterEOF(t, nexter)
+}
+
+// TestPeekTypesMatch
+//
+func TestPeekTypesMatch(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		if types := p.PeekTypes(2); len(types) != 2 || types[0] != TOne || types[1] != TTwo {
+			t.Errorf("Parser.PeekTypes(2) expecting [%d %d], received %v", TOne, TTwo, types)
+		}
+		if types := p.PeekTypes(5); len(types) != 3 || types[2] != TThree {
+			t.Errorf("Parser.PeekTypes(5) expecting [%d %d %d], received %v", TOne, TTwo, TThree, types)
+		}
+		if !p.Match() {
+			t.Error("Parser.Match() expecting true for empty pattern")
+		}
+		if !p.Match(TOne, TAny, TThree) {
+			t.Error("Parser.Match(TOne, TAny, TThree) expecting true")
+		}
+		if p.Match(TOne, TThree) {
+			t.Error("Parser.Match(TOne, TThree) expecting false")
+		}
+		if p.Match(TOne, TTwo, TThree, TAny) {
+			t.Error("Parser.Match(TOne, TTwo, TThree, TAny) expecting false when too few tokens")
+		}
+		p.Next()
+		if types := p.PeekTypes(1); len(types) != 1 || types[0] != TTwo {
+			t.Errorf("Parser.PeekTypes(1) expecting [%d] after Next(), received %v", TTwo, types)
+		}
+		if !p.Match(TTwo, TThree) {
+			t.Error("Parser.Match(TTwo, TThree) expecting true after Next()")
+		}
+		assertPanic(t, func() { p.PeekTypes(0) }, "Parser.PeekTypes: range error")
+		p.EmitEOF()
+		if types := p.PeekTypes(1); types != nil {
+			t.Errorf("Parser.PeekTypes(1) expecting nil after EOF, received %v", types)
+		}
+		if p.Match(TAny) {
+			t.Error("Parser.Match(TAny) expecting false after EOF")
+		}
+		return nil
+	}
+	expectNexterEOF(t, Parse(mockLexer(TOne, TTwo, TThree), fn))
+}
+
+// TestNotFollowedBy
+//
+func TestNotFollowedBy(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		if p.NotFollowedBy(TOne, TAny) {
+			t.Error("Parser.NotFollowedBy(TOne, TAny) expecting false")
+		}
+		if !p.NotFollowedBy(TTwo) {
+			t.Error("Parser.NotFollowedBy(TTwo) expecting true")
+		}
+		if !p.NotFollowedBy(TOne, TTwo, TThree) {
+			t.Error("Parser.NotFollowedBy(TOne, TTwo, TThree) expecting true when too few tokens")
+		}
+		if p.NotFollowedBy() {
+			t.Error("Parser.NotFollowedBy() expecting false for empty types")
+		}
+		expectNext(t, p, TOne, "")
+		return nil
+	}
+	expectNexterEOF(t, Parse(mockLexer(TOne, TTwo), fn))
+}
+
+// TestPeekInAcceptIn
+//
+func TestPeekInAcceptIn(t *testing.T) {
+	ones := token.NewSet(TOne)
+	fn := func(p *Parser) Fn {
+		if !p.PeekIn(1, ones) || p.PeekIn(2, ones) || p.PeekIn(3, ones) {
+			t.Error("Parser.PeekIn() expecting true for 1st token only")
+		}
+		if tok, ok := p.AcceptIn(ones); !ok || tok.Type() != TOne {
+			t.Errorf("Parser.AcceptIn() expecting (%d, true)", TOne)
+		}
+		if tok, ok := p.AcceptIn(ones); ok || tok != nil {
+			t.Error("Parser.AcceptIn() expecting (nil, false)")
+		}
+		if tok, ok := p.AcceptIn(ones.With(TTwo)); !ok || tok.Type() != TTwo {
+			t.Errorf("Parser.AcceptIn() expecting (%d, true)", TTwo)
+		}
+		if _, ok := p.AcceptIn(ones.With(TTwo)); ok {
+			t.Error("Parser.AcceptIn() expecting false when no tokens available")
+		}
+		assertPanic(t, func() { p.PeekIn(0, ones) }, "Parser.PeekIn: range error")
+		p.EmitEOF()
+		if p.PeekIn(1, ones) {
+			t.Error("Parser.PeekIn() expecting false after EOF")
+		}
+		return nil
+	}
+	expectNexterEOF(t, Parse(mockLexer(TOne, TTwo), fn))
+}
+
+// TestContext
+//
+func TestContext(t *testing.T) {
+	fn1 := func(p *Parser) Fn {
+		if ctx := p.Context(); ctx != 2 {
+			t.Errorf("Parser.Context() expecting '2', received '%v'", ctx)
+		}
+		p.EmitEOF()
+		// Context still available after EOF
+		//
+		p.SetContext(3)
+		if ctx := p.Context(); ctx != 3 {
+			t.Errorf("Parser.Context() expecting '3', received '%v'", ctx)
+		}
+		return nil
+	}
+	fn := func(p *Parser) Fn {
+		if ctx := p.Context(); ctx != nil {
+			t.Errorf("Parser.Context() expecting nil, received '%v'", ctx)
+		}
+		p.SetContext(2)
+		return fn1
+	}
+	tokens := mockLexer(TOne)
+	nexter := Parse(tokens, fn)
+	expectNexterEOF(t, nexter)
+}
+
 // TestCanPeekAfterEOF
 //
 func TestCanPeekAfterEOF(t *testing.T) {
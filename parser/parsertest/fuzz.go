@@ -0,0 +1,84 @@
+//go:build go1.18
+// +build go1.18
+
+package parsertest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/lexertest"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// FuzzOption configures the invariants checked by FuzzParse.
+//
+type FuzzOption func(*fuzzConfig)
+
+// fuzzConfig holds the options for FuzzParse.
+//
+type fuzzConfig struct {
+	allowed []string
+}
+
+// AllowPanic allows panics whose message contains substr, for grammars that document panics on certain inputs.
+// Can be specified multiple times.
+//
+func AllowPanic(substr string) FuzzOption {
+	return func(c *fuzzConfig) {
+		c.allowed = append(c.allowed, substr)
+	}
+}
+
+// FuzzParse fuzzes the parser starting with parseFn, over tokens lexed starting with lexFn, asserting that, for
+// every input:
+//
+//  - Neither the lexer nor the parser panic (see AllowPanic)
+//  - Neither the lexer nor the parser loop without making progress (see lexertest.FuzzLoopLimit)
+//  - Once io.EOF is returned, further calls continue to return io.EOF
+//
+// Add seed inputs via f.Add() (or lexertest.AddCorpus) before calling FuzzParse:
+//
+//	func FuzzParseMyLang(f *testing.F) {
+//		f.Add("let x = 1")
+//		parsertest.FuzzParse(f, lexMyLang, parseMyLang)
+//	}
+//
+func FuzzParse(f *testing.F, lexFn lexer.Fn, parseFn parser.Fn, opts ...FuzzOption) {
+	c := &fuzzConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		defer func() {
+			if r := recover(); r != nil && !c.isAllowed(r) {
+				t.Fatalf("parsertest: panic on input %q: %v", input, r)
+			}
+		}()
+		tokens := lexer.LexString(input, lexFn, lexer.WithLoopGuard(lexertest.FuzzLoopLimit))
+		asts := parser.Parse(tokens, parseFn, parser.WithLoopGuard(lexertest.FuzzLoopLimit))
+		for _, err := asts.Next(); err != io.EOF; _, err = asts.Next() {
+			if _, ok := err.(*parser.LoopError); ok {
+				t.Fatalf("parsertest: %s on input %q", err, input)
+			}
+		}
+		if _, err := asts.Next(); err != io.EOF {
+			t.Fatalf("parsertest: expecting io.EOF after io.EOF, received %v on input %q", err, input)
+		}
+	})
+}
+
+// isAllowed confirms if the recovered panic value matches one of the allowed panics (see AllowPanic).
+//
+func (c *fuzzConfig) isAllowed(r interface{}) bool {
+	msg := fmt.Sprint(r)
+	for _, substr := range c.allowed {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+//go:build go1.18
+// +build go1.18
+
+package parsertest
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/lexertest"
+)
+
+// FuzzSum
+//
+func FuzzSum(f *testing.F) {
+	lexertest.AddCorpus(f, "testdata/*.txt")
+	f.Add("1+")
+	FuzzParse(f, lexSum, parseSum)
+}
@@ -0,0 +1,293 @@
+package parsertest
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// Term describes a terminal symbol for generated token streams: The token type, along with sample values to
+// choose from.
+// If Values is empty, tokens are generated with an empty value.
+//
+type Term struct {
+	Type   token.Type
+	Values []string
+}
+
+// Vocabulary maps terminal names to their terms.
+//
+type Vocabulary map[string]Term
+
+// Grammar maps non-terminal names to their alternative productions.
+// Each production is a sequence of symbol names, where each name is either a non-terminal within the grammar, or a
+// terminal within the Vocabulary.
+//
+type Grammar map[string][][]string
+
+// Generator produces random, type-valid, token streams for stress-testing parsers.
+// Without a grammar, streams are random sequences of terms from the vocabulary.
+// With a grammar, streams are random derivations of the start symbol.
+// Generated tokens are positioned on line 1, separated by a single column.
+// Output is deterministic for a given seed.
+//
+type Generator struct {
+	vocab    Vocabulary
+	terms    []string
+	grammar  Grammar
+	start    string
+	cost     map[string]int
+	rand     *rand.Rand
+	MaxLen   int // Maximum stream length, when generating without a grammar
+	MaxDepth int // Derivation depth beyond which the shortest productions are chosen, when generating with a grammar
+}
+
+// NewGenerator returns a Generator producing random sequences of terms from the vocabulary.
+// Panics if the vocabulary is empty.
+//
+func NewGenerator(seed int64, vocab Vocabulary) *Generator {
+	return newGenerator(seed, vocab, nil, "")
+}
+
+// NewGrammarGenerator returns a Generator producing random derivations of the start symbol from the grammar.
+// Panics if the vocabulary is empty, if the grammar references an unknown symbol, or if any non-terminal cannot
+// derive a finite stream.
+//
+func NewGrammarGenerator(seed int64, vocab Vocabulary, grammar Grammar, start string) *Generator {
+	return newGenerator(seed, vocab, grammar, start)
+}
+
+// newGenerator
+//
+func newGenerator(seed int64, vocab Vocabulary, grammar Grammar, start string) *Generator {
+	if len(vocab) == 0 {
+		panic("NewGenerator: vocabulary is empty")
+	}
+	terms := make([]string, 0, len(vocab))
+	for name := range vocab {
+		terms = append(terms, name)
+	}
+	sort.Strings(terms)
+	g := &Generator{
+		vocab:    vocab,
+		terms:    terms,
+		grammar:  grammar,
+		start:    start,
+		cost:     nil,
+		rand:     rand.New(rand.NewSource(seed)),
+		MaxLen:   20,
+		MaxDepth: 10,
+	}
+	if grammar != nil {
+		g.cost = g.computeCosts()
+		if _, ok := g.cost[start]; !ok {
+			panic(fmt.Sprintf("NewGrammarGenerator: start symbol '%s' cannot derive a finite stream", start))
+		}
+	}
+	return g
+}
+
+// computeCosts computes the length of the shortest stream each non-terminal can derive.
+// Non-terminals that cannot derive a finite stream are omitted.
+//
+func (g *Generator) computeCosts() map[string]int {
+	for name, alts := range g.grammar {
+		for _, alt := range alts {
+			for _, sym := range alt {
+				if _, ok := g.grammar[sym]; !ok {
+					if _, ok = g.vocab[sym]; !ok {
+						panic(fmt.Sprintf("NewGrammarGenerator: unknown symbol '%s' in production for '%s'", sym, name))
+					}
+				}
+			}
+		}
+	}
+	cost := make(map[string]int)
+	for changed := true; changed; {
+		changed = false
+		for name, alts := range g.grammar {
+			for _, alt := range alts {
+				if c, ok := g.altCost(alt, cost); ok {
+					if old, found := cost[name]; !found || c < old {
+						cost[name] = c
+						changed = true
+					}
+				}
+			}
+		}
+	}
+	for name := range g.grammar {
+		if _, ok := cost[name]; !ok {
+			panic(fmt.Sprintf("NewGrammarGenerator: '%s' cannot derive a finite stream", name))
+		}
+	}
+	return cost
+}
+
+// altCost computes the length of the shortest stream the production can derive, given the known costs.
+// Returns false if any non-terminal within the production has no known cost.
+//
+func (g *Generator) altCost(alt []string, cost map[string]int) (int, bool) {
+	total := 0
+	for _, sym := range alt {
+		if _, ok := g.grammar[sym]; !ok {
+			total++
+		} else if c, ok := cost[sym]; ok {
+			total += c
+		} else {
+			return 0, false
+		}
+	}
+	return total, true
+}
+
+// Generate returns a new random token stream.
+//
+func (g *Generator) Generate() []token.Token {
+	var names []string
+	if g.grammar != nil {
+		names = g.derive(g.start, 0, nil)
+	} else {
+		for n := g.rand.Intn(g.MaxLen + 1); n > 0; n-- {
+			names = append(names, g.terms[g.rand.Intn(len(g.terms))])
+		}
+	}
+	tokens := make([]token.Token, 0, len(names))
+	column := 1
+	for _, name := range names {
+		term := g.vocab[name]
+		value := ""
+		if len(term.Values) > 0 {
+			value = term.Values[g.rand.Intn(len(term.Values))]
+		}
+		tokens = append(tokens, token.New(term.Type, value, 1, column))
+		column += len([]rune(value)) + 1
+	}
+	return tokens
+}
+
+// derive appends a random derivation of sym to names.
+// Beyond MaxDepth, only the shortest productions are chosen, ensuring the derivation terminates.
+//
+func (g *Generator) derive(sym string, depth int, names []string) []string {
+	alts, ok := g.grammar[sym]
+	if !ok {
+		return append(names, sym)
+	}
+	var alt []string
+	if depth < g.MaxDepth {
+		alt = alts[g.rand.Intn(len(alts))]
+	} else {
+		for _, a := range alts {
+			if c, ok := g.altCost(a, g.cost); ok && c == g.cost[sym] {
+				alt = a
+				break
+			}
+		}
+	}
+	for _, s := range alt {
+		names = g.derive(s, depth+1, names)
+	}
+	return names
+}
+
+// Tokens returns a token.Nexter over the tokens, for feeding generated streams to the parser.
+//
+func Tokens(tokens []token.Token) token.Nexter {
+	return token.SliceNexter(tokens...)
+}
+
+// propertyLoopLimit is the loop guard limit (see parser.WithLoopGuard) used by ParseProperty.
+//
+const propertyLoopLimit = 1000
+
+// Property is a function that checks a generated token stream, returning a non-nil error if the check fails.
+//
+type Property func(tokens []token.Token) error
+
+// ParseProperty returns a Property that parses the token stream starting with parseFn, failing on runaway loops
+// (see parser.WithLoopGuard).
+// When used with Check, panics also fail the property.
+// Emitted ASTs and errors are otherwise ignored.
+//
+func ParseProperty(parseFn parser.Fn) Property {
+	return func(tokens []token.Token) error {
+		asts := parser.Parse(Tokens(tokens), parseFn, parser.WithLoopGuard(propertyLoopLimit))
+		for _, err := asts.Next(); err != io.EOF; _, err = asts.Next() {
+			if _, ok := err.(*parser.LoopError); ok {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Check runs prop against the specified number of generated token streams.
+// A panic within prop is treated as a failure.
+// On failure, the failing stream is shrunk (see Shrink) and the test fails, reporting the shrunk stream.
+//
+func Check(t *testing.T, g *Generator, runs int, prop Property) {
+	t.Helper()
+	for run := 1; run <= runs; run++ {
+		tokens := g.Generate()
+		if err := checkProperty(prop, tokens); err != nil {
+			shrunk := Shrink(tokens, func(tokens []token.Token) bool { return checkProperty(prop, tokens) != nil })
+			t.Fatalf("parsertest: property failed on run %d (shrunk from %d to %d tokens): %s\n%s",
+				run, len(tokens), len(shrunk), checkProperty(prop, shrunk), FormatTokens(shrunk))
+		}
+	}
+}
+
+// checkProperty calls prop, converting panics into errors.
+//
+func checkProperty(prop Property, tokens []token.Token) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return prop(tokens)
+}
+
+// Shrink reduces a failing token stream to a smaller stream that still fails, by repeatedly removing chunks of
+// tokens, from large to small, until no single token can be removed.
+// fails should return true if the stream still exhibits the failure.
+// Shrunk streams preserve the relative order of tokens, but may no longer be valid derivations of a grammar.
+//
+func Shrink(tokens []token.Token, fails func([]token.Token) bool) []token.Token {
+	for chunk := (len(tokens) + 1) / 2; chunk >= 1; {
+		removed := false
+		for i := 0; i+chunk <= len(tokens); {
+			candidate := make([]token.Token, 0, len(tokens)-chunk)
+			candidate = append(append(candidate, tokens[:i]...), tokens[i+chunk:]...)
+			if fails(candidate) {
+				tokens = candidate
+				removed = true
+			} else {
+				i += chunk
+			}
+		}
+		if !removed {
+			chunk /= 2
+		} else if chunk > (len(tokens)+1)/2 {
+			chunk = (len(tokens) + 1) / 2
+		}
+	}
+	return tokens
+}
+
+// FormatTokens returns the tokens formatted one per line, as "line:column type value", with quoted values.
+//
+func FormatTokens(tokens []token.Token) string {
+	var lines []string
+	for _, tok := range tokens {
+		lines = append(lines, fmt.Sprintf("%s %d %q", token.Start(tok), tok.Type(), tok.Value()))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,148 @@
+package parsertest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// sumVocab
+//
+var sumVocab = Vocabulary{
+	"num":  {Type: TNum, Values: []string{"1", "22", "333"}},
+	"plus": {Type: TPlus, Values: []string{"+"}},
+}
+
+// sumGrammar
+//
+var sumGrammar = Grammar{
+	"expr": {{"num"}, {"num", "plus", "expr"}},
+}
+
+// expectPanic
+//
+func expectPanic(t *testing.T, msg string, fn func()) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expecting panic '%s'", msg)
+		} else if r != msg {
+			t.Errorf("expecting panic '%s', received '%v'", msg, r)
+		}
+	}()
+	fn()
+}
+
+// TestGenerate
+//
+func TestGenerate(t *testing.T) {
+	g1, g2 := NewGenerator(42, sumVocab), NewGenerator(42, sumVocab)
+	g1.MaxLen = 5
+	g2.MaxLen = 5
+	for i := 0; i < 20; i++ {
+		a, b := FormatTokens(g1.Generate()), FormatTokens(g2.Generate())
+		if a != b {
+			t.Fatalf("Generate expecting deterministic output, received:\n%s\nvs\n%s", a, b)
+		}
+	}
+	tokens := g1.Generate()
+	if len(tokens) > 5 {
+		t.Errorf("Generate expecting at most 5 tokens, received %d", len(tokens))
+	}
+	column := 1
+	for _, tok := range tokens {
+		if tok.Line() != 1 || tok.Column() != column {
+			t.Errorf("Generate expecting token at 1:%d, received %s", column, token.Start(tok))
+		}
+		column += len(tok.Value()) + 1
+	}
+}
+
+// TestGenerateGrammar
+//
+func TestGenerateGrammar(t *testing.T) {
+	g := NewGrammarGenerator(7, sumVocab, sumGrammar, "expr")
+	g.MaxDepth = 6
+	for i := 0; i < 50; i++ {
+		tokens := g.Generate()
+		if len(tokens)%2 != 1 {
+			t.Fatalf("Generate expecting odd token count, received:\n%s", FormatTokens(tokens))
+		}
+		for j, tok := range tokens {
+			if want := []token.Type{TNum, TPlus}[j%2]; tok.Type() != want {
+				t.Fatalf("Generate expecting type %d at %d, received:\n%s", want, j, FormatTokens(tokens))
+			}
+		}
+		if len(tokens) > 2*6+1 {
+			t.Fatalf("Generate expecting derivation depth limit, received %d tokens", len(tokens))
+		}
+	}
+}
+
+// TestGeneratorPanics
+//
+func TestGeneratorPanics(t *testing.T) {
+	expectPanic(t, "NewGenerator: vocabulary is empty", func() { NewGenerator(0, Vocabulary{}) })
+	expectPanic(t, "NewGrammarGenerator: unknown symbol 'minus' in production for 'expr'", func() {
+		NewGrammarGenerator(0, sumVocab, Grammar{"expr": {{"num", "minus"}}}, "expr")
+	})
+	expectPanic(t, "NewGrammarGenerator: 'expr' cannot derive a finite stream", func() {
+		NewGrammarGenerator(0, sumVocab, Grammar{"expr": {{"num", "expr"}}}, "expr")
+	})
+	expectPanic(t, "NewGrammarGenerator: start symbol 'stmt' cannot derive a finite stream", func() {
+		NewGrammarGenerator(0, sumVocab, sumGrammar, "stmt")
+	})
+}
+
+// TestShrink
+//
+func TestShrink(t *testing.T) {
+	var tokens []token.Token
+	for i, typ := range []token.Type{TNum, TPlus, TNum, TNum, TPlus, TPlus, TNum, TNum} {
+		tokens = append(tokens, token.New(typ, "x", 1, i+1))
+	}
+	// Fails if two consecutive TPlus tokens are present
+	//
+	fails := func(tokens []token.Token) bool {
+		for i := 1; i < len(tokens); i++ {
+			if tokens[i-1].Type() == TPlus && tokens[i].Type() == TPlus {
+				return true
+			}
+		}
+		return false
+	}
+	shrunk := Shrink(tokens, fails)
+	if got, want := FormatTokens(shrunk), "1:5 4 \"x\"\n1:6 4 \"x\""; got != want {
+		t.Errorf("Shrink expecting:\n%s\nreceived:\n%s", want, got)
+	}
+	if shrunk = Shrink(tokens, func([]token.Token) bool { return true }); len(shrunk) != 0 {
+		t.Errorf("Shrink expecting empty stream, received:\n%s", FormatTokens(shrunk))
+	}
+}
+
+// parseStuck never consumes tokens
+//
+func parseStuck(_ *parser.Parser) parser.Fn {
+	return parseStuck
+}
+
+// TestCheck
+//
+func TestCheck(t *testing.T) {
+	g := NewGrammarGenerator(1, sumVocab, sumGrammar, "expr")
+	Check(t, g, 50, ParseProperty(parseSum))
+	if err := checkProperty(ParseProperty(parseSum), []token.Token{token.New(TPlus, "", -1, -1)}); err != nil {
+		t.Errorf("checkProperty expecting nil, received '%s'", err)
+	}
+	err := checkProperty(ParseProperty(parseStuck), []token.Token{token.New(TNum, "1", 1, 1)})
+	if _, ok := err.(*parser.LoopError); !ok {
+		t.Errorf("checkProperty expecting runaway loop error, received '%v'", err)
+	}
+	if err := checkProperty(func([]token.Token) error { panic("boom") }, nil); err == nil || err.Error() != "panic: boom" {
+		t.Errorf("checkProperty expecting 'panic: boom', received '%v'", err)
+	}
+	if err := checkProperty(func([]token.Token) error { return errors.New("bad") }, nil); err == nil || err.Error() != "bad" {
+		t.Errorf("checkProperty expecting 'bad', received '%v'", err)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Package parsertest provides golden-file snapshot testing and error assertions for parsers.
+
+Golden parses an input file and compares the pretty-printed ASTs against a golden file, failing the test with a line
+diff on any mismatch; GoldenJSON does the same using indented JSON:
+
+	func TestParseMyLang(t *testing.T) {
+		parsertest.Golden(t, "testdata/input.txt", "testdata/input.golden", lexMyLang, parseMyLang)
+	}
+
+Golden files are (re-)generated by running the tests with the -update flag (registered by the lexertest package):
+
+	$ go test -update
+
+ExpectErrors asserts the errors emitted while parsing:
+
+	parsertest.ExpectErrors(t, parser.Parse(lexer.LexString("1 +", lexMyLang), parseMyLang), "expecting operand")
+
+Generator produces random, type-valid, token streams from a vocabulary (and optionally a grammar), and Check runs a
+property against generated streams, shrinking any failing stream to a minimal reproduction:
+
+	vocab := parsertest.Vocabulary{
+		"num":  {Type: TNum, Values: []string{"1", "42"}},
+		"plus": {Type: TPlus, Values: []string{"+"}},
+	}
+	grammar := parsertest.Grammar{"expr": {{"num"}, {"num", "plus", "expr"}}}
+	g := parsertest.NewGrammarGenerator(seed, vocab, grammar, "expr")
+	parsertest.Check(t, g, 1000, parsertest.ParseProperty(parseExpr))
+
+FuzzParse (Go 1.18+) fuzzes a lexer + parser pair while asserting common invariants:
+
+	func FuzzParseMyLang(f *testing.F) {
+		lexertest.AddCorpus(f, "testdata/*.txt")
+		parsertest.FuzzParse(f, lexMyLang, parseMyLang)
+	}
+
+*/
+package parsertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/lexertest"
+	"github.com/tekwizely/go-parsing/parser"
+	"github.com/tekwizely/go-parsing/parser/ast"
+)
+
+// Golden lexes and parses the file at inputPath, and compares the pretty-printed ASTs (and errors) against the file
+// at goldenPath.
+// See Pretty for details on the format.
+// If the tests were run with -update, the golden file is (re-)written instead.
+//
+func Golden(t *testing.T, inputPath string, goldenPath string, lexFn lexer.Fn, parseFn parser.Fn) {
+	t.Helper()
+	lexertest.CompareGolden(t, goldenPath, Pretty(parse(t, inputPath, lexFn, parseFn)))
+}
+
+// GoldenJSON lexes and parses the file at inputPath, and compares the JSON-encoded ASTs (and errors) against the
+// file at goldenPath.
+// See JSON for details on the format.
+// If the tests were run with -update, the golden file is (re-)written instead.
+//
+func GoldenJSON(t *testing.T, inputPath string, goldenPath string, lexFn lexer.Fn, parseFn parser.Fn) {
+	t.Helper()
+	b, err := JSON(parse(t, inputPath, lexFn, parseFn))
+	if err != nil {
+		t.Fatalf("parsertest: %s", err)
+	}
+	lexertest.CompareGolden(t, goldenPath, b)
+}
+
+// parse reads the file at inputPath and returns the parser's ASTNexter, failing the test if the file cannot be read.
+//
+func parse(t *testing.T, inputPath string, lexFn lexer.Fn, parseFn parser.Fn) parser.ASTNexter {
+	t.Helper()
+	input, err := ioutil.ReadFile(inputPath)
+	if err != nil {
+		t.Fatalf("parsertest: %s", err)
+	}
+	return parser.Parse(lexer.LexBytes(input, lexFn), parseFn)
+}
+
+// Pretty drains the AST stream, returning each AST (or error) in emit order.
+// ASTs implementing ast.Node are printed as indented trees, one node per line, with each node printed via "%v" if
+// it implements fmt.Stringer, or as its type name otherwise.
+// All other ASTs are printed via "%+v".
+// Errors are printed as "error: <message>".
+//
+func Pretty(asts parser.ASTNexter) []byte {
+	var buf bytes.Buffer
+	for a, err := asts.Next(); err != io.EOF; a, err = asts.Next() {
+		if err != nil {
+			fmt.Fprintf(&buf, "error: %s\n", err)
+		}
+		if a != nil {
+			if n, ok := a.(ast.Node); ok {
+				writeTree(&buf, n, 0)
+			} else {
+				fmt.Fprintf(&buf, "%+v\n", a)
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+// writeTree writes node, and its children, to buf as an indented tree.
+//
+func writeTree(buf *bytes.Buffer, node ast.Node, depth int) {
+	indent := strings.Repeat("  ", depth)
+	switch n := node.(type) {
+	case nil:
+		fmt.Fprintf(buf, "%s<nil>\n", indent)
+		return
+	case fmt.Stringer:
+		fmt.Fprintf(buf, "%s%v\n", indent, n)
+	default:
+		fmt.Fprintf(buf, "%s%s\n", indent, reflect.TypeOf(n))
+	}
+	for _, child := range node.Children() {
+		writeTree(buf, child, depth+1)
+	}
+}
+
+// jsonEntry captures a single AST (or error) for JSON encoding.
+//
+type jsonEntry struct {
+	AST   interface{} `json:"ast,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// JSON drains the AST stream, returning an indented JSON array with an entry for each AST (or error), in emit order.
+// ASTs are encoded as {"ast": ...} and errors as {"error": "<message>"}.
+//
+func JSON(asts parser.ASTNexter) ([]byte, error) {
+	entries := []jsonEntry{}
+	for a, err := asts.Next(); err != io.EOF; a, err = asts.Next() {
+		if err != nil {
+			entries = append(entries, jsonEntry{Error: err.Error()})
+		}
+		if a != nil {
+			entries = append(entries, jsonEntry{AST: a})
+		}
+	}
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// ExpectErrors drains the AST stream and confirms that the emitted errors match the expected error messages, in order.
+// Emitted ASTs are ignored.
+//
+func ExpectErrors(t *testing.T, asts parser.ASTNexter, errs ...string) {
+	t.Helper()
+	var got []string
+	for _, err := asts.Next(); err != io.EOF; _, err = asts.Next() {
+		if err != nil {
+			got = append(got, err.Error())
+		}
+	}
+	for i := 0; i < len(errs) || i < len(got); i++ {
+		switch {
+		case i >= len(got):
+			t.Errorf("parsertest: expecting error '%s', received none", errs[i])
+		case i >= len(errs):
+			t.Errorf("parsertest: unexpected error '%s'", got[i])
+		case got[i] != errs[i]:
+			t.Errorf("parsertest: expecting error '%s', received '%s'", errs[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package parsertest
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/parser"
+	"github.com/tekwizely/go-parsing/parser/ast"
+)
+
+const (
+	TNum = lexer.TStart + iota
+	TPlus
+	TNewline
+)
+
+// sum is an ast.Node for "a + b"
+//
+type sum struct {
+	Left  ast.Node
+	Right ast.Node
+}
+
+func (s *sum) Children() []ast.Node               { return []ast.Node{s.Left, s.Right} }
+func (s *sum) WithChildren(c []ast.Node) ast.Node { return &sum{Left: c[0], Right: c[1]} }
+func (s *sum) String() string                     { return "+" }
+
+// num is a leaf ast.Node, without a String() method
+type num struct {
+	Value string
+}
+
+func (n *num) Children() []ast.Node               { return nil }
+func (n *num) WithChildren(_ []ast.Node) ast.Node { return n }
+
+// lexSum emits digits as TNum, '+' as TPlus, and newlines as TNewline
+//
+func lexSum(l *lexer.Lexer) lexer.Fn {
+	switch l.Next() {
+	case '+':
+		l.EmitToken(TPlus)
+	case '\n':
+		l.EmitToken(TNewline)
+	default:
+		l.EmitToken(TNum)
+	}
+	return lexSum
+}
+
+// parseSum parses "num + num" lines, emitting a *sum, or an error
+//
+func parseSum(p *parser.Parser) parser.Fn {
+	if p.CanPeek(3) && p.PeekType(1) == TNum && p.PeekType(2) == TPlus && p.PeekType(3) == TNum {
+		left, _, right := p.Next(), p.Next(), p.Next()
+		p.Emit(&sum{Left: &num{Value: left.Value()}, Right: &num{Value: right.Value()}})
+	} else {
+		for p.CanPeek(1) && p.PeekType(1) != TNewline {
+			p.Next()
+		}
+		p.EmitError("expecting num + num")
+	}
+	if p.CanPeek(1) {
+		p.Next()
+		p.Clear()
+	}
+	return parseSum
+}
+
+// parseString emits each token value as a string
+//
+func parseString(p *parser.Parser) parser.Fn {
+	p.Emit(p.Next().Value())
+	return parseString
+}
+
+// TestGolden
+//
+func TestGolden(t *testing.T) {
+	Golden(t, "testdata/sums.txt", "testdata/sums.golden", lexSum, parseSum)
+}
+
+// TestGoldenJSON
+//
+func TestGoldenJSON(t *testing.T) {
+	GoldenJSON(t, "testdata/sums.txt", "testdata/sums.json", lexSum, parseSum)
+}
+
+// TestPretty
+//
+func TestPretty(t *testing.T) {
+	got := string(Pretty(parser.Parse(lexer.LexString("1+2\n+\n", lexSum), parseSum)))
+	want := "" +
+		"+\n" +
+		"  *parsertest.num\n" +
+		"  *parsertest.num\n" +
+		"error: expecting num + num\n"
+	if got != want {
+		t.Errorf("Pretty expecting:\n%s\nreceived:\n%s", want, got)
+	}
+	got = string(Pretty(parser.Parse(lexer.LexString("1+", lexSum), parseString)))
+	if want = "1\n+\n"; got != want {
+		t.Errorf("Pretty expecting:\n%s\nreceived:\n%s", want, got)
+	}
+}
+
+// TestJSON
+//
+func TestJSON(t *testing.T) {
+	b, err := JSON(parser.Parse(lexer.LexString("1+2\n+\n", lexSum), parseSum))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[
+  {
+    "ast": {
+      "Left": {
+        "Value": "1"
+      },
+      "Right": {
+        "Value": "2"
+      }
+    }
+  },
+  {
+    "error": "expecting num + num"
+  }
+]
+`
+	if string(b) != want {
+		t.Errorf("JSON expecting:\n%s\nreceived:\n%s", want, b)
+	}
+}
+
+// TestGoldenMissing confirms golden files are created when run with -update
+//
+func TestGoldenMissing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parsertest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	golden := filepath.Join(dir, "sums.golden")
+	if err = flag.Set("update", "true"); err != nil {
+		t.Fatal(err)
+	}
+	Golden(t, "testdata/sums.txt", golden, lexSum, parseSum)
+	if err = flag.Set("update", "false"); err != nil {
+		t.Fatal(err)
+	}
+	Golden(t, "testdata/sums.txt", golden, lexSum, parseSum)
+}
+
+// TestExpectErrors
+//
+func TestExpectErrors(t *testing.T) {
+	ExpectErrors(t, parser.Parse(lexer.LexString("1+2\n+\n1\n", lexSum), parseSum), "expecting num + num", "expecting num + num")
+	ExpectErrors(t, parser.Parse(lexer.LexString("1+2\n", lexSum), parseSum))
+}
@@ -0,0 +1,237 @@
+/*
+Package parsetrace implements the parsetrace developer tool, which runs a lexer + parser pair with tracing enabled and
+renders the parser function transition / emit timeline, or a DOT graph of the observed function transitions.
+
+Lexer + parser pairs are registered by name, allowing you to build a parsetrace for your own parsers:
+
+	func main() {
+		parsetrace.Register("mylang", lexMyLang, parseMyLang)
+		parsetrace.Main()
+	}
+
+The cmd/parsetrace program ships with a built-in example pair.
+
+*/
+package parsetrace
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+// Pair describes a registered lexer + parser pair.
+//
+type Pair struct {
+	Name  string    // Name used to select the pair
+	Lex   lexer.Fn  // Starting lexer function
+	Parse parser.Fn // Starting parser function
+}
+
+// registry holds the registered pairs, keyed by name.
+//
+var registry = map[string]*Pair{}
+
+// Register registers a lexer + parser pair under the specified name.
+// Panics if the name is already registered.
+//
+func Register(name string, lex lexer.Fn, parse parser.Fn) {
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("parsetrace.Register: pair '%s' already registered", name))
+	}
+	registry[name] = &Pair{Name: name, Lex: lex, Parse: parse}
+}
+
+// Lookup returns the pair registered under the specified name, or nil if not found.
+//
+func Lookup(name string) *Pair {
+	return registry[name]
+}
+
+// Names returns the names of the registered pairs, sorted.
+//
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Collect parses the token stream with tracing enabled, returning the trace events, in order.
+// Emitted ASTs and errors are discarded, as they are captured by the trace.
+//
+func Collect(tokens token.Nexter, start parser.Fn) []parser.TraceEvent {
+	var events []parser.TraceEvent
+	asts := parser.Parse(tokens, start, parser.WithTrace(func(e parser.TraceEvent) {
+		events = append(events, e)
+	}))
+	for _, err := asts.Next(); err != io.EOF; _, err = asts.Next() {
+	}
+	return events
+}
+
+// ShortName returns the function name without its package path, i.e. "main.parseExpr".
+//
+func ShortName(fn string) string {
+	if i := strings.LastIndex(fn, "/"); i >= 0 {
+		return fn[i+1:]
+	}
+	return fn
+}
+
+// WriteTimeline writes the function transitions, emits, and errors to w, one per line.
+// Transitions are positioned at the start of the next token when the function was entered.
+// Unknown positions are written as "-".
+// If all is true, every trace event is written.
+//
+func WriteTimeline(w io.Writer, events []parser.TraceEvent, all bool) error {
+	var entered token.Position
+	for _, e := range events {
+		var line string
+		switch e.Kind {
+		case parser.TraceEnter:
+			entered = e.Pos
+			if !all {
+				continue
+			}
+			e.Fn = ShortName(e.Fn)
+			line = e.String()
+		case parser.TraceExit:
+			to := ShortName(e.Value)
+			if to == "" {
+				to = "(stop)"
+			}
+			line = fmt.Sprintf("%s\t%s -> %s", posString(entered), ShortName(e.Fn), to)
+		case parser.TraceEmit:
+			if e.AST == nil {
+				line = fmt.Sprintf("%s\temit EOF", posString(e.Pos))
+			} else {
+				line = fmt.Sprintf("%s\temit %v", posString(e.Pos), e.AST)
+			}
+		case parser.TraceError:
+			line = fmt.Sprintf("%s\terror %q", posString(e.Pos), e.Value)
+		default:
+			if !all {
+				continue
+			}
+			e.Fn = ShortName(e.Fn)
+			line = e.String()
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// posString returns the position as "line:column", or "-" if the position is not valid.
+//
+func posString(pos token.Position) string {
+	if !pos.IsValid() {
+		return "-"
+	}
+	return pos.String()
+}
+
+// WriteDOT writes a DOT graph of the observed function transitions to w.
+// Each edge is labeled with the number of times the transition was observed.
+//
+func WriteDOT(w io.Writer, events []parser.TraceEvent) error {
+	type edge struct{ from, to string }
+	counts := map[edge]int{}
+	var edges []edge
+	for _, e := range events {
+		if e.Kind != parser.TraceExit || e.Value == "" {
+			continue
+		}
+		k := edge{from: ShortName(e.Fn), to: ShortName(e.Value)}
+		if counts[k] == 0 {
+			edges = append(edges, k)
+		}
+		counts[k]++
+	}
+	b := &strings.Builder{}
+	b.WriteString("digraph parsetrace {\n")
+	for _, k := range edges {
+		fmt.Fprintf(b, "\t%q -> %q [label=\"%d\"];\n", k.from, k.to, counts[k])
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Run implements the parsetrace command, using the specified arguments (excluding the program name) and streams.
+// Input is read from the named file, or from stdin if no file is named.
+// Returns the process exit code.
+//
+func Run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("parsetrace", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	name := flags.String("pair", "", "name of the lexer + parser pair to use (see -list)")
+	format := flags.String("format", "timeline", "output format: timeline or dot")
+	all := flags.Bool("all", false, "include all trace events in the timeline")
+	list := flags.Bool("list", false, "list the registered pairs")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: parsetrace [-format timeline|dot] [-all] -pair name [file]")
+		fmt.Fprintln(stderr, "       parsetrace -list")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *list {
+		fmt.Fprintln(stdout, strings.Join(Names(), "\n"))
+		return 0
+	}
+	pair := Lookup(*name)
+	if pair == nil {
+		fmt.Fprintf(stderr, "parsetrace: unknown pair '%s' (available: %s)\n", *name, strings.Join(Names(), ", "))
+		return 2
+	}
+	if *format != "timeline" && *format != "dot" {
+		fmt.Fprintf(stderr, "parsetrace: unknown format '%s'\n", *format)
+		return 2
+	}
+	input := stdin
+	switch flags.NArg() {
+	case 0:
+	case 1:
+		f, err := os.Open(flags.Arg(0))
+		if err != nil {
+			fmt.Fprintf(stderr, "parsetrace: %s\n", err)
+			return 1
+		}
+		defer f.Close()
+		input = f
+	default:
+		flags.Usage()
+		return 2
+	}
+	events := Collect(lexer.LexReader(input, pair.Lex), pair.Parse)
+	var err error
+	if *format == "dot" {
+		err = WriteDOT(stdout, events)
+	} else {
+		err = WriteTimeline(stdout, events, *all)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "parsetrace: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+// Main runs the parsetrace command using the process arguments and standard streams, then exits.
+//
+func Main() {
+	os.Exit(Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
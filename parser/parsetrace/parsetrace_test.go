@@ -0,0 +1,109 @@
+package parsetrace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/parser"
+)
+
+const (
+	TNum = lexer.TStart + iota
+	TPlus
+)
+
+// lexSum emits digits as TNum and all else as TPlus
+//
+func lexSum(l *lexer.Lexer) lexer.Fn {
+	if r := l.Next(); r >= '0' && r <= '9' {
+		l.EmitToken(TNum)
+	} else {
+		l.EmitToken(TPlus)
+	}
+	return lexSum
+}
+
+// parseNum emits numbers, then switches to parseOp
+//
+func parseNum(p *parser.Parser) parser.Fn {
+	if p.Next().Type() != TNum {
+		p.EmitError("expecting number")
+		return nil
+	}
+	p.Emit(p.Last().Value())
+	return parseOp
+}
+
+// parseOp matches operators, then switches to parseNum
+//
+func parseOp(p *parser.Parser) parser.Fn {
+	p.Next()
+	p.Clear()
+	return parseNum
+}
+
+func init() {
+	Register("sum", lexSum, parseNum)
+}
+
+// expectRun
+//
+func expectRun(t *testing.T, args []string, input string, code int, stdout string, stderr string) {
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	if c := Run(args, strings.NewReader(input), out, errOut); c != code {
+		t.Errorf("Run(%v) expecting exit code %d, received %d", args, code, c)
+	}
+	if out.String() != stdout {
+		t.Errorf("Run(%v) expecting stdout:\n%s\nreceived:\n%s", args, stdout, out.String())
+	}
+	if !strings.Contains(errOut.String(), stderr) {
+		t.Errorf("Run(%v) expecting stderr to contain '%s', received '%s'", args, stderr, errOut.String())
+	}
+}
+
+// TestRunTimeline
+//
+func TestRunTimeline(t *testing.T) {
+	expectRun(t, []string{"-pair", "sum"}, "1+2++", 0, ""+
+		"1:1\temit 1\n"+
+		"1:1\tparsetrace.parseNum -> parsetrace.parseOp\n"+
+		"1:2\tparsetrace.parseOp -> parsetrace.parseNum\n"+
+		"1:3\temit 2\n"+
+		"1:3\tparsetrace.parseNum -> parsetrace.parseOp\n"+
+		"1:4\tparsetrace.parseOp -> parsetrace.parseNum\n"+
+		"1:5\terror \"expecting number\"\n"+
+		"1:5\tparsetrace.parseNum -> (stop)\n"+
+		"-\temit EOF\n", "")
+}
+
+// TestRunTimelineAll
+//
+func TestRunTimelineAll(t *testing.T) {
+	expectRun(t, []string{"-pair", "sum", "-all"}, "1", 0, ""+
+		"1:1 Enter (parsetrace.parseNum)\n"+
+		"1:1 Next 3 \"1\" (parsetrace.parseNum)\n"+
+		"1:1\temit 1\n"+
+		"1:1\tparsetrace.parseNum -> parsetrace.parseOp\n"+
+		"-\temit EOF\n", "")
+}
+
+// TestRunDOT
+//
+func TestRunDOT(t *testing.T) {
+	expectRun(t, []string{"-pair", "sum", "-format", "dot"}, "1+2+3", 0, ""+
+		"digraph parsetrace {\n"+
+		"\t\"parsetrace.parseNum\" -> \"parsetrace.parseOp\" [label=\"3\"];\n"+
+		"\t\"parsetrace.parseOp\" -> \"parsetrace.parseNum\" [label=\"2\"];\n"+
+		"}\n", "")
+}
+
+// TestRunErrors
+//
+func TestRunErrors(t *testing.T) {
+	expectRun(t, []string{"-list"}, "", 0, "sum\n", "")
+	expectRun(t, []string{"-pair", "nope"}, "", 2, "", "unknown pair 'nope' (available: sum)")
+	expectRun(t, []string{"-pair", "sum", "-format", "xml"}, "", 2, "", "unknown format 'xml'")
+	expectRun(t, []string{"-pair", "sum", "no-such-file"}, "", 1, "", "no-such-file")
+}
@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"sync"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Pool caches parsers for reuse across inputs, so servers parsing many small payloads avoid allocating a new parser
+// for each one.
+// All parsers in the pool share the options passed to NewPool; Avoid options that capture per-input state
+// (i.e. WithStats, WithRecorder, WithNodeCache) when using the pool concurrently.
+// A Pool is safe for concurrent use.
+//
+type Pool struct {
+	opts []Option
+	pool sync.Pool
+}
+
+// NewPool returns a new, empty, Pool whose parsers are configured with the specified options.
+//
+func NewPool(opts ...Option) *Pool {
+	return &Pool{opts: opts}
+}
+
+// Parse initiates a parser, taken from the pool, against the input token stream.
+// The returned ASTNexter can be used to retrieve emitted ASTs.
+// Return the nexter to the pool via Put() once done with it.
+//
+func (p *Pool) Parse(tokens token.Nexter, start Fn) ASTNexter {
+	if e, ok := p.pool.Get().(*astNexter); ok {
+		e.parser.Reset(tokens, start)
+		e.next = nil
+		e.eof = false
+		e.misused = false
+		return e
+	}
+	return &astNexter{parser: newParser(tokens, start, p.opts), pool: p}
+}
+
+// Put returns the parser backing the ASTNexter to the pool.
+// The nexter must not be used after this call.
+// Nexters not created via this pool's Parse() are ignored.
+//
+func (p *Pool) Put(nexter ASTNexter) {
+	if e, ok := nexter.(*astNexter); ok && e.pool == p {
+		e.parser.input = nil // Release the input
+		e.parser.output.reset()
+		p.pool.Put(e)
+	}
+}
@@ -0,0 +1,63 @@
+package parser
+
+import "testing"
+
+// parseTypes emits the name of each token type ("TStart" / "TOne")
+//
+func parseTypes(p *Parser) Fn {
+	if p.Next().Type() == TStart {
+		p.Emit("TStart")
+	} else {
+		p.Emit("TOne")
+	}
+	return parseTypes
+}
+
+// TestPool
+//
+func TestPool(t *testing.T) {
+	pool := NewPool(WithContext("CTX"))
+	fn := func(p *Parser) Fn {
+		if ctx := p.Context(); ctx != "CTX" {
+			t.Errorf("Parser.Context() expecting 'CTX', received '%v'", ctx)
+		}
+		p.SetContext("CHANGED")
+		return parseTypes(p)
+	}
+	nexter := pool.Parse(mockLexer(TStart, TOne), fn)
+	expectNexterNext(t, nexter, "TStart")
+	pool.Put(nexter)
+	nexter = pool.Parse(mockLexer(TOne), fn)
+	expectNexterNext(t, nexter, "TOne")
+	expectNexterEOF(t, nexter)
+	pool.Put(nexter)
+	// Foreign nexters are ignored
+	//
+	pool.Put(Parse(mockLexer(TOne), fn))
+	NewPool().Put(nexter)
+}
+
+// TestReset
+//
+func TestReset(t *testing.T) {
+	var m *Marker
+	nexter := Parse(mockLexer(TStart, TOne, TOne), func(p *Parser) Fn {
+		p.Next()
+		m = p.Marker()
+		p.Next()
+		p.Emit("TStart")
+		return nil
+	}, WithOutputBuffer(4))
+	expectNexterNext(t, nexter, "TStart")
+	e := nexter.(*astNexter)
+	p := e.parser
+	p.Reset(mockLexer(TOne, TStart), parseTypes)
+	e.eof = false
+	expectMarkerValid(t, m, false)
+	if p.Last() != nil || p.output.Len() != 0 {
+		t.Error("Parser.Reset() expecting initial state")
+	}
+	expectNexterNext(t, nexter, "TOne")
+	expectNexterNext(t, nexter, "TStart")
+	expectNexterEOF(t, nexter)
+}
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// parseRecordOne matches TOne tokens, switching to parseRecordOther otherwise
+//
+func parseRecordOne(p *Parser) Fn {
+	if p.PeekType(1) != TOne {
+		return parseRecordOther
+	}
+	p.Next()
+	p.Clear()
+	return parseRecordOne
+}
+
+// parseRecordOther matches non-TOne tokens, switching to parseRecordOne otherwise
+//
+func parseRecordOther(p *Parser) Fn {
+	if p.PeekType(1) == TOne {
+		return parseRecordOne
+	}
+	p.Next()
+	p.Clear()
+	return parseRecordOther
+}
+
+// TestWithRecorder
+//
+func TestWithRecorder(t *testing.T) {
+	r := &Recorder{}
+	nexter := Parse(mockPosLexer(), parseRecordOne, WithRecorder(r))
+	expectNexterEOF(t, nexter)
+	b := &bytes.Buffer{}
+	if err := r.Dump(b); err != nil {
+		t.Errorf("Recorder.Dump() received unexpected error '%s'", err)
+	}
+	dump := strings.Replace(b.String(), "github.com/tekwizely/go-parsing/parser.", "", -1)
+	expected := "1:1 parseRecordOne x2\n1:5 parseRecordOther x2\n"
+	if dump != expected {
+		t.Errorf("Recorder.Dump() expecting:\n%s\nreceived:\n%s", expected, dump)
+	}
+	transitions, err := ReadTransitions(strings.NewReader(b.String()))
+	if err != nil || len(transitions) != 2 || transitions[1] != r.Transitions()[1] {
+		t.Errorf("ReadTransitions() expecting %v, received %v (%v)", r.Transitions(), transitions, err)
+	}
+}
@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// ruleNames maps parser functions to rule names, keyed by function code pointer - see Rule().
+//
+type ruleNames map[uintptr]string
+
+// ruleFrame is an entry in the rule stack, recording the rule name and the position of the next token on entry.
+//
+type ruleFrame struct {
+	name string
+	pos  token.Position
+}
+
+// Rule names the parser function fn, returning fn, i.e:
+//
+//	return p.Rule("expression", parseExpression)
+//
+// Rule names give structure to otherwise anonymous function pointers, and are used in place of function names in
+// trace events (see WithTrace), recorded transitions (see WithRecorder) and runaway loop errors (see WithLoopGuard).
+// While a named function is running, its name is the outermost entry of the rule stack (see EnterRule).
+// Names are attached to the function's code, so all closures created from the same function literal share a name.
+// Returns nil if fn is nil.
+//
+func (p *Parser) Rule(name string, fn Fn) Fn {
+	if fn == nil {
+		return nil
+	}
+	pc := reflect.ValueOf(fn).Pointer()
+	if p.rules == nil {
+		p.rules = make(ruleNames)
+	}
+	if p.rules[pc] != name {
+		p.rules[pc] = name
+	}
+	return fn
+}
+
+// EnterRule pushes a rule onto the rule stack, recording the position of the next token.
+// Use it to name helper functions that are called directly, rather than returned as the next parser function.
+// While the stack is not empty, errors emitted via EmitError are suffixed with the chain of rules, innermost first,
+// along with the position of the innermost rule, i.e. "unexpected ')' while parsing argument-list of call at 3:14".
+// Every EnterRule should be paired with an ExitRule; Any rules left on the stack are exited when the parser function
+// returns.
+// If the depth limit is exceeded (see WithMaxDepth), the parser function is aborted, a *DepthError is emitted, and
+// parsing is terminated.
+//
+func (p *Parser) EnterRule(name string) {
+	p.frames = append(p.frames, ruleFrame{name: name, pos: p.pos()})
+	p.guardDepth()
+}
+
+// ExitRule pops the innermost rule from the rule stack.
+// Panics if the rule stack is empty.
+//
+func (p *Parser) ExitRule() {
+	if len(p.frames) == 0 {
+		p.raise("Parser.ExitRule: No rule entered")
+	}
+	p.frames = p.frames[:len(p.frames)-1]
+}
+
+// RuleName returns the name of the innermost rule on the rule stack (see Rule and EnterRule).
+// Returns "" if the rule stack is empty.
+//
+func (p *Parser) RuleName() string {
+	if len(p.frames) == 0 {
+		return ""
+	}
+	return p.frames[len(p.frames)-1].name
+}
+
+// RuleStack returns the names of the rules on the rule stack, outermost first.
+//
+func (p *Parser) RuleStack() []string {
+	names := make([]string, len(p.frames))
+	for i, f := range p.frames {
+		names[i] = f.name
+	}
+	return names
+}
+
+// ruleContext returns the error context for the rule stack, i.e. " while parsing argument-list of call at 3:14".
+// The position is omitted if unknown.
+// Returns "" if the rule stack is empty.
+//
+func (p *Parser) ruleContext() string {
+	if len(p.frames) == 0 {
+		return ""
+	}
+	b := &strings.Builder{}
+	b.WriteString(" while parsing ")
+	b.WriteString(p.ruleChain())
+	if pos := p.frames[len(p.frames)-1].pos; pos.Line > 0 {
+		b.WriteString(" at ")
+		b.WriteString(pos.String())
+	}
+	return b.String()
+}
+
+// ruleChain returns the rule stack, innermost first, i.e. "argument-list of call".
+//
+func (p *Parser) ruleChain() string {
+	names := make([]string, len(p.frames))
+	for i, f := range p.frames {
+		names[len(names)-1-i] = f.name
+	}
+	return strings.Join(names, " of ")
+}
+
+// enterFn pushes the rule name of fn onto the (empty) rule stack, if fn is named.
+//
+func (p *Parser) enterFn(fn Fn) {
+	if name := p.ruleOf(fn); name != "" {
+		p.frames = append(p.frames, ruleFrame{name: name, pos: p.pos()})
+	}
+}
+
+// exitFn exits any rules left on the rule stack after a parser function returns.
+//
+func (p *Parser) exitFn() {
+	p.frames = p.frames[:0]
+}
+
+// ruleOf returns the rule name of fn, or "" if fn is not named.
+//
+func (p *Parser) ruleOf(fn Fn) string {
+	if p.rules == nil || fn == nil {
+		return ""
+	}
+	return p.rules[reflect.ValueOf(fn).Pointer()]
+}
+
+// fnName returns the rule name of fn (see Rule), falling back to the name of the function itself.
+// Returns "" if fn is nil.
+//
+func (p *Parser) fnName(fn Fn) string {
+	if name := p.ruleOf(fn); name != "" {
+		return name
+	}
+	return fnName(fn)
+}
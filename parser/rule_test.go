@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// parseRuleStatement matches TOne, emitting an error for anything else
+//
+func parseRuleStatement(p *Parser) Fn {
+	if p.Next().Type() != TOne {
+		p.EmitError("expecting one")
+		return nil
+	}
+	p.Emit(p.RuleName())
+	return p.Rule("statement", parseRuleStatement)
+}
+
+// TestRule
+//
+func TestRule(t *testing.T) {
+	var events []string
+	trace := func(e TraceEvent) {
+		if e.Kind == TraceEnter {
+			events = append(events, e.Fn)
+		}
+	}
+	nexter := Parse(mockLexer(TOne, TTwo), parseRuleStatement, WithTrace(trace))
+	expectNexterNext(t, nexter, "")
+	expectNexterError(t, nexter, "expecting one while parsing statement")
+	expectNexterEOF(t, nexter)
+	got := strings.Replace(strings.Join(events, ","), "github.com/tekwizely/go-parsing/parser.", "", -1)
+	if got != "parseRuleStatement,statement" {
+		t.Errorf("Rule() expecting trace 'parseRuleStatement,statement', received '%s'", got)
+	}
+}
+
+// TestRuleNil
+//
+func TestRuleNil(t *testing.T) {
+	p := &Parser{}
+	if p.Rule("nil", nil) != nil {
+		t.Error("Rule(nil) expecting nil")
+	}
+	if p.RuleName() != "" {
+		t.Errorf("RuleName() expecting '', received '%s'", p.RuleName())
+	}
+}
+
+// TestRuleLoopGuard
+//
+func TestRuleLoopGuard(t *testing.T) {
+	var p *Parser
+	start := func(parser *Parser) Fn {
+		p = parser
+		return parser.Rule("stuck", parseStuck)
+	}
+	nexter := Parse(mockPosLexer(), start, WithLoopGuard(10))
+	expectNexterError(t, nexter, "1:1: runaway loop detected: stuck returned 10 times without consuming tokens or emitting ASTs")
+	expectNexterEOF(t, nexter)
+	if p.RuleName() != "" {
+		t.Errorf("RuleName() expecting '' after parsing, received '%s'", p.RuleName())
+	}
+}
+
+// parseRuleCall matches TOne as a call, with the arguments parsed by parseRuleArgs
+//
+func parseRuleCall(p *Parser) Fn {
+	p.Next()
+	parseRuleArgs(p)
+	if got := strings.Join(p.RuleStack(), ","); got != "call" {
+		p.EmitErrorf("unexpected rule stack '%s'", got)
+	}
+	p.EnterRule("unbalanced")
+	return nil
+}
+
+// parseRuleArgs matches TThree, emitting an error for anything else
+//
+func parseRuleArgs(p *Parser) {
+	p.EnterRule("argument-list")
+	defer p.ExitRule()
+	if p.Next().Type() != TThree {
+		p.EmitError("unexpected token")
+	}
+}
+
+// TestEnterRule
+//
+func TestEnterRule(t *testing.T) {
+	var p *Parser
+	start := func(parser *Parser) Fn {
+		p = parser
+		return parser.Rule("call", parseRuleCall)
+	}
+	nexter := Parse(mockPosLexer(), start)
+	expectNexterError(t, nexter, "unexpected token while parsing argument-list of call at 1:5")
+	expectNexterEOF(t, nexter)
+	if len(p.RuleStack()) != 0 {
+		t.Errorf("RuleStack() expecting [] after parsing, received %v", p.RuleStack())
+	}
+}
+
+// TestExitRulePanic
+//
+func TestExitRulePanic(t *testing.T) {
+	p := &Parser{}
+	assertPanic(t, func() {
+		p.ExitRule()
+	}, "Parser.ExitRule: No rule entered")
+}
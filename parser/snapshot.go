@@ -0,0 +1,160 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// snapshotVersion identifies the snapshot format, allowing future formats to reject older snapshots.
+//
+const snapshotVersion = 1
+
+// parserSnapshot is the serialized form of the parser state - see Snapshot.
+//
+type parserSnapshot struct {
+	Version   int
+	Tokens    []snapshotToken // Cached tokens, matched tokens first
+	MatchLen  int             // Number of matched tokens at the front of Tokens
+	EOF       bool            // Has EOF been reached on the input tokens?
+	EOFOut    bool            // Has EOF been emitted?
+	Output    []snapshotEmit
+	Flushed   bool
+	Last      *snapshotToken `json:",omitempty"`
+	Discarded int
+	ErrCount  int
+}
+
+// snapshotToken is the serialized form of a token.
+//
+type snapshotToken struct {
+	Type   int
+	Value  string
+	Line   int
+	Column int
+}
+
+// newSnapshotToken
+//
+func newSnapshotToken(t token.Token) snapshotToken {
+	return snapshotToken{Type: int(t.Type()), Value: t.Value(), Line: t.Line(), Column: t.Column()}
+}
+
+// token returns the token represented by the snapshot.
+//
+func (t snapshotToken) token() token.Token {
+	return token.New(token.Type(t.Type), t.Value, t.Line, t.Column)
+}
+
+// snapshotEmit is the serialized form of an emitted error or EOF, not yet delivered.
+//
+type snapshotEmit struct {
+	EOF   bool   `json:",omitempty"`
+	Error string `json:",omitempty"`
+}
+
+// Snapshot captures the state of the parser, allowing long-running interactive sessions (REPLs, notebook kernels) to
+// checkpoint parsing and resume it later, possibly in another process, via RestoreSnapshot.
+// The snapshot includes the buffered tokens (matched and peeked), and any errors emitted but not yet delivered.
+// Tokens are captured by type, value and position only (see token.New).
+// Parser functions, the user context and the options are not captured, and are supplied again when restoring.
+// Tokens not yet read by the parser are not captured either; it is the caller's responsibility to resume the token
+// stream from where the parser left off (i.e. via Lexer.Snapshot).
+// Returns an error if any emitted ASTs have not yet been delivered, as ASTs cannot be captured.
+//
+func (p *Parser) Snapshot() ([]byte, error) {
+	s := &parserSnapshot{
+		Version:   snapshotVersion,
+		MatchLen:  p.matchLen,
+		EOF:       p.eof,
+		EOFOut:    p.eofOut,
+		Flushed:   p.flushed,
+		Discarded: p.discarded,
+		ErrCount:  p.errCount,
+	}
+	for e := p.cache.Front(); e != nil; e = e.Next() {
+		s.Tokens = append(s.Tokens, newSnapshotToken(e.Value.(token.Token)))
+	}
+	for i := 0; i < p.output.Len(); i++ {
+		switch emit := p.output.items[(p.output.head+i)%len(p.output.items)].(type) {
+		case nil:
+			s.Output = append(s.Output, snapshotEmit{EOF: true})
+		case *emitError:
+			s.Output = append(s.Output, snapshotEmit{Error: emit.err.Error()})
+		default:
+			return nil, errors.New("Parser.Snapshot: emitted ASTs pending delivery cannot be captured")
+		}
+	}
+	if p.last != nil {
+		t := newSnapshotToken(p.last)
+		s.Last = &t
+	}
+	return json.Marshal(s)
+}
+
+// RestoreSnapshot restores the parser state captured by Snapshot, resetting the parser to resume parsing from the
+// snapshot, with the specified token stream and function.
+// The tokens provide the tokens following those read by the parser when the snapshot was taken, and are ignored if the
+// parser had already reached the end of its input.
+// Pending errors are restored with their messages only.
+// As with Reset, options applied at creation remain in effect, the user context is restored to its initial value (see
+// WithContext), and all outstanding markers are invalidated.
+// Returns an error, leaving the parser unchanged, if the snapshot is invalid.
+//
+func (p *Parser) RestoreSnapshot(data []byte, tokens token.Nexter, next Fn) error {
+	s := &parserSnapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return fmt.Errorf("Parser.RestoreSnapshot: %v", err)
+	}
+	if s.Version != snapshotVersion {
+		return fmt.Errorf("Parser.RestoreSnapshot: unsupported snapshot version %d", s.Version)
+	}
+	if s.MatchLen < 0 || s.MatchLen > len(s.Tokens) {
+		return fmt.Errorf("Parser.RestoreSnapshot: match length %d out of range", s.MatchLen)
+	}
+	p.Reset(tokens, next)
+	for i, t := range s.Tokens {
+		e := p.cache.PushBack(t.token())
+		if i < s.MatchLen {
+			p.matchTail = e
+		}
+	}
+	p.matchLen = s.MatchLen
+	p.eof = s.EOF
+	p.eofOut = s.EOFOut
+	for _, emit := range s.Output {
+		if emit.EOF {
+			p.output.PushBack(nil)
+		} else {
+			p.output.PushBack(&emitError{err: errors.New(emit.Error)})
+		}
+	}
+	// Nexters stop once EOF is delivered, re-deliver it if needed
+	//
+	if p.eofOut && p.output.Len() == 0 {
+		p.output.PushBack(nil)
+	}
+	p.flushed = s.Flushed
+	if s.Last != nil {
+		p.last = s.Last.token()
+	}
+	p.discarded = s.Discarded
+	p.errCount = s.ErrCount
+	return nil
+}
+
+// ParseSnapshot initiates a parser that resumes parsing from a snapshot (see Parser.Snapshot), against the remaining
+// token stream, starting with the specified function.
+// The returned ASTNexter can be used to retrieve emitted ASTs, starting with any errors emitted but not yet delivered
+// when the snapshot was taken.
+// Returns an error if the snapshot is invalid.
+//
+func ParseSnapshot(data []byte, tokens token.Nexter, next Fn, opts ...Option) (ASTNexter, error) {
+	p := newParser(tokens, next, opts)
+	if err := p.RestoreSnapshot(data, tokens, next); err != nil {
+		return nil, err
+	}
+	return &astNexter{parser: p}, nil
+}
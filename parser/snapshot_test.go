@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// parseValues emits the values of the remaining unmatched tokens as a single AST.
+//
+func parseValues(p *Parser) Fn {
+	s := ""
+	for p.CanPeek(1) {
+		s += p.Next().Value()
+	}
+	p.Emit(s)
+	return nil
+}
+
+// TestSnapshot
+//
+func TestSnapshot(t *testing.T) {
+	var data []byte
+	var err error
+	fn := func(p *Parser) Fn {
+		p.Next()
+		p.EmitError("oops") // Pending
+		p.Next()
+		p.CanPeek(1) // Buffer 'c'
+		data, err = p.Snapshot()
+		return nil
+	}
+	nexter := Parse(positioned(TOne, TTwo, TThree, TOne), fn)
+	expectNexterError(t, nexter, "oops")
+	if err != nil {
+		t.Fatalf("Parser.Snapshot() returned error: %v", err)
+	}
+	// Resume with the tokens not yet read
+	//
+	nexter, err = ParseSnapshot(data, token.SliceNexter(token.New(TOne, "d", 1, 7)), parseValues)
+	if err != nil {
+		t.Fatalf("ParseSnapshot() returned error: %v", err)
+	}
+	expectNexterError(t, nexter, "oops")
+	expectNexterNext(t, nexter, "cd")
+	expectNexterEOF(t, nexter)
+	// Errors emitted before the snapshot count towards the error limit
+	//
+	again := func(p *Parser) Fn {
+		p.Next()
+		p.EmitError("again")
+		return nil
+	}
+	nexter, err = ParseSnapshot(data, token.SliceNexter(token.New(TOne, "d", 1, 7)), again, WithMaxErrors(2))
+	if err != nil {
+		t.Fatalf("ParseSnapshot() returned error: %v", err)
+	}
+	expectNexterError(t, nexter, "oops")
+	expectNexterError(t, nexter, "again")
+	expectNexterError(t, nexter, "too many errors")
+	expectNexterEOF(t, nexter)
+}
+
+// TestSnapshotPendingAST
+//
+func TestSnapshotPendingAST(t *testing.T) {
+	var err error
+	fn := func(p *Parser) Fn {
+		p.Next()
+		p.Emit("AST")
+		_, err = p.Snapshot()
+		return nil
+	}
+	expectNexterNext(t, Parse(mockLexer(TOne), fn), "AST")
+	if err == nil || err.Error() != "Parser.Snapshot: emitted ASTs pending delivery cannot be captured" {
+		t.Errorf("Parser.Snapshot() expecting pending AST error, received '%v'", err)
+	}
+}
+
+// TestSnapshotInvalid
+//
+func TestSnapshotInvalid(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string
+	}{
+		{`{`, "Parser.RestoreSnapshot: unexpected end of JSON input"},
+		{`{"Version":2}`, "Parser.RestoreSnapshot: unsupported snapshot version 2"},
+		{`{"Version":1,"MatchLen":1}`, "Parser.RestoreSnapshot: match length 1 out of range"},
+	}
+	for _, test := range tests {
+		_, err := ParseSnapshot([]byte(test.data), mockLexer(), parseValues)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("ParseSnapshot(%s) expecting error '%s', received '%v'", test.data, test.err, err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package parser
+
+import "github.com/tekwizely/go-parsing/lexer/token"
+
+// SpanSetter can be implemented by ASTs that wish to receive their span directly from EmitSpanned,
+// rather than being wrapped in a Spanned.
+//
+type SpanSetter interface {
+
+	// SetSpan stores the span of source input covered by the AST.
+	//
+	SetSpan(span token.Span)
+}
+
+// Spanner can be implemented by ASTs that carry their span, for checking via WithPositionChecks.
+//
+type Spanner interface {
+
+	// Span returns the span of source input covered by the AST.
+	//
+	Span() token.Span
+}
+
+// Spanned wraps an AST emitted via EmitSpanned, along with the span of source input it covers.
+// Only ASTs that do not implement SpanSetter are wrapped.
+//
+type Spanned struct {
+	AST  interface{}
+	Span token.Span
+}
+
+// EmitSpanned emits an AST, attaching the span covering all of the previously-matched tokens, from the start of the
+// first token to the end of the last token.
+// If the AST implements SpanSetter, SetSpan() is called and the AST is emitted as-is.
+// Otherwise, the AST is emitted wrapped in a *Spanned.
+// If no tokens are matched, the span is empty, positioned at the start of the next token if already peeked, otherwise
+// at the end of the last token discarded (if any); No further tokens are read from the input.
+// All previously-matched tokens are discarded.
+// All outstanding markers are invalidated after this call.
+// Panics if ast is nil.
+// Panics if EOF already emitted (see WithLenient).
+//
+func (p *Parser) EmitSpanned(ast interface{}) {
+	// Nothing can be emitted after EOF emitted
+	//
+	if p.afterEOF("Parser.EmitSpanned: No further emits allowed after EOF is emitted") {
+		return
+	}
+	if ast == nil {
+		p.raise("Parser.EmitSpanned: Cannot emit nil")
+	}
+	span := p.matchSpan()
+	if s, ok := ast.(SpanSetter); ok {
+		s.SetSpan(span)
+	} else {
+		ast = &Spanned{AST: ast, Span: span}
+	}
+	p.emit(ast)
+}
+
+// matchSpan computes the span of the matched tokens.
+// If no tokens are matched, the span is computed without reading from the input - see EmitSpanned.
+//
+func (p *Parser) matchSpan() token.Span {
+	if p.matchLen > 0 {
+		return token.SpanOf(p.cache.Front().Value.(token.Token), p.matchTail.Value.(token.Token))
+	}
+	pos := token.Position{Line: -1, Column: -1}
+	switch {
+	case p.cache.Len() > 0:
+		pos = token.Start(p.peekHead().Value.(token.Token))
+	case p.last != nil:
+		pos = token.End(p.last)
+	}
+	return token.Span{Start: pos, End: pos}
+}
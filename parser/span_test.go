@@ -0,0 +1,199 @@
+package parser
+
+import (
+	"io"
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// posToken is a token.Token with a value and position
+//
+type posToken struct {
+	typ    token.Type
+	value  string
+	line   int
+	column int
+}
+
+func (t *posToken) Type() token.Type {
+	return t.typ
+}
+func (t *posToken) Value() string {
+	return t.value
+}
+func (t *posToken) Line() int {
+	return t.line
+}
+func (t *posToken) Column() int {
+	return t.column
+}
+
+// endToken is a posToken that records its end position, as tokens emitted by the lexer do (see token.Ender)
+//
+type endToken struct {
+	posToken
+	end token.Position
+}
+
+func (t *endToken) End() token.Position {
+	return t.end
+}
+
+// posNexter creates a token.Nexter from a list of posTokens
+//
+type posNexter struct {
+	tokens []*posToken
+}
+
+func (n *posNexter) Next() (token.Token, error) {
+	if len(n.tokens) == 0 {
+		return nil, io.EOF
+	}
+	t := n.tokens[0]
+	n.tokens = n.tokens[1:]
+	return t, nil
+}
+
+// spanNode implements SpanSetter
+//
+type spanNode struct {
+	span token.Span
+}
+
+func (n *spanNode) SetSpan(span token.Span) {
+	n.span = span
+}
+
+// mockPosLexer returns tokens "one two\nthree"
+//
+func mockPosLexer() token.Nexter {
+	return &posNexter{tokens: []*posToken{
+		{typ: TOne, value: "one", line: 1, column: 1},
+		{typ: TTwo, value: "two\n", line: 1, column: 5},
+		{typ: TThree, value: "three", line: 2, column: 1},
+	}}
+}
+
+// expectSpan
+//
+func expectSpan(t *testing.T, span token.Span, match string) {
+	if s := span.String(); s != match {
+		t.Errorf("Expecting span '%s', received '%s'", match, s)
+	}
+}
+
+// TestEmitSpannedWrapped
+//
+func TestEmitSpannedWrapped(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.Next()
+		p.EmitSpanned("one")
+		p.Next()
+		p.Next()
+		p.EmitSpanned("two three")
+		return nil
+	}
+	nexter := Parse(mockPosLexer(), fn)
+	for _, match := range []string{"1:1-1:4", "1:5-2:6"} {
+		ast, err := nexter.Next()
+		if err != nil {
+			t.Errorf("Nexter.Next() expecting no error, received '%s'", err.Error())
+			continue
+		}
+		spanned, ok := ast.(*Spanned)
+		if !ok {
+			t.Errorf("Nexter.Next() expecting *Spanned, received %T", ast)
+			continue
+		}
+		expectSpan(t, spanned.Span, match)
+	}
+	expectNexterEOF(t, nexter)
+}
+
+// TestEmitSpannedSetter
+//
+func TestEmitSpannedSetter(t *testing.T) {
+	node := &spanNode{}
+	fn := func(p *Parser) Fn {
+		p.Next()
+		p.Next()
+		p.EmitSpanned(node)
+		return nil
+	}
+	nexter := Parse(mockPosLexer(), fn)
+	if ast, _ := nexter.Next(); ast != node {
+		t.Errorf("Nexter.Next() expecting SpanSetter to be emitted as-is, received %T", ast)
+	}
+	expectSpan(t, node.span, "1:1-2:0")
+}
+
+// TestEmitSpannedEnder confirms spans use the end position recorded by the token, i.e. when the lexer counts
+// display-width columns (see lexer.WithColumns).
+//
+func TestEmitSpannedEnder(t *testing.T) {
+	node := &spanNode{}
+	fn := func(p *Parser) Fn {
+		p.Next()
+		p.EmitSpanned(node)
+		return nil
+	}
+	tok := &endToken{posToken: posToken{typ: TOne, value: "e\u0301x", line: 1, column: 1}, end: token.Position{Line: 1, Column: 3}}
+	nexter := Parse(token.SliceNexter(tok), fn)
+	_, _ = nexter.Next()
+	expectSpan(t, node.span, "1:1-1:3")
+}
+
+// TestEmitSpannedNoMatch
+//
+func TestEmitSpannedNoMatch(t *testing.T) {
+	node := &spanNode{}
+	fn := func(p *Parser) Fn {
+		p.Next()
+		p.Clear()
+		p.EmitSpanned(node)
+		return nil
+	}
+	nexter := Parse(mockPosLexer(), fn)
+	_, _ = nexter.Next()
+	expectSpan(t, node.span, "1:4-1:4")
+	// Positioned at the next token, if already peeked
+	//
+	fn = func(p *Parser) Fn {
+		p.Next()
+		p.Clear()
+		p.Peek(1)
+		p.EmitSpanned(node)
+		return nil
+	}
+	nexter = Parse(mockPosLexer(), fn)
+	_, _ = nexter.Next()
+	expectSpan(t, node.span, "1:5-1:5")
+}
+
+// TestEmitSpannedNil
+//
+func TestEmitSpannedNil(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		assertPanic(t, func() {
+			p.EmitSpanned(nil)
+		}, "Parser.EmitSpanned: Cannot emit nil")
+		return nil
+	}
+	nexter := Parse(mockPosLexer(), fn)
+	expectNexterEOF(t, nexter)
+}
+
+// TestEmitSpannedAfterEOF
+//
+func TestEmitSpannedAfterEOF(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.EmitEOF()
+		assertPanic(t, func() {
+			p.EmitSpanned("AST")
+		}, "Parser.EmitSpanned: No further emits allowed after EOF is emitted")
+		return nil
+	}
+	nexter := Parse(mockPosLexer(), fn)
+	expectNexterEOF(t, nexter)
+}
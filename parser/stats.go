@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Stats captures counters from a parser, for performance tuning and production monitoring.
+// Counters are only recorded when enabled via WithStats, keeping the default path free of bookkeeping.
+// Stats are updated without synchronization; Read them from within parser functions, or after parsing completes.
+// See Parser.Stats() and WithStats.
+//
+type Stats struct {
+	TokensRead    int64                // Tokens read from the input
+	TokensByType  map[token.Type]int64 // Tokens read, per token type
+	ASTsEmitted   int64                // ASTs emitted, excluding EOF
+	ErrorsEmitted int64                // Errors emitted via EmitError()
+	PeakBuffer    int                  // Peak number of tokens held in the token buffer (matched + peeked)
+	MarkerApplies int64                // Markers applied via Marker.Apply()
+	FnCalls       int64                // Parser function invocations
+}
+
+// Snapshot returns the counters as a flat map, with keys suitable for metrics systems (i.e. expvar, Prometheus).
+// Per-type token counts are keyed as "tokens_read_type_N".
+//
+func (s *Stats) Snapshot() map[string]int64 {
+	m := map[string]int64{
+		"tokens_read":    s.TokensRead,
+		"asts_emitted":   s.ASTsEmitted,
+		"errors_emitted": s.ErrorsEmitted,
+		"peak_buffer":    int64(s.PeakBuffer),
+		"marker_applies": s.MarkerApplies,
+		"fn_calls":       s.FnCalls,
+	}
+	for typ, n := range s.TokensByType {
+		m[fmt.Sprintf("tokens_read_type_%d", typ)] = n
+	}
+	return m
+}
+
+// String returns the Snapshot() as JSON, allowing Stats to be published as an expvar.Var.
+//
+func (s *Stats) String() string {
+	b, _ := json.Marshal(s.Snapshot()) // Marshalling a map[string]int64 cannot fail
+	return string(b)
+}
+
+// Stats returns a copy of the parser's current counters.
+// Counters are only recorded when enabled via WithStats, otherwise zero counters are returned.
+// See WithStats for retrieving counters after parsing completes.
+//
+func (p *Parser) Stats() Stats {
+	if p.stats == nil {
+		return Stats{TokensByType: make(map[token.Type]int64)}
+	}
+	s := *p.stats
+	s.TokensByType = make(map[token.Type]int64, len(p.stats.TokensByType))
+	for typ, n := range p.stats.TokensByType {
+		s.TokensByType[typ] = n
+	}
+	return s
+}
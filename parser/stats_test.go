@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// parseStats emits each token value, applying a marker before each match, and emits an error for TTwo
+//
+func parseStats(p *Parser) Fn {
+	m := p.Marker()
+	p.Peek(1)
+	m.Apply()
+	if tok := p.Next(); tok.Type() == TTwo {
+		p.EmitError("two")
+	} else {
+		p.Emit(tok.Value())
+	}
+	return parseStats
+}
+
+// TestWithStats
+//
+func TestWithStats(t *testing.T) {
+	stats := &Stats{}
+	nexter := Parse(mockPosLexer(), parseStats, WithStats(stats))
+	expectNexterNext(t, nexter, "one")
+	expectNexterError(t, nexter, "two")
+	expectNexterNext(t, nexter, "three")
+	expectNexterEOF(t, nexter)
+	expected := map[string]int64{
+		"tokens_read":        3,
+		"tokens_read_type_1": 1,
+		"tokens_read_type_2": 1,
+		"tokens_read_type_3": 1,
+		"asts_emitted":       2,
+		"errors_emitted":     1,
+		"peak_buffer":        1,
+		"marker_applies":     3,
+		"fn_calls":           3,
+	}
+	snapshot := stats.Snapshot()
+	if len(snapshot) != len(expected) {
+		t.Errorf("Stats.Snapshot() expecting %v, received %v", expected, snapshot)
+	}
+	for k, v := range expected {
+		if snapshot[k] != v {
+			t.Errorf("Stats.Snapshot()[%s] expecting %d, received %d", k, v, snapshot[k])
+		}
+	}
+	var decoded map[string]int64
+	if err := json.Unmarshal([]byte(stats.String()), &decoded); err != nil || decoded["fn_calls"] != 3 {
+		t.Errorf("Stats.String() expecting JSON snapshot, received '%s'", stats.String())
+	}
+}
+
+// TestStats
+//
+func TestStats(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.Peek(2)
+		p.Next()
+		p.Emit("one")
+		stats := p.Stats()
+		if stats.TokensRead != 2 || stats.PeakBuffer != 2 || stats.ASTsEmitted != 1 {
+			t.Errorf("Parser.Stats() expecting (2, 2, 1), received (%d, %d, %d)",
+				stats.TokensRead, stats.PeakBuffer, stats.ASTsEmitted)
+		}
+		// Returned stats is a copy
+		//
+		stats.TokensByType[TOne] = 99
+		if p.Stats().TokensByType[TOne] != 1 {
+			t.Error("Parser.Stats() expecting copy of counters")
+		}
+		return nil
+	}
+	nexter := Parse(mockPosLexer(), fn, WithStats(&Stats{}))
+	expectNexterNext(t, nexter, "one")
+	expectNexterEOF(t, nexter)
+}
+
+// TestStatsDisabled
+//
+func TestStatsDisabled(t *testing.T) {
+	fn := func(p *Parser) Fn {
+		p.Next()
+		p.Emit("one")
+		if p.stats != nil {
+			t.Error("Parser.stats expecting nil without WithStats")
+		}
+		stats := p.Stats()
+		if stats.TokensRead != 0 || stats.ASTsEmitted != 0 || len(stats.TokensByType) != 0 {
+			t.Errorf("Parser.Stats() expecting zero counters, received '%s'", stats.String())
+		}
+		return nil
+	}
+	nexter := Parse(mockPosLexer(), fn)
+	expectNexterNext(t, nexter, "one")
+	expectNexterEOF(t, nexter)
+}
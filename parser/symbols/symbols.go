@@ -0,0 +1,175 @@
+/*
+Package symbols provides a scoped symbol table, suitable for driving from your Parser.Fn functions.
+
+Scopes are entered and exited via Push() and Pop(), names are added to the current scope via Define(),
+and names are looked up, innermost scope first, via Resolve().
+
+Duplicate and undefined names are reported as errors that include the positions of the offending tokens.
+
+*/
+package symbols
+
+import (
+	"fmt"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// Shadowing controls whether a name defined in an outer scope can be redefined in an inner scope.
+//
+type Shadowing int
+
+const (
+	// AllowShadowing permits inner scopes to redefine names from outer scopes
+	//
+	AllowShadowing Shadowing = iota
+	// DenyShadowing reports a DuplicateError when an inner scope redefines a name from an outer scope
+	//
+	DenyShadowing
+)
+
+// Symbol captures a defined name, along with the token that defined it and an optional user value.
+//
+type Symbol struct {
+	Name  string
+	Token token.Token // Defining token. Can be nil
+	Value interface{} // User value. Can be nil
+	Depth int         // Depth of the defining scope. The global scope has depth 0
+}
+
+// Table is a stack of scopes mapping names to symbols.
+// A new Table starts with a single (global) scope.
+//
+type Table struct {
+	scopes    []map[string]*Symbol
+	shadowing Shadowing
+}
+
+// New creates a new symbol table using the specified shadowing rule.
+//
+func New(shadowing Shadowing) *Table {
+	return &Table{scopes: []map[string]*Symbol{{}}, shadowing: shadowing}
+}
+
+// Push enters a new (inner) scope.
+//
+func (t *Table) Push() {
+	t.scopes = append(t.scopes, map[string]*Symbol{})
+}
+
+// Pop exits the current scope, discarding all symbols defined within it.
+// Panics if called on the global scope.
+//
+func (t *Table) Pop() {
+	if len(t.scopes) == 1 {
+		panic("Table.Pop: cannot pop global scope")
+	}
+	t.scopes[len(t.scopes)-1] = nil
+	t.scopes = t.scopes[:len(t.scopes)-1]
+}
+
+// Depth returns the depth of the current scope.
+// The global scope has depth 0.
+//
+func (t *Table) Depth() int {
+	return len(t.scopes) - 1
+}
+
+// Define adds a symbol to the current scope.
+// tok is the defining token and is used for error reporting. Can be nil.
+// Returns a *DuplicateError if the name is already defined in the current scope,
+// or if the name is defined in an outer scope and the table was created with DenyShadowing.
+// The table is unchanged when an error is returned.
+//
+func (t *Table) Define(name string, tok token.Token, value interface{}) (*Symbol, error) {
+	if prev, ok := t.Lookup(name); ok {
+		if prev.Depth == t.Depth() {
+			return nil, &DuplicateError{Name: name, Token: tok, Previous: prev}
+		}
+		if t.shadowing == DenyShadowing {
+			return nil, &DuplicateError{Name: name, Token: tok, Previous: prev, Shadow: true}
+		}
+	}
+	sym := &Symbol{Name: name, Token: tok, Value: value, Depth: t.Depth()}
+	t.scopes[len(t.scopes)-1][name] = sym
+	return sym, nil
+}
+
+// Lookup searches for a name, innermost scope first, returning the symbol and true if found.
+//
+func (t *Table) Lookup(name string) (*Symbol, bool) {
+	for i := len(t.scopes) - 1; i >= 0; i-- {
+		if sym, ok := t.scopes[i][name]; ok {
+			return sym, true
+		}
+	}
+	return nil, false
+}
+
+// Resolve searches for a name, innermost scope first.
+// tok is the referencing token and is used for error reporting. Can be nil.
+// Returns an *UndefinedError if the name is not defined in any scope.
+//
+func (t *Table) Resolve(name string, tok token.Token) (*Symbol, error) {
+	if sym, ok := t.Lookup(name); ok {
+		return sym, nil
+	}
+	return nil, &UndefinedError{Name: name, Token: tok}
+}
+
+// DuplicateError reports an attempt to define a name that is already defined.
+//
+type DuplicateError struct {
+	Name     string
+	Token    token.Token // Token of the attempted definition. Can be nil
+	Previous *Symbol     // The existing definition
+	Shadow   bool        // True if the existing definition is in an outer scope
+}
+
+// Error implements error.Error().
+// Example: "3:5: 'x' already defined at 1:1"
+//
+func (e *DuplicateError) Error() string {
+	what := "already defined"
+	if e.Shadow {
+		what = "shadows definition"
+	}
+	if at := position(e.Previous.Token); at != "" {
+		return fmt.Sprintf("%s'%s' %s at %s", prefix(e.Token), e.Name, what, at)
+	}
+	return fmt.Sprintf("%s'%s' %s", prefix(e.Token), e.Name, what)
+}
+
+// UndefinedError reports a reference to a name that is not defined.
+//
+type UndefinedError struct {
+	Name  string
+	Token token.Token // Token of the reference. Can be nil
+}
+
+// Error implements error.Error().
+// Example: "3:5: 'x' not defined"
+//
+func (e *UndefinedError) Error() string {
+	return fmt.Sprintf("%s'%s' not defined", prefix(e.Token), e.Name)
+}
+
+// position formats the token position as "line:column".
+// Returns "" if the token is nil or has no position.
+//
+func position(tok token.Token) string {
+	if tok == nil || tok.Line() < 0 || tok.Column() < 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", tok.Line(), tok.Column())
+}
+
+// prefix formats the token position as an error message prefix, "line:column: ".
+// Returns "" if the token is nil or has no position.
+//
+func prefix(tok token.Token) string {
+	if at := position(tok); at != "" {
+		return at + ": "
+	}
+	return ""
+}
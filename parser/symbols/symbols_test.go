@@ -0,0 +1,189 @@
+package symbols
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// mockToken creates a token.Token with a position
+//
+type mockToken struct {
+	line   int
+	column int
+}
+
+func (t *mockToken) Type() token.Type {
+	return 0
+}
+func (t *mockToken) Value() string {
+	return ""
+}
+func (t *mockToken) Line() int {
+	return t.line
+}
+func (t *mockToken) Column() int {
+	return t.column
+}
+
+// at
+//
+func at(line int, column int) token.Token {
+	return &mockToken{line: line, column: column}
+}
+
+// assertPanic
+//
+func assertPanic(t *testing.T, f func(), msg string) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("assertPanic: did not generate panic()")
+		} else if r != msg {
+			t.Errorf("assertPanic: recover() recieved message '%s' instead of '%s'", r, msg)
+		}
+	}()
+	f()
+}
+
+// expectDefine
+//
+func expectDefine(t *testing.T, table *Table, name string, tok token.Token, value interface{}) {
+	sym, err := table.Define(name, tok, value)
+	if err != nil {
+		t.Errorf("Table.Define('%s') expecting no error, received '%s'", name, err.Error())
+		return
+	}
+	if sym.Name != name || sym.Token != tok || sym.Value != value || sym.Depth != table.Depth() {
+		t.Errorf("Table.Define('%s') returned unexpected symbol %v", name, sym)
+	}
+}
+
+// expectDefineError
+//
+func expectDefineError(t *testing.T, table *Table, name string, tok token.Token, errMsg string) {
+	sym, err := table.Define(name, tok, nil)
+	if err == nil {
+		t.Errorf("Table.Define('%s') expecting error '%s', received symbol %v", name, errMsg, sym)
+		return
+	}
+	if _, ok := err.(*DuplicateError); !ok {
+		t.Errorf("Table.Define('%s') expecting *DuplicateError, received %T", name, err)
+	}
+	if err.Error() != errMsg {
+		t.Errorf("Table.Define('%s') expecting error '%s', received '%s'", name, errMsg, err.Error())
+	}
+}
+
+// expectResolve
+//
+func expectResolve(t *testing.T, table *Table, name string, value interface{}) {
+	sym, err := table.Resolve(name, nil)
+	if err != nil {
+		t.Errorf("Table.Resolve('%s') expecting no error, received '%s'", name, err.Error())
+		return
+	}
+	if sym.Value != value {
+		t.Errorf("Table.Resolve('%s') expecting value '%v', received '%v'", name, value, sym.Value)
+	}
+}
+
+// expectResolveError
+//
+func expectResolveError(t *testing.T, table *Table, name string, tok token.Token, errMsg string) {
+	sym, err := table.Resolve(name, tok)
+	if err == nil {
+		t.Errorf("Table.Resolve('%s') expecting error '%s', received symbol %v", name, errMsg, sym)
+		return
+	}
+	if _, ok := err.(*UndefinedError); !ok {
+		t.Errorf("Table.Resolve('%s') expecting *UndefinedError, received %T", name, err)
+	}
+	if err.Error() != errMsg {
+		t.Errorf("Table.Resolve('%s') expecting error '%s', received '%s'", name, errMsg, err.Error())
+	}
+}
+
+// TestDefineResolve
+//
+func TestDefineResolve(t *testing.T) {
+	table := New(AllowShadowing)
+	expectDefine(t, table, "x", at(1, 1), 1)
+	expectResolve(t, table, "x", 1)
+}
+
+// TestResolveUndefined
+//
+func TestResolveUndefined(t *testing.T) {
+	table := New(AllowShadowing)
+	expectResolveError(t, table, "x", at(2, 3), "2:3: 'x' not defined")
+	expectResolveError(t, table, "x", nil, "'x' not defined")
+}
+
+// TestDefineDuplicate
+//
+func TestDefineDuplicate(t *testing.T) {
+	table := New(AllowShadowing)
+	expectDefine(t, table, "x", at(1, 1), 1)
+	expectDefineError(t, table, "x", at(3, 5), "3:5: 'x' already defined at 1:1")
+	// Original definition retained
+	//
+	expectResolve(t, table, "x", 1)
+}
+
+// TestDefineDuplicateNoPosition
+//
+func TestDefineDuplicateNoPosition(t *testing.T) {
+	table := New(AllowShadowing)
+	expectDefine(t, table, "x", nil, 1)
+	expectDefineError(t, table, "x", at(-1, -1), "'x' already defined")
+}
+
+// TestScopes
+//
+func TestScopes(t *testing.T) {
+	table := New(AllowShadowing)
+	expectDefine(t, table, "x", nil, 1)
+	table.Push()
+	if table.Depth() != 1 {
+		t.Errorf("Table.Depth() expecting 1, received %d", table.Depth())
+	}
+	expectDefine(t, table, "y", nil, 2)
+	expectResolve(t, table, "x", 1)
+	expectResolve(t, table, "y", 2)
+	table.Pop()
+	if table.Depth() != 0 {
+		t.Errorf("Table.Depth() expecting 0, received %d", table.Depth())
+	}
+	expectResolveError(t, table, "y", nil, "'y' not defined")
+}
+
+// TestShadowingAllowed
+//
+func TestShadowingAllowed(t *testing.T) {
+	table := New(AllowShadowing)
+	expectDefine(t, table, "x", nil, 1)
+	table.Push()
+	expectDefine(t, table, "x", nil, 2)
+	expectResolve(t, table, "x", 2)
+	table.Pop()
+	expectResolve(t, table, "x", 1)
+}
+
+// TestShadowingDenied
+//
+func TestShadowingDenied(t *testing.T) {
+	table := New(DenyShadowing)
+	expectDefine(t, table, "x", at(1, 1), 1)
+	table.Push()
+	expectDefineError(t, table, "x", at(2, 5), "2:5: 'x' shadows definition at 1:1")
+	expectResolve(t, table, "x", 1)
+}
+
+// TestPopGlobal
+//
+func TestPopGlobal(t *testing.T) {
+	table := New(AllowShadowing)
+	assertPanic(t, func() {
+		table.Pop()
+	}, "Table.Pop: cannot pop global scope")
+}
@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// TraceKind identifies the parser activity that generated a TraceEvent.
+//
+type TraceKind int
+
+// Trace event kinds.
+//
+const (
+	TraceEnter  TraceKind = iota // Parser function entered
+	TraceExit                    // Parser function exited
+	TraceNext                    // Token matched via Next()
+	TracePeek                    // Token reviewed via Peek()
+	TraceEmit                    // AST emitted (nil for EOF)
+	TraceError                   // Error emitted via EmitError()
+	TraceMarker                  // Marker created
+	TraceApply                   // Marker applied
+	TraceClear                   // Matched tokens discarded via Clear()
+)
+
+var traceKindNames = [...]string{"Enter", "Exit", "Next", "Peek", "Emit", "Error", "Marker", "Apply", "Clear"}
+
+// String returns the name of the trace kind.
+//
+func (k TraceKind) String() string {
+	if k < 0 || int(k) >= len(traceKindNames) {
+		return fmt.Sprintf("TraceKind(%d)", int(k))
+	}
+	return traceKindNames[k]
+}
+
+// TraceEvent describes a single parser activity, delivered to the function registered via WithTrace.
+// Pos is the starting position of the token for Next and Peek events.
+// For all other events, Pos is the starting position of the first matched token, or of the next token if no tokens
+// are matched. Pos is invalid if neither is available.
+//
+type TraceEvent struct {
+	Kind  TraceKind      // The activity that generated the event
+	Fn    string         // Name of the current parser function
+	Pos   token.Position // See above
+	Token token.Token    // The token for Next and Peek events
+	AST   interface{}    // The AST for Emit events
+	Value string         // The error text for Error events, the name of the returned function for Exit events
+}
+
+// String returns a single-line description of the event, suitable for logging.
+//
+func (e TraceEvent) String() string {
+	switch e.Kind {
+	case TraceNext, TracePeek:
+		return fmt.Sprintf("%s %s %d %q (%s)", e.Pos, e.Kind, e.Token.Type(), e.Token.Value(), e.Fn)
+	case TraceEmit:
+		return fmt.Sprintf("%s %s %v (%s)", e.Pos, e.Kind, e.AST, e.Fn)
+	case TraceError:
+		return fmt.Sprintf("%s %s %q (%s)", e.Pos, e.Kind, e.Value, e.Fn)
+	case TraceExit:
+		return fmt.Sprintf("%s %s (%s) -> %s", e.Pos, e.Kind, e.Fn, e.Value)
+	default:
+		return fmt.Sprintf("%s %s (%s)", e.Pos, e.Kind, e.Fn)
+	}
+}
+
+// traceEvent delivers an event to the trace function, if any.
+//
+func (p *Parser) traceEvent(kind TraceKind, tok token.Token, ast interface{}, value string) {
+	if p.trace == nil {
+		return
+	}
+	var pos token.Position
+	switch {
+	case tok != nil:
+		pos = token.Start(tok)
+	// The cache front is the first matched token, or the next token if none are matched
+	//
+	case p.cache.Len() > 0:
+		pos = token.Start(p.cache.Front().Value.(token.Token))
+	default:
+		pos = token.Position{Line: -1, Column: -1}
+	}
+	p.trace(TraceEvent{
+		Kind:  kind,
+		Fn:    p.fnName(p.nextFn),
+		Pos:   pos,
+		Token: tok,
+		AST:   ast,
+		Value: value,
+	})
+}
+
+// fnName returns the name of the parser function, or "" if fn is nil.
+//
+func fnName(fn Fn) string {
+	if fn == nil {
+		return ""
+	}
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return ""
+}
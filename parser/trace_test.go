@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// parseTraceTwo matches "two", clearing it, then emits an error
+//
+func parseTraceTwo(p *Parser) Fn {
+	p.Next()
+	p.Clear()
+	p.EmitError("error")
+	return nil
+}
+
+// parseTraceOne matches "one", using a marker to try (and fail) to match TThree
+//
+func parseTraceOne(p *Parser) Fn {
+	p.Next()
+	m := p.Marker()
+	if p.PeekType(1) != TThree {
+		m.Apply()
+	}
+	p.Emit("ONE")
+	return parseTraceTwo
+}
+
+// TestWithTrace
+//
+func TestWithTrace(t *testing.T) {
+	var events []string
+	trace := func(e TraceEvent) {
+		s := e.String()
+		s = strings.Replace(s, "github.com/tekwizely/go-parsing/parser.", "", -1)
+		events = append(events, s)
+	}
+	nexter := Parse(mockPosLexer(), parseTraceOne, WithTrace(trace))
+	expectNexterNext(t, nexter, "ONE")
+	expectNexterError(t, nexter, "error")
+	expectNexterEOF(t, nexter)
+	expected := []string{
+		"1:1 Enter (parseTraceOne)",
+		"1:1 Next 1 \"one\" (parseTraceOne)",
+		"1:1 Marker (parseTraceOne)",
+		"1:5 Peek 2 \"two\\n\" (parseTraceOne)",
+		"1:1 Apply (parseTraceOne)",
+		"1:1 Emit ONE (parseTraceOne)",
+		"1:5 Exit (parseTraceOne) -> parseTraceTwo",
+		"1:5 Enter (parseTraceTwo)",
+		"1:5 Next 2 \"two\\n\" (parseTraceTwo)",
+		"1:5 Clear (parseTraceTwo)",
+		"-1:-1 Error \"error\" (parseTraceTwo)",
+		"-1:-1 Exit (parseTraceTwo) -> ",
+		"-1:-1 Emit <nil> ()",
+	}
+	if strings.Join(events, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expecting trace:\n%s\nreceived:\n%s", strings.Join(expected, "\n"), strings.Join(events, "\n"))
+	}
+}
+
+// TestTraceKindString
+//
+func TestTraceKindString(t *testing.T) {
+	if s := TraceError.String(); s != "Error" {
+		t.Errorf("TraceKind.String() expecting 'Error', received '%s'", s)
+	}
+	if s := TraceKind(99).String(); s != "TraceKind(99)" {
+		t.Errorf("TraceKind.String() expecting 'TraceKind(99)', received '%s'", s)
+	}
+}
@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// positionCheckFailed prefixes the panic messages of failed position checks (see WithPositionChecks).
+//
+const positionCheckFailed = "Parser: position check failed: "
+
+// checkToken confirms that tok neither precedes nor overlaps the previous token read from the input, panicking if it
+// does (see WithPositionChecks).
+// The end of the previous token is taken from token.End, which honors the lexer's column and line modes for tokens
+// emitted by the lexer (see token.Ender).
+// Tokens with invalid positions are not checked.
+//
+func (p *Parser) checkToken(tok token.Token) {
+	start := token.Start(tok)
+	if !start.IsValid() {
+		return
+	}
+	if prev := p.prevRead; prev != nil {
+		if prevStart := token.Start(prev); before(start, prevStart) {
+			panic(fmt.Sprintf("%stoken %q at %s precedes previous token %q at %s",
+				positionCheckFailed, tok.Value(), start, prev.Value(), prevStart))
+		}
+		if prevEnd := token.End(prev); before(start, prevEnd) {
+			panic(fmt.Sprintf("%stoken %q at %s overlaps previous token %q ending at %s",
+				positionCheckFailed, tok.Value(), start, prev.Value(), prevEnd))
+		}
+	}
+	p.prevRead = tok
+}
+
+// checkAST confirms that the span of the AST (see Spanner and Spanned), if any, lies within the span of the matched
+// tokens it is emitted from, panicking if it does not (see WithPositionChecks).
+// ASTs emitted without matched tokens (with valid positions) are not checked.
+//
+func (p *Parser) checkAST(ast interface{}) {
+	var span token.Span
+	switch a := ast.(type) {
+	case *Spanned:
+		span = a.Span
+	case Spanner:
+		span = a.Span()
+	default:
+		return
+	}
+	match, ok := p.validMatchSpan()
+	if !ok || !span.Start.IsValid() {
+		return
+	}
+	if before(span.Start, match.Start) || before(match.End, span.End) || before(span.End, span.Start) {
+		panic(fmt.Sprintf("%sAST %T spanning %s lies outside its tokens spanning %s",
+			positionCheckFailed, ast, span, match))
+	}
+}
+
+// validMatchSpan computes the span of the matched tokens, ignoring tokens with invalid positions.
+// Returns false if there are no such tokens.
+//
+func (p *Parser) validMatchSpan() (token.Span, bool) {
+	var span token.Span
+	found := false
+	e := p.cache.Front()
+	for i := 0; i < p.matchLen; i++ {
+		tok := e.Value.(token.Token)
+		e = e.Next()
+		if start := token.Start(tok); start.IsValid() {
+			if !found {
+				span.Start = start
+				found = true
+			}
+			span.End = token.End(tok)
+		}
+	}
+	return span, found
+}
@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/tekwizely/go-parsing/lexer"
+	"github.com/tekwizely/go-parsing/lexer/token"
+)
+
+// spanAST reports a fixed span
+//
+type spanAST struct {
+	span token.Span
+}
+
+// Span implements Spanner.Span().
+//
+func (a *spanAST) Span() token.Span {
+	return a.span
+}
+
+// parseAllTokens matches all tokens, then emits the AST returned by fn
+//
+func parseAllTokens(fn func() interface{}) Fn {
+	return func(p *Parser) Fn {
+		for p.CanPeek(1) {
+			p.Next()
+		}
+		p.Emit(fn())
+		return nil
+	}
+}
+
+// TestPositionChecks
+//
+func TestPositionChecks(t *testing.T) {
+	tokens := token.SliceNexter(
+		token.New(TOne, "ab", 1, 1),
+		token.New(TTwo, "\n", 1, 3),
+		token.New(TOne, "c", 2, 1),
+		token.New(TTwo, "", 2, 2),
+		token.New(TOne, "d", 2, 2),
+		token.New(TThree, "?", -1, -1),
+	)
+	ast := &spanAST{span: token.Span{Start: token.Position{Line: 1, Column: 2}, End: token.Position{Line: 2, Column: 3}}}
+	nexter := Parse(tokens, parseAllTokens(func() interface{} {
+		return ast
+	}), WithPositionChecks())
+	if received, err := nexter.Next(); received != ast || err != nil {
+		t.Errorf("Nexter.Next() expecting (%v, nil), received (%v, %v)", ast, received, err)
+	}
+	expectNexterEOF(t, nexter)
+}
+
+// TestPositionChecksSpanned
+//
+func TestPositionChecksSpanned(t *testing.T) {
+	nexter := Parse(positioned(TOne, TTwo), func(p *Parser) Fn {
+		p.Next()
+		p.Next()
+		p.EmitSpanned("ab")
+		return nil
+	}, WithPositionChecks())
+	if _, err := nexter.Next(); err != nil {
+		t.Errorf("Nexter.Next() received unexpected error '%s'", err)
+	}
+}
+
+// TestPositionChecksPrecedes
+//
+func TestPositionChecksPrecedes(t *testing.T) {
+	tokens := token.SliceNexter(token.New(TOne, "a", 1, 5), token.New(TTwo, "b", 1, 1))
+	nexter := Parse(tokens, parseAllTokens(func() interface{} {
+		return "ab"
+	}), WithPositionChecks())
+	assertPanic(t, func() {
+		_, _ = nexter.Next()
+	}, `Parser: position check failed: token "b" at 1:1 precedes previous token "a" at 1:5`)
+}
+
+// TestPositionChecksOverlaps
+//
+func TestPositionChecksOverlaps(t *testing.T) {
+	tokens := token.SliceNexter(token.New(TOne, "abc", 1, 1), token.New(TTwo, "x", 1, 3))
+	nexter := Parse(tokens, parseAllTokens(func() interface{} {
+		return "abcx"
+	}), WithPositionChecks())
+	assertPanic(t, func() {
+		_, _ = nexter.Next()
+	}, `Parser: position check failed: token "x" at 1:3 overlaps previous token "abc" ending at 1:4`)
+}
+
+// TestPositionChecksModes confirms tokens are checked against the end positions computed by the lexer, when the
+// lexer uses non-default column and line modes.
+//
+func TestPositionChecksModes(t *testing.T) {
+	var perRune lexer.Fn
+	perRune = func(l *lexer.Lexer) lexer.Fn {
+		l.Next()
+		l.EmitToken(lexer.TStart)
+		return perRune
+	}
+	tests := []struct {
+		input string
+		opts  []lexer.Option
+	}{
+		{"e\u0301x", []lexer.Option{lexer.WithColumns(lexer.ColumnWidth)}},
+		{"字e\u0301\r\nx\ry", []lexer.Option{lexer.WithColumns(lexer.ColumnBytes), lexer.WithLineTerminators(lexer.LineCRLF)}},
+		{"a\u2028\u0301b", []lexer.Option{lexer.WithColumns(lexer.ColumnWidth), lexer.WithLineTerminators(lexer.LineUnicode)}},
+	}
+	for _, test := range tests {
+		tokens := lexer.LexString(test.input, perRune, test.opts...)
+		nexter := Parse(tokens, parseAllTokens(func() interface{} {
+			return test.input
+		}), WithPositionChecks())
+		expectNexterNext(t, nexter, test.input)
+		expectNexterEOF(t, nexter)
+	}
+}
+
+// TestPositionChecksAST
+//
+func TestPositionChecksAST(t *testing.T) {
+	nexter := Parse(positioned(TOne, TTwo), parseAllTokens(func() interface{} {
+		return &spanAST{span: token.Span{Start: token.Position{Line: 1, Column: 1}, End: token.Position{Line: 1, Column: 9}}}
+	}), WithPositionChecks())
+	assertPanic(t, func() {
+		_, _ = nexter.Next()
+	}, "Parser: position check failed: AST *parser.spanAST spanning 1:1-1:9 lies outside its tokens spanning 1:1-1:4")
+}
+
+// TestPositionChecksDisabled
+//
+func TestPositionChecksDisabled(t *testing.T) {
+	tokens := token.SliceNexter(token.New(TOne, "a", 1, 5), token.New(TTwo, "b", 1, 1))
+	nexter := Parse(tokens, parseAllTokens(func() interface{} {
+		return "ab"
+	}))
+	expectNexterNext(t, nexter, "ab")
+	expectNexterEOF(t, nexter)
+}